@@ -0,0 +1,113 @@
+// Echo cancellation evaluator - replays a recorded speaker/mic pair
+//
+// Record with:  eva --aec-record recordings/
+// Evaluate with: aec-eval -mic recordings/X_mic.wav -ref recordings/X_ref.wav -out cleaned.wav
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"github.com/teslashibe/go-reachy/pkg/audio"
+)
+
+func main() {
+	defaults := audio.DefaultEchoCancellerConfig()
+
+	micFlag := flag.String("mic", "", "Microphone WAV recorded with eva --aec-record")
+	refFlag := flag.String("ref", "", "Reference WAV (what Eva played) from the same recording")
+	outFlag := flag.String("out", "", "Write the echo-cancelled mic audio to this WAV")
+	filterFlag := flag.Duration("filter", defaults.FilterLength, "Echo tail modelled by the adaptive filter")
+	delayFlag := flag.Duration("delay", defaults.InitialDelay, "Initial playback-to-capture delay guess")
+	stepFlag := flag.Float64("step", defaults.StepSize, "NLMS step size (0-1)")
+	suppressFlag := flag.Float64("suppress", defaults.Suppression, "Residual gain while only Eva talks (1 = off)")
+	flag.Parse()
+
+	if *micFlag == "" || *refFlag == "" {
+		fmt.Println("Usage: aec-eval -mic X_mic.wav -ref X_ref.wav [-out cleaned.wav]")
+		os.Exit(1)
+	}
+
+	mic, rate, err := audio.ReadWAV(*micFlag)
+	if err != nil {
+		fmt.Printf("❌ Read %s: %v\n", *micFlag, err)
+		os.Exit(1)
+	}
+	ref, refRate, err := audio.ReadWAV(*refFlag)
+	if err != nil {
+		fmt.Printf("❌ Read %s: %v\n", *refFlag, err)
+		os.Exit(1)
+	}
+	if rate != refRate {
+		ref = audio.Resample(ref, refRate, rate)
+	}
+	n := min(len(mic), len(ref))
+
+	aec := audio.NewEchoCanceller(nil, audio.EchoCancellerConfig{
+		SampleRate:   rate,
+		FilterLength: *filterFlag,
+		InitialDelay: *delayFlag,
+		MaxDelay:     defaults.MaxDelay,
+		StepSize:     *stepFlag,
+		Suppression:  *suppressFlag,
+	})
+
+	// Replay in 20ms blocks like the live pipeline, scoring blocks where
+	// Eva was talking
+	block := rate / 50
+	out := make([]int16, 0, n)
+	var echoIn, echoOut float64
+	var converged time.Duration = -1
+	start := time.Now()
+	for off := 0; off+block <= n; off += block {
+		cleaned := aec.ProcessBlock(mic[off:off+block], ref[off:off+block])
+		out = append(out, cleaned...)
+
+		if rms(ref[off:off+block]) > 100 {
+			echoIn += energy(mic[off : off+block])
+			echoOut += energy(cleaned)
+		}
+		if converged < 0 && aec.Stats().Converged {
+			converged = time.Duration(off) * time.Second / time.Duration(rate)
+		}
+	}
+	elapsed := time.Since(start)
+	audioLen := time.Duration(n) * time.Second / time.Duration(rate)
+
+	stats := aec.Stats()
+	fmt.Println("🔊 Echo cancellation")
+	fmt.Printf("   Audio:      %v at %d Hz\n", audioLen.Round(time.Millisecond), rate)
+	fmt.Printf("   Delay:      %v\n", stats.Delay.Round(100*time.Microsecond))
+	if echoOut > 0 {
+		fmt.Printf("   ERLE:       %.1f dB (while Eva talks)\n", 10*math.Log10(echoIn/echoOut))
+	}
+	if converged >= 0 {
+		fmt.Printf("   Converged:  after %v\n", converged.Round(time.Millisecond))
+	} else {
+		fmt.Println("   Converged:  never")
+	}
+	fmt.Printf("   CPU:        %.1f%% of real time\n", 100*elapsed.Seconds()/audioLen.Seconds())
+
+	if *outFlag != "" {
+		if err := audio.WriteWAV(*outFlag, out, rate); err != nil {
+			fmt.Printf("❌ Write %s: %v\n", *outFlag, err)
+			os.Exit(1)
+		}
+		fmt.Printf("\n📄 Cleaned audio written to %s\n", *outFlag)
+	}
+}
+
+func energy(s []int16) float64 {
+	var e float64
+	for _, v := range s {
+		e += float64(v) * float64(v)
+	}
+	return e
+}
+
+func rms(s []int16) float64 {
+	return math.Sqrt(energy(s) / float64(len(s)))
+}
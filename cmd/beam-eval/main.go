@@ -0,0 +1,180 @@
+// Beamforming evaluator - replays a recorded multichannel mic file
+//
+// Record the XVF3800's raw channels on the robot, with a few seconds of
+// room noise (nobody talking) before the speech:
+//
+//	arecord -D <xvf3800 device> -c 4 -r 16000 -f S16_LE room.wav
+//
+// Evaluate with: beam-eval -in room.wav -angle 30 -learn 3s -out beam.wav
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/teslashibe/go-reachy/pkg/audio"
+	"github.com/teslashibe/go-reachy/pkg/audio/beamform"
+)
+
+func main() {
+	inFlag := flag.String("in", "", "Multichannel WAV recorded from the mic array")
+	outFlag := flag.String("out", "", "Write the beamformed mono audio to this WAV")
+	methodFlag := flag.String("method", "mvdr", "Beamformer: das (delay-and-sum) or mvdr")
+	angleFlag := flag.Float64("angle", 0, "Steering direction in degrees (0 = front, 90 = left)")
+	learnFlag := flag.Duration("learn", 0, "Leading noise-only audio: MVDR learns the noise here, and it is scored as noise")
+	channelsFlag := flag.String("channels", "", "Stream channel of each mic, e.g. 2,3,4,5 (default 0,1,2,3)")
+	fftFlag := flag.Int("fft", 0, "STFT frame length (default about 32ms)")
+	loadingFlag := flag.Float64("loading", 0, "MVDR diagonal loading (default from the library)")
+	scanFlag := flag.Bool("scan", false, "Print the steered response power around the circle, to find the talker")
+	flag.Parse()
+
+	if *inFlag == "" {
+		fmt.Println("Usage: beam-eval -in room.wav [-angle 30] [-learn 3s] [-method mvdr] [-out beam.wav]")
+		os.Exit(1)
+	}
+
+	in, channels, rate, err := audio.ReadWAVChannels(*inFlag)
+	if err != nil {
+		fmt.Printf("❌ Read %s: %v\n", *inFlag, err)
+		os.Exit(1)
+	}
+	method, err := beamform.ParseMethod(*methodFlag)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	cfg := beamform.DefaultConfig(rate, channels)
+	cfg.Method = method
+	if *channelsFlag != "" {
+		for _, s := range strings.Split(*channelsFlag, ",") {
+			ch, err := strconv.Atoi(strings.TrimSpace(s))
+			if err != nil {
+				fmt.Printf("❌ Bad -channels %q\n", *channelsFlag)
+				os.Exit(1)
+			}
+			cfg.Array.Channels = append(cfg.Array.Channels, ch)
+		}
+	}
+	if *fftFlag > 0 {
+		cfg.FFTSize = *fftFlag
+	}
+	if *loadingFlag > 0 {
+		cfg.Loading = *loadingFlag
+	}
+
+	frames := len(in) / channels
+	learn := min(int(learnFlag.Seconds()*float64(rate)), frames)
+	audioLen := time.Duration(frames) * time.Second / time.Duration(rate)
+
+	if *scanFlag {
+		scan(cfg, in, learn)
+	}
+
+	bf, err := beamform.New(cfg)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	bf.Steer(*angleFlag * math.Pi / 180)
+	if method == beamform.MVDR && learn == 0 {
+		fmt.Println("⚠️  No -learn segment: MVDR has no noise to null and acts as delay-and-sum")
+	}
+
+	// Replay in 20ms blocks like the live pipeline
+	block := rate / 50 * channels
+	out := make([]int16, 0, frames)
+	start := time.Now()
+	for off := 0; off < len(in); off += block {
+		bf.SetTargetActive(off/channels >= learn)
+		out = bf.Append(out, in[off:min(off+block, len(in))])
+	}
+	elapsed := time.Since(start)
+
+	// Align the output with the input for scoring
+	delay := int(bf.Delay().Seconds() * float64(rate))
+	if len(out) > delay {
+		out = out[delay:]
+	}
+	ref := channel(in, channels, mapped(cfg.Array, 0))[:len(out)]
+
+	fmt.Println("🎯 Beamforming")
+	fmt.Printf("   Audio:      %v at %d Hz, %d channels\n", audioLen.Round(time.Millisecond), rate, channels)
+	fmt.Printf("   Method:     %s, steered to %.0f°\n", method, *angleFlag)
+	fmt.Printf("   Level:      %.1f dBFS in (mic 0), %.1f dBFS out\n", dbfs(ref), dbfs(out))
+	if learn > 0 && learn < len(out) {
+		noiseGain := dbfs(out[:learn]) - dbfs(ref[:learn])
+		speechGain := dbfs(out[learn:]) - dbfs(ref[learn:])
+		fmt.Printf("   Noise:      %+.1f dB (first %v)\n", noiseGain, *learnFlag)
+		fmt.Printf("   Speech:     %+.1f dB (rest)\n", speechGain)
+		fmt.Printf("   SNR gain:   %+.1f dB (approximate)\n", speechGain-noiseGain)
+	}
+	fmt.Printf("   CPU:        %.1f%% of real time\n", 100*elapsed.Seconds()/audioLen.Seconds())
+
+	if *outFlag != "" {
+		if err := audio.WriteWAV(*outFlag, out, rate); err != nil {
+			fmt.Printf("❌ Write %s: %v\n", *outFlag, err)
+			os.Exit(1)
+		}
+		fmt.Printf("\n📄 Beamformed audio written to %s\n", *outFlag)
+	}
+}
+
+// scan prints the delay-and-sum output level of the speech part for
+// steering directions around the circle.
+func scan(cfg beamform.Config, in []int16, learn int) {
+	cfg.Method = beamform.DelayAndSum
+	fmt.Println("🧭 Steered response power (speech part)")
+	var best float64
+	bestLevel := math.Inf(-1)
+	for deg := -180.0; deg < 180; deg += 15 {
+		bf, err := beamform.New(cfg)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		bf.Steer(deg * math.Pi / 180)
+		out := bf.Process(in)
+		if learn < len(out) {
+			out = out[learn:]
+		}
+		level := dbfs(out)
+		fmt.Printf("   %+4.0f°  %6.1f dBFS  %s\n", deg, level, strings.Repeat("█", max(int(level+60), 0)))
+		if level > bestLevel {
+			best, bestLevel = deg, level
+		}
+	}
+	fmt.Printf("   Loudest: %+.0f°\n\n", best)
+}
+
+// mapped returns the stream channel of mic i.
+func mapped(a beamform.Array, i int) int {
+	if a.Channels == nil {
+		return i
+	}
+	return a.Channels[i]
+}
+
+func channel(in []int16, channels, ch int) []int16 {
+	out := make([]int16, len(in)/channels)
+	for i := range out {
+		out[i] = in[i*channels+ch]
+	}
+	return out
+}
+
+func dbfs(s []int16) float64 {
+	if len(s) == 0 {
+		return math.Inf(-1)
+	}
+	var e float64
+	for _, v := range s {
+		e += float64(v) * float64(v)
+	}
+	return 10 * math.Log10(e/float64(len(s))/(32768*32768))
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/teslashibe/go-reachy/pkg/audio"
+	"github.com/teslashibe/go-reachy/pkg/audio/beamform"
+	"github.com/teslashibe/go-reachy/pkg/audio/dsp"
+	"github.com/teslashibe/go-reachy/pkg/debug"
+	"github.com/teslashibe/go-reachy/pkg/video"
+)
+
+// beamResteer is how far the target must move before the beam follows.
+const beamResteer = 2 * math.Pi / 180
+
+var (
+	beamMode = "off"     // "off", "das" or "mvdr"
+	beam     *beamSource // Beamformed mic (nil = WebRTC mic only)
+)
+
+// beamSource beamforms go-eva's raw multichannel mic stream toward the
+// tracked speaker. Its 48kHz mono frames replace the WebRTC mic track
+// while they flow.
+type beamSource struct {
+	method   beamform.Method
+	steering *beamform.Steering
+	frames   chan video.AudioFrame
+	last     atomic.Int64 // UnixNano of the last frame
+
+	// Owned by the stream goroutine
+	format audio.RawFormat
+	bf     *beamform.Beamformer
+	rs     *dsp.Resampler // To 48kHz; nil when the stream is already 48kHz
+	source string         // Where the beam points: "focus" or "doa"
+}
+
+// startBeamforming streams raw mic audio from go-eva and beamforms it,
+// steered by focus (the world model's tracked person) or else by DOA.
+func startBeamforming(ctx context.Context, client *audio.Client, focus func() (float64, bool)) error {
+	method, err := beamform.ParseMethod(beamMode)
+	if err != nil {
+		return err
+	}
+	b := &beamSource{
+		method:   method,
+		steering: beamform.NewSteering(focus),
+		frames:   make(chan video.AudioFrame, 25),
+	}
+	client.AddListener(b.steering.UpdateDOA)
+	beam = b
+	go b.run(ctx, client)
+	return nil
+}
+
+// live reports whether beamformed audio is flowing.
+func (b *beamSource) live() bool {
+	return time.Since(time.Unix(0, b.last.Load())) < 200*time.Millisecond
+}
+
+// run keeps the raw stream connected until ctx ends or go-eva turns out
+// not to have one.
+func (b *beamSource) run(ctx context.Context, client *audio.Client) {
+	for ctx.Err() == nil {
+		err := client.StreamRaw(ctx, b.process)
+		switch {
+		case ctx.Err() != nil:
+			return
+		case errors.Is(err, audio.ErrRawUnsupported):
+			fmt.Println("⚠️  go-eva has no raw multichannel stream; beamforming off, using the WebRTC mic")
+			return
+		}
+		debug.Log("🎯 Raw mic stream: %v (reconnecting)\n", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
+// process beamforms one raw frame and forwards the result.
+func (b *beamSource) process(frame audio.RawFrame) {
+	if frame.RawFormat != b.format {
+		b.format = frame.RawFormat
+		b.bf, b.rs = nil, nil
+		cfg := beamform.DefaultConfig(frame.SampleRate, frame.Channels)
+		cfg.Method = b.method
+		bf, err := beamform.New(cfg)
+		if err != nil {
+			fmt.Printf("⚠️  Beamforming disabled for %d Hz, %d channels: %v\n", frame.SampleRate, frame.Channels, err)
+			return
+		}
+		if frame.SampleRate != video.MicSampleRate {
+			if b.rs, err = dsp.NewResampler(frame.SampleRate, video.MicSampleRate, dsp.QualityDefault); err != nil {
+				fmt.Printf("⚠️  Beamforming disabled: %v\n", err)
+				return
+			}
+		}
+		b.bf = bf
+		fmt.Printf("🎯 Beamforming %d mics (%s, %d Hz)\n", frame.Channels, b.method, frame.SampleRate)
+	}
+	if b.bf == nil {
+		return
+	}
+
+	if angle, source, ok := b.steering.Angle(); ok {
+		if math.Abs(angle-b.bf.Angle()) > beamResteer {
+			b.bf.Steer(angle)
+		}
+		if source != b.source {
+			debug.Log("🎯 Beam steered by %s (%.0f°)\n", source, angle*180/math.Pi)
+			b.source = source
+		}
+	}
+	b.bf.SetTargetActive(b.steering.Speaking())
+
+	samples := b.bf.Process(frame.Samples)
+	if b.rs != nil {
+		samples = b.rs.Process(samples)
+	}
+	if len(samples) == 0 {
+		return
+	}
+	b.last.Store(time.Now().UnixNano())
+	select {
+	case b.frames <- video.AudioFrame{Samples: samples, Time: frame.Time}:
+	default:
+		debug.Logln("🎯 Beamformed frame dropped (uplink behind)")
+	}
+}
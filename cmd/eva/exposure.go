@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/teslashibe/go-reachy/pkg/camera"
+)
+
+var (
+	autoExposureEnabled = true
+	autoExposure        *camera.AutoExposure // nil when disabled
+)
+
+// startAutoExposure adapts the camera exposure to the light, metering
+// the tracker's frames (see onTrackerFrame). Decisions go to the
+// dashboard log.
+func startAutoExposure(ctx context.Context) {
+	if !autoExposureEnabled || headTracker == nil || cameraManager == nil {
+		return
+	}
+
+	ae := camera.NewAutoExposure(cameraManager, camera.DefaultAutoExposureConfig())
+	ae.OnDecision = func(d camera.ExposureDecision) {
+		fmt.Printf("📷 %s\n", d)
+		if webServer != nil {
+			webServer.AddLog("camera", d.String())
+		}
+	}
+	autoExposure = ae
+	go ae.Run(ctx)
+
+	fmt.Printf("📷 Auto-exposure enabled (%s mode)\n", ae.Mode())
+}
+
+// autoExposureStatus summarizes auto-exposure for the camera API.
+func autoExposureStatus() map[string]interface{} {
+	s := autoExposure.Stats()
+	status := map[string]interface{}{
+		"mode":       autoExposure.Mode().String(),
+		"frame_luma": s.Mean,
+		"clipped":    s.Clipped,
+	}
+	if s.HasFace {
+		status["face_luma"] = s.FaceLuma
+	}
+	return status
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/teslashibe/go-reachy/pkg/camera"
+	"github.com/teslashibe/go-reachy/pkg/tracking"
+	"github.com/teslashibe/go-reachy/pkg/tracking/detection"
+)
+
+var (
+	framingMode = "off"        // "off", "person" or "group"
+	framer      *camera.Framer // nil when off
+)
+
+// startAutoFraming moves the camera crop to keep the focused person (or
+// the group) framed.
+func startAutoFraming(ctx context.Context) {
+	if framingMode == "off" || headTracker == nil || cameraManager == nil {
+		return
+	}
+
+	cfg := camera.DefaultFramingConfig()
+	cfg.Group = framingMode == "group"
+	framer = camera.NewFramer(cameraManager, cfg)
+	framer.OnEvent = func(msg string) {
+		fmt.Printf("🎬 Auto-framing: %s\n", msg)
+		if webServer != nil {
+			webServer.AddLog("camera", "auto-framing: "+msg)
+		}
+	}
+	go framer.Run(ctx)
+
+	fmt.Printf("🎬 Auto-framing enabled (%s)\n", framingMode)
+}
+
+// onTrackerFrame feeds each analysed frame and its faces to
+// auto-exposure and auto-framing.
+func onTrackerFrame(frame []byte, faces []detection.Detection) {
+	regions := make([]camera.Region, len(faces))
+	focus := -1
+	best := detection.SelectBest(faces)
+	for i := range faces {
+		f := faces[i]
+		regions[i] = camera.Region{X: f.X, Y: f.Y, W: f.W, H: f.H}
+		if &faces[i] == best {
+			focus = i
+		}
+	}
+	if autoExposure != nil {
+		autoExposure.Observe(frame, regions)
+	}
+	if framer != nil {
+		framer.Observe(regions, focus)
+	}
+}
+
+// applyCameraConfig applies cfg on the robot, then tells the tracker the
+// new crop so head tracking stays accurate at any zoom.
+func applyCameraConfig(cfg camera.Config) error {
+	if err := cameraCtrl.Apply(cfg); err != nil {
+		return err
+	}
+	setTrackerCrop(cfg)
+	return nil
+}
+
+// setTrackerCrop tells the tracker which part of the view cfg shows.
+func setTrackerCrop(cfg camera.Config) {
+	if headTracker == nil {
+		return
+	}
+	v := cfg.View()
+	headTracker.SetCameraCrop(tracking.Crop{X: v.X, Y: v.Y, W: v.W, H: v.H})
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/teslashibe/go-reachy/pkg/conversation"
+	"github.com/teslashibe/go-reachy/pkg/eva"
+)
+
+// thinkingStep is how often the antennas swap sides while a background
+// tool runs.
+const thinkingStep = 400 * time.Millisecond
+
+var thinking atomic.Bool // The thinking animation is playing
+
+// setupBackgroundJobs reports background tool jobs on the dashboard,
+// plays the thinking animation while they run, and hands their results
+// to the model when they finish.
+func setupBackgroundJobs(registry *eva.ToolRegistry) {
+	registry.OnJobStart = func(job eva.ToolJob) {
+		fmt.Printf("⏳ Task %s started: %s\n", job.ID, job.Tool)
+		if webServer != nil {
+			webServer.AddLog("tool", fmt.Sprintf("Task %s: %s started", job.ID, job.Tool))
+		}
+		startThinking(registry)
+	}
+	registry.OnProgress = func(job eva.ToolJob, message string) {
+		fmt.Printf("⏳ Task %s (%s): %s\n", job.ID, job.Tool, message)
+		if webServer != nil {
+			webServer.AddLog("tool", fmt.Sprintf("Task %s: %s", job.ID, message))
+		}
+	}
+	registry.OnResult = func(job eva.ToolJob, result string, err error) {
+		if errors.Is(err, eva.ErrToolCanceled) {
+			fmt.Printf("⏳ Task %s (%s) canceled\n", job.ID, job.Tool)
+			return
+		}
+		if err != nil {
+			fmt.Printf("⏳ Task %s failed: %v\n", job.ID, err)
+		} else {
+			fmt.Printf("⏳ Task %s (%s) done\n", job.ID, job.Tool)
+		}
+		if webServer != nil {
+			webServer.AddLog("tool", fmt.Sprintf("Task %s: %s finished", job.ID, job.Tool))
+		}
+		if realtimeClient == nil || !realtimeClient.IsConnected() {
+			return
+		}
+		injector, ok := realtimeClient.(conversation.Injector)
+		if !ok {
+			fmt.Printf("⏳ Task %s result can't be delivered: the %s provider doesn't take late results\n", job.ID, providerName)
+			return
+		}
+		if err := injector.Inject(job.Message(result, err)); err != nil {
+			fmt.Printf("⏳ Failed to deliver task %s result: %v\n", job.ID, err)
+		}
+	}
+}
+
+// startThinking sways the antennas in turn until no background jobs are
+// left, pausing the breathing sway.
+func startThinking(registry *eva.ToolRegistry) {
+	if rateCtrl == nil || !thinking.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer thinking.Store(false)
+		left := true
+		for len(registry.Jobs()) > 0 {
+			antennaCueUntil.Store(time.Now().Add(2 * thinkingStep).UnixNano())
+			if left {
+				rateCtrl.SetAntennas(0.4, -0.1)
+			} else {
+				rateCtrl.SetAntennas(-0.1, 0.4)
+			}
+			left = !left
+			time.Sleep(thinkingStep)
+		}
+		rateCtrl.SetAntennas(0, 0)
+	}()
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/teslashibe/go-reachy/pkg/conversation"
+	"github.com/teslashibe/go-reachy/pkg/debug"
+	"github.com/teslashibe/go-reachy/pkg/listen"
+	"github.com/teslashibe/go-reachy/pkg/web"
+)
+
+// wakeCueDuration is how long the antennas stay perked after a wake word.
+const wakeCueDuration = 700 * time.Millisecond
+
+var (
+	listenCtrl  *listen.Controller // Listening mode: decides when mic audio is sent
+	wakeSpotter listen.Spotter     // Local wake-word spotter (nil = wake_word mode unavailable)
+
+	pttReleased     atomic.Bool  // Talk button released; the uplink commits the turn
+	antennaCueUntil atomic.Int64 // UnixNano; breathing pauses while the wake cue plays
+	clientTurns     atomic.Bool  // The Realtime session leaves turn detection to Eva
+)
+
+// setupListening creates the listening controller and wake-word spotter
+// from the command-line flags.
+func setupListening() error {
+	mode, err := listen.ParseMode(listenMode)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case wakeTemplates != "":
+		s := listen.NewTemplateSpotter(24000)
+		phrases, err := s.LoadTemplates(wakeTemplates)
+		if err != nil {
+			return err
+		}
+		wakeSpotter = s
+		fmt.Printf("👂 Wake words (templates): %s\n", strings.Join(phrases, ", "))
+	case wakeSTT != "":
+		phrases := splitPhrases(wakeWords)
+		if len(phrases) == 0 {
+			return fmt.Errorf("--wake-words is empty")
+		}
+		s := listen.NewPhraseSpotter(conversation.NewWhisperTranscriber(wakeSTT, "", ""), 24000, phrases)
+		s.OnError = func(err error) {
+			debug.Log("👂 Wake-word STT error: %v\n", err)
+		}
+		wakeSpotter = s
+		fmt.Printf("👂 Wake words (%s): %s\n", wakeSTT, strings.Join(phrases, ", "))
+	}
+	if mode == listen.ModeWakeWord && wakeSpotter == nil {
+		return fmt.Errorf("wake_word mode needs --wake-templates or --wake-stt")
+	}
+
+	cfg := listen.DefaultConfig()
+	cfg.Mode = mode
+	cfg.FollowUp = followUp
+	listenCtrl = listen.New(cfg)
+	listenCtrl.OnChange(handleListenChange(listenCtrl.State()))
+	fmt.Printf("👂 Listening mode: %s\n", mode)
+	return nil
+}
+
+func splitPhrases(s string) []string {
+	var out []string
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// useClientVAD reports whether the Realtime session should leave turn
+// detection to Eva: with the local VAD, and in push-to-talk where the
+// button release ends the turn.
+func useClientVAD(m listen.Mode) bool {
+	return vadMode == "local" || m == listen.ModePushToTalk
+}
+
+// setListeningMode switches mode from the dashboard, reconfiguring the
+// Realtime session when turn detection changes hands.
+func setListeningMode(name string) error {
+	m, err := listen.ParseMode(name)
+	if err != nil {
+		return err
+	}
+	if m == listen.ModeWakeWord && wakeSpotter == nil {
+		return fmt.Errorf("no wake-word spotter (start Eva with --wake-templates or --wake-stt)")
+	}
+	listenCtrl.SetMode(m)
+	fmt.Printf("👂 Listening mode: %s\n", m)
+
+	if realtimeClient != nil && clientTurns.Load() != useClientVAD(m) {
+		clientTurns.Store(useClientVAD(m))
+		if err := realtimeClient.ConfigureSession(realtimeSession(useClientVAD(m))); err != nil {
+			return fmt.Errorf("reconfigure session: %w", err)
+		}
+	}
+	return nil
+}
+
+// realtimeSession returns Eva's Realtime session options, with turn
+// detection on the server or, for clientVAD, left to Eva.
+func realtimeSession(clientVAD bool) conversation.SessionOptions {
+	td := &conversation.TurnDetection{
+		Type:              "server_vad",
+		Threshold:         0.5,
+		PrefixPaddingMs:   300,
+		SilenceDurationMs: 300,
+	}
+	if clientVAD {
+		td = &conversation.TurnDetection{Type: "client_vad"}
+	}
+	return conversation.SessionOptions{
+		SystemPrompt:  evaInstructions,
+		Voice:         evaVoice,
+		TurnDetection: td,
+	}
+}
+
+// pushToTalk handles the dashboard's talk button.
+func pushToTalk(pressed bool) error {
+	if !pressed {
+		if listenCtrl.Release() {
+			pttReleased.Store(true) // Commit after the last audio is sent
+		}
+		return nil
+	}
+	if !listenCtrl.Press() {
+		return fmt.Errorf("not in push-to-talk mode")
+	}
+	if bargeIn != nil && audioPlayer != nil {
+		bargeIn.Interrupt()
+	}
+	if realtimeClient != nil && realtimeClient.IsConnected() {
+		realtimeClient.ClearAudio() // Drop unfinished follow-up audio
+	}
+	return nil
+}
+
+// wake opens the mic for a detected wake word and plays the antenna cue.
+func wake(det listen.Detection) bool {
+	if !listenCtrl.Wake() {
+		return false
+	}
+	fmt.Printf("👂 Wake word: %q (score %.2f)\n", det.Phrase, det.Score)
+	if webServer != nil {
+		webServer.AddLog("info", fmt.Sprintf("Wake word: %s", det.Phrase))
+	}
+	playWakeCue()
+	return true
+}
+
+// playWakeCue perks both antennas up briefly, pausing the breathing sway.
+func playWakeCue() {
+	if rateCtrl == nil {
+		return
+	}
+	antennaCueUntil.Store(time.Now().Add(wakeCueDuration).UnixNano())
+	go func() {
+		rateCtrl.SetAntennas(0.5, 0.5)
+		time.Sleep(wakeCueDuration / 2)
+		rateCtrl.SetAntennas(0.3, 0.3)
+		time.Sleep(wakeCueDuration / 2)
+		rateCtrl.SetAntennas(0, 0)
+	}()
+}
+
+// wakeCueActive reports whether the wake cue owns the antennas.
+func wakeCueActive() bool {
+	return time.Now().UnixNano() < antennaCueUntil.Load()
+}
+
+// handleListenChange returns the controller listener: it logs mic
+// changes, mirrors them to the dashboard and, when a wake or follow-up
+// window lapses with client-side turns, drops the uncommitted audio.
+func handleListenChange(prev listen.State) func(listen.State) {
+	return func(st listen.State) {
+		if st.Open != prev.Open {
+			if st.Open {
+				fmt.Printf("👂 Mic open (%s)\n", st.Reason)
+			} else {
+				fmt.Println("💤 Mic closed")
+			}
+		}
+		lapsed := prev.Open && !st.Open && (prev.Reason == listen.ReasonWakeWord || prev.Reason == listen.ReasonFollowUp)
+		if lapsed && realtimeClient != nil && clientTurns.Load() && realtimeClient.IsConnected() {
+			realtimeClient.ClearAudio()
+		}
+		prev = st // Listener calls are serialized
+
+		if webServer != nil {
+			webServer.UpdateState(func(s *web.EvaState) {
+				applyListenState(s, st)
+			})
+		}
+	}
+}
+
+func applyListenState(s *web.EvaState, st listen.State) {
+	s.ListeningMode = string(st.Mode)
+	s.MicOpen = st.Open
+	s.MicOpenReason = st.Reason
+}
@@ -4,27 +4,36 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"net"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
 	"github.com/teslashibe/go-reachy/pkg/audio"
+	"github.com/teslashibe/go-reachy/pkg/audio/beamform"
 	"github.com/teslashibe/go-reachy/pkg/camera"
+	"github.com/teslashibe/go-reachy/pkg/conversation"
 	"github.com/teslashibe/go-reachy/pkg/debug"
 	"github.com/teslashibe/go-reachy/pkg/emotions"
 	"github.com/teslashibe/go-reachy/pkg/eva"
+	"github.com/teslashibe/go-reachy/pkg/listen"
 	"github.com/teslashibe/go-reachy/pkg/memory"
-	"github.com/teslashibe/go-reachy/pkg/openai"
 	"github.com/teslashibe/go-reachy/pkg/robot"
 	"github.com/teslashibe/go-reachy/pkg/spark"
 	"github.com/teslashibe/go-reachy/pkg/speech"
 	"github.com/teslashibe/go-reachy/pkg/tracking"
 	"github.com/teslashibe/go-reachy/pkg/tracking/detection"
+	"github.com/teslashibe/go-reachy/pkg/tracking/telemetry"
 	"github.com/teslashibe/go-reachy/pkg/tts"
+	"github.com/teslashibe/go-reachy/pkg/vad"
 	"github.com/teslashibe/go-reachy/pkg/video"
 	"github.com/teslashibe/go-reachy/pkg/web"
 )
@@ -37,6 +46,18 @@ const (
 
 var robotIP = defaultRobotIP
 var transport = "zenoh" // "zenoh" (default) or "http"
+var audioOut = "native" // "native" (default), "udp", "ssh" or "file:PATH"
+var aecEnabled = true   // Echo cancellation for full-duplex listening
+var aecRecordDir = ""   // Record mic/reference pairs for offline tuning
+var audioVolumes = ""   // Per-channel output volume, e.g. "effects=0.8,music=0.3"
+var vadMode = "server"  // "server" (provider endpoints) or "local" (client_vad)
+var vadModel = ""       // Silero-style ONNX model for the local VAD (empty = feature classifier)
+
+var listenMode = "always_on"   // "always_on", "wake_word" or "push_to_talk"
+var wakeWords = "hey eva"      // Comma-separated phrases for the STT wake-word spotter
+var wakeTemplates = ""         // DIR/<phrase>/*.wav examples for the template spotter
+var wakeSTT = ""               // OpenAI-compatible STT server for the phrase spotter
+var followUp = 8 * time.Second // Mic stays open after Eva answers (wake_word/push_to_talk)
 
 func init() {
 	if ip := os.Getenv("ROBOT_IP"); ip != "" {
@@ -44,6 +65,9 @@ func init() {
 	}
 }
 
+// Eva's voice on the Realtime API
+const evaVoice = "shimmer"
+
 // Eva's personality and instructions
 const evaInstructions = `You are Eva, a friendly and curious robot with expressive antenna ears and a camera. You're warm, engaging, and love meeting people.
 
@@ -79,16 +103,26 @@ Vision:
 Memory:
 - remember_person: Store facts about someone (name + fact)
 - recall_person: Remember what you know about someone
+- recall: Search all your memories by meaning when you're not sure where something is filed
 
 Utilities:
 - get_time: Get current time and date
 - set_timer: Set a countdown timer (duration, unit, optional label)
+- set_alarm: Set an alarm for a time of day (time, optional label)
+- set_reminder: Remind about something at a time or on a repeating schedule
+- list_timers / cancel_timer / snooze_timer: Check, cancel or snooze timers, alarms and reminders
 - set_volume: Adjust your speaker volume (0-100)
 
 Search:
 - web_search: Search the internet for news, facts, weather, products
 - search_flights: Find real flight prices (origin, destination, date, cabin class)
 
+Background tasks:
+- describe_scene, web_search, search_flights and generate_plan run in the background: they reply right away, and the result arrives later as a system message
+- While one runs, say briefly that you're on it and keep chatting; never guess the result
+- When the result arrives, tell the user about it
+- cancel_task: Stop a background task (task number or tool name)
+
 BEHAVIOR:
 - Keep responses conversational - 1-2 sentences usually
 - JUST DO gestures and movements - don't ask permission
@@ -104,9 +138,14 @@ IMPORTANT:
 - When you can't see or hear something, use your tools to actually look`
 
 var (
-	realtimeClient  *openai.Client
+	realtimeClient  evaProvider
 	videoClient     *video.Client
 	audioPlayer     *audio.Player
+	audioOutput     *audio.Output // Native audio output (nil for SSH pipeline)
+	echoReference   *audio.EchoReference   // What Eva played, for echo cancellation (nil without AEC)
+	aecRecorder     *aecRecording          // Mic/reference pairs (--aec-record)
+	bargeIn         *conversation.BargeIn  // Stops Eva when the user talks over her
+	speechDetector  *vad.Detector          // Local VAD: speech events for tracker/dashboard, endpointing in --vad=local
 	robotCtrl       robot.MotionController  // Motion control (HTTP or Zenoh)
 	httpCtrl        *robot.HTTPController   // HTTP-only ops (status, volume)
 	rateCtrl        *robot.RateController // Centralized rate-limited controller (Issue #135)
@@ -115,12 +154,14 @@ var (
 	sparkGemini     *spark.GeminiClient
 	sparkGoogleDocs *spark.GoogleDocsClient
 	webServer       *web.Server
+	toolRegistry    *eva.ToolRegistry // Validates and runs tools for the model and dashboard
 	headTracker     *tracking.Tracker
 	ttsProvider     tts.Provider      // HTTP TTS provider
 	ttsStreaming    *tts.ElevenLabsWS // WebSocket streaming TTS
 	objectDetector  *detection.YOLODetector
 	speechWobbler   *speech.Wobbler    // Speech-synced head movement
 	cameraManager   *camera.Manager    // Camera configuration manager
+	cameraCtrl      *camera.Controller // Applies camera configs on the robot, with rollback
 	emotionRegistry *emotions.Registry // Pre-recorded emotion animations
 
 	speaking   bool
@@ -170,9 +211,33 @@ func main() {
 	modelFlag := flag.String("model", "gpt-realtime-2025-08-28", "OpenAI Realtime model (e.g., gpt-realtime-2025-08-28)")
 	ttsFlag := flag.String("tts", "realtime", "TTS provider: realtime, elevenlabs, elevenlabs-streaming (lowest latency), openai-tts")
 	ttsVoice := flag.String("tts-voice", "", "Voice ID for ElevenLabs (required if --tts=elevenlabs)")
+	ttsURLFlag := flag.String("tts-url", "", "OpenAI-compatible /audio/speech URL for --tts=openai-tts (e.g. a local TTS server)")
+	providerFlag := flag.String("provider", "realtime", "Conversation provider: realtime (OpenAI Realtime speech-to-speech) or cascade (speech-to-text → chat model → --tts)")
+	cascadeSTTFlag := flag.String("cascade-stt", "", "OpenAI-compatible speech-to-text base URL for --provider=cascade (default: OpenAI; e.g. a local faster-whisper-server)")
+	cascadeLLMFlag := flag.String("cascade-llm", "", "OpenAI-compatible chat-completions base URL for --provider=cascade (default: OpenAI; e.g. http://localhost:11434/v1 for Ollama)")
+	cascadeModelFlag := flag.String("cascade-model", "", "Chat model for --provider=cascade (default: gpt-4o-mini)")
 	sparkFlag := flag.Bool("spark", true, "Enable Spark idea collection (overrides SPARK_ENABLED env var)")
 	noBodyFlag := flag.Bool("no-body", false, "Disable body rotation (head-only tracking)")
 	transportFlag := flag.String("transport", "zenoh", "Robot transport: zenoh (default, direct 100Hz+) or http")
+	telemetryFileFlag := flag.String("telemetry-file", "", "Append per-tick tracking telemetry (JSON lines) to this file")
+	audioOutFlag := flag.String("audio-out", "native", "Audio output: native (Opus/RTP relayed to the robot's stock receiver over one SSH session), udp (Opus/RTP straight to ROBOT_IP:5000; needs a receiver that accepts RTP from the network), ssh (legacy sshpass + gst-launch per utterance; no echo cancellation or volumes), or file:PATH (WAV, no robot audio)")
+	volumesFlag := flag.String("volumes", "", "Output mixer channel volumes, e.g. effects=0.8,music=0.3 (channels: speech, alerts, effects, music)")
+	aecFlag := flag.Bool("aec", true, "Echo cancellation so Eva keeps listening while speaking (barge-in); needs a native audio output")
+	aecRecordFlag := flag.String("aec-record", "", "Record mic/reference WAV pairs to this directory for tuning echo cancellation (see cmd/aec-eval)")
+	autoFrameFlag := flag.String("auto-frame", "off", "Digital reframing: off, person (crop follows the focused face) or group (keeps every face in frame)")
+	autoExposureFlag := flag.Bool("auto-exposure", true, "Adapt camera exposure to the light (face and frame luminance, night/bright presets)")
+	beamformFlag := flag.String("beamform", "off", "Mic-array beamforming toward the tracked speaker: off, das or mvdr (needs go-eva's raw multichannel stream; otherwise the WebRTC mic is used)")
+	vadFlag := flag.String("vad", "server", "Turn detection: server (OpenAI server_vad) or local (on-robot VAD gates the mic and ends turns)")
+	vadModelFlag := flag.String("vad-model", "", "Silero-style ONNX model for the local VAD (default: built-in energy/spectral classifier)")
+	listenModeFlag := flag.String("listen-mode", "always_on", "Listening mode: always_on, wake_word (local wake-word spotter opens the mic) or push_to_talk (dashboard button)")
+	wakeWordsFlag := flag.String("wake-words", "hey eva", "Comma-separated wake phrases for --wake-stt")
+	wakeTemplatesFlag := flag.String("wake-templates", "", "Directory of recorded wake words (DIR/<phrase>/*.wav) for the built-in template spotter")
+	wakeSTTFlag := flag.String("wake-stt", "", "OpenAI-compatible speech-to-text server used to spot --wake-words (e.g. a local faster-whisper-server)")
+	followUpFlag := flag.Duration("follow-up", 8*time.Second, "Keep the mic open this long after Eva answers in wake_word/push_to_talk modes (0 = off)")
+	mcpServeFlag := flag.String("mcp-serve", "", "Serve Eva's tools to other agents over MCP: stdio, http (address from ~/.eva/config.json) or an address like 127.0.0.1:8765; allow-list and token in the config's mcp.serve")
+	memoryStoreFlag := flag.String("memory-store", "sqlite", "Memory storage: sqlite (~/.eva/memory.db, imports memory.json the first time) or json (~/.eva/memory.json)")
+	embedURLFlag := flag.String("embed-url", "", "OpenAI-compatible embeddings base URL for recall (default: OpenAI with OPENAI_API_KEY, else local word matching; e.g. http://localhost:11434/v1)")
+	embedModelFlag := flag.String("embed-model", "", "Embedding model for recall (default: text-embedding-3-small)")
 	sparkFlagSet := false
 	flag.Parse()
 	// Check if --spark was explicitly set
@@ -196,6 +261,34 @@ func main() {
 	if *transportFlag != "" {
 		transport = *transportFlag
 	}
+	if *audioOutFlag != "" {
+		audioOut = *audioOutFlag
+	}
+	audioVolumes = *volumesFlag
+	aecEnabled = *aecFlag
+	aecRecordDir = *aecRecordFlag
+	autoExposureEnabled = *autoExposureFlag
+	framingMode = *autoFrameFlag
+	beamMode = *beamformFlag
+	vadMode = *vadFlag
+	vadModel = *vadModelFlag
+	listenMode = *listenModeFlag
+	wakeWords = *wakeWordsFlag
+	wakeTemplates = *wakeTemplatesFlag
+	wakeSTT = *wakeSTTFlag
+	followUp = *followUpFlag
+	mcpServe = *mcpServeFlag
+	memoryBackend = *memoryStoreFlag
+	embedURL = *embedURLFlag
+	embedModel = *embedModelFlag
+	providerName = *providerFlag
+	cascadeSTT = *cascadeSTTFlag
+	cascadeLLM = *cascadeLLMFlag
+	cascadeModel = *cascadeModelFlag
+	if mcpServe == "stdio" {
+		// stdout carries MCP messages, so logs go to stderr
+		mcpStdout, os.Stdout = os.Stdout, os.Stderr
+	}
 
 	fmt.Println("🤖 Eva 2.0 - Low-Latency Conversational Agent")
 	fmt.Println("==============================================")
@@ -203,11 +296,23 @@ func main() {
 		fmt.Println("🐛 Debug mode enabled")
 	}
 
+	if audioOut == "ssh" && audioVolumes != "" {
+		fmt.Println("❌ --volumes needs the mixer; use --audio-out native")
+		os.Exit(1)
+	}
+
 	openaiKey := os.Getenv("OPENAI_API_KEY")
-	if openaiKey == "" {
+	if openaiKey == "" && providerName != "cascade" {
 		fmt.Println("❌ Set OPENAI_API_KEY!")
 		os.Exit(1)
 	}
+	if openaiKey == "" && providerName == "cascade" {
+		if flags := cascadeCloudFlags(*ttsFlag, *ttsURLFlag); len(flags) > 0 {
+			fmt.Printf("❌ Set OPENAI_API_KEY, or point %s at local servers\n", strings.Join(flags, ", "))
+			os.Exit(1)
+		}
+		fmt.Println("🔌 Cascade: no OPENAI_API_KEY, using local servers only")
+	}
 
 	// Set TTS mode
 	ttsMode = *ttsFlag
@@ -311,6 +416,7 @@ func main() {
 		var err error
 		ttsProvider, err = tts.NewOpenAI(
 			tts.WithAPIKey(openaiKey),
+			tts.WithBaseURL(*ttsURLFlag),
 			tts.WithVoice("shimmer"),
 			tts.WithOutputFormat(tts.EncodingPCM24),
 		)
@@ -324,6 +430,42 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Local VAD: always publishes speech events; with --vad=local it also
+	// gates the mic and ends turns instead of OpenAI's server_vad
+	if vadMode != "server" && vadMode != "local" {
+		fmt.Printf("❌ Unknown VAD mode: %s (use: server, local)\n", vadMode)
+		os.Exit(1)
+	}
+	if det, err := newSpeechDetector(vadModel); err != nil {
+		if vadMode == "local" {
+			fmt.Printf("❌ Local VAD failed: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("⚠️  Local VAD disabled: %v\n", err)
+	} else {
+		speechDetector = det
+		speechDetector.OnEvent(handleSpeechEvent)
+		fmt.Printf("🗣️  Turn detection: %s VAD\n", vadMode)
+	}
+
+	// Listening modes: always on, wake word or push-to-talk
+	if err := setupListening(); err != nil {
+		fmt.Printf("❌ Listening mode: %v\n", err)
+		os.Exit(1)
+	}
+	if beamMode != "off" {
+		if _, err := beamform.ParseMethod(beamMode); err != nil {
+			fmt.Printf("❌ Beamforming: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	switch framingMode {
+	case "off", "person", "group":
+	default:
+		fmt.Printf("❌ Auto-framing: unknown mode %q (want off, person or group)\n", framingMode)
+		os.Exit(1)
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -379,6 +521,17 @@ func main() {
 			rateCtrl.SetTrackingOffset(offset)
 		})
 		fmt.Println("✅ (offset mode → RateController)")
+
+		// Per-tick telemetry for the dashboard and offline analysis
+		recorder := telemetry.NewRecorder(telemetry.DefaultCapacity)
+		if *telemetryFileFlag != "" {
+			if err := recorder.OpenFile(*telemetryFileFlag); err != nil {
+				fmt.Printf("⚠️  Telemetry file disabled: %v\n", err)
+			} else {
+				fmt.Printf("📈 Tracking telemetry → %s\n", *telemetryFileFlag)
+			}
+		}
+		headTracker.SetTelemetry(recorder)
 	}
 
 	// Initialize YOLO object detection
@@ -388,6 +541,13 @@ func main() {
 		fmt.Printf("⚠️  Disabled: %v\n", err)
 	} else {
 		fmt.Println("✅")
+		// Feed YOLO into the tracker's world tracks (people, pets, objects) at 2 Hz
+		// alongside face detection, sharing the same frames
+		if headTracker != nil {
+			pipeline := detection.NewPipeline()
+			pipeline.AddStage(detection.NewObjectSource("yolo", objectDetector), 500*time.Millisecond)
+			headTracker.SetObservationPipeline(pipeline)
+		}
 	}
 
 	// Initialize emotion system (81 pre-recorded animations)
@@ -424,7 +584,15 @@ func main() {
 			fmt.Printf("⚠️  %v (audio DOA disabled)\n", err)
 		} else {
 			headTracker.SetAudioClient(audioClient)
+			if speechDetector != nil {
+				audioClient.AddListener(speechDetector.UpdateDOA) // Fuse the XVF3800 speaking flag
+			}
 			fmt.Println("✅")
+			if beamMode != "off" {
+				if err := startBeamforming(ctx, audioClient, headTracker.GetWorld().GetTargetWorldAngle); err != nil {
+					fmt.Printf("⚠️  Beamforming disabled: %v\n", err)
+				}
+			}
 		}
 
 		// Set up automatic body rotation when head reaches limits (unless --no-body flag)
@@ -463,6 +631,9 @@ func main() {
 		// Enable antenna breathing animation (matches Python reachy)
 		// Route through RateController to prevent HTTP racing (Issue #139)
 		headTracker.SetAntennaHandler(func(left, right float64) {
+			if wakeCueActive() {
+				return // Wake-word cue owns the antennas
+			}
 			rateCtrl.SetAntennas(left, right)
 		})
 		fmt.Println("😮‍💨 Breathing antenna sway enabled (→ RateController)")
@@ -474,30 +645,19 @@ func main() {
 		fmt.Println("😮‍💨 Speech wobble enabled")
 	}
 
-	// Connect to OpenAI Realtime API
-	fmt.Printf("🧠 Connecting to OpenAI Realtime API (model: %s)... ", *modelFlag)
-	if err := connectRealtime(openaiKey, *modelFlag); err != nil {
-		fmt.Printf("❌ Failed: %v\n", err)
-		os.Exit(1)
+	// Start the conversation; Eva's personality is sent as the first
+	// session update
+	if providerName == "cascade" {
+		fmt.Print("🧠 Starting cascade (speech-to-text → chat model → TTS)... ")
+	} else {
+		fmt.Printf("🧠 Connecting to OpenAI Realtime API (model: %s)... ", *modelFlag)
 	}
-	fmt.Println("✅")
-
-	// Configure session
-	fmt.Print("⚙️  Configuring Eva's personality... ")
-	if err := realtimeClient.ConfigureSession(evaInstructions, "shimmer"); err != nil {
+	if err := connectRealtime(ctx, openaiKey, *modelFlag); err != nil {
 		fmt.Printf("❌ Failed: %v\n", err)
 		os.Exit(1)
 	}
 	fmt.Println("✅")
 
-	// Wait for session ready
-	for i := 0; i < 50; i++ {
-		if realtimeClient.IsReady() {
-			break
-		}
-		time.Sleep(100 * time.Millisecond)
-	}
-
 	fmt.Println("\n🎤 Eva is listening! Speak to start a conversation...")
 	fmt.Println("   (Ctrl+C to exit)")
 
@@ -512,6 +672,9 @@ func main() {
 	// Start web dashboard
 	go startWebDashboard(ctx)
 
+	// Serve Eva's tools to other agents
+	go serveMCP(ctx)
+
 	// Start camera streaming to web
 	go streamCameraToWeb(ctx)
 
@@ -524,6 +687,7 @@ func main() {
 				s.OpenAIConnected = realtimeClient != nil && realtimeClient.IsConnected()
 				s.WebRTCConnected = videoClient != nil
 				s.Listening = true
+				applyListenState(s, listenCtrl.State())
 			})
 			webServer.AddLog("info", "Eva 2.0 started")
 		}
@@ -558,11 +722,23 @@ func initialize() error {
 	rateCtrl = robot.NewRateController(robotCtrl, 50*time.Millisecond)
 	go rateCtrl.Run() // Start control loop in background
 
-	// Create persistent memory (saves to ~/.eva/memory.json)
+	// Create persistent memory (~/.eva/memory.db, or memory.json with --memory-store=json)
 	homeDir, _ := os.UserHomeDir()
-	memoryPath := homeDir + "/.eva/memory.json"
-	memoryStore = memory.NewWithFile(memoryPath)
-	fmt.Printf("📝 Memory loaded from %s\n", memoryPath)
+	if err := setupMemory(homeDir); err != nil {
+		return err
+	}
+
+	// Semantic recall, indexed next to the memory file so only new
+	// memories are embedded on each run
+	vectorsPath := homeDir + "/.eva/memory.vectors.json"
+	if err := memoryStore.EnableRecall(recallEmbedder(os.Getenv("OPENAI_API_KEY")), vectorsPath); err != nil {
+		fmt.Printf("⚠️  Recall index error (rebuilding): %v\n", err)
+	}
+	go func() {
+		if err := memoryStore.SyncRecall(context.Background()); err != nil {
+			fmt.Printf("⚠️  Recall index sync failed: %v\n", err)
+		}
+	}()
 
 	// Create Spark components (idea collection)
 	// Priority: CLI flags > env vars > config file (~/.eva/config.json) > defaults
@@ -613,8 +789,21 @@ func initialize() error {
 		fmt.Println("🔥 Spark disabled")
 	}
 
+	// Load timers, alarms and reminders
+	setupScheduler()
+
+	// Connect to external tool servers
+	setupMCP()
+
 	// Create audio player
 	audioPlayer = audio.NewPlayer(robotIP, sshUser, sshPass)
+	if out, err := newAudioOutput(audioOut); err != nil {
+		fmt.Printf("⚠️  Native audio output unavailable, using SSH pipeline: %v\n", err)
+		warnNoAEC()
+	} else if out != nil {
+		audioOutput = out
+		audioPlayer.SetOutput(out)
+	}
 	audioPlayer.OnPlaybackStart = func() {
 		speakingMu.Lock()
 		speaking = true
@@ -624,6 +813,7 @@ func initialize() error {
 		speakingMu.Lock()
 		speaking = false
 		speakingMu.Unlock()
+		listenCtrl.ResponseDone() // Follow-up window
 	}
 
 	// Wire up streaming TTS audio callback (if using WebSocket streaming)
@@ -687,37 +877,32 @@ func startWebDashboard(ctx context.Context) {
 	// Create web server
 	webServer = web.NewServer("8181")
 
-	// Configure tool trigger callback
+	// Configure tool trigger callback: dashboard calls go through the
+	// same registry (validation, policy, metrics) as the model's
 	webServer.OnToolTrigger = func(name string, args map[string]interface{}) (string, error) {
 		fmt.Printf("🎮 Dashboard tool: %s (args: %v)\n", name, args)
-
-		// Get tool config - Motion routes through RateController (Issue #139)
-		cfg := eva.ToolsConfig{
-			Robot:          httpCtrl,  // For non-motion (volume, status) - always HTTP
-			Motion:         rateCtrl,   // For all motion (head, antennas, body)
-			Memory:         memoryStore,
-			Vision:         &videoVisionAdapter{videoClient},
-			ObjectDetector: &yoloAdapter{objectDetector},
-			GoogleAPIKey:   os.Getenv("GOOGLE_API_KEY"),
-			AudioPlayer:    audioPlayer,
-			Tracker:        headTracker,
-		}
-
-		// Get tools and find the one requested
-		tools := eva.Tools(cfg)
-		for _, tool := range tools {
-			if tool.Name == name {
-				result, err := tool.Handler(args)
-				if err != nil {
-					fmt.Printf("🎮 Tool error: %v\n", err)
-				} else {
-					fmt.Printf("🎮 Tool result: %s\n", result)
-				}
-				return result, err
+		if toolRegistry == nil {
+			return "", fmt.Errorf("tools not ready")
+		}
+		result, err := toolRegistry.Call(ctx, name, args)
+		if err != nil {
+			fmt.Printf("🎮 Tool error: %v\n", err)
+		} else {
+			fmt.Printf("🎮 Tool result: %s\n", result)
+		}
+		return result, err
+	}
+	if toolRegistry != nil {
+		webServer.OnGetTools = func() interface{} {
+			return toolRegistry.Stats()
+		}
+		webServer.OnSetToolEnabled = func(name string, enabled bool) error {
+			if err := toolRegistry.SetEnabled(name, enabled); err != nil {
+				return err
 			}
+			fmt.Printf("🔧 Tool %s enabled=%v (dashboard)\n", name, enabled)
+			return nil
 		}
-		fmt.Printf("🎮 Tool not found: %s\n", name)
-		return "", fmt.Errorf("tool not found: %s", name)
 	}
 
 	// Configure frame capture callback
@@ -853,6 +1038,20 @@ func startWebDashboard(ctx context.Context) {
 				tp.ScanRange = v
 			}
 
+			// === Search behavior ===
+			if v, ok := params["search_enabled"].(bool); ok {
+				tp.SearchEnabled = &v
+			}
+			if v, ok := params["search_dwell"].(float64); ok {
+				tp.SearchDwell = v
+			}
+			if v, ok := params["search_max_duration"].(float64); ok {
+				tp.SearchMaxDuration = v
+			}
+			if v, ok := params["search_give_up"].(string); ok {
+				tp.SearchGiveUp = v
+			}
+
 			headTracker.SetTuningParams(tp)
 			fmt.Printf("🎛️  Tuning params updated: %+v\n", tp)
 		}
@@ -860,6 +1059,50 @@ func startWebDashboard(ctx context.Context) {
 			headTracker.EnableTuningMode(enabled)
 			fmt.Printf("🎛️  Tuning mode: %v\n", enabled)
 		}
+		webServer.OnGetTelemetry = func(n int) interface{} {
+			return headTracker.GetTelemetry().Recent(n)
+		}
+		headTracker.GetTelemetry().Subscribe(func(r telemetry.Record) {
+			webServer.SendTelemetry(r)
+		})
+		webServer.OnGetTrackingState = func() interface{} {
+			return headTracker.GetStateInfo()
+		}
+		webServer.OnGetAutoTuneStatus = func() interface{} {
+			return headTracker.GetAutoTuneStatus()
+		}
+		webServer.OnStartAutoTune = func(axis, mode string, apply bool) error {
+			if headTracker.GetAutoTuneStatus().Running {
+				return fmt.Errorf("auto-tune already running")
+			}
+			var plant tracking.Plant = tracking.NewSimulatedPlant(time.Now().UnixNano())
+			if mode == "visual" {
+				plant = tracking.NewVisualPlant(headTracker)
+			}
+			if apply && mode != "visual" {
+				return tracking.ErrSimulatedApply
+			}
+			tuneCfg := tracking.DefaultAutoTuneConfig(tracking.Axis(axis))
+			go func() {
+				// Stopped by DELETE /api/tracking/autotune or shutdown (CancelAutoTune)
+				result, err := headTracker.AutoTune(context.Background(), tuneCfg, plant, apply)
+				if err != nil {
+					fmt.Printf("🎛️  Auto-tune %s failed: %v\n", axis, err)
+					return
+				}
+				fmt.Printf("🎛️  Auto-tune %s: best kp=%.3f kd=%.3f (score %.2f, baseline %.2f, applied=%v)\n",
+					axis, result.Best.Kp, result.Best.Kd, result.Best.Score, result.Baseline.Score, result.Applied)
+			}()
+			fmt.Printf("🎛️  Auto-tune started: axis=%s mode=%s apply=%v\n", axis, mode, apply)
+			return nil
+		}
+		webServer.OnCancelAutoTune = func() bool {
+			if !headTracker.CancelAutoTune() {
+				return false
+			}
+			fmt.Println("🎛️  Auto-tune cancelled")
+			return true
+		}
 	}
 
 	// Initialize camera configuration manager
@@ -868,12 +1111,62 @@ func startWebDashboard(ctx context.Context) {
 	fmt.Printf("📷 Camera config: %dx%d @ %dfps (default: 1080p for better tracking)\n",
 		cfg.Width, cfg.Height, cfg.Framerate)
 
+	// Apply camera configs on the robot; a config that stops the video is
+	// rolled back
+	cameraBackend := camera.NewHTTPBackend(robotIP)
+	cameraBackend.OnRestart = func() error {
+		if videoClient == nil {
+			return nil
+		}
+		fmt.Println("📷 Camera restarted, reconnecting video...")
+		return videoClient.Reconnect()
+	}
+	cameraCtrl = camera.NewController(cameraBackend, func() time.Time {
+		if videoClient == nil {
+			return time.Time{}
+		}
+		return videoClient.LastFrameTime()
+	})
+	setTrackerCrop(cfg)
+
+	// The camera settings service isn't part of the stock daemon; without
+	// it configs can't be applied, so refuse them instead of pretending
+	cameraSupported := true
+	probeCtx, cancelProbe := context.WithTimeout(ctx, 3*time.Second)
+	if eff, err := cameraCtrl.Refresh(probeCtx); errors.Is(err, camera.ErrUnsupported) {
+		cameraSupported = false
+		fmt.Println("📷 Robot has no camera settings service (/api/camera/settings): camera configs, auto-exposure and auto-framing are off")
+	} else if err != nil {
+		debug.Log("📷 Camera settings read-back unavailable: %v\n", err)
+	} else {
+		fmt.Printf("📷 Camera running at %dx%d @ %dfps\n", eff.Width, eff.Height, eff.Framerate)
+	}
+	cancelProbe()
+	if cameraSupported {
+		cameraManager.OnConfigChange = applyCameraConfig
+		startAutoExposure(ctx)
+		startAutoFraming(ctx)
+	} else {
+		cameraManager.OnConfigChange = func(camera.Config) error { return camera.ErrUnsupported }
+	}
+	if headTracker != nil && (autoExposure != nil || framer != nil) {
+		headTracker.SetFrameHandler(onTrackerFrame)
+	}
+
 	// Wire up camera API callbacks
 	webServer.OnGetCameraConfig = func() interface{} {
-		return cameraManager.GetConfigJSON()
+		cfg := cameraManager.GetConfigJSON()
+		if eff, ok := cameraCtrl.Effective(); ok {
+			cfg["effective"] = eff // What the sensor is really running
+		}
+		if autoExposure != nil {
+			cfg["auto_exposure"] = autoExposureStatus()
+		}
+		return cfg
 	}
 	webServer.OnSetCameraConfig = func(params map[string]interface{}) error {
 		if err := cameraManager.UpdateConfig(params); err != nil {
+			fmt.Printf("⚠️  Camera config not applied: %v\n", err)
 			return err
 		}
 		cfg := cameraManager.GetConfig()
@@ -947,6 +1240,9 @@ func startWebDashboard(ctx context.Context) {
 		}
 	}
 
+	// Timers, alarms and reminders (announced on the alerts channel)
+	startScheduler(ctx)
+
 	// Connect head tracker to web dashboard for state updates
 	if headTracker != nil {
 		headTracker.SetStateUpdater(&webStateAdapter{webServer})
@@ -964,6 +1260,9 @@ func startWebDashboard(ctx context.Context) {
 		}
 	}
 
+	webServer.OnSetListeningMode = setListeningMode
+	webServer.OnPushToTalk = pushToTalk
+
 	webServer.OnSetListening = func(enabled bool) {
 		pauseMu.Lock()
 		evaMuted = !enabled
@@ -1081,8 +1380,16 @@ func connectWebRTC() error {
 	return videoClient.Connect()
 }
 
-func connectRealtime(apiKey, model string) error {
-	realtimeClient = openai.NewClient(apiKey, model)
+func connectRealtime(ctx context.Context, apiKey, model string) error {
+	client, err := newProvider(apiKey, model)
+	if err != nil {
+		return err
+	}
+	realtimeClient = client
+	clientTurns.Store(useClientVAD(listenCtrl.Mode()))
+	if err := realtimeClient.ConfigureSession(realtimeSession(clientTurns.Load())); err != nil {
+		return err
+	}
 
 	// Set OpenAI key on audio player for timer announcements
 	audioPlayer.SetOpenAIKey(apiKey)
@@ -1102,20 +1409,31 @@ func connectRealtime(apiKey, model string) error {
 		SparkStore:      sparkStore,      // Idea collection
 		SparkGemini:     sparkGemini,     // Gemini for title/tag generation
 		SparkGoogleDocs: sparkGoogleDocs, // Google Docs for syncing
+		Schedule:        scheduler,       // Timers, alarms and reminders
+		MCP:             mcpManager,      // Tools from MCP servers
 	}
-	tools := eva.Tools(toolsCfg)
-	for _, tool := range tools {
-		realtimeClient.RegisterTool(openai.Tool{
-			Name:        tool.Name,
-			Description: tool.Description,
-			Parameters:  tool.Parameters,
-			Handler:     tool.Handler,
-		})
+	registry, err := eva.NewRegistry(toolsCfg)
+	if err != nil {
+		return fmt.Errorf("tool registry: %w", err)
+	}
+	registry.OnCall = func(name string, latency time.Duration, err error) {
+		if err != nil {
+			fmt.Printf("🔧 Tool %s failed after %v: %v\n", name, latency.Round(time.Millisecond), err)
+		}
+	}
+	setupBackgroundJobs(registry)
+	toolRegistry = registry
+	for _, tool := range registry.ConversationTools() {
+		realtimeClient.RegisterTool(tool)
 	}
 
 	// Set up callbacks
-	realtimeClient.OnTranscript = func(text string, isFinal bool) {
-		if isFinal && text != "" {
+	realtimeClient.OnTranscript(func(role, text string, isFinal bool) {
+		if role == "agent" && isFinal {
+			handleTranscriptDone()
+			return
+		}
+		if role == "user" && isFinal && text != "" {
 			// User's final transcript
 			fmt.Printf("👤 User: %s\n", text)
 			evaResponseStarted = false
@@ -1128,7 +1446,7 @@ func connectRealtime(apiKey, model string) error {
 				})
 				webServer.AddConversation("user", text)
 			}
-		} else if !isFinal && text != "" {
+		} else if role == "agent" && text != "" {
 			// Eva's speech - stream continuously on one line
 			if !evaResponseStarted {
 				fmt.Print("🤖 Eva: ")
@@ -1145,20 +1463,20 @@ func connectRealtime(apiKey, model string) error {
 				}
 			}
 		}
-	}
+	})
 
-	realtimeClient.OnAudioDelta = func(audioBase64 string) {
-		// Only use OpenAI audio in realtime mode
-		if ttsMode == "realtime" {
-			if err := audioPlayer.AppendAudio(audioBase64); err != nil {
+	realtimeClient.OnAudio(func(pcm []byte) {
+		// Only play the provider's audio when it speaks for Eva
+		if providerSpeaks() {
+			if err := audioPlayer.AppendPCM(pcm); err != nil {
 				fmt.Printf("⚠️  Audio append error: %v\n", err)
 			}
 		}
-	}
+	})
 
-	realtimeClient.OnAudioDone = func() {
-		// Only handle realtime TTS mode here
-		if ttsMode != "realtime" {
+	realtimeClient.OnAudioDone(func() {
+		// Only handle the provider's own audio here
+		if !providerSpeaks() {
 			return // External TTS handled in OnTranscriptDone
 		}
 
@@ -1195,183 +1513,304 @@ func connectRealtime(apiKey, model string) error {
 			webServer.AddLog("speech", "Audio done")
 		}
 		evaCurrentResponse = ""
-	}
+	})
 
-	// OnTranscriptDone fires when OpenAI's transcript is complete
-	// Use this for external TTS (ElevenLabs/OpenAI TTS) to ensure we have full text
-	realtimeClient.OnTranscriptDone = func() {
-		// Only handle external TTS modes here
-		if ttsMode == "realtime" {
-			return // Realtime audio handled in OnAudioDone
+	realtimeClient.OnError(func(err error) {
+		fmt.Printf("⚠️  Error: %v\n", err)
+		if webServer != nil {
+			webServer.AddLog("error", err.Error())
 		}
+	})
 
-		// End the Eva response line
-		if evaResponseStarted {
-			fmt.Println() // newline after streaming text
-			evaResponseStarted = false
+	// User started speaking - if Eva is talking, interrupt her
+	bargeIn = conversation.NewBargeIn(realtimeClient, audioPlayer)
+	bargeIn.OnBargeIn = func() {
+		fmt.Println("🛑 [interrupted]")
+	}
+	realtimeClient.OnInterruption(func() {
+		listenCtrl.SpeechStarted()
+		if audioPlayer != nil {
+			bargeIn.Interrupt()
 		}
-
-		// Skip if no text to synthesize
-		if evaCurrentResponse == "" {
-			return
+	})
+	realtimeClient.OnSpeechStopped(func() {
+		if !clientTurns.Load() {
+			listenCtrl.TurnEnded() // Server VAD ended the turn
 		}
+	})
 
-		// Update web dashboard with Eva's response
-		if webServer != nil {
-			webServer.UpdateState(func(s *web.EvaState) {
-				s.Speaking = true
-				s.Listening = false
-				s.LastEvaMessage = evaCurrentResponse
-			})
-			webServer.AddConversation("eva", evaCurrentResponse)
-			webServer.AddLog("speech", "Synthesizing with "+ttsMode+"...")
-		}
+	return realtimeClient.Connect(ctx)
+}
 
-		// Streaming TTS: just flush to signal end of text
-		if ttsMode == "elevenlabs-streaming" && ttsStreaming != nil {
-			debug.Log("🗣️  Flushing streaming TTS...\n")
-			if err := ttsStreaming.Flush(); err != nil {
-				fmt.Printf("⚠️  Streaming TTS flush error: %v\n", err)
-			}
-			// Audio playback handled by ttsStreaming.OnAudio callback
-			evaCurrentResponse = ""
-			return
-		}
+// handleTranscriptDone runs when OpenAI's transcript is complete
+// Use this for external TTS (ElevenLabs/OpenAI TTS) to ensure we have full text
+func handleTranscriptDone() {
+	// Only handle external TTS modes here
+	if providerSpeaks() {
+		return // Provider audio handled in OnAudioDone
+	}
 
-		// Use HTTP TTS provider (ElevenLabs or OpenAI TTS)
-		if ttsProvider != nil {
-			fmt.Printf("🗣️  [synthesizing with %s...]\n", ttsMode)
-			go func(text string) {
-				result, err := ttsProvider.Synthesize(context.Background(), text)
-				if err != nil {
-					fmt.Printf("⚠️  TTS error: %v\n", err)
-					return
-				}
-				fmt.Printf("🗣️  TTS: %d bytes, %d latency\n", len(result.Audio), result.LatencyMs)
+	// End the Eva response line
+	if evaResponseStarted {
+		fmt.Println() // newline after streaming text
+		evaResponseStarted = false
+	}
 
-				// Play the PCM audio
-				if err := audioPlayer.PlayPCM(result.Audio); err != nil {
-					fmt.Printf("⚠️  Audio playback error: %v\n", err)
-				}
-				fmt.Println("🗣️  [done]")
-
-				// Update web dashboard
-				if webServer != nil {
-					webServer.UpdateState(func(s *web.EvaState) {
-						s.Speaking = false
-						s.Listening = true
-					})
-					webServer.AddLog("speech", "Audio done")
-				}
-			}(evaCurrentResponse)
-		}
+	// Skip if no text to synthesize
+	if evaCurrentResponse == "" {
+		return
+	}
 
-		evaCurrentResponse = ""
+	// Update web dashboard with Eva's response
+	if webServer != nil {
+		webServer.UpdateState(func(s *web.EvaState) {
+			s.Speaking = true
+			s.Listening = false
+			s.LastEvaMessage = evaCurrentResponse
+		})
+		webServer.AddConversation("eva", evaCurrentResponse)
+		webServer.AddLog("speech", "Synthesizing with "+ttsMode+"...")
 	}
 
-	realtimeClient.OnError = func(err error) {
-		fmt.Printf("⚠️  Error: %v\n", err)
-		if webServer != nil {
-			webServer.AddLog("error", err.Error())
+	// Streaming TTS: just flush to signal end of text
+	if ttsMode == "elevenlabs-streaming" && ttsStreaming != nil {
+		debug.Log("🗣️  Flushing streaming TTS...\n")
+		if err := ttsStreaming.Flush(); err != nil {
+			fmt.Printf("⚠️  Streaming TTS flush error: %v\n", err)
 		}
+		// Audio playback handled by ttsStreaming.OnAudio callback
+		evaCurrentResponse = ""
+		return
 	}
 
-	realtimeClient.OnSessionCreated = func() {
-		fmt.Println("   Session created!")
-	}
+	// Use HTTP TTS provider (ElevenLabs or OpenAI TTS)
+	if ttsProvider != nil {
+		fmt.Printf("🗣️  [synthesizing with %s...]\n", ttsMode)
+		go func(text string) {
+			result, err := ttsProvider.Synthesize(context.Background(), text)
+			if err != nil {
+				fmt.Printf("⚠️  TTS error: %v\n", err)
+				return
+			}
+			fmt.Printf("🗣️  TTS: %d bytes, %d latency\n", len(result.Audio), result.LatencyMs)
 
-	realtimeClient.OnSpeechStarted = func() {
-		// User started speaking - if Eva is talking, interrupt her
-		if audioPlayer != nil && audioPlayer.IsSpeaking() {
-			fmt.Println("🛑 [interrupted]")
-			audioPlayer.Cancel()
-			realtimeClient.CancelResponse()
-		}
+			// Play the PCM audio
+			if err := audioPlayer.PlayPCM(result.Audio); err != nil {
+				fmt.Printf("⚠️  Audio playback error: %v\n", err)
+			}
+			fmt.Println("🗣️  [done]")
+
+			// Update web dashboard
+			if webServer != nil {
+				webServer.UpdateState(func(s *web.EvaState) {
+					s.Speaking = false
+					s.Listening = true
+				})
+				webServer.AddLog("speech", "Audio done")
+			}
+		}(evaCurrentResponse)
 	}
 
-	return realtimeClient.Connect()
+	evaCurrentResponse = ""
 }
 
 func streamAudioToRealtime(ctx context.Context) {
-	// Buffer for accumulating audio
-	var audioBuffer []int16
-	const chunkSize = 2400 // 100ms at 24kHz
+	// Wait for the WebRTC client
+	for videoClient == nil {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+
+	// Subscribe to the mic and resample 48kHz → 24kHz (OpenAI Realtime)
+	// in 20ms frames for echo cancellation and VAD, then re-chunk to 100ms
+	sub := videoClient.SubscribeAudio(video.DefaultAudioBuffer)
+	defer sub.Close()
+	frameStage := audio.NewMicStage(video.MicSampleRate, 24000, 20*time.Millisecond)
+	mic := audio.NewMicStage(24000, 24000, 100*time.Millisecond)
+
+	// Beamformed audio from the mic array, when go-eva streams it, stands
+	// in for the WebRTC track
+	var beamFrames <-chan video.AudioFrame
+	if beam != nil {
+		beamFrames = beam.frames
+	}
+	fromBeam := false
+
+	// With echo cancellation Eva keeps listening while she speaks, so the
+	// user can barge in
+	var aec *audio.EchoCanceller
+	var aecClock time.Time // Mic timeline, advanced by samples so arrival jitter doesn't misalign the reference
+	if echoReference != nil {
+		aec = audio.NewEchoCanceller(echoReference, audio.DefaultEchoCancellerConfig())
+		if aecRecordDir != "" {
+			rec, err := newAECRecording(aecRecordDir, 24000)
+			if err != nil {
+				fmt.Printf("⚠️  AEC recording disabled: %v\n", err)
+			} else {
+				aecRecorder = rec
+				aec.Tap = rec.write
+			}
+		}
+	}
+
+	// Local VAD runs on the same (echo-cancelled) audio as the uplink. In
+	// --vad=local mode its events also gate what is sent and end turns.
+	var vadStage *audio.MicStage
+	var gate *vad.Gate
+	if speechDetector != nil {
+		cfg := speechDetector.Config()
+		vadStage = audio.NewMicStage(24000, cfg.SampleRate, cfg.FrameDuration)
+		if vadMode == "local" {
+			gate = vad.NewGate(24000, cfg.GatePrefix())
+		}
+	}
+
+	// Listening modes: recent audio lets a wake word's utterance be sent
+	// once the spotter has found it
+	recent := listen.NewRecent(24000, 5*time.Second)
+	spotting := false
+	var wakeEnd time.Time // When the last wake word was spotted
 
 	// Counters for debug logging
-	var loopCount, emptyCount, sentCount int
+	var frameCount, skippedCount, sentCount int
 	lastLogTime := time.Now()
 
 	debug.Logln("🎵 Audio streaming goroutine started")
 
 	for {
+		var frame video.AudioFrame
 		select {
 		case <-ctx.Done():
 			debug.Logln("🎵 Audio streaming stopped (context cancelled)")
 			return
-		default:
+		case f, ok := <-sub.Frames():
+			if !ok {
+				debug.Logln("🎵 Audio streaming stopped (video client closed)")
+				return
+			}
+			if beam != nil && beam.live() {
+				continue
+			}
+			frame = f
+		case f := <-beamFrames:
+			frame = f
+		}
+		if usingBeam := beamFrames != nil && beam.live(); usingBeam != fromBeam {
+			debug.Log("🎵 Mic source: beamformed=%v\n", usingBeam)
+			fromBeam = usingBeam
+			frameStage.Reset() // Don't splice the two sources together
+		}
+
+		frameCount++
+		if frameCount == 1 {
+			debug.Log("🎵 First audio frame: %d samples\n", len(frame.Samples))
+		}
+		if time.Since(lastLogTime) > 5*time.Second {
+			debug.Log("🎵 Audio stats: frames=%d, skipped=%d, sent=%d, dropped=%d\n",
+				frameCount, skippedCount, sentCount, sub.Dropped())
+			if aec != nil {
+				st := aec.Stats()
+				debug.Log("🎵 AEC: delay=%v, erle=%.1fdB, converged=%v\n", st.Delay, st.ERLE, st.Converged)
+			}
+			lastLogTime = time.Now()
 		}
 
-		loopCount++
-
 		// Don't send audio while speaking (to avoid echo)
 		speakingMu.Lock()
 		isSpeaking := speaking
 		speakingMu.Unlock()
 
-		if isSpeaking {
-			time.Sleep(50 * time.Millisecond)
-			continue
-		}
-
 		// Don't send audio if Eva is paused or muted
 		pauseMu.Lock()
 		isPaused := evaPaused
 		isMuted := evaMuted
 		pauseMu.Unlock()
 
-		if isPaused || isMuted {
-			time.Sleep(50 * time.Millisecond)
+		if (isSpeaking && aec == nil) || isPaused || isMuted {
+			skippedCount++
+			mic.Reset() // Don't splice stale audio onto the next chunk
+			frameStage.Reset()
+			if speechDetector != nil {
+				if speechDetector.Speaking() {
+					handleSpeechEvent(vad.Event{Type: vad.SpeechEnd, Time: frame.Time})
+				}
+				speechDetector.Reset()
+				vadStage.Reset()
+			}
+			if gate != nil {
+				gate.Reset()
+			}
 			continue
 		}
 
-		// Get audio from WebRTC (48kHz)
-		if videoClient == nil {
-			if loopCount == 1 {
-				debug.Logln("🎵 videoClient is nil!")
+		if drift := frame.Time.Sub(aecClock); drift > 200*time.Millisecond || drift < -200*time.Millisecond {
+			aecClock = frame.Time // First frame or a gap in capture
+		}
+		var samples []int16
+		for _, block := range frameStage.Process(frame.Samples) {
+			if aec != nil {
+				block = aec.Process(block, aecClock)
 			}
-			time.Sleep(50 * time.Millisecond)
-			continue
+			samples = append(samples, block...)
+			aecClock = aecClock.Add(20 * time.Millisecond)
 		}
 
-		// Record a small chunk
-		videoClient.StartRecording()
-		time.Sleep(100 * time.Millisecond)
-		pcmData := videoClient.StopRecording()
+		var events []vad.Event
+		if speechDetector != nil {
+			for _, block := range vadStage.Process(samples) {
+				events = append(events, speechDetector.Process(block, frame.Time)...)
+			}
+		}
 
-		if len(pcmData) == 0 {
-			emptyCount++
-			// Log every 5 seconds if getting empty audio
-			if time.Since(lastLogTime) > 5*time.Second {
-				debug.Log("🎵 Audio stats: loops=%d, empty=%d, sent=%d (empty audio!)\n", loopCount, emptyCount, sentCount)
-				lastLogTime = time.Now()
+		// Wake words are spotted locally while the mic is closed; on a hit
+		// the utterance is sent from the start of the wake word
+		recent.Write(samples, frame.Time)
+		var backlog []int16
+		if wakeSpotter != nil && listenCtrl.Mode() == listen.ModeWakeWord && !listenCtrl.Open() {
+			spotting = true
+			for _, det := range wakeSpotter.Process(samples, frame.Time) {
+				if wake(det) {
+					backlog = recent.Since(det.Start)
+					wakeEnd = det.End
+					break
+				}
 			}
-			continue
+		} else if spotting {
+			spotting = false
+			wakeSpotter.Reset()
 		}
 
-		// First time we get audio
-		if sentCount == 0 && emptyCount == 0 {
-			debug.Log("🎵 First audio chunk: %d samples\n", len(pcmData))
+		// Push-to-talk sends everything while the button is held
+		listening := listenCtrl.State()
+		if gate != nil {
+			if gated := gate.Process(samples, events); listening.Reason != listen.ReasonPushToTalk {
+				samples = gated
+			}
+		}
+		if backlog != nil {
+			samples = backlog
 		}
 
-		// Resample from 48kHz to 24kHz (OpenAI Realtime uses 24kHz)
-		resampled := audio.Resample(pcmData, 48000, 24000)
-		audioBuffer = append(audioBuffer, resampled...)
+		// End a push-to-talk turn once the button is released
+		if pttReleased.Swap(false) {
+			mic.Reset()
+			if realtimeClient != nil && realtimeClient.IsConnected() {
+				if err := realtimeClient.Commit(); err != nil {
+					debug.Log("🎵 Commit error: %v\n", err)
+				}
+			}
+		}
+		if !listening.Open {
+			skippedCount++
+			mic.Reset()
+			continue
+		}
 
-		// Send when we have enough
-		if len(audioBuffer) >= chunkSize {
-			// Convert to bytes
-			pcm16Bytes := audio.ConvertInt16ToPCM16(audioBuffer[:chunkSize])
-			audioBuffer = audioBuffer[chunkSize:]
+		for _, chunk := range mic.Process(samples) {
+			pcm16Bytes := audio.ConvertInt16ToPCM16(chunk)
 
 			// Send to Realtime API
 			if realtimeClient == nil {
@@ -1392,13 +1831,96 @@ func streamAudioToRealtime(ctx context.Context) {
 				}
 			}
 		}
+
+		// With client-side turns, end the turn once everything up to the
+		// endpoint has been sent (push-to-talk waits for the release)
+		clientVAD := realtimeClient != nil && clientTurns.Load()
+		if ev, ok := findEndpoint(events); ok && clientVAD && listening.Reason != listen.ReasonPushToTalk {
+			if listening.Reason == listen.ReasonWakeWord && ev.Time.Before(wakeEnd.Add(300*time.Millisecond)) {
+				continue // Only the wake word was said; wait for the request
+			}
+			mic.Reset() // Drop the partial chunk of trailing silence
+			if realtimeClient.IsConnected() {
+				if err := realtimeClient.Commit(); err != nil {
+					debug.Log("🎵 Commit error: %v\n", err)
+				}
+			}
+			listenCtrl.TurnEnded()
+		}
 	}
 }
 
+// newSpeechDetector builds the local VAD: a Silero-style ONNX model at
+// 16kHz when modelPath is set, otherwise the feature classifier at the
+// uplink rate.
+func newSpeechDetector(modelPath string) (*vad.Detector, error) {
+	cfg := vad.DefaultConfig()
+	if modelPath == "" {
+		return vad.New(vad.NewFeatureClassifier(cfg.SampleRate), cfg), nil
+	}
+
+	onnxCfg := vad.DefaultONNXConfig()
+	onnxCfg.ModelPath = modelPath
+	cls, err := vad.NewONNXClassifier(onnxCfg)
+	if err != nil {
+		return nil, err
+	}
+	cfg.SampleRate = cls.SampleRate()
+	return vad.New(cls, cfg), nil
+}
+
+// handleSpeechEvent publishes local VAD speech start/end to the tracker
+// and dashboard. With --vad=local, speech also interrupts Eva (there is
+// no server speech_started event); endpoints are handled by the uplink.
+func handleSpeechEvent(ev vad.Event) {
+	if ev.Type == vad.Endpoint {
+		return
+	}
+	speaking := ev.Type == vad.SpeechStart
+	debug.Log("🗣️  VAD %s (p=%.2f)\n", ev.Type, ev.Probability)
+
+	if headTracker != nil {
+		headTracker.SetUserSpeaking(speaking, ev.Angle, ev.HasAngle)
+	}
+	if webServer != nil {
+		webServer.UpdateState(func(s *web.EvaState) {
+			s.UserSpeaking = speaking
+		})
+	}
+	if !speaking || !listenCtrl.Open() {
+		return // Nobody is talking to Eva
+	}
+	listenCtrl.SpeechStarted()
+	if vadMode == "local" && bargeIn != nil && audioPlayer != nil {
+		bargeIn.Interrupt()
+	}
+}
+
+func findEndpoint(events []vad.Event) (vad.Event, bool) {
+	for _, ev := range events {
+		if ev.Type == vad.Endpoint {
+			return ev, true
+		}
+	}
+	return vad.Event{}, false
+}
+
 func shutdown() {
+	if toolRegistry != nil {
+		toolRegistry.Cancel("") // Stop background tools
+	}
+	if headTracker != nil {
+		headTracker.CancelAutoTune() // Stop moving the head for tuning
+	}
 	if realtimeClient != nil {
 		realtimeClient.Close()
 	}
+	if mcpManager != nil {
+		mcpManager.Close() // Stop server processes
+	}
+	if memoryStore != nil {
+		memoryStore.Close()
+	}
 	if videoClient != nil {
 		videoClient.Close()
 	}
@@ -1408,6 +1930,17 @@ func shutdown() {
 	if ttsStreaming != nil {
 		ttsStreaming.Close()
 	}
+	if headTracker != nil && headTracker.GetTelemetry() != nil {
+		if err := headTracker.GetTelemetry().Close(); err != nil { // Flush telemetry file
+			fmt.Printf("⚠️  Telemetry file: %v\n", err)
+		}
+	}
+	if audioOutput != nil {
+		audioOutput.Close() // Finalise WAV header for file:PATH
+	}
+	if aecRecorder != nil {
+		aecRecorder.Close()
+	}
 }
 
 // videoVisionAdapter wraps video.Client to implement VisionProvider
@@ -1449,3 +1982,142 @@ func (y *yoloAdapter) Detect(jpeg []byte) ([]eva.ObjectDetectionResult, error) {
 	}
 	return results, nil
 }
+
+// newAudioOutput creates the native audio output for the --audio-out mode.
+// It returns nil for the legacy SSH pipeline.
+func newAudioOutput(mode string) (*audio.Output, error) {
+	var sink audio.AudioSink
+	var err error
+	switch {
+	case mode == "ssh":
+		fmt.Println("🔊 Audio output: SSH + gst-launch")
+		warnNoAEC()
+		return nil, nil
+	case mode == "native":
+		addr := net.JoinHostPort(robotIP, "22")
+		sink, err = audio.NewSSHSink(addr, sshUser, sshPass)
+		if err == nil {
+			fmt.Printf("🔊 Audio output: Opus/RTP over SSH relay → %s receiver\n", robotIP)
+		}
+	case mode == "udp":
+		addr := net.JoinHostPort(robotIP, "5000")
+		sink, err = audio.NewUDPSink(addr)
+		if err == nil {
+			fmt.Printf("🔊 Audio output: Opus/RTP → %s\n", addr)
+		}
+	case strings.HasPrefix(mode, "file:"):
+		path := strings.TrimPrefix(mode, "file:")
+		sink, err = audio.NewFileSink(path)
+		if err == nil {
+			fmt.Printf("🔊 Audio output: %s\n", path)
+		}
+	default:
+		return nil, fmt.Errorf("unknown audio output %q", mode)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep a copy of everything played so the mic can cancel it
+	if aecEnabled {
+		echoReference = audio.NewEchoReference(24000, 2*time.Second)
+		sink = audio.NewTeeSink(sink, echoReference)
+		fmt.Println("🔊 Echo cancellation on: Eva listens while speaking")
+	}
+
+	cfg := audio.DefaultOutputConfig()
+	if err := setVolumes(cfg.Channels, audioVolumes); err != nil {
+		sink.Close()
+		echoReference = nil
+		return nil, err
+	}
+	out, err := audio.NewOutput(sink, cfg)
+	if err != nil {
+		sink.Close()
+		echoReference = nil
+		return nil, err
+	}
+	return out, nil
+}
+
+// warnNoAEC says echo cancellation is off when the SSH pipeline plays
+// audio, since it bypasses the echo reference.
+func warnNoAEC() {
+	if aecEnabled {
+		fmt.Println("⚠️  Echo cancellation needs --audio-out native; off, Eva won't listen while speaking")
+	}
+}
+
+// setVolumes applies a --volumes list ("effects=0.8,music=0.3") to the
+// mixer layout.
+func setVolumes(channels map[audio.Channel]audio.ChannelConfig, list string) error {
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(item, "=")
+		if !ok {
+			return fmt.Errorf("volume %q: want channel=volume", item)
+		}
+		ch := audio.Channel(strings.TrimSpace(name))
+		cc, ok := channels[ch]
+		if !ok {
+			return fmt.Errorf("volume %q: unknown channel %q", item, ch)
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || v < 0 {
+			return fmt.Errorf("volume %q: want a number >= 0", item)
+		}
+		cc.Volume = v
+		channels[ch] = cc
+	}
+	return nil
+}
+
+// aecRecording writes the echo canceller's mic/reference pairs as WAV
+// files, for replaying with cmd/aec-eval or as test data.
+type aecRecording struct {
+	mu  sync.Mutex
+	mic *audio.WAVWriter
+	ref *audio.WAVWriter
+}
+
+func newAECRecording(dir string, rate int) (*aecRecording, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	name := time.Now().Format("20060102-150405")
+	mic, err := audio.CreateWAV(filepath.Join(dir, name+"_mic.wav"), rate)
+	if err != nil {
+		return nil, err
+	}
+	ref, err := audio.CreateWAV(filepath.Join(dir, name+"_ref.wav"), rate)
+	if err != nil {
+		mic.Close()
+		return nil, err
+	}
+	fmt.Printf("📼 Recording echo pairs → %s/%s_{mic,ref}.wav\n", dir, name)
+	return &aecRecording{mic: mic, ref: ref}, nil
+}
+
+func (r *aecRecording) write(mic, ref []int16) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.mic == nil {
+		return
+	}
+	r.mic.Write(mic)
+	r.ref.Write(ref)
+}
+
+func (r *aecRecording) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.mic == nil {
+		return
+	}
+	r.mic.Close()
+	r.ref.Close()
+	r.mic, r.ref = nil, nil
+}
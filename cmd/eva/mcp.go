@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/teslashibe/go-reachy/pkg/mcp"
+)
+
+// mcpInstructions tell other agents' models what Eva's tools are for.
+const mcpInstructions = "Eva is a Reachy Mini robot. These tools move her head and antennas, play emotions, " +
+	"remember people and facts, and look around with her camera. They act on the real robot while it talks to people."
+
+var (
+	mcpManager *mcp.Manager    // External tool servers (nil if none are configured)
+	mcpServe   string          // --mcp-serve: "", "stdio", "http" or an address
+	mcpStdout  *os.File        // The real stdout in stdio mode, where MCP messages go
+	mcpConfig  mcp.ServeConfig // Who may call Eva's tools
+)
+
+// setupMCP connects to the MCP servers in ~/.eva/config.json. Servers
+// that fail are logged and left out; the rest still give Eva tools.
+func setupMCP() {
+	cfg, err := mcp.LoadConfig()
+	if err != nil {
+		fmt.Printf("⚠️  MCP config error: %v\n", err)
+		return
+	}
+	mcpConfig = cfg.Serve
+	if len(cfg.Servers) == 0 {
+		return
+	}
+
+	mcpManager = mcp.NewManager(cfg)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := mcpManager.Connect(ctx); err != nil {
+		fmt.Printf("⚠️  MCP: %v\n", err)
+	}
+
+	for _, st := range mcpManager.Status() {
+		if !st.Connected {
+			continue
+		}
+		fmt.Printf("🔌 MCP %s (%s): %d tools allowed [%s]\n",
+			st.Name, st.Server, len(st.Tools), strings.Join(st.Tools, ", "))
+	}
+}
+
+// serveMCP serves Eva's tools to other agents over stdio or HTTP, as
+// --mcp-serve says. Calls go through the same registry as the model's,
+// so they move the same robot and use the same memory. Closing stdin
+// in stdio mode shuts Eva down, as MCP clients expect.
+func serveMCP(ctx context.Context) {
+	if mcpServe == "" || toolRegistry == nil {
+		return
+	}
+	if mcpConfig.Token == "" {
+		mcpConfig.Token = os.Getenv("EVA_MCP_TOKEN")
+	}
+	server := mcp.NewServer(mcp.Implementation{Name: "eva", Version: "2.0"}, mcpInstructions,
+		toolRegistry.MCPTools(), mcpConfig)
+	if len(server.ToolNames()) == 0 {
+		fmt.Println("⚠️  MCP: no tools allowed; set mcp.serve.allow in ~/.eva/config.json")
+	}
+
+	if mcpServe == "stdio" {
+		fmt.Printf("🔌 Serving %d tools over MCP (stdio) [%s]\n",
+			len(server.ToolNames()), strings.Join(server.ToolNames(), ", "))
+		if err := server.ServeStdio(ctx, os.Stdin, mcpStdout); err != nil && ctx.Err() == nil {
+			fmt.Printf("⚠️  MCP stdio: %v\n", err)
+		}
+		if ctx.Err() == nil {
+			fmt.Println("👋 MCP client disconnected")
+			shutdown()
+			os.Exit(0)
+		}
+		return
+	}
+
+	addr := mcpServe
+	if addr == "http" {
+		addr = mcpConfig.Addr
+	}
+	if addr == "" {
+		addr = mcp.DefaultServeAddr
+	}
+	fmt.Printf("🔌 Serving %d tools over MCP at http://%s [%s]\n",
+		len(server.ToolNames()), addr, strings.Join(server.ToolNames(), ", "))
+	if err := server.ListenAndServe(ctx, addr); err != nil {
+		fmt.Printf("⚠️  MCP server: %v\n", err)
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/teslashibe/go-reachy/pkg/memory"
+)
+
+var memoryBackend string // --memory-store: "sqlite" or "json"
+
+var (
+	embedURL   string // --embed-url: OpenAI-compatible embeddings base URL for recall
+	embedModel string // --embed-model: embedding model for recall
+)
+
+// errJSONImport marks a failed import of memory.json. The file is
+// then still Eva's up-to-date memory.
+var errJSONImport = errors.New("import memory.json")
+
+// setupMemory opens Eva's persistent memory in ~/.eva. Until the
+// SQLite store has imported memory.json (the database records it), it
+// imports it on open, so nothing Eva learned before is lost; the JSON
+// file is left as a backup. If the database can't be opened before it
+// holds anything, Eva falls back to the JSON file, which is imported
+// once the database opens. Once it holds memories, the JSON file is
+// stale and Eva refuses to fall back to it.
+func setupMemory(homeDir string) error {
+	jsonPath := filepath.Join(homeDir, ".eva", "memory.json")
+	switch memoryBackend {
+	case "json":
+	case "sqlite":
+		dbPath := filepath.Join(homeDir, ".eva", "memory.db")
+		_, statErr := os.Stat(dbPath)
+		existed := statErr == nil
+		m, err := openSQLiteMemory(dbPath, jsonPath)
+		if err == nil {
+			memoryStore = m
+			fmt.Printf("📝 Memory loaded from %s\n", dbPath)
+			return nil
+		}
+		if existed && !errors.Is(err, errJSONImport) {
+			return fmt.Errorf("memory database %s: %w (fix or move it; --memory-store=json uses %s, which lacks what Eva learned since switching to the database)", dbPath, err, jsonPath)
+		}
+		fmt.Printf("⚠️  Memory database error: %v\n", err)
+		fmt.Printf("⚠️  Using %s for this run; it is imported into %s once the database opens\n", jsonPath, dbPath)
+	default:
+		return fmt.Errorf("unknown --memory-store %q (use sqlite or json)", memoryBackend)
+	}
+
+	memoryStore = memory.NewWithFile(jsonPath)
+	fmt.Printf("📝 Memory loaded from %s\n", jsonPath)
+	return nil
+}
+
+// openSQLiteMemory opens the database, importing jsonPath if the file
+// exists and the database hasn't imported one yet. A database that
+// opens but can't be loaded is an error, not an empty memory.
+func openSQLiteMemory(dbPath, jsonPath string) (*memory.Memory, error) {
+	if _, err := os.Stat(jsonPath); err == nil {
+		store, err := memory.NewSQLiteStore(dbPath)
+		if err != nil {
+			return nil, err
+		}
+		imported, err := store.ImportJSONFileOnce(jsonPath)
+		store.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errJSONImport, err) // Tried again next time
+		}
+		if imported {
+			fmt.Printf("📝 Imported %s into the memory database\n", jsonPath)
+		}
+	}
+	return memory.NewWithSQLite(dbPath)
+}
+
+// recallEmbedder picks the embedder for semantic recall: --embed-url,
+// else OpenAI if there's a key, else the local HashEmbedder, which
+// matches words rather than meaning but needs no network.
+func recallEmbedder(apiKey string) memory.Embedder {
+	if embedURL == "" && apiKey == "" {
+		fmt.Println("📝 Recall: no OPENAI_API_KEY or --embed-url, matching words locally")
+		return memory.NewHashEmbedder()
+	}
+	return memory.NewOpenAIEmbedder(embedURL, apiKey, embedModel)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/teslashibe/go-reachy/pkg/conversation"
+)
+
+var (
+	providerName string // --provider: "realtime" or "cascade"
+	cascadeSTT   string // --cascade-stt: OpenAI-compatible speech-to-text base URL
+	cascadeLLM   string // --cascade-llm: OpenAI-compatible chat-completions base URL
+	cascadeModel string // --cascade-model: chat model for the cascade
+)
+
+// evaProvider is what Eva needs from a conversation provider: local
+// endpointing commits turns and clears abandoned audio, and follow-up
+// listening needs to know when the provider ended a turn.
+type evaProvider interface {
+	conversation.Provider
+	conversation.Committer
+	ClearAudio() error
+	OnSpeechStopped(fn func())
+}
+
+// newProvider creates the conversation provider chosen with --provider.
+// Both take and return PCM16 at 24kHz, the rate of Eva's mic stage and
+// player.
+func newProvider(apiKey, model string) (evaProvider, error) {
+	switch providerName {
+	case "realtime":
+		p, err := conversation.NewOpenAI(
+			conversation.WithAPIKey(apiKey),
+			conversation.WithModel(model),
+			conversation.WithReadTimeout(0), // Eva may sit quietly for a long time
+		)
+		if err != nil {
+			return nil, err
+		}
+		return p, nil
+	case "cascade":
+		if ttsProvider == nil {
+			return nil, fmt.Errorf("--provider=cascade needs --tts=elevenlabs or openai-tts (with --tts-url for a local server)")
+		}
+		p, err := conversation.NewCascade(conversation.CascadeComponents{
+			STT: conversation.NewWhisperTranscriber(cascadeSTT, apiKey, ""),
+			TTS: ttsProvider,
+		},
+			conversation.WithAPIKey(apiKey),
+			conversation.WithBaseURL(cascadeLLM),
+			conversation.WithModel(cascadeModel),
+			conversation.WithInputSampleRate(24000),
+			conversation.WithOutputSampleRate(24000),
+		)
+		if err != nil {
+			return nil, err
+		}
+		return p, nil
+	default:
+		return nil, fmt.Errorf("unknown --provider %q (use realtime or cascade)", providerName)
+	}
+}
+
+// cascadeCloudFlags returns the flags that would point the cascade at
+// a local server instead of OpenAI, for each part still using OpenAI,
+// which needs OPENAI_API_KEY. None means it can run without a key.
+func cascadeCloudFlags(tts, ttsURL string) []string {
+	var flags []string
+	if cascadeSTT == "" {
+		flags = append(flags, "--cascade-stt")
+	}
+	if cascadeLLM == "" {
+		flags = append(flags, "--cascade-llm")
+	}
+	if tts == "openai-tts" && ttsURL == "" {
+		flags = append(flags, "--tts-url")
+	}
+	return flags
+}
+
+// providerSpeaks reports whether Eva plays the provider's audio, rather
+// than speaking its transcript with a separate TTS.
+func providerSpeaks() bool {
+	return ttsMode == "realtime" || providerName == "cascade"
+}
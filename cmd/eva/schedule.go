@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/teslashibe/go-reachy/pkg/audio"
+	"github.com/teslashibe/go-reachy/pkg/schedule"
+	"github.com/teslashibe/go-reachy/pkg/web"
+)
+
+// defaultTimerEmotions is what Eva plays when an item without its own
+// emotion goes off.
+var defaultTimerEmotions = map[schedule.Kind]string{
+	schedule.KindTimer:    "enthusiastic1",
+	schedule.KindAlarm:    "cheerful1",
+	schedule.KindReminder: "attentive1",
+}
+
+var scheduler *schedule.Scheduler // Timers, alarms and reminders
+
+// setupScheduler loads the persisted timers, alarms and reminders. If the
+// file can't be opened Eva still keeps timers, in memory only.
+func setupScheduler() {
+	s, err := schedule.NewDefault()
+	if err != nil {
+		fmt.Printf("⚠️  Schedule error: %v (timers won't survive a restart)\n", err)
+		s, _ = schedule.New("") // In memory; can't fail
+	}
+	scheduler = s
+	fmt.Printf("⏰ Schedule loaded (%d pending)\n", len(s.List()))
+}
+
+// startScheduler fires timers, alarms and reminders and keeps the
+// dashboard's countdowns current. Items that came due while Eva was
+// off go off right away.
+func startScheduler(ctx context.Context) {
+	if scheduler == nil {
+		return
+	}
+
+	scheduler.OnFire = func(f schedule.Firing) {
+		go announceFiring(ctx, f)
+	}
+	scheduler.OnChange = updateTimerState
+	webServer.OnGetTimers = func() interface{} {
+		return timerStates(time.Now())
+	}
+	webServer.OnCancelTimer = func(id string) error {
+		it, err := scheduler.Cancel(id)
+		if err == nil {
+			webServer.AddLog("tool", "Cancelled "+it.Name())
+		}
+		return err
+	}
+
+	go scheduler.Run(ctx)
+
+	// Tick the dashboard countdowns while anything is pending
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if len(scheduler.List()) > 0 {
+					updateTimerState()
+				}
+			}
+		}
+	}()
+	updateTimerState()
+}
+
+// announceFiring speaks a timer, alarm or reminder on the alerts
+// channel while playing its emotion.
+func announceFiring(ctx context.Context, f schedule.Firing) {
+	msg := firingMessage(f)
+	fmt.Printf("🔔 %s\n", msg)
+	if webServer != nil {
+		webServer.AddLog("tool", msg)
+	}
+
+	if emotionRegistry != nil {
+		name := f.Emotion
+		if _, err := emotionRegistry.Get(name); err != nil {
+			name = defaultTimerEmotions[f.Kind]
+		}
+		if emotion, err := emotionRegistry.Get(name); err == nil {
+			go func() {
+				if err := emotionRegistry.PlaySync(ctx, name); err != nil {
+					fmt.Printf("🎭 Emotion playback error: %v\n", err)
+				}
+			}()
+			if emotion.HasSound && audioPlayer != nil {
+				go func() {
+					if err := audioPlayer.PlayWAV(audio.ChannelEffects, emotion.SoundPath); err != nil {
+						fmt.Printf("🎭 Emotion sound error: %v\n", err)
+					}
+				}()
+			}
+		}
+	}
+
+	if audioPlayer == nil {
+		fmt.Println("🔔 Error: AudioPlayer is nil, cannot announce timer")
+		return
+	}
+	if err := audioPlayer.SpeakText(msg); err != nil {
+		fmt.Printf("🔔 Timer TTS error: %v\n", err)
+	}
+}
+
+// firingMessage is what Eva says when an item goes off.
+func firingMessage(f schedule.Firing) string {
+	var msg string
+	switch f.Kind {
+	case schedule.KindTimer:
+		msg = "Timer done!"
+		if f.Label != "" {
+			msg = fmt.Sprintf("Your %s timer is done!", f.Label)
+		}
+	case schedule.KindAlarm:
+		msg = fmt.Sprintf("It's %s!", f.Due.Format("3:04 PM"))
+		if f.Label != "" {
+			msg = fmt.Sprintf("It's %s, your %s alarm!", f.Due.Format("3:04 PM"), f.Label)
+		}
+	default:
+		msg = "Reminder: " + f.Label
+	}
+	if f.Late > time.Minute {
+		msg = fmt.Sprintf("This was due %s ago, while I was away. %s", schedule.FormatDuration(f.Late.Truncate(time.Minute)), msg)
+	}
+	return msg
+}
+
+// timerStates lists the pending items for the dashboard.
+func timerStates(now time.Time) []web.TimerState {
+	items := scheduler.List()
+	states := make([]web.TimerState, len(items))
+	for i, it := range items {
+		states[i] = web.TimerState{
+			ID:        it.ID,
+			Kind:      string(it.Kind),
+			Label:     it.Label,
+			Due:       it.Due.Format(time.RFC3339),
+			Remaining: int(max(it.Due.Sub(now), 0).Seconds()),
+			Recur:     it.Recur,
+		}
+	}
+	return states
+}
+
+// updateTimerState pushes the pending items and the soonest one's
+// countdown to the dashboard.
+func updateTimerState() {
+	if webServer == nil {
+		return
+	}
+	now := time.Now()
+	states := timerStates(now)
+	active := ""
+	if items := scheduler.List(); len(items) > 0 {
+		left := max(items[0].Due.Sub(now), 0).Round(time.Second)
+		h, m, sec := int(left.Hours()), int(left.Minutes())%60, int(left.Seconds())%60
+		if h > 0 {
+			active = fmt.Sprintf("%s in %d:%02d:%02d", items[0].Name(), h, m, sec)
+		} else {
+			active = fmt.Sprintf("%s in %d:%02d", items[0].Name(), m, sec)
+		}
+	}
+	webServer.UpdateState(func(s *web.EvaState) {
+		s.Timers = states
+		s.ActiveTimer = active
+	})
+}
@@ -0,0 +1,122 @@
+// Tracking telemetry analyzer - summarises a recorded telemetry file
+//
+// Record with:  eva --telemetry-file tracking.jsonl
+// Analyze with: tracking-telemetry -in tracking.jsonl -csv tracking.csv
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/teslashibe/go-reachy/pkg/tracking/telemetry"
+)
+
+func main() {
+	defaults := telemetry.DefaultAnalyzeConfig()
+
+	inFlag := flag.String("in", "", "Telemetry file (JSON lines) recorded with eva --telemetry-file")
+	csvFlag := flag.String("csv", "", "Write records as CSV for plotting")
+	jsonFlag := flag.Bool("json", false, "Print the summary as JSON")
+	thresholdFlag := flag.Float64("threshold", defaults.StepThreshold, "Offset (rad) that starts a response event")
+	bandFlag := flag.Float64("band", defaults.SettleBand, "Offset (rad) considered centred")
+	holdFlag := flag.Duration("hold", defaults.SettleHold, "Time inside the band to count as settled")
+	flag.Parse()
+
+	if *inFlag == "" && flag.NArg() > 0 {
+		*inFlag = flag.Arg(0)
+	}
+	if *inFlag == "" {
+		fmt.Println("Usage: tracking-telemetry -in tracking.jsonl [-csv out.csv] [-json]")
+		os.Exit(1)
+	}
+
+	records, err := telemetry.ReadFile(*inFlag)
+	if err != nil {
+		fmt.Printf("❌ Read %s: %v\n", *inFlag, err)
+		os.Exit(1)
+	}
+	if len(records) == 0 {
+		fmt.Println("❌ No telemetry records")
+		os.Exit(1)
+	}
+
+	summary := telemetry.Analyze(records, telemetry.AnalyzeConfig{
+		StepThreshold: *thresholdFlag,
+		SettleBand:    *bandFlag,
+		SettleHold:    *holdFlag,
+	})
+
+	if *csvFlag != "" {
+		if err := writeCSV(*csvFlag, records); err != nil {
+			fmt.Printf("❌ Write CSV: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if *jsonFlag {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(summary)
+		return
+	}
+
+	printSummary(summary)
+	if *csvFlag != "" {
+		fmt.Printf("\n📄 CSV written to %s\n", *csvFlag)
+	}
+}
+
+func writeCSV(path string, records []telemetry.Record) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := telemetry.WriteCSV(f, records); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func printSummary(s telemetry.Summary) {
+	fmt.Println("📈 Tracking Telemetry Summary")
+	fmt.Println("=============================")
+	fmt.Printf("Records:      %d over %v\n", s.Records, s.Duration.Round(time.Millisecond))
+	fmt.Printf("Tick rate:    %.1f Hz\n", s.TickHz)
+	fmt.Printf("Detections:   %.1f Hz\n", s.DetectionHz)
+	fmt.Printf("Face target:  %.0f%% of ticks\n", s.TargetFraction*100)
+
+	fmt.Println("\nModes:")
+	modes := make([]string, 0, len(s.Modes))
+	for m := range s.Modes {
+		modes = append(modes, m)
+	}
+	sort.Slice(modes, func(i, j int) bool { return s.Modes[modes[i]] > s.Modes[modes[j]] })
+	for _, m := range modes {
+		pct := 0.0
+		if s.Duration > 0 {
+			pct = float64(s.Modes[m]) / float64(s.Duration) * 100
+		}
+		fmt.Printf("  %-14s %8v (%.0f%%)\n", m, s.Modes[m].Round(time.Millisecond), pct)
+	}
+
+	printAxis("Yaw", s.Yaw)
+	printAxis("Pitch", s.Pitch)
+}
+
+func printAxis(name string, a telemetry.AxisStats) {
+	fmt.Printf("\n%s:\n", name)
+	fmt.Printf("  Events:     %d (%d settled)\n", a.Events, a.Settled)
+	if a.Settled > 0 {
+		fmt.Printf("  Settling:   mean %v, max %v\n",
+			a.MeanSettling.Round(time.Millisecond), a.MaxSettling.Round(time.Millisecond))
+	}
+	if a.Events > 0 {
+		fmt.Printf("  Overshoot:  mean %.0f%%, max %.0f%%\n", a.MeanOvershoot*100, a.MaxOvershoot*100)
+	}
+	fmt.Printf("  Jitter:     %.4f rad/tick RMS (%.2f°)\n", a.Jitter, a.Jitter*180/3.14159265)
+}
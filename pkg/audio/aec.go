@@ -0,0 +1,544 @@
+package audio
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/teslashibe/go-reachy/pkg/audio/dsp"
+)
+
+// EchoReference keeps recently played audio on a wall-clock timeline so
+// an EchoCanceller can line it up with the microphone. It is an
+// AudioSink: tee it with the robot sink and every frame the Output sends
+// is recorded at the moment it was released.
+type EchoReference struct {
+	rate int
+	rs   *dsp.Resampler // Output frames to rate; only the pacer uses it
+
+	mu      sync.Mutex
+	ring    []int16
+	anchor  time.Time // Time of sample index 0
+	end     int64     // Index one past the last written sample
+	started bool
+}
+
+// NewEchoReference creates a reference at rate keeping history of audio.
+func NewEchoReference(rate int, history time.Duration) *EchoReference {
+	n := int(float64(rate) * history.Seconds())
+	r := &EchoReference{rate: rate, ring: make([]int16, max(n, rate/10))}
+	if rate != outputSampleRate {
+		r.rs, _ = dsp.NewResampler(outputSampleRate, rate, dsp.QualityDefault)
+	}
+	return r
+}
+
+// SampleRate returns the reference's sample rate.
+func (r *EchoReference) SampleRate() int {
+	return r.rate
+}
+
+// Write records samples whose first sample was played at t.
+func (r *EchoReference) Write(samples []int16, t time.Time) {
+	if len(samples) == 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.started {
+		r.anchor = t
+		r.started = true
+	}
+
+	// Frames are paced back to back: snap send-time jitter onto the end
+	// of the previous frame so the timeline has no holes or overlaps
+	idx := r.index(t)
+	if idx < r.end+int64(len(samples)/2) {
+		idx = r.end
+	}
+
+	n := int64(len(r.ring))
+	for j := max(r.end, idx-n); j < idx; j++ {
+		r.ring[j%n] = 0 // Silence between talkspurts
+	}
+	for i, s := range samples {
+		r.ring[(idx+int64(i))%n] = s
+	}
+	r.end = idx + int64(len(samples))
+}
+
+// Read fills dst with the audio played from t onwards. Anything not
+// played (or aged out of the history) reads as silence.
+func (r *EchoReference) Read(dst []int16, t time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.started {
+		clear(dst)
+		return
+	}
+	n := int64(len(r.ring))
+	idx := r.index(t)
+	for i := range dst {
+		j := idx + int64(i)
+		if j < 0 || j >= r.end || j < r.end-n {
+			dst[i] = 0
+		} else {
+			dst[i] = r.ring[j%n]
+		}
+	}
+}
+
+// WriteFrame implements AudioSink.
+func (r *EchoReference) WriteFrame(f *OutputFrame) error {
+	if r.rs == nil {
+		r.Write(Resample(f.PCM, outputSampleRate, r.rate), f.Time)
+		return nil
+	}
+	// Frames are contiguous, so resample them as one stream
+	r.Write(r.rs.Process(f.PCM), f.Time)
+	return nil
+}
+
+// Close implements AudioSink.
+func (r *EchoReference) Close() error {
+	return nil
+}
+
+// index converts t to a sample index. Caller holds mu.
+func (r *EchoReference) index(t time.Time) int64 {
+	return int64(math.Round(t.Sub(r.anchor).Seconds() * float64(r.rate)))
+}
+
+// EchoCancellerConfig configures an EchoCanceller.
+type EchoCancellerConfig struct {
+	// SampleRate of the microphone and reference audio.
+	SampleRate int
+
+	// FilterLength is the echo tail the adaptive filter models.
+	FilterLength time.Duration
+
+	// InitialDelay is the playback-to-capture delay assumed until it
+	// has been measured.
+	InitialDelay time.Duration
+
+	// MaxDelay bounds the delay search.
+	MaxDelay time.Duration
+
+	// StepSize is the NLMS adaptation rate (0-1).
+	StepSize float64
+
+	// Suppression is the gain applied to the residual while only the
+	// robot is talking; 1 disables residual suppression.
+	Suppression float64
+}
+
+// DefaultEchoCancellerConfig is tuned for the robot's speaker and mic
+// array at the Realtime API's 24kHz.
+func DefaultEchoCancellerConfig() EchoCancellerConfig {
+	return EchoCancellerConfig{
+		SampleRate:   24000,
+		FilterLength: 64 * time.Millisecond,
+		InitialDelay: 120 * time.Millisecond,
+		MaxDelay:     500 * time.Millisecond,
+		StepSize:     0.5,
+		Suppression:  0.3,
+	}
+}
+
+// EchoStats describes the canceller's state.
+type EchoStats struct {
+	Delay      time.Duration // Measured playback-to-capture delay
+	ERLE       float64       // Echo return loss enhancement (dB, smoothed)
+	Converged  bool          // The filter is removing echo
+	DoubleTalk bool          // Someone is talking over the robot
+}
+
+const (
+	aecActiveLevel   = 100.0                   // Reference RMS below this is silence (~-50 dBFS)
+	aecDecimatedRate = 4000                    // Rate for delay estimation
+	aecMinCorr       = 0.3                     // Weakest correlation trusted as an echo
+	aecDoubleTalkDB  = 10.0                    // ERLE drop that signals double talk
+	aecDoubleTalkMax = 1500 * time.Millisecond // Longer "double talk" means the echo path changed
+	aecHold          = 200 * time.Millisecond  // Freeze adaptation this long after double talk
+)
+
+// EchoCanceller removes the robot's own voice from the microphone using
+// an NLMS adaptive filter driven by the played reference.
+//
+// The bulk playback-to-capture delay (network, jitter buffers, speaker,
+// mic) is measured by cross-correlation and tracked as it drifts; the
+// filter then only has to model the short acoustic path. Adaptation
+// freezes during double talk so the user's speech isn't cancelled, which
+// is what makes barge-in work.
+type EchoCanceller struct {
+	cfg EchoCancellerConfig
+	ref *EchoReference
+
+	// Tap, if set, receives each microphone block with the reference
+	// played at the same moment, before delay compensation. Recording
+	// these pairs gives test material for tuning.
+	Tap func(mic, ref []int16)
+
+	mu       sync.Mutex
+	taps     int
+	lead     int       // Taps before the measured delay
+	w        []float64 // Filter, oldest tap first
+	delay    int
+	maxDelay int
+	hist     []float64 // Reference history on the microphone's timeline
+
+	// Delay estimation on decimated signals
+	decim     int
+	micDec    []float64
+	refDec    []float64
+	accMic    float64
+	accRef    float64
+	accN      int
+	sinceEst  int
+	candidate int
+	measured  bool
+
+	erle     float64
+	dtHold   int
+	dtRun    int
+	gain     float64
+	refBlock []int16
+}
+
+// NewEchoCanceller creates a canceller reading playback from ref, which
+// may be nil when blocks are fed with ProcessBlock.
+func NewEchoCanceller(ref *EchoReference, cfg EchoCancellerConfig) *EchoCanceller {
+	def := DefaultEchoCancellerConfig()
+	if cfg.SampleRate <= 0 {
+		cfg.SampleRate = def.SampleRate
+	}
+	if cfg.FilterLength <= 0 {
+		cfg.FilterLength = def.FilterLength
+	}
+	if cfg.MaxDelay <= 0 {
+		cfg.MaxDelay = def.MaxDelay
+	}
+	if cfg.StepSize <= 0 {
+		cfg.StepSize = def.StepSize
+	}
+	if cfg.Suppression <= 0 {
+		cfg.Suppression = 1
+	}
+
+	samples := func(d time.Duration) int { return int(float64(cfg.SampleRate) * d.Seconds()) }
+	taps := max(samples(cfg.FilterLength), 16)
+	c := &EchoCanceller{
+		cfg:       cfg,
+		ref:       ref,
+		taps:      taps,
+		lead:      taps / 4,
+		w:         make([]float64, taps),
+		maxDelay:  samples(cfg.MaxDelay),
+		decim:     max(1, cfg.SampleRate/aecDecimatedRate),
+		candidate: -1,
+		gain:      1,
+	}
+	c.delay = c.clampDelay(samples(cfg.InitialDelay))
+	return c
+}
+
+// Process cancels echo in a microphone block whose first sample was
+// captured at t. A constant offset between the two clocks is absorbed
+// by delay estimation.
+func (c *EchoCanceller) Process(mic []int16, t time.Time) []int16 {
+	if cap(c.refBlock) < len(mic) {
+		c.refBlock = make([]int16, len(mic))
+	}
+	ref := c.refBlock[:len(mic)]
+	if c.ref != nil {
+		c.ref.Read(ref, t)
+	} else {
+		clear(ref)
+	}
+	return c.ProcessBlock(mic, ref)
+}
+
+// ProcessBlock cancels echo in mic given the reference played over the
+// same interval. Blocks must be contiguous.
+func (c *EchoCanceller) ProcessBlock(mic, ref []int16) []int16 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.Tap != nil {
+		c.Tap(mic, ref)
+	}
+
+	n := len(mic)
+	base := len(c.hist)
+	var refPow float64
+	for i := 0; i < n; i++ {
+		var v float64
+		if i < len(ref) {
+			v = float64(ref[i])
+		}
+		c.hist = append(c.hist, v)
+		refPow += v * v
+	}
+	refActive := n > 0 && refPow/float64(n) > aecActiveLevel*aecActiveLevel
+
+	c.trackDelay(mic, c.hist[base:])
+
+	// Check for double talk with the filter frozen, then adapt only if
+	// the robot alone is talking, so the user's voice never trains it
+	res := make([]float64, n)
+	micPow, resPow := c.filter(base, mic, res, false)
+	c.updateState(refActive, micPow, resPow, n)
+	if refActive && c.dtHold == 0 {
+		c.filter(base, mic, res, true)
+	}
+
+	// Residual suppression while only the robot is talking
+	target := 1.0
+	if refActive && c.dtHold == 0 && c.erle > 6 {
+		target = c.cfg.Suppression
+	}
+	out := make([]int16, n)
+	for i, e := range res {
+		g := c.gain + (target-c.gain)*float64(i+1)/float64(n)
+		out[i] = clip16(e * g)
+	}
+	c.gain = target
+
+	c.trimHistory()
+	return out
+}
+
+// filter writes mic - w·x to res, adapting w by NLMS if asked, and
+// returns the block's mic and residual energy. Caller holds mu.
+func (c *EchoCanceller) filter(base int, mic []int16, res []float64, adapt bool) (micPow, resPow float64) {
+	minPow := float64(c.taps) * aecActiveLevel * aecActiveLevel / 4
+	for i := range mic {
+		d := float64(mic[i])
+		micPow += d * d
+
+		newest := base + i - c.delay + c.lead
+		if newest < 0 {
+			// No reference history this far back yet
+			res[i] = d
+			resPow += d * d
+			continue
+		}
+		start := newest - c.taps + 1
+		off := 0
+		if start < 0 {
+			off, start = -start, 0
+		}
+		xs := c.hist[start : newest+1]
+		ws := c.w[off:]
+
+		var y, pow float64
+		for j, x := range xs {
+			y += ws[j] * x
+			pow += x * x
+		}
+		e := d - y
+		if adapt && pow > minPow {
+			g := c.cfg.StepSize * e / (pow + 1)
+			for j, x := range xs {
+				ws[j] += g * x
+			}
+		}
+		res[i] = e
+		resPow += e * e
+	}
+	return micPow, resPow
+}
+
+// Stats returns the canceller's current state.
+func (c *EchoCanceller) Stats() EchoStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return EchoStats{
+		Delay:      time.Duration(c.delay) * time.Second / time.Duration(c.cfg.SampleRate),
+		ERLE:       c.erle,
+		Converged:  c.erle > 6,
+		DoubleTalk: c.dtHold > 0,
+	}
+}
+
+// Reset forgets the echo path, e.g. after the speaker volume changes.
+func (c *EchoCanceller) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.w)
+	c.erle = 0
+	c.dtHold = 0
+	c.dtRun = 0
+	c.gain = 1
+}
+
+// updateState tracks ERLE and double talk after a block. Caller holds mu.
+func (c *EchoCanceller) updateState(refActive bool, micPow, resPow float64, n int) {
+	if !refActive {
+		c.dtHold = max(0, c.dtHold-n)
+		c.dtRun = 0
+		return
+	}
+
+	// Filter made things worse: start again
+	if resPow > 4*micPow+1 {
+		clear(c.w)
+		c.erle = 0
+	}
+
+	blockERLE := 10 * math.Log10((micPow+1)/(resPow+1))
+	converged := c.erle > 6
+	switch {
+	case converged && blockERLE < c.erle-aecDoubleTalkDB:
+		// Residual jumped: near-end speech on top of the echo
+		c.dtHold = int(float64(c.cfg.SampleRate) * aecHold.Seconds())
+		c.dtRun += n
+		if c.dtRun > int(float64(c.cfg.SampleRate)*aecDoubleTalkMax.Seconds()) {
+			// Too long for a barge-in: the echo path changed, re-adapt
+			c.erle = 0
+			c.dtHold = 0
+			c.dtRun = 0
+		}
+	default:
+		c.dtHold = max(0, c.dtHold-n)
+		c.dtRun = 0
+		if c.dtHold == 0 {
+			c.erle += 0.1 * (blockERLE - c.erle)
+		}
+	}
+}
+
+// trackDelay feeds the delay estimator. Caller holds mu.
+func (c *EchoCanceller) trackDelay(mic []int16, ref []float64) {
+	for i := range mic {
+		c.accMic += float64(mic[i])
+		c.accRef += ref[i]
+		c.accN++
+		if c.accN == c.decim {
+			c.micDec = append(c.micDec, c.accMic/float64(c.decim))
+			c.refDec = append(c.refDec, c.accRef/float64(c.decim))
+			c.accMic, c.accRef, c.accN = 0, 0, 0
+			c.sinceEst++
+		}
+	}
+
+	window := c.cfg.SampleRate / c.decim // One second
+	maxLag := c.maxDelay / c.decim
+	keep := window + maxLag
+	if len(c.micDec) > 2*keep {
+		c.micDec = append(c.micDec[:0], c.micDec[len(c.micDec)-keep:]...)
+		c.refDec = append(c.refDec[:0], c.refDec[len(c.refDec)-keep:]...)
+	}
+	if c.sinceEst < window/2 || len(c.micDec) < keep {
+		return
+	}
+	c.sinceEst = 0
+
+	lag, ok := estimateDelay(c.micDec[len(c.micDec)-keep:], c.refDec[len(c.refDec)-keep:], window, maxLag)
+	if !ok {
+		c.candidate = -1
+		return
+	}
+
+	// Require two estimates to agree before moving the filter
+	d := lag * c.decim
+	if c.candidate >= 0 && abs(d-c.candidate) <= 2*c.decim {
+		if !c.measured || abs(d-c.delay) > c.lead/2 {
+			c.setDelay(d)
+		}
+		c.measured = true
+	}
+	c.candidate = d
+}
+
+// setDelay moves the filter window, keeping taps that still line up.
+// Caller holds mu.
+func (c *EchoCanceller) setDelay(d int) {
+	d = c.clampDelay(d)
+	shift := d - c.delay
+	if shift == 0 {
+		return
+	}
+	w := make([]float64, c.taps)
+	for j := range w {
+		if k := j - shift; k >= 0 && k < c.taps {
+			w[j] = c.w[k]
+		}
+	}
+	c.w = w
+	c.delay = d
+}
+
+func (c *EchoCanceller) clampDelay(d int) int {
+	return min(max(d, c.lead), max(c.maxDelay, c.lead))
+}
+
+// trimHistory bounds the reference history. Caller holds mu.
+func (c *EchoCanceller) trimHistory() {
+	keep := c.maxDelay + c.taps + c.cfg.SampleRate/10
+	if len(c.hist) > 2*keep {
+		c.hist = append(c.hist[:0], c.hist[len(c.hist)-keep:]...)
+	}
+}
+
+// estimateDelay finds the lag (in samples) at which ref best explains
+// the last window samples of mic. Both slices cover the same interval.
+func estimateDelay(mic, ref []float64, window, maxLag int) (int, bool) {
+	end := len(mic)
+	m := mic[end-window:]
+
+	var micE float64
+	for _, v := range m {
+		micE += v * v
+	}
+	if micE == 0 {
+		return 0, false
+	}
+
+	// Reference energy over the window, slid as the lag grows
+	var refE float64
+	for _, v := range ref[end-window:] {
+		refE += v * v
+	}
+	minRefE := float64(window) * aecActiveLevel * aecActiveLevel
+
+	best, bestLag := 0.0, -1
+	for lag := 0; lag <= maxLag && end-window-lag >= 0; lag++ {
+		r := ref[end-window-lag : end-lag]
+		if lag > 0 {
+			refE += r[0]*r[0] - ref[end-lag]*ref[end-lag]
+		}
+		if refE < minRefE {
+			continue
+		}
+		var dot float64
+		for i, v := range m {
+			dot += v * r[i]
+		}
+		if corr := dot / math.Sqrt(micE*refE); corr > best {
+			best, bestLag = corr, lag
+		}
+	}
+	return bestLag, bestLag >= 0 && best >= aecMinCorr
+}
+
+func clip16(v float64) int16 {
+	switch {
+	case v > math.MaxInt16:
+		return math.MaxInt16
+	case v < math.MinInt16:
+		return math.MinInt16
+	default:
+		return int16(v)
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,248 @@
+package audio
+
+import (
+	"math"
+	"math/rand/v2"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const aecTestRate = 16000
+
+// speechLike returns band-limited noise with a syllable-rate envelope.
+func speechLike(rng *rand.Rand, n int, level float64) []int16 {
+	out := make([]int16, n)
+	var lp float64
+	for i := range out {
+		lp += 0.3 * (rng.NormFloat64() - lp)
+		env := math.Abs(math.Sin(2 * math.Pi * 3 * float64(i) / aecTestRate))
+		out[i] = clip16(level * lp * (0.2 + env))
+	}
+	return out
+}
+
+// roomEcho plays ref through a synthetic speaker-to-mic path: a bulk
+// delay, a direct path and a decaying tail of reflections.
+func roomEcho(rng *rand.Rand, ref []int16, delay time.Duration) []int16 {
+	d := int(aecTestRate * delay.Seconds())
+	rir := make([]float64, aecTestRate*20/1000)
+	rir[0] = 0.7
+	for k := 1; k < len(rir); k++ {
+		rir[k] = 0.2 * rng.NormFloat64() * math.Exp(-float64(k)/60)
+	}
+
+	out := make([]int16, len(ref))
+	for i := range out {
+		var v float64
+		for k, h := range rir {
+			if j := i - d - k; j >= 0 {
+				v += h * float64(ref[j])
+			}
+		}
+		out[i] = clip16(v)
+	}
+	return out
+}
+
+func mix(a, b []int16) []int16 {
+	out := make([]int16, len(a))
+	for i := range a {
+		out[i] = clip16(float64(a[i]) + float64(b[i]))
+	}
+	return out
+}
+
+func energy(s []int16) float64 {
+	var e float64
+	for _, v := range s {
+		e += float64(v) * float64(v)
+	}
+	return e
+}
+
+// runEchoPair feeds a recorded speaker/mic pair through a canceller in
+// 20ms blocks, as the live pipeline does.
+func runEchoPair(mic, ref []int16, rate int) ([]int16, *EchoCanceller) {
+	cfg := DefaultEchoCancellerConfig()
+	cfg.SampleRate = rate
+	cfg.FilterLength = 32 * time.Millisecond
+	cfg.Suppression = 1 // Measure the filter alone
+	c := NewEchoCanceller(nil, cfg)
+
+	block := rate / 50
+	var out []int16
+	for off := 0; off+block <= len(mic); off += block {
+		out = append(out, c.ProcessBlock(mic[off:off+block], ref[off:off+block])...)
+	}
+	return out, c
+}
+
+// erleDB compares echo energy before and after cancellation over the
+// last second.
+func erleDB(mic, out []int16, rate int) float64 {
+	n := len(out)
+	return 10 * math.Log10(energy(mic[n-rate:n])/(energy(out[n-rate:])+1))
+}
+
+func TestEchoCanceller_Converges(t *testing.T) {
+	tests := []struct {
+		name  string
+		delay time.Duration
+	}{
+		{"short delay", 40 * time.Millisecond},
+		{"network delay", 180 * time.Millisecond},
+		{"long delay", 350 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rng := rand.New(rand.NewPCG(1, 2))
+			ref := speechLike(rng, 5*aecTestRate, 3000)
+			mic := mix(roomEcho(rng, ref, tt.delay), speechLike(rng, len(ref), 20))
+
+			out, c := runEchoPair(mic, ref, aecTestRate)
+
+			if erle := erleDB(mic, out, aecTestRate); erle < 15 {
+				t.Errorf("ERLE = %.1f dB, want >= 15", erle)
+			}
+			s := c.Stats()
+			if diff := s.Delay - tt.delay; diff < -2*time.Millisecond || diff > 2*time.Millisecond {
+				t.Errorf("Delay = %v, want %v", s.Delay, tt.delay)
+			}
+			if !s.Converged {
+				t.Errorf("Converged = false, ERLE %.1f dB", s.ERLE)
+			}
+		})
+	}
+}
+
+// The user talking over the robot must come through: that is barge-in.
+func TestEchoCanceller_DoubleTalk(t *testing.T) {
+	rng := rand.New(rand.NewPCG(3, 4))
+	n := 6 * aecTestRate
+	ref := speechLike(rng, n, 3000)
+	echo := roomEcho(rng, ref, 120*time.Millisecond)
+
+	// Near-end speech for the last second, as when the user barges in
+	near := make([]int16, n)
+	copy(near[5*aecTestRate:], speechLike(rng, aecTestRate, 2000))
+	mic := mix(echo, near)
+
+	out, _ := runEchoPair(mic, ref, aecTestRate)
+
+	seg := func(s []int16) []int16 { return s[5*aecTestRate : len(out)] }
+	gotNear, wantNear := seg(out), seg(near)
+	ratio := 10 * math.Log10(energy(gotNear)/energy(wantNear))
+	if ratio < -3 || ratio > 3 {
+		t.Errorf("near-end level changed by %.1f dB, want within 3 dB", ratio)
+	}
+
+	// And it's the user, not echo, that remains
+	var dot float64
+	for i := range gotNear {
+		dot += float64(gotNear[i]) * float64(wantNear[i])
+	}
+	if corr := dot / math.Sqrt(energy(gotNear)*energy(wantNear)); corr < 0.9 {
+		t.Errorf("correlation with near-end = %.2f, want >= 0.9", corr)
+	}
+}
+
+func TestEchoCanceller_SilentReferencePassesThrough(t *testing.T) {
+	rng := rand.New(rand.NewPCG(5, 6))
+	mic := speechLike(rng, aecTestRate, 2000)
+	out, _ := runEchoPair(mic, make([]int16, len(mic)), aecTestRate)
+	for i := range out {
+		if out[i] != mic[i] {
+			t.Fatalf("sample %d = %d, want %d", i, out[i], mic[i])
+		}
+	}
+}
+
+func TestEchoCanceller_ReferenceTimeline(t *testing.T) {
+	rng := rand.New(rand.NewPCG(7, 8))
+	ref := NewEchoReference(aecTestRate, 2*time.Second)
+	c := NewEchoCanceller(ref, EchoCancellerConfig{SampleRate: aecTestRate, FilterLength: 32 * time.Millisecond})
+
+	var tapped []int16
+	c.Tap = func(mic, r []int16) { tapped = append(tapped, r...) }
+
+	start := time.Now()
+	played := speechLike(rng, aecTestRate/2, 3000)
+	block := aecTestRate / 50
+	for off := 0; off < len(played); off += block {
+		// Send-time jitter of a millisecond must not tear the timeline
+		var jitter time.Duration
+		if off > 0 {
+			jitter = time.Duration(rng.IntN(2000)-1000) * time.Microsecond
+		}
+		at := start.Add(time.Duration(off)*time.Second/aecTestRate + jitter)
+		ref.Write(played[off:off+block], at)
+	}
+
+	// The mic block covering the same interval sees the same reference
+	c.Process(make([]int16, len(played)), start)
+	for i := range played {
+		if tapped[i] != played[i] {
+			t.Fatalf("reference sample %d = %d, want %d", i, tapped[i], played[i])
+		}
+	}
+
+	// Before and after playback is silence
+	dst := make([]int16, block)
+	ref.Read(dst, start.Add(-time.Second))
+	if energy(dst) != 0 {
+		t.Error("reference before playback is not silent")
+	}
+	ref.Read(dst, start.Add(time.Second))
+	if energy(dst) != 0 {
+		t.Error("reference after playback is not silent")
+	}
+}
+
+// TestEchoCanceller_RecordedPairs runs every speaker/mic pair in
+// testdata/aec (NAME_mic.wav + NAME_ref.wav, recorded with
+// eva --aec-record) plus a synthetic pair written the same way.
+func TestEchoCanceller_RecordedPairs(t *testing.T) {
+	dir := t.TempDir()
+	rng := rand.New(rand.NewPCG(9, 10))
+	ref := speechLike(rng, 4*aecTestRate, 3000)
+	mic := mix(roomEcho(rng, ref, 150*time.Millisecond), speechLike(rng, len(ref), 20))
+	if err := WriteWAV(filepath.Join(dir, "synthetic_mic.wav"), mic, aecTestRate); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteWAV(filepath.Join(dir, "synthetic_ref.wav"), ref, aecTestRate); err != nil {
+		t.Fatal(err)
+	}
+
+	recorded, _ := filepath.Glob("testdata/aec/*_mic.wav")
+	synthetic, _ := filepath.Glob(filepath.Join(dir, "*_mic.wav"))
+
+	for _, micPath := range append(synthetic, recorded...) {
+		name := strings.TrimSuffix(filepath.Base(micPath), "_mic.wav")
+		t.Run(name, func(t *testing.T) {
+			mic, rate, err := ReadWAV(micPath)
+			if err != nil {
+				t.Fatalf("read mic: %v", err)
+			}
+			ref, refRate, err := ReadWAV(strings.TrimSuffix(micPath, "_mic.wav") + "_ref.wav")
+			if err != nil {
+				t.Fatalf("read ref: %v", err)
+			}
+			if rate != refRate {
+				t.Fatalf("mic at %d Hz, ref at %d Hz", rate, refRate)
+			}
+			n := min(len(mic), len(ref))
+			if n < 2*rate {
+				t.Skipf("pair too short (%d samples)", n)
+			}
+
+			out, c := runEchoPair(mic[:n], ref[:n], rate)
+			erle := erleDB(mic[:len(out)], out, rate)
+			t.Logf("ERLE %.1f dB, delay %v", erle, c.Stats().Delay)
+			if erle < 10 {
+				t.Errorf("ERLE = %.1f dB, want >= 10", erle)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+// Package beamform steers the XVF3800's mic array toward the person Eva
+// is listening to, producing an enhanced mono stream for speech-to-text.
+package beamform
+
+import (
+	"errors"
+	"math"
+)
+
+// SpeedOfSound is in meters per second at room temperature.
+const SpeedOfSound = 343.0
+
+// Mic is one microphone's position in meters from the array center. X
+// points to Eva's front and Y to her left, so angles match DOA readings
+// (0 = front, +π/2 = left).
+type Mic struct {
+	X, Y float64
+}
+
+// Array describes a microphone array and where each mic is in the
+// interleaved stream.
+type Array struct {
+	Mics []Mic
+
+	// Channels maps Mics[i] to its channel in the stream; nil means
+	// channel i.
+	Channels []int
+}
+
+// XVF3800 returns the ReSpeaker XVF3800's four mics, nominally on a 32mm
+// radius at ±45° and ±135°. Measure the board if the beams look off.
+func XVF3800() Array {
+	const r = 0.032
+	var mics []Mic
+	for _, deg := range []float64{45, 135, -135, -45} {
+		a := deg * math.Pi / 180
+		mics = append(mics, Mic{X: r * math.Cos(a), Y: r * math.Sin(a)})
+	}
+	return Array{Mics: mics}
+}
+
+// channel returns the stream channel of mic i.
+func (a Array) channel(i int) int {
+	if a.Channels == nil {
+		return i
+	}
+	return a.Channels[i]
+}
+
+// validate checks the array against a stream of n channels.
+func (a Array) validate(n int) error {
+	if len(a.Mics) < 2 {
+		return errors.New("beamform: need at least two mics")
+	}
+	if a.Channels != nil && len(a.Channels) != len(a.Mics) {
+		return errors.New("beamform: Channels must have one entry per mic")
+	}
+	for i := range a.Mics {
+		if ch := a.channel(i); ch < 0 || ch >= n {
+			return errors.New("beamform: mic channel outside the stream")
+		}
+	}
+	return nil
+}
+
+// delays returns each mic's arrival time, relative to the array center,
+// for a far-field source at angle. Mics nearer the source hear it first.
+func (a Array) delays(angle, c float64) []float64 {
+	ux, uy := math.Cos(angle), math.Sin(angle)
+	tau := make([]float64, len(a.Mics))
+	for i, m := range a.Mics {
+		tau[i] = -(m.X*ux + m.Y*uy) / c
+	}
+	return tau
+}
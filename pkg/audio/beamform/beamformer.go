@@ -0,0 +1,388 @@
+package beamform
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/cmplx"
+	"time"
+)
+
+// Method selects how the mics are combined.
+type Method int
+
+const (
+	// DelayAndSum aligns the mics on the steering direction and averages
+	// them. Robust, but the small array gives little directivity below
+	// 1-2kHz.
+	DelayAndSum Method = iota
+
+	// MVDR (minimum variance distortionless response) passes the
+	// steering direction unchanged and places nulls on the noise it has
+	// learned while the target was quiet, e.g. a TV or fan.
+	MVDR
+)
+
+func (m Method) String() string {
+	switch m {
+	case DelayAndSum:
+		return "das"
+	case MVDR:
+		return "mvdr"
+	}
+	return fmt.Sprintf("Method(%d)", int(m))
+}
+
+// ParseMethod parses "das" or "mvdr".
+func ParseMethod(s string) (Method, error) {
+	switch s {
+	case "das", "delay-and-sum":
+		return DelayAndSum, nil
+	case "mvdr":
+		return MVDR, nil
+	}
+	return 0, fmt.Errorf("unknown beamforming method %q (want das or mvdr)", s)
+}
+
+// Config configures a Beamformer.
+type Config struct {
+	SampleRate int
+	Channels   int // In the interleaved input
+	Array      Array
+	Method     Method
+
+	// FFTSize is the STFT frame length, a power of two. Frames overlap by
+	// half, so the output lags the input by FFTSize/2 samples.
+	FFTSize int
+
+	// NoiseTime is the MVDR noise estimate's time constant.
+	NoiseTime time.Duration
+
+	// Loading is the MVDR diagonal loading, relative to the average mic
+	// noise power. More loading trades null depth for robustness to
+	// steering and array errors; infinite loading is delay-and-sum.
+	Loading float64
+}
+
+// DefaultConfig returns an MVDR configuration for the XVF3800 with about
+// 32ms frames.
+func DefaultConfig(sampleRate, channels int) Config {
+	n := 1
+	for n < sampleRate*32/1000 {
+		n <<= 1
+	}
+	return Config{
+		SampleRate: sampleRate,
+		Channels:   channels,
+		Array:      XVF3800(),
+		Method:     MVDR,
+		FFTSize:    n,
+		NoiseTime:  2 * time.Second,
+		Loading:    0.05,
+	}
+}
+
+// Beamformer turns interleaved multichannel PCM16 into a mono stream
+// steered toward one direction. It processes in overlapping STFT frames;
+// blocks of any size may be written and the output is seamless. Not safe
+// for concurrent use.
+type Beamformer struct {
+	cfg        Config
+	n, hop     int
+	bins       int
+	window     []float64 // Square-root Hann, for analysis and synthesis
+	fft        *fft
+	alpha      float64 // Noise estimate smoothing per hop
+	minSamples int     // Hops of noise before MVDR is trusted
+
+	frames [][]float64 // Per mic, the last n samples
+	fill   int         // Samples of the next hop received
+	olap   []float64   // Overlap-add accumulator
+	buf    []complex128
+	spec   [][]complex128 // [mic][bin] of the current frame
+
+	angle   float64
+	steer   [][]complex128 // [bin][mic]
+	weights [][]complex128 // [bin][mic]
+	dirty   bool           // Weights need solving
+
+	noise   [][]complex128 // [bin] mics×mics covariance, row-major
+	learned int            // Hops of noise in the estimate
+	since   int            // Hops since the weights were solved
+	active  bool           // Target speaking; noise learning paused
+}
+
+// New creates a Beamformer steered to the front.
+func New(cfg Config) (*Beamformer, error) {
+	if cfg.SampleRate <= 0 || cfg.Channels <= 0 {
+		return nil, errors.New("beamform: SampleRate and Channels must be positive")
+	}
+	if err := cfg.Array.validate(cfg.Channels); err != nil {
+		return nil, err
+	}
+	if cfg.FFTSize < 16 || cfg.FFTSize&(cfg.FFTSize-1) != 0 {
+		return nil, fmt.Errorf("beamform: FFTSize %d is not a power of two", cfg.FFTSize)
+	}
+	if cfg.NoiseTime <= 0 {
+		cfg.NoiseTime = 2 * time.Second
+	}
+
+	n := cfg.FFTSize
+	m := len(cfg.Array.Mics)
+	b := &Beamformer{
+		cfg:    cfg,
+		n:      n,
+		hop:    n / 2,
+		bins:   n/2 + 1,
+		window: make([]float64, n),
+		fft:    newFFT(n),
+		frames: make([][]float64, m),
+		olap:   make([]float64, n),
+		buf:    make([]complex128, n),
+		spec:   make([][]complex128, m),
+	}
+	hopTime := float64(b.hop) / float64(cfg.SampleRate)
+	b.alpha = math.Exp(-hopTime / cfg.NoiseTime.Seconds())
+	b.minSamples = max(int(0.5/hopTime), 2*m) // Half a second
+	for i := range b.window {
+		b.window[i] = math.Sqrt(0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(n)))
+	}
+	for i := range m {
+		b.frames[i] = make([]float64, n)
+		b.spec[i] = make([]complex128, b.bins)
+	}
+	b.steer = make([][]complex128, b.bins)
+	b.weights = make([][]complex128, b.bins)
+	b.noise = make([][]complex128, b.bins)
+	for k := range b.bins {
+		b.steer[k] = make([]complex128, m)
+		b.weights[k] = make([]complex128, m)
+		b.noise[k] = make([]complex128, m*m)
+	}
+	b.Steer(0)
+	return b, nil
+}
+
+// Method returns the beamforming method.
+func (b *Beamformer) Method() Method {
+	return b.cfg.Method
+}
+
+// Delay returns how far the output lags the input.
+func (b *Beamformer) Delay() time.Duration {
+	return time.Duration(b.hop) * time.Second / time.Duration(b.cfg.SampleRate)
+}
+
+// Angle returns the steering direction in radians.
+func (b *Beamformer) Angle() float64 {
+	return b.angle
+}
+
+// Steer points the beam at angle (radians, 0 = front, +π/2 = left). It
+// takes effect from the next frame.
+func (b *Beamformer) Steer(angle float64) {
+	b.angle = angle
+	tau := b.cfg.Array.delays(angle, SpeedOfSound)
+	for k := range b.bins {
+		f := float64(k) * float64(b.cfg.SampleRate) / float64(b.n)
+		for i, t := range tau {
+			b.steer[k][i] = cmplx.Exp(complex(0, -2*math.Pi*f*t))
+		}
+	}
+	b.dirty = true
+}
+
+// SetTargetActive tells the beamformer whether the target is talking.
+// MVDR learns the noise field only while it isn't, so it never learns to
+// cancel the speaker.
+func (b *Beamformer) SetTargetActive(active bool) {
+	b.active = active
+}
+
+// Reset clears the audio buffers and the learned noise.
+func (b *Beamformer) Reset() {
+	for i := range b.frames {
+		clear(b.frames[i])
+	}
+	clear(b.olap)
+	for k := range b.noise {
+		clear(b.noise[k])
+	}
+	b.fill = 0
+	b.learned = 0
+	b.dirty = true
+}
+
+// Process beamforms interleaved samples and returns the mono output.
+func (b *Beamformer) Process(in []int16) []int16 {
+	return b.Append(nil, in)
+}
+
+// Append beamforms interleaved samples, appending the mono output to dst.
+// Output comes a hop (FFTSize/2 samples) at a time.
+func (b *Beamformer) Append(dst, in []int16) []int16 {
+	c := b.cfg.Channels
+	for off := 0; off+c <= len(in); off += c {
+		pos := b.hop + b.fill
+		for i := range b.frames {
+			b.frames[i][pos] = float64(in[off+b.cfg.Array.channel(i)])
+		}
+		if b.fill++; b.fill == b.hop {
+			dst = b.processFrame(dst)
+			b.fill = 0
+		}
+	}
+	return dst
+}
+
+// processFrame beamforms the buffered frame and emits a hop of output.
+func (b *Beamformer) processFrame(dst []int16) []int16 {
+	for i, frame := range b.frames {
+		for k, v := range frame {
+			b.buf[k] = complex(v*b.window[k], 0)
+		}
+		b.fft.transform(b.buf, false)
+		copy(b.spec[i], b.buf[:b.bins])
+		copy(frame, frame[b.hop:])
+	}
+
+	if b.cfg.Method == MVDR && !b.active {
+		b.learnNoise()
+	}
+	if b.dirty || (b.cfg.Method == MVDR && b.since >= 8) {
+		b.solve()
+	}
+	b.since++
+
+	for k := range b.bins {
+		var y complex128
+		for i, w := range b.weights[k] {
+			y += cmplx.Conj(w) * b.spec[i][k]
+		}
+		b.buf[k] = y
+	}
+	b.buf[0] = complex(real(b.buf[0]), 0)
+	b.buf[b.n/2] = complex(real(b.buf[b.n/2]), 0)
+	for k := 1; k < b.n/2; k++ {
+		b.buf[b.n-k] = cmplx.Conj(b.buf[k])
+	}
+	b.fft.transform(b.buf, true)
+
+	for k := range b.olap {
+		b.olap[k] += real(b.buf[k]) * b.window[k]
+	}
+	for _, v := range b.olap[:b.hop] {
+		dst = append(dst, int16(max(min(math.Round(v), math.MaxInt16), math.MinInt16)))
+	}
+	copy(b.olap, b.olap[b.hop:])
+	clear(b.olap[b.n-b.hop:])
+	return dst
+}
+
+// learnNoise folds the current frame into the noise covariance.
+func (b *Beamformer) learnNoise() {
+	a := complex(b.alpha, 0)
+	if b.learned == 0 {
+		a = 0
+	}
+	m := len(b.spec)
+	for k := range b.bins {
+		r := b.noise[k]
+		for i := range m {
+			xi := b.spec[i][k]
+			for j := range m {
+				r[i*m+j] = a*r[i*m+j] + (1-a)*xi*cmplx.Conj(b.spec[j][k])
+			}
+		}
+	}
+	b.learned++
+}
+
+// solve recomputes the weights for the steering direction.
+func (b *Beamformer) solve() {
+	b.dirty = false
+	b.since = 0
+	m := len(b.spec)
+	mvdr := b.cfg.Method == MVDR && b.learned >= b.minSamples
+	a := make([]complex128, m*m)
+	z := make([]complex128, m)
+	for k := range b.bins {
+		d := b.steer[k]
+		if mvdr && b.mvdrWeights(b.noise[k], d, a, z) {
+			copy(b.weights[k], z)
+			continue
+		}
+		for i := range d {
+			b.weights[k][i] = d[i] / complex(float64(m), 0)
+		}
+	}
+}
+
+// mvdrWeights computes R⁻¹d / (dᴴR⁻¹d) into z, with R diagonally loaded.
+// It reports false if R is empty or singular.
+func (b *Beamformer) mvdrWeights(r, d, a, z []complex128) bool {
+	m := len(d)
+	var trace float64
+	for i := range m {
+		trace += real(r[i*m+i])
+	}
+	if trace <= 0 {
+		return false
+	}
+	copy(a, r)
+	load := complex(b.cfg.Loading*trace/float64(m)+1e-9*trace, 0)
+	for i := range m {
+		a[i*m+i] += load
+	}
+	copy(z, d)
+	if !solveLinear(a, z, m) {
+		return false
+	}
+	var norm complex128
+	for i := range d {
+		norm += cmplx.Conj(d[i]) * z[i]
+	}
+	if cmplx.Abs(norm) < 1e-12 {
+		return false
+	}
+	for i := range z {
+		z[i] /= norm
+	}
+	return true
+}
+
+// solveLinear solves a·x = z in place (z becomes x) by Gaussian
+// elimination with partial pivoting. a is m×m row-major and is destroyed.
+func solveLinear(a, z []complex128, m int) bool {
+	for col := range m {
+		pivot := col
+		for row := col + 1; row < m; row++ {
+			if cmplx.Abs(a[row*m+col]) > cmplx.Abs(a[pivot*m+col]) {
+				pivot = row
+			}
+		}
+		if cmplx.Abs(a[pivot*m+col]) == 0 {
+			return false
+		}
+		if pivot != col {
+			for j := range m {
+				a[col*m+j], a[pivot*m+j] = a[pivot*m+j], a[col*m+j]
+			}
+			z[col], z[pivot] = z[pivot], z[col]
+		}
+		for row := col + 1; row < m; row++ {
+			f := a[row*m+col] / a[col*m+col]
+			for j := col; j < m; j++ {
+				a[row*m+j] -= f * a[col*m+j]
+			}
+			z[row] -= f * z[col]
+		}
+	}
+	for row := m - 1; row >= 0; row-- {
+		for j := row + 1; j < m; j++ {
+			z[row] -= a[row*m+j] * z[j]
+		}
+		z[row] /= a[row*m+row]
+	}
+	return true
+}
@@ -0,0 +1,269 @@
+package beamform
+
+import (
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"testing"
+)
+
+const rate = 16000
+
+type tone struct {
+	freq, amp, phase float64
+}
+
+// planeWave renders tones arriving from angle at each mic of the array,
+// interleaved.
+func planeWave(a Array, n int, angle float64, tones []tone) []int16 {
+	tau := a.delays(angle, SpeedOfSound)
+	out := make([]int16, n*len(a.Mics))
+	for t := range n {
+		for m := range a.Mics {
+			var v float64
+			for _, tn := range tones {
+				v += tn.amp * math.Sin(2*math.Pi*tn.freq*(float64(t)/rate-tau[m])+tn.phase)
+			}
+			out[t*len(a.Mics)+m] = int16(math.Round(v))
+		}
+	}
+	return out
+}
+
+// center renders the tones as heard at the array center, delayed by
+// delay samples.
+func center(n, delay int, tones []tone) []int16 {
+	out := make([]int16, n)
+	for t := range n {
+		var v float64
+		for _, tn := range tones {
+			v += tn.amp * math.Sin(2*math.Pi*tn.freq*float64(t-delay)/rate+tn.phase)
+		}
+		out[t] = int16(math.Round(v))
+	}
+	return out
+}
+
+func add(a, b []int16) []int16 {
+	out := make([]int16, len(a))
+	for i := range a {
+		out[i] = a[i] + b[i]
+	}
+	return out
+}
+
+func power(s []int16) float64 {
+	var e float64
+	for _, v := range s {
+		e += float64(v) * float64(v)
+	}
+	return e / float64(len(s))
+}
+
+// snr returns the ratio of want to the error in got, in dB.
+func snr(got, want []int16) float64 {
+	var sig, noise float64
+	for i := range want {
+		d := float64(got[i]) - float64(want[i])
+		sig += float64(want[i]) * float64(want[i])
+		noise += d * d
+	}
+	return 10 * math.Log10(sig/noise)
+}
+
+var (
+	speech = []tone{{300, 2000, 0}, {700, 2000, 1}, {1200, 1500, 2}, {2500, 1000, 3}}
+	noise  = func() []tone {
+		rng := rand.New(rand.NewSource(1))
+		var tones []tone
+		for f := 450.0; f < 3800; f += 170 {
+			tones = append(tones, tone{f, 500, rng.Float64() * 2 * math.Pi})
+		}
+		return tones
+	}()
+)
+
+func newTestBeamformer(t *testing.T, method Method) *Beamformer {
+	t.Helper()
+	cfg := DefaultConfig(rate, 4)
+	cfg.Method = method
+	b, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return b
+}
+
+// A source in the steering direction comes through unchanged, just
+// delayed.
+func TestBeamformer_PassesTarget(t *testing.T) {
+	a := XVF3800()
+	for _, method := range []Method{DelayAndSum, MVDR} {
+		t.Run(method.String(), func(t *testing.T) {
+			b := newTestBeamformer(t, method)
+			if method == MVDR {
+				b.Process(planeWave(a, 2*rate, -1.2, noise)) // Learn a noise field, in whole hops
+				b.SetTargetActive(true)
+			}
+			b.Steer(0.4)
+
+			n := 2 * rate
+			out := b.Process(planeWave(a, n, 0.4, speech))
+			delay := int(b.Delay().Seconds() * rate)
+			want := center(n, delay, speech)
+			skip := 2 * b.n
+			if got := snr(out[skip:], want[skip:len(out)]); got < 30 {
+				t.Errorf("target SNR = %.1fdB, want over 30dB", got)
+			}
+		})
+	}
+}
+
+// MVDR learns an interferer while the target is quiet and nulls it,
+// well beyond what delay-and-sum manages.
+func TestBeamformer_NullsInterferer(t *testing.T) {
+	a := XVF3800()
+	suppression := map[Method]float64{}
+	for _, method := range []Method{DelayAndSum, MVDR} {
+		b := newTestBeamformer(t, method)
+		b.Steer(0.3)
+		b.Process(planeWave(a, 2*rate, -1.2, noise))
+		b.SetTargetActive(true)
+
+		in := planeWave(a, rate, -1.2, noise)
+		out := b.Process(in)
+		suppression[method] = 10 * math.Log10(power(center(rate, 0, noise))/power(out[b.n:]))
+	}
+	if got := suppression[MVDR]; got < 15 {
+		t.Errorf("MVDR suppressed the interferer %.1fdB, want over 15dB", got)
+	}
+	if suppression[MVDR] < suppression[DelayAndSum]+10 {
+		t.Errorf("MVDR %.1fdB vs delay-and-sum %.1fdB, want 10dB better", suppression[MVDR], suppression[DelayAndSum])
+	}
+}
+
+// With both talking, the target survives and the interferer drops.
+func TestBeamformer_Mixture(t *testing.T) {
+	a := XVF3800()
+	b := newTestBeamformer(t, MVDR)
+	b.Steer(0.3)
+	b.Process(planeWave(a, 2*rate, -1.2, noise))
+	b.SetTargetActive(true)
+
+	n := 2 * rate
+	in := add(planeWave(a, n, 0.3, speech), planeWave(a, n, -1.2, noise))
+	out := b.Process(in)
+	delay := int(b.Delay().Seconds() * rate)
+	want := center(n, delay, speech)
+	skip := 2 * b.n
+	before := snr(center(n, delay, append(append([]tone(nil), speech...), noise...))[skip:len(out)], want[skip:len(out)])
+	after := snr(out[skip:], want[skip:len(out)])
+	if after < before+10 {
+		t.Errorf("SNR %.1fdB → %.1fdB, want a 10dB gain", before, after)
+	}
+}
+
+// Writing in blocks must match writing all at once.
+func TestBeamformer_Streaming(t *testing.T) {
+	a := XVF3800()
+	in := add(planeWave(a, rate, 0.3, speech), planeWave(a, rate, -1.2, noise))
+
+	whole := newTestBeamformer(t, MVDR).Process(in)
+
+	b := newTestBeamformer(t, MVDR)
+	var chunked []int16
+	for off := 0; off < len(in); off += 4 * 37 {
+		chunked = b.Append(chunked, in[off:min(off+4*37, len(in))])
+	}
+	if len(chunked) != len(whole) {
+		t.Fatalf("chunked output %d samples, want %d", len(chunked), len(whole))
+	}
+	for i := range whole {
+		if whole[i] != chunked[i] {
+			t.Fatalf("sample %d = %d, want %d", i, chunked[i], whole[i])
+		}
+	}
+}
+
+func TestBeamformer_ChannelMap(t *testing.T) {
+	// Two processed channels, then the four raw mics
+	cfg := DefaultConfig(rate, 6)
+	cfg.Array.Channels = []int{2, 3, 4, 5}
+	cfg.Method = DelayAndSum
+	b, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw := planeWave(cfg.Array, rate, 0, speech)
+	in := make([]int16, rate*6)
+	for i := range rate {
+		in[i*6] = 9999 // Must be ignored
+		copy(in[i*6+2:i*6+6], raw[i*4:i*4+4])
+	}
+	out := b.Process(in)
+	want := center(rate, int(b.Delay().Seconds()*rate), speech)
+	if got := snr(out[2*b.n:], want[2*b.n:len(out)]); got < 30 {
+		t.Errorf("SNR = %.1fdB, want over 30dB", got)
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+	}{
+		{"no sample rate", func(c *Config) { c.SampleRate = 0 }},
+		{"too few channels", func(c *Config) { c.Channels = 3 }},
+		{"one mic", func(c *Config) { c.Array.Mics = c.Array.Mics[:1] }},
+		{"channel map length", func(c *Config) { c.Array.Channels = []int{0, 1} }},
+		{"FFT size", func(c *Config) { c.FFTSize = 500 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig(rate, 4)
+			tt.modify(&cfg)
+			if _, err := New(cfg); err == nil {
+				t.Error("New succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestParseMethod(t *testing.T) {
+	for _, m := range []Method{DelayAndSum, MVDR} {
+		if got, err := ParseMethod(m.String()); err != nil || got != m {
+			t.Errorf("ParseMethod(%q) = %v, %v", m.String(), got, err)
+		}
+	}
+	if _, err := ParseMethod("beam"); err == nil {
+		t.Error("ParseMethod(beam) succeeded")
+	}
+}
+
+func TestFFT(t *testing.T) {
+	const n = 64
+	rng := rand.New(rand.NewSource(2))
+	x := make([]complex128, n)
+	for i := range x {
+		x[i] = complex(rng.NormFloat64(), rng.NormFloat64())
+	}
+	got := append([]complex128(nil), x...)
+	f := newFFT(n)
+	f.transform(got, false)
+	for k := range n {
+		var want complex128
+		for i, v := range x {
+			want += v * cmplx.Exp(complex(0, -2*math.Pi*float64(i*k)/n))
+		}
+		if cmplx.Abs(got[k]-want) > 1e-9 {
+			t.Fatalf("bin %d = %v, want %v", k, got[k], want)
+		}
+	}
+	f.transform(got, true)
+	for i := range x {
+		if cmplx.Abs(got[i]-x[i]) > 1e-9 {
+			t.Fatalf("inverse sample %d = %v, want %v", i, got[i], x[i])
+		}
+	}
+}
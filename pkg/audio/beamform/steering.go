@@ -0,0 +1,74 @@
+package beamform
+
+import (
+	"sync"
+	"time"
+
+	"github.com/teslashibe/go-reachy/pkg/audio"
+)
+
+// Steering decides where the beam points: at the tracked person when the
+// world model has a focus target, otherwise toward the latest confident
+// DOA reading. Safe for concurrent use.
+type Steering struct {
+	// Focus returns the focus target's angle in DOA coordinates, e.g.
+	// worldmodel's GetTargetWorldAngle. May be nil.
+	Focus func() (float64, bool)
+
+	// MinConfidence ignores weaker DOA readings.
+	MinConfidence float64
+
+	// MaxAge ignores DOA readings older than this.
+	MaxAge time.Duration
+
+	mu       sync.Mutex
+	doa      float64
+	doaTime  time.Time // Of the last confident speaking reading
+	speaking bool
+	seen     time.Time // Of the last reading
+}
+
+// NewSteering creates a Steering that prefers focus.
+func NewSteering(focus func() (float64, bool)) *Steering {
+	return &Steering{
+		Focus:         focus,
+		MinConfidence: 0.3,
+		MaxAge:        2 * time.Second,
+	}
+}
+
+// UpdateDOA records a DOA reading; use it as an audio.Client listener.
+func (s *Steering) UpdateDOA(r *audio.DOAResult) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.speaking = r.Speaking
+	s.seen = now
+	if r.Speaking && r.Confidence >= s.MinConfidence {
+		s.doa = r.Angle
+		s.doaTime = now
+	}
+}
+
+// Angle returns the steering angle and where it came from ("focus" or
+// "doa"). ok is false when neither is available.
+func (s *Steering) Angle() (angle float64, source string, ok bool) {
+	if s.Focus != nil {
+		if a, ok := s.Focus(); ok {
+			return a, "focus", true
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.doaTime.IsZero() && time.Since(s.doaTime) <= s.MaxAge {
+		return s.doa, "doa", true
+	}
+	return 0, "", false
+}
+
+// Speaking reports whether the latest DOA reading heard speech.
+func (s *Steering) Speaking() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.speaking && time.Since(s.seen) <= s.MaxAge
+}
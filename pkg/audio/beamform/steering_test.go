@@ -0,0 +1,52 @@
+package beamform
+
+import (
+	"testing"
+	"time"
+
+	"github.com/teslashibe/go-reachy/pkg/audio"
+)
+
+func TestSteering(t *testing.T) {
+	var focus float64
+	var hasFocus bool
+	s := NewSteering(func() (float64, bool) { return focus, hasFocus })
+
+	if _, _, ok := s.Angle(); ok {
+		t.Error("Angle() ok with no focus or DOA")
+	}
+
+	s.UpdateDOA(&audio.DOAResult{Angle: 0.5, Speaking: true, Confidence: 0.1})
+	if _, _, ok := s.Angle(); ok {
+		t.Error("low-confidence DOA used")
+	}
+
+	s.UpdateDOA(&audio.DOAResult{Angle: 0.5, Speaking: true, Confidence: 0.8})
+	if a, src, ok := s.Angle(); !ok || a != 0.5 || src != "doa" {
+		t.Errorf("Angle() = %v, %q, %v, want DOA 0.5", a, src, ok)
+	}
+	if !s.Speaking() {
+		t.Error("Speaking() = false after a speaking reading")
+	}
+
+	focus, hasFocus = -0.2, true
+	if a, src, ok := s.Angle(); !ok || a != -0.2 || src != "focus" {
+		t.Errorf("Angle() = %v, %q, %v, want focus -0.2", a, src, ok)
+	}
+
+	// A quiet reading keeps the last direction but ends speech
+	hasFocus = false
+	s.UpdateDOA(&audio.DOAResult{Angle: 2, Confidence: 0.9})
+	if a, _, _ := s.Angle(); a != 0.5 {
+		t.Errorf("Angle() = %v after a quiet reading, want 0.5", a)
+	}
+	if s.Speaking() {
+		t.Error("Speaking() = true after a quiet reading")
+	}
+
+	s.MaxAge = time.Millisecond
+	time.Sleep(5 * time.Millisecond)
+	if _, _, ok := s.Angle(); ok {
+		t.Error("stale DOA used")
+	}
+}
@@ -0,0 +1,194 @@
+package audio
+
+import (
+	"context"
+	"time"
+
+	"github.com/teslashibe/go-reachy/pkg/audio/dsp"
+)
+
+// Channel names one of an Output's mixer channels.
+type Channel string
+
+// Eva's output channels.
+const (
+	ChannelSpeech  Channel = "speech"  // Conversation responses
+	ChannelAlerts  Channel = "alerts"  // Timer and reminder announcements
+	ChannelEffects Channel = "effects" // Emotion sounds and cues
+	ChannelMusic   Channel = "music"
+)
+
+// ChannelConfig configures one mixer channel.
+type ChannelConfig struct {
+	// Priority orders channels: while one is playing, every channel with
+	// a lower priority is ducked.
+	Priority int
+
+	// Volume is the channel's linear gain (1 is unity).
+	Volume float64
+
+	// Exclusive channels take turns: an utterance waits until no other
+	// exclusive channel is mid-utterance, and is not cut off by one. This
+	// keeps an announcement from talking over Eva, or her over it.
+	Exclusive bool
+}
+
+// DefaultChannels returns Eva's mixer layout. Alerts queue behind a
+// response in progress, then play in full with everything else ducked;
+// speech ducks effects and music.
+func DefaultChannels() map[Channel]ChannelConfig {
+	return map[Channel]ChannelConfig{
+		ChannelAlerts:  {Priority: 3, Volume: 1, Exclusive: true},
+		ChannelSpeech:  {Priority: 2, Volume: 1, Exclusive: true},
+		ChannelEffects: {Priority: 1, Volume: 0.8},
+		ChannelMusic:   {Priority: 0, Volume: 0.5},
+	}
+}
+
+// OutputChannel is one channel of an Output's mixer. Like the Output
+// itself (which writes to ChannelSpeech), it plays utterances: the first
+// Write starts one and Flush ends it. An utterance written while another
+// is still playing queues behind it.
+type OutputChannel struct {
+	o *Output
+	t *track
+}
+
+// segment is one utterance queued on a channel.
+type segment struct {
+	samples []int16 // 48kHz, not yet sent
+	flushed bool
+	done    chan struct{}
+}
+
+// track is a channel's state inside an Output. Fields are guarded by
+// the Output's mu.
+type track struct {
+	name   Channel
+	cfg    ChannelConfig
+	volume float32
+	duck   float32        // Current ducking gain
+	rs     *dsp.Resampler // InputRate to 48kHz; nil when they match
+
+	segs     []*segment    // segs[0] is playing or next
+	started  bool          // segs[0] is past its prebuffer
+	played   time.Duration // Of segs[0]
+	sounding bool          // Contributed to the last frame
+}
+
+// Name returns the channel's name.
+func (c *OutputChannel) Name() Channel {
+	return c.t.name
+}
+
+// Write queues PCM16 mono audio at the Output's InputRate.
+func (c *OutputChannel) Write(pcm []byte) error {
+	return c.o.write(c.t, pcm)
+}
+
+// Flush marks the end of the utterance; queued audio still plays.
+func (c *OutputChannel) Flush() {
+	c.o.flush(c.t)
+}
+
+// Wait blocks until the last queued utterance has finished playing or
+// been cancelled.
+func (c *OutputChannel) Wait(ctx context.Context) error {
+	return c.o.wait(c.t, ctx)
+}
+
+// Play queues pcm as a complete utterance and waits for it to finish.
+// Concurrent calls play one after the other, never mixed together.
+func (c *OutputChannel) Play(ctx context.Context, pcm []byte) error {
+	done, err := c.o.enqueue(c.t, pcm)
+	if err != nil {
+		return err
+	}
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Cancel drops the channel's queued audio and ends its utterances.
+func (c *OutputChannel) Cancel() {
+	c.o.cancel(c.t)
+}
+
+// Playing reports whether an utterance is in progress or queued.
+func (c *OutputChannel) Playing() bool {
+	c.o.mu.Lock()
+	defer c.o.mu.Unlock()
+	return len(c.t.segs) > 0
+}
+
+// Position returns how much of the current utterance the listener has
+// heard.
+func (c *OutputChannel) Position() time.Duration {
+	return c.o.position(c.t)
+}
+
+// Buffered returns how much audio is queued but not yet sent.
+func (c *OutputChannel) Buffered() time.Duration {
+	return c.o.buffered(c.t)
+}
+
+// Volume returns the channel's linear gain.
+func (c *OutputChannel) Volume() float64 {
+	c.o.mu.Lock()
+	defer c.o.mu.Unlock()
+	return float64(c.t.volume)
+}
+
+// SetVolume sets the channel's linear gain; it applies from the next
+// frame.
+func (c *OutputChannel) SetVolume(v float64) {
+	c.o.mu.Lock()
+	defer c.o.mu.Unlock()
+	c.t.volume = float32(max(v, 0))
+}
+
+// unflushedLocked returns the utterance being written, if any; there is
+// at most one. Caller holds mu.
+func (t *track) unflushedLocked() *segment {
+	for _, seg := range t.segs {
+		if !seg.flushed {
+			return seg
+		}
+	}
+	return nil
+}
+
+// openLocked returns the utterance Write appends to, starting a new one
+// if there is none. Caller holds mu.
+func (t *track) openLocked() *segment {
+	if seg := t.unflushedLocked(); seg != nil {
+		return seg
+	}
+	if t.rs != nil {
+		t.rs.Reset()
+	}
+	seg := &segment{done: make(chan struct{})}
+	t.segs = append(t.segs, seg)
+	return seg
+}
+
+// pending returns how many samples of the utterance being written are
+// still in the resampler. Caller holds mu.
+func (t *track) pending() int {
+	if t.rs == nil {
+		return 0
+	}
+	return t.rs.Pending()
+}
+
+// finishHeadLocked retires the playing utterance. Caller holds mu.
+func (t *track) finishHeadLocked() {
+	close(t.segs[0].done)
+	t.segs[0] = nil
+	t.segs = t.segs[1:]
+	t.started = false
+	t.played = 0
+}
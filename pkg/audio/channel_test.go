@@ -0,0 +1,216 @@
+package audio
+
+import (
+	"context"
+	"math"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newMixerOutput is newTestOutput with instant ducking, so frame levels
+// are predictable.
+func newMixerOutput(t *testing.T) (*Output, *LoopbackSink) {
+	t.Helper()
+	sink := NewLoopbackSink()
+	cfg := DefaultOutputConfig()
+	cfg.Prebuffer = 0
+	cfg.Channels = map[Channel]ChannelConfig{
+		ChannelAlerts: {Priority: 3, Volume: 1, Exclusive: true},
+		ChannelSpeech: {Priority: 2, Volume: 1, Exclusive: true},
+		ChannelMusic:  {Priority: 0, Volume: 1},
+	}
+	cfg.DuckDB = -20
+	cfg.DuckAttack = time.Nanosecond
+	cfg.DuckRelease = time.Nanosecond
+	cfg.NewEncoder = func(int, int) (FrameEncoder, error) { return fakeEncoder{}, nil }
+	out, err := NewOutput(sink, cfg)
+	if err != nil {
+		t.Fatalf("NewOutput: %v", err)
+	}
+	t.Cleanup(func() { out.Close() })
+	return out, sink
+}
+
+// pcmLevel returns d of 24kHz PCM16 at a constant level.
+func pcmLevel(d time.Duration, level int16) []byte {
+	samples := make([]int16, int(24000*d/time.Second))
+	for i := range samples {
+		samples[i] = level
+	}
+	return ConvertInt16ToPCM16(samples)
+}
+
+// frameLevel returns the middle sample of each frame sent.
+func frameLevels(sink *LoopbackSink) []int {
+	var levels []int
+	for _, f := range sink.Frames() {
+		levels = append(levels, int(f.PCM[len(f.PCM)/2]))
+	}
+	return levels
+}
+
+func near(got, want int) bool {
+	return math.Abs(float64(got-want)) <= 20
+}
+
+func TestOutput_Channels(t *testing.T) {
+	out, _ := newMixerOutput(t)
+	if out.Channel(ChannelSpeech) == nil || out.Channel(ChannelMusic) == nil {
+		t.Error("configured channel missing")
+	}
+	if c := out.Channel(ChannelEffects); c != nil {
+		t.Error("Channel(effects) should be nil when not configured")
+	}
+
+	// Speech is always there, even if the layout leaves it out
+	cfg := DefaultOutputConfig()
+	cfg.Channels = map[Channel]ChannelConfig{ChannelMusic: {Volume: 1}}
+	cfg.NewEncoder = func(int, int) (FrameEncoder, error) { return fakeEncoder{}, nil }
+	o, err := NewOutput(NewLoopbackSink(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer o.Close()
+	if o.Channel(ChannelSpeech) == nil {
+		t.Error("speech channel not added")
+	}
+}
+
+func TestOutput_Ducking(t *testing.T) {
+	out, sink := newMixerOutput(t)
+	music := out.Channel(ChannelMusic)
+
+	music.Write(pcmLevel(time.Second, 2000))
+	time.Sleep(100 * time.Millisecond)
+	out.Write(pcmLevel(100*time.Millisecond, 1000))
+	out.Flush()
+	waitOutput(t, out)
+	time.Sleep(100 * time.Millisecond)
+	music.Cancel()
+
+	// Music alone, then ducked by 20dB under speech, then back
+	var phases []string
+	for _, v := range frameLevels(sink) {
+		var p string
+		switch {
+		case near(v, 2000):
+			p = "music"
+		case near(v, 200+1000):
+			p = "ducked"
+		default:
+			continue // Ramp frames at the edges
+		}
+		if len(phases) == 0 || phases[len(phases)-1] != p {
+			phases = append(phases, p)
+		}
+	}
+	want := []string{"music", "ducked", "music"}
+	if len(phases) != len(want) || phases[0] != want[0] || phases[1] != want[1] || phases[2] != want[2] {
+		t.Errorf("phases = %v, want %v (levels %v)", phases, want, frameLevels(sink))
+	}
+}
+
+// An alert raised mid-response waits for it, then plays in full; speech
+// arriving during the alert waits in turn.
+func TestOutput_AlertWaitsForSpeech(t *testing.T) {
+	out, sink := newMixerOutput(t)
+	alerts := out.Channel(ChannelAlerts)
+
+	out.Write(pcmLevel(100*time.Millisecond, 1000))
+	time.Sleep(30 * time.Millisecond)
+
+	played := make(chan error, 1)
+	go func() {
+		played <- alerts.Play(context.Background(), pcmLevel(100*time.Millisecond, 3000))
+	}()
+	time.Sleep(30 * time.Millisecond)
+	out.Write(pcmLevel(60*time.Millisecond, 1000)) // Same response
+	out.Flush()
+	waitOutput(t, out)
+
+	// The next response queues behind the alert
+	time.Sleep(30 * time.Millisecond)
+	out.Write(pcmLevel(60*time.Millisecond, 1000))
+	out.Flush()
+
+	if err := <-played; err != nil {
+		t.Fatalf("Play: %v", err)
+	}
+	waitOutput(t, out)
+
+	var order []int
+	for _, v := range frameLevels(sink) {
+		if !near(v, 1000) && !near(v, 3000) {
+			t.Fatalf("frame level %d: alert and speech mixed (levels %v)", v, frameLevels(sink))
+		}
+		if len(order) == 0 || !near(order[len(order)-1], v) {
+			order = append(order, v)
+		}
+	}
+	if len(order) != 3 || !near(order[0], 1000) || !near(order[1], 3000) || !near(order[2], 1000) {
+		t.Errorf("levels = %v, want speech, alert, speech", frameLevels(sink))
+	}
+}
+
+func TestOutput_AlertSurvivesCancel(t *testing.T) {
+	out, sink := newMixerOutput(t)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- out.Channel(ChannelAlerts).Play(context.Background(), pcmLevel(100*time.Millisecond, 3000))
+	}()
+	time.Sleep(40 * time.Millisecond)
+	out.Cancel() // Barge-in stops speech, not alerts
+
+	if err := <-done; err != nil {
+		t.Fatalf("Play: %v", err)
+	}
+	if n := len(sink.Frames()); n != 5 {
+		t.Errorf("frames = %d, want the whole 5-frame alert", n)
+	}
+}
+
+func TestOutput_AlertQueue(t *testing.T) {
+	out, sink := newMixerOutput(t)
+	alerts := out.Channel(ChannelAlerts)
+
+	var wg sync.WaitGroup
+	for _, level := range []int16{1000, 3000} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			alerts.Play(context.Background(), pcmLevel(60*time.Millisecond, level))
+		}()
+	}
+	wg.Wait()
+
+	levels := frameLevels(sink)
+	if len(levels) != 6 {
+		t.Fatalf("frames = %d, want 6", len(levels))
+	}
+	for _, v := range levels {
+		if !near(v, 1000) && !near(v, 3000) {
+			t.Errorf("frame level %d: alerts mixed together", v)
+		}
+	}
+}
+
+func TestOutputChannel_Volume(t *testing.T) {
+	out, sink := newMixerOutput(t)
+	music := out.Channel(ChannelMusic)
+
+	music.SetVolume(0.5)
+	if v := music.Volume(); v != 0.5 {
+		t.Errorf("Volume() = %v, want 0.5", v)
+	}
+	music.Write(pcmLevel(60*time.Millisecond, 2000))
+	music.Flush()
+	music.Wait(context.Background())
+
+	for _, v := range frameLevels(sink) {
+		if !near(v, 1000) {
+			t.Errorf("frame level %d, want 1000 at half volume", v)
+		}
+	}
+}
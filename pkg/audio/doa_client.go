@@ -54,6 +54,7 @@ type Client struct {
 	conn      *websocket.Conn
 	connected bool
 	handler   DOAHandler
+	listeners []DOAHandler
 	cancel    context.CancelFunc
 }
 
@@ -150,6 +151,14 @@ func (c *Client) StreamDOA(ctx context.Context, handler DOAHandler) error {
 	return nil
 }
 
+// AddListener registers an extra handler for streamed DOA readings, for
+// consumers besides the one that called StreamDOA (e.g. the local VAD).
+func (c *Client) AddListener(fn DOAHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.listeners = append(c.listeners, fn)
+}
+
 // readLoop reads WebSocket messages and calls the handler
 func (c *Client) readLoop(ctx context.Context) {
 	defer func() {
@@ -172,6 +181,7 @@ func (c *Client) readLoop(ctx context.Context) {
 		c.mu.RLock()
 		conn := c.conn
 		handler := c.handler
+		listeners := c.listeners
 		c.mu.RUnlock()
 
 		if conn == nil {
@@ -202,10 +212,15 @@ func (c *Client) readLoop(ctx context.Context) {
 		}
 
 		// Handle DOA messages
-		if msg.Type == "doa" && handler != nil {
+		if msg.Type == "doa" && (handler != nil || len(listeners) > 0) {
 			var result DOAResult
 			if err := json.Unmarshal(msg.Data, &result); err == nil {
-				handler(&result)
+				if handler != nil {
+					handler(&result)
+				}
+				for _, fn := range listeners {
+					fn(&result)
+				}
 			}
 		}
 	}
@@ -0,0 +1,62 @@
+package dsp
+
+import "math"
+
+// butterworthQ gives a maximally flat second-order response.
+const butterworthQ = math.Sqrt2 / 2
+
+// Biquad is a second-order IIR filter (RBJ cookbook coefficients), run
+// in transposed direct form II. State is float64 so low cutoffs at high
+// sample rates stay stable.
+type Biquad struct {
+	b0, b1, b2, a1, a2 float64
+	z1, z2             float64
+}
+
+// NewHighPass creates a Butterworth high-pass filter, e.g. at 80-100Hz
+// to remove rumble, motor hum and DC from the mic.
+func NewHighPass(sampleRate int, cutoffHz float64) *Biquad {
+	w := 2 * math.Pi * cutoffHz / float64(sampleRate)
+	cos, alpha := math.Cos(w), math.Sin(w)/(2*butterworthQ)
+	a0 := 1 + alpha
+	return &Biquad{
+		b0: (1 + cos) / 2 / a0,
+		b1: -(1 + cos) / a0,
+		b2: (1 + cos) / 2 / a0,
+		a1: -2 * cos / a0,
+		a2: (1 - alpha) / a0,
+	}
+}
+
+// NewLowPass creates a Butterworth low-pass filter.
+func NewLowPass(sampleRate int, cutoffHz float64) *Biquad {
+	w := 2 * math.Pi * cutoffHz / float64(sampleRate)
+	cos, alpha := math.Cos(w), math.Sin(w)/(2*butterworthQ)
+	a0 := 1 + alpha
+	return &Biquad{
+		b0: (1 - cos) / 2 / a0,
+		b1: (1 - cos) / a0,
+		b2: (1 - cos) / 2 / a0,
+		a1: -2 * cos / a0,
+		a2: (1 - alpha) / a0,
+	}
+}
+
+// Process filters samples in place.
+func (f *Biquad) Process(samples []int16) {
+	b0, b1, b2, a1, a2 := f.b0, f.b1, f.b2, f.a1, f.a2
+	z1, z2 := f.z1, f.z2
+	for i, s := range samples {
+		x := float64(s)
+		y := b0*x + z1
+		z1 = b1*x - a1*y + z2
+		z2 = b2*x - a2*y
+		samples[i] = toInt16(float32(y))
+	}
+	f.z1, f.z2 = z1, z2
+}
+
+// Reset clears the filter state.
+func (f *Biquad) Reset() {
+	f.z1, f.z2 = 0, 0
+}
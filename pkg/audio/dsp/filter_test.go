@@ -0,0 +1,65 @@
+package dsp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBiquad(t *testing.T) {
+	const rate = 16000
+	tests := []struct {
+		name   string
+		filter func() *Biquad
+		freq   float64
+		wantDB float64 // Expected level change
+		tol    float64
+	}{
+		{"high-pass stops rumble", func() *Biquad { return NewHighPass(rate, 100) }, 20, -28, 2},
+		{"high-pass passes speech", func() *Biquad { return NewHighPass(rate, 100) }, 1000, 0, 0.1},
+		{"high-pass at cutoff", func() *Biquad { return NewHighPass(rate, 100) }, 100, -3, 0.2},
+		{"low-pass passes speech", func() *Biquad { return NewLowPass(rate, 4000) }, 300, 0, 0.1},
+		{"low-pass stops hiss", func() *Biquad { return NewLowPass(rate, 2000) }, 7000, -40, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := tone(rate, tt.freq, rate, 16000)
+			out := append([]int16(nil), in...)
+			tt.filter().Process(out)
+			skip := rate / 4 // Settle
+			got := LevelDBFS(out[skip:]) - LevelDBFS(in[skip:])
+			if math.Abs(got-tt.wantDB) > tt.tol {
+				t.Errorf("%.0fHz: level changed %.1fdB, want %.1f±%.1fdB", tt.freq, got, tt.wantDB, tt.tol)
+			}
+		})
+	}
+}
+
+func TestHighPass_RemovesDC(t *testing.T) {
+	in := make([]int16, 16000)
+	for i := range in {
+		in[i] = 5000
+	}
+	f := NewHighPass(16000, 80)
+	f.Process(in)
+	if got := PeakDBFS(in[8000:]); got > -80 {
+		t.Errorf("DC residue at %.1fdBFS, want below -80dBFS", got)
+	}
+}
+
+// Processing in blocks must match processing all at once.
+func TestBiquad_Streaming(t *testing.T) {
+	in := tone(16000, 440, 4000, 12000)
+	whole := append([]int16(nil), in...)
+	NewHighPass(16000, 100).Process(whole)
+
+	chunked := append([]int16(nil), in...)
+	f := NewHighPass(16000, 100)
+	for off := 0; off < len(chunked); off += 160 {
+		f.Process(chunked[off:min(off+160, len(chunked))])
+	}
+	for i := range whole {
+		if whole[i] != chunked[i] {
+			t.Fatalf("sample %d = %d, want %d", i, chunked[i], whole[i])
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package dsp
+
+import (
+	"math"
+	"time"
+)
+
+// DBToGain converts decibels to a linear gain.
+func DBToGain(db float64) float64 {
+	return math.Pow(10, db/20)
+}
+
+// GainToDB converts a linear gain to decibels.
+func GainToDB(gain float64) float64 {
+	return 20 * math.Log10(gain)
+}
+
+// LevelDBFS returns the RMS level of samples in dBFS (-Inf for silence).
+func LevelDBFS(samples []int16) float64 {
+	var sum float64
+	for _, s := range samples {
+		v := float64(s)
+		sum += v * v
+	}
+	if sum == 0 {
+		return math.Inf(-1)
+	}
+	return 10 * math.Log10(sum/float64(len(samples))/(32768*32768))
+}
+
+// PeakDBFS returns the peak level of samples in dBFS.
+func PeakDBFS(samples []int16) float64 {
+	peak := 0
+	for _, s := range samples {
+		peak = max(peak, abs(int(s)))
+	}
+	if peak == 0 {
+		return math.Inf(-1)
+	}
+	return 20 * math.Log10(float64(peak)/32768)
+}
+
+// ApplyGain scales samples in place by a linear gain, saturating.
+func ApplyGain(samples []int16, gain float64) {
+	g := float32(gain)
+	for i, s := range samples {
+		samples[i] = toInt16(float32(s) * g)
+	}
+}
+
+// AGCConfig tunes automatic gain control.
+type AGCConfig struct {
+	// TargetDBFS is the RMS level speech is brought to.
+	TargetDBFS float64
+
+	// MinGainDB and MaxGainDB bound the gain.
+	MinGainDB float64
+	MaxGainDB float64
+
+	// NoiseFloorDBFS: quieter audio holds the gain rather than being
+	// boosted, so background hiss doesn't swell between words.
+	NoiseFloorDBFS float64
+
+	// Attack is how fast gain drops when audio gets louder; Release how
+	// fast it recovers.
+	Attack  time.Duration
+	Release time.Duration
+
+	// LimitDBFS caps output peaks.
+	LimitDBFS float64
+}
+
+// DefaultAGCConfig returns settings for speech from a far-field mic.
+func DefaultAGCConfig() AGCConfig {
+	return AGCConfig{
+		TargetDBFS:     -20,
+		MinGainDB:      -12,
+		MaxGainDB:      30,
+		NoiseFloorDBFS: -55,
+		Attack:         20 * time.Millisecond,
+		Release:        time.Second,
+		LimitDBFS:      -1,
+	}
+}
+
+// agcFrame is the AGC's analysis step.
+const agcFrame = 10 * time.Millisecond
+
+// AGC is an automatic gain control: it measures the level of the
+// incoming audio over ~100ms and steers a smoothed gain toward the
+// target, with a peak limiter so a sudden shout doesn't clip.
+type AGC struct {
+	cfg   AGCConfig
+	frame int
+
+	level   float64 // Smoothed power (linear, full scale = 1)
+	gainDB  float64
+	applied float32 // Linear gain at the end of the last frame
+
+	levelCoef, attackCoef, releaseCoef float64
+	limit                              float32
+}
+
+// NewAGC creates an AGC for audio at sampleRate.
+func NewAGC(sampleRate int, cfg AGCConfig) *AGC {
+	coef := func(tau time.Duration) float64 {
+		if tau <= 0 {
+			return 1
+		}
+		return 1 - math.Exp(-float64(agcFrame)/float64(tau))
+	}
+	a := &AGC{
+		cfg:         cfg,
+		frame:       max(1, int(int64(sampleRate)*int64(agcFrame)/int64(time.Second))),
+		levelCoef:   coef(100 * time.Millisecond),
+		attackCoef:  coef(cfg.Attack),
+		releaseCoef: coef(cfg.Release),
+		limit:       float32(32768 * DBToGain(cfg.LimitDBFS)),
+	}
+	a.Reset()
+	return a
+}
+
+// GainDB returns the current gain.
+func (a *AGC) GainDB() float64 {
+	return a.gainDB
+}
+
+// Reset returns to unity gain.
+func (a *AGC) Reset() {
+	a.gainDB = 0
+	a.applied = 1
+	a.level = 0
+}
+
+// Process applies the gain to samples in place.
+func (a *AGC) Process(samples []int16) {
+	for off := 0; off < len(samples); off += a.frame {
+		a.processFrame(samples[off:min(off+a.frame, len(samples))])
+	}
+}
+
+func (a *AGC) processFrame(frame []int16) {
+	var sum float64
+	peak := 0
+	for _, s := range frame {
+		v := float64(s)
+		sum += v * v
+		peak = max(peak, abs(int(s)))
+	}
+	// Only frames above the noise floor count toward the level, so the
+	// tail of a word doesn't drag it down and swell the gain on hiss.
+	power := sum / float64(len(frame)) / (32768 * 32768)
+	if 10*math.Log10(power+1e-12) > a.cfg.NoiseFloorDBFS {
+		if a.level == 0 {
+			a.level = power
+		}
+		a.level += a.levelCoef * (power - a.level)
+		levelDB := 10 * math.Log10(a.level)
+		want := min(max(a.cfg.TargetDBFS-levelDB, a.cfg.MinGainDB), a.cfg.MaxGainDB)
+		c := a.releaseCoef
+		if want < a.gainDB {
+			c = a.attackCoef
+		}
+		a.gainDB += c * (want - a.gainDB)
+	}
+
+	// Never let this frame's peak exceed the limit
+	g := float32(DBToGain(a.gainDB))
+	if p := float32(peak) * g; p > a.limit {
+		g = a.limit / float32(peak)
+		a.applied = min(a.applied, g) // No ramp: the limit applies from the first sample
+	}
+
+	// Ramp from the previous gain to avoid zipper noise
+	start, step := a.applied, (g-a.applied)/float32(len(frame))
+	for i, s := range frame {
+		frame[i] = toInt16(float32(s) * (start + step*float32(i+1)))
+	}
+	a.applied = g
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
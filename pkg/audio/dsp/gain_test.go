@@ -0,0 +1,93 @@
+package dsp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDBToGain(t *testing.T) {
+	tests := []struct {
+		db, gain float64
+	}{
+		{0, 1},
+		{20, 10},
+		{-20, 0.1},
+		{-6.0206, 0.5},
+	}
+	for _, tt := range tests {
+		if got := DBToGain(tt.db); math.Abs(got-tt.gain) > 1e-4 {
+			t.Errorf("DBToGain(%v) = %v, want %v", tt.db, got, tt.gain)
+		}
+		if got := GainToDB(tt.gain); math.Abs(got-tt.db) > 1e-3 {
+			t.Errorf("GainToDB(%v) = %v, want %v", tt.gain, got, tt.db)
+		}
+	}
+}
+
+func TestLevelDBFS(t *testing.T) {
+	// A full-scale sine is -3dBFS RMS
+	if got := LevelDBFS(tone(16000, 1000, 16000, 32767)); math.Abs(got+3.01) > 0.05 {
+		t.Errorf("LevelDBFS(full-scale sine) = %.2f, want -3.01", got)
+	}
+	if got := PeakDBFS(tone(16000, 1000, 16000, 16384)); math.Abs(got+6.02) > 0.05 {
+		t.Errorf("PeakDBFS(half-scale sine) = %.2f, want -6.02", got)
+	}
+	if got := LevelDBFS(make([]int16, 100)); !math.IsInf(got, -1) {
+		t.Errorf("LevelDBFS(silence) = %v, want -Inf", got)
+	}
+}
+
+func TestApplyGain_Saturates(t *testing.T) {
+	s := []int16{1000, -1000, 20000, -20000}
+	ApplyGain(s, 2)
+	want := []int16{2000, -2000, 32767, -32768}
+	for i := range want {
+		if s[i] != want[i] {
+			t.Errorf("sample %d = %d, want %d", i, s[i], want[i])
+		}
+	}
+}
+
+func TestAGC_Converges(t *testing.T) {
+	const rate = 16000
+	cfg := DefaultAGCConfig()
+	for _, amp := range []float64{300, 3000, 15000} {
+		a := NewAGC(rate, cfg)
+		s := tone(rate, 300, 5*rate, amp) // Release takes a few seconds
+		a.Process(s)
+		got := LevelDBFS(s[4*rate:])
+		if math.Abs(got-cfg.TargetDBFS) > 2 {
+			t.Errorf("amplitude %.0f: output at %.1fdBFS, want %.0f±2dBFS", amp, got, cfg.TargetDBFS)
+		}
+	}
+}
+
+func TestAGC_HoldsOnNoise(t *testing.T) {
+	const rate = 16000
+	a := NewAGC(rate, DefaultAGCConfig())
+	s := tone(rate, 300, rate, 3000)
+	a.Process(s)
+	before := a.GainDB()
+
+	// Below the noise floor: hiss mustn't be pumped up between words
+	quiet := tone(rate, 300, 2*rate, 20)
+	a.Process(quiet)
+	if got := a.GainDB(); math.Abs(got-before) > 0.01 {
+		t.Errorf("gain moved %.2fdB -> %.2fdB on noise", before, got)
+	}
+}
+
+func TestAGC_NoClipping(t *testing.T) {
+	const rate = 16000
+	cfg := DefaultAGCConfig()
+	a := NewAGC(rate, cfg)
+
+	// Boost during a quiet stretch, then shout
+	quiet := tone(rate, 300, 3*rate, 300)
+	a.Process(quiet)
+	loud := tone(rate, 300, rate/2, 30000)
+	a.Process(loud)
+	if got := PeakDBFS(loud); got > cfg.LimitDBFS+0.1 {
+		t.Errorf("peak %.2fdBFS, want <= %.0fdBFS", got, cfg.LimitDBFS)
+	}
+}
@@ -0,0 +1,107 @@
+package dsp
+
+import (
+	"math"
+	"time"
+)
+
+// NoiseGateConfig tunes a NoiseGate.
+type NoiseGateConfig struct {
+	// ThresholdDBFS opens the gate; it closes HysteresisDB below.
+	ThresholdDBFS float64
+	HysteresisDB  float64
+
+	// RangeDB is the attenuation while closed (e.g. -40; -Inf mutes).
+	RangeDB float64
+
+	// Attack is the fade-in on opening, Hold how long the gate stays
+	// open after the level drops, Release the fade-out.
+	Attack  time.Duration
+	Hold    time.Duration
+	Release time.Duration
+}
+
+// DefaultNoiseGateConfig returns settings that silence room noise
+// between words without chopping quiet consonants.
+func DefaultNoiseGateConfig() NoiseGateConfig {
+	return NoiseGateConfig{
+		ThresholdDBFS: -50,
+		HysteresisDB:  6,
+		RangeDB:       -40,
+		Attack:        2 * time.Millisecond,
+		Hold:          150 * time.Millisecond,
+		Release:       100 * time.Millisecond,
+	}
+}
+
+// NoiseGate attenuates audio whose level stays below a threshold.
+type NoiseGate struct {
+	open, close float32 // Envelope thresholds (linear, full scale 32768)
+	floor       float32 // Gain while closed
+	attack      float32 // Gain change per sample
+	release     float32
+	decay       float32 // Envelope decay per sample
+	hold        int
+
+	env    float32
+	gain   float32
+	held   int // Samples left in the hold period
+	isOpen bool
+}
+
+// NewNoiseGate creates a gate for audio at sampleRate.
+func NewNoiseGate(sampleRate int, cfg NoiseGateConfig) *NoiseGate {
+	samples := func(d time.Duration) float32 {
+		return float32(max(1, float64(sampleRate)*d.Seconds()))
+	}
+	floor := float32(DBToGain(cfg.RangeDB))
+	g := &NoiseGate{
+		open:    float32(32768 * DBToGain(cfg.ThresholdDBFS)),
+		close:   float32(32768 * DBToGain(cfg.ThresholdDBFS-cfg.HysteresisDB)),
+		floor:   floor,
+		attack:  (1 - floor) / samples(cfg.Attack),
+		release: (1 - floor) / samples(cfg.Release),
+		decay:   float32(math.Exp(-1 / (float64(sampleRate) * 0.010))), // 10ms envelope release
+		hold:    int(samples(cfg.Hold)),
+	}
+	g.Reset()
+	return g
+}
+
+// Open reports whether the gate is passing audio.
+func (g *NoiseGate) Open() bool {
+	return g.isOpen
+}
+
+// Reset closes the gate.
+func (g *NoiseGate) Reset() {
+	g.env = 0
+	g.gain = g.floor
+	g.held = 0
+	g.isOpen = false
+}
+
+// Process gates samples in place.
+func (g *NoiseGate) Process(samples []int16) {
+	for i, s := range samples {
+		x := float32(s)
+		g.env = max(x, -x, g.env*g.decay)
+
+		switch {
+		case g.env >= g.open:
+			g.isOpen = true
+			g.held = g.hold
+		case g.env < g.close && g.isOpen:
+			if g.held--; g.held <= 0 {
+				g.isOpen = false
+			}
+		}
+
+		if g.isOpen {
+			g.gain = min(1, g.gain+g.attack)
+		} else {
+			g.gain = max(g.floor, g.gain-g.release)
+		}
+		samples[i] = toInt16(x * g.gain)
+	}
+}
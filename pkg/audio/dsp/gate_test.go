@@ -0,0 +1,59 @@
+package dsp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNoiseGate(t *testing.T) {
+	const rate = 16000
+	cfg := DefaultNoiseGateConfig()
+
+	t.Run("noise is attenuated", func(t *testing.T) {
+		g := NewNoiseGate(rate, cfg)
+		in := tone(rate, 300, rate, 50) // About -59dBFS peak
+		out := append([]int16(nil), in...)
+		g.Process(out)
+		if g.Open() {
+			t.Error("gate opened on noise")
+		}
+		got := LevelDBFS(out) - LevelDBFS(in)
+		if math.Abs(got-cfg.RangeDB) > 2 { // Rounding at this level
+			t.Errorf("noise changed %.1fdB, want %.0fdB", got, cfg.RangeDB)
+		}
+	})
+
+	t.Run("speech passes", func(t *testing.T) {
+		g := NewNoiseGate(rate, cfg)
+		in := tone(rate, 300, rate, 3000)
+		out := append([]int16(nil), in...)
+		g.Process(out)
+		if !g.Open() {
+			t.Error("gate closed on speech")
+		}
+		if got := LevelDBFS(out[rate/10:]) - LevelDBFS(in[rate/10:]); math.Abs(got) > 0.1 {
+			t.Errorf("speech changed %.2fdB, want 0", got)
+		}
+	})
+
+	t.Run("hold then release", func(t *testing.T) {
+		g := NewNoiseGate(rate, cfg)
+		g.Process(tone(rate, 300, rate/2, 3000))
+
+		hold := int(cfg.Hold.Seconds() * rate)
+		silence := make([]int16, hold/2)
+		g.Process(silence)
+		if !g.Open() {
+			t.Error("gate closed during hold")
+		}
+		g.Process(make([]int16, hold))
+		if g.Open() {
+			t.Error("gate still open after hold")
+		}
+
+		g.Reset()
+		if g.Open() {
+			t.Error("gate open after Reset")
+		}
+	})
+}
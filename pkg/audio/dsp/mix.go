@@ -0,0 +1,150 @@
+package dsp
+
+import (
+	"math"
+	"sync"
+)
+
+// limitKnee is where the mixer's soft limiter starts (about -2.5dBFS).
+const limitKnee = 24576
+
+// SoftLimit converts a mixed sample to PCM16: it passes v below the knee
+// and compresses what's above it smoothly toward full scale, so summed
+// sources never wrap or clip hard.
+func SoftLimit(v float32) int16 {
+	a := v
+	if a < 0 {
+		a = -a
+	}
+	if a <= limitKnee {
+		return toInt16(v)
+	}
+	const room = 32767 - limitKnee
+	y := limitKnee + room*float32(math.Tanh(float64((a-limitKnee)/room)))
+	if v < 0 {
+		y = -y
+	}
+	return toInt16(y)
+}
+
+// Mix sums srcs into dst, overwriting it and soft-limiting the result.
+// Sources shorter than dst contribute silence past their end.
+func Mix(dst []int16, srcs ...[]int16) {
+	for i := range dst {
+		var sum float32
+		for _, src := range srcs {
+			if i < len(src) {
+				sum += float32(src[i])
+			}
+		}
+		dst[i] = SoftLimit(sum)
+	}
+}
+
+// Mixer mixes several streams, each with its own gain, into one. Inputs
+// are written by producers and drained by a single reader (e.g. the
+// audio output's pacing loop); it is safe for concurrent use.
+type Mixer struct {
+	mu     sync.Mutex
+	inputs []*MixerInput
+	acc    []float32
+}
+
+// MixerInput is one stream feeding a Mixer.
+type MixerInput struct {
+	m    *Mixer
+	gain float32
+	buf  []int16
+}
+
+// NewMixer creates an empty mixer.
+func NewMixer() *Mixer {
+	return &Mixer{}
+}
+
+// Add creates an input with the given linear gain.
+func (m *Mixer) Add(gain float64) *MixerInput {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	in := &MixerInput{m: m, gain: float32(gain)}
+	m.inputs = append(m.inputs, in)
+	return in
+}
+
+// Remove detaches an input, dropping its buffered audio.
+func (m *Mixer) Remove(in *MixerInput) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, x := range m.inputs {
+		if x == in {
+			m.inputs = append(m.inputs[:i], m.inputs[i+1:]...)
+			return
+		}
+	}
+}
+
+// Read fills dst with the mix of whatever the inputs have buffered,
+// padding with silence. It reports whether any input had audio.
+func (m *Mixer) Read(dst []int16) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if cap(m.acc) < len(dst) {
+		m.acc = make([]float32, len(dst))
+	}
+	acc := m.acc[:len(dst)]
+	clear(acc)
+
+	active := false
+	for _, in := range m.inputs {
+		n := min(len(in.buf), len(dst))
+		if n == 0 {
+			continue
+		}
+		active = true
+		for i, s := range in.buf[:n] {
+			acc[i] += float32(s) * in.gain
+		}
+		rest := copy(in.buf, in.buf[n:])
+		in.buf = in.buf[:rest]
+	}
+	for i, v := range acc {
+		dst[i] = SoftLimit(v)
+	}
+	return active
+}
+
+// Write queues samples on the input.
+func (in *MixerInput) Write(samples []int16) {
+	in.m.mu.Lock()
+	defer in.m.mu.Unlock()
+	in.buf = append(in.buf, samples...)
+}
+
+// SetGain sets the input's linear gain.
+func (in *MixerInput) SetGain(gain float64) {
+	in.m.mu.Lock()
+	defer in.m.mu.Unlock()
+	in.gain = float32(gain)
+}
+
+// Gain returns the input's linear gain.
+func (in *MixerInput) Gain() float64 {
+	in.m.mu.Lock()
+	defer in.m.mu.Unlock()
+	return float64(in.gain)
+}
+
+// Buffered returns how many samples are queued.
+func (in *MixerInput) Buffered() int {
+	in.m.mu.Lock()
+	defer in.m.mu.Unlock()
+	return len(in.buf)
+}
+
+// Clear drops the queued samples.
+func (in *MixerInput) Clear() {
+	in.m.mu.Lock()
+	defer in.m.mu.Unlock()
+	in.buf = in.buf[:0]
+}
@@ -0,0 +1,84 @@
+package dsp
+
+import "testing"
+
+func TestMix(t *testing.T) {
+	dst := make([]int16, 4)
+	Mix(dst, []int16{100, 200, 300, 400}, []int16{-50, 50})
+	want := []int16{50, 250, 300, 400}
+	for i := range want {
+		if dst[i] != want[i] {
+			t.Errorf("dst[%d] = %d, want %d", i, dst[i], want[i])
+		}
+	}
+}
+
+func TestMix_SoftLimit(t *testing.T) {
+	dst := make([]int16, 3)
+	Mix(dst, []int16{30000, -30000, 20000}, []int16{30000, -30000, 6000})
+	if dst[0] <= limitKnee || dst[0] == 32767 {
+		t.Errorf("dst[0] = %d, want compressed between knee and full scale", dst[0])
+	}
+	if dst[1] != -dst[0] {
+		t.Errorf("dst[1] = %d, want %d", dst[1], -dst[0])
+	}
+	if dst[2] <= limitKnee {
+		t.Errorf("dst[2] = %d, want above knee", dst[2])
+	}
+
+	// Louder in stays louder out
+	a, b := make([]int16, 1), make([]int16, 1)
+	Mix(a, []int16{28000})
+	Mix(b, []int16{32000}, []int16{32000})
+	if !(a[0] < b[0]) {
+		t.Errorf("limiter not monotonic: %d >= %d", a[0], b[0])
+	}
+}
+
+func TestMixer(t *testing.T) {
+	m := NewMixer()
+	speech := m.Add(1)
+	music := m.Add(0.5)
+
+	out := make([]int16, 4)
+	if m.Read(out) {
+		t.Error("Read reported audio with nothing queued")
+	}
+
+	speech.Write([]int16{1000, 1000, 1000})
+	music.Write([]int16{400, 400, 400, 400, 400, 400})
+	if !m.Read(out) {
+		t.Error("Read reported silence")
+	}
+	want := []int16{1200, 1200, 1200, 200}
+	for i := range want {
+		if out[i] != want[i] {
+			t.Errorf("out[%d] = %d, want %d", i, out[i], want[i])
+		}
+	}
+	if speech.Buffered() != 0 || music.Buffered() != 2 {
+		t.Errorf("Buffered() = %d, %d, want 0, 2", speech.Buffered(), music.Buffered())
+	}
+
+	music.SetGain(0.25)
+	if got := music.Gain(); got != 0.25 {
+		t.Errorf("Gain() = %v, want 0.25", got)
+	}
+	m.Read(out[:1])
+	if out[0] != 100 {
+		t.Errorf("out[0] = %d after SetGain, want 100", out[0])
+	}
+
+	m.Remove(music)
+	speech.Write([]int16{7})
+	m.Read(out)
+	if out[0] != 7 || out[1] != 0 {
+		t.Errorf("after Remove out = %v, want [7 0 ...]", out)
+	}
+
+	speech.Write([]int16{1, 2, 3})
+	speech.Clear()
+	if m.Read(out) {
+		t.Error("Read reported audio after Clear")
+	}
+}
@@ -0,0 +1,292 @@
+// Package dsp provides audio signal processing for PCM16 mono streams:
+// band-limited resampling, high-pass filtering, gain and automatic gain
+// control, a noise gate, and mixing.
+//
+// Everything runs in float32 on plain slices so the inner loops
+// vectorize and stay cache-friendly on the robot's ARM cores; nothing
+// allocates per sample.
+package dsp
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Quality trades resampler filter length for CPU.
+type Quality int
+
+const (
+	// QualityDefault has ~80dB of alias rejection; 32 taps at the lower
+	// rate.
+	QualityDefault Quality = iota
+	// QualityFast has ~60dB of alias rejection; 16 taps.
+	QualityFast
+	// QualityHigh has ~100dB of alias rejection; 64 taps.
+	QualityHigh
+)
+
+// params returns the zero crossings on each side of the sinc and the
+// Kaiser window beta.
+func (q Quality) params() (zeroCrossings int, beta float64) {
+	switch q {
+	case QualityFast:
+		return 8, 6
+	case QualityHigh:
+		return 32, 10
+	default:
+		return 16, 8
+	}
+}
+
+// maxPhases bounds the polyphase table; it covers every ratio between
+// the usual 8k/16k/22.05k/24k/32k/44.1k/48k rates.
+const maxPhases = 1024
+
+// Resampler converts a continuous stream between two sample rates with a
+// polyphase windowed-sinc filter, so content above the lower rate's
+// Nyquist frequency is removed instead of aliasing. It keeps state
+// between calls: blocks can be any size and boundaries are seamless.
+//
+// The output is delayed by Delay (up to a millisecond at the default
+// quality); the one-shot Resample compensates for it.
+type Resampler struct {
+	inRate  int
+	outRate int
+	up      int // L: interpolation factor
+	down    int // M: decimation factor
+	taps    int // K: taps per phase
+
+	// phases[p] holds the taps for phase p, reversed so each output is
+	// a dot product with a contiguous run of input
+	phases [][]float32
+
+	hist  []float32 // Input; hist[0] is taps-1 samples before the next output's newest input
+	rel   int       // Next output position in upsampled samples, relative to hist[0]
+	start int       // Initial rel
+	delay int       // Filter delay in upsampled samples
+
+	nIn, nOut int64 // Samples consumed and produced since Reset
+}
+
+// NewResampler creates a resampler from inRate to outRate.
+func NewResampler(inRate, outRate int, q Quality) (*Resampler, error) {
+	if inRate <= 0 || outRate <= 0 {
+		return nil, fmt.Errorf("dsp: invalid sample rates %d -> %d", inRate, outRate)
+	}
+	g := gcd(inRate, outRate)
+	up, down := outRate/g, inRate/g
+	if up > maxPhases {
+		return nil, fmt.Errorf("dsp: resampling %d -> %d needs %d filter phases (max %d)", inRate, outRate, up, maxPhases)
+	}
+
+	zc, beta := q.params()
+	r := &Resampler{inRate: inRate, outRate: outRate, up: up, down: down}
+
+	// Prototype low-pass at the upsampled rate, cut off just below the
+	// lower rate's Nyquist frequency so the transition band ends there
+	span := max(up, down) // Upsampled samples per zero crossing
+	n := 2*zc*span + 1
+	r.delay = zc * span
+	atten := beta/0.1102 + 8.7
+	transition := (atten - 8) / (2.285 * 2 * math.Pi * float64(n-1)) // Fraction of the upsampled rate
+	cutoff := max(0.5/float64(span)-transition/2, 0.25/float64(span))
+
+	proto := make([]float64, n)
+	var sum float64
+	for j := range proto {
+		x := float64(j - r.delay)
+		proto[j] = 2 * cutoff * sinc(2*cutoff*x) * kaiser(float64(j)/float64(n-1), beta)
+		sum += proto[j]
+	}
+
+	r.taps = (n + up - 1) / up
+	r.phases = make([][]float32, up)
+	for p := range r.phases {
+		ph := make([]float32, r.taps)
+		for k := range r.taps {
+			if j := p + k*up; j < n {
+				ph[r.taps-1-k] = float32(proto[j] * float64(up) / sum)
+			}
+		}
+		r.phases[p] = ph
+	}
+	r.Reset()
+	return r, nil
+}
+
+// InRate returns the input sample rate.
+func (r *Resampler) InRate() int { return r.inRate }
+
+// OutRate returns the output sample rate.
+func (r *Resampler) OutRate() int { return r.outRate }
+
+// Delay returns how far the output lags the input.
+func (r *Resampler) Delay() time.Duration {
+	return time.Duration(r.delay) * time.Second / time.Duration(r.up*r.inRate)
+}
+
+// Reset clears the filter history, e.g. after a gap in the stream.
+func (r *Resampler) Reset() {
+	r.hist = append(r.hist[:0], make([]float32, r.taps-1)...)
+	r.rel = r.start + (r.taps-1)*r.up
+	r.nIn, r.nOut = 0, 0
+}
+
+// Pending returns how many output samples are held back waiting for
+// more input; Flush would emit them.
+func (r *Resampler) Pending() int {
+	return max(0, int(r.nIn*int64(r.up)/int64(r.down)-r.nOut))
+}
+
+// Flush ends the stream: it appends the output still owed, padding the
+// input with silence, so the total since Reset is exactly the input's
+// length at the output rate. The resampler is then Reset.
+func (r *Resampler) Flush(dst []int16) []int16 {
+	if owed := r.Pending(); owed > 0 {
+		n := len(dst)
+		dst = r.Append(dst, make([]int16, r.delay/r.up+r.taps))
+		dst = dst[:n+owed]
+	}
+	r.Reset()
+	return dst
+}
+
+// Process resamples a block of PCM16 samples.
+func (r *Resampler) Process(in []int16) []int16 {
+	return r.Append(nil, in)
+}
+
+// Append resamples in and appends the output to dst. Reusing dst
+// avoids allocating per block.
+func (r *Resampler) Append(dst []int16, in []int16) []int16 {
+	for _, s := range in {
+		r.hist = append(r.hist, float32(s))
+	}
+	r.nIn += int64(len(in))
+	r.run(func(v float32) {
+		dst = append(dst, toInt16(v))
+	})
+	return dst
+}
+
+// ProcessFloat32 resamples a block of float samples.
+func (r *Resampler) ProcessFloat32(in []float32) []float32 {
+	r.hist = append(r.hist, in...)
+	r.nIn += int64(len(in))
+	out := make([]float32, 0, len(in)*r.up/r.down+1)
+	r.run(func(v float32) {
+		out = append(out, v)
+	})
+	return out
+}
+
+// run emits every output whose input is available, then drops history
+// no future output needs.
+func (r *Resampler) run(emit func(float32)) {
+	for {
+		newest := r.rel / r.up
+		if newest >= len(r.hist) {
+			break
+		}
+		first := newest - (r.taps - 1)
+		emit(dot(r.phases[r.rel%r.up], r.hist[first:newest+1]))
+		r.rel += r.down
+		r.nOut++
+	}
+	if drop := r.rel/r.up - (r.taps - 1); drop > 0 {
+		drop = min(drop, len(r.hist))
+		n := copy(r.hist, r.hist[drop:])
+		r.hist = r.hist[:n]
+		r.rel -= drop * r.up
+	}
+}
+
+// Resample converts a whole clip from inRate to outRate at the default
+// quality. The output is time-aligned with the input (no filter delay)
+// and has len(samples)*outRate/inRate samples.
+func Resample(samples []int16, inRate, outRate int) ([]int16, error) {
+	if inRate == outRate {
+		return samples, nil
+	}
+	r, err := NewResampler(inRate, outRate, QualityDefault)
+	if err != nil {
+		return nil, err
+	}
+	// Start the output clock a filter delay in, so output n is centered
+	// on input time n/outRate, then flush with silence
+	r.start = r.delay
+	r.Reset()
+
+	want := int(int64(len(samples)) * int64(outRate) / int64(inRate))
+	out := r.Append(make([]int16, 0, want), samples)
+	return r.Flush(out), nil
+}
+
+// dot is the filter inner loop. Four accumulators break the dependency
+// chain so it pipelines (and auto-vectorizes where the compiler can) on
+// ARM64 as well as amd64; the re-slice lets the compiler drop bounds
+// checks.
+func dot(taps, x []float32) float32 {
+	x = x[:len(taps)]
+	var s0, s1, s2, s3 float32
+	i := 0
+	for ; i+4 <= len(taps); i += 4 {
+		s0 += taps[i] * x[i]
+		s1 += taps[i+1] * x[i+1]
+		s2 += taps[i+2] * x[i+2]
+		s3 += taps[i+3] * x[i+3]
+	}
+	for ; i < len(taps); i++ {
+		s0 += taps[i] * x[i]
+	}
+	return (s0 + s1) + (s2 + s3)
+}
+
+func sinc(x float64) float64 {
+	if x == 0 {
+		return 1
+	}
+	return math.Sin(math.Pi*x) / (math.Pi * x)
+}
+
+// kaiser evaluates the Kaiser window at t in [0, 1].
+func kaiser(t, beta float64) float64 {
+	x := 2*t - 1
+	return besselI0(beta*math.Sqrt(max(0, 1-x*x))) / besselI0(beta)
+}
+
+// besselI0 is the zeroth-order modified Bessel function of the first
+// kind, by its power series.
+func besselI0(x float64) float64 {
+	sum, term := 1.0, 1.0
+	for k := 1; k < 50; k++ {
+		term *= (x / (2 * float64(k))) * (x / (2 * float64(k)))
+		sum += term
+		if term < sum*1e-12 {
+			break
+		}
+	}
+	return sum
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// toInt16 rounds and saturates a sample.
+func toInt16(v float32) int16 {
+	switch {
+	case v >= 32767:
+		return 32767
+	case v <= -32768:
+		return -32768
+	case v >= 0:
+		return int16(v + 0.5)
+	default:
+		return int16(v - 0.5)
+	}
+}
@@ -0,0 +1,124 @@
+package audio
+
+import (
+	"time"
+
+	"github.com/teslashibe/go-reachy/pkg/audio/dsp"
+)
+
+// MicStage resamples a continuous microphone stream to a consumer's rate
+// and re-chunks it into fixed-size frames. Unlike Resample it keeps
+// filter state between calls, so frame boundaries are seamless.
+type MicStage struct {
+	inRate  int
+	outRate int
+	chunk   int
+
+	rs *dsp.Resampler // nil when the rates match or the filter can't do them
+
+	// Linear interpolation fallback for rate pairs the filter rejects
+	step float64 // Input samples per output sample
+	pos  float64 // Next output position, relative to prev
+	prev int16   // Last input sample of the previous call
+	have bool    // prev is valid
+
+	buf []int16
+}
+
+// NewMicStage creates a stage converting inRate audio to outRate chunks
+// of the given duration.
+func NewMicStage(inRate, outRate int, chunk time.Duration) *MicStage {
+	n := int(int64(outRate) * int64(chunk) / int64(time.Second))
+	if n < 1 {
+		n = 1
+	}
+	m := &MicStage{
+		inRate:  inRate,
+		outRate: outRate,
+		chunk:   n,
+		step:    float64(inRate) / float64(outRate),
+	}
+	if inRate != outRate {
+		m.rs, _ = dsp.NewResampler(inRate, outRate, dsp.QualityDefault)
+	}
+	return m
+}
+
+// ChunkSamples returns the number of samples in each output chunk.
+func (m *MicStage) ChunkSamples() int {
+	return m.chunk
+}
+
+// Delay returns the latency the resampling filter adds.
+func (m *MicStage) Delay() time.Duration {
+	if m.rs == nil {
+		return 0
+	}
+	return m.rs.Delay()
+}
+
+// Process consumes input samples and returns any complete chunks.
+func (m *MicStage) Process(in []int16) [][]int16 {
+	if len(in) == 0 {
+		return nil
+	}
+	if m.rs != nil {
+		m.buf = m.rs.Append(m.buf, in)
+	} else {
+		m.buf = append(m.buf, m.resampleLinear(in)...)
+	}
+
+	var out [][]int16
+	for len(m.buf) >= m.chunk {
+		c := make([]int16, m.chunk)
+		copy(c, m.buf)
+		m.buf = m.buf[m.chunk:]
+		out = append(out, c)
+	}
+	return out
+}
+
+// Reset discards buffered audio, e.g. after a gap in capture.
+func (m *MicStage) Reset() {
+	m.buf = m.buf[:0]
+	if m.rs != nil {
+		m.rs.Reset()
+	}
+	m.pos = 0
+	m.have = false
+}
+
+// resampleLinear linearly interpolates in, treating prev as sample -1.
+func (m *MicStage) resampleLinear(in []int16) []int16 {
+	if m.inRate == m.outRate {
+		return in
+	}
+
+	// at returns input sample i, where -1 is the carried-over sample
+	at := func(i int) float64 {
+		if i < 0 {
+			return float64(m.prev)
+		}
+		return float64(in[i])
+	}
+
+	if !m.have {
+		// Start exactly on the first sample of the stream
+		m.prev = in[0]
+		m.have = true
+		m.pos = 1
+	}
+
+	out := make([]int16, 0, int(float64(len(in))/m.step)+1)
+	// Positions are measured from prev: 0 is prev, 1 is in[0]
+	for m.pos < float64(len(in)) {
+		i := int(m.pos)
+		frac := m.pos - float64(i)
+		v := at(i-1)*(1-frac) + at(i)*frac
+		out = append(out, int16(v))
+		m.pos += m.step
+	}
+	m.pos -= float64(len(in))
+	m.prev = in[len(in)-1]
+	return out
+}
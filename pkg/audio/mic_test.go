@@ -0,0 +1,90 @@
+package audio
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestMicStage_Chunking(t *testing.T) {
+	tests := []struct {
+		name    string
+		inRate  int
+		outRate int
+		frames  int // 20ms input frames
+		want    int // 100ms output chunks
+	}{
+		{"48k to 24k", 48000, 24000, 10, 2},
+		{"48k to 16k", 48000, 16000, 10, 2},
+		{"passthrough", 24000, 24000, 10, 2},
+		{"partial chunk held back", 48000, 24000, 9, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMicStage(tt.inRate, tt.outRate, 100*time.Millisecond)
+			frame := make([]int16, tt.inRate/50)
+
+			var chunks [][]int16
+			for i := 0; i < tt.frames; i++ {
+				chunks = append(chunks, m.Process(frame)...)
+			}
+			if len(chunks) != tt.want {
+				t.Fatalf("chunks = %d, want %d", len(chunks), tt.want)
+			}
+			for _, c := range chunks {
+				if len(c) != m.ChunkSamples() {
+					t.Errorf("chunk len = %d, want %d", len(c), m.ChunkSamples())
+				}
+			}
+		})
+	}
+}
+
+// A sine split across many small frames should come out as one smooth
+// sine, with no glitches at the frame boundaries.
+func TestMicStage_Seamless(t *testing.T) {
+	const (
+		inRate  = 48000
+		outRate = 24000
+		freq    = 440.0
+	)
+	m := NewMicStage(inRate, outRate, 10*time.Millisecond)
+
+	in := make([]int16, inRate/5)
+	for i := range in {
+		in[i] = int16(10000 * math.Sin(2*math.Pi*freq*float64(i)/inRate))
+	}
+
+	var out []int16
+	for off := 0; off < len(in); off += 317 { // Odd size to misalign chunks
+		end := min(off+317, len(in))
+		for _, c := range m.Process(in[off:end]) {
+			out = append(out, c...)
+		}
+	}
+
+	if len(out) < outRate/5-outRate/100 {
+		t.Fatalf("got %d samples, want ~%d", len(out), outRate/5)
+	}
+	delay := m.Delay().Seconds()
+	if delay <= 0 || delay > 0.001 {
+		t.Errorf("Delay() = %v, want under 1ms", m.Delay())
+	}
+	// Skip the filter's start-up, before it has a full window of input
+	for i := int(2*delay*outRate) + 1; i < len(out); i++ {
+		v := out[i]
+		want := 10000 * math.Sin(2*math.Pi*freq*(float64(i)/outRate-delay))
+		if math.Abs(float64(v)-want) > 20 {
+			t.Fatalf("sample %d = %d, want ~%.0f", i, v, want)
+		}
+	}
+}
+
+func TestMicStage_Reset(t *testing.T) {
+	m := NewMicStage(48000, 24000, 100*time.Millisecond)
+	m.Process(make([]int16, 4000))
+	m.Reset()
+	if c := m.Process(make([]int16, 960)); len(c) != 0 {
+		t.Errorf("Process after Reset returned %d chunks, want 0", len(c))
+	}
+}
@@ -0,0 +1,676 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pion/rtp"
+	"gopkg.in/hraban/opus.v2"
+
+	"github.com/teslashibe/go-reachy/pkg/audio/dsp"
+)
+
+const (
+	outputSampleRate = 48000 // Opus/RTP clock rate
+	maxOpusPacket    = 1275  // Largest Opus frame (RFC 6716)
+	maxPacerLag      = 3     // Frames behind schedule before the pacer resyncs
+)
+
+// ErrOutputClosed is returned when writing to a closed Output.
+var ErrOutputClosed = errors.New("audio: output closed")
+
+// FrameEncoder encodes one frame of 48kHz mono PCM. *opus.Encoder
+// satisfies it.
+type FrameEncoder interface {
+	Encode(pcm []int16, data []byte) (int, error)
+}
+
+// OutputConfig configures an Output.
+type OutputConfig struct {
+	// InputRate is the sample rate of PCM passed to Write.
+	InputRate int
+
+	// FrameDuration is the Opus frame (and RTP packet) length.
+	FrameDuration time.Duration
+
+	// PayloadType is the RTP payload type the receiver expects.
+	PayloadType uint8
+
+	// SSRC identifies the stream; 0 picks a random one.
+	SSRC uint32
+
+	// Bitrate is the Opus bitrate in bits/s; 0 keeps the encoder default.
+	Bitrate int
+
+	// Prebuffer is how much audio is queued before a talkspurt starts,
+	// absorbing jitter in how fast PCM arrives (e.g. from streaming TTS).
+	Prebuffer time.Duration
+
+	// Latency is the receiver's playout delay, subtracted from Position.
+	Latency time.Duration
+
+	// Channels lays out the mixer; nil uses DefaultChannels. A speech
+	// channel is always added, since Write plays on it.
+	Channels map[Channel]ChannelConfig
+
+	// DuckDB is the gain applied to lower-priority channels while a
+	// higher one plays; 0 disables ducking.
+	DuckDB float64
+
+	// DuckAttack and DuckRelease are how fast ducking engages and lets
+	// go.
+	DuckAttack  time.Duration
+	DuckRelease time.Duration
+
+	// NewEncoder creates the frame encoder; nil uses libopus.
+	NewEncoder func(sampleRate, channels int) (FrameEncoder, error)
+}
+
+// DefaultOutputConfig matches the robot's receiver: 20ms Opus frames
+// with payload type 96, fed by 24kHz PCM.
+func DefaultOutputConfig() OutputConfig {
+	return OutputConfig{
+		InputRate:     24000,
+		FrameDuration: 20 * time.Millisecond,
+		PayloadType:   96,
+		Bitrate:       32000,
+		Prebuffer:     60 * time.Millisecond,
+		Channels:      DefaultChannels(),
+		DuckDB:        -15,
+		DuckAttack:    50 * time.Millisecond,
+		DuckRelease:   500 * time.Millisecond,
+	}
+}
+
+// OutputStats counts what the pacer has done.
+type OutputStats struct {
+	Frames    uint64 // Frames sent
+	Underruns uint64 // Utterances that ran dry and had to re-buffer
+	Errors    uint64 // Encode or sink failures
+}
+
+// Output encodes PCM to Opus, packetises it as RTP and paces the packets
+// to an AudioSink in real time. It replaces the sshpass + gst-launch
+// pipeline: there is no process startup and the playback position is
+// known to within a frame.
+//
+// It mixes several channels (speech, alerts, effects, music), each with
+// its own volume and queue of utterances; see Channel. Write, Flush and
+// the other utterance methods act on ChannelSpeech. An utterance starts
+// with the first Write and ends when a Flush has drained or on Cancel.
+type Output struct {
+	sink         AudioSink
+	cfg          OutputConfig
+	enc          FrameEncoder
+	frameSamples int
+	prebuffer    int
+
+	// Ducking gain and per-frame smoothing coefficients
+	duckGain    float32
+	duckAttack  float32
+	duckRelease float32
+
+	mu       sync.Mutex
+	tracks   []*track // By priority, highest first
+	speech   *OutputChannel
+	channels map[Channel]*OutputChannel
+	owner    *track // Exclusive channel whose turn it is
+	acc      []float32
+	playing  bool
+	lastSend time.Time
+	pausedAt time.Time
+	stats    OutputStats
+
+	// RTP state, advanced by the pacer
+	seq    uint16
+	ts     uint32
+	marker bool
+
+	wake   chan struct{}
+	stop   chan struct{}
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewOutput creates an Output writing to sink and starts its pacer.
+func NewOutput(sink AudioSink, cfg OutputConfig) (*Output, error) {
+	def := DefaultOutputConfig()
+	if cfg.InputRate <= 0 {
+		cfg.InputRate = def.InputRate
+	}
+	if cfg.FrameDuration <= 0 {
+		cfg.FrameDuration = def.FrameDuration
+	}
+	if cfg.SSRC == 0 {
+		cfg.SSRC = rand.Uint32()
+	}
+
+	var enc FrameEncoder
+	var err error
+	if cfg.NewEncoder != nil {
+		enc, err = cfg.NewEncoder(outputSampleRate, 1)
+	} else {
+		enc, err = newOpusEncoder(outputSampleRate, 1, cfg.Bitrate)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("create encoder: %w", err)
+	}
+
+	if cfg.DuckAttack <= 0 {
+		cfg.DuckAttack = def.DuckAttack
+	}
+	if cfg.DuckRelease <= 0 {
+		cfg.DuckRelease = def.DuckRelease
+	}
+	channels := make(map[Channel]ChannelConfig, len(def.Channels))
+	if cfg.Channels == nil {
+		cfg.Channels = def.Channels
+	}
+	for name, cc := range cfg.Channels {
+		channels[name] = cc
+	}
+	if _, ok := channels[ChannelSpeech]; !ok {
+		channels[ChannelSpeech] = def.Channels[ChannelSpeech]
+	}
+	cfg.Channels = channels
+
+	// Per-frame smoothing toward the ducking target
+	coef := func(tau time.Duration) float32 {
+		return float32(1 - math.Exp(-float64(cfg.FrameDuration)/float64(tau)))
+	}
+
+	o := &Output{
+		sink:         sink,
+		cfg:          cfg,
+		enc:          enc,
+		frameSamples: int(int64(outputSampleRate) * int64(cfg.FrameDuration) / int64(time.Second)),
+		prebuffer:    int(int64(outputSampleRate) * int64(cfg.Prebuffer) / int64(time.Second)),
+		duckGain:     float32(dsp.DBToGain(min(cfg.DuckDB, 0))),
+		duckAttack:   coef(cfg.DuckAttack),
+		duckRelease:  coef(cfg.DuckRelease),
+		channels:     make(map[Channel]*OutputChannel, len(channels)),
+		seq:          uint16(rand.Uint32()),
+		ts:           rand.Uint32(),
+		wake:         make(chan struct{}, 1),
+		stop:         make(chan struct{}),
+	}
+	o.acc = make([]float32, o.frameSamples)
+	for name, cc := range channels {
+		t := &track{name: name, cfg: cc, volume: float32(max(cc.Volume, 0)), duck: 1}
+		if cfg.InputRate != outputSampleRate {
+			if t.rs, err = dsp.NewResampler(cfg.InputRate, outputSampleRate, dsp.QualityDefault); err != nil {
+				return nil, fmt.Errorf("create resampler: %w", err)
+			}
+		}
+		o.tracks = append(o.tracks, t)
+		o.channels[name] = &OutputChannel{o: o, t: t}
+	}
+	sort.SliceStable(o.tracks, func(i, j int) bool {
+		if o.tracks[i].cfg.Priority != o.tracks[j].cfg.Priority {
+			return o.tracks[i].cfg.Priority > o.tracks[j].cfg.Priority
+		}
+		return o.tracks[i].name < o.tracks[j].name
+	})
+	o.speech = o.channels[ChannelSpeech]
+
+	o.wg.Add(1)
+	go o.run()
+	return o, nil
+}
+
+// newOpusEncoder creates a libopus encoder tuned for speech.
+func newOpusEncoder(sampleRate, channels, bitrate int) (FrameEncoder, error) {
+	enc, err := opus.NewEncoder(sampleRate, channels, opus.AppVoIP)
+	if err != nil {
+		return nil, err
+	}
+	if bitrate > 0 {
+		if err := enc.SetBitrate(bitrate); err != nil {
+			return nil, err
+		}
+	}
+	return enc, nil
+}
+
+// Write queues PCM16 mono audio at InputRate on the speech channel.
+func (o *Output) Write(pcm []byte) error {
+	return o.write(o.speech.t, pcm)
+}
+
+// Flush marks the end of the utterance; queued audio still plays.
+func (o *Output) Flush() {
+	o.flush(o.speech.t)
+}
+
+// Wait blocks until the current utterance has finished playing or been
+// cancelled.
+func (o *Output) Wait(ctx context.Context) error {
+	return o.wait(o.speech.t, ctx)
+}
+
+// Cancel drops queued speech and ends the utterance immediately. Other
+// channels keep playing.
+func (o *Output) Cancel() {
+	o.cancel(o.speech.t)
+}
+
+// Playing reports whether an utterance is in progress.
+func (o *Output) Playing() bool {
+	return o.speech.Playing()
+}
+
+// Position returns how much of the current utterance the listener has
+// heard, interpolated within the frame being played.
+func (o *Output) Position() time.Duration {
+	return o.position(o.speech.t)
+}
+
+// Buffered returns how much speech is queued but not yet sent.
+func (o *Output) Buffered() time.Duration {
+	return o.buffered(o.speech.t)
+}
+
+// Channel returns the named mixer channel, or nil if it isn't
+// configured.
+func (o *Output) Channel(name Channel) *OutputChannel {
+	return o.channels[name]
+}
+
+// Stats returns pacer counters.
+func (o *Output) Stats() OutputStats {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.stats
+}
+
+// Close stops the pacer and closes the sink.
+func (o *Output) Close() error {
+	o.mu.Lock()
+	if o.closed {
+		o.mu.Unlock()
+		return nil
+	}
+	o.closed = true
+	close(o.stop)
+	o.mu.Unlock()
+
+	o.wg.Wait()
+
+	o.mu.Lock()
+	for _, t := range o.tracks {
+		o.dropLocked(t)
+	}
+	o.mu.Unlock()
+
+	return o.sink.Close()
+}
+
+// write resamples pcm onto t's open utterance.
+func (o *Output) write(t *track, pcm []byte) error {
+	samples := ConvertPCM16ToInt16(pcm)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.closed {
+		return ErrOutputClosed
+	}
+	seg := t.openLocked()
+	if t.rs != nil {
+		seg.samples = t.rs.Append(seg.samples, samples)
+	} else {
+		seg.samples = append(seg.samples, samples...)
+	}
+	o.signal()
+	return nil
+}
+
+// flush ends t's open utterance, draining the resampler into it.
+func (o *Output) flush(t *track) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	seg := t.unflushedLocked()
+	if seg == nil {
+		return
+	}
+	if t.rs != nil {
+		seg.samples = t.rs.Flush(seg.samples)
+	}
+	seg.flushed = true
+	o.signal()
+}
+
+// enqueue queues pcm as a complete utterance on t.
+func (o *Output) enqueue(t *track, pcm []byte) (<-chan struct{}, error) {
+	samples := Resample(ConvertPCM16ToInt16(pcm), o.cfg.InputRate, outputSampleRate)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.closed {
+		return nil, ErrOutputClosed
+	}
+	seg := &segment{samples: samples, flushed: true, done: make(chan struct{})}
+	t.segs = append(t.segs, seg)
+	o.signal()
+	return seg.done, nil
+}
+
+// wait blocks until t's last queued utterance is done.
+func (o *Output) wait(t *track, ctx context.Context) error {
+	o.mu.Lock()
+	var done chan struct{}
+	if n := len(t.segs); n > 0 {
+		done = t.segs[n-1].done
+	}
+	o.mu.Unlock()
+
+	if done == nil {
+		return nil
+	}
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// cancel drops everything queued on t.
+func (o *Output) cancel(t *track) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.dropLocked(t)
+	o.signal() // A channel waiting for its turn may start
+}
+
+// position returns how much of t's current utterance has been heard.
+func (o *Output) position(t *track) time.Duration {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if len(t.segs) == 0 || t.played == 0 {
+		return 0
+	}
+	into := time.Since(o.lastSend)
+	if into > o.cfg.FrameDuration || !t.sounding {
+		into = o.cfg.FrameDuration
+	}
+	pos := t.played - o.cfg.FrameDuration + into - o.cfg.Latency
+	if pos < 0 {
+		return 0
+	}
+	return pos
+}
+
+// buffered returns how much audio t has queued.
+func (o *Output) buffered(t *track) time.Duration {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	n := 0
+	for _, seg := range t.segs {
+		n += len(seg.samples)
+	}
+	return time.Duration(n) * time.Second / outputSampleRate
+}
+
+// signal wakes the pacer. Caller holds mu.
+func (o *Output) signal() {
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+}
+
+// dropLocked ends all of t's utterances. Caller holds mu.
+func (o *Output) dropLocked(t *track) {
+	for _, seg := range t.segs {
+		close(seg.done)
+	}
+	t.segs = nil
+	t.started = false
+	t.played = 0
+	t.sounding = false
+	if t.rs != nil {
+		t.rs.Reset()
+	}
+	if o.owner == t {
+		o.owner = nil
+	}
+}
+
+// run paces frames to the sink on an absolute schedule, so timing errors
+// don't accumulate.
+func (o *Output) run() {
+	defer o.wg.Done()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	<-timer.C
+
+	buf := make([]byte, maxOpusPacket)
+	var next time.Time
+
+	for {
+		o.mu.Lock()
+		if !o.playing {
+			o.arbitrateLocked()
+			if !o.startedLocked() {
+				o.mu.Unlock()
+				select {
+				case <-o.wake:
+					continue
+				case <-o.stop:
+					return
+				}
+			}
+			o.startTalkspurtLocked()
+			next = time.Now()
+		}
+		o.mu.Unlock()
+
+		if d := time.Until(next); d > 0 {
+			timer.Reset(d)
+			select {
+			case <-timer.C:
+			case <-o.stop:
+				return
+			}
+		}
+
+		o.mu.Lock()
+		pcm, ok := o.mixFrameLocked()
+		if !ok {
+			// Every channel finished or ran dry: pause until there's more
+			o.playing = false
+			o.pausedAt = time.Now()
+			o.mu.Unlock()
+			continue
+		}
+
+		pkt := &rtp.Packet{
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         o.marker,
+				PayloadType:    o.cfg.PayloadType,
+				SequenceNumber: o.seq,
+				Timestamp:      o.ts,
+				SSRC:           o.cfg.SSRC,
+			},
+		}
+		o.seq++
+		o.ts += uint32(o.frameSamples)
+		o.marker = false
+		o.lastSend = time.Now()
+		o.mu.Unlock()
+
+		err := o.sendFrame(pkt, pcm, buf)
+
+		o.mu.Lock()
+		o.stats.Frames++
+		if err != nil {
+			o.stats.Errors++
+		}
+		o.mu.Unlock()
+
+		next = next.Add(o.cfg.FrameDuration)
+		if time.Since(next) > maxPacerLag*o.cfg.FrameDuration {
+			next = time.Now() // Stalled (GC, suspended laptop): resync rather than burst
+		}
+	}
+}
+
+// arbitrateLocked retires finished utterances and starts channels whose
+// next utterance is ready. Exclusive channels take turns, in priority
+// order when several are waiting. Caller holds mu.
+func (o *Output) arbitrateLocked() {
+	for _, t := range o.tracks {
+		for len(t.segs) > 0 && t.segs[0].flushed && len(t.segs[0].samples) == 0 {
+			t.finishHeadLocked()
+			if o.owner == t {
+				o.owner = nil
+			}
+		}
+	}
+	for _, t := range o.tracks {
+		if t.started || len(t.segs) == 0 || o.heldLocked(t) || !o.readyLocked(t) {
+			continue
+		}
+		t.started = true
+		if t.cfg.Exclusive {
+			o.owner = t
+		}
+	}
+}
+
+// readyLocked reports whether t's next utterance can start: the
+// prebuffer (and at least one frame) is queued, or it has been flushed.
+// Caller holds mu.
+func (o *Output) readyLocked(t *track) bool {
+	seg := t.segs[0]
+	if seg.flushed {
+		return true
+	}
+	return len(seg.samples)+t.pending() >= max(o.prebuffer, o.frameSamples)
+}
+
+// heldLocked reports whether t is waiting for another exclusive
+// channel's turn to end. Caller holds mu.
+func (o *Output) heldLocked(t *track) bool {
+	return t.cfg.Exclusive && o.owner != nil && o.owner != t
+}
+
+// duckedLocked reports whether a higher-priority channel is playing (or
+// about to). Caller holds mu.
+func (o *Output) duckedLocked(t *track) bool {
+	for _, u := range o.tracks {
+		if u.cfg.Priority > t.cfg.Priority && len(u.segs) > 0 && !o.heldLocked(u) {
+			return true
+		}
+	}
+	return false
+}
+
+// startedLocked reports whether any channel has audio to send. Caller
+// holds mu.
+func (o *Output) startedLocked() bool {
+	for _, t := range o.tracks {
+		if t.started {
+			return true
+		}
+	}
+	return false
+}
+
+// startTalkspurtLocked begins pacing. The RTP timestamp jumps by the
+// silence since the last talkspurt so the receiver schedules playout
+// correctly, and the marker bit flags the start. Caller holds mu.
+func (o *Output) startTalkspurtLocked() {
+	o.playing = true
+	o.marker = true
+	if !o.pausedAt.IsZero() {
+		gap := time.Since(o.pausedAt)
+		frames := int64(gap / o.cfg.FrameDuration)
+		o.ts += uint32(frames * int64(o.frameSamples))
+	}
+}
+
+// mixFrameLocked mixes one frame from every channel that is playing,
+// with its volume and a ducking gain ramped across the frame. A channel
+// that runs dry mid-utterance drops out until it has re-buffered; the
+// tail of a flushed utterance is padded with silence. It reports false
+// when no channel had audio. Caller holds mu.
+func (o *Output) mixFrameLocked() ([]int16, bool) {
+	o.arbitrateLocked()
+
+	acc := o.acc
+	clear(acc)
+	sounding := false
+	for _, t := range o.tracks {
+		target := float32(1)
+		if o.duckedLocked(t) {
+			target = o.duckGain
+		}
+		if !t.sounding {
+			t.duck = target // Nothing audible to smooth from
+		}
+		t.sounding = false
+		if !t.started {
+			continue
+		}
+		from, c := t.duck, o.duckRelease
+		if target < from {
+			c = o.duckAttack
+		}
+		t.duck = from + c*(target-from)
+		seg := t.segs[0]
+		if len(seg.samples) < o.frameSamples && !seg.flushed {
+			if len(seg.samples)+t.pending() < o.frameSamples {
+				t.started = false
+				o.stats.Underruns++
+				continue
+			}
+			// Only the resampler's look-ahead is missing: end the stream
+			// here rather than stall
+			seg.samples = t.rs.Flush(seg.samples)
+		}
+
+		n := min(len(seg.samples), o.frameSamples)
+		g0, g1 := t.volume*from, t.volume*t.duck
+		step := (g1 - g0) / float32(o.frameSamples)
+		for i, v := range seg.samples[:n] {
+			acc[i] += float32(v) * (g0 + step*float32(i+1))
+		}
+		seg.samples = seg.samples[n:]
+		t.played += o.cfg.FrameDuration
+		t.sounding = true
+		sounding = true
+	}
+	if !sounding {
+		return nil, false
+	}
+
+	pcm := make([]int16, o.frameSamples)
+	for i, v := range acc {
+		pcm[i] = dsp.SoftLimit(v)
+	}
+	return pcm, true
+}
+
+// sendFrame encodes and delivers one frame.
+func (o *Output) sendFrame(pkt *rtp.Packet, pcm []int16, buf []byte) error {
+	n, err := o.enc.Encode(pcm, buf)
+	if err != nil {
+		return fmt.Errorf("encode: %w", err)
+	}
+	pkt.Payload = buf[:n]
+	return o.sink.WriteFrame(&OutputFrame{Packet: pkt, PCM: pcm, Time: time.Now()})
+}
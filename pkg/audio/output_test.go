@@ -0,0 +1,251 @@
+package audio
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+)
+
+// fakeEncoder emits a fixed 3-byte payload per frame.
+type fakeEncoder struct{}
+
+func (fakeEncoder) Encode(pcm []int16, data []byte) (int, error) {
+	return copy(data, []byte{0xf8, 0xff, 0xfe}), nil
+}
+
+func newTestOutput(t *testing.T, prebuffer time.Duration) (*Output, *LoopbackSink) {
+	t.Helper()
+	sink := NewLoopbackSink()
+	cfg := DefaultOutputConfig()
+	cfg.SSRC = 0x1234
+	cfg.Prebuffer = prebuffer
+	cfg.NewEncoder = func(int, int) (FrameEncoder, error) { return fakeEncoder{}, nil }
+	out, err := NewOutput(sink, cfg)
+	if err != nil {
+		t.Fatalf("NewOutput: %v", err)
+	}
+	t.Cleanup(func() { out.Close() })
+	return out, sink
+}
+
+// pcm24k returns d of constant-level 24kHz PCM16.
+func pcm24k(d time.Duration) []byte {
+	samples := make([]int16, int(24000*d/time.Second))
+	for i := range samples {
+		samples[i] = 1000
+	}
+	return ConvertInt16ToPCM16(samples)
+}
+
+func waitOutput(t *testing.T, out *Output) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := out.Wait(ctx); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+}
+
+func TestOutput_Packetisation(t *testing.T) {
+	out, sink := newTestOutput(t, 0)
+
+	if err := out.Write(pcm24k(50 * time.Millisecond)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	out.Flush()
+	waitOutput(t, out)
+
+	frames := sink.Frames()
+	if len(frames) != 3 {
+		t.Fatalf("frames = %d, want 3", len(frames))
+	}
+	first := frames[0].Packet.Header
+	for i, f := range frames {
+		h := f.Packet.Header
+		if h.Version != 2 || h.PayloadType != 96 || h.SSRC != 0x1234 {
+			t.Errorf("frame %d header = %+v", i, h)
+		}
+		if want := first.SequenceNumber + uint16(i); h.SequenceNumber != want {
+			t.Errorf("frame %d seq = %d, want %d", i, h.SequenceNumber, want)
+		}
+		if want := first.Timestamp + uint32(i*960); h.Timestamp != want {
+			t.Errorf("frame %d ts = %d, want %d", i, h.Timestamp, want)
+		}
+		if h.Marker != (i == 0) {
+			t.Errorf("frame %d marker = %v, want %v", i, h.Marker, i == 0)
+		}
+		if len(f.PCM) != 960 {
+			t.Errorf("frame %d has %d samples, want 960", i, len(f.PCM))
+		}
+		if len(f.Packet.Payload) != 3 {
+			t.Errorf("frame %d payload = %d bytes, want 3", i, len(f.Packet.Payload))
+		}
+	}
+
+	// 50ms is 2.5 frames: the tail is padded with silence
+	last := frames[2].PCM
+	if last[0] == 0 || last[len(last)-1] != 0 {
+		t.Errorf("last frame not padded: first=%d last=%d", last[0], last[len(last)-1])
+	}
+	if out.Playing() {
+		t.Error("Playing() = true after Wait")
+	}
+}
+
+func TestOutput_Pacing(t *testing.T) {
+	out, sink := newTestOutput(t, 0)
+
+	start := time.Now()
+	out.Write(pcm24k(200 * time.Millisecond))
+	out.Flush()
+	waitOutput(t, out)
+	elapsed := time.Since(start)
+
+	// 10 frames, the first sent immediately
+	if elapsed < 170*time.Millisecond {
+		t.Errorf("200ms of audio sent in %v, want real-time pacing", elapsed)
+	}
+	if n := len(sink.Frames()); n != 10 {
+		t.Errorf("frames = %d, want 10", n)
+	}
+}
+
+func TestOutput_UnderrunStartsTalkspurt(t *testing.T) {
+	out, sink := newTestOutput(t, 0)
+
+	out.Write(pcm24k(20 * time.Millisecond))
+	time.Sleep(100 * time.Millisecond)
+	out.Write(pcm24k(20 * time.Millisecond))
+	out.Flush()
+	waitOutput(t, out)
+
+	frames := sink.Frames()
+	if len(frames) != 2 {
+		t.Fatalf("frames = %d, want 2", len(frames))
+	}
+	a, b := frames[0].Packet.Header, frames[1].Packet.Header
+	if !b.Marker {
+		t.Error("frame after underrun has no marker bit")
+	}
+	if b.SequenceNumber != a.SequenceNumber+1 {
+		t.Errorf("seq = %d, want %d", b.SequenceNumber, a.SequenceNumber+1)
+	}
+	if gap := b.Timestamp - a.Timestamp; gap < 3*960 {
+		t.Errorf("timestamp gap = %d, want it to cover the silence", gap)
+	}
+	if s := out.Stats(); s.Underruns != 1 || s.Frames != 2 {
+		t.Errorf("stats = %+v, want 1 underrun and 2 frames", s)
+	}
+}
+
+func TestOutput_Prebuffer(t *testing.T) {
+	out, sink := newTestOutput(t, 60*time.Millisecond)
+
+	out.Write(pcm24k(40 * time.Millisecond))
+	time.Sleep(60 * time.Millisecond)
+	if n := len(sink.Frames()); n != 0 {
+		t.Fatalf("sent %d frames before prebuffer filled", n)
+	}
+
+	// Flushing plays a short utterance without waiting for the prebuffer
+	out.Flush()
+	waitOutput(t, out)
+	if n := len(sink.Frames()); n != 2 {
+		t.Errorf("frames = %d, want 2", n)
+	}
+}
+
+func TestOutput_Cancel(t *testing.T) {
+	out, sink := newTestOutput(t, 0)
+
+	out.Write(pcm24k(time.Second))
+	time.Sleep(50 * time.Millisecond)
+	out.Cancel()
+	waitOutput(t, out)
+
+	if out.Playing() {
+		t.Error("Playing() = true after Cancel")
+	}
+	if out.Buffered() != 0 {
+		t.Errorf("Buffered() = %v after Cancel, want 0", out.Buffered())
+	}
+	n := len(sink.Frames())
+	time.Sleep(60 * time.Millisecond)
+	if m := len(sink.Frames()); m > n+1 || m > 10 {
+		t.Errorf("frames kept flowing after Cancel: %d then %d", n, m)
+	}
+}
+
+func TestOutput_Position(t *testing.T) {
+	out, _ := newTestOutput(t, 0)
+
+	if p := out.Position(); p != 0 {
+		t.Errorf("idle Position() = %v, want 0", p)
+	}
+
+	out.Write(pcm24k(500 * time.Millisecond))
+	time.Sleep(150 * time.Millisecond)
+	p := out.Position()
+	if p < 80*time.Millisecond || p > 220*time.Millisecond {
+		t.Errorf("Position() = %v after 150ms, want ~150ms", p)
+	}
+
+	out.Cancel()
+	if p := out.Position(); p != 0 {
+		t.Errorf("Position() after Cancel = %v, want 0", p)
+	}
+}
+
+func TestOutput_WriteAfterClose(t *testing.T) {
+	out, _ := newTestOutput(t, 0)
+	out.Close()
+	if err := out.Write(pcm24k(20 * time.Millisecond)); !errors.Is(err, ErrOutputClosed) {
+		t.Errorf("Write after Close = %v, want ErrOutputClosed", err)
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.wav")
+	sink, err := NewFileSink(path)
+	if err != nil {
+		t.Fatalf("NewFileSink: %v", err)
+	}
+	pcm := make([]int16, 960)
+	for i := 0; i < 3; i++ {
+		if err := sink.WriteFrame(&OutputFrame{Packet: &rtp.Packet{}, PCM: pcm}); err != nil {
+			t.Fatalf("WriteFrame: %v", err)
+		}
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dataSize := 3 * 960 * 2
+	if len(data) != 44+dataSize {
+		t.Fatalf("file size = %d, want %d", len(data), 44+dataSize)
+	}
+	tests := []struct {
+		name string
+		off  int
+		want uint32
+	}{
+		{"riff size", 4, uint32(36 + dataSize)},
+		{"sample rate", 24, 48000},
+		{"data size", 40, uint32(dataSize)},
+	}
+	for _, tt := range tests {
+		if got := binary.LittleEndian.Uint32(data[tt.off:]); got != tt.want {
+			t.Errorf("%s = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
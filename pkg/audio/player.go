@@ -2,6 +2,7 @@ package audio
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/binary"
 	"encoding/json"
@@ -11,6 +12,8 @@ import (
 	"os/exec"
 	"sync"
 	"time"
+
+	"github.com/teslashibe/go-reachy/pkg/audio/dsp"
 )
 
 // Player handles streaming audio playback to the robot.
@@ -26,6 +29,9 @@ type Player struct {
 	streamMu    sync.Mutex
 	streaming   bool
 
+	// Native output; nil uses the SSH + GStreamer pipeline
+	output *Output
+
 	// Callbacks
 	OnPlaybackStart func()
 	OnPlaybackEnd   func()
@@ -49,16 +55,93 @@ func (p *Player) SetOpenAIKey(key string) {
 	p.openaiKey = key
 }
 
+// SetOutput routes playback through a native Output instead of spawning
+// gst-launch on the robot over SSH. Pass nil to restore the SSH pipeline.
+func (p *Player) SetOutput(out *Output) {
+	p.streamMu.Lock()
+	defer p.streamMu.Unlock()
+	p.output = out
+}
+
+// PlaybackPosition returns how much of the current utterance has been
+// heard. It is only known with a native Output; otherwise it is 0.
+func (p *Player) PlaybackPosition() time.Duration {
+	p.streamMu.Lock()
+	out := p.output
+	p.streamMu.Unlock()
+	if out == nil {
+		return 0
+	}
+	return out.Position()
+}
+
+// writeNativeLocked queues PCM on the native output, starting an utterance if
+// needed (must hold streamMu).
+func (p *Player) writeNativeLocked(pcmData []byte) error {
+	if !p.streaming {
+		p.streaming = true
+		p.speakingMu.Lock()
+		p.speaking = true
+		p.speakingMu.Unlock()
+		if p.OnPlaybackStart != nil {
+			p.OnPlaybackStart()
+		}
+	}
+	return p.output.Write(pcmData)
+}
+
+// flushNative drains the native output and waits for playback to end.
+func (p *Player) flushNative() error {
+	p.streamMu.Lock()
+	if !p.streaming {
+		p.streamMu.Unlock()
+		return nil
+	}
+	out := p.output
+	out.Flush()
+	p.streamMu.Unlock()
+
+	// Don't hold streamMu while waiting so Cancel can interrupt
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := out.Wait(ctx); err != nil {
+		out.Cancel()
+	}
+
+	p.streamMu.Lock()
+	defer p.streamMu.Unlock()
+	if !p.streaming {
+		return nil // Cancelled meanwhile
+	}
+	p.streaming = false
+
+	p.speakingMu.Lock()
+	p.speaking = false
+	p.speakingMu.Unlock()
+	if p.OnPlaybackEnd != nil {
+		p.OnPlaybackEnd()
+	}
+	return nil
+}
+
 // AppendAudio streams audio data directly to the robot (base64 encoded PCM16 at 24kHz).
 func (p *Player) AppendAudio(base64Audio string) error {
 	decoded, err := base64.StdEncoding.DecodeString(base64Audio)
 	if err != nil {
 		return err
 	}
+	return p.AppendPCM(decoded)
+}
 
+// AppendPCM streams PCM16 audio at 24kHz directly to the robot.
+func (p *Player) AppendPCM(pcm []byte) error {
 	p.streamMu.Lock()
 	defer p.streamMu.Unlock()
 
+	if p.output != nil {
+		return p.writeNativeLocked(pcm)
+	}
+
 	// Start streaming pipeline if not already running
 	if !p.streaming {
 		if err := p.startStream(); err != nil {
@@ -68,7 +151,7 @@ func (p *Player) AppendAudio(base64Audio string) error {
 
 	// Write audio data directly to the pipeline
 	if p.streamStdin != nil {
-		_, err = p.streamStdin.Write(decoded)
+		_, err := p.streamStdin.Write(pcm)
 		if err != nil {
 			// Pipeline died, try to restart
 			p.stopStreamLocked()
@@ -114,6 +197,10 @@ func (p *Player) startStream() error {
 // FlushAndPlay signals end of audio stream and waits for playback to complete.
 func (p *Player) FlushAndPlay() error {
 	p.streamMu.Lock()
+	if p.output != nil {
+		p.streamMu.Unlock()
+		return p.flushNative()
+	}
 	defer p.streamMu.Unlock()
 
 	if !p.streaming {
@@ -185,7 +272,15 @@ func (p *Player) stopStreamLocked() {
 func (p *Player) Cancel() {
 	p.streamMu.Lock()
 	defer p.streamMu.Unlock()
-	p.stopStreamLocked()
+	if p.output != nil {
+		p.output.Cancel()
+		p.streaming = false
+		p.speakingMu.Lock()
+		p.speaking = false
+		p.speakingMu.Unlock()
+	} else {
+		p.stopStreamLocked()
+	}
 
 	if p.OnPlaybackEnd != nil {
 		p.OnPlaybackEnd()
@@ -221,6 +316,10 @@ func (p *Player) AppendPCMChunk(pcmData []byte) error {
 	p.streamMu.Lock()
 	defer p.streamMu.Unlock()
 
+	if p.output != nil {
+		return p.writeNativeLocked(pcmData)
+	}
+
 	// Start streaming pipeline if not already running
 	if !p.streaming {
 		if err := p.startStream(); err != nil {
@@ -248,6 +347,14 @@ func (p *Player) PlayPCM(pcmData []byte) error {
 	}
 
 	p.streamMu.Lock()
+	if p.output != nil {
+		err := p.writeNativeLocked(pcmData)
+		p.streamMu.Unlock()
+		if err != nil {
+			return err
+		}
+		return p.flushNative()
+	}
 	defer p.streamMu.Unlock()
 
 	// Trigger callbacks
@@ -258,6 +365,22 @@ func (p *Player) PlayPCM(pcmData []byte) error {
 		p.OnPlaybackStart()
 	}
 
+	err := p.playSSHLocked(pcmData)
+
+	if p.OnPlaybackEnd != nil {
+		p.speakingMu.Lock()
+		p.speaking = false
+		p.speakingMu.Unlock()
+		p.OnPlaybackEnd()
+	}
+
+	return err
+}
+
+// playSSHLocked plays a PCM16 clip at 24kHz through a one-shot
+// gst-launch pipeline on the robot and waits for it. Caller holds
+// streamMu, so clips never overlap.
+func (p *Player) playSSHLocked(pcmData []byte) error {
 	// GStreamer pipeline - same format as streaming audio
 	pipeline := `gst-launch-1.0 -q fdsrc fd=0 ! rawaudioparse format=pcm pcm-format=s16le sample-rate=24000 num-channels=1 ! audioconvert ! audioresample ! audio/x-raw,rate=48000,channels=1,layout=interleaved ! queue ! opusenc frame-size=20 ! rtpopuspay pt=96 ! udpsink host=127.0.0.1 port=5000 sync=true`
 
@@ -280,16 +403,40 @@ func (p *Player) PlayPCM(pcmData []byte) error {
 	stdin.Close()
 
 	// Wait for playback to complete
-	cmd.Wait()
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("playback: %w", err)
+	}
+	return nil
+}
 
-	if p.OnPlaybackEnd != nil {
-		p.speakingMu.Lock()
-		p.speaking = false
-		p.speakingMu.Unlock()
-		p.OnPlaybackEnd()
+// PlayOn plays a complete PCM16 clip at 24kHz on one of the native
+// output's mixer channels and waits for it. Unlike PlayPCM it doesn't
+// count as Eva speaking: clips on other channels mix with (or, for
+// alerts, queue behind) her responses. Without the native output there
+// is no mixer: the clip plays on its own SSH pipeline, at full volume.
+func (p *Player) PlayOn(ch Channel, pcmData []byte) error {
+	p.streamMu.Lock()
+	out := p.output
+	if out == nil {
+		defer p.streamMu.Unlock()
+		return p.playSSHLocked(pcmData)
+	}
+	p.streamMu.Unlock()
+	c := out.Channel(ch)
+	if c == nil {
+		return fmt.Errorf("play on %s: no such channel", ch)
 	}
+	return c.Play(context.Background(), pcmData)
+}
 
-	return nil
+// PlayWAV plays a WAV file on a mixer channel, e.g. an emotion's sound
+// on ChannelEffects.
+func (p *Player) PlayWAV(ch Channel, path string) error {
+	samples, rate, err := ReadWAV(path)
+	if err != nil {
+		return err
+	}
+	return p.PlayOn(ch, ConvertInt16ToPCM16(Resample(samples, rate, 24000)))
 }
 
 // SpeakText uses OpenAI TTS to speak text directly (for timer announcements, etc.).
@@ -302,12 +449,20 @@ func (p *Player) SpeakText(text string) error {
 	fmt.Printf("🔔 Speaking: %s\n", text)
 	fmt.Println("🔔 Calling OpenAI TTS...")
 
+	p.streamMu.Lock()
+	native := p.output != nil
+	p.streamMu.Unlock()
+
 	// Call OpenAI TTS API
 	payload := map[string]interface{}{
 		"model": "tts-1",
 		"voice": "shimmer",
 		"input": text,
 	}
+	if native {
+		// Raw 24kHz PCM16 goes straight to the native output
+		payload["response_format"] = "pcm"
+	}
 	jsonData, _ := json.Marshal(payload)
 
 	req, _ := http.NewRequest("POST", "https://api.openai.com/v1/audio/speech", bytes.NewReader(jsonData))
@@ -334,6 +489,17 @@ func (p *Player) SpeakText(text string) error {
 	}
 	fmt.Printf("🔔 Got %d bytes of audio from TTS\n", len(audioData))
 
+	if native {
+		// The alerts channel waits for a response in progress instead of
+		// talking over it
+		fmt.Println("🔔 Playing timer audio...")
+		if err := p.PlayOn(ChannelAlerts, audioData); err != nil {
+			return err
+		}
+		fmt.Println("🔔 Timer audio complete")
+		return nil
+	}
+
 	// Play via SSH and GStreamer
 	cmd := exec.Command("sshpass", "-p", p.sshPass,
 		"ssh", "-o", "StrictHostKeyChecking=no",
@@ -392,11 +558,39 @@ func ConvertInt16ToPCM16(samples []int16) []byte {
 	return data
 }
 
-// Resample resamples audio from srcRate to dstRate.
+// ConvertULawToInt16 decodes G.711 μ-law bytes to int16 samples.
+func ConvertULawToInt16(data []byte) []int16 {
+	samples := make([]int16, len(data))
+	for i, b := range data {
+		u := ^b
+		t := (int16(u&0x0F)<<3 + 0x84) << (u & 0x70 >> 4)
+		if u&0x80 != 0 {
+			samples[i] = 0x84 - t
+		} else {
+			samples[i] = t - 0x84
+		}
+	}
+	return samples
+}
+
+// Resample resamples a whole clip from srcRate to dstRate with a
+// band-limited filter (see dsp.Resample). Rates the filter can't handle
+// fall back to linear interpolation.
 func Resample(samples []int16, srcRate, dstRate int) []int16 {
 	if srcRate == dstRate {
 		return samples
 	}
+	if out, err := dsp.Resample(samples, srcRate, dstRate); err == nil {
+		return out
+	}
+	return resampleLinear(samples, srcRate, dstRate)
+}
+
+// resampleLinear linearly interpolates samples from srcRate to dstRate.
+func resampleLinear(samples []int16, srcRate, dstRate int) []int16 {
+	if srcRate <= 0 || dstRate <= 0 || len(samples) == 0 {
+		return nil
+	}
 
 	ratio := float64(dstRate) / float64(srcRate)
 	newLen := int(float64(len(samples)) * ratio)
@@ -0,0 +1,101 @@
+package audio
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// RawFormat describes go-eva's raw multichannel mic stream.
+type RawFormat struct {
+	SampleRate int `json:"sample_rate"`
+	Channels   int `json:"channels"`
+}
+
+// RawFrame is one block of raw multichannel mic audio.
+type RawFrame struct {
+	RawFormat
+
+	// Samples is interleaved PCM16, Channels samples per instant.
+	Samples []int16
+
+	// Time is when the block arrived.
+	Time time.Time
+}
+
+// Frames returns the number of sample instants in the block.
+func (f RawFrame) Frames() int {
+	if f.Channels == 0 {
+		return 0
+	}
+	return len(f.Samples) / f.Channels
+}
+
+// RawHandler is called for each raw frame received.
+type RawHandler func(frame RawFrame)
+
+// ErrRawUnsupported means the daemon has no raw multichannel stream.
+var ErrRawUnsupported = errors.New("go-eva has no raw multichannel audio stream")
+
+// StreamRaw streams the XVF3800's unprocessed mic channels from go-eva,
+// for beamforming on this side. The daemon sends a "format" message, then
+// binary messages of interleaved little-endian PCM16.
+//
+// It blocks until ctx is cancelled or the connection drops. If the daemon
+// doesn't offer the stream, it returns ErrRawUnsupported.
+func (c *Client) StreamRaw(ctx context.Context, handler RawHandler) error {
+	wsURL := strings.Replace(c.wsURL, "/doa/stream", "/raw/stream", 1)
+	dialer := websocket.Dialer{
+		HandshakeTimeout: 5 * time.Second,
+	}
+	conn, resp, err := dialer.DialContext(ctx, wsURL, nil)
+	if err != nil {
+		if resp != nil && resp.StatusCode == 404 {
+			return ErrRawUnsupported
+		}
+		return fmt.Errorf("raw audio connect failed: %w", err)
+	}
+	defer conn.Close()
+
+	// Unblock the read when the context ends
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	var format RawFormat
+	for {
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		kind, message, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("raw audio stream: %w", err)
+		}
+
+		switch kind {
+		case websocket.TextMessage:
+			var msg wsMessage
+			if err := json.Unmarshal(message, &msg); err != nil || msg.Type != "format" {
+				continue
+			}
+			if err := json.Unmarshal(msg.Data, &format); err != nil {
+				return fmt.Errorf("raw audio format: %w", err)
+			}
+			if format.SampleRate <= 0 || format.Channels <= 0 {
+				return fmt.Errorf("raw audio format: %d Hz, %d channels", format.SampleRate, format.Channels)
+			}
+		case websocket.BinaryMessage:
+			if format.Channels == 0 {
+				continue // Audio before the format message
+			}
+			samples := ConvertPCM16ToInt16(message)
+			samples = samples[:len(samples)/format.Channels*format.Channels]
+			handler(RawFrame{RawFormat: format, Samples: samples, Time: time.Now()})
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+// newRawServer serves the raw stream: a format message, then the given
+// binary blocks.
+func newRawServer(t *testing.T, format string, blocks ...[]int16) *Client {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/audio/raw/stream", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.WriteMessage(websocket.BinaryMessage, ConvertInt16ToPCM16([]int16{1, 2})) // Before the format
+		conn.WriteMessage(websocket.TextMessage, []byte(format))
+		for _, b := range blocks {
+			conn.WriteMessage(websocket.BinaryMessage, ConvertInt16ToPCM16(b))
+		}
+		conn.ReadMessage() // Until the client hangs up
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	c := NewClient("127.0.0.1")
+	c.wsURL = "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/audio/doa/stream"
+	return c
+}
+
+func TestClient_StreamRaw(t *testing.T) {
+	c := newRawServer(t, `{"type":"format","data":{"sample_rate":16000,"channels":4}}`,
+		[]int16{1, 2, 3, 4, 5, 6, 7, 8},
+		[]int16{9, 10, 11, 12, 13}, // Trailing partial frame is dropped
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var frames []RawFrame
+	err := c.StreamRaw(ctx, func(f RawFrame) {
+		frames = append(frames, f)
+		if len(frames) == 2 {
+			cancel()
+		}
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("StreamRaw = %v, want context.Canceled", err)
+	}
+	if len(frames) != 2 {
+		t.Fatalf("frames = %d, want 2", len(frames))
+	}
+	if f := frames[0]; f.SampleRate != 16000 || f.Channels != 4 || f.Frames() != 2 {
+		t.Errorf("frame 0 = %d Hz, %d channels, %d frames", f.SampleRate, f.Channels, f.Frames())
+	}
+	if got := frames[1].Samples; len(got) != 4 || got[0] != 9 {
+		t.Errorf("frame 1 samples = %v, want [9 10 11 12]", got)
+	}
+}
+
+func TestClient_StreamRaw_Unsupported(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+	c := NewClient("127.0.0.1")
+	c.wsURL = "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/audio/doa/stream"
+
+	if err := c.StreamRaw(context.Background(), func(RawFrame) {}); !errors.Is(err, ErrRawUnsupported) {
+		t.Errorf("StreamRaw = %v, want ErrRawUnsupported", err)
+	}
+}
+
+func TestClient_StreamRaw_BadFormat(t *testing.T) {
+	c := newRawServer(t, `{"type":"format","data":{"sample_rate":16000,"channels":0}}`)
+	if err := c.StreamRaw(context.Background(), func(RawFrame) {}); err == nil {
+		t.Error("StreamRaw accepted a zero-channel format")
+	}
+}
@@ -0,0 +1,175 @@
+package audio
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pion/rtp"
+)
+
+// OutputFrame is one paced frame of outgoing audio.
+type OutputFrame struct {
+	// Packet is the RTP packet carrying the Opus payload.
+	Packet *rtp.Packet
+
+	// PCM holds the 48kHz mono samples the payload encodes.
+	PCM []int16
+
+	// Time is when the frame was released by the pacer.
+	Time time.Time
+}
+
+// AudioSink receives paced output frames.
+type AudioSink interface {
+	// WriteFrame delivers one frame. It must not block for long; the
+	// pacer calls it on the real-time schedule.
+	WriteFrame(f *OutputFrame) error
+
+	// Close releases the sink.
+	Close() error
+}
+
+// UDPSink sends RTP packets to the robot's audio receiver.
+type UDPSink struct {
+	conn *net.UDPConn
+	buf  []byte
+}
+
+// NewUDPSink dials the receiver at addr (host:port).
+func NewUDPSink(addr string) (*UDPSink, error) {
+	raddr, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("resolve %s: %w", addr, err)
+	}
+	conn, err := net.DialUDP("udp", nil, raddr)
+	if err != nil {
+		return nil, fmt.Errorf("dial %s: %w", addr, err)
+	}
+	return &UDPSink{conn: conn, buf: make([]byte, 1500)}, nil
+}
+
+// WriteFrame sends the frame's RTP packet.
+func (s *UDPSink) WriteFrame(f *OutputFrame) error {
+	n, err := f.Packet.MarshalTo(s.buf)
+	if err != nil {
+		return fmt.Errorf("marshal rtp: %w", err)
+	}
+	_, err = s.conn.Write(s.buf[:n])
+	return err
+}
+
+// Close closes the socket.
+func (s *UDPSink) Close() error {
+	return s.conn.Close()
+}
+
+// FileSink writes the decoded output to a 48kHz mono WAV file, for
+// listening to what the robot would have played.
+type FileSink struct {
+	w *WAVWriter
+}
+
+// NewFileSink creates (or truncates) the WAV file at path.
+func NewFileSink(path string) (*FileSink, error) {
+	w, err := CreateWAV(path, outputSampleRate)
+	if err != nil {
+		return nil, err
+	}
+	return &FileSink{w: w}, nil
+}
+
+// WriteFrame appends the frame's samples.
+func (s *FileSink) WriteFrame(f *OutputFrame) error {
+	return s.w.Write(f.PCM)
+}
+
+// Close finalises the WAV header and closes the file.
+func (s *FileSink) Close() error {
+	return s.w.Close()
+}
+
+// LoopbackSink keeps frames in memory for tests and diagnostics.
+type LoopbackSink struct {
+	mu     sync.Mutex
+	frames []*OutputFrame
+	closed bool
+}
+
+// NewLoopbackSink creates an empty loopback sink.
+func NewLoopbackSink() *LoopbackSink {
+	return &LoopbackSink{}
+}
+
+// WriteFrame records a copy of the frame.
+func (s *LoopbackSink) WriteFrame(f *OutputFrame) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return fmt.Errorf("loopback sink closed")
+	}
+	pkt := *f.Packet
+	pkt.Payload = append([]byte(nil), f.Packet.Payload...)
+	s.frames = append(s.frames, &OutputFrame{
+		Packet: &pkt,
+		PCM:    append([]int16(nil), f.PCM...),
+		Time:   f.Time,
+	})
+	return nil
+}
+
+// Frames returns the frames received so far.
+func (s *LoopbackSink) Frames() []*OutputFrame {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*OutputFrame(nil), s.frames...)
+}
+
+// Close marks the sink closed.
+func (s *LoopbackSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+// TeeSink copies every frame to several sinks, e.g. the robot and an
+// echo reference.
+type TeeSink struct {
+	sinks []AudioSink
+}
+
+// NewTeeSink creates a sink writing to all of sinks in order.
+func NewTeeSink(sinks ...AudioSink) *TeeSink {
+	return &TeeSink{sinks: sinks}
+}
+
+// WriteFrame writes to every sink and returns the first error.
+func (s *TeeSink) WriteFrame(f *OutputFrame) error {
+	var first error
+	for _, sink := range s.sinks {
+		if err := sink.WriteFrame(f); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// Close closes every sink and returns the first error.
+func (s *TeeSink) Close() error {
+	var first error
+	for _, sink := range s.sinks {
+		if err := sink.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+var (
+	_ AudioSink = (*TeeSink)(nil)
+	_ AudioSink = (*UDPSink)(nil)
+	_ AudioSink = (*FileSink)(nil)
+	_ AudioSink = (*LoopbackSink)(nil)
+)
@@ -0,0 +1,206 @@
+package audio
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// ErrRelayDown is returned by SSHSink.WriteFrame while it reconnects.
+var ErrRelayDown = errors.New("audio: ssh relay down")
+
+// relayPipeline runs on the robot: it unframes the RTP packets arriving
+// on stdin (RFC 4571: a 2-byte length before each packet) and replays
+// them to the stock receiver, which only listens on loopback.
+const relayPipeline = `gst-launch-1.0 -q fdsrc fd=0 ! application/x-rtp-stream,media=audio,clock-rate=48000,encoding-name=OPUS,payload=96 ! rtpstreamdepay ! udpsink host=127.0.0.1 port=5000 sync=false`
+
+// Relay reconnect backoff
+const (
+	relayRetryMin = time.Second
+	relayRetryMax = 10 * time.Second
+)
+
+// SSHSink sends RTP packets to the robot's stock audio receiver through
+// one SSH session that lasts as long as the sink: packets are framed on
+// the session's stdin and a gst-launch relay on the robot replays them
+// to 127.0.0.1:5000. The SSH client runs in-process, so the password
+// never appears in a process list. If the session drops, frames are
+// dropped (ErrRelayDown) while it reconnects in the background.
+type SSHSink struct {
+	dial func() (io.WriteCloser, error) // Opens a relay; closing it ends the session
+	buf  []byte
+
+	mu        sync.Mutex
+	w         io.WriteCloser // nil while reconnecting
+	closed    bool
+	redialing bool
+}
+
+// NewSSHSink connects to the robot's SSH server at addr (host:port) and
+// starts the relay.
+func NewSSHSink(addr, user, password string) (*SSHSink, error) {
+	cfg := &ssh.ClientConfig{
+		User: user,
+		Auth: []ssh.AuthMethod{ssh.Password(password)},
+		// The robot's key isn't known ahead of time; same trust as the
+		// SSH pipeline's StrictHostKeyChecking=no
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Timeout:         5 * time.Second,
+	}
+	return newSSHSink(func() (io.WriteCloser, error) { return dialRelay(addr, cfg) })
+}
+
+func newSSHSink(dial func() (io.WriteCloser, error)) (*SSHSink, error) {
+	w, err := dial()
+	if err != nil {
+		return nil, err
+	}
+	return &SSHSink{dial: dial, buf: make([]byte, 2+1500), w: w}, nil
+}
+
+// WriteFrame sends the frame's RTP packet down the relay.
+func (s *SSHSink) WriteFrame(f *OutputFrame) error {
+	n, err := f.Packet.MarshalTo(s.buf[2:])
+	if err != nil {
+		return fmt.Errorf("marshal rtp: %w", err)
+	}
+	binary.BigEndian.PutUint16(s.buf, uint16(n))
+
+	s.mu.Lock()
+	w := s.w
+	s.mu.Unlock()
+	if w == nil {
+		return ErrRelayDown
+	}
+	if _, err := w.Write(s.buf[:2+n]); err != nil {
+		s.lost(w, err)
+		return err
+	}
+	return nil
+}
+
+// lost drops the broken relay w and reconnects in the background.
+func (s *SSHSink) lost(w io.WriteCloser, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.w != w {
+		return // Already handled
+	}
+	s.w = nil
+	w.Close()
+	if s.closed || s.redialing {
+		return
+	}
+	fmt.Printf("🔊 Audio relay lost (%v), reconnecting\n", err)
+	s.redialing = true
+	go s.redial()
+}
+
+// redial reconnects with backoff until it succeeds or the sink closes.
+func (s *SSHSink) redial() {
+	wait := relayRetryMin
+	for {
+		time.Sleep(wait)
+		s.mu.Lock()
+		closed := s.closed
+		s.mu.Unlock()
+		if closed {
+			return
+		}
+
+		w, err := s.dial()
+
+		s.mu.Lock()
+		if err == nil {
+			if s.closed {
+				s.mu.Unlock()
+				w.Close()
+				return
+			}
+			s.w = w
+			s.redialing = false
+			s.mu.Unlock()
+			fmt.Println("🔊 Audio relay reconnected")
+			return
+		}
+		s.mu.Unlock()
+		wait = min(2*wait, relayRetryMax)
+	}
+}
+
+// Close ends the relay session.
+func (s *SSHSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	if s.w == nil {
+		return nil
+	}
+	err := s.w.Close()
+	s.w = nil
+	return err
+}
+
+// dialRelay opens an SSH session running relayPipeline and returns its
+// stdin. Closing it closes the session and the connection.
+func dialRelay(addr string, cfg *ssh.ClientConfig) (io.WriteCloser, error) {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return nil, fmt.Errorf("ssh relay: %w", err)
+	}
+	client, err := ssh.Dial("tcp", addr, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("ssh relay: dial %s: %w", addr, err)
+	}
+	session, err := client.NewSession()
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("ssh relay: session: %w", err)
+	}
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("ssh relay: stdin: %w", err)
+	}
+	if err := session.Start(relayPipeline); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("ssh relay: start: %w", err)
+	}
+	r := &relayConn{stdin: stdin, session: session, client: client, done: make(chan struct{})}
+	go func() {
+		session.Wait()
+		close(r.done)
+	}()
+	return r, nil
+}
+
+// relayConn is a running relay session.
+type relayConn struct {
+	stdin   io.WriteCloser
+	session *ssh.Session
+	client  *ssh.Client
+	done    chan struct{} // Closed when the relay exits
+}
+
+// Write sends p to the relay, failing once it has exited rather than
+// filling the session's window.
+func (r *relayConn) Write(p []byte) (int, error) {
+	select {
+	case <-r.done:
+		return 0, errors.New("ssh relay: gst-launch exited")
+	default:
+	}
+	return r.stdin.Write(p)
+}
+
+// Close ends stdin, so gst-launch exits, then drops the connection.
+func (r *relayConn) Close() error {
+	r.stdin.Close()
+	r.session.Close()
+	return r.client.Close()
+}
@@ -0,0 +1,156 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+)
+
+// fakeRelay records what a relay session receives.
+type fakeRelay struct {
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	fail   bool
+	closed bool
+}
+
+func (r *fakeRelay) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.fail || r.closed {
+		return 0, errors.New("broken pipe")
+	}
+	return r.buf.Write(p)
+}
+
+func (r *fakeRelay) isClosed() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.closed
+}
+
+func (r *fakeRelay) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closed = true
+	return nil
+}
+
+// packets splits RFC 4571 framed data into RTP packets.
+func (r *fakeRelay) packets(t *testing.T) []*rtp.Packet {
+	t.Helper()
+	r.mu.Lock()
+	data := append([]byte(nil), r.buf.Bytes()...)
+	r.mu.Unlock()
+
+	var pkts []*rtp.Packet
+	for len(data) > 0 {
+		if len(data) < 2 {
+			t.Fatalf("%d stray bytes", len(data))
+		}
+		n := int(binary.BigEndian.Uint16(data))
+		if len(data) < 2+n {
+			t.Fatalf("frame of %d bytes, %d left", n, len(data)-2)
+		}
+		pkt := &rtp.Packet{}
+		if err := pkt.Unmarshal(data[2 : 2+n]); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		pkts = append(pkts, pkt)
+		data = data[2+n:]
+	}
+	return pkts
+}
+
+func testFrame(seq uint16) *OutputFrame {
+	return &OutputFrame{Packet: &rtp.Packet{
+		Header:  rtp.Header{Version: 2, PayloadType: 96, SequenceNumber: seq},
+		Payload: []byte{1, 2, 3, byte(seq)},
+	}}
+}
+
+func TestSSHSink(t *testing.T) {
+	var mu sync.Mutex
+	var relays []*fakeRelay
+	dials := make(chan struct{}, 10)
+	relay := func(i int) *fakeRelay {
+		mu.Lock()
+		defer mu.Unlock()
+		return relays[i]
+	}
+	s, err := newSSHSink(func() (io.WriteCloser, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		r := &fakeRelay{}
+		relays = append(relays, r)
+		dials <- struct{}{}
+		return r, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-dials
+
+	for seq := range uint16(3) {
+		if err := s.WriteFrame(testFrame(seq)); err != nil {
+			t.Fatalf("WriteFrame(%d): %v", seq, err)
+		}
+	}
+	first := relay(0)
+	pkts := first.packets(t)
+	if len(pkts) != 3 || pkts[2].SequenceNumber != 2 || !bytes.Equal(pkts[2].Payload, []byte{1, 2, 3, 2}) {
+		t.Fatalf("relayed %+v, want 3 framed packets", pkts)
+	}
+
+	// A broken session is dropped and reconnected in the background
+	first.mu.Lock()
+	first.fail = true
+	first.mu.Unlock()
+	if err := s.WriteFrame(testFrame(3)); err == nil {
+		t.Error("WriteFrame on a broken relay = nil, want an error")
+	}
+	if err := s.WriteFrame(testFrame(4)); !errors.Is(err, ErrRelayDown) {
+		t.Errorf("WriteFrame while reconnecting = %v, want ErrRelayDown", err)
+	}
+	if !first.isClosed() {
+		t.Error("broken relay wasn't closed")
+	}
+	select {
+	case <-dials:
+	case <-time.After(3 * relayRetryMin):
+		t.Fatal("relay wasn't redialed")
+	}
+	deadline := time.Now().Add(time.Second)
+	for s.WriteFrame(testFrame(5)) != nil && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	second := relay(1)
+	if pkts := second.packets(t); len(pkts) != 1 || pkts[0].SequenceNumber != 5 {
+		t.Errorf("after reconnect relayed %+v, want packet 5", pkts)
+	}
+
+	if err := s.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+	if !second.isClosed() {
+		t.Error("Close didn't end the relay")
+	}
+	if err := s.WriteFrame(testFrame(6)); !errors.Is(err, ErrRelayDown) {
+		t.Errorf("WriteFrame after Close = %v, want ErrRelayDown", err)
+	}
+}
+
+func TestSSHSink_DialError(t *testing.T) {
+	if _, err := newSSHSink(func() (io.WriteCloser, error) { return nil, errors.New("refused") }); err == nil {
+		t.Error("newSSHSink with a failing dial = nil error")
+	}
+	if _, err := NewSSHSink("no-port", "pollen", "root"); err == nil {
+		t.Error("NewSSHSink(no-port) = nil error")
+	}
+}
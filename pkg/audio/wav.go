@@ -0,0 +1,164 @@
+package audio
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// WAVWriter streams mono PCM16 samples to a WAV file. The header sizes
+// are patched on Close.
+type WAVWriter struct {
+	f       *os.File
+	w       *bufio.Writer
+	rate    int
+	samples int
+}
+
+// CreateWAV creates (or truncates) a mono PCM16 WAV file at path.
+func CreateWAV(path string, sampleRate int) (*WAVWriter, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	w := &WAVWriter{f: f, w: bufio.NewWriter(f), rate: sampleRate}
+	// Placeholder header; sizes are patched on Close
+	if err := writeWAVHeader(w.w, sampleRate, 0); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// Write appends samples.
+func (w *WAVWriter) Write(samples []int16) error {
+	w.samples += len(samples)
+	return binary.Write(w.w, binary.LittleEndian, samples)
+}
+
+// Close finalises the header and closes the file.
+func (w *WAVWriter) Close() error {
+	if err := w.w.Flush(); err != nil {
+		w.f.Close()
+		return err
+	}
+	if _, err := w.f.Seek(0, io.SeekStart); err != nil {
+		w.f.Close()
+		return err
+	}
+	if err := writeWAVHeader(w.f, w.rate, w.samples); err != nil {
+		w.f.Close()
+		return err
+	}
+	return w.f.Close()
+}
+
+// WriteWAV writes mono PCM16 samples to a WAV file.
+func WriteWAV(path string, samples []int16, sampleRate int) error {
+	w, err := CreateWAV(path, sampleRate)
+	if err != nil {
+		return err
+	}
+	if err := w.Write(samples); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// ReadWAV reads a PCM16 WAV file, downmixing to mono. It returns the
+// samples and the sample rate.
+func ReadWAV(path string) ([]int16, int, error) {
+	interleaved, channels, rate, err := ReadWAVChannels(path)
+	if err != nil || channels == 1 {
+		return interleaved, rate, err
+	}
+	mono := make([]int16, len(interleaved)/channels)
+	for i := range mono {
+		var sum int
+		for ch := 0; ch < channels; ch++ {
+			sum += int(interleaved[i*channels+ch])
+		}
+		mono[i] = int16(sum / channels)
+	}
+	return mono, rate, nil
+}
+
+// ReadWAVChannels reads a PCM16 WAV file without downmixing. It returns
+// the interleaved samples, the channel count and the sample rate.
+func ReadWAVChannels(path string) ([]int16, int, int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	var riff [12]byte
+	if _, err := io.ReadFull(r, riff[:]); err != nil {
+		return nil, 0, 0, fmt.Errorf("read header: %w", err)
+	}
+	if string(riff[0:4]) != "RIFF" || string(riff[8:12]) != "WAVE" {
+		return nil, 0, 0, errors.New("not a WAV file")
+	}
+
+	var channels, bits uint16
+	var rate uint32
+	for {
+		var chunk [8]byte
+		if _, err := io.ReadFull(r, chunk[:]); err != nil {
+			return nil, 0, 0, fmt.Errorf("no data chunk: %w", err)
+		}
+		size := binary.LittleEndian.Uint32(chunk[4:])
+
+		switch string(chunk[0:4]) {
+		case "fmt ":
+			fmtChunk := make([]byte, size)
+			if _, err := io.ReadFull(r, fmtChunk); err != nil {
+				return nil, 0, 0, fmt.Errorf("read fmt: %w", err)
+			}
+			// PCM, or WAVE_FORMAT_EXTENSIBLE as written by multichannel recorders
+			format := binary.LittleEndian.Uint16(fmtChunk[0:])
+			if len(fmtChunk) < 16 || (format != 1 && format != 0xFFFE) {
+				return nil, 0, 0, errors.New("only PCM WAV is supported")
+			}
+			channels = binary.LittleEndian.Uint16(fmtChunk[2:])
+			rate = binary.LittleEndian.Uint32(fmtChunk[4:])
+			bits = binary.LittleEndian.Uint16(fmtChunk[14:])
+		case "data":
+			if bits != 16 || channels == 0 {
+				return nil, 0, 0, fmt.Errorf("unsupported format: %d-bit, %d channels", bits, channels)
+			}
+			data, err := io.ReadAll(io.LimitReader(r, int64(size)))
+			if err != nil {
+				return nil, 0, 0, fmt.Errorf("read data: %w", err)
+			}
+			interleaved := ConvertPCM16ToInt16(data)
+			frames := len(interleaved) / int(channels)
+			return interleaved[:frames*int(channels)], int(channels), int(rate), nil
+		default:
+			if _, err := r.Discard(int(size + size%2)); err != nil {
+				return nil, 0, 0, fmt.Errorf("skip %q chunk: %w", chunk[0:4], err)
+			}
+		}
+	}
+}
+
+// writeWAVHeader writes a mono PCM16 header for n samples.
+func writeWAVHeader(w io.Writer, rate, n int) error {
+	dataSize := uint32(n * 2)
+	header := []any{
+		[]byte("RIFF"), 36 + dataSize, []byte("WAVE"),
+		[]byte("fmt "), uint32(16), uint16(1), uint16(1), uint32(rate), uint32(rate * 2), uint16(2), uint16(16),
+		[]byte("data"), dataSize,
+	}
+	for _, v := range header {
+		if err := binary.Write(w, binary.LittleEndian, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
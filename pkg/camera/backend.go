@@ -0,0 +1,145 @@
+package camera
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ErrUnsupported is returned when the robot doesn't provide the camera
+// settings service, so configs can't be applied.
+var ErrUnsupported = errors.New("camera: robot has no camera settings service")
+
+// Backend applies camera configs to the sensor.
+type Backend interface {
+	// Apply reconfigures the camera. Changing the stream format restarts
+	// the video producer.
+	Apply(ctx context.Context, cfg Config) error
+
+	// Effective reads back the settings the camera is actually using,
+	// which can differ from the request (e.g. a resolution snapped to a
+	// sensor mode).
+	Effective(ctx context.Context) (Config, error)
+}
+
+// StreamChanged reports whether going from a to b changes the video
+// stream format, which needs the producer restarted. Everything else
+// (exposure, gain, crop, focus) can be set on the running camera.
+func StreamChanged(a, b Config) bool {
+	return a.Width != b.Width || a.Height != b.Height ||
+		a.Framerate != b.Framerate || a.Quality != b.Quality
+}
+
+// HTTPBackend drives the robot-side camera service on the daemon's HTTP
+// API (port 8000):
+//
+//	GET  /api/camera/settings              effective settings
+//	POST /api/camera/settings[?restart=1]  apply, returns effective settings
+//
+// With restart the service rebuilds the WebRTC producer's pipeline, so
+// the client has to reconnect. Calls must not overlap; Controller
+// serialises them.
+//
+// The stock Reachy Mini daemon doesn't serve these endpoints; they need a
+// camera service installed on the robot. Without one, every call fails
+// with ErrUnsupported, so callers can probe with Effective at startup and
+// leave the camera alone.
+type HTTPBackend struct {
+	BaseURL string
+
+	// OnRestart is called after the producer restarts, to reconnect the
+	// video client. Optional.
+	OnRestart func() error
+
+	client *http.Client
+	last   *Config // Last settings read back or applied
+}
+
+var _ Backend = (*HTTPBackend)(nil)
+
+// NewHTTPBackend creates a backend for the camera service on robotIP.
+func NewHTTPBackend(robotIP string) *HTTPBackend {
+	return &HTTPBackend{
+		BaseURL: fmt.Sprintf("http://%s:8000", robotIP),
+		client:  &http.Client{Timeout: 10 * time.Second}, // A pipeline restart takes a few seconds
+	}
+}
+
+// Apply sends cfg to the camera service, restarting the producer if the
+// stream format changes.
+func (b *HTTPBackend) Apply(ctx context.Context, cfg Config) error {
+	restart := b.last == nil || StreamChanged(*b.last, cfg)
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal camera config: %w", err)
+	}
+	url := b.BaseURL + "/api/camera/settings"
+	if restart {
+		url += "?restart=1"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	eff, err := b.do(req)
+	if err != nil {
+		return fmt.Errorf("camera apply failed: %w", err)
+	}
+	b.last = &eff
+
+	if restart && b.OnRestart != nil {
+		if err := b.OnRestart(); err != nil {
+			return fmt.Errorf("video reconnect after camera restart failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// Effective reads back the camera's current settings.
+func (b *HTTPBackend) Effective(ctx context.Context) (Config, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.BaseURL+"/api/camera/settings", nil)
+	if err != nil {
+		return Config{}, err
+	}
+	eff, err := b.do(req)
+	if err != nil {
+		return Config{}, fmt.Errorf("camera settings request failed: %w", err)
+	}
+	b.last = &eff
+	return eff, nil
+}
+
+// do sends req and decodes the settings in the response.
+func (b *HTTPBackend) do(req *http.Request) (Config, error) {
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return Config{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusMethodNotAllowed {
+		return Config{}, fmt.Errorf("%w (status %d)", ErrUnsupported, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var body struct {
+			Error string `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&body)
+		if body.Error != "" {
+			return Config{}, fmt.Errorf("status %d: %s", resp.StatusCode, body.Error)
+		}
+		return Config{}, fmt.Errorf("status %d", resp.StatusCode)
+	}
+
+	var cfg Config
+	if err := json.NewDecoder(resp.Body).Decode(&cfg); err != nil {
+		return Config{}, fmt.Errorf("failed to decode camera settings: %w", err)
+	}
+	return cfg, nil
+}
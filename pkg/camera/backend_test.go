@@ -0,0 +1,123 @@
+package camera
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// cameraService fakes the robot-side camera service.
+type cameraService struct {
+	current  Config
+	restarts int
+}
+
+func (s *cameraService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/api/camera/settings" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method == http.MethodPost {
+		var cfg Config
+		if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+		if cfg.Width > 3000 {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			json.NewEncoder(w).Encode(map[string]string{"error": "no sensor mode"})
+			return
+		}
+		if r.URL.Query().Get("restart") != "" {
+			s.restarts++
+		}
+		s.current = cfg
+	}
+	json.NewEncoder(w).Encode(s.current)
+}
+
+func TestHTTPBackend(t *testing.T) {
+	svc := &cameraService{current: LegacyConfig()}
+	srv := httptest.NewServer(svc)
+	defer srv.Close()
+
+	b := NewHTTPBackend("127.0.0.1")
+	b.BaseURL = srv.URL
+	reconnects := 0
+	b.OnRestart = func() error {
+		reconnects++
+		return nil
+	}
+
+	eff, err := b.Effective(t.Context())
+	if err != nil || eff != LegacyConfig() {
+		t.Fatalf("Effective() = %+v, %v, want the legacy config", eff, err)
+	}
+
+	// A new resolution restarts the producer
+	if err := b.Apply(t.Context(), HD720Config()); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if svc.restarts != 1 || reconnects != 1 {
+		t.Errorf("restarts = %d, reconnects = %d, want 1 and 1", svc.restarts, reconnects)
+	}
+
+	// Exposure is set on the running camera
+	cfg := HD720Config()
+	cfg.ExposureValue = 1
+	if err := b.Apply(t.Context(), cfg); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if svc.restarts != 1 || reconnects != 1 || svc.current.ExposureValue != 1 {
+		t.Errorf("exposure change: restarts = %d, reconnects = %d, EV = %v", svc.restarts, reconnects, svc.current.ExposureValue)
+	}
+
+	err = b.Apply(t.Context(), UHD4KConfig())
+	if err == nil {
+		t.Fatal("Apply(4K) succeeded")
+	}
+	if want := "camera apply failed: status 422: no sensor mode"; err.Error() != want {
+		t.Errorf("error = %q, want %q", err, want)
+	}
+}
+
+func TestHTTPBackend_Unsupported(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler()) // Stock daemon
+	defer srv.Close()
+
+	b := NewHTTPBackend("127.0.0.1")
+	b.BaseURL = srv.URL
+
+	if _, err := b.Effective(t.Context()); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Effective() error = %v, want ErrUnsupported", err)
+	}
+	if err := b.Apply(t.Context(), HD720Config()); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Apply() error = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestStreamChanged(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		want   bool
+	}{
+		{"nothing", func(*Config) {}, false},
+		{"resolution", func(c *Config) { c.Width = 1280 }, true},
+		{"framerate", func(c *Config) { c.Framerate = 15 }, true},
+		{"exposure", func(c *Config) { c.ExposureMode = "long" }, false},
+		{"zoom", func(c *Config) { c.ZoomLevel = 2 }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := DefaultConfig()
+			tt.modify(&b)
+			if got := StreamChanged(DefaultConfig(), b); got != tt.want {
+				t.Errorf("StreamChanged = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
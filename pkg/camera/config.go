@@ -2,6 +2,8 @@
 // This follows the same pattern as pkg/tracking for tunable parameters.
 package camera
 
+import "math"
+
 // Config holds all camera configuration parameters.
 // These can be modified via the camera API at runtime.
 type Config struct {
@@ -157,6 +159,14 @@ func (c *Config) Validate() []string {
 		errors = append(errors, "zoom_level must be between 1.0 and 4.0")
 	}
 
+	// Crop
+	if c.CropWidth != 0 || c.CropHeight != 0 {
+		if c.CropWidth <= 0 || c.CropHeight <= 0 || c.CropX < 0 || c.CropY < 0 ||
+			c.CropX+c.CropWidth > SensorMaxWidth || c.CropY+c.CropHeight > SensorMaxHeight {
+			errors = append(errors, "crop must lie within the 4608x2592 sensor")
+		}
+	}
+
 	// AF mode
 	validAfModes := map[string]bool{"manual": true, "auto": true, "continuous": true}
 	if c.AfMode != "" && !validAfModes[c.AfMode] {
@@ -166,6 +176,47 @@ func (c *Config) Validate() []string {
 	return errors
 }
 
+// fullView returns the sensor area of the uncropped stream, in pixels:
+// the largest centered window with the stream's aspect ratio.
+func (c Config) fullView() (x, y, w, h float64) {
+	w, h = SensorMaxWidth, SensorMaxHeight
+	if c.Width > 0 && c.Height > 0 {
+		aspect := float64(c.Width) / float64(c.Height)
+		if w/h > aspect {
+			w = h * aspect
+		} else {
+			h = w / aspect
+		}
+	}
+	return (SensorMaxWidth - w) / 2, (SensorMaxHeight - h) / 2, w, h
+}
+
+// View returns the part of the uncropped view the stream shows,
+// normalized (0-1, top-left origin): the manual crop if set, otherwise a
+// centered window for ZoomLevel.
+func (c Config) View() Region {
+	fx, fy, fw, fh := c.fullView()
+	if c.CropWidth > 0 && c.CropHeight > 0 {
+		return Region{
+			X: (float64(c.CropX) - fx) / fw,
+			Y: (float64(c.CropY) - fy) / fh,
+			W: float64(c.CropWidth) / fw,
+			H: float64(c.CropHeight) / fh,
+		}
+	}
+	size := 1 / math.Max(c.ZoomLevel, 1)
+	return Region{X: (1 - size) / 2, Y: (1 - size) / 2, W: size, H: size}
+}
+
+// SetView sets the manual crop to show r of the uncropped view (see View).
+func (c *Config) SetView(r Region) {
+	fx, fy, fw, fh := c.fullView()
+	c.CropX = int(math.Round(fx + r.X*fw))
+	c.CropY = int(math.Round(fy + r.Y*fh))
+	c.CropWidth = int(math.Round(r.W * fw))
+	c.CropHeight = int(math.Round(r.H * fh))
+}
+
 // Capabilities returns the camera sensor capabilities.
 func Capabilities() map[string]interface{} {
 	return map[string]interface{}{
@@ -0,0 +1,133 @@
+package camera
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrNoFrames means the camera stopped delivering frames after a config
+// was applied.
+var ErrNoFrames = errors.New("camera: no frames after applying config")
+
+// Controller applies configs through a Backend and rolls back any that
+// stop the video. Its Apply method is meant for Manager.OnConfigChange.
+type Controller struct {
+	backend   Backend
+	lastFrame func() time.Time // When the last video frame arrived
+
+	// FrameTimeout is how long a new config has to produce a frame.
+	FrameTimeout time.Duration
+
+	mu        sync.Mutex
+	good      *Config // Last config known to produce frames
+	effective *Config // Last settings read back
+}
+
+// NewController creates a Controller. lastFrame reports when the last
+// video frame arrived (e.g. video.Client.LastFrameTime); if it returns
+// the zero time, no video is flowing and the frame check is skipped.
+func NewController(backend Backend, lastFrame func() time.Time) *Controller {
+	return &Controller{
+		backend:      backend,
+		lastFrame:    lastFrame,
+		FrameTimeout: 5 * time.Second,
+	}
+}
+
+// Apply applies cfg and waits for a frame. If the backend fails or no
+// frame arrives within FrameTimeout, it restores the last good config
+// and returns an error.
+func (c *Controller) Apply(cfg Config) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.FrameTimeout+30*time.Second)
+	defer cancel()
+
+	// The first time, what the camera is running now is the fallback
+	if c.good == nil {
+		if eff, err := c.backend.Effective(ctx); err == nil {
+			c.good = &eff
+			c.effective = &eff
+		}
+	}
+
+	watch := c.lastFrame != nil && !c.lastFrame().IsZero()
+	start := time.Now()
+	err := c.backend.Apply(ctx, cfg)
+	if err == nil && watch && !c.waitFrame(ctx, start) {
+		err = fmt.Errorf("%w within %v", ErrNoFrames, c.FrameTimeout)
+	}
+	if err != nil {
+		return c.rollback(ctx, err)
+	}
+
+	c.good = &cfg
+	if eff, err := c.backend.Effective(ctx); err == nil {
+		c.effective = &eff
+	}
+	return nil
+}
+
+// Effective returns the settings the camera reported after the last
+// apply (or the first read-back); ok is false if it never answered.
+func (c *Controller) Effective() (cfg Config, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.effective == nil {
+		return Config{}, false
+	}
+	return *c.effective, true
+}
+
+// Refresh reads back the camera's settings.
+func (c *Controller) Refresh(ctx context.Context) (Config, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	eff, err := c.backend.Effective(ctx)
+	if err != nil {
+		return Config{}, err
+	}
+	c.effective = &eff
+	if c.good == nil {
+		c.good = &eff
+	}
+	return eff, nil
+}
+
+// waitFrame waits for a frame newer than since.
+func (c *Controller) waitFrame(ctx context.Context, since time.Time) bool {
+	deadline := time.NewTimer(c.FrameTimeout)
+	defer deadline.Stop()
+	tick := time.NewTicker(50 * time.Millisecond)
+	defer tick.Stop()
+	for {
+		if c.lastFrame().After(since) {
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-deadline.C:
+			return c.lastFrame().After(since)
+		case <-tick.C:
+		}
+	}
+}
+
+// rollback restores the last good config after cause. Caller holds mu.
+func (c *Controller) rollback(ctx context.Context, cause error) error {
+	if c.good == nil {
+		return fmt.Errorf("%w (no known-good config to roll back to)", cause)
+	}
+	if err := c.backend.Apply(ctx, *c.good); err != nil {
+		return errors.Join(cause, fmt.Errorf("rollback failed: %w", err))
+	}
+	if eff, err := c.backend.Effective(ctx); err == nil {
+		c.effective = &eff
+	}
+	return fmt.Errorf("%w; rolled back to %dx%d @ %dfps", cause, c.good.Width, c.good.Height, c.good.Framerate)
+}
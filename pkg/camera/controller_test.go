@@ -0,0 +1,113 @@
+package camera
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestController(fake *FakeBackend) *Controller {
+	c := NewController(fake, fake.LastFrameTime)
+	c.FrameTimeout = 100 * time.Millisecond
+	return c
+}
+
+func TestController_Apply(t *testing.T) {
+	fake := NewFakeBackend(DefaultConfig())
+	fake.Snap = func(cfg Config) Config {
+		cfg.Framerate = min(cfg.Framerate, 30) // Sensor mode limit
+		return cfg
+	}
+	c := newTestController(fake)
+
+	cfg := HD720Config()
+	cfg.Framerate = 60
+	if err := c.Apply(cfg); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if applied := fake.Applied(); len(applied) != 1 || applied[0] != cfg {
+		t.Errorf("applied = %+v, want just the new config", applied)
+	}
+	eff, ok := c.Effective()
+	if !ok || eff.Width != 1280 || eff.Framerate != 30 {
+		t.Errorf("Effective() = %dx%d @ %d, %v, want 1280x720 @ 30", eff.Width, eff.Height, eff.Framerate, ok)
+	}
+}
+
+func TestController_RollsBackWithoutFrames(t *testing.T) {
+	fake := NewFakeBackend(DefaultConfig())
+	fake.Broken = func(cfg Config) bool { return cfg.Width == 3840 }
+	c := newTestController(fake)
+	fake.LastFrameTime() // Video is flowing
+
+	err := c.Apply(UHD4KConfig())
+	if !errors.Is(err, ErrNoFrames) {
+		t.Fatalf("Apply = %v, want ErrNoFrames", err)
+	}
+	applied := fake.Applied()
+	if len(applied) != 2 || applied[1] != DefaultConfig() {
+		t.Errorf("applied = %+v, want 4K then the default back", applied)
+	}
+	if eff, _ := c.Effective(); eff != DefaultConfig() {
+		t.Errorf("Effective() = %+v after rollback, want the default", eff)
+	}
+
+	// The last good config is still the fallback
+	if err := c.Apply(HD720Config()); err != nil {
+		t.Fatalf("Apply(720p): %v", err)
+	}
+	if err := c.Apply(UHD4KConfig()); err == nil {
+		t.Fatal("Apply(4K) succeeded")
+	}
+	if applied := fake.Applied(); applied[len(applied)-1] != HD720Config() {
+		t.Errorf("rolled back to %+v, want 720p", applied[len(applied)-1])
+	}
+}
+
+func TestController_RollsBackOnError(t *testing.T) {
+	fake := NewFakeBackend(DefaultConfig())
+	c := newTestController(fake)
+	if _, err := c.Refresh(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+
+	fake.Err = errors.New("pipeline failed")
+	if err := c.Apply(NightModeConfig()); err == nil {
+		t.Error("Apply succeeded with a failing backend")
+	}
+	if eff, _ := c.Effective(); eff != DefaultConfig() {
+		t.Errorf("Effective() = %+v, want the default", eff)
+	}
+}
+
+// Without video there are no frames to wait for.
+func TestController_NoVideo(t *testing.T) {
+	fake := NewFakeBackend(DefaultConfig())
+	c := NewController(fake, func() time.Time { return time.Time{} })
+	c.FrameTimeout = time.Hour
+	if err := c.Apply(HD720Config()); err != nil {
+		t.Errorf("Apply: %v", err)
+	}
+}
+
+func TestManager_RejectedConfigNotKept(t *testing.T) {
+	fake := NewFakeBackend(DefaultConfig())
+	fake.Broken = func(cfg Config) bool { return cfg.ExposureMode == "long" }
+	c := newTestController(fake)
+	fake.LastFrameTime()
+
+	m := NewManager()
+	m.OnConfigChange = c.Apply
+	if err := m.UpdateConfig(map[string]interface{}{"preset": PresetNight}); err == nil {
+		t.Fatal("UpdateConfig succeeded")
+	}
+	if got := m.GetConfig(); got != DefaultConfig() {
+		t.Errorf("config = %+v, want the default kept", got)
+	}
+	if err := m.UpdateConfig(map[string]interface{}{"zoom_level": 2.0}); err != nil {
+		t.Fatalf("UpdateConfig: %v", err)
+	}
+	if got := m.GetConfig().ZoomLevel; got != 2 {
+		t.Errorf("zoom = %v, want 2", got)
+	}
+}
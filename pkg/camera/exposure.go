@@ -0,0 +1,390 @@
+package camera
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image/jpeg"
+	"math"
+	"sync"
+	"time"
+)
+
+// LightMode is the lighting regime auto-exposure has chosen.
+type LightMode int
+
+const (
+	LightNormal LightMode = iota
+	LightNight            // NightModeConfig exposure
+	LightBright           // BrightModeConfig exposure
+)
+
+// String returns the mode name.
+func (m LightMode) String() string {
+	switch m {
+	case LightNight:
+		return "night"
+	case LightBright:
+		return "bright"
+	}
+	return "normal"
+}
+
+// AutoExposureConfig tunes the auto-exposure controller. Luma values
+// are 0-255.
+type AutoExposureConfig struct {
+	Interval time.Duration // Minimum time between analysed frames
+	Hold     time.Duration // Settle time after each change
+
+	FaceTarget  float64 // Face luma to aim for when a face is visible
+	FrameTarget float64 // Frame luma to aim for otherwise
+	Deadband    float64 // No nudge within target ± Deadband
+
+	Step    float64 // EV change per nudge (stops)
+	MaxGain float64 // Highest manual analogue gain
+
+	NightEnter  float64       // Frame luma below which night mode is entered
+	NightExit   float64       // Frame luma above which night mode is left
+	BrightEnter float64       // Clipped fraction above which bright mode is entered
+	BrightExit  float64       // Clipped fraction below which bright mode is left
+	Dwell       time.Duration // How long a mode condition must hold
+
+	ManualHold   time.Duration // Pause after someone else changes exposure
+	ErrorBackoff time.Duration // Pause after the camera rejects a change
+}
+
+// DefaultAutoExposureConfig returns settings for the IMX708 indoors.
+func DefaultAutoExposureConfig() AutoExposureConfig {
+	return AutoExposureConfig{
+		Interval:     time.Second,
+		Hold:         2 * time.Second,
+		FaceTarget:   120,
+		FrameTarget:  110,
+		Deadband:     25,
+		Step:         0.25,
+		MaxGain:      SensorMaxGain,
+		NightEnter:   40,
+		NightExit:    110,
+		BrightEnter:  0.08,
+		BrightExit:   0.01,
+		Dwell:        10 * time.Second,
+		ManualHold:   5 * time.Minute,
+		ErrorBackoff: time.Minute,
+	}
+}
+
+const (
+	minEV         = -2.0
+	maxEV         = 2.0
+	baseGain      = 8.0 // First manual gain once EV is exhausted
+	gainStepRatio = 1.4
+)
+
+// ExposureDecision describes one auto-exposure action.
+type ExposureDecision struct {
+	Time   time.Time
+	Mode   LightMode
+	Action string // "mode", "nudge", "manual" or "error"
+	Detail string
+	Stats  FrameStats
+}
+
+// String formats the decision for logs.
+func (d ExposureDecision) String() string {
+	return fmt.Sprintf("auto-exposure %s (%s): %s", d.Action, d.Mode, d.Detail)
+}
+
+// exposure is the part of a Config auto-exposure owns.
+type exposure struct {
+	mode       string
+	constraint string
+	ev         float64
+	gain       float64
+	time       int
+}
+
+func exposureOf(cfg Config) exposure {
+	return exposure{cfg.ExposureMode, cfg.ConstraintMode, cfg.ExposureValue, cfg.AnalogueGain, cfg.ExposureTime}
+}
+
+func (e exposure) applyTo(cfg Config) Config {
+	cfg.ExposureMode = e.mode
+	cfg.ConstraintMode = e.constraint
+	cfg.ExposureValue = e.ev
+	cfg.AnalogueGain = e.gain
+	cfg.ExposureTime = e.time
+	return cfg
+}
+
+// modeOf infers the light mode a config was set up for.
+func modeOf(cfg Config) LightMode {
+	switch {
+	case cfg.ExposureMode == "long" && cfg.ConstraintMode == "shadows":
+		return LightNight
+	case cfg.ConstraintMode == "highlight":
+		return LightBright
+	}
+	return LightNormal
+}
+
+// modeExposure returns the preset exposure for a light mode, with auto
+// gain and shutter. Resolution is not part of it, so a mode switch
+// never restarts the stream.
+func modeExposure(m LightMode) exposure {
+	var cfg Config
+	switch m {
+	case LightNight:
+		cfg = NightModeConfig()
+	case LightBright:
+		cfg = BrightModeConfig()
+	default:
+		cfg = DefaultConfig()
+	}
+	e := exposureOf(cfg)
+	e.gain, e.time = 0, 0
+	return e
+}
+
+// AutoExposure adapts the camera to changing light. It meters decoded
+// frames (faces first), nudges ExposureValue and AnalogueGain toward a
+// target luma, and switches between the normal, night and bright
+// presets with hysteresis. Changes go through the Manager, so they are
+// validated and rolled back like any other config change.
+type AutoExposure struct {
+	config  AutoExposureConfig
+	manager *Manager
+
+	// OnDecision is called for every change or pause. Optional.
+	OnDecision func(ExposureDecision)
+
+	frames chan frameSample
+	now    func() time.Time
+
+	mu          sync.Mutex
+	lastObserve time.Time
+	mode        LightMode
+	stats       FrameStats
+	applied     *exposure // What we last set; nil until the first update
+	holdUntil   time.Time // No changes before this
+	enterSince  time.Time // When the condition to enter a mode started
+	exitSince   time.Time // When the condition to leave the mode started
+}
+
+type frameSample struct {
+	jpeg  []byte
+	faces []Region
+}
+
+// NewAutoExposure creates an auto-exposure controller for m.
+func NewAutoExposure(m *Manager, cfg AutoExposureConfig) *AutoExposure {
+	return &AutoExposure{
+		config:  cfg,
+		manager: m,
+		frames:  make(chan frameSample, 1),
+		now:     time.Now,
+		mode:    modeOf(m.GetConfig()),
+	}
+}
+
+// Observe offers a JPEG frame and the faces found in it. It never
+// blocks: frames arriving faster than Interval, or while the last one
+// is still being processed, are dropped.
+func (a *AutoExposure) Observe(frame []byte, faces []Region) {
+	a.mu.Lock()
+	now := a.now()
+	if now.Sub(a.lastObserve) < a.config.Interval {
+		a.mu.Unlock()
+		return
+	}
+	a.lastObserve = now
+	a.mu.Unlock()
+
+	select {
+	case a.frames <- frameSample{frame, faces}:
+	default:
+	}
+}
+
+// Run processes observed frames until ctx is done.
+func (a *AutoExposure) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case f := <-a.frames:
+			img, err := jpeg.Decode(bytes.NewReader(f.jpeg))
+			if err != nil {
+				continue
+			}
+			a.update(ComputeStats(img, f.faces))
+		}
+	}
+}
+
+// Mode returns the current light mode.
+func (a *AutoExposure) Mode() LightMode {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.mode
+}
+
+// Stats returns the statistics of the last analysed frame.
+func (a *AutoExposure) Stats() FrameStats {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.stats
+}
+
+// update meters one frame and applies at most one change.
+func (a *AutoExposure) update(s FrameStats) {
+	a.mu.Lock()
+	now := a.now()
+	a.stats = s
+	cur := exposureOf(a.manager.GetConfig())
+
+	// Someone else changed exposure: follow them and stay out of the way
+	if a.applied != nil && *a.applied != cur {
+		a.applied = nil
+		a.mode = modeOf(a.manager.GetConfig())
+		a.enterSince, a.exitSince = time.Time{}, time.Time{}
+		a.holdUntil = now.Add(a.config.ManualHold)
+		d := a.decision(now, "manual", fmt.Sprintf("exposure changed externally, pausing %v", a.config.ManualHold))
+		a.mu.Unlock()
+		a.report(d)
+		return
+	}
+	if now.Before(a.holdUntil) {
+		a.mu.Unlock()
+		return
+	}
+
+	next, action, detail := a.decide(now, s, cur)
+	if next == cur {
+		a.applied = &cur
+		a.mu.Unlock()
+		return
+	}
+	a.mu.Unlock()
+
+	// Apply without the lock: the camera may take seconds to settle
+	err := a.manager.SetConfig(next.applyTo(a.manager.GetConfig()))
+
+	a.mu.Lock()
+	if err != nil {
+		a.holdUntil = a.now().Add(a.config.ErrorBackoff)
+		if action == "mode" {
+			a.mode = modeOf(a.manager.GetConfig())
+		}
+		applied := exposureOf(a.manager.GetConfig())
+		a.applied = &applied
+		d := a.decision(now, "error", fmt.Sprintf("%s: %v", detail, err))
+		a.mu.Unlock()
+		a.report(d)
+		return
+	}
+	a.applied = &next
+	a.holdUntil = a.now().Add(a.config.Hold)
+	d := a.decision(now, action, detail)
+	a.mu.Unlock()
+	a.report(d)
+}
+
+// decide picks the next exposure. A mode switch wins over a nudge.
+// Caller holds mu.
+func (a *AutoExposure) decide(now time.Time, s FrameStats, cur exposure) (exposure, string, string) {
+	c := a.config
+
+	// Mode hysteresis on whole-frame statistics
+	var enter, exit bool
+	target := a.mode
+	switch a.mode {
+	case LightNormal:
+		switch {
+		case s.Mean < c.NightEnter:
+			enter, target = true, LightNight
+		case s.Clipped > c.BrightEnter:
+			enter, target = true, LightBright
+		}
+	case LightNight:
+		// Only once the preset alone is enough, or we'd flap straight back
+		exit = s.Mean > c.NightExit && cur.gain == 0 && cur.ev <= modeExposure(LightNight).ev
+	case LightBright:
+		exit = s.Clipped < c.BrightExit && s.Mean < c.FrameTarget
+	}
+	if enter {
+		if a.enterSince.IsZero() {
+			a.enterSince = now
+		}
+	} else {
+		a.enterSince = time.Time{}
+	}
+	if exit {
+		if a.exitSince.IsZero() {
+			a.exitSince = now
+		}
+	} else {
+		a.exitSince = time.Time{}
+	}
+
+	switch {
+	case enter && now.Sub(a.enterSince) >= c.Dwell:
+		detail := fmt.Sprintf("frame luma %.0f < %.0f for %v", s.Mean, c.NightEnter, c.Dwell)
+		if target == LightBright {
+			detail = fmt.Sprintf("%.0f%% clipped for %v", s.Clipped*100, c.Dwell)
+		}
+		return a.switchMode(target), "mode", detail
+	case exit && now.Sub(a.exitSince) >= c.Dwell:
+		detail := fmt.Sprintf("frame luma %.0f, %.0f%% clipped for %v", s.Mean, s.Clipped*100, c.Dwell)
+		return a.switchMode(LightNormal), "mode", detail
+	}
+
+	// Nudge toward the target, metering the face when there is one
+	luma, goal, what := s.Mean, c.FrameTarget, "frame"
+	if s.HasFace {
+		luma, goal, what = s.FaceLuma, c.FaceTarget, "face"
+	}
+	next := cur
+	switch {
+	case luma < goal-c.Deadband:
+		if next.ev < maxEV {
+			next.ev = math.Min(next.ev+c.Step, maxEV)
+		} else if next.gain < c.MaxGain {
+			next.gain = math.Min(math.Max(next.gain*gainStepRatio, baseGain), c.MaxGain)
+		}
+	case luma > goal+c.Deadband:
+		if next.gain > 0 {
+			if next.gain /= gainStepRatio; next.gain < baseGain {
+				next.gain = 0 // Back to auto gain
+			}
+		} else if next.ev > minEV {
+			next.ev = math.Max(next.ev-c.Step, minEV)
+		}
+	}
+	return next, "nudge", fmt.Sprintf("%s luma %.0f (target %.0f): EV %+.2f, gain %s",
+		what, luma, goal, next.ev, gainString(next.gain))
+}
+
+// switchMode resets the dwell timers and returns the new mode's
+// exposure. Caller holds mu.
+func (a *AutoExposure) switchMode(m LightMode) exposure {
+	a.mode = m
+	a.enterSince, a.exitSince = time.Time{}, time.Time{}
+	return modeExposure(m)
+}
+
+func (a *AutoExposure) decision(now time.Time, action, detail string) ExposureDecision {
+	return ExposureDecision{Time: now, Mode: a.mode, Action: action, Detail: detail, Stats: a.stats}
+}
+
+func (a *AutoExposure) report(d ExposureDecision) {
+	if a.OnDecision != nil {
+		a.OnDecision(d)
+	}
+}
+
+func gainString(g float64) string {
+	if g == 0 {
+		return "auto"
+	}
+	return fmt.Sprintf("%.1fx", g)
+}
@@ -0,0 +1,173 @@
+package camera
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type aeHarness struct {
+	ae        *AutoExposure
+	manager   *Manager
+	clock     time.Time
+	decisions []ExposureDecision
+}
+
+func newAEHarness() *aeHarness {
+	h := &aeHarness{manager: NewManager(), clock: time.Unix(1000, 0)}
+	h.ae = NewAutoExposure(h.manager, DefaultAutoExposureConfig())
+	h.ae.now = func() time.Time { return h.clock }
+	h.ae.OnDecision = func(d ExposureDecision) { h.decisions = append(h.decisions, d) }
+	return h
+}
+
+// feed runs n updates one second apart and returns the decisions made.
+func (h *aeHarness) feed(s FrameStats, n int) []ExposureDecision {
+	start := len(h.decisions)
+	for range n {
+		h.ae.update(s)
+		h.clock = h.clock.Add(time.Second)
+	}
+	return h.decisions[start:]
+}
+
+func TestAutoExposure_NudgesTowardFace(t *testing.T) {
+	h := newAEHarness()
+	dark := FrameStats{Mean: 110, FaceLuma: 50, HasFace: true}
+
+	// One step per Hold (2s)
+	if d := h.feed(dark, 2); len(d) != 1 || d[0].Action != "nudge" {
+		t.Fatalf("decisions = %v, want one nudge", d)
+	}
+	if ev := h.manager.GetConfig().ExposureValue; ev != 0.25 {
+		t.Errorf("EV = %v, want 0.25", ev)
+	}
+
+	// EV runs out, then gain takes over up to MaxGain
+	h.feed(dark, 40)
+	cfg := h.manager.GetConfig()
+	if cfg.ExposureValue != maxEV || cfg.AnalogueGain != SensorMaxGain {
+		t.Errorf("EV = %v, gain = %v, want %v and %v", cfg.ExposureValue, cfg.AnalogueGain, maxEV, SensorMaxGain)
+	}
+	if cfg.Width != 1920 {
+		t.Errorf("width = %d, nudging must not change resolution", cfg.Width)
+	}
+
+	// Too bright: gain comes down first, back to auto
+	bright := FrameStats{Mean: 110, FaceLuma: 200, HasFace: true}
+	h.feed(bright, 8)
+	cfg = h.manager.GetConfig()
+	if cfg.AnalogueGain != 0 || cfg.ExposureValue != maxEV-0.25 {
+		t.Errorf("EV = %v, gain = %v, want %v and auto", cfg.ExposureValue, cfg.AnalogueGain, maxEV-0.25)
+	}
+
+	// Within the deadband nothing happens
+	if d := h.feed(FrameStats{Mean: 110, FaceLuma: 130, HasFace: true}, 5); len(d) != 0 {
+		t.Errorf("decisions = %v in the deadband, want none", d)
+	}
+}
+
+func TestAutoExposure_NightHysteresis(t *testing.T) {
+	h := newAEHarness()
+
+	// Brief darkness doesn't switch
+	h.feed(FrameStats{Mean: 20}, 5)
+	if m := h.ae.Mode(); m != LightNormal {
+		t.Fatalf("mode = %v after 5s, want normal", m)
+	}
+	h.feed(FrameStats{Mean: 20}, 10)
+	if m := h.ae.Mode(); m != LightNight {
+		t.Fatalf("mode = %v after 15s of darkness, want night", m)
+	}
+	cfg := h.manager.GetConfig()
+	if cfg.ExposureMode != "long" || cfg.ConstraintMode != "shadows" || cfg.Width != 1920 {
+		t.Errorf("config = %+v, want night exposure at the same resolution", cfg)
+	}
+
+	// Between the thresholds the mode holds
+	h.feed(FrameStats{Mean: 90}, 30)
+	if m := h.ae.Mode(); m != LightNight {
+		t.Errorf("mode = %v at luma 90, want night", m)
+	}
+
+	// Lights on: EV comes down to the preset, then the mode is left
+	h.feed(FrameStats{Mean: 200}, 20)
+	if m := h.ae.Mode(); m != LightNormal {
+		t.Errorf("mode = %v after the lights came on, want normal", m)
+	}
+	if cfg := h.manager.GetConfig(); cfg.ExposureMode != "normal" || cfg.ConstraintMode != "normal" {
+		t.Errorf("config = %+v, want normal exposure", cfg)
+	}
+}
+
+func TestAutoExposure_BrightMode(t *testing.T) {
+	h := newAEHarness()
+	h.feed(FrameStats{Mean: 120, Clipped: 0.2}, 12)
+	if m := h.ae.Mode(); m != LightBright {
+		t.Fatalf("mode = %v, want bright", m)
+	}
+	if cfg := h.manager.GetConfig(); cfg.ConstraintMode != "highlight" {
+		t.Errorf("constraint = %q, want highlight", cfg.ConstraintMode)
+	}
+	// Still some clipping: stay
+	h.feed(FrameStats{Mean: 100, Clipped: 0.03}, 15)
+	if m := h.ae.Mode(); m != LightBright {
+		t.Errorf("mode = %v with 3%% clipped, want bright", m)
+	}
+	h.feed(FrameStats{Mean: 100}, 12)
+	if m := h.ae.Mode(); m != LightNormal {
+		t.Errorf("mode = %v, want normal", m)
+	}
+}
+
+func TestAutoExposure_ManualOverride(t *testing.T) {
+	h := newAEHarness()
+	h.feed(FrameStats{Mean: 110}, 1) // Adopts the current exposure
+
+	if err := h.manager.UpdateConfig(map[string]interface{}{"exposure_value": -1.0}); err != nil {
+		t.Fatal(err)
+	}
+	d := h.feed(FrameStats{Mean: 20, FaceLuma: 20, HasFace: true}, 60)
+	if len(d) != 1 || d[0].Action != "manual" {
+		t.Fatalf("decisions = %v, want just the manual pause", d)
+	}
+	if ev := h.manager.GetConfig().ExposureValue; ev != -1 {
+		t.Errorf("EV = %v during the pause, want -1", ev)
+	}
+
+	h.clock = h.clock.Add(5 * time.Minute)
+	h.feed(FrameStats{Mean: 20, FaceLuma: 20, HasFace: true}, 1)
+	if ev := h.manager.GetConfig().ExposureValue; ev != -0.75 {
+		t.Errorf("EV = %v after the pause, want -0.75", ev)
+	}
+}
+
+func TestAutoExposure_ErrorBackoff(t *testing.T) {
+	h := newAEHarness()
+	h.manager.OnConfigChange = func(Config) error { return errors.New("camera offline") }
+
+	d := h.feed(FrameStats{Mean: 110, FaceLuma: 40, HasFace: true}, 30)
+	if len(d) != 1 || d[0].Action != "error" {
+		t.Fatalf("decisions = %v, want one error", d)
+	}
+	if cfg := h.manager.GetConfig(); cfg != DefaultConfig() {
+		t.Errorf("config = %+v, want the default kept", cfg)
+	}
+}
+
+func TestAutoExposure_Observe(t *testing.T) {
+	h := newAEHarness()
+	h.ae.Observe([]byte("a"), nil)
+	h.ae.Observe([]byte("b"), nil) // Too soon
+	h.clock = h.clock.Add(2 * time.Second)
+	h.ae.Observe([]byte("c"), nil) // Channel still full
+
+	if f := <-h.ae.frames; string(f.jpeg) != "a" {
+		t.Errorf("frame = %q, want a", f.jpeg)
+	}
+	select {
+	case f := <-h.ae.frames:
+		t.Errorf("unexpected frame %q", f.jpeg)
+	default:
+	}
+}
@@ -0,0 +1,73 @@
+package camera
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// FakeBackend is an in-memory Backend for tests. It simulates the video
+// stream: LastFrameTime advances while the applied config is not Broken.
+type FakeBackend struct {
+	// Broken reports whether a config stops the video. Optional.
+	Broken func(Config) bool
+
+	// Snap maps a requested config to what the sensor really runs, for
+	// read-back. Optional.
+	Snap func(Config) Config
+
+	// Err, if set, fails every Apply.
+	Err error
+
+	mu      sync.Mutex
+	current Config
+	applied []Config
+	frame   time.Time // Last simulated frame
+}
+
+var _ Backend = (*FakeBackend)(nil)
+
+// NewFakeBackend creates a FakeBackend running cfg.
+func NewFakeBackend(cfg Config) *FakeBackend {
+	return &FakeBackend{current: cfg}
+}
+
+// Apply records cfg and makes it current.
+func (f *FakeBackend) Apply(ctx context.Context, cfg Config) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return f.Err
+	}
+	f.applied = append(f.applied, cfg)
+	if f.Snap != nil {
+		cfg = f.Snap(cfg)
+	}
+	f.current = cfg
+	return nil
+}
+
+// Effective returns the current config.
+func (f *FakeBackend) Effective(ctx context.Context) (Config, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.current, nil
+}
+
+// Applied returns every config passed to Apply, in order.
+func (f *FakeBackend) Applied() []Config {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Config(nil), f.applied...)
+}
+
+// LastFrameTime returns now while the video is flowing; under a broken
+// config it stays at the last frame before the break.
+func (f *FakeBackend) LastFrameTime() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Broken == nil || !f.Broken(f.current) {
+		f.frame = time.Now()
+	}
+	return f.frame
+}
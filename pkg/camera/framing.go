@@ -0,0 +1,287 @@
+package camera
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// FramingConfig tunes auto-framing. Sizes are fractions of the uncropped
+// view.
+type FramingConfig struct {
+	Interval time.Duration // Minimum time between crop updates
+
+	Group     bool    // Frame every face instead of just the focused one
+	FaceScale float64 // Window width as a multiple of the largest face's width
+	Padding   float64 // Margin around the faces, per side, as a fraction of their extent
+	EyeLine   float64 // Where the faces sit vertically in the window (0 = top)
+	MaxZoom   float64 // Smallest window is 1/MaxZoom
+
+	Smoothing float64 // Fraction of the way to the target per update
+	MaxStep   float64 // Largest move of the window per update
+	Deadband  float64 // Smaller moves are skipped
+
+	LostTimeout time.Duration // Zoom back out after no face for this long
+	ManualHold  time.Duration // Pause after someone else changes the crop or zoom
+}
+
+// DefaultFramingConfig returns settings for framing one person.
+func DefaultFramingConfig() FramingConfig {
+	return FramingConfig{
+		Interval:    200 * time.Millisecond,
+		FaceScale:   5,
+		Padding:     0.3,
+		EyeLine:     0.4,
+		MaxZoom:     3,
+		Smoothing:   0.25,
+		MaxStep:     0.03,
+		Deadband:    0.005,
+		LostTimeout: 3 * time.Second,
+		ManualHold:  5 * time.Minute,
+	}
+}
+
+// window is a square (in view units, so the stream's aspect ratio) crop
+// window.
+type window struct {
+	x, y, size float64
+}
+
+func (w window) region() Region {
+	return Region{X: w.x, Y: w.y, W: w.size, H: w.size}
+}
+
+// crop is the part of a Config the framer owns.
+type crop struct {
+	x, y, w, h int
+	zoom       float64
+}
+
+func cropOf(cfg Config) crop {
+	return crop{cfg.CropX, cfg.CropY, cfg.CropWidth, cfg.CropHeight, cfg.ZoomLevel}
+}
+
+// Framer moves the camera's digital crop to keep the focused person (or
+// everyone, with Group) well framed. It works in the uncropped view, so
+// faces found in the cropped frames are mapped back through the crop
+// they were seen with. Changes go through the Manager, like any other.
+type Framer struct {
+	config  FramingConfig
+	manager *Manager
+
+	// OnEvent is called when framing pauses, resumes or fails. Optional.
+	OnEvent func(msg string)
+
+	faces chan faceSample
+	now   func() time.Time
+
+	mu          sync.Mutex
+	lastObserve time.Time
+	lastSeen    time.Time
+	lost        bool
+	applied     *crop     // What we last set; nil until the first update
+	holdUntil   time.Time // No changes before this
+}
+
+type faceSample struct {
+	faces []Region
+	focus int
+}
+
+// NewFramer creates an auto-framer for m.
+func NewFramer(m *Manager, cfg FramingConfig) *Framer {
+	return &Framer{
+		config:  cfg,
+		manager: m,
+		faces:   make(chan faceSample, 1),
+		now:     time.Now,
+		lost:    true,
+	}
+}
+
+// Observe offers the faces found in a frame, normalized to the frame,
+// and the index of the focused one (-1 if none). It never blocks.
+func (f *Framer) Observe(faces []Region, focus int) {
+	f.mu.Lock()
+	now := f.now()
+	if now.Sub(f.lastObserve) < f.config.Interval {
+		f.mu.Unlock()
+		return
+	}
+	f.lastObserve = now
+	f.mu.Unlock()
+
+	select {
+	case f.faces <- faceSample{faces, focus}:
+	default:
+	}
+}
+
+// Run reframes on observed faces until ctx is done.
+func (f *Framer) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case s := <-f.faces:
+			f.update(s.faces, s.focus)
+		}
+	}
+}
+
+// update moves the crop one step toward the target framing.
+func (f *Framer) update(faces []Region, focus int) {
+	f.mu.Lock()
+	now := f.now()
+	cfg := f.manager.GetConfig()
+
+	// Someone else set the zoom or crop: leave it alone for a while
+	if f.applied != nil && *f.applied != cropOf(cfg) {
+		f.applied = nil
+		f.holdUntil = now.Add(f.config.ManualHold)
+		f.mu.Unlock()
+		f.event(fmt.Sprintf("crop changed externally, pausing %v", f.config.ManualHold))
+		return
+	}
+	if now.Before(f.holdUntil) {
+		f.mu.Unlock()
+		return
+	}
+
+	cur := cfg.View()
+	var msg string
+	target, ok := f.target(faces, focus, cur)
+	if ok {
+		f.lastSeen = now
+		if f.lost {
+			f.lost = false
+			msg = "subject found, framing"
+		}
+	} else {
+		if now.Sub(f.lastSeen) < f.config.LostTimeout {
+			applied := cropOf(cfg)
+			f.applied = &applied
+			f.mu.Unlock()
+			return
+		}
+		target = window{0, 0, 1}
+		if !f.lost {
+			f.lost = true
+			msg = "subject lost, zooming out"
+		}
+	}
+
+	next := cfg
+	setWindow(&next, f.step(window{cur.X, cur.Y, math.Max(cur.W, cur.H)}, target))
+	if cropOf(next) == cropOf(cfg) {
+		applied := cropOf(cfg)
+		f.applied = &applied
+		f.mu.Unlock()
+		f.event(msg)
+		return
+	}
+	f.mu.Unlock()
+	f.event(msg)
+
+	// Apply without the lock: the camera may take a moment
+	err := f.manager.SetConfig(next)
+
+	f.mu.Lock()
+	applied := cropOf(f.manager.GetConfig())
+	f.applied = &applied
+	if err != nil {
+		f.holdUntil = f.now().Add(f.config.ManualHold)
+	}
+	f.mu.Unlock()
+	if err != nil {
+		f.event(fmt.Sprintf("crop not applied, pausing %v: %v", f.config.ManualHold, err))
+	}
+}
+
+// target returns the ideal window for faces seen through view, or false
+// if there is no one to frame.
+func (f *Framer) target(faces []Region, focus int, view Region) (window, bool) {
+	c := f.config
+	subject := faces
+	if !c.Group {
+		if focus < 0 || focus >= len(faces) {
+			return window{}, false
+		}
+		subject = faces[focus : focus+1]
+	}
+	if len(subject) == 0 {
+		return window{}, false
+	}
+
+	// Bounding box and largest face, in view units
+	x0, y0 := math.Inf(1), math.Inf(1)
+	x1, y1 := math.Inf(-1), math.Inf(-1)
+	largest := 0.0
+	for _, r := range subject {
+		x0 = math.Min(x0, view.X+r.X*view.W)
+		y0 = math.Min(y0, view.Y+r.Y*view.H)
+		x1 = math.Max(x1, view.X+(r.X+r.W)*view.W)
+		y1 = math.Max(y1, view.Y+(r.Y+r.H)*view.H)
+		largest = math.Max(largest, r.W*view.W)
+	}
+
+	size := math.Max(math.Max(x1-x0, y1-y0)*(1+2*c.Padding), largest*c.FaceScale)
+	size = clampf(size, 1/math.Max(c.MaxZoom, 1), 1)
+	w := window{
+		x:    (x0+x1)/2 - size/2,
+		y:    (y0+y1)/2 - c.EyeLine*size,
+		size: size,
+	}
+	w.x = clampf(w.x, 0, 1-size)
+	w.y = clampf(w.y, 0, 1-size)
+	return w, true
+}
+
+// step moves cur part of the way to target, at most MaxStep per edge.
+// Moves within the deadband are skipped, except the last one back to
+// the full view.
+func (f *Framer) step(cur, target window) window {
+	c := f.config
+	full := window{0, 0, 1}
+	if target != full && math.Abs(target.x-cur.x) < c.Deadband &&
+		math.Abs(target.y-cur.y) < c.Deadband && math.Abs(target.size-cur.size) < c.Deadband {
+		return cur
+	}
+	move := func(from, to float64) float64 {
+		if math.Abs(to-from) <= c.Deadband {
+			return to
+		}
+		return from + clampf((to-from)*c.Smoothing, -c.MaxStep, c.MaxStep)
+	}
+	next := window{
+		x:    move(cur.x, target.x),
+		y:    move(cur.y, target.y),
+		size: move(cur.size, target.size),
+	}
+	next.size = clampf(next.size, 0, 1)
+	next.x = clampf(next.x, 0, 1-next.size)
+	next.y = clampf(next.y, 0, 1-next.size)
+	return next
+}
+
+// setWindow crops cfg to w; the full view clears the crop and zoom.
+func setWindow(cfg *Config, w window) {
+	if w == (window{0, 0, 1}) {
+		cfg.CropX, cfg.CropY, cfg.CropWidth, cfg.CropHeight = 0, 0, 0, 0
+		cfg.ZoomLevel = 1
+		return
+	}
+	cfg.SetView(w.region())
+}
+
+func (f *Framer) event(msg string) {
+	if msg != "" && f.OnEvent != nil {
+		f.OnEvent(msg)
+	}
+}
+
+func clampf(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, v))
+}
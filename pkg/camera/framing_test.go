@@ -0,0 +1,169 @@
+package camera
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestConfig_View(t *testing.T) {
+	cfg := Zoom2xConfig()
+	if got, want := cfg.View(), (Region{X: 0.25, Y: 0.25, W: 0.5, H: 0.5}); got != want {
+		t.Errorf("View() at 2x = %+v, want %+v", got, want)
+	}
+
+	cfg.SetView(Region{X: 0.5, Y: 0.25, W: 0.5, H: 0.5})
+	if cfg.CropX != 2304 || cfg.CropY != 648 || cfg.CropWidth != 2304 || cfg.CropHeight != 1296 {
+		t.Errorf("crop = %d,%d %dx%d, want 2304,648 2304x1296", cfg.CropX, cfg.CropY, cfg.CropWidth, cfg.CropHeight)
+	}
+	if errs := cfg.Validate(); len(errs) > 0 {
+		t.Errorf("Validate() = %v", errs)
+	}
+
+	// A 4:3 stream sees a narrower full view, centered on the sensor
+	legacy := LegacyConfig()
+	legacy.SetView(Region{W: 1, H: 1})
+	if legacy.CropX != 576 || legacy.CropWidth != 3456 || legacy.CropHeight != 2592 {
+		t.Errorf("4:3 full view = %d %dx%d, want 576 3456x2592", legacy.CropX, legacy.CropWidth, legacy.CropHeight)
+	}
+	if got := legacy.View(); got != (Region{W: 1, H: 1}) {
+		t.Errorf("View() = %+v, want the full view", got)
+	}
+
+	legacy.CropX = 2000
+	if errs := legacy.Validate(); len(errs) == 0 {
+		t.Error("Validate() accepted a crop off the sensor")
+	}
+}
+
+type framerHarness struct {
+	framer  *Framer
+	manager *Manager
+	clock   time.Time
+	events  []string
+}
+
+func newFramerHarness(cfg FramingConfig) *framerHarness {
+	h := &framerHarness{manager: NewManager(), clock: time.Unix(1000, 0)}
+	h.framer = NewFramer(h.manager, cfg)
+	h.framer.now = func() time.Time { return h.clock }
+	h.framer.OnEvent = func(msg string) { h.events = append(h.events, msg) }
+	return h
+}
+
+// see runs n updates 200ms apart with faces fixed in the full view,
+// as the camera would see them through the current crop.
+func (h *framerHarness) see(faces []Region, n int) {
+	for range n {
+		view := h.manager.GetConfig().View()
+		inFrame := make([]Region, len(faces))
+		for i, r := range faces {
+			inFrame[i] = Region{
+				X: (r.X - view.X) / view.W,
+				Y: (r.Y - view.Y) / view.H,
+				W: r.W / view.W,
+				H: r.H / view.H,
+			}
+		}
+		h.framer.update(inFrame, 0)
+		h.clock = h.clock.Add(200 * time.Millisecond)
+	}
+}
+
+func TestFramer_FramesFocusedFace(t *testing.T) {
+	h := newFramerHarness(DefaultFramingConfig())
+	face := Region{X: 0.7, Y: 0.3, W: 0.06, H: 0.1}
+
+	h.see([]Region{face}, 1)
+	first := h.manager.GetConfig().View()
+	if first.W < 0.95 {
+		t.Errorf("first step zoomed to %.2f, want a gradual move", first.W)
+	}
+
+	h.see([]Region{face}, 100)
+	view := h.manager.GetConfig().View()
+	if math.Abs(view.W-1/3.0) > 0.01 {
+		t.Errorf("window = %.3f, want 1/3 (MaxZoom)", view.W)
+	}
+	// Centered horizontally, on the eye line vertically
+	cx := (face.X + face.W/2 - view.X) / view.W
+	cy := (face.Y + face.H/2 - view.Y) / view.H
+	if math.Abs(cx-0.5) > 0.02 || math.Abs(cy-0.4) > 0.02 {
+		t.Errorf("face at (%.2f, %.2f) in the frame, want (0.5, 0.4)", cx, cy)
+	}
+	if len(h.events) != 1 || h.events[0] != "subject found, framing" {
+		t.Errorf("events = %q", h.events)
+	}
+
+	// Settled: no more config changes
+	before := h.manager.GetConfig()
+	h.see([]Region{face}, 5)
+	if h.manager.GetConfig() != before {
+		t.Error("crop kept moving on a still face")
+	}
+}
+
+func TestFramer_ZoomsOutWhenLost(t *testing.T) {
+	h := newFramerHarness(DefaultFramingConfig())
+	h.see([]Region{{X: 0.45, Y: 0.3, W: 0.06, H: 0.1}}, 60)
+	if h.manager.GetConfig().View().W > 0.5 {
+		t.Fatal("never zoomed in")
+	}
+
+	// Within LostTimeout the crop holds
+	zoomed := h.manager.GetConfig()
+	h.see(nil, 10)
+	if h.manager.GetConfig() != zoomed {
+		t.Error("crop moved before LostTimeout")
+	}
+
+	h.see(nil, 100)
+	cfg := h.manager.GetConfig()
+	if cfg.CropWidth != 0 || cfg.ZoomLevel != 1 {
+		t.Errorf("crop = %dx%d zoom %v, want cleared", cfg.CropWidth, cfg.CropHeight, cfg.ZoomLevel)
+	}
+	if last := h.events[len(h.events)-1]; last != "subject lost, zooming out" {
+		t.Errorf("last event = %q", last)
+	}
+}
+
+func TestFramer_Group(t *testing.T) {
+	cfg := DefaultFramingConfig()
+	cfg.Group = true
+	h := newFramerHarness(cfg)
+	faces := []Region{
+		{X: 0.2, Y: 0.4, W: 0.05, H: 0.09},
+		{X: 0.6, Y: 0.35, W: 0.05, H: 0.09},
+	}
+	h.see(faces, 100)
+
+	view := h.manager.GetConfig().View()
+	for _, f := range faces {
+		if f.X < view.X || f.X+f.W > view.X+view.W || f.Y < view.Y || f.Y+f.H > view.Y+view.H {
+			t.Errorf("face %+v outside the window %+v", f, view)
+		}
+	}
+	if view.W > 0.8 {
+		t.Errorf("window = %.2f, want zoomed in on the group", view.W)
+	}
+}
+
+func TestFramer_ManualOverride(t *testing.T) {
+	h := newFramerHarness(DefaultFramingConfig())
+	face := []Region{{X: 0.45, Y: 0.3, W: 0.06, H: 0.1}}
+	h.see(face, 5)
+
+	if err := h.manager.UpdateConfig(map[string]interface{}{"preset": PresetZoom2x}); err != nil {
+		t.Fatal(err)
+	}
+	h.see(face, 50)
+	if cfg := h.manager.GetConfig(); cfg.ZoomLevel != 2 || cfg.CropWidth != 0 {
+		t.Errorf("config = %+v, want the manual 2x zoom kept", cfg)
+	}
+
+	h.clock = h.clock.Add(5 * time.Minute)
+	h.see(face, 1)
+	if h.manager.GetConfig().CropWidth == 0 {
+		t.Error("framing didn't resume after ManualHold")
+	}
+}
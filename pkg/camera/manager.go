@@ -37,13 +37,19 @@ func (m *Manager) SetConfig(cfg Config) error {
 	}
 
 	m.mu.Lock()
+	prev := m.config
 	m.config = cfg
 	callback := m.OnConfigChange
 	m.mu.Unlock()
 
-	// Notify callback if set
+	// Notify callback if set; a config the camera rejected isn't kept
 	if callback != nil {
 		if err := callback(cfg); err != nil {
+			m.mu.Lock()
+			if m.config == cfg {
+				m.config = prev
+			}
+			m.mu.Unlock()
 			return fmt.Errorf("failed to apply config: %w", err)
 		}
 	}
@@ -0,0 +1,108 @@
+package camera
+
+import "image"
+
+// Region is a normalized image region (0-1, top-left origin), such as
+// a face detection box.
+type Region struct {
+	X, Y, W, H float64
+}
+
+// FrameStats summarizes the brightness of a frame.
+type FrameStats struct {
+	Histogram [256]int // Luma histogram of the sampled pixels
+	Samples   int      // Number of sampled pixels
+
+	Mean    float64 // Mean luma (0-255)
+	Clipped float64 // Fraction of samples at or above clipLuma
+	Dark    float64 // Fraction of samples at or below darkLuma
+
+	FaceLuma float64 // Mean luma inside the face regions
+	HasFace  bool    // Whether any face region had pixels
+}
+
+const (
+	clipLuma = 250
+	darkLuma = 16
+
+	statsColumns = 160 // Frame samples per row
+	faceColumns  = 32  // Face samples per row
+)
+
+// ComputeStats computes luma statistics for img, subsampling the frame
+// to about statsColumns pixels per row. faces are metered separately
+// into FaceLuma.
+func ComputeStats(img image.Image, faces []Region) FrameStats {
+	var s FrameStats
+	b := img.Bounds()
+	if b.Empty() {
+		return s
+	}
+	luma := lumaFunc(img)
+
+	step := max(1, b.Dx()/statsColumns)
+	sum := 0
+	for y := b.Min.Y + step/2; y < b.Max.Y; y += step {
+		for x := b.Min.X + step/2; x < b.Max.X; x += step {
+			l := luma(x, y)
+			s.Histogram[l]++
+			sum += int(l)
+		}
+	}
+	for l, n := range s.Histogram {
+		s.Samples += n
+		if l >= clipLuma {
+			s.Clipped += float64(n)
+		}
+		if l <= darkLuma {
+			s.Dark += float64(n)
+		}
+	}
+	if s.Samples > 0 {
+		n := float64(s.Samples)
+		s.Mean = float64(sum) / n
+		s.Clipped /= n
+		s.Dark /= n
+	}
+
+	faceSum, faceN := 0, 0
+	for _, f := range faces {
+		r := image.Rect(
+			b.Min.X+int(f.X*float64(b.Dx())),
+			b.Min.Y+int(f.Y*float64(b.Dy())),
+			b.Min.X+int((f.X+f.W)*float64(b.Dx())),
+			b.Min.Y+int((f.Y+f.H)*float64(b.Dy())),
+		).Intersect(b)
+		if r.Empty() {
+			continue
+		}
+		fstep := max(1, r.Dx()/faceColumns)
+		for y := r.Min.Y + fstep/2; y < r.Max.Y; y += fstep {
+			for x := r.Min.X + fstep/2; x < r.Max.X; x += fstep {
+				faceSum += int(luma(x, y))
+				faceN++
+			}
+		}
+	}
+	if faceN > 0 {
+		s.FaceLuma = float64(faceSum) / float64(faceN)
+		s.HasFace = true
+	}
+	return s
+}
+
+// lumaFunc returns a luma reader for img. Decoded JPEGs are YCbCr, so
+// the Y plane is read directly.
+func lumaFunc(img image.Image) func(x, y int) uint8 {
+	switch im := img.(type) {
+	case *image.YCbCr:
+		return func(x, y int) uint8 { return im.Y[im.YOffset(x, y)] }
+	case *image.Gray:
+		return func(x, y int) uint8 { return im.GrayAt(x, y).Y }
+	}
+	return func(x, y int) uint8 {
+		r, g, b, _ := img.At(x, y).RGBA()
+		// Same weights as color.GrayModel
+		return uint8((19595*r + 38470*g + 7471*b + 1<<15) >> 24)
+	}
+}
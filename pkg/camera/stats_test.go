@@ -0,0 +1,71 @@
+package camera
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+// testFrame returns a YCbCr frame of luma bg with a face-sized square
+// of luma face in the middle.
+func testFrame(bg, face uint8) *image.YCbCr {
+	img := image.NewYCbCr(image.Rect(0, 0, 640, 480), image.YCbCrSubsampleRatio420)
+	for y := 0; y < 480; y++ {
+		for x := 0; x < 640; x++ {
+			l := bg
+			if x >= 240 && x < 400 && y >= 160 && y < 320 {
+				l = face
+			}
+			img.Y[img.YOffset(x, y)] = l
+		}
+	}
+	return img
+}
+
+var testFace = Region{X: 0.375, Y: 1.0 / 3, W: 0.25, H: 1.0 / 3}
+
+func TestComputeStats(t *testing.T) {
+	s := ComputeStats(testFrame(255, 40), []Region{testFace})
+	if !s.HasFace || s.FaceLuma != 40 {
+		t.Errorf("FaceLuma = %v, %v, want 40", s.FaceLuma, s.HasFace)
+	}
+	// The face covers 1/12 of the frame
+	if want := (255*11 + 40) / 12.0; math.Abs(s.Mean-want) > 3 {
+		t.Errorf("Mean = %.1f, want %.1f", s.Mean, want)
+	}
+	if math.Abs(s.Clipped-11/12.0) > 0.02 {
+		t.Errorf("Clipped = %.3f, want %.3f", s.Clipped, 11/12.0)
+	}
+	if s.Dark != 0 {
+		t.Errorf("Dark = %v, want 0", s.Dark)
+	}
+	total := 0
+	for _, n := range s.Histogram {
+		total += n
+	}
+	if total != s.Samples || s.Histogram[255]+s.Histogram[40] != total {
+		t.Errorf("histogram has %d samples, want %d in bins 40 and 255", total, s.Samples)
+	}
+}
+
+func TestComputeStats_NoFace(t *testing.T) {
+	s := ComputeStats(testFrame(10, 10), []Region{{X: 1.5, Y: 0, W: 0.1, H: 0.1}})
+	if s.HasFace {
+		t.Error("HasFace = true for a region outside the frame")
+	}
+	if s.Mean != 10 || s.Dark != 1 {
+		t.Errorf("Mean = %v, Dark = %v, want 10 and 1", s.Mean, s.Dark)
+	}
+}
+
+func TestComputeStats_RGBA(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	for i := range img.Pix {
+		img.Pix[i] = 128
+	}
+	s := ComputeStats(img, nil)
+	if want := color.GrayModel.Convert(color.RGBA{128, 128, 128, 128}).(color.Gray).Y; s.Mean != float64(want) {
+		t.Errorf("Mean = %v, want %v", s.Mean, want)
+	}
+}
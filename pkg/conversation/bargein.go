@@ -0,0 +1,96 @@
+package conversation
+
+import (
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// ResponseCanceller stops the response being generated. Every Provider
+// satisfies it.
+type ResponseCanceller interface {
+	CancelResponse() error
+}
+
+// Playback is the agent's audio output. audio.Player satisfies it.
+type Playback interface {
+	IsSpeaking() bool
+	Cancel()
+}
+
+// positionReporter is implemented by playback that knows how much of the
+// current utterance has been heard (audio.Player with a native output).
+type positionReporter interface {
+	PlaybackPosition() time.Duration
+}
+
+// BargeIn lets the user interrupt the agent mid-sentence: when speech is
+// detected while the agent is talking, playback stops and the response
+// is cancelled. It needs echo-cancelled microphone audio so the agent's
+// own voice doesn't trigger it.
+type BargeIn struct {
+	// Canceller cancels the in-flight response.
+	Canceller ResponseCanceller
+
+	// Playback is stopped on barge-in.
+	Playback Playback
+
+	// Grace ignores speech this early in an utterance, while the echo
+	// canceller may still be converging. It needs Playback to report
+	// its position; otherwise it is ignored.
+	Grace time.Duration
+
+	// OnBargeIn is called after an interruption.
+	OnBargeIn func()
+
+	logger *slog.Logger
+	count  atomic.Int64
+}
+
+// NewBargeIn creates a barge-in handler.
+func NewBargeIn(canceller ResponseCanceller, playback Playback) *BargeIn {
+	return &BargeIn{
+		Canceller: canceller,
+		Playback:  playback,
+		Grace:     300 * time.Millisecond,
+		logger:    slog.Default().With("component", "conversation.bargein"),
+	}
+}
+
+// Attach routes the provider's interruption events to the handler.
+func (b *BargeIn) Attach(p Provider) {
+	p.OnInterruption(func() { b.Interrupt() })
+}
+
+// Interrupt handles user speech and reports whether the agent was cut
+// off.
+func (b *BargeIn) Interrupt() bool {
+	if b.Playback == nil || !b.Playback.IsSpeaking() {
+		return false
+	}
+	if pr, ok := b.Playback.(positionReporter); ok && b.Grace > 0 {
+		if pos := pr.PlaybackPosition(); pos < b.Grace {
+			b.logger.Debug("speech ignored during grace period", "position_ms", pos.Milliseconds())
+			return false
+		}
+	}
+
+	b.Playback.Cancel()
+	if b.Canceller != nil {
+		if err := b.Canceller.CancelResponse(); err != nil {
+			b.logger.Debug("cancel response failed", "error", err)
+		}
+	}
+	b.count.Add(1)
+	b.logger.Info("🛑 user barged in")
+
+	if b.OnBargeIn != nil {
+		b.OnBargeIn()
+	}
+	return true
+}
+
+// Count returns how many times the user has barged in.
+func (b *BargeIn) Count() int64 {
+	return b.count.Load()
+}
@@ -0,0 +1,57 @@
+package conversation
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type fakePlayback struct {
+	speaking  bool
+	position  time.Duration
+	cancelled int
+}
+
+func (p *fakePlayback) IsSpeaking() bool                { return p.speaking }
+func (p *fakePlayback) Cancel()                         { p.cancelled++; p.speaking = false }
+func (p *fakePlayback) PlaybackPosition() time.Duration { return p.position }
+
+func TestBargeIn(t *testing.T) {
+	tests := []struct {
+		name     string
+		speaking bool
+		position time.Duration
+		want     bool
+	}{
+		{"interrupts speech", true, time.Second, true},
+		{"ignores speech when silent", false, 0, false},
+		{"ignores speech in grace period", true, 100 * time.Millisecond, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := NewMock()
+			provider.Connect(context.Background())
+			playback := &fakePlayback{speaking: tt.speaking, position: tt.position}
+
+			b := NewBargeIn(provider, playback)
+			called := false
+			b.OnBargeIn = func() { called = true }
+			b.Attach(provider)
+
+			provider.SimulateInterruption()
+
+			if provider.CancelCalled != tt.want {
+				t.Errorf("CancelCalled = %v, want %v", provider.CancelCalled, tt.want)
+			}
+			if got := playback.cancelled == 1; got != tt.want {
+				t.Errorf("playback cancelled = %v, want %v", got, tt.want)
+			}
+			if called != tt.want {
+				t.Errorf("OnBargeIn called = %v, want %v", called, tt.want)
+			}
+			if want := map[bool]int64{true: 1, false: 0}[tt.want]; b.Count() != want {
+				t.Errorf("Count() = %d, want %d", b.Count(), want)
+			}
+		})
+	}
+}
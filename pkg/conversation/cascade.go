@@ -0,0 +1,893 @@
+package conversation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/teslashibe/go-reachy/pkg/audio"
+	"github.com/teslashibe/go-reachy/pkg/tts"
+)
+
+const (
+	cascadeFrameMs       = 20               // VAD frame length
+	cascadeStartFrames   = 3                // Consecutive speech frames to start an utterance
+	cascadeMaxUtterance  = 30 * time.Second // Force an endpoint on very long speech
+	cascadeMaxToolRounds = 5                // Tool call round trips per turn
+	cascadeMaxHistory    = 40               // Chat messages kept between turns
+	cascadeToolTimeout   = 30 * time.Second // Wait for SubmitToolResult
+)
+
+// CascadeComponents are the pluggable stages of a Cascade.
+type CascadeComponents struct {
+	// VAD detects speech in incoming audio. Nil uses an EnergyVAD.
+	VAD VAD
+
+	// STT transcribes each utterance. Required.
+	STT Transcriber
+
+	// LLM produces replies. Nil uses a ChatCompletions client built
+	// from the config's BaseURL, APIKey and Model.
+	LLM ChatModel
+
+	// TTS speaks replies. Required; configure it for PCM or μ-law output.
+	TTS tts.Provider
+}
+
+// Cascade implements Provider by chaining local endpointing, speech-to-text,
+// a chat-completions model and text-to-speech. Every stage is swappable,
+// so the whole pipeline can run offline (e.g. whisper.cpp + Ollama + Piper).
+//
+// Audio in is PCM16 mono at InputSampleRate; audio out is PCM16 mono at
+// OutputSampleRate (TTS audio is decoded and resampled when rates differ).
+// Tools follow the OpenAI provider: the OnToolCall callback takes
+// precedence, otherwise the tool's Handler runs.
+type Cascade struct {
+	config *Config
+	logger *slog.Logger
+
+	vad VAD
+	stt Transcriber
+	llm ChatModel
+	tts tts.Provider
+
+	mu        sync.RWMutex
+	state     ConnectionState
+	session   SessionOptions
+	tools     []Tool
+	history   []ChatMessage
+	ctx       context.Context
+	cancelCtx context.CancelFunc
+	connected time.Time
+
+	// Endpointing state, owned by SendAudio
+	audioMu    sync.Mutex
+	pending    []byte
+	preroll    [][]byte
+	utterance  []byte
+	inSpeech   bool
+	speechRun  int
+	silenceRun int
+
+	// Current turn and playback estimate for barge-in
+	turnMu        sync.Mutex
+	turnCancel    context.CancelFunc
+	turnSeq       uint64
+	speakingUntil time.Time
+
+	toolMu      sync.Mutex
+	toolResults map[string]chan string
+	toolSeq     atomic.Int64
+
+	// Callbacks
+	onAudio        func([]byte)
+	onAudioDone    func()
+	onTranscript   func(role, text string, isFinal bool)
+	onToolCall     func(id, name string, args map[string]any)
+	onError        func(error)
+	onInterruption func()
+	onSpeechStop   func()
+
+	// Atomic counters for metrics
+	messagesSent       atomic.Int64
+	messagesReceived   atomic.Int64
+	audioBytesSent     atomic.Int64
+	audioBytesReceived atomic.Int64
+	toolCalls          atomic.Int64
+	errors             atomic.Int64
+}
+
+// NewCascade creates a cascaded conversation provider. Unlike the
+// speech-to-speech providers no API key is required, since every stage
+// may be local.
+//
+//	provider, _ := NewCascade(CascadeComponents{
+//	    STT: NewWhisperTranscriber("http://localhost:8000/v1", "", "base.en"),
+//	    TTS: piper,
+//	}, WithBaseURL("http://localhost:11434/v1"), WithModel("llama3.1"))
+func NewCascade(c CascadeComponents, opts ...Option) (*Cascade, error) {
+	cfg := DefaultConfig()
+	cfg.Apply(opts...)
+
+	if c.STT == nil || c.TTS == nil {
+		return nil, ErrMissingComponent
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	if c.VAD == nil {
+		c.VAD = NewEnergyVAD()
+	}
+	if c.LLM == nil {
+		c.LLM = NewChatCompletions(cfg.BaseURL, cfg.APIKey, cfg.Model)
+	}
+
+	return &Cascade{
+		config:      cfg,
+		logger:      cfg.Logger.With("component", "conversation.cascade"),
+		vad:         c.VAD,
+		stt:         c.STT,
+		llm:         c.LLM,
+		tts:         c.TTS,
+		state:       StateDisconnected,
+		tools:       append([]Tool(nil), cfg.Tools...),
+		toolResults: make(map[string]chan string),
+	}, nil
+}
+
+// Connect starts the session. No network is touched until the first
+// utterance; the components are expected to be ready.
+func (c *Cascade) Connect(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state == StateConnected {
+		return ErrAlreadyConnected
+	}
+
+	c.ctx, c.cancelCtx = context.WithCancel(context.Background())
+	c.state = StateConnected
+	c.connected = time.Now()
+	c.logger.Info("cascade session started")
+	return nil
+}
+
+// Close ends the session and cancels any turn in progress. The
+// components are owned by the caller and are not closed.
+func (c *Cascade) Close() error {
+	c.mu.Lock()
+	if c.state == StateDisconnected {
+		c.mu.Unlock()
+		return nil
+	}
+	if c.cancelCtx != nil {
+		c.cancelCtx()
+	}
+	c.state = StateDisconnected
+	c.mu.Unlock()
+
+	c.audioMu.Lock()
+	c.resetEndpointing()
+	c.audioMu.Unlock()
+
+	c.logger.Info("cascade session closed")
+	return nil
+}
+
+// IsConnected returns true if the session is active.
+func (c *Cascade) IsConnected() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.state == StateConnected
+}
+
+// SendAudio feeds PCM16 mono audio at InputSampleRate. Utterances are
+// endpointed locally and each one starts a new turn.
+func (c *Cascade) SendAudio(pcm []byte) error {
+	if !c.IsConnected() {
+		return ErrNotConnected
+	}
+	c.audioBytesSent.Add(int64(len(pcm)))
+
+	frameBytes := c.config.InputSampleRate * cascadeFrameMs / 1000 * 2
+	if frameBytes <= 0 {
+		return ErrInvalidAudio
+	}
+
+	var started bool
+	var utterances [][]byte
+
+	c.audioMu.Lock()
+	c.pending = append(c.pending, pcm...)
+	for len(c.pending) >= frameBytes {
+		frame := c.pending[:frameBytes:frameBytes]
+		c.pending = c.pending[frameBytes:]
+		s, u := c.processFrame(frame)
+		started = started || s
+		if u != nil {
+			utterances = append(utterances, u)
+		}
+	}
+	c.audioMu.Unlock()
+
+	// Act outside the lock so callbacks may call back into the provider
+	if started {
+		c.handleSpeechStart()
+	}
+	for _, u := range utterances {
+		c.emitSpeechStopped()
+		c.startTurn(u)
+	}
+	return nil
+}
+
+// ClearAudio drops the utterance in progress without answering it.
+func (c *Cascade) ClearAudio() error {
+	if !c.IsConnected() {
+		return ErrNotConnected
+	}
+	c.audioMu.Lock()
+	c.resetEndpointing()
+	c.vad.Reset()
+	c.audioMu.Unlock()
+	return nil
+}
+
+// Commit ends the current utterance immediately, as with push-to-talk or
+// when TurnDetection is "none" or "client_vad".
+func (c *Cascade) Commit() error {
+	if !c.IsConnected() {
+		return ErrNotConnected
+	}
+
+	c.audioMu.Lock()
+	u := c.utterance
+	if !c.inSpeech {
+		u = nil
+	}
+	c.resetEndpointing()
+	c.audioMu.Unlock()
+
+	if len(u) > 0 {
+		c.startTurn(u)
+	}
+	return nil
+}
+
+// processFrame runs the VAD on one frame. It reports whether speech
+// started and returns a finished utterance, if any. Caller holds audioMu.
+func (c *Cascade) processFrame(frame []byte) (started bool, utterance []byte) {
+	td := c.turnDetection()
+	auto := td.Automatic()
+	speech := c.vad.IsSpeech(frame)
+
+	if !c.inSpeech {
+		prefixFrames := 0
+		if td != nil {
+			prefixFrames = td.PrefixPaddingMs / cascadeFrameMs
+		}
+		c.preroll = append(c.preroll, frame)
+		if over := len(c.preroll) - (prefixFrames + cascadeStartFrames); over > 0 {
+			c.preroll = c.preroll[over:]
+		}
+
+		if !speech {
+			c.speechRun = 0
+			return false, nil
+		}
+		c.speechRun++
+		if c.speechRun < cascadeStartFrames {
+			return false, nil
+		}
+
+		c.inSpeech = true
+		c.silenceRun = 0
+		c.utterance = nil
+		for _, f := range c.preroll {
+			c.utterance = append(c.utterance, f...)
+		}
+		c.preroll = nil
+		return true, nil
+	}
+
+	c.utterance = append(c.utterance, frame...)
+	if speech {
+		c.silenceRun = 0
+	} else {
+		c.silenceRun++
+	}
+
+	maxBytes := int(cascadeMaxUtterance.Seconds()) * c.config.InputSampleRate * 2
+	ended := len(c.utterance) >= maxBytes
+	if auto && c.silenceRun*cascadeFrameMs >= td.SilenceDurationMs {
+		ended = true
+	}
+	if !ended {
+		return false, nil
+	}
+
+	utterance = c.utterance
+	c.resetEndpointing()
+	c.vad.Reset()
+	return false, utterance
+}
+
+// resetEndpointing clears the utterance state. Caller holds audioMu.
+func (c *Cascade) resetEndpointing() {
+	c.pending = nil
+	c.preroll = nil
+	c.utterance = nil
+	c.inSpeech = false
+	c.speechRun = 0
+	c.silenceRun = 0
+}
+
+// turnDetection returns the effective turn detection settings.
+func (c *Cascade) turnDetection() *TurnDetection {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.session.TurnDetection != nil {
+		return c.session.TurnDetection
+	}
+	return c.config.TurnDetection
+}
+
+// handleSpeechStart interrupts the agent if it is replying or its audio
+// is still playing.
+func (c *Cascade) handleSpeechStart() {
+	c.turnMu.Lock()
+	busy := c.turnCancel != nil || time.Now().Before(c.speakingUntil)
+	if c.turnCancel != nil {
+		c.turnCancel()
+		c.turnCancel = nil
+	}
+	c.speakingUntil = time.Time{}
+	c.turnMu.Unlock()
+
+	if busy {
+		c.logger.Info("⚡ user interrupted")
+		c.emitInterruption()
+	}
+}
+
+// startTurn runs a new turn for the utterance, replacing any current one.
+func (c *Cascade) startTurn(pcm []byte) {
+	c.mu.RLock()
+	parent := c.ctx
+	c.mu.RUnlock()
+	if parent == nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(parent)
+
+	c.turnMu.Lock()
+	if c.turnCancel != nil {
+		c.turnCancel()
+	}
+	c.turnSeq++
+	seq := c.turnSeq
+	c.turnCancel = cancel
+	c.turnMu.Unlock()
+
+	go func() {
+		defer c.finishTurn(seq, cancel)
+		c.runTurn(ctx, pcm)
+	}()
+}
+
+// finishTurn clears the turn if it is still the current one.
+func (c *Cascade) finishTurn(seq uint64, cancel context.CancelFunc) {
+	cancel()
+	c.turnMu.Lock()
+	if c.turnSeq == seq {
+		c.turnCancel = nil
+	}
+	c.turnMu.Unlock()
+}
+
+// runTurn transcribes, replies and speaks.
+func (c *Cascade) runTurn(ctx context.Context, pcm []byte) {
+	start := time.Now()
+
+	c.messagesSent.Add(1)
+	text, err := c.stt.Transcribe(ctx, pcm, c.config.InputSampleRate)
+	if err != nil {
+		c.fail(ctx, "transcription failed", err)
+		return
+	}
+	c.messagesReceived.Add(1)
+	if text == "" {
+		return
+	}
+	c.logger.Info("🎤 user transcript", "text", text, "stt_ms", time.Since(start).Milliseconds())
+	c.emitTranscript("user", text, true)
+
+	reply, err := c.respond(ctx, text)
+	if err != nil {
+		c.fail(ctx, "chat failed", err)
+		return
+	}
+	if reply == "" {
+		return
+	}
+	c.logger.Info("🤖 agent reply", "text", reply, "turn_ms", time.Since(start).Milliseconds())
+	c.speak(ctx, reply)
+}
+
+// fail reports a stage error unless the turn was cancelled.
+func (c *Cascade) fail(ctx context.Context, what string, err error) {
+	if ctx.Err() != nil {
+		return
+	}
+	c.logger.Error(what, "error", err)
+	c.errors.Add(1)
+	c.emitError(fmt.Errorf("conversation.cascade: %s: %w", what, err))
+}
+
+// respond runs the chat model, resolving tool calls, and returns the
+// final reply. The turn is committed to history only when it completes,
+// so a cancelled turn never leaves unanswered tool calls behind.
+func (c *Cascade) respond(ctx context.Context, text string) (string, error) {
+	user := ChatMessage{Role: "user", Content: text}
+
+	c.mu.RLock()
+	opts := c.session
+	tools := append([]Tool(nil), c.tools...)
+	messages := make([]ChatMessage, 0, len(c.history)+2)
+	if prompt := c.systemPrompt(); prompt != "" {
+		messages = append(messages, ChatMessage{Role: "system", Content: prompt})
+	}
+	messages = append(messages, c.history...)
+	c.mu.RUnlock()
+
+	messages = append(messages, user)
+	turn := []ChatMessage{user}
+
+	req := ChatRequest{
+		Tools:       tools,
+		Temperature: opts.Temperature,
+		MaxTokens:   opts.MaxResponseTokens,
+	}
+	if req.Temperature == 0 {
+		req.Temperature = c.config.Temperature
+	}
+	if req.MaxTokens == 0 {
+		req.MaxTokens = opts.MaxTokens
+	}
+	if req.MaxTokens == 0 {
+		req.MaxTokens = c.config.MaxResponseTokens
+	}
+
+	for round := 0; round < cascadeMaxToolRounds; round++ {
+		req.Messages = messages
+
+		c.messagesSent.Add(1)
+		msg, err := c.llm.Complete(ctx, req)
+		if err != nil {
+			c.commitHistory(turn[:1])
+			return "", err
+		}
+		c.messagesReceived.Add(1)
+
+		// Some local servers omit call IDs; the tool replies need them
+		for i := range msg.ToolCalls {
+			if msg.ToolCalls[i].ID == "" {
+				msg.ToolCalls[i].ID = fmt.Sprintf("call_%d", c.toolSeq.Add(1))
+			}
+		}
+		messages = append(messages, *msg)
+		turn = append(turn, *msg)
+
+		if len(msg.ToolCalls) == 0 {
+			c.commitHistory(turn)
+			return strings.TrimSpace(msg.Content), nil
+		}
+
+		for _, call := range msg.ToolCalls {
+			result := c.callTool(ctx, call, tools)
+			if ctx.Err() != nil {
+				c.commitHistory(turn[:1])
+				return "", ctx.Err()
+			}
+			out := ChatMessage{Role: "tool", Content: result, ToolCallID: call.ID}
+			messages = append(messages, out)
+			turn = append(turn, out)
+		}
+	}
+
+	c.commitHistory(turn[:1])
+	return "", fmt.Errorf("%w: more than %d tool rounds", ErrToolCallFailed, cascadeMaxToolRounds)
+}
+
+// systemPrompt returns the effective instructions. Caller holds c.mu.
+func (c *Cascade) systemPrompt() string {
+	if c.session.SystemPrompt != "" {
+		return c.session.SystemPrompt
+	}
+	return c.config.SystemPrompt
+}
+
+// commitHistory appends messages to the history, trimming the oldest
+// turns so the history always starts with a user message.
+func (c *Cascade) commitHistory(msgs []ChatMessage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.history = append(c.history, msgs...)
+	if len(c.history) <= cascadeMaxHistory {
+		return
+	}
+	cut := len(c.history) - cascadeMaxHistory
+	for cut < len(c.history) && c.history[cut].Role != "user" {
+		cut++
+	}
+	c.history = append([]ChatMessage(nil), c.history[cut:]...)
+}
+
+// callTool runs one tool call and returns its result text.
+func (c *Cascade) callTool(ctx context.Context, call ChatToolCall, tools []Tool) string {
+	args := map[string]any{}
+	if call.Arguments != "" {
+		if err := json.Unmarshal([]byte(call.Arguments), &args); err != nil {
+			c.logger.Warn("failed to parse tool arguments", "tool", call.Name, "error", err)
+		}
+	}
+	c.logger.Info("🔧 tool call", "tool", call.Name, "call_id", call.ID)
+	c.toolCalls.Add(1)
+
+	c.mu.RLock()
+	fn := c.onToolCall
+	c.mu.RUnlock()
+
+	if fn != nil {
+		ch := make(chan string, 1)
+		c.toolMu.Lock()
+		c.toolResults[call.ID] = ch
+		c.toolMu.Unlock()
+		defer func() {
+			c.toolMu.Lock()
+			delete(c.toolResults, call.ID)
+			c.toolMu.Unlock()
+		}()
+
+		fn(call.ID, call.Name, args)
+
+		select {
+		case result := <-ch:
+			return result
+		case <-ctx.Done():
+			return ""
+		case <-time.After(cascadeToolTimeout):
+			return "Error: tool timed out"
+		}
+	}
+
+	for _, t := range tools {
+		if t.Name != call.Name {
+			continue
+		}
+		if t.Handler == nil {
+			break
+		}
+		result, err := t.Handler(args)
+		if err != nil {
+			return "Error: " + err.Error()
+		}
+		return result
+	}
+	return fmt.Sprintf("Error: unknown tool %q", call.Name)
+}
+
+// speak synthesises the reply sentence by sentence so playback can start
+// before the whole reply is rendered. Like the Realtime API, each
+// sentence's text is sent as a partial agent transcript with its audio,
+// and the whole reply as the final one.
+func (c *Cascade) speak(ctx context.Context, text string) {
+	for i, sentence := range splitSentences(text) {
+		if ctx.Err() != nil {
+			return
+		}
+
+		c.messagesSent.Add(1)
+		result, err := c.tts.Synthesize(ctx, sentence)
+		if err != nil {
+			c.fail(ctx, "synthesis failed", err)
+			return
+		}
+		c.messagesReceived.Add(1)
+		if ctx.Err() != nil {
+			return
+		}
+
+		pcm, err := c.toOutput(result)
+		if err != nil {
+			c.fail(ctx, "synthesis failed", err)
+			return
+		}
+		if len(pcm) == 0 {
+			continue
+		}
+		c.audioBytesReceived.Add(int64(len(pcm)))
+
+		// Extend the playback estimate used to detect barge-in
+		dur := time.Duration(len(pcm)/2) * time.Second / time.Duration(c.config.OutputSampleRate)
+		c.turnMu.Lock()
+		if now := time.Now(); c.speakingUntil.Before(now) {
+			c.speakingUntil = now
+		}
+		c.speakingUntil = c.speakingUntil.Add(dur)
+		c.turnMu.Unlock()
+
+		if i > 0 {
+			sentence = " " + sentence
+		}
+		c.emitTranscript("agent", sentence, false)
+		c.emitAudio(pcm)
+	}
+
+	if ctx.Err() == nil {
+		c.emitTranscript("agent", text, true)
+		c.emitAudioDone()
+	}
+}
+
+// toOutput converts TTS audio to PCM16 at OutputSampleRate. PCM and
+// μ-law are decoded; compressed encodings (MP3, Opus) are refused, since
+// playing them as PCM would only make noise.
+func (c *Cascade) toOutput(result *tts.AudioResult) ([]byte, error) {
+	if result == nil {
+		return nil, nil
+	}
+
+	rate := result.Format.SampleRate
+	if rate == 0 {
+		rate = tts.SampleRateFromEncoding(result.Format.Encoding)
+	}
+	var samples []int16
+	switch {
+	case strings.HasPrefix(string(result.Format.Encoding), "pcm_"):
+		if rate == c.config.OutputSampleRate {
+			return result.Audio, nil
+		}
+		samples = audio.ConvertPCM16ToInt16(result.Audio)
+	case result.Format.Encoding == tts.EncodingULaw:
+		samples = audio.ConvertULawToInt16(result.Audio)
+	default:
+		return nil, fmt.Errorf("%w: TTS returned %q; configure it for PCM or μ-law output",
+			ErrInvalidAudio, result.Format.Encoding)
+	}
+
+	samples = audio.Resample(samples, rate, c.config.OutputSampleRate)
+	return audio.ConvertInt16ToPCM16(samples), nil
+}
+
+// splitSentences breaks text at sentence-ending punctuation.
+func splitSentences(text string) []string {
+	var sentences []string
+	start := 0
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '.', '!', '?', '\n':
+			if text[i] != '\n' && i+1 < len(text) && text[i+1] != ' ' && text[i+1] != '\n' {
+				continue // e.g. "3.5" or "e.g."
+			}
+			if s := strings.TrimSpace(text[start : i+1]); s != "" {
+				sentences = append(sentences, s)
+			}
+			start = i + 1
+		}
+	}
+	if s := strings.TrimSpace(text[start:]); s != "" {
+		sentences = append(sentences, s)
+	}
+	return sentences
+}
+
+// ConfigureSession sets the system prompt, tools, sampling and turn
+// detection. Non-empty Tools replace the registered tools.
+func (c *Cascade) ConfigureSession(opts SessionOptions) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.session = opts
+	if len(opts.Tools) > 0 {
+		c.tools = append([]Tool(nil), opts.Tools...)
+	}
+	return nil
+}
+
+// RegisterTool registers a tool.
+func (c *Cascade) RegisterTool(tool Tool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tools = append(c.tools, tool)
+}
+
+// CancelResponse cancels the turn in progress.
+func (c *Cascade) CancelResponse() error {
+	if !c.IsConnected() {
+		return ErrNotConnected
+	}
+
+	c.turnMu.Lock()
+	defer c.turnMu.Unlock()
+	if c.turnCancel != nil {
+		c.turnCancel()
+		c.turnCancel = nil
+	}
+	c.speakingUntil = time.Time{}
+	return nil
+}
+
+// SubmitToolResult delivers the result of a tool call to the waiting turn.
+func (c *Cascade) SubmitToolResult(callID, result string) error {
+	if !c.IsConnected() {
+		return ErrNotConnected
+	}
+
+	c.toolMu.Lock()
+	ch, ok := c.toolResults[callID]
+	c.toolMu.Unlock()
+	if !ok {
+		return fmt.Errorf("conversation.cascade: %w: no pending call %q", ErrToolCallFailed, callID)
+	}
+
+	select {
+	case ch <- result:
+	default: // Already answered
+	}
+	return nil
+}
+
+// Capabilities returns provider capabilities.
+func (c *Cascade) Capabilities() Capabilities {
+	var models []string
+	if cc, ok := c.llm.(*ChatCompletions); ok {
+		models = []string{cc.Model}
+	}
+	return Capabilities{
+		SupportsToolCalls:    true,
+		SupportsInterruption: true,
+		SupportsCustomVoice:  true,
+		SupportsStreaming:    true,
+		InputSampleRate:      c.config.InputSampleRate,
+		OutputSampleRate:     c.config.OutputSampleRate,
+		SupportedModels:      models,
+	}
+}
+
+// Metrics returns a snapshot of usage statistics. Messages count requests
+// to and responses from the STT, LLM and TTS stages.
+func (c *Cascade) Metrics() Metrics {
+	c.mu.RLock()
+	connected := c.connected
+	c.mu.RUnlock()
+
+	return Metrics{
+		ConnectionTime:     connected,
+		MessagesSent:       c.messagesSent.Load(),
+		MessagesReceived:   c.messagesReceived.Load(),
+		AudioBytesSent:     c.audioBytesSent.Load(),
+		AudioBytesReceived: c.audioBytesReceived.Load(),
+		ToolCallsExecuted:  c.toolCalls.Load(),
+		Errors:             c.errors.Load(),
+	}
+}
+
+// OnAudio sets the audio callback.
+func (c *Cascade) OnAudio(fn func([]byte)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onAudio = fn
+}
+
+// OnAudioDone sets the audio done callback.
+func (c *Cascade) OnAudioDone(fn func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onAudioDone = fn
+}
+
+// OnTranscript sets the transcript callback. Roles are "user" and "agent".
+func (c *Cascade) OnTranscript(fn func(role, text string, isFinal bool)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onTranscript = fn
+}
+
+// OnToolCall sets the tool call callback.
+func (c *Cascade) OnToolCall(fn func(id, name string, args map[string]any)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onToolCall = fn
+}
+
+// OnError sets the error callback.
+func (c *Cascade) OnError(fn func(error)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onError = fn
+}
+
+// OnInterruption sets the interruption callback. It fires when the user
+// starts speaking while a reply is being prepared or played.
+func (c *Cascade) OnInterruption(fn func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onInterruption = fn
+}
+
+// OnSpeechStopped sets the callback for an utterance being endpointed by
+// the VAD, just before its turn starts.
+func (c *Cascade) OnSpeechStopped(fn func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onSpeechStop = fn
+}
+
+// Emit helpers
+
+func (c *Cascade) emitAudio(pcm []byte) {
+	c.mu.RLock()
+	fn := c.onAudio
+	c.mu.RUnlock()
+	if fn != nil {
+		fn(pcm)
+	}
+}
+
+func (c *Cascade) emitAudioDone() {
+	c.mu.RLock()
+	fn := c.onAudioDone
+	c.mu.RUnlock()
+	if fn != nil {
+		fn()
+	}
+}
+
+func (c *Cascade) emitTranscript(role, text string, isFinal bool) {
+	c.mu.RLock()
+	fn := c.onTranscript
+	c.mu.RUnlock()
+	if fn != nil {
+		fn(role, text, isFinal)
+	}
+}
+
+func (c *Cascade) emitInterruption() {
+	c.mu.RLock()
+	fn := c.onInterruption
+	c.mu.RUnlock()
+	if fn != nil {
+		fn()
+	}
+}
+
+func (c *Cascade) emitSpeechStopped() {
+	c.mu.RLock()
+	fn := c.onSpeechStop
+	c.mu.RUnlock()
+	if fn != nil {
+		fn()
+	}
+}
+
+func (c *Cascade) emitError(err error) {
+	c.mu.RLock()
+	fn := c.onError
+	c.mu.RUnlock()
+	if fn != nil {
+		fn(err)
+	}
+}
+
+// Ensure Cascade implements Provider
+var (
+	_ Provider  = (*Cascade)(nil)
+	_ Committer = (*Cascade)(nil)
+)
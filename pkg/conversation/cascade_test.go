@@ -0,0 +1,606 @@
+package conversation
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/teslashibe/go-reachy/pkg/tts"
+)
+
+type funcTranscriber func(ctx context.Context, pcm []byte, sampleRate int) (string, error)
+
+func (f funcTranscriber) Transcribe(ctx context.Context, pcm []byte, sampleRate int) (string, error) {
+	return f(ctx, pcm, sampleRate)
+}
+
+type funcChat func(ctx context.Context, req ChatRequest) (*ChatMessage, error)
+
+func (f funcChat) Complete(ctx context.Context, req ChatRequest) (*ChatMessage, error) {
+	return f(ctx, req)
+}
+
+// tone returns PCM16 at 16kHz: a loud sine, or silence when amp is 0.
+func tone(d time.Duration, amp float64) []byte {
+	n := int(d.Seconds() * 16000)
+	pcm := make([]byte, n*2)
+	for i := 0; i < n; i++ {
+		s := int16(amp * 32767 * math.Sin(2*math.Pi*300*float64(i)/16000))
+		binary.LittleEndian.PutUint16(pcm[2*i:], uint16(s))
+	}
+	return pcm
+}
+
+// speak feeds an utterance followed by enough silence to endpoint it.
+func speak(t *testing.T, c *Cascade) {
+	t.Helper()
+	for _, chunk := range [][]byte{tone(200*time.Millisecond, 0), tone(400*time.Millisecond, 0.5), tone(600*time.Millisecond, 0)} {
+		if err := c.SendAudio(chunk); err != nil {
+			t.Fatalf("SendAudio: %v", err)
+		}
+	}
+}
+
+type cascadeRecorder struct {
+	mu          sync.Mutex
+	transcripts []string
+	audio       int
+	done        chan struct{}
+	interrupts  int
+	errs        []error
+}
+
+func recordCascade(c *Cascade) *cascadeRecorder {
+	r := &cascadeRecorder{done: make(chan struct{}, 10)}
+	c.OnTranscript(func(role, text string, isFinal bool) {
+		r.mu.Lock()
+		r.transcripts = append(r.transcripts, role+":"+text)
+		r.mu.Unlock()
+	})
+	c.OnAudio(func(pcm []byte) { r.mu.Lock(); r.audio += len(pcm); r.mu.Unlock() })
+	c.OnAudioDone(func() { r.done <- struct{}{} })
+	c.OnInterruption(func() { r.mu.Lock(); r.interrupts++; r.mu.Unlock() })
+	c.OnError(func(err error) { r.mu.Lock(); r.errs = append(r.errs, err); r.mu.Unlock() })
+	return r
+}
+
+func (r *cascadeRecorder) waitDone(t *testing.T) {
+	t.Helper()
+	select {
+	case <-r.done:
+	case <-time.After(2 * time.Second):
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		t.Fatalf("audio never finished; transcripts %v errors %v", r.transcripts, r.errs)
+	}
+}
+
+func newTestCascade(t *testing.T, stt Transcriber, llm ChatModel, opts ...Option) *Cascade {
+	c, err := NewCascade(CascadeComponents{STT: stt, LLM: llm, TTS: tts.NewMock()}, opts...)
+	if err != nil {
+		t.Fatalf("NewCascade: %v", err)
+	}
+	if err := c.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestNewCascadeValidation(t *testing.T) {
+	stt := funcTranscriber(func(context.Context, []byte, int) (string, error) { return "", nil })
+
+	tests := []struct {
+		name string
+		c    CascadeComponents
+		want error
+	}{
+		{"missing STT", CascadeComponents{TTS: tts.NewMock()}, ErrMissingComponent},
+		{"missing TTS", CascadeComponents{STT: stt}, ErrMissingComponent},
+		{"defaults VAD and LLM", CascadeComponents{STT: stt, TTS: tts.NewMock()}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewCascade(tt.c)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("NewCascade() error = %v, want %v", err, tt.want)
+			}
+			if err == nil && (c.vad == nil || c.llm == nil) {
+				t.Error("VAD/LLM defaults not set")
+			}
+		})
+	}
+}
+
+func TestCascade_Turn(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		sttBytes int
+		requests []ChatRequest
+	)
+	stt := funcTranscriber(func(ctx context.Context, pcm []byte, rate int) (string, error) {
+		mu.Lock()
+		sttBytes = len(pcm)
+		mu.Unlock()
+		if rate != 16000 {
+			t.Errorf("sample rate = %d, want 16000", rate)
+		}
+		return "hello eva", nil
+	})
+	llm := funcChat(func(ctx context.Context, req ChatRequest) (*ChatMessage, error) {
+		mu.Lock()
+		requests = append(requests, req)
+		mu.Unlock()
+		return &ChatMessage{Role: "assistant", Content: "Hi there. How are you?"}, nil
+	})
+
+	c := newTestCascade(t, stt, llm, WithSystemPrompt("You are Eva."))
+	r := recordCascade(c)
+	var stops atomic.Int32
+	c.OnSpeechStopped(func() { stops.Add(1) })
+
+	speak(t, c)
+	r.waitDone(t)
+	if n := stops.Load(); n != 1 {
+		t.Errorf("speech stops = %d, want 1", n)
+	}
+
+	r.mu.Lock()
+	want := []string{"user:hello eva", "agent:Hi there.", "agent: How are you?", "agent:Hi there. How are you?"}
+	if strings.Join(r.transcripts, "|") != strings.Join(want, "|") {
+		t.Errorf("transcripts = %v, want %v", r.transcripts, want)
+	}
+	// Mock TTS renders 24kHz; output is resampled to 16kHz
+	if wantAudio := len("Hi there.")*960*2/3 + len("How are you?")*960*2/3; r.audio != wantAudio {
+		t.Errorf("audio bytes = %d, want %d", r.audio, wantAudio)
+	}
+	r.mu.Unlock()
+
+	mu.Lock()
+	defer mu.Unlock()
+	// 400ms of speech plus prefix padding and trailing silence
+	if sttBytes < 400*32 {
+		t.Errorf("utterance = %d bytes, want at least %d", sttBytes, 400*32)
+	}
+	if len(requests) != 1 {
+		t.Fatalf("chat requests = %d, want 1", len(requests))
+	}
+	msgs := requests[0].Messages
+	if len(msgs) != 2 || msgs[0].Role != "system" || msgs[1].Content != "hello eva" {
+		t.Errorf("messages = %+v", msgs)
+	}
+
+	m := c.Metrics()
+	if m.AudioBytesSent == 0 || m.AudioBytesReceived != int64(r.audio) || m.MessagesSent != 4 {
+		t.Errorf("metrics = %+v", m)
+	}
+}
+
+// With client_vad the caller endpoints: silence alone must not end the
+// turn, Commit must.
+func TestCascade_ClientVAD(t *testing.T) {
+	var turns atomic.Int32
+	stt := funcTranscriber(func(ctx context.Context, pcm []byte, rate int) (string, error) {
+		turns.Add(1)
+		return "hello eva", nil
+	})
+	llm := funcChat(func(ctx context.Context, req ChatRequest) (*ChatMessage, error) {
+		return &ChatMessage{Role: "assistant", Content: "Hi."}, nil
+	})
+
+	c := newTestCascade(t, stt, llm, WithTurnDetection(&TurnDetection{Type: "client_vad", PrefixPaddingMs: 300}))
+	r := recordCascade(c)
+
+	speak(t, c)
+	time.Sleep(50 * time.Millisecond)
+	if n := turns.Load(); n != 0 {
+		t.Fatalf("turns before Commit = %d, want 0", n)
+	}
+
+	if err := c.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	r.waitDone(t)
+	if n := turns.Load(); n != 1 {
+		t.Errorf("turns after Commit = %d, want 1", n)
+	}
+
+	// Cleared audio is never answered
+	speak(t, c)
+	if err := c.ClearAudio(); err != nil {
+		t.Fatalf("ClearAudio: %v", err)
+	}
+	c.Commit()
+	time.Sleep(50 * time.Millisecond)
+	if n := turns.Load(); n != 1 {
+		t.Errorf("turns after ClearAudio = %d, want 1", n)
+	}
+}
+
+func TestCascade_ToolCalls(t *testing.T) {
+	stt := funcTranscriber(func(context.Context, []byte, int) (string, error) { return "what time is it", nil })
+
+	newLLM := func(seen *[]ChatMessage) ChatModel {
+		var mu sync.Mutex
+		return funcChat(func(ctx context.Context, req ChatRequest) (*ChatMessage, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			last := req.Messages[len(req.Messages)-1]
+			if last.Role == "tool" {
+				*seen = append(*seen, last)
+				return &ChatMessage{Role: "assistant", Content: "It is " + last.Content + "."}, nil
+			}
+			return &ChatMessage{Role: "assistant", ToolCalls: []ChatToolCall{{Name: "get_time", Arguments: `{"zone":"UTC"}`}}}, nil
+		})
+	}
+
+	t.Run("callback", func(t *testing.T) {
+		var seen []ChatMessage
+		c := newTestCascade(t, stt, newLLM(&seen))
+		r := recordCascade(c)
+		c.OnToolCall(func(id, name string, args map[string]any) {
+			if name != "get_time" || args["zone"] != "UTC" {
+				t.Errorf("tool call = %s %v", name, args)
+			}
+			go c.SubmitToolResult(id, "noon")
+		})
+
+		speak(t, c)
+		r.waitDone(t)
+
+		if len(seen) != 1 || seen[0].Content != "noon" || seen[0].ToolCallID == "" {
+			t.Errorf("tool message = %+v", seen)
+		}
+		if got := c.Metrics().ToolCallsExecuted; got != 1 {
+			t.Errorf("ToolCallsExecuted = %d, want 1", got)
+		}
+		if err := c.SubmitToolResult("nope", "x"); !errors.Is(err, ErrToolCallFailed) {
+			t.Errorf("SubmitToolResult(unknown) = %v, want ErrToolCallFailed", err)
+		}
+
+		// The history keeps the full exchange for the next turn
+		c.mu.RLock()
+		roles := make([]string, len(c.history))
+		for i, m := range c.history {
+			roles[i] = m.Role
+		}
+		c.mu.RUnlock()
+		if got := strings.Join(roles, ","); got != "user,assistant,tool,assistant" {
+			t.Errorf("history roles = %s", got)
+		}
+	})
+
+	t.Run("handler", func(t *testing.T) {
+		var seen []ChatMessage
+		c := newTestCascade(t, stt, newLLM(&seen), WithTools(Tool{
+			Name:    "get_time",
+			Handler: func(args map[string]any) (string, error) { return "midnight", nil },
+		}))
+		r := recordCascade(c)
+
+		speak(t, c)
+		r.waitDone(t)
+
+		if len(seen) != 1 || seen[0].Content != "midnight" {
+			t.Errorf("tool message = %+v", seen)
+		}
+	})
+}
+
+func TestCascade_Interruption(t *testing.T) {
+	thinking := make(chan struct{}, 1)
+	cancelled := make(chan struct{}, 1)
+	var calls int
+	var mu sync.Mutex
+
+	stt := funcTranscriber(func(context.Context, []byte, int) (string, error) { return "tell me a story", nil })
+	llm := funcChat(func(ctx context.Context, req ChatRequest) (*ChatMessage, error) {
+		mu.Lock()
+		calls++
+		first := calls == 1
+		mu.Unlock()
+		if first {
+			thinking <- struct{}{}
+			<-ctx.Done() // Slow model: still thinking when the user talks again
+			cancelled <- struct{}{}
+			return nil, ctx.Err()
+		}
+		return &ChatMessage{Role: "assistant", Content: "Okay."}, nil
+	})
+
+	c := newTestCascade(t, stt, llm)
+	r := recordCascade(c)
+
+	speak(t, c)
+	select {
+	case <-thinking:
+	case <-time.After(2 * time.Second):
+		t.Fatal("first turn never reached the model")
+	}
+	speak(t, c)
+
+	select {
+	case <-cancelled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("first turn was not cancelled")
+	}
+	r.waitDone(t)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.interrupts != 1 {
+		t.Errorf("interrupts = %d, want 1", r.interrupts)
+	}
+	if len(r.errs) != 0 {
+		t.Errorf("errors = %v, want none for a cancelled turn", r.errs)
+	}
+
+	// Barge-in while the reply is still playing
+	if !time.Now().Before(c.speakingUntil) {
+		t.Fatal("speakingUntil not extended by emitted audio")
+	}
+	r.mu.Unlock()
+	c.handleSpeechStart()
+	r.mu.Lock()
+	if r.interrupts != 2 {
+		t.Errorf("interrupts = %d, want 2 after barge-in during playback", r.interrupts)
+	}
+}
+
+// encodedTTS returns a fixed clip in one encoding.
+type encodedTTS struct {
+	tts.Provider
+	format tts.AudioFormat
+	audio  []byte
+}
+
+func (e encodedTTS) Synthesize(ctx context.Context, text string) (*tts.AudioResult, error) {
+	return &tts.AudioResult{Audio: e.audio, Format: e.format}, nil
+}
+
+func TestCascade_TTSEncodings(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    tts.AudioFormat
+		wantAudio int
+		wantErr   error
+	}{
+		{"mu-law is decoded", tts.AudioFormat{Encoding: tts.EncodingULaw, SampleRate: 8000}, 1600 * 2 * 2, nil},
+		{"mp3 is refused", tts.AudioFormat{Encoding: tts.EncodingMP3, SampleRate: 44100}, 0, ErrInvalidAudio},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stt := funcTranscriber(func(context.Context, []byte, int) (string, error) { return "hi", nil })
+			llm := funcChat(func(context.Context, ChatRequest) (*ChatMessage, error) {
+				return &ChatMessage{Role: "assistant", Content: "Hello"}, nil
+			})
+			speaker := encodedTTS{format: tt.format, audio: make([]byte, 1600)} // 200ms at 8kHz
+			c, err := NewCascade(CascadeComponents{STT: stt, LLM: llm, TTS: speaker})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := c.Connect(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			defer c.Close()
+			r := recordCascade(c)
+
+			speak(t, c)
+			if tt.wantErr == nil {
+				r.waitDone(t)
+			} else {
+				waitFor(t, "error", func() bool { r.mu.Lock(); defer r.mu.Unlock(); return len(r.errs) > 0 })
+			}
+
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			if r.audio != tt.wantAudio {
+				t.Errorf("audio bytes = %d, want %d", r.audio, tt.wantAudio)
+			}
+			if tt.wantErr != nil && !errors.Is(r.errs[0], tt.wantErr) {
+				t.Errorf("error = %v, want %v", r.errs[0], tt.wantErr)
+			}
+		})
+	}
+}
+
+// Local servers for every stage: the cascade runs with no API key and
+// sends none.
+func TestCascade_Offline(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		paths []string
+		auths []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		auths = append(auths, r.Header.Get("Authorization"))
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/audio/transcriptions":
+			io.WriteString(w, `{"text":"hello eva"}`)
+		case "/chat/completions":
+			io.WriteString(w, `{"choices":[{"message":{"role":"assistant","content":"Hi there."}}]}`)
+		case "/audio/speech":
+			w.Write(make([]byte, 960))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	speech, err := tts.NewOpenAI(tts.WithBaseURL(srv.URL+"/audio/speech"), tts.WithOutputFormat(tts.EncodingPCM24))
+	if err != nil {
+		t.Fatalf("tts.NewOpenAI without a key: %v", err)
+	}
+	c, err := NewCascade(CascadeComponents{STT: NewWhisperTranscriber(srv.URL, "", ""), TTS: speech}, WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("NewCascade without a key: %v", err)
+	}
+	if err := c.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer c.Close()
+	r := recordCascade(c)
+
+	speak(t, c)
+	r.waitDone(t)
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"/audio/transcriptions", "/chat/completions", "/audio/speech"}
+	if strings.Join(paths, " ") != strings.Join(want, " ") {
+		t.Errorf("requests = %v, want %v", paths, want)
+	}
+	for i, auth := range auths {
+		if auth != "" {
+			t.Errorf("%s sent Authorization %q, want none", paths[i], auth)
+		}
+	}
+}
+
+func TestCascade_NotConnected(t *testing.T) {
+	stt := funcTranscriber(func(context.Context, []byte, int) (string, error) { return "", nil })
+	c, err := NewCascade(CascadeComponents{STT: stt, TTS: tts.NewMock()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SendAudio([]byte{0, 0}); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("SendAudio = %v, want ErrNotConnected", err)
+	}
+	if err := c.CancelResponse(); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("CancelResponse = %v, want ErrNotConnected", err)
+	}
+}
+
+func TestChatCompletions(t *testing.T) {
+	var body map[string]any
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		auth = r.Header.Get("Authorization")
+		data, _ := io.ReadAll(r.Body)
+		json.Unmarshal(data, &body)
+
+		// Arguments as a string and as an inline object (Ollama)
+		io.WriteString(w, `{"choices":[{"message":{"role":"assistant","content":null,"tool_calls":[
+			{"id":"a","type":"function","function":{"name":"look","arguments":"{\"x\":1}"}},
+			{"id":"b","type":"function","function":{"name":"look","arguments":{"x":2}}}]}}]}`)
+	}))
+	defer srv.Close()
+
+	c := NewChatCompletions(srv.URL+"/v1/", "key", "llama3.1")
+	msg, err := c.Complete(context.Background(), ChatRequest{
+		Messages: []ChatMessage{{Role: "user", Content: "hi"}},
+		Tools:    []Tool{{Name: "look", Description: "Look around"}},
+	})
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+
+	if auth != "Bearer key" || body["model"] != "llama3.1" {
+		t.Errorf("auth %q model %v", auth, body["model"])
+	}
+	tools, _ := body["tools"].([]any)
+	if len(tools) != 1 || tools[0].(map[string]any)["function"].(map[string]any)["name"] != "look" {
+		t.Errorf("tools = %v", body["tools"])
+	}
+	if len(msg.ToolCalls) != 2 || msg.ToolCalls[0].Arguments != `{"x":1}` || msg.ToolCalls[1].Arguments != `{"x":2}` {
+		t.Errorf("tool calls = %+v", msg.ToolCalls)
+	}
+
+	// Round trip back to the wire format
+	data, _ := json.Marshal(msg.ToolCalls[0])
+	if !strings.Contains(string(data), `"arguments":"{\"x\":1}"`) || !strings.Contains(string(data), `"type":"function"`) {
+		t.Errorf("marshalled = %s", data)
+	}
+}
+
+func TestChatCompletions_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		io.WriteString(w, `{"error":{"message":"slow down","code":"rate_limit_exceeded"}}`)
+	}))
+	defer srv.Close()
+
+	_, err := NewChatCompletions(srv.URL, "", "m").Complete(context.Background(), ChatRequest{})
+	if !IsRateLimited(err) {
+		t.Errorf("err = %v, want rate limited", err)
+	}
+}
+
+func TestWhisperTranscriber(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/audio/transcriptions" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if r.FormValue("model") != "base.en" {
+			t.Errorf("model = %q", r.FormValue("model"))
+		}
+		f, _, err := r.FormFile("file")
+		if err != nil {
+			t.Fatalf("FormFile: %v", err)
+		}
+		wav, _ := io.ReadAll(f)
+		if len(wav) != 44+4 || string(wav[:4]) != "RIFF" || binary.LittleEndian.Uint32(wav[24:]) != 16000 {
+			t.Errorf("wav header = %q", wav[:min(len(wav), 44)])
+		}
+		io.WriteString(w, `{"text":" hello there "}`)
+	}))
+	defer srv.Close()
+
+	text, err := NewWhisperTranscriber(srv.URL, "", "base.en").Transcribe(context.Background(), []byte{1, 2, 3, 4}, 16000)
+	if err != nil {
+		t.Fatalf("Transcribe: %v", err)
+	}
+	if text != "hello there" {
+		t.Errorf("text = %q, want %q", text, "hello there")
+	}
+}
+
+func TestSplitSentences(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"Hello", []string{"Hello"}},
+		{"Hi there. How are you?", []string{"Hi there.", "How are you?"}},
+		{"It costs 3.5 dollars! Wow", []string{"It costs 3.5 dollars!", "Wow"}},
+		{"Line one\nLine two", []string{"Line one", "Line two"}},
+	}
+	for _, tt := range tests {
+		got := splitSentences(tt.in)
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("splitSentences(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestEnergyVAD(t *testing.T) {
+	v := NewEnergyVAD()
+	if v.IsSpeech(tone(20*time.Millisecond, 0)) {
+		t.Error("silence detected as speech")
+	}
+	if v.IsSpeech(tone(20*time.Millisecond, 0.001)) {
+		t.Error("faint noise detected as speech")
+	}
+	if !v.IsSpeech(tone(20*time.Millisecond, 0.3)) {
+		t.Error("tone not detected as speech")
+	}
+}
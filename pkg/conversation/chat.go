@@ -0,0 +1,188 @@
+package conversation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const defaultChatModel = "gpt-4o-mini"
+
+// ChatMessage is one message in a chat-completions conversation.
+type ChatMessage struct {
+	Role       string         `json:"role"`
+	Content    string         `json:"content"`
+	ToolCalls  []ChatToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string         `json:"tool_call_id,omitempty"`
+}
+
+// ChatToolCall is a function call requested by the model.
+type ChatToolCall struct {
+	ID        string `json:"id"`
+	Name      string `json:"-"`
+	Arguments string `json:"-"`
+}
+
+// MarshalJSON encodes the call in the chat-completions wire format.
+func (c ChatToolCall) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"id":   c.ID,
+		"type": "function",
+		"function": map[string]string{
+			"name":      c.Name,
+			"arguments": c.Arguments,
+		},
+	})
+}
+
+// UnmarshalJSON decodes the wire format. Arguments may be a JSON string
+// (OpenAI, llama.cpp) or an inline object (some Ollama versions).
+func (c *ChatToolCall) UnmarshalJSON(data []byte) error {
+	var wire struct {
+		ID       string `json:"id"`
+		Function struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		} `json:"function"`
+	}
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+	c.ID = wire.ID
+	c.Name = wire.Function.Name
+
+	var s string
+	if err := json.Unmarshal(wire.Function.Arguments, &s); err == nil {
+		c.Arguments = s
+	} else {
+		c.Arguments = string(wire.Function.Arguments)
+	}
+	return nil
+}
+
+// ChatRequest is a single completion request.
+type ChatRequest struct {
+	Messages    []ChatMessage
+	Tools       []Tool
+	Temperature float64
+	MaxTokens   int
+}
+
+// ChatModel produces the next assistant message for a conversation.
+type ChatModel interface {
+	Complete(ctx context.Context, req ChatRequest) (*ChatMessage, error)
+}
+
+// ChatCompletions is a ChatModel for any OpenAI-compatible
+// /chat/completions endpoint, including local llama.cpp and Ollama
+// servers (e.g. "http://localhost:11434/v1").
+type ChatCompletions struct {
+	// BaseURL is the API root.
+	BaseURL string
+
+	// APIKey is sent as a bearer token when set.
+	APIKey string
+
+	// Model is the model name.
+	Model string
+
+	// Client is the HTTP client to use.
+	Client *http.Client
+}
+
+// NewChatCompletions creates a chat-completions client. Empty baseURL and
+// model default to OpenAI's API and gpt-4o-mini.
+func NewChatCompletions(baseURL, apiKey, model string) *ChatCompletions {
+	if baseURL == "" {
+		baseURL = openAIAPIBaseURL
+	}
+	if model == "" {
+		model = defaultChatModel
+	}
+	return &ChatCompletions{
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+		APIKey:  apiKey,
+		Model:   model,
+		Client:  &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// Complete sends the conversation and returns the assistant's reply.
+func (c *ChatCompletions) Complete(ctx context.Context, req ChatRequest) (*ChatMessage, error) {
+	payload := map[string]any{
+		"model":    c.Model,
+		"messages": req.Messages,
+	}
+	if req.Temperature > 0 {
+		payload["temperature"] = req.Temperature
+	}
+	if req.MaxTokens > 0 {
+		payload["max_tokens"] = req.MaxTokens
+	}
+	if len(req.Tools) > 0 {
+		tools := make([]map[string]any, 0, len(req.Tools))
+		for _, t := range req.Tools {
+			params := t.Parameters
+			if params == nil {
+				params = map[string]any{"type": "object", "properties": map[string]any{}}
+			}
+			tools = append(tools, map[string]any{
+				"type": "function",
+				"function": map[string]any{
+					"name":        t.Name,
+					"description": t.Description,
+					"parameters":  params,
+				},
+			})
+		}
+		payload["tools"] = tools
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("conversation.chat: marshal failed: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.BaseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("conversation.chat: create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.APIKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.APIKey)
+	}
+
+	resp, err := c.Client.Do(httpReq)
+	if err != nil {
+		return nil, NewConnectionError("chat request failed", err, true)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseHTTPError(resp)
+	}
+
+	var result struct {
+		Choices []struct {
+			Message ChatMessage `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("conversation.chat: decode response: %w", err)
+	}
+	if len(result.Choices) == 0 {
+		return nil, fmt.Errorf("conversation.chat: %w: no choices in response", ErrInvalidMessage)
+	}
+
+	msg := result.Choices[0].Message
+	if msg.Role == "" {
+		msg.Role = "assistant"
+	}
+	return &msg, nil
+}
+
+var _ ChatModel = (*ChatCompletions)(nil)
@@ -56,7 +56,8 @@ type Config struct {
 	// Timeout is the connection timeout.
 	Timeout time.Duration
 
-	// ReadTimeout is the timeout for reading messages.
+	// ReadTimeout is the timeout for reading messages. Zero disables it,
+	// for sessions that may sit silent for a long time.
 	ReadTimeout time.Duration
 
 	// WriteTimeout is the timeout for writing messages.
@@ -199,6 +200,14 @@ func WithTimeout(d time.Duration) Option {
 	}
 }
 
+// WithReadTimeout sets how long to wait for a server message before
+// treating the connection as dead.
+func WithReadTimeout(d time.Duration) Option {
+	return func(c *Config) {
+		c.ReadTimeout = d
+	}
+}
+
 // WithReconnect configures reconnection behavior.
 func WithReconnect(attempts int, delay time.Duration) Option {
 	return func(c *Config) {
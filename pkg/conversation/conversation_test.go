@@ -150,6 +150,22 @@ func TestMockProvider(t *testing.T) {
 		}
 	})
 
+	t.Run("inject", func(t *testing.T) {
+		m := NewMock()
+		if err := m.Inject("late result"); err != ErrNotConnected {
+			t.Errorf("inject before connect = %v, want ErrNotConnected", err)
+		}
+		_ = m.Connect(context.Background())
+
+		if err := m.Inject("late result"); err != nil {
+			t.Errorf("inject failed: %v", err)
+		}
+
+		if len(m.Injected) != 1 || m.Injected[0] != "late result" {
+			t.Errorf("injected = %v", m.Injected)
+		}
+	})
+
 	t.Run("cancel response", func(t *testing.T) {
 		m := NewMock()
 		_ = m.Connect(context.Background())
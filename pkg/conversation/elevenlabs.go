@@ -474,7 +474,9 @@ func (e *ElevenLabs) handleMessages(ctx context.Context) {
 		}
 
 		// Set read deadline
-		_ = conn.SetReadDeadline(time.Now().Add(e.config.ReadTimeout))
+		if e.config.ReadTimeout > 0 {
+			_ = conn.SetReadDeadline(time.Now().Add(e.config.ReadTimeout))
+		}
 
 		_, data, err := conn.ReadMessage()
 		if err != nil {
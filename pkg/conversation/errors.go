@@ -57,6 +57,9 @@ var (
 
 	// ErrMissingVoiceID indicates the voice ID was not provided for programmatic agent creation.
 	ErrMissingVoiceID = errors.New("conversation: voice ID is required for programmatic agent creation")
+
+	// ErrMissingComponent indicates a cascade was created without STT or TTS.
+	ErrMissingComponent = errors.New("conversation: cascade requires speech-to-text and text-to-speech components")
 )
 
 // APIError represents an error from the conversation API.
@@ -14,6 +14,7 @@ type Mock struct {
 	// Recorded data
 	AudioSent      [][]byte
 	ToolResults    map[string]string
+	Injected       []string
 	CancelCalled   bool
 	SessionOptions *SessionOptions
 
@@ -111,6 +112,17 @@ func (m *Mock) SubmitToolResult(callID, result string) error {
 	return nil
 }
 
+// Inject records injected text.
+func (m *Mock) Inject(text string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.connected {
+		return ErrNotConnected
+	}
+	m.Injected = append(m.Injected, text)
+	return nil
+}
+
 // Capabilities returns mock capabilities.
 func (m *Mock) Capabilities() Capabilities {
 	return Capabilities{
@@ -231,11 +243,15 @@ func (m *Mock) Reset() {
 	defer m.mu.Unlock()
 	m.AudioSent = nil
 	m.ToolResults = make(map[string]string)
+	m.Injected = nil
 	m.CancelCalled = false
 }
 
-// Ensure Mock implements Provider
-var _ Provider = (*Mock)(nil)
+// Ensure Mock implements Provider and Injector
+var (
+	_ Provider = (*Mock)(nil)
+	_ Injector = (*Mock)(nil)
+)
 
 
 
@@ -2,20 +2,50 @@ package conversation
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
 	"log/slog"
+	"net/http"
+	"net/url"
 	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	openAIBaseURL      = "wss://api.openai.com/v1/realtime"
+	openAIDefaultModel = "gpt-realtime-2025-08-28"
+	openAIDefaultVoice = VoiceShimmer
+	openAISampleRate   = 24000
 )
 
 // OpenAI implements Provider for OpenAI's Realtime API.
-// Note: This is a stub implementation. For full functionality,
-// use the pkg/openai package directly.
+//
+// Audio is PCM16 mono at 24kHz in both directions. Tools registered with a
+// Handler are executed automatically when no OnToolCall callback is set;
+// otherwise the callback is responsible for calling SubmitToolResult.
 type OpenAI struct {
 	config *Config
 	logger *slog.Logger
 
 	mu        sync.RWMutex
-	connected bool
+	conn      *websocket.Conn
+	state     ConnectionState
 	tools     []Tool
+	session   SessionOptions
+	connected time.Time
+	cancelCtx context.CancelFunc
+
+	// Response state, for Inject
+	responding    bool     // A response is in progress
+	injects       []string // Text waiting for it to finish
+	retryResponse bool     // A response.create was refused while one was active
+
+	// writeMu serialises writes; gorilla allows one concurrent writer
+	writeMu sync.Mutex
 
 	// Callbacks
 	onAudio        func([]byte)
@@ -24,6 +54,15 @@ type OpenAI struct {
 	onToolCall     func(id, name string, args map[string]any)
 	onError        func(error)
 	onInterruption func()
+	onSpeechStop   func()
+
+	// Atomic counters for metrics
+	messagesSent       atomic.Int64
+	messagesReceived   atomic.Int64
+	audioBytesSent     atomic.Int64
+	audioBytesReceived atomic.Int64
+	toolCalls          atomic.Int64
+	errors             atomic.Int64
 }
 
 // NewOpenAI creates a new OpenAI conversation provider.
@@ -38,91 +77,275 @@ func NewOpenAI(opts ...Option) (*OpenAI, error) {
 	if cfg.Logger == nil {
 		cfg.Logger = slog.Default()
 	}
+	if cfg.Model == "" {
+		cfg.Model = openAIDefaultModel
+	}
+	if cfg.Voice == "" {
+		cfg.Voice = openAIDefaultVoice
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = openAIBaseURL
+	}
 
 	return &OpenAI{
 		config: cfg,
 		logger: cfg.Logger.With("component", "conversation.openai"),
+		state:  StateDisconnected,
+		tools:  append([]Tool(nil), cfg.Tools...),
 	}, nil
 }
 
-// Connect establishes the WebSocket connection.
+// Connect establishes the WebSocket connection and sends the session
+// configuration (instructions, voice, tools, turn detection).
 func (o *OpenAI) Connect(ctx context.Context) error {
 	o.mu.Lock()
-	defer o.mu.Unlock()
+	if o.state == StateConnected || o.state == StateReconnecting {
+		o.mu.Unlock()
+		return ErrAlreadyConnected
+	}
+	o.state = StateConnecting
+	o.mu.Unlock()
+
+	o.logger.Info("connecting to OpenAI Realtime API", "model", o.config.Model)
+
+	conn, err := o.dial(ctx)
+	if err != nil {
+		o.mu.Lock()
+		o.state = StateDisconnected
+		o.mu.Unlock()
+		return err
+	}
+
+	// Create cancellation context for message handler
+	msgCtx, cancel := context.WithCancel(context.Background())
+
+	o.mu.Lock()
+	o.conn = conn
+	o.state = StateConnected
+	o.cancelCtx = cancel
+	o.connected = time.Now()
+	o.responding = false
+	o.mu.Unlock()
+
+	go o.handleMessages(msgCtx, conn)
+
+	if err := o.sendSessionUpdate(); err != nil {
+		o.Close()
+		return err
+	}
 
-	// TODO: Implement actual WebSocket connection
-	o.connected = true
-	o.logger.Info("connected to OpenAI Realtime API (stub)")
+	o.logger.Info("connected to OpenAI Realtime API")
 	return nil
 }
 
-// Close closes the connection.
+// dial opens a WebSocket to the Realtime endpoint.
+func (o *OpenAI) dial(ctx context.Context) (*websocket.Conn, error) {
+	wsURL, err := url.Parse(o.config.BaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("conversation.openai: invalid URL: %w", err)
+	}
+
+	q := wsURL.Query()
+	q.Set("model", o.config.Model)
+	wsURL.RawQuery = q.Encode()
+
+	headers := http.Header{}
+	headers.Set("Authorization", "Bearer "+o.config.APIKey)
+	headers.Set("OpenAI-Beta", "realtime=v1")
+
+	dialer := websocket.Dialer{
+		HandshakeTimeout: o.config.Timeout,
+	}
+
+	conn, resp, err := dialer.DialContext(ctx, wsURL.String(), headers)
+	if err != nil {
+		if resp != nil {
+			return nil, NewConnectionError(
+				fmt.Sprintf("dial failed with status %d", resp.StatusCode),
+				err,
+				resp.StatusCode >= 500,
+			)
+		}
+		return nil, NewConnectionError("dial failed", err, true)
+	}
+	return conn, nil
+}
+
+// Close gracefully closes the connection.
 func (o *OpenAI) Close() error {
 	o.mu.Lock()
 	defer o.mu.Unlock()
-	o.connected = false
+
+	if o.state == StateDisconnected {
+		return nil
+	}
+
+	// Cancel message handler and any reconnect in progress
+	if o.cancelCtx != nil {
+		o.cancelCtx()
+		o.cancelCtx = nil
+	}
+
+	if o.conn != nil {
+		o.writeMu.Lock()
+		deadline := time.Now().Add(time.Second)
+		_ = o.conn.WriteControl(
+			websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+			deadline,
+		)
+		o.writeMu.Unlock()
+		o.conn.Close()
+		o.conn = nil
+	}
+
+	o.state = StateDisconnected
+	o.logger.Info("disconnected from OpenAI Realtime API")
+
 	return nil
 }
 
-// IsConnected returns the connection state.
+// IsConnected returns true if connected.
 func (o *OpenAI) IsConnected() bool {
 	o.mu.RLock()
 	defer o.mu.RUnlock()
-	return o.connected
+	return o.state == StateConnected
 }
 
-// SendAudio sends audio data.
-func (o *OpenAI) SendAudio(audio []byte) error {
+// State returns the current connection state.
+func (o *OpenAI) State() ConnectionState {
 	o.mu.RLock()
-	connected := o.connected
-	o.mu.RUnlock()
+	defer o.mu.RUnlock()
+	return o.state
+}
 
-	if !connected {
-		return ErrNotConnected
+// SendAudio appends PCM16 audio (24kHz mono) to the input buffer.
+func (o *OpenAI) SendAudio(audio []byte) error {
+	msg := map[string]any{
+		"type":  "input_audio_buffer.append",
+		"audio": base64.StdEncoding.EncodeToString(audio),
 	}
-
-	// TODO: Implement audio sending
+	if err := o.send(msg); err != nil {
+		return err
+	}
+	o.audioBytesSent.Add(int64(len(audio)))
 	return nil
 }
 
-// ConfigureSession configures the session.
+// ConfigureSession configures the session. Options left at their zero
+// value fall back to the provider config. When connected the update is
+// sent immediately; otherwise it is sent on Connect. Non-empty Tools
+// replace the registered tools.
 func (o *OpenAI) ConfigureSession(opts SessionOptions) error {
 	o.mu.Lock()
-	defer o.mu.Unlock()
-	// Store tools
-	o.tools = opts.Tools
-	return nil
+	o.session = opts
+	if len(opts.Tools) > 0 {
+		o.tools = append([]Tool(nil), opts.Tools...)
+	}
+	connected := o.state == StateConnected
+	o.mu.Unlock()
+
+	if !connected {
+		return nil
+	}
+	return o.sendSessionUpdate()
 }
 
-// RegisterTool registers a tool.
+// RegisterTool registers a tool. Tools registered after Connect take
+// effect on the next ConfigureSession or reconnect.
 func (o *OpenAI) RegisterTool(tool Tool) {
 	o.mu.Lock()
 	defer o.mu.Unlock()
 	o.tools = append(o.tools, tool)
 }
 
+// Commit ends the user's turn: the buffered audio is committed and a
+// response requested. Use it when TurnDetection is "none" or
+// "client_vad".
+func (o *OpenAI) Commit() error {
+	if err := o.send(map[string]any{"type": "input_audio_buffer.commit"}); err != nil {
+		return err
+	}
+	return o.send(map[string]any{"type": "response.create"})
+}
+
+// ClearAudio drops audio appended since the last commit, such as a
+// push-to-talk turn that was abandoned.
+func (o *OpenAI) ClearAudio() error {
+	return o.send(map[string]any{"type": "input_audio_buffer.clear"})
+}
+
 // CancelResponse cancels the current response.
 func (o *OpenAI) CancelResponse() error {
-	o.mu.RLock()
-	connected := o.connected
-	o.mu.RUnlock()
-
-	if !connected {
-		return ErrNotConnected
-	}
-	return nil
+	return o.send(map[string]any{"type": "response.cancel"})
 }
 
-// SubmitToolResult submits a tool result.
+// SubmitToolResult submits a tool result and asks the model to continue.
 func (o *OpenAI) SubmitToolResult(callID, result string) error {
-	o.mu.RLock()
-	connected := o.connected
-	o.mu.RUnlock()
+	item := map[string]any{
+		"type": "conversation.item.create",
+		"item": map[string]any{
+			"type":    "function_call_output",
+			"call_id": callID,
+			"output":  result,
+		},
+	}
+	if err := o.send(item); err != nil {
+		return err
+	}
 
-	if !connected {
+	o.logger.Debug("submitted tool result",
+		"call_id", callID,
+		"result_len", len(result),
+	)
+	return o.send(map[string]any{"type": "response.create"})
+}
+
+// Inject adds text to the conversation as a system message and asks for
+// a response. While a response is in progress the text waits until it
+// finishes, so Eva isn't cut off mid-sentence.
+func (o *OpenAI) Inject(text string) error {
+	if !o.IsConnected() {
 		return ErrNotConnected
 	}
-	return nil
+	o.mu.Lock()
+	if o.responding {
+		o.injects = append(o.injects, text)
+		o.mu.Unlock()
+		return nil
+	}
+	o.responding = true
+	o.mu.Unlock()
+	return o.sendInjected([]string{text})
+}
+
+// sendInjected adds texts to the conversation and asks for one
+// response to them all.
+func (o *OpenAI) sendInjected(texts []string) error {
+	err := func() error {
+		for _, text := range texts {
+			item := map[string]any{
+				"type": "conversation.item.create",
+				"item": map[string]any{
+					"type": "message",
+					"role": "system",
+					"content": []map[string]any{
+						{"type": "input_text", "text": text},
+					},
+				},
+			}
+			if err := o.send(item); err != nil {
+				return err
+			}
+		}
+		return o.send(map[string]any{"type": "response.create"})
+	}()
+	if err != nil {
+		o.mu.Lock()
+		o.responding = false
+		o.mu.Unlock()
+	}
+	return err
 }
 
 // Capabilities returns provider capabilities.
@@ -132,9 +355,26 @@ func (o *OpenAI) Capabilities() Capabilities {
 		SupportsInterruption: true,
 		SupportsCustomVoice:  false,
 		SupportsStreaming:    true,
-		InputSampleRate:      24000,
-		OutputSampleRate:     24000,
-		SupportedModels:      []string{"gpt-4o-realtime-preview"},
+		InputSampleRate:      openAISampleRate,
+		OutputSampleRate:     openAISampleRate,
+		SupportedModels:      []string{openAIDefaultModel, "gpt-4o-realtime-preview"},
+	}
+}
+
+// Metrics returns a snapshot of connection and usage statistics.
+func (o *OpenAI) Metrics() Metrics {
+	o.mu.RLock()
+	connected := o.connected
+	o.mu.RUnlock()
+
+	return Metrics{
+		ConnectionTime:     connected,
+		MessagesSent:       o.messagesSent.Load(),
+		MessagesReceived:   o.messagesReceived.Load(),
+		AudioBytesSent:     o.audioBytesSent.Load(),
+		AudioBytesReceived: o.audioBytesReceived.Load(),
+		ToolCallsExecuted:  o.toolCalls.Load(),
+		Errors:             o.errors.Load(),
 	}
 }
 
@@ -152,14 +392,15 @@ func (o *OpenAI) OnAudioDone(fn func()) {
 	o.onAudioDone = fn
 }
 
-// OnTranscript sets the transcript callback.
+// OnTranscript sets the transcript callback. Roles are "user" and "agent".
 func (o *OpenAI) OnTranscript(fn func(role, text string, isFinal bool)) {
 	o.mu.Lock()
 	defer o.mu.Unlock()
 	o.onTranscript = fn
 }
 
-// OnToolCall sets the tool call callback.
+// OnToolCall sets the tool call callback. It runs on the read loop, so
+// it should hand slow work off and return.
 func (o *OpenAI) OnToolCall(fn func(id, name string, args map[string]any)) {
 	o.mu.Lock()
 	defer o.mu.Unlock()
@@ -173,16 +414,464 @@ func (o *OpenAI) OnError(fn func(error)) {
 	o.onError = fn
 }
 
-// OnInterruption sets the interruption callback.
+// OnInterruption sets the interruption callback. It fires when the
+// server detects the user starting to speak.
 func (o *OpenAI) OnInterruption(fn func()) {
 	o.mu.Lock()
 	defer o.mu.Unlock()
 	o.onInterruption = fn
 }
 
-// Ensure OpenAI implements Provider
-var _ Provider = (*OpenAI)(nil)
+// OnSpeechStopped sets the callback for the server detecting the end of
+// user speech. With server turn detection this ends the user's turn.
+func (o *OpenAI) OnSpeechStopped(fn func()) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.onSpeechStop = fn
+}
 
+// send marshals and writes a client event.
+func (o *OpenAI) send(msg any) error {
+	o.mu.RLock()
+	conn := o.conn
+	state := o.state
+	o.mu.RUnlock()
 
+	if state != StateConnected || conn == nil {
+		return ErrNotConnected
+	}
 
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("conversation.openai: marshal failed: %w", err)
+	}
 
+	o.writeMu.Lock()
+	_ = conn.SetWriteDeadline(time.Now().Add(o.config.WriteTimeout))
+	err = conn.WriteMessage(websocket.TextMessage, data)
+	o.writeMu.Unlock()
+	if err != nil {
+		o.errors.Add(1)
+		return NewConnectionError("send failed", err, true)
+	}
+
+	o.messagesSent.Add(1)
+	return nil
+}
+
+// sendSessionUpdate sends the merged session configuration.
+func (o *OpenAI) sendSessionUpdate() error {
+	o.mu.RLock()
+	session := o.buildSession()
+	o.mu.RUnlock()
+
+	return o.send(map[string]any{
+		"type":    "session.update",
+		"session": session,
+	})
+}
+
+// buildSession merges the session options over the provider config.
+// Callers must hold o.mu.
+func (o *OpenAI) buildSession() map[string]any {
+	opts := o.session
+
+	instructions := opts.SystemPrompt
+	if instructions == "" {
+		instructions = o.config.SystemPrompt
+	}
+	voice := opts.Voice
+	if voice == "" {
+		voice = o.config.Voice
+	}
+	temperature := opts.Temperature
+	if temperature == 0 {
+		temperature = o.config.Temperature
+	}
+	maxTokens := opts.MaxResponseTokens
+	if maxTokens == 0 {
+		maxTokens = opts.MaxTokens
+	}
+	if maxTokens == 0 {
+		maxTokens = o.config.MaxResponseTokens
+	}
+	td := opts.TurnDetection
+	if td == nil {
+		td = o.config.TurnDetection
+	}
+
+	session := map[string]any{
+		"modalities":          []string{"text", "audio"},
+		"instructions":        instructions,
+		"voice":               voice,
+		"input_audio_format":  "pcm16",
+		"output_audio_format": "pcm16",
+		"input_audio_transcription": map[string]any{
+			"model": "whisper-1",
+		},
+		"turn_detection": buildTurnDetection(td),
+	}
+	if temperature > 0 {
+		session["temperature"] = temperature
+	}
+	if maxTokens > 0 {
+		session["max_response_output_tokens"] = maxTokens
+	}
+
+	if len(o.tools) > 0 {
+		tools := make([]map[string]any, 0, len(o.tools))
+		for _, t := range o.tools {
+			params := t.Parameters
+			if params == nil {
+				params = map[string]any{"type": "object", "properties": map[string]any{}}
+			}
+			tools = append(tools, map[string]any{
+				"type":        "function",
+				"name":        t.Name,
+				"description": t.Description,
+				"parameters":  params,
+			})
+		}
+		session["tools"] = tools
+		session["tool_choice"] = "auto"
+	}
+
+	return session
+}
+
+// buildTurnDetection converts TurnDetection to the wire format; nil
+// disables server-side turn detection.
+func buildTurnDetection(td *TurnDetection) any {
+	if !td.Automatic() {
+		return nil
+	}
+	return map[string]any{
+		"type":                td.Type,
+		"threshold":           td.Threshold,
+		"prefix_padding_ms":   td.PrefixPaddingMs,
+		"silence_duration_ms": td.SilenceDurationMs,
+	}
+}
+
+// handleMessages processes incoming WebSocket messages for one connection.
+func (o *OpenAI) handleMessages(ctx context.Context, conn *websocket.Conn) {
+	for {
+		if o.config.ReadTimeout > 0 {
+			_ = conn.SetReadDeadline(time.Now().Add(o.config.ReadTimeout))
+		}
+
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return // Closed by us
+			}
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+				o.logger.Info("connection closed by server")
+				o.markDisconnected(conn)
+				return
+			}
+			o.logger.Error("read error", "error", err)
+			o.errors.Add(1)
+			o.reconnect(ctx, conn, err)
+			return
+		}
+
+		o.messagesReceived.Add(1)
+
+		var msg openAIEvent
+		if err := json.Unmarshal(data, &msg); err != nil {
+			o.logger.Warn("failed to parse message", "error", err)
+			continue
+		}
+
+		o.handleEvent(msg)
+	}
+}
+
+// markDisconnected drops conn if it is still the active connection.
+func (o *OpenAI) markDisconnected(conn *websocket.Conn) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.conn == conn {
+		o.conn.Close()
+		o.conn = nil
+		o.state = StateDisconnected
+	}
+}
+
+// reconnect re-dials after an unexpected read failure and re-sends the
+// session configuration. It gives up after ReconnectAttempts tries.
+func (o *OpenAI) reconnect(ctx context.Context, old *websocket.Conn, cause error) {
+	o.mu.Lock()
+	if o.conn != old {
+		o.mu.Unlock()
+		return
+	}
+	old.Close()
+	o.conn = nil
+	o.state = StateReconnecting
+	o.mu.Unlock()
+
+	for attempt := 1; attempt <= o.config.ReconnectAttempts; attempt++ {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(o.config.ReconnectDelay):
+		}
+
+		o.logger.Info("reconnecting to OpenAI Realtime API", "attempt", attempt)
+
+		conn, err := o.dial(ctx)
+		if err != nil {
+			o.logger.Warn("reconnect failed", "attempt", attempt, "error", err)
+			continue
+		}
+
+		o.mu.Lock()
+		if ctx.Err() != nil {
+			o.mu.Unlock()
+			conn.Close()
+			return
+		}
+		o.conn = conn
+		o.state = StateConnected
+		o.connected = time.Now()
+		o.responding = false
+		o.mu.Unlock()
+
+		go o.handleMessages(ctx, conn)
+
+		if err := o.sendSessionUpdate(); err != nil {
+			o.logger.Warn("session update after reconnect failed", "error", err)
+		}
+		o.logger.Info("reconnected to OpenAI Realtime API", "attempt", attempt)
+		return
+	}
+
+	o.mu.Lock()
+	if ctx.Err() == nil {
+		o.state = StateDisconnected
+	}
+	o.mu.Unlock()
+	o.emitError(NewConnectionError("connection lost", cause, false))
+}
+
+// handleEvent processes a single server event.
+func (o *OpenAI) handleEvent(msg openAIEvent) {
+	switch msg.Type {
+	case "session.created", "session.updated":
+		o.logger.Debug("session event", "type", msg.Type)
+
+	case "input_audio_buffer.speech_started":
+		o.logger.Debug("user speech started")
+		o.emitInterruption()
+
+	case "input_audio_buffer.speech_stopped":
+		o.logger.Debug("user speech stopped")
+		o.emitSpeechStopped()
+
+	case "conversation.item.input_audio_transcription.delta":
+		o.emitTranscript("user", msg.Delta, false)
+
+	case "conversation.item.input_audio_transcription.completed":
+		o.logger.Info("🎤 user transcript", "text", msg.Transcript)
+		o.emitTranscript("user", msg.Transcript, true)
+
+	case "conversation.item.input_audio_transcription.failed":
+		if msg.Error != nil {
+			o.logger.Warn("⚠️  transcription failed", "code", msg.Error.Code, "message", msg.Error.Message)
+		}
+
+	case "response.audio.delta":
+		audio, err := base64.StdEncoding.DecodeString(msg.Delta)
+		if err != nil {
+			o.logger.Warn("failed to decode audio", "error", err)
+			return
+		}
+		o.audioBytesReceived.Add(int64(len(audio)))
+		o.emitAudio(audio)
+
+	case "response.audio.done":
+		o.emitAudioDone()
+
+	case "response.audio_transcript.delta", "response.text.delta":
+		o.emitTranscript("agent", msg.Delta, false)
+
+	case "response.audio_transcript.done":
+		o.emitTranscript("agent", msg.Transcript, true)
+
+	case "response.text.done":
+		o.emitTranscript("agent", msg.Text, true)
+
+	case "response.function_call_arguments.done":
+		args := map[string]any{}
+		if msg.Arguments != "" {
+			if err := json.Unmarshal([]byte(msg.Arguments), &args); err != nil {
+				o.logger.Warn("failed to parse tool arguments", "tool", msg.Name, "error", err)
+			}
+		}
+		o.logger.Info("🔧 tool call", "tool", msg.Name, "call_id", msg.CallID)
+		o.toolCalls.Add(1)
+		o.dispatchToolCall(msg.CallID, msg.Name, args)
+
+	case "response.created":
+		o.mu.Lock()
+		o.responding = true
+		o.mu.Unlock()
+
+	case "response.done":
+		o.logger.Debug("response done")
+		o.mu.Lock()
+		o.responding = false
+		texts, retry := o.injects, o.retryResponse
+		o.injects, o.retryResponse = nil, false
+		if len(texts) > 0 || retry {
+			o.responding = true
+		}
+		o.mu.Unlock()
+		if len(texts) > 0 || retry {
+			if err := o.sendInjected(texts); err != nil {
+				o.logger.Warn("failed to send injected text", "error", err)
+			}
+		}
+
+	case "error":
+		if msg.Error == nil {
+			return
+		}
+		// Cancelling when nothing is playing is harmless
+		if msg.Error.Code == "response_cancel_not_active" {
+			return
+		}
+		// A response was requested while another was running: the new
+		// items are in the conversation, so ask again once it finishes
+		if msg.Error.Code == "conversation_already_has_active_response" {
+			o.mu.Lock()
+			o.retryResponse = true
+			o.mu.Unlock()
+			return
+		}
+		o.logger.Error("❌ API error", "code", msg.Error.Code, "message", msg.Error.Message)
+		o.errors.Add(1)
+		o.emitError(NewAPIError(0, msg.Error.Code, msg.Error.Message))
+	}
+}
+
+// dispatchToolCall hands a tool call to the OnToolCall callback, or runs
+// the registered tool handler and submits its result. Handlers run on
+// their own goroutine, so a slow tool doesn't stop the read loop (and
+// trip ReadTimeout) in the middle of a turn.
+func (o *OpenAI) dispatchToolCall(id, name string, args map[string]any) {
+	o.mu.RLock()
+	fn := o.onToolCall
+	var handler func(map[string]any) (string, error)
+	for _, t := range o.tools {
+		if t.Name == name {
+			handler = t.Handler
+			break
+		}
+	}
+	o.mu.RUnlock()
+
+	if fn != nil {
+		fn(id, name, args)
+		return
+	}
+
+	go func() {
+		var result string
+		if handler == nil {
+			result = fmt.Sprintf("Error: unknown tool %q", name)
+		} else if out, err := handler(args); err != nil {
+			result = "Error: " + err.Error()
+		} else {
+			result = out
+		}
+
+		if err := o.SubmitToolResult(id, result); err != nil {
+			o.emitError(fmt.Errorf("conversation.openai: submit tool result: %w", err))
+		}
+	}()
+}
+
+// Emit helpers
+
+func (o *OpenAI) emitAudio(audio []byte) {
+	o.mu.RLock()
+	fn := o.onAudio
+	o.mu.RUnlock()
+	if fn != nil {
+		fn(audio)
+	}
+}
+
+func (o *OpenAI) emitAudioDone() {
+	o.mu.RLock()
+	fn := o.onAudioDone
+	o.mu.RUnlock()
+	if fn != nil {
+		fn()
+	}
+}
+
+func (o *OpenAI) emitTranscript(role, text string, isFinal bool) {
+	o.mu.RLock()
+	fn := o.onTranscript
+	o.mu.RUnlock()
+	if fn != nil {
+		fn(role, text, isFinal)
+	}
+}
+
+func (o *OpenAI) emitInterruption() {
+	o.mu.RLock()
+	fn := o.onInterruption
+	o.mu.RUnlock()
+	if fn != nil {
+		fn()
+	}
+}
+
+func (o *OpenAI) emitSpeechStopped() {
+	o.mu.RLock()
+	fn := o.onSpeechStop
+	o.mu.RUnlock()
+	if fn != nil {
+		fn()
+	}
+}
+
+func (o *OpenAI) emitError(err error) {
+	o.mu.RLock()
+	fn := o.onError
+	o.mu.RUnlock()
+	if fn != nil {
+		fn(err)
+	}
+}
+
+// Message types for the OpenAI Realtime API
+
+type openAIEvent struct {
+	Type       string       `json:"type"`
+	Delta      string       `json:"delta,omitempty"`
+	Transcript string       `json:"transcript,omitempty"`
+	Text       string       `json:"text,omitempty"`
+	Name       string       `json:"name,omitempty"`
+	CallID     string       `json:"call_id,omitempty"`
+	Arguments  string       `json:"arguments,omitempty"`
+	Error      *openAIError `json:"error,omitempty"`
+}
+
+type openAIError struct {
+	Type    string `json:"type"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Ensure OpenAI implements Provider, Committer and Injector
+var (
+	_ Provider  = (*OpenAI)(nil)
+	_ Committer = (*OpenAI)(nil)
+	_ Injector  = (*OpenAI)(nil)
+)
@@ -0,0 +1,539 @@
+package conversation
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// fakeRealtime is a local stand-in for the OpenAI Realtime websocket.
+type fakeRealtime struct {
+	t      *testing.T
+	server *httptest.Server
+
+	mu       sync.Mutex
+	headers  []http.Header
+	queries  []string
+	conns    []*websocket.Conn
+	received chan map[string]any
+}
+
+func newFakeRealtime(t *testing.T) *fakeRealtime {
+	f := &fakeRealtime{t: t, received: make(chan map[string]any, 100)}
+	upgrader := websocket.Upgrader{}
+
+	f.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade: %v", err)
+			return
+		}
+		f.mu.Lock()
+		f.headers = append(f.headers, r.Header.Clone())
+		f.queries = append(f.queries, r.URL.RawQuery)
+		f.conns = append(f.conns, conn)
+		f.mu.Unlock()
+
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			var msg map[string]any
+			if err := json.Unmarshal(data, &msg); err == nil {
+				f.received <- msg
+			}
+		}
+	}))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeRealtime) url() string {
+	return "ws" + strings.TrimPrefix(f.server.URL, "http")
+}
+
+// send writes a server event on the latest connection.
+func (f *fakeRealtime) send(event map[string]any) {
+	f.mu.Lock()
+	conn := f.conns[len(f.conns)-1]
+	f.mu.Unlock()
+	if err := conn.WriteJSON(event); err != nil {
+		f.t.Fatalf("send %v: %v", event["type"], err)
+	}
+}
+
+// dropLatest closes the latest connection without a close handshake.
+func (f *fakeRealtime) dropLatest() {
+	f.mu.Lock()
+	conn := f.conns[len(f.conns)-1]
+	f.mu.Unlock()
+	conn.UnderlyingConn().Close()
+}
+
+// next waits for the next client event of the given type.
+func (f *fakeRealtime) next(typ string) map[string]any {
+	f.t.Helper()
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case msg := <-f.received:
+			if msg["type"] == typ {
+				return msg
+			}
+		case <-timeout:
+			f.t.Fatalf("timed out waiting for %s", typ)
+			return nil
+		}
+	}
+}
+
+func newTestOpenAI(t *testing.T, f *fakeRealtime, opts ...Option) *OpenAI {
+	opts = append([]Option{
+		WithAPIKey("test-key"),
+		WithBaseURL(f.url()),
+		WithReconnect(3, 10*time.Millisecond),
+	}, opts...)
+	p, err := NewOpenAI(opts...)
+	if err != nil {
+		t.Fatalf("NewOpenAI: %v", err)
+	}
+	t.Cleanup(func() { p.Close() })
+	return p
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestOpenAI_ConnectConfiguresSession(t *testing.T) {
+	f := newFakeRealtime(t)
+	p := newTestOpenAI(t, f, WithSystemPrompt("You are Eva."), WithModel("test-model"))
+	p.RegisterTool(Tool{Name: "wave", Description: "Waves"})
+
+	if err := p.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	if !p.IsConnected() {
+		t.Fatal("IsConnected() = false after Connect")
+	}
+	if err := p.Connect(context.Background()); !errors.Is(err, ErrAlreadyConnected) {
+		t.Errorf("second Connect = %v, want ErrAlreadyConnected", err)
+	}
+
+	msg := f.next("session.update")
+	f.mu.Lock()
+	h, q := f.headers[0], f.queries[0]
+	f.mu.Unlock()
+	if got := h.Get("Authorization"); got != "Bearer test-key" {
+		t.Errorf("Authorization = %q", got)
+	}
+	if got := h.Get("OpenAI-Beta"); got != "realtime=v1" {
+		t.Errorf("OpenAI-Beta = %q", got)
+	}
+	if q != "model=test-model" {
+		t.Errorf("query = %q, want model=test-model", q)
+	}
+
+	session := msg["session"].(map[string]any)
+	if session["instructions"] != "You are Eva." || session["voice"] != VoiceShimmer {
+		t.Errorf("session instructions/voice = %v / %v", session["instructions"], session["voice"])
+	}
+	if td, ok := session["turn_detection"].(map[string]any); !ok || td["type"] != "server_vad" {
+		t.Errorf("turn_detection = %v, want server_vad", session["turn_detection"])
+	}
+	tools, _ := session["tools"].([]any)
+	if len(tools) != 1 || tools[0].(map[string]any)["name"] != "wave" {
+		t.Errorf("tools = %v, want [wave]", session["tools"])
+	}
+
+	// Reconfiguring while connected sends a fresh update
+	err := p.ConfigureSession(SessionOptions{
+		SystemPrompt:  "Be brief.",
+		Voice:         VoiceAlloy,
+		TurnDetection: &TurnDetection{Type: "none"},
+	})
+	if err != nil {
+		t.Fatalf("ConfigureSession: %v", err)
+	}
+	session = f.next("session.update")["session"].(map[string]any)
+	if session["instructions"] != "Be brief." || session["voice"] != VoiceAlloy {
+		t.Errorf("updated instructions/voice = %v / %v", session["instructions"], session["voice"])
+	}
+	if session["turn_detection"] != nil {
+		t.Errorf("turn_detection = %v, want null", session["turn_detection"])
+	}
+}
+
+func TestOpenAI_ServerEvents(t *testing.T) {
+	f := newFakeRealtime(t)
+	p := newTestOpenAI(t, f)
+
+	var (
+		mu          sync.Mutex
+		audio       []byte
+		audioDone   int
+		transcripts []string
+		interrupts  int
+		stops       int
+		errs        []error
+	)
+	p.OnAudio(func(b []byte) { mu.Lock(); audio = append(audio, b...); mu.Unlock() })
+	p.OnAudioDone(func() { mu.Lock(); audioDone++; mu.Unlock() })
+	p.OnTranscript(func(role, text string, isFinal bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		mark := ""
+		if isFinal {
+			mark = "!"
+		}
+		transcripts = append(transcripts, role+":"+text+mark)
+	})
+	p.OnInterruption(func() { mu.Lock(); interrupts++; mu.Unlock() })
+	p.OnSpeechStopped(func() { mu.Lock(); stops++; mu.Unlock() })
+	p.OnError(func(err error) { mu.Lock(); errs = append(errs, err); mu.Unlock() })
+
+	if err := p.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	f.next("session.update")
+
+	pcm := []byte{1, 2, 3, 4}
+	f.send(map[string]any{"type": "input_audio_buffer.speech_started"})
+	f.send(map[string]any{"type": "input_audio_buffer.speech_stopped"})
+	f.send(map[string]any{"type": "conversation.item.input_audio_transcription.completed", "transcript": "hi eva"})
+	f.send(map[string]any{"type": "response.audio_transcript.delta", "delta": "Hel"})
+	f.send(map[string]any{"type": "response.audio.delta", "delta": base64.StdEncoding.EncodeToString(pcm)})
+	f.send(map[string]any{"type": "response.audio.done"})
+	f.send(map[string]any{"type": "response.audio_transcript.done", "transcript": "Hello"})
+	f.send(map[string]any{"type": "error", "error": map[string]any{"code": "response_cancel_not_active", "message": "nothing to cancel"}})
+	f.send(map[string]any{"type": "error", "error": map[string]any{"code": "rate_limit_exceeded", "message": "slow down"}})
+
+	waitFor(t, "error callback", func() bool { mu.Lock(); defer mu.Unlock(); return len(errs) > 0 })
+
+	mu.Lock()
+	defer mu.Unlock()
+	if string(audio) != string(pcm) || audioDone != 1 {
+		t.Errorf("audio = %v done %d, want %v done 1", audio, audioDone, pcm)
+	}
+	want := []string{"user:hi eva!", "agent:Hel", "agent:Hello!"}
+	if strings.Join(transcripts, "|") != strings.Join(want, "|") {
+		t.Errorf("transcripts = %v, want %v", transcripts, want)
+	}
+	if interrupts != 1 || stops != 1 {
+		t.Errorf("interrupts = %d, speech stops = %d, want 1 each", interrupts, stops)
+	}
+	var apiErr *APIError
+	if len(errs) != 1 || !errors.As(errs[0], &apiErr) || apiErr.Code != "rate_limit_exceeded" {
+		t.Errorf("errors = %v, want one rate_limit_exceeded API error", errs)
+	}
+
+	m := p.Metrics()
+	if m.AudioBytesReceived != int64(len(pcm)) || m.MessagesReceived != 9 || m.Errors != 1 {
+		t.Errorf("metrics = %+v", m)
+	}
+	if m.ConnectionTime.IsZero() {
+		t.Error("ConnectionTime is zero")
+	}
+}
+
+func TestOpenAI_SendAudioAndCancel(t *testing.T) {
+	f := newFakeRealtime(t)
+	p := newTestOpenAI(t, f)
+
+	if err := p.SendAudio([]byte{1}); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("SendAudio before Connect = %v, want ErrNotConnected", err)
+	}
+	if err := p.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	f.next("session.update")
+
+	pcm := []byte{10, 20, 30, 40}
+	if err := p.SendAudio(pcm); err != nil {
+		t.Fatalf("SendAudio: %v", err)
+	}
+	msg := f.next("input_audio_buffer.append")
+	got, _ := base64.StdEncoding.DecodeString(msg["audio"].(string))
+	if string(got) != string(pcm) {
+		t.Errorf("audio = %v, want %v", got, pcm)
+	}
+
+	if err := p.CancelResponse(); err != nil {
+		t.Fatalf("CancelResponse: %v", err)
+	}
+	f.next("response.cancel")
+
+	m := p.Metrics()
+	if m.AudioBytesSent != int64(len(pcm)) || m.MessagesSent != 3 {
+		t.Errorf("metrics = %+v, want 4 audio bytes / 3 messages", m)
+	}
+}
+
+func TestOpenAI_ClientVADCommit(t *testing.T) {
+	f := newFakeRealtime(t)
+	p := newTestOpenAI(t, f, WithTurnDetection(&TurnDetection{Type: "client_vad"}))
+	if err := p.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+
+	// The client endpoints, so the server must not
+	session := f.next("session.update")["session"].(map[string]any)
+	if session["turn_detection"] != nil {
+		t.Errorf("turn_detection = %v, want null", session["turn_detection"])
+	}
+
+	if err := p.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	f.next("input_audio_buffer.commit")
+	f.next("response.create")
+
+	if err := p.ClearAudio(); err != nil {
+		t.Fatalf("ClearAudio: %v", err)
+	}
+	f.next("input_audio_buffer.clear")
+}
+
+func TestOpenAI_ToolCalls(t *testing.T) {
+	t.Run("callback submits result", func(t *testing.T) {
+		f := newFakeRealtime(t)
+		p := newTestOpenAI(t, f)
+
+		calls := make(chan map[string]any, 1)
+		p.OnToolCall(func(id, name string, args map[string]any) {
+			if id != "call-1" || name != "look" {
+				t.Errorf("tool call = %s/%s", id, name)
+			}
+			calls <- args
+			if err := p.SubmitToolResult(id, "looked"); err != nil {
+				t.Errorf("SubmitToolResult: %v", err)
+			}
+		})
+		if err := p.Connect(context.Background()); err != nil {
+			t.Fatalf("Connect: %v", err)
+		}
+		f.next("session.update")
+
+		f.send(map[string]any{
+			"type":      "response.function_call_arguments.done",
+			"call_id":   "call-1",
+			"name":      "look",
+			"arguments": `{"direction":"left"}`,
+		})
+
+		select {
+		case args := <-calls:
+			if args["direction"] != "left" {
+				t.Errorf("args = %v", args)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("tool callback not called")
+		}
+
+		item := f.next("conversation.item.create")["item"].(map[string]any)
+		if item["type"] != "function_call_output" || item["call_id"] != "call-1" || item["output"] != "looked" {
+			t.Errorf("item = %v", item)
+		}
+		f.next("response.create")
+
+		if got := p.Metrics().ToolCallsExecuted; got != 1 {
+			t.Errorf("ToolCallsExecuted = %d, want 1", got)
+		}
+	})
+
+	t.Run("handler runs without callback", func(t *testing.T) {
+		f := newFakeRealtime(t)
+		p := newTestOpenAI(t, f, WithTools(Tool{
+			Name: "time",
+			Handler: func(args map[string]any) (string, error) {
+				return "noon", nil
+			},
+		}))
+		if err := p.Connect(context.Background()); err != nil {
+			t.Fatalf("Connect: %v", err)
+		}
+		f.next("session.update")
+
+		f.send(map[string]any{"type": "response.function_call_arguments.done", "call_id": "c2", "name": "time", "arguments": "{}"})
+		item := f.next("conversation.item.create")["item"].(map[string]any)
+		if item["output"] != "noon" {
+			t.Errorf("output = %v, want noon", item["output"])
+		}
+
+		f.send(map[string]any{"type": "response.function_call_arguments.done", "call_id": "c3", "name": "missing"})
+		item = f.next("conversation.item.create")["item"].(map[string]any)
+		if !strings.HasPrefix(item["output"].(string), "Error:") {
+			t.Errorf("unknown tool output = %v, want error", item["output"])
+		}
+	})
+
+	t.Run("slow handler doesn't stop reads", func(t *testing.T) {
+		f := newFakeRealtime(t)
+		release := make(chan struct{})
+		p := newTestOpenAI(t, f, WithTools(Tool{
+			Name: "search",
+			Handler: func(args map[string]any) (string, error) {
+				<-release
+				return "found", nil
+			},
+		}))
+		audio := make(chan []byte, 1)
+		p.OnAudio(func(b []byte) { audio <- b })
+		if err := p.Connect(context.Background()); err != nil {
+			t.Fatalf("Connect: %v", err)
+		}
+		f.next("session.update")
+
+		f.send(map[string]any{"type": "response.function_call_arguments.done", "call_id": "c4", "name": "search"})
+		f.send(map[string]any{"type": "response.audio.delta", "delta": base64.StdEncoding.EncodeToString([]byte{7})})
+		select {
+		case <-audio:
+		case <-time.After(2 * time.Second):
+			t.Fatal("events not read while the tool runs")
+		}
+
+		close(release)
+		item := f.next("conversation.item.create")["item"].(map[string]any)
+		if item["output"] != "found" {
+			t.Errorf("output = %v, want found", item["output"])
+		}
+	})
+}
+
+func TestOpenAI_Inject(t *testing.T) {
+	f := newFakeRealtime(t)
+	p := newTestOpenAI(t, f)
+	var errs []error
+	p.OnError(func(err error) { errs = append(errs, err) })
+	if err := p.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	f.next("session.update")
+	responding := func() bool {
+		p.mu.RLock()
+		defer p.mu.RUnlock()
+		return p.responding
+	}
+	injected := func(want string) {
+		t.Helper()
+		item := f.next("conversation.item.create")["item"].(map[string]any)
+		content := item["content"].([]any)[0].(map[string]any)
+		if item["role"] != "system" || content["text"] != want {
+			t.Errorf("item = %v, want system text %q", item, want)
+		}
+	}
+
+	// Idle: sent straight away
+	if err := p.Inject("first"); err != nil {
+		t.Fatalf("Inject: %v", err)
+	}
+	injected("first")
+	f.next("response.create")
+	f.send(map[string]any{"type": "response.done"})
+	waitFor(t, "response done", func() bool { return !responding() })
+
+	// Mid-response: held until it finishes, then sent together
+	f.send(map[string]any{"type": "response.created"})
+	waitFor(t, "response created", responding)
+	p.Inject("second")
+	p.Inject("third")
+	select {
+	case msg := <-f.received:
+		t.Fatalf("sent %v during a response", msg["type"])
+	case <-time.After(50 * time.Millisecond):
+	}
+	f.send(map[string]any{"type": "response.done"})
+	injected("second")
+	injected("third")
+	f.next("response.create")
+
+	// A refused response.create is retried when the active one finishes
+	f.send(map[string]any{"type": "error", "error": map[string]any{"code": "conversation_already_has_active_response", "message": "busy"}})
+	waitFor(t, "retry", func() bool {
+		p.mu.RLock()
+		defer p.mu.RUnlock()
+		return p.retryResponse
+	})
+	f.send(map[string]any{"type": "response.done"})
+	f.next("response.create")
+	if len(errs) != 0 {
+		t.Errorf("errors = %v, want none", errs)
+	}
+}
+
+func TestOpenAI_Reconnect(t *testing.T) {
+	f := newFakeRealtime(t)
+	p := newTestOpenAI(t, f, WithSystemPrompt("You are Eva."))
+
+	if err := p.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	f.next("session.update")
+
+	f.dropLatest()
+
+	// The session is restored on the new connection
+	session := f.next("session.update")["session"].(map[string]any)
+	if session["instructions"] != "You are Eva." {
+		t.Errorf("instructions after reconnect = %v", session["instructions"])
+	}
+	waitFor(t, "reconnect", p.IsConnected)
+
+	if err := p.SendAudio([]byte{1, 2}); err != nil {
+		t.Errorf("SendAudio after reconnect: %v", err)
+	}
+	f.next("input_audio_buffer.append")
+
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if p.State() != StateDisconnected {
+		t.Errorf("State() = %s after Close", p.State())
+	}
+}
+
+func TestOpenAI_ReconnectGivesUp(t *testing.T) {
+	f := newFakeRealtime(t)
+	p := newTestOpenAI(t, f, WithReconnect(2, 10*time.Millisecond))
+
+	errs := make(chan error, 1)
+	p.OnError(func(err error) { errs <- err })
+
+	if err := p.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	f.next("session.update")
+
+	f.server.Close()
+	f.dropLatest()
+
+	select {
+	case err := <-errs:
+		var connErr *ConnectionError
+		if !errors.As(err, &connErr) {
+			t.Errorf("error = %v, want ConnectionError", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no error after reconnect attempts ran out")
+	}
+	if p.IsConnected() {
+		t.Error("IsConnected() = true after giving up")
+	}
+}
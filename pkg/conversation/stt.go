@@ -0,0 +1,160 @@
+package conversation
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	openAIAPIBaseURL    = "https://api.openai.com/v1"
+	defaultWhisperModel = "whisper-1"
+)
+
+// Transcriber converts an utterance of PCM16 mono audio to text.
+type Transcriber interface {
+	Transcribe(ctx context.Context, pcm []byte, sampleRate int) (string, error)
+}
+
+// WhisperTranscriber transcribes audio with any OpenAI-compatible
+// /audio/transcriptions endpoint (OpenAI, faster-whisper-server,
+// whisper.cpp server, LocalAI, ...).
+type WhisperTranscriber struct {
+	// BaseURL is the API root, e.g. "http://localhost:8000/v1".
+	BaseURL string
+
+	// APIKey is sent as a bearer token when set.
+	APIKey string
+
+	// Model is the transcription model name.
+	Model string
+
+	// Language is an optional ISO-639-1 hint (e.g. "en").
+	Language string
+
+	// Client is the HTTP client to use.
+	Client *http.Client
+}
+
+// NewWhisperTranscriber creates a transcriber for an OpenAI-compatible
+// endpoint. Empty baseURL and model default to OpenAI's whisper-1.
+func NewWhisperTranscriber(baseURL, apiKey, model string) *WhisperTranscriber {
+	if baseURL == "" {
+		baseURL = openAIAPIBaseURL
+	}
+	if model == "" {
+		model = defaultWhisperModel
+	}
+	return &WhisperTranscriber{
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+		APIKey:  apiKey,
+		Model:   model,
+		Client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Transcribe uploads the audio as a WAV file and returns the text.
+func (w *WhisperTranscriber) Transcribe(ctx context.Context, pcm []byte, sampleRate int) (string, error) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+
+	part, err := mw.CreateFormFile("file", "audio.wav")
+	if err != nil {
+		return "", fmt.Errorf("conversation.stt: form file: %w", err)
+	}
+	if _, err := part.Write(encodeWAV(pcm, sampleRate)); err != nil {
+		return "", fmt.Errorf("conversation.stt: write audio: %w", err)
+	}
+	_ = mw.WriteField("model", w.Model)
+	_ = mw.WriteField("response_format", "json")
+	if w.Language != "" {
+		_ = mw.WriteField("language", w.Language)
+	}
+	if err := mw.Close(); err != nil {
+		return "", fmt.Errorf("conversation.stt: close form: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.BaseURL+"/audio/transcriptions", &body)
+	if err != nil {
+		return "", fmt.Errorf("conversation.stt: create request: %w", err)
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	if w.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+w.APIKey)
+	}
+
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return "", NewConnectionError("transcription request failed", err, true)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", parseHTTPError(resp)
+	}
+
+	var result struct {
+		Text string `json:"text"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("conversation.stt: decode response: %w", err)
+	}
+	return strings.TrimSpace(result.Text), nil
+}
+
+// parseHTTPError converts an OpenAI-style error response to an APIError.
+func parseHTTPError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+
+	var errResp struct {
+		Error struct {
+			Message string `json:"message"`
+			Code    any    `json:"code"`
+		} `json:"error"`
+	}
+
+	message := strings.TrimSpace(string(body))
+	code := ""
+	if json.Unmarshal(body, &errResp) == nil && errResp.Error.Message != "" {
+		message = errResp.Error.Message
+		if errResp.Error.Code != nil {
+			code = fmt.Sprint(errResp.Error.Code)
+		}
+	}
+	return NewAPIError(resp.StatusCode, code, message)
+}
+
+// encodeWAV wraps PCM16 mono audio in a WAV header.
+func encodeWAV(pcm []byte, sampleRate int) []byte {
+	var buf bytes.Buffer
+	buf.Grow(44 + len(pcm))
+
+	buf.WriteString("RIFF")
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(36+len(pcm)))
+	buf.WriteString("WAVE")
+
+	// fmt chunk: PCM, mono, 16-bit
+	buf.WriteString("fmt ")
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(16))
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(1))
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(1))
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(sampleRate))
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(sampleRate*2))
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(2))
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(16))
+
+	buf.WriteString("data")
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(len(pcm)))
+	buf.Write(pcm)
+
+	return buf.Bytes()
+}
+
+var _ Transcriber = (*WhisperTranscriber)(nil)
@@ -67,6 +67,13 @@ type TurnDetection struct {
 	SilenceDurationMs int
 }
 
+// Automatic reports whether the provider ends turns by itself. It is
+// false for nil, "none" and "client_vad", where the caller endpoints
+// locally and calls Commit.
+func (td *TurnDetection) Automatic() bool {
+	return td != nil && td.Type != "" && td.Type != "none" && td.Type != "client_vad"
+}
+
 // SessionOptions configures a conversation session.
 type SessionOptions struct {
 	// Tools available to the agent during this session.
@@ -204,3 +211,18 @@ type Provider interface {
 	OnInterruption(fn func())
 }
 
+// Committer is implemented by providers that accept an explicit end of
+// turn, for push-to-talk or local ("client_vad") endpointing.
+type Committer interface {
+	// Commit ends the user's turn and asks for a response.
+	Commit() error
+}
+
+// Injector is implemented by providers that can add text to the
+// conversation outside a user turn, such as a background tool's late
+// result.
+type Injector interface {
+	// Inject adds text to the conversation and asks for a response,
+	// after the response in progress (if any) finishes.
+	Inject(text string) error
+}
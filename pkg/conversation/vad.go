@@ -0,0 +1,60 @@
+package conversation
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// VAD decides whether a frame of PCM16 mono audio contains speech.
+// Implementations may keep state between frames (e.g. noise estimates).
+type VAD interface {
+	// IsSpeech reports whether the frame contains speech.
+	IsSpeech(frame []byte) bool
+
+	// Reset clears any state between utterances.
+	Reset()
+}
+
+// DefaultVADThresholdDBFS is the EnergyVAD speech threshold.
+const DefaultVADThresholdDBFS = -45.0
+
+// EnergyVAD is a simple loudness-based VAD. It treats any frame louder
+// than ThresholdDBFS as speech, which works well for a close microphone
+// in a quiet room; swap in a model-based VAD for noisier spaces.
+type EnergyVAD struct {
+	// ThresholdDBFS is the RMS level above which a frame is speech.
+	ThresholdDBFS float64
+}
+
+// NewEnergyVAD creates an EnergyVAD with the default threshold.
+func NewEnergyVAD() *EnergyVAD {
+	return &EnergyVAD{ThresholdDBFS: DefaultVADThresholdDBFS}
+}
+
+// IsSpeech reports whether the frame's RMS level exceeds the threshold.
+func (v *EnergyVAD) IsSpeech(frame []byte) bool {
+	return pcm16DBFS(frame) > v.ThresholdDBFS
+}
+
+// Reset is a no-op; EnergyVAD is stateless.
+func (v *EnergyVAD) Reset() {}
+
+// pcm16DBFS returns the RMS level of little-endian PCM16 audio in dBFS.
+func pcm16DBFS(pcm []byte) float64 {
+	n := len(pcm) / 2
+	if n == 0 {
+		return math.Inf(-1)
+	}
+	var sum float64
+	for i := 0; i < n; i++ {
+		s := float64(int16(binary.LittleEndian.Uint16(pcm[2*i:]))) / 32768.0
+		sum += s * s
+	}
+	rms := math.Sqrt(sum / float64(n))
+	if rms == 0 {
+		return math.Inf(-1)
+	}
+	return 20 * math.Log10(rms)
+}
+
+var _ VAD = (*EnergyVAD)(nil)
@@ -0,0 +1,121 @@
+package eva
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultAck is what a background tool replies at once when its
+// AsyncTool has no Ack.
+const DefaultAck = "Started. The result will arrive in a later message; tell the user you're working on it and don't guess the answer."
+
+// ProgressFunc reports what a background tool is doing, like
+// "Searching the web". A nil ProgressFunc ignores reports.
+type ProgressFunc func(message string)
+
+// report calls p if it is set.
+func (p ProgressFunc) report(message string) {
+	if p != nil {
+		p(message)
+	}
+}
+
+// AsyncHandler runs a tool in the background. It should stop and return
+// ctx's error when ctx ends.
+type AsyncHandler func(ctx context.Context, args map[string]interface{}, progress ProgressFunc) (string, error)
+
+// AsyncTool is a tool that replies at once and finishes in the
+// background, so the model can keep talking while it runs. The
+// registry reports its result through OnResult.
+type AsyncTool struct {
+	Tool              // Name, Description and Parameters; Handler is unused
+	Ack  string       // Immediate reply (DefaultAck if empty)
+	Run  AsyncHandler // Does the work
+}
+
+// Async runs a tool's synchronous handler in the background. The
+// handler gets the job's ctx; one that ignores it keeps running when the
+// job is cancelled or times out, but its result is dropped.
+func Async(tool Tool, ack string) AsyncTool {
+	handler := tool.Handler
+	return AsyncTool{
+		Tool: tool,
+		Ack:  ack,
+		Run: func(ctx context.Context, args map[string]interface{}, _ ProgressFunc) (string, error) {
+			type result struct {
+				out string
+				err error
+			}
+			done := make(chan result, 1)
+			go func() {
+				out, err := handler(ctx, args)
+				done <- result{out, err}
+			}()
+			select {
+			case res := <-done:
+				return res.out, res.err
+			case <-ctx.Done():
+				return "", ctx.Err()
+			}
+		},
+	}
+}
+
+// asyncAcks are the immediate replies of Eva's background tools.
+var asyncAcks = map[string]string{
+	"describe_scene": "Taking a look now. What I see will arrive in a later message; say you're having a look and don't guess.",
+	"web_search":     "Searching now. The results will arrive in a later message; say you're looking it up and don't guess the answer.",
+	"search_flights": "Checking flights now. The results will arrive in a later message; say you're looking and don't guess prices or times.",
+	"generate_plan":  "Drafting the plan now. It will arrive in a later message; say you're working on it.",
+}
+
+// AsyncTools returns the background versions of Eva's slow tools for
+// cfg: describe_scene, web_search, search_flights and, with sparks,
+// generate_plan.
+func AsyncTools(cfg ToolsConfig) []AsyncTool {
+	runs := map[string]AsyncHandler{
+		"describe_scene": bindConfig(describeScene, cfg),
+		"web_search":     bindConfig(webSearch, cfg),
+		"search_flights": bindConfig(searchFlights, cfg),
+	}
+
+	var tools []AsyncTool
+	for _, tool := range Tools(cfg) {
+		ack, ok := asyncAcks[tool.Name]
+		if !ok {
+			continue
+		}
+		if run, ok := runs[tool.Name]; ok {
+			tools = append(tools, AsyncTool{Tool: tool, Ack: ack, Run: run})
+		} else {
+			tools = append(tools, Async(tool, ack))
+		}
+	}
+	return tools
+}
+
+// bindConfig makes an AsyncHandler from a tool function and its config.
+func bindConfig(fn func(context.Context, ToolsConfig, map[string]interface{}, ProgressFunc) (string, error), cfg ToolsConfig) AsyncHandler {
+	return func(ctx context.Context, args map[string]interface{}, progress ProgressFunc) (string, error) {
+		return fn(ctx, cfg, args, progress)
+	}
+}
+
+// ToolJob is a background tool run.
+type ToolJob struct {
+	ID       string                 `json:"id"`
+	Tool     string                 `json:"tool"`
+	Args     map[string]interface{} `json:"args,omitempty"`
+	Started  time.Time              `json:"started"`
+	Progress string                 `json:"progress,omitempty"` // Latest progress report
+}
+
+// Message is the job's outcome worded for the model, to add to the
+// conversation when it finishes.
+func (j ToolJob) Message(result string, err error) string {
+	if err != nil {
+		return fmt.Sprintf("[Background task %s (%s) failed: %v] Tell the user it didn't work.", j.ID, j.Tool, err)
+	}
+	return fmt.Sprintf("[Result of background task %s (%s)]\n%s\n\nTell the user what you found.", j.ID, j.Tool, result)
+}
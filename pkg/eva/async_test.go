@@ -0,0 +1,244 @@
+package eva
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type jobResult struct {
+	job ToolJob
+	out string
+	err error
+}
+
+// resultChan collects r's job results.
+func resultChan(r *ToolRegistry) chan jobResult {
+	results := make(chan jobResult, 10)
+	r.OnResult = func(job ToolJob, out string, err error) {
+		results <- jobResult{job, out, err}
+	}
+	return results
+}
+
+func waitResult(t *testing.T, results chan jobResult) jobResult {
+	t.Helper()
+	select {
+	case res := <-results:
+		return res
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for a job result")
+		return jobResult{}
+	}
+}
+
+func TestToolRegistry_Async(t *testing.T) {
+	r := NewToolRegistry()
+	release := make(chan struct{})
+	reported := make(chan struct{})
+	search := AsyncTool{
+		Tool: Tool{
+			Name:       "search",
+			Parameters: map[string]interface{}{"query": map[string]interface{}{"type": "string"}},
+		},
+		Ack: "Searching",
+		Run: func(ctx context.Context, args map[string]interface{}, progress ProgressFunc) (string, error) {
+			progress("Asking the web")
+			close(reported)
+			<-release
+			return "found " + args["query"].(string), nil
+		},
+	}
+	if err := r.RegisterAsync(search, ToolPolicy{MaxConcurrent: 1}); err != nil {
+		t.Fatalf("RegisterAsync: %v", err)
+	}
+	results := resultChan(r)
+	var started []ToolJob
+	var progress []string
+	r.OnJobStart = func(job ToolJob) { started = append(started, job) }
+	r.OnProgress = func(job ToolJob, message string) { progress = append(progress, message) }
+
+	if _, err := r.Call(context.Background(), "search", map[string]interface{}{"query": 5.0}); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("Call with bad args = %v, want ErrInvalidArgs", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out, err := r.Call(ctx, "search", map[string]interface{}{"query": "otters"})
+	if err != nil || out != "Searching (task 1)" {
+		t.Fatalf("Call = %q, %v", out, err)
+	}
+	cancel() // The job outlives the call's context
+	<-reported
+
+	jobs := r.Jobs()
+	if len(jobs) != 1 || jobs[0].ID != "1" || jobs[0].Tool != "search" || jobs[0].Progress != "Asking the web" {
+		t.Errorf("Jobs = %+v", jobs)
+	}
+	if _, err := r.Call(context.Background(), "search", map[string]interface{}{"query": "seals"}); !errors.Is(err, ErrToolBusy) {
+		t.Errorf("second Call = %v, want ErrToolBusy", err)
+	}
+
+	close(release)
+	res := waitResult(t, results)
+	if res.err != nil || res.out != "found otters" || res.job.ID != "1" {
+		t.Errorf("result = %+v", res)
+	}
+	if len(started) != 1 || len(progress) != 1 {
+		t.Errorf("started %v, progress %v", started, progress)
+	}
+	if jobs := r.Jobs(); len(jobs) != 0 {
+		t.Errorf("Jobs after finishing = %+v", jobs)
+	}
+	if s := r.Stats()[0]; s.Calls != 1 || s.Rejected != 2 || s.InFlight != 0 {
+		t.Errorf("stats = %+v", s)
+	}
+}
+
+func TestToolRegistry_AsyncCancel(t *testing.T) {
+	r := NewToolRegistry()
+	wait := func(ctx context.Context, args map[string]interface{}, progress ProgressFunc) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}
+	r.RegisterAsync(AsyncTool{Tool: Tool{Name: "a"}, Run: wait}, ToolPolicy{})
+	r.RegisterAsync(AsyncTool{Tool: Tool{Name: "b"}, Run: wait}, ToolPolicy{Timeout: 20 * time.Millisecond})
+	results := resultChan(r)
+
+	out, _ := r.Call(context.Background(), "a", nil)
+	if !strings.HasPrefix(out, DefaultAck) {
+		t.Errorf("Call = %q, want the default ack", out)
+	}
+	if n := r.Cancel("nothing"); n != 0 {
+		t.Errorf("Cancel(nothing) = %d, want 0", n)
+	}
+	if n := r.Cancel("a"); n != 1 {
+		t.Errorf("Cancel(a) = %d, want 1", n)
+	}
+	if res := waitResult(t, results); !errors.Is(res.err, ErrToolCanceled) || res.job.Tool != "a" {
+		t.Errorf("canceled result = %+v", res)
+	}
+
+	r.Call(context.Background(), "b", nil)
+	if res := waitResult(t, results); !errors.Is(res.err, ErrToolTimeout) {
+		t.Errorf("timed out result = %+v", res)
+	}
+	if s := r.Stats()[1]; s.Timeouts != 1 {
+		t.Errorf("stats = %+v", s)
+	}
+}
+
+func TestToolRegistry_CallWait(t *testing.T) {
+	r := NewToolRegistry()
+	r.Register(Tool{Name: "wave", Handler: func(context.Context, map[string]interface{}) (string, error) { return "Waved", nil }}, ToolPolicy{})
+	r.RegisterAsync(AsyncTool{
+		Tool: Tool{Name: "search"},
+		Run: func(ctx context.Context, args map[string]interface{}, progress ProgressFunc) (string, error) {
+			return "found otters", nil
+		},
+	}, ToolPolicy{})
+	started := make(chan struct{}, 1)
+	r.RegisterAsync(AsyncTool{
+		Tool: Tool{Name: "slow"},
+		Run: func(ctx context.Context, args map[string]interface{}, progress ProgressFunc) (string, error) {
+			started <- struct{}{}
+			<-ctx.Done()
+			return "", ctx.Err()
+		},
+	}, ToolPolicy{})
+	r.OnResult = func(job ToolJob, out string, err error) {
+		t.Errorf("OnResult(%+v, %q, %v) for a waited call", job, out, err)
+	}
+
+	if out, err := r.CallWait(context.Background(), "wave", nil); err != nil || out != "Waved" {
+		t.Errorf("CallWait(wave) = %q, %v", out, err)
+	}
+	if out, err := r.CallWait(context.Background(), "search", nil); err != nil || out != "found otters" {
+		t.Errorf("CallWait(search) = %q, %v", out, err)
+	}
+
+	// Ending the caller's context cancels the job
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+	if _, err := r.CallWait(ctx, "slow", nil); !errors.Is(err, ErrToolCanceled) {
+		t.Errorf("CallWait after ctx ends = %v, want ErrToolCanceled", err)
+	}
+
+	// So does the end of an MCP client's request
+	for _, tool := range r.MCPTools() {
+		if tool.Name != "slow" {
+			continue
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			<-started
+			cancel()
+		}()
+		if _, err := tool.Handler(ctx, nil); !errors.Is(err, ErrToolCanceled) {
+			t.Errorf("MCP call after its request ends = %v, want ErrToolCanceled", err)
+		}
+	}
+	if jobs := r.Jobs(); len(jobs) != 0 {
+		t.Errorf("Jobs after CallWait = %+v", jobs)
+	}
+}
+
+func TestAsync(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	tool := Async(Tool{
+		Name: "plan",
+		Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
+			if args["wait"] != nil {
+				<-release
+			}
+			return "a plan", nil
+		},
+	}, "Planning")
+
+	out, err := tool.Run(context.Background(), map[string]interface{}{}, nil)
+	if err != nil || out != "a plan" {
+		t.Errorf("Run = %q, %v", out, err)
+	}
+
+	// A handler that can't be interrupted has its result dropped
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := tool.Run(ctx, map[string]interface{}{"wait": true}, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("Run after cancel = %v, want context.Canceled", err)
+	}
+}
+
+func TestNewRegistry_Async(t *testing.T) {
+	r, err := NewRegistry(ToolsConfig{})
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+	results := resultChan(r)
+
+	out, err := r.Call(context.Background(), "describe_scene", nil)
+	if err != nil || !strings.HasPrefix(out, asyncAcks["describe_scene"]) {
+		t.Fatalf("describe_scene = %q, %v", out, err)
+	}
+	if res := waitResult(t, results); res.out != "I cannot see right now - camera not connected" {
+		t.Errorf("result = %+v", res)
+	}
+
+	if out, _ := r.Call(context.Background(), "cancel_task", nil); out != "No background task to stop" {
+		t.Errorf("cancel_task = %q", out)
+	}
+}
+
+func TestToolJob_Message(t *testing.T) {
+	job := ToolJob{ID: "3", Tool: "web_search"}
+	if got := job.Message("42", nil); !strings.HasPrefix(got, "[Result of background task 3 (web_search)]\n42") {
+		t.Errorf("Message = %q", got)
+	}
+	if got := job.Message("", errors.New("boom")); !strings.Contains(got, "task 3 (web_search) failed: boom") {
+		t.Errorf("Message = %q", got)
+	}
+}
@@ -2,10 +2,14 @@
 package eva
 
 import (
+	"context"
+
 	"github.com/teslashibe/go-reachy/pkg/audio"
 	"github.com/teslashibe/go-reachy/pkg/emotions"
+	"github.com/teslashibe/go-reachy/pkg/mcp"
 	"github.com/teslashibe/go-reachy/pkg/memory"
 	"github.com/teslashibe/go-reachy/pkg/robot"
+	"github.com/teslashibe/go-reachy/pkg/schedule"
 	"github.com/teslashibe/go-reachy/pkg/spark"
 	"github.com/teslashibe/go-reachy/pkg/vision"
 )
@@ -36,12 +40,15 @@ type ObjectDetectionResult struct {
 	X, Y, W, H float64 // Normalized 0-1
 }
 
-// Tool represents an AI tool that Eva can use.
+// Tool represents an AI tool that Eva can use. Like an AsyncHandler,
+// Handler should stop when ctx ends: the registry cancels it when the
+// call times out.
 type Tool struct {
 	Name        string
 	Description string
 	Parameters  map[string]interface{}
-	Handler     func(args map[string]interface{}) (string, error)
+	Required    []string // Parameters every call must include
+	Handler     func(ctx context.Context, args map[string]interface{}) (string, error)
 }
 
 // ToolsConfig holds dependencies for Eva's tools.
@@ -58,6 +65,8 @@ type ToolsConfig struct {
 	SparkStore      *spark.JSONStore        // Spark idea storage
 	SparkGemini     *spark.GeminiClient     // Spark Gemini for title/tag generation
 	SparkGoogleDocs *spark.GoogleDocsClient // Spark Google Docs for syncing
+	Schedule        *schedule.Scheduler     // Timers, alarms and reminders
+	MCP             *mcp.Manager            // Tools from MCP servers
 }
 
 // isAnimal returns true if the class name is an animal.
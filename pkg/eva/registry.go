@@ -0,0 +1,627 @@
+package eva
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/teslashibe/go-reachy/pkg/conversation"
+	"github.com/teslashibe/go-reachy/pkg/mcp"
+	"github.com/teslashibe/go-reachy/pkg/openai"
+)
+
+// DefaultToolTimeout is how long a tool may run when its policy doesn't
+// say otherwise.
+const DefaultToolTimeout = 30 * time.Second
+
+// Errors returned by ToolRegistry.Call. The conversation providers pass
+// them back to the model as "Error: ..." results.
+var (
+	ErrToolNotFound = errors.New("unknown tool")
+	ErrToolDisabled = errors.New("tool is disabled")
+	ErrInvalidArgs  = errors.New("invalid arguments")
+	ErrToolTimeout  = errors.New("tool timed out")
+	ErrToolBusy     = errors.New("tool is already running")
+	ErrRateLimited  = errors.New("tool called too often, try again shortly")
+	ErrToolCanceled = errors.New("tool was canceled")
+)
+
+// ToolPolicy limits how a tool runs.
+type ToolPolicy struct {
+	Timeout       time.Duration // Max run time (0 = DefaultToolTimeout)
+	MaxConcurrent int           // Calls allowed at once (0 = unlimited)
+	MaxPerMinute  int           // Calls allowed per minute (0 = unlimited)
+	Disabled      bool          // Start disabled
+}
+
+// toolPolicies are the default policies for Eva's tools. Motion tools
+// run one at a time; network tools get longer timeouts.
+var toolPolicies = map[string]ToolPolicy{
+	"move_head":      {MaxConcurrent: 1},
+	"rotate_body":    {MaxConcurrent: 1},
+	"look_around":    {MaxConcurrent: 1},
+	"nod_yes":        {MaxConcurrent: 1},
+	"shake_head_no":  {MaxConcurrent: 1},
+	"wave_hello":     {MaxConcurrent: 1},
+	"set_volume":     {MaxPerMinute: 20},
+	"describe_scene": {Timeout: 45 * time.Second, MaxConcurrent: 1},
+	"detect_objects": {Timeout: 45 * time.Second, MaxConcurrent: 1},
+	"find_person":    {Timeout: 90 * time.Second, MaxConcurrent: 1},
+	"web_search":     {Timeout: 45 * time.Second, MaxConcurrent: 2, MaxPerMinute: 10},
+	"search_flights": {Timeout: 60 * time.Second, MaxConcurrent: 1, MaxPerMinute: 5},
+	"generate_plan":  {Timeout: 60 * time.Second, MaxConcurrent: 1},
+	"sync_spark":     {Timeout: 60 * time.Second, MaxConcurrent: 1},
+}
+
+// DefaultToolPolicy returns the default policy for the named tool.
+func DefaultToolPolicy(name string) ToolPolicy {
+	return toolPolicies[name]
+}
+
+// ToolStats are a tool's call metrics, for the dashboard.
+type ToolStats struct {
+	Name         string  `json:"name"`
+	Description  string  `json:"description"`
+	Enabled      bool    `json:"enabled"`
+	Calls        int     `json:"calls"`    // Handler runs
+	Errors       int     `json:"errors"`   // Handler errors, including timeouts
+	Timeouts     int     `json:"timeouts"` // Runs past the policy timeout
+	Rejected     int     `json:"rejected"` // Disabled, invalid args, busy or rate limited
+	InFlight     int     `json:"in_flight"`
+	AvgLatencyMs float64 `json:"avg_latency_ms"`
+	MaxLatencyMs float64 `json:"max_latency_ms"`
+	LastError    string  `json:"last_error,omitempty"`
+	LastCall     string  `json:"last_call,omitempty"` // RFC 3339
+}
+
+// registeredTool is a tool with its policy and metrics. Guarded by the
+// registry's mu.
+type registeredTool struct {
+	tool    Tool
+	run     AsyncHandler // Set for background tools
+	ack     string
+	policy  ToolPolicy
+	enabled bool
+
+	inFlight int
+	recent   []time.Time // Call times within the last minute
+
+	calls, errors, timeouts, rejected int
+	totalLatency, maxLatency          time.Duration
+	lastError                         string
+	lastCall                          time.Time
+}
+
+// ToolRegistry runs Eva's tools: it validates arguments against each
+// tool's parameter schema and enforces its policy (timeout, concurrency
+// and rate limits), tools can be turned on and off at runtime, and it
+// keeps per-tool metrics.
+//
+// Background tools (RegisterAsync) reply at once and run as jobs; their
+// results arrive through OnResult, for the caller to add to the
+// conversation.
+type ToolRegistry struct {
+	// OnCall is called after each call with its outcome (optional).
+	OnCall func(name string, latency time.Duration, err error)
+
+	// Background job callbacks (optional). OnResult gets the result or
+	// error of every job, including ErrToolCanceled and ErrToolTimeout.
+	OnJobStart func(job ToolJob)
+	OnProgress func(job ToolJob, message string)
+	OnResult   func(job ToolJob, result string, err error)
+
+	mu      sync.Mutex
+	tools   map[string]*registeredTool
+	order   []string
+	jobs    map[string]*runningJob
+	nextJob int
+
+	now func() time.Time
+}
+
+// NewToolRegistry creates an empty registry.
+func NewToolRegistry() *ToolRegistry {
+	return &ToolRegistry{
+		tools: make(map[string]*registeredTool),
+		jobs:  make(map[string]*runningJob),
+		now:   time.Now,
+	}
+}
+
+// NewRegistry creates a registry with Eva's tools for cfg and their
+// default policies. The slow ones (AsyncTools) run in the background,
+// and cancel_task lets the model stop them.
+func NewRegistry(cfg ToolsConfig) (*ToolRegistry, error) {
+	r := NewToolRegistry()
+	async := make(map[string]AsyncTool)
+	for _, at := range AsyncTools(cfg) {
+		async[at.Name] = at
+	}
+	for _, tool := range Tools(cfg) {
+		var err error
+		if at, ok := async[tool.Name]; ok {
+			err = r.RegisterAsync(at, DefaultToolPolicy(tool.Name))
+		} else {
+			err = r.Register(tool, DefaultToolPolicy(tool.Name))
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := r.Register(r.cancelTool(), ToolPolicy{}); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Register adds a tool. Its Parameters must be a map of property
+// schemas, each with a "type".
+func (r *ToolRegistry) Register(tool Tool, policy ToolPolicy) error {
+	if tool.Handler == nil {
+		return fmt.Errorf("tool %q: handler is required", tool.Name)
+	}
+	return r.add(&registeredTool{tool: tool, policy: policy})
+}
+
+// RegisterAsync adds a background tool: calls return its Ack at once
+// and run it as a job, within the policy's timeout.
+func (r *ToolRegistry) RegisterAsync(tool AsyncTool, policy ToolPolicy) error {
+	if tool.Run == nil {
+		return fmt.Errorf("tool %q: run is required", tool.Name)
+	}
+	ack := tool.Ack
+	if ack == "" {
+		ack = DefaultAck
+	}
+	return r.add(&registeredTool{tool: tool.Tool, run: tool.Run, ack: ack, policy: policy})
+}
+
+// add checks and registers t.
+func (r *ToolRegistry) add(t *registeredTool) error {
+	tool := t.tool
+	if tool.Name == "" {
+		return errors.New("tool name is required")
+	}
+	for name, p := range tool.Parameters {
+		prop, ok := p.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("tool %q: parameter %q is not a schema", tool.Name, name)
+		}
+		if _, ok := prop["type"].(string); !ok {
+			return fmt.Errorf("tool %q: parameter %q has no type", tool.Name, name)
+		}
+		if enum, ok := prop["enum"]; ok && enumValues(enum) == nil {
+			return fmt.Errorf("tool %q: parameter %q has an enum of %T, want a list of strings or numbers", tool.Name, name, enum)
+		}
+	}
+	for _, name := range tool.Required {
+		if _, ok := tool.Parameters[name]; !ok {
+			return fmt.Errorf("tool %q: required parameter %q isn't declared", tool.Name, name)
+		}
+	}
+	if t.policy.Timeout <= 0 {
+		t.policy.Timeout = DefaultToolTimeout
+	}
+	t.enabled = !t.policy.Disabled
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.tools[tool.Name]; exists {
+		return fmt.Errorf("tool %q is already registered", tool.Name)
+	}
+	r.tools[tool.Name] = t
+	r.order = append(r.order, tool.Name)
+	return nil
+}
+
+// Call validates args and runs the named tool within its policy. At the
+// timeout, or when ctx ends, the handler's context is canceled and Call
+// returns; a handler that ignores its context keeps its concurrency slot
+// until it returns, but its result is dropped.
+//
+// A background tool returns its Ack with the job ID straight away; the
+// job isn't tied to ctx, so it outlives the model turn that started it.
+func (r *ToolRegistry) Call(ctx context.Context, name string, args map[string]interface{}) (string, error) {
+	return r.call(ctx, name, args, false)
+}
+
+// CallWait is Call for callers that want every tool's result, such as
+// other agents over MCP: a background tool still runs as a job (listed
+// by Jobs, stopped by Cancel), but CallWait waits for it and returns its
+// result instead of passing it to OnResult. Ending ctx cancels the job.
+func (r *ToolRegistry) CallWait(ctx context.Context, name string, args map[string]interface{}) (string, error) {
+	return r.call(ctx, name, args, true)
+}
+
+func (r *ToolRegistry) call(ctx context.Context, name string, args map[string]interface{}, wait bool) (string, error) {
+	r.mu.Lock()
+	t, ok := r.tools[name]
+	if !ok {
+		r.mu.Unlock()
+		return "", fmt.Errorf("%w: %s", ErrToolNotFound, name)
+	}
+	if err := r.admitLocked(t); err != nil {
+		t.rejected++
+		r.mu.Unlock()
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	valid, err := validateArgs(t.tool.Parameters, t.tool.Required, args)
+	if err != nil {
+		t.rejected++
+		r.mu.Unlock()
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	t.inFlight++
+	if t.policy.MaxPerMinute > 0 {
+		t.recent = append(t.recent, r.now())
+	}
+	handler, timeout := t.tool.Handler, t.policy.Timeout
+	if t.run != nil {
+		rj := r.startJobLocked(ctx, t, valid, wait)
+		r.mu.Unlock()
+		if !wait {
+			return fmt.Sprintf("%s (task %s)", t.ack, rj.job.ID), nil
+		}
+		res := <-rj.done
+		return res.out, res.err
+	}
+	r.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	done := make(chan result, 1)
+	start := time.Now()
+	go func() {
+		defer func() {
+			r.mu.Lock()
+			t.inFlight--
+			r.mu.Unlock()
+		}()
+		out, err := handler(ctx, valid)
+		done <- result{out, err}
+	}()
+
+	var res result
+	select {
+	case res = <-done:
+	case <-ctx.Done():
+		res.err = ctx.Err()
+		if errors.Is(res.err, context.DeadlineExceeded) {
+			res.err = fmt.Errorf("%w after %v", ErrToolTimeout, timeout)
+		}
+	}
+
+	r.record(t, time.Since(start), res.err)
+	if res.err != nil {
+		return "", fmt.Errorf("%s: %w", name, res.err)
+	}
+	return res.out, nil
+}
+
+// admitLocked checks whether t may run now. Caller holds mu.
+func (r *ToolRegistry) admitLocked(t *registeredTool) error {
+	if !t.enabled {
+		return ErrToolDisabled
+	}
+	if t.policy.MaxConcurrent > 0 && t.inFlight >= t.policy.MaxConcurrent {
+		return ErrToolBusy
+	}
+	if t.policy.MaxPerMinute > 0 {
+		cutoff := r.now().Add(-time.Minute)
+		kept := t.recent[:0]
+		for _, at := range t.recent {
+			if at.After(cutoff) {
+				kept = append(kept, at)
+			}
+		}
+		t.recent = kept
+		if len(t.recent) >= t.policy.MaxPerMinute {
+			return ErrRateLimited
+		}
+	}
+	return nil
+}
+
+// record updates t's metrics after a call.
+func (r *ToolRegistry) record(t *registeredTool, latency time.Duration, err error) {
+	r.mu.Lock()
+	t.calls++
+	t.totalLatency += latency
+	t.maxLatency = max(t.maxLatency, latency)
+	t.lastCall = r.now()
+	if err != nil {
+		t.errors++
+		t.lastError = err.Error()
+		if errors.Is(err, ErrToolTimeout) {
+			t.timeouts++
+		}
+	}
+	onCall := r.OnCall
+	r.mu.Unlock()
+
+	if onCall != nil {
+		onCall(t.tool.Name, latency, err)
+	}
+}
+
+// result is a tool's output or error.
+type result struct {
+	out string
+	err error
+}
+
+// runningJob is a background job in progress. Guarded by the
+// registry's mu.
+type runningJob struct {
+	job    ToolJob
+	cancel context.CancelFunc
+	done   chan result // Gets the result instead of OnResult (CallWait)
+}
+
+// startJobLocked starts t as a background job. Unless wait is set, the
+// job isn't tied to ctx. Caller holds mu and has counted the call in
+// flight.
+func (r *ToolRegistry) startJobLocked(ctx context.Context, t *registeredTool, args map[string]interface{}, wait bool) *runningJob {
+	r.nextJob++
+	rj := &runningJob{
+		job: ToolJob{ID: strconv.Itoa(r.nextJob), Tool: t.tool.Name, Args: args, Started: r.now()},
+	}
+	if wait {
+		rj.done = make(chan result, 1)
+	} else {
+		ctx = context.WithoutCancel(ctx)
+	}
+	jobCtx, cancel := context.WithTimeout(ctx, t.policy.Timeout)
+	rj.cancel = cancel
+	r.jobs[rj.job.ID] = rj
+	go r.runJob(jobCtx, t, rj)
+	return rj
+}
+
+// runJob runs a background job and reports its progress and result.
+// A result that arrives after the job was canceled or timed out is
+// dropped.
+func (r *ToolRegistry) runJob(ctx context.Context, t *registeredTool, rj *runningJob) {
+	r.mu.Lock()
+	job, onStart := rj.job, r.OnJobStart
+	r.mu.Unlock()
+	if onStart != nil {
+		onStart(job)
+	}
+
+	progress := func(message string) {
+		r.mu.Lock()
+		rj.job.Progress = message
+		job, onProgress := rj.job, r.OnProgress
+		r.mu.Unlock()
+		if onProgress != nil {
+			onProgress(job, message)
+		}
+	}
+
+	start := time.Now()
+	out, err := t.run(ctx, job.Args, progress)
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		out, err = "", fmt.Errorf("%w after %v", ErrToolTimeout, t.policy.Timeout)
+	case ctx.Err() != nil:
+		out, err = "", ErrToolCanceled
+	}
+	rj.cancel()
+
+	r.mu.Lock()
+	delete(r.jobs, job.ID)
+	t.inFlight--
+	job, onResult := rj.job, r.OnResult
+	r.mu.Unlock()
+
+	r.record(t, time.Since(start), err)
+	if err != nil {
+		err = fmt.Errorf("%s: %w", t.tool.Name, err)
+	}
+	if rj.done != nil {
+		rj.done <- result{out, err}
+		return
+	}
+	if onResult != nil {
+		onResult(job, out, err)
+	}
+}
+
+// Jobs returns the background jobs in progress, oldest first.
+func (r *ToolRegistry) Jobs() []ToolJob {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	jobs := make([]ToolJob, 0, len(r.jobs))
+	for _, rj := range r.jobs {
+		jobs = append(jobs, rj.job)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		a, _ := strconv.Atoi(jobs[i].ID)
+		b, _ := strconv.Atoi(jobs[j].ID)
+		return a < b
+	})
+	return jobs
+}
+
+// Cancel cancels the background jobs whose ID or tool name is ref, or
+// every job if ref is empty, and returns how many it canceled. Each one
+// reports ErrToolCanceled through OnResult.
+func (r *ToolRegistry) Cancel(ref string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for id, rj := range r.jobs {
+		if ref == "" || ref == id || ref == rj.job.Tool {
+			rj.cancel()
+			n++
+		}
+	}
+	return n
+}
+
+// cancelTool lets the model stop background jobs.
+func (r *ToolRegistry) cancelTool() Tool {
+	return Tool{
+		Name:        "cancel_task",
+		Description: `Stop a background task that hasn't finished, like a search or a look around. Use when someone says "never mind", "stop searching" or "forget it".`,
+		Parameters: map[string]interface{}{
+			"task": map[string]interface{}{
+				"type":        "string",
+				"description": "The task number or tool name (like 'web_search'). Leave empty to stop all of them.",
+			},
+		},
+		Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
+			ref, _ := args["task"].(string)
+			switch n := r.Cancel(ref); n {
+			case 0:
+				return "No background task to stop", nil
+			case 1:
+				return "Stopped the task", nil
+			default:
+				return fmt.Sprintf("Stopped %d tasks", n), nil
+			}
+		},
+	}
+}
+
+// SetEnabled turns a tool on or off. Calls to a disabled tool fail with
+// ErrToolDisabled.
+func (r *ToolRegistry) SetEnabled(name string, enabled bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.tools[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrToolNotFound, name)
+	}
+	t.enabled = enabled
+	return nil
+}
+
+// Enabled reports whether the named tool exists and is enabled.
+func (r *ToolRegistry) Enabled(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.tools[name]
+	return ok && t.enabled
+}
+
+// Stats returns every tool's metrics, in registration order.
+func (r *ToolRegistry) Stats() []ToolStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats := make([]ToolStats, 0, len(r.order))
+	for _, name := range r.order {
+		t := r.tools[name]
+		s := ToolStats{
+			Name:         name,
+			Description:  t.tool.Description,
+			Enabled:      t.enabled,
+			Calls:        t.calls,
+			Errors:       t.errors,
+			Timeouts:     t.timeouts,
+			Rejected:     t.rejected,
+			InFlight:     t.inFlight,
+			MaxLatencyMs: float64(t.maxLatency) / float64(time.Millisecond),
+			LastError:    t.lastError,
+		}
+		if t.calls > 0 {
+			s.AvgLatencyMs = float64(t.totalLatency) / float64(t.calls) / float64(time.Millisecond)
+			s.LastCall = t.lastCall.Format(time.RFC3339)
+		}
+		stats = append(stats, s)
+	}
+	return stats
+}
+
+// Tools returns the registered tools, in registration order, with
+// handlers that go through Call.
+func (r *ToolRegistry) Tools() []Tool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tools := make([]Tool, len(r.order))
+	for i, name := range r.order {
+		tool := r.tools[name].tool
+		tool.Handler = r.handler(name)
+		tools[i] = tool
+	}
+	return tools
+}
+
+// handler returns a tool handler that calls name through the registry.
+func (r *ToolRegistry) handler(name string) func(context.Context, map[string]interface{}) (string, error) {
+	return func(ctx context.Context, args map[string]interface{}) (string, error) {
+		return r.Call(ctx, name, args)
+	}
+}
+
+// ConversationTools returns the tools for a conversation.Provider.
+// Every tool is included, so ones enabled later work without
+// re-registering; disabled ones return an error when called. Providers
+// take a full JSON Schema, so the parameters are wrapped in an object
+// with the required list.
+// Calls aren't tied to a model turn; each tool's timeout bounds them.
+func (r *ToolRegistry) ConversationTools() []conversation.Tool {
+	tools := r.Tools()
+	out := make([]conversation.Tool, len(tools))
+	for i, t := range tools {
+		params := t.Parameters
+		if params == nil {
+			params = map[string]interface{}{}
+		}
+		schema := map[string]any{"type": "object", "properties": params}
+		if len(t.Required) > 0 {
+			schema["required"] = t.Required
+		}
+		name := t.Name
+		out[i] = conversation.Tool{
+			Name:        t.Name,
+			Description: t.Description,
+			Parameters:  schema,
+			Handler: func(args map[string]any) (string, error) {
+				return r.Call(context.Background(), name, args)
+			},
+		}
+	}
+	return out
+}
+
+// MCPTools returns the tools to serve to other agents over MCP. Their
+// handlers use CallWait, so background tools return their result, and
+// a client that disconnects or cancels its request cancels the call.
+func (r *ToolRegistry) MCPTools() []mcp.Tool {
+	tools := r.Tools()
+	out := make([]mcp.Tool, len(tools))
+	for i, t := range tools {
+		name := t.Name
+		t.Handler = func(ctx context.Context, args map[string]interface{}) (string, error) {
+			return r.CallWait(ctx, name, args)
+		}
+		out[i] = mcp.Tool(t)
+	}
+	return out
+}
+
+// OpenAITools returns the tools for the OpenAI Realtime client, like
+// ConversationTools. The client wraps the parameters itself.
+func (r *ToolRegistry) OpenAITools() []openai.Tool {
+	tools := r.Tools()
+	out := make([]openai.Tool, len(tools))
+	for i, t := range tools {
+		name := t.Name
+		out[i] = openai.Tool{
+			Name:        t.Name,
+			Description: t.Description,
+			Parameters:  t.Parameters,
+			Required:    t.Required,
+			Handler: func(args map[string]interface{}) (string, error) {
+				return r.Call(context.Background(), name, args)
+			},
+		}
+	}
+	return out
+}
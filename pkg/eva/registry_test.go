@@ -0,0 +1,388 @@
+package eva
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func echoTool(name string) Tool {
+	return Tool{
+		Name:        name,
+		Description: "Echoes its arguments",
+		Parameters: map[string]interface{}{
+			"text": map[string]interface{}{"type": "string"},
+			"count": map[string]interface{}{
+				"type":    "integer",
+				"minimum": 1,
+				"maximum": 10,
+			},
+			"mode": map[string]interface{}{
+				"type": "string",
+				"enum": []string{"loud", "quiet"},
+			},
+			"tags": map[string]interface{}{
+				"type":  "array",
+				"items": map[string]interface{}{"type": "string"},
+			},
+		},
+		Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
+			count, _ := args["count"].(float64)
+			text, _ := args["text"].(string)
+			return strings.Repeat(text, max(int(count), 1)), nil
+		},
+	}
+}
+
+func TestValidateArgs(t *testing.T) {
+	params := echoTool("echo").Parameters
+	tests := []struct {
+		args map[string]interface{}
+		want string // Error substring, "" = valid
+	}{
+		{map[string]interface{}{}, ""},
+		{map[string]interface{}{"text": "hi", "count": 3.0, "mode": "loud"}, ""},
+		{map[string]interface{}{"count": "4"}, ""},
+		{map[string]interface{}{"text": nil}, ""},
+		{map[string]interface{}{"tags": []interface{}{"a", "b"}}, ""},
+		{map[string]interface{}{"text": 5.0}, "text: want a string, got a number"},
+		{map[string]interface{}{"count": 2.5}, "want a whole number"},
+		{map[string]interface{}{"count": 11.0}, "above the maximum 10"},
+		{map[string]interface{}{"count": 0.0}, "below the minimum 1"},
+		{map[string]interface{}{"count": "lots"}, "want a number, got a string"},
+		{map[string]interface{}{"mode": "whisper"}, "whisper is not one of [loud quiet]"},
+		{map[string]interface{}{"tags": []interface{}{"a", 1.0}}, "item 1: want a string"},
+		{map[string]interface{}{"colour": "red"}, `unknown argument "colour"`},
+		{map[string]interface{}{"colour": "red", "text": true}, `unknown argument "colour"; text: want a string`},
+	}
+	for _, tt := range tests {
+		_, err := validateArgs(params, nil, tt.args)
+		switch {
+		case tt.want == "" && err != nil:
+			t.Errorf("validateArgs(%v) = %v, want valid", tt.args, err)
+		case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+			t.Errorf("validateArgs(%v) = %v, want %q", tt.args, err, tt.want)
+		case err != nil && !errors.Is(err, ErrInvalidArgs):
+			t.Errorf("validateArgs(%v) error doesn't wrap ErrInvalidArgs", tt.args)
+		}
+	}
+
+	// Numeric strings reach the handler as float64
+	got, _ := validateArgs(params, nil, map[string]interface{}{"count": " 4 "})
+	if got["count"] != 4.0 {
+		t.Errorf("count = %#v, want 4.0", got["count"])
+	}
+}
+
+func TestValidateArgs_Required(t *testing.T) {
+	params := map[string]interface{}{
+		"duration": map[string]interface{}{"type": "integer"},
+		"label":    map[string]interface{}{"type": "string"},
+	}
+	required := []string{"duration"}
+	tests := []struct {
+		args map[string]interface{}
+		want string // Error substring, "" = valid
+	}{
+		{map[string]interface{}{"duration": 5.0}, ""},
+		{map[string]interface{}{"duration": 5.0, "label": "pasta"}, ""},
+		{map[string]interface{}{}, `missing required argument "duration"`},
+		{map[string]interface{}{"duration": nil, "label": "pasta"}, `missing required argument "duration"`},
+		{map[string]interface{}{"label": 1.0}, `missing required argument "duration"; label: want a string`},
+	}
+	for _, tt := range tests {
+		_, err := validateArgs(params, required, tt.args)
+		switch {
+		case tt.want == "" && err != nil:
+			t.Errorf("validateArgs(%v) = %v, want valid", tt.args, err)
+		case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+			t.Errorf("validateArgs(%v) = %v, want %q", tt.args, err, tt.want)
+		}
+	}
+}
+
+func TestValidateArgs_NumericEnum(t *testing.T) {
+	params := map[string]interface{}{
+		"speed": map[string]interface{}{"type": "integer", "enum": []int{1, 2, 3}},
+		"scale": map[string]interface{}{"type": "number", "enum": []float64{0.5, 1.5}},
+	}
+	tests := []struct {
+		args map[string]interface{}
+		want string // Error substring, "" = valid
+	}{
+		{map[string]interface{}{"speed": 2.0}, ""},
+		{map[string]interface{}{"speed": "3"}, ""},
+		{map[string]interface{}{"scale": 1.5}, ""},
+		{map[string]interface{}{"speed": 4.0}, "4 is not one of [1 2 3]"},
+		{map[string]interface{}{"scale": 1.0}, "1 is not one of [0.5 1.5]"},
+	}
+	for _, tt := range tests {
+		_, err := validateArgs(params, nil, tt.args)
+		switch {
+		case tt.want == "" && err != nil:
+			t.Errorf("validateArgs(%v) = %v, want valid", tt.args, err)
+		case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+			t.Errorf("validateArgs(%v) = %v, want %q", tt.args, err, tt.want)
+		}
+	}
+}
+
+func TestToolRegistry_Register(t *testing.T) {
+	r := NewToolRegistry()
+	if err := r.Register(echoTool("echo"), ToolPolicy{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register(echoTool("echo"), ToolPolicy{}); err == nil {
+		t.Error("registered a duplicate name")
+	}
+	bad := echoTool("bad")
+	bad.Parameters = map[string]interface{}{"x": map[string]interface{}{"description": "no type"}}
+	if err := r.Register(bad, ToolPolicy{}); err == nil {
+		t.Error("registered a parameter without a type")
+	}
+	bad = echoTool("enum")
+	bad.Parameters = map[string]interface{}{"x": map[string]interface{}{"type": "integer", "enum": []int8{1, 2}}}
+	if err := r.Register(bad, ToolPolicy{}); err == nil {
+		t.Error("registered an enum it can't check")
+	}
+	bad = echoTool("required")
+	bad.Required = []string{"nope"}
+	if err := r.Register(bad, ToolPolicy{}); err == nil {
+		t.Error("registered an undeclared required parameter")
+	}
+}
+
+func TestToolRegistry_Call(t *testing.T) {
+	r := NewToolRegistry()
+	r.Register(echoTool("echo"), ToolPolicy{})
+
+	out, err := r.Call(context.Background(), "echo", map[string]interface{}{"text": "ab", "count": "2"})
+	if err != nil || out != "abab" {
+		t.Errorf("Call = %q, %v, want abab", out, err)
+	}
+	if _, err := r.Call(context.Background(), "echo", map[string]interface{}{"count": 99.0}); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("invalid args error = %v", err)
+	}
+	if _, err := r.Call(context.Background(), "nope", nil); !errors.Is(err, ErrToolNotFound) {
+		t.Errorf("unknown tool error = %v", err)
+	}
+
+	r.SetEnabled("echo", false)
+	if _, err := r.Call(context.Background(), "echo", nil); !errors.Is(err, ErrToolDisabled) {
+		t.Errorf("disabled tool error = %v", err)
+	}
+	if err := r.SetEnabled("nope", true); !errors.Is(err, ErrToolNotFound) {
+		t.Errorf("SetEnabled(nope) = %v", err)
+	}
+
+	s := r.Stats()[0]
+	if s.Enabled || s.Calls != 1 || s.Rejected != 2 || s.Errors != 0 {
+		t.Errorf("stats = %+v, want disabled, 1 call, 2 rejected", s)
+	}
+}
+
+func TestToolRegistry_Timeout(t *testing.T) {
+	r := NewToolRegistry()
+	release := make(chan struct{})
+	slow := Tool{
+		Name: "slow",
+		Handler: func(context.Context, map[string]interface{}) (string, error) {
+			<-release
+			return "done", nil
+		},
+	}
+	r.Register(slow, ToolPolicy{Timeout: 20 * time.Millisecond, MaxConcurrent: 1})
+
+	var calls []error
+	r.OnCall = func(name string, latency time.Duration, err error) { calls = append(calls, err) }
+
+	if _, err := r.Call(context.Background(), "slow", nil); !errors.Is(err, ErrToolTimeout) {
+		t.Fatalf("Call = %v, want ErrToolTimeout", err)
+	}
+	// The timed-out handler still holds the only slot
+	if _, err := r.Call(context.Background(), "slow", nil); !errors.Is(err, ErrToolBusy) {
+		t.Errorf("second Call = %v, want ErrToolBusy", err)
+	}
+	close(release)
+
+	deadline := time.Now().Add(time.Second)
+	for r.Stats()[0].InFlight > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if out, err := r.Call(context.Background(), "slow", nil); err != nil || out != "done" {
+		t.Errorf("Call after release = %q, %v", out, err)
+	}
+
+	s := r.Stats()[0]
+	if s.Calls != 2 || s.Errors != 1 || s.Timeouts != 1 || s.Rejected != 1 || !strings.Contains(s.LastError, "timed out") {
+		t.Errorf("stats = %+v", s)
+	}
+	if len(calls) != 2 || !errors.Is(calls[0], ErrToolTimeout) || calls[1] != nil {
+		t.Errorf("OnCall got %v", calls)
+	}
+}
+
+func TestToolRegistry_TimeoutCancelsHandler(t *testing.T) {
+	r := NewToolRegistry()
+	stopped := make(chan error, 1)
+	motion := Tool{
+		Name: "motion",
+		Handler: func(ctx context.Context, _ map[string]interface{}) (string, error) {
+			<-ctx.Done()
+			stopped <- ctx.Err()
+			return "", ctx.Err()
+		},
+	}
+	r.Register(motion, ToolPolicy{Timeout: 20 * time.Millisecond, MaxConcurrent: 1})
+
+	if _, err := r.Call(context.Background(), "motion", nil); !errors.Is(err, ErrToolTimeout) {
+		t.Fatalf("Call = %v, want ErrToolTimeout", err)
+	}
+	select {
+	case err := <-stopped:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("handler ctx error = %v, want DeadlineExceeded", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("handler's context wasn't canceled at the timeout")
+	}
+
+	// The canceled handler gives its slot back
+	deadline := time.Now().Add(time.Second)
+	for r.Stats()[0].InFlight > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if s := r.Stats()[0]; s.InFlight != 0 {
+		t.Errorf("InFlight = %d after the handler stopped, want 0", s.InFlight)
+	}
+}
+
+func TestToolRegistry_Concurrency(t *testing.T) {
+	r := NewToolRegistry()
+	var mu sync.Mutex
+	running, peak := 0, 0
+	tool := Tool{
+		Name: "busy",
+		Handler: func(context.Context, map[string]interface{}) (string, error) {
+			mu.Lock()
+			running++
+			peak = max(peak, running)
+			mu.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			mu.Lock()
+			running--
+			mu.Unlock()
+			return "", nil
+		},
+	}
+	r.Register(tool, ToolPolicy{MaxConcurrent: 2})
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.Call(context.Background(), "busy", nil)
+		}()
+	}
+	wg.Wait()
+	if peak > 2 {
+		t.Errorf("peak concurrency = %d, want <= 2", peak)
+	}
+	if s := r.Stats()[0]; s.Calls+s.Rejected != 8 {
+		t.Errorf("calls %d + rejected %d, want 8", s.Calls, s.Rejected)
+	}
+}
+
+func TestToolRegistry_RateLimit(t *testing.T) {
+	r := NewToolRegistry()
+	clock := time.Unix(1000, 0)
+	r.now = func() time.Time { return clock }
+	r.Register(echoTool("echo"), ToolPolicy{MaxPerMinute: 2})
+
+	for i := range 2 {
+		if _, err := r.Call(context.Background(), "echo", nil); err != nil {
+			t.Fatalf("call %d: %v", i, err)
+		}
+	}
+	if _, err := r.Call(context.Background(), "echo", nil); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("third call = %v, want ErrRateLimited", err)
+	}
+	clock = clock.Add(61 * time.Second)
+	if _, err := r.Call(context.Background(), "echo", nil); err != nil {
+		t.Errorf("call after a minute = %v", err)
+	}
+}
+
+func TestToolRegistry_Convert(t *testing.T) {
+	r := NewToolRegistry()
+	r.Register(echoTool("echo"), ToolPolicy{})
+	r.Register(echoTool("other"), ToolPolicy{Disabled: true})
+
+	conv := r.ConversationTools()
+	oai := r.OpenAITools()
+	if len(conv) != 2 || len(oai) != 2 || conv[0].Name != "echo" || oai[1].Name != "other" {
+		t.Fatalf("converted %d/%d tools", len(conv), len(oai))
+	}
+	if conv[0].Parameters["type"] != "object" || conv[0].Parameters["properties"] == nil {
+		t.Errorf("parameters = %v, want an object schema", conv[0].Parameters)
+	}
+	if _, ok := conv[0].Parameters["required"]; ok {
+		t.Errorf("parameters = %v, want no required list", conv[0].Parameters)
+	}
+
+	// Converted handlers go through the registry
+	if _, err := oai[0].Handler(map[string]interface{}{"count": 0.0}); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("openai handler = %v, want validation", err)
+	}
+	if out, err := oai[0].Handler(map[string]interface{}{"text": "y"}); err != nil || out != "y" {
+		t.Errorf("openai handler = %q, %v", out, err)
+	}
+	if _, err := conv[0].Handler(map[string]interface{}{"count": 0.0}); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("conversation handler = %v, want validation", err)
+	}
+	if _, err := conv[1].Handler(nil); !errors.Is(err, ErrToolDisabled) {
+		t.Errorf("conversation handler = %v, want ErrToolDisabled", err)
+	}
+	if out, err := conv[0].Handler(map[string]interface{}{"text": "x"}); err != nil || out != "x" {
+		t.Errorf("conversation handler = %q, %v", out, err)
+	}
+}
+
+func TestToolRegistry_ConvertRequired(t *testing.T) {
+	r := NewToolRegistry()
+	tool := echoTool("echo")
+	tool.Required = []string{"text"}
+	r.Register(tool, ToolPolicy{})
+
+	conv := r.ConversationTools()[0]
+	if got, _ := conv.Parameters["required"].([]string); len(got) != 1 || got[0] != "text" {
+		t.Errorf("conversation required = %v, want [text]", conv.Parameters["required"])
+	}
+	if got := r.OpenAITools()[0].Required; len(got) != 1 || got[0] != "text" {
+		t.Errorf("openai required = %v, want [text]", got)
+	}
+	if got := r.MCPTools()[0].Required; len(got) != 1 || got[0] != "text" {
+		t.Errorf("mcp required = %v, want [text]", got)
+	}
+	if _, err := conv.Handler(map[string]interface{}{"count": 2.0}); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("call without text = %v, want ErrInvalidArgs", err)
+	}
+}
+
+func TestNewRegistry(t *testing.T) {
+	r, err := NewRegistry(ToolsConfig{})
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+	if !r.Enabled("get_time") || r.Enabled("save_spark") {
+		t.Error("want the built-in tools and no spark tools without a store")
+	}
+	// Eva's own schemas pass their own validation
+	if _, err := r.Call(context.Background(), "set_volume", map[string]interface{}{"level": 150.0}); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("set_volume(150) = %v, want out of range", err)
+	}
+}
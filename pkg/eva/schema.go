@@ -0,0 +1,183 @@
+package eva
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// validateArgs checks tool arguments against the tool's parameter
+// schema (a JSON Schema "properties" map) and required parameters, and
+// returns them ready for the handler. Numbers sent as strings ("5") are
+// converted; missing required arguments, unknown arguments, wrong
+// types, values outside an enum and numbers outside minimum/maximum
+// are errors naming every problem, so the model can correct its call.
+func validateArgs(params map[string]interface{}, required []string, args map[string]interface{}) (map[string]interface{}, error) {
+	out := make(map[string]interface{}, len(args))
+	var problems []string
+
+	for _, name := range required {
+		if args[name] == nil {
+			problems = append(problems, fmt.Sprintf("missing required argument %q", name))
+		}
+	}
+
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := args[name]
+		if value == nil {
+			continue // Same as omitted
+		}
+		prop, ok := params[name].(map[string]interface{})
+		if !ok {
+			problems = append(problems, fmt.Sprintf("unknown argument %q", name))
+			continue
+		}
+		v, err := validateValue(prop, value)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		out[name] = v
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidArgs, strings.Join(problems, "; "))
+	}
+	return out, nil
+}
+
+// validateValue checks one value against its property schema.
+func validateValue(prop map[string]interface{}, value interface{}) (interface{}, error) {
+	typ, _ := prop["type"].(string)
+	switch typ {
+	case "string":
+		if _, ok := value.(string); !ok {
+			return nil, fmt.Errorf("want a string, got %s", jsonType(value))
+		}
+	case "integer", "number":
+		n, ok := toFloat(value)
+		if !ok || math.IsNaN(n) || math.IsInf(n, 0) {
+			return nil, fmt.Errorf("want a number, got %s", jsonType(value))
+		}
+		if typ == "integer" && n != math.Trunc(n) {
+			return nil, fmt.Errorf("want a whole number, got %v", n)
+		}
+		if lo, ok := toFloat(prop["minimum"]); ok && n < lo {
+			return nil, fmt.Errorf("%v is below the minimum %v", n, lo)
+		}
+		if hi, ok := toFloat(prop["maximum"]); ok && n > hi {
+			return nil, fmt.Errorf("%v is above the maximum %v", n, hi)
+		}
+		value = n // Handlers read numbers as float64, as decoded from JSON
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return nil, fmt.Errorf("want true or false, got %s", jsonType(value))
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("want an array, got %s", jsonType(value))
+		}
+		if itemProp, ok := prop["items"].(map[string]interface{}); ok {
+			checked := make([]interface{}, len(items))
+			for i, item := range items {
+				v, err := validateValue(itemProp, item)
+				if err != nil {
+					return nil, fmt.Errorf("item %d: %w", i, err)
+				}
+				checked[i] = v
+			}
+			value = checked
+		}
+	case "object":
+		if _, ok := value.(map[string]interface{}); !ok {
+			return nil, fmt.Errorf("want an object, got %s", jsonType(value))
+		}
+	}
+
+	if enum := enumValues(prop["enum"]); enum != nil {
+		switch value.(type) {
+		case string, float64, bool:
+		default:
+			return nil, fmt.Errorf("want one of %v, got %s", enum, jsonType(value))
+		}
+		for _, e := range enum {
+			switch e.(type) {
+			case int, int64, float32:
+				e, _ = toFloat(e)
+			}
+			if e == value {
+				return value, nil
+			}
+		}
+		return nil, fmt.Errorf("%v is not one of %v", value, enum)
+	}
+	return value, nil
+}
+
+// enumValues returns a schema enum as a list of comparable values, or
+// nil for a type it can't read (Register refuses those).
+func enumValues(enum interface{}) []interface{} {
+	switch e := enum.(type) {
+	case []string:
+		return anySlice(e)
+	case []int:
+		return anySlice(e)
+	case []float64:
+		return anySlice(e)
+	case []interface{}:
+		return e
+	}
+	return nil
+}
+
+func anySlice[T any](s []T) []interface{} {
+	values := make([]interface{}, len(s))
+	for i, v := range s {
+		values[i] = v
+	}
+	return values
+}
+
+// toFloat converts a JSON or Go number, or a numeric string, to float64.
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// jsonType names the JSON type of a decoded value.
+func jsonType(v interface{}) string {
+	switch v.(type) {
+	case string:
+		return "a string"
+	case float64, int, int64, float32:
+		return "a number"
+	case bool:
+		return "a boolean"
+	case []interface{}:
+		return "an array"
+	case map[string]interface{}:
+		return "an object"
+	}
+	return fmt.Sprintf("%T", v)
+}
@@ -6,7 +6,11 @@ import (
 	"strings"
 	"time"
 
+	"github.com/teslashibe/go-reachy/pkg/audio"
+	"github.com/teslashibe/go-reachy/pkg/mcp"
+	"github.com/teslashibe/go-reachy/pkg/memory"
 	"github.com/teslashibe/go-reachy/pkg/robot"
+	"github.com/teslashibe/go-reachy/pkg/schedule"
 	"github.com/teslashibe/go-reachy/pkg/spark"
 	"github.com/teslashibe/go-reachy/pkg/vision"
 )
@@ -26,7 +30,8 @@ func Tools(cfg ToolsConfig) []Tool {
 					"description": "Direction to look",
 				},
 			},
-			Handler: func(args map[string]interface{}) (string, error) {
+			Required: []string{"direction"},
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
 				dir, _ := args["direction"].(string)
 				var roll, pitch, yaw float64
 
@@ -59,7 +64,8 @@ func Tools(cfg ToolsConfig) []Tool {
 					"description": "Name of the emotion to play (e.g., 'yes1', 'surprised1', 'dance1', 'laughing1')",
 				},
 			},
-			Handler: func(args map[string]interface{}) (string, error) {
+			Required: []string{"emotion"},
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
 				emotionName, _ := args["emotion"].(string)
 				if emotionName == "" {
 					return "Please specify an emotion name", nil
@@ -90,6 +96,15 @@ func Tools(cfg ToolsConfig) []Tool {
 					}
 				}()
 
+				// The sound mixes under Eva's voice on the effects channel
+				if emotion.HasSound && cfg.AudioPlayer != nil {
+					go func() {
+						if err := cfg.AudioPlayer.PlayWAV(audio.ChannelEffects, emotion.SoundPath); err != nil {
+							fmt.Printf("🎭 Emotion sound error: %v\n", err)
+						}
+					}()
+				}
+
 				return fmt.Sprintf("Playing emotion: %s - %s", emotionName, emotion.Description), nil
 			},
 		},
@@ -97,7 +112,7 @@ func Tools(cfg ToolsConfig) []Tool {
 			Name:        "stop_emotion",
 			Description: "Stop the currently playing emotion animation.",
 			Parameters:  map[string]interface{}{},
-			Handler: func(args map[string]interface{}) (string, error) {
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
 				if cfg.Emotions != nil {
 					cfg.Emotions.Stop()
 					return "Stopped emotion playback", nil
@@ -109,7 +124,7 @@ func Tools(cfg ToolsConfig) []Tool {
 			Name:        "list_emotions",
 			Description: "List available emotion categories. Use this to see what emotions you can play.",
 			Parameters:  map[string]interface{}{},
-			Handler: func(args map[string]interface{}) (string, error) {
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
 				if cfg.Emotions == nil {
 					return "Emotion system not available", nil
 				}
@@ -126,14 +141,14 @@ func Tools(cfg ToolsConfig) []Tool {
 			Name:        "wave_hello",
 			Description: "Wave your antennas to greet someone friendly.",
 			Parameters:  map[string]interface{}{},
-			Handler: func(args map[string]interface{}) (string, error) {
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
 				// Use RateController for motion (Issue #139)
 				if cfg.Motion != nil {
-					for i := 0; i < 3; i++ {
+					for i := 0; i < 3 && ctx.Err() == nil; i++ {
 						cfg.Motion.SetAntennas(0.4, 0)
-						time.Sleep(150 * time.Millisecond)
+						sleepCtx(ctx, 150*time.Millisecond)
 						cfg.Motion.SetAntennas(0, 0.4)
-						time.Sleep(150 * time.Millisecond)
+						sleepCtx(ctx, 150*time.Millisecond)
 					}
 					cfg.Motion.SetAntennas(0, 0)
 				}
@@ -144,76 +159,11 @@ func Tools(cfg ToolsConfig) []Tool {
 			Name:        "get_time",
 			Description: "Get the current time and date. Use when someone asks what time it is or what day it is.",
 			Parameters:  map[string]interface{}{},
-			Handler: func(args map[string]interface{}) (string, error) {
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
 				now := time.Now()
 				return now.Format("It's Monday, January 2 at 3:04 PM"), nil
 			},
 		},
-		{
-			Name:        "set_timer",
-			Description: "Set a timer for a specified duration. Use when someone asks to set a timer or reminder.",
-			Parameters: map[string]interface{}{
-				"duration": map[string]interface{}{
-					"type":        "integer",
-					"description": "Number of minutes or seconds",
-				},
-				"unit": map[string]interface{}{
-					"type":        "string",
-					"enum":        []string{"seconds", "minutes"},
-					"description": "Time unit: seconds or minutes",
-				},
-				"label": map[string]interface{}{
-					"type":        "string",
-					"description": "Optional label for the timer like 'pasta' or 'meeting'",
-				},
-			},
-			Handler: func(args map[string]interface{}) (string, error) {
-				duration := 1
-				if d, ok := args["duration"].(float64); ok {
-					duration = int(d)
-				}
-
-				unit := "minutes"
-				if u, ok := args["unit"].(string); ok && u != "" {
-					unit = u
-				}
-
-				label, _ := args["label"].(string)
-
-				var wait time.Duration
-				if unit == "seconds" {
-					wait = time.Duration(duration) * time.Second
-				} else {
-					wait = time.Duration(duration) * time.Minute
-				}
-
-				fmt.Printf("⏱️  Timer set: %d %s (label: %s)\n", duration, unit, label)
-
-				go func() {
-					time.Sleep(wait)
-
-					msg := "Timer done!"
-					if label != "" {
-						msg = fmt.Sprintf("Your %s timer is done!", label)
-					}
-
-					fmt.Printf("🔔 Timer finished: %s\n", msg)
-
-					if cfg.AudioPlayer != nil {
-						if err := cfg.AudioPlayer.SpeakText(msg); err != nil {
-							fmt.Printf("🔔 Timer TTS error: %v\n", err)
-						}
-					} else {
-						fmt.Println("🔔 Error: AudioPlayer is nil, cannot announce timer")
-					}
-				}()
-
-				if label != "" {
-					return fmt.Sprintf("Timer set for %d %s - I'll let you know when your %s is ready!", duration, unit, label), nil
-				}
-				return fmt.Sprintf("Timer set for %d %s!", duration, unit), nil
-			},
-		},
 		{
 			Name:        "remember_person",
 			Description: "Remember something about a person you're talking to. Use this to store facts about people.",
@@ -227,7 +177,8 @@ func Tools(cfg ToolsConfig) []Tool {
 					"description": "A fact to remember about them",
 				},
 			},
-			Handler: func(args map[string]interface{}) (string, error) {
+			Required: []string{"name", "fact"},
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
 				name, _ := args["name"].(string)
 				fact, _ := args["fact"].(string)
 
@@ -247,7 +198,8 @@ func Tools(cfg ToolsConfig) []Tool {
 					"description": "The person's name to recall",
 				},
 			},
-			Handler: func(args map[string]interface{}) (string, error) {
+			Required: []string{"name"},
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
 				name, _ := args["name"].(string)
 
 				if mem != nil && name != "" {
@@ -264,13 +216,14 @@ func Tools(cfg ToolsConfig) []Tool {
 			Name:        "look_around",
 			Description: "Look around the room to see who or what is there.",
 			Parameters:  map[string]interface{}{},
-			Handler: func(args map[string]interface{}) (string, error) {
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
 				// Use RateController for motion (Issue #139)
 				if cfg.Motion != nil {
 					cfg.Motion.SetBaseHead(robot.Offset{Yaw: 0.4})
-					time.Sleep(500 * time.Millisecond)
-					cfg.Motion.SetBaseHead(robot.Offset{Yaw: -0.4})
-					time.Sleep(500 * time.Millisecond)
+					if sleepCtx(ctx, 500*time.Millisecond) == nil {
+						cfg.Motion.SetBaseHead(robot.Offset{Yaw: -0.4})
+						sleepCtx(ctx, 500*time.Millisecond)
+					}
 					cfg.Motion.SetBaseHead(robot.Offset{})
 				}
 				return "Looked around the room", nil
@@ -286,7 +239,8 @@ func Tools(cfg ToolsConfig) []Tool {
 					"description": "Direction to rotate body",
 				},
 			},
-			Handler: func(args map[string]interface{}) (string, error) {
+			Required: []string{"direction"},
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
 				dir, _ := args["direction"].(string)
 				var yaw float64
 
@@ -315,14 +269,14 @@ func Tools(cfg ToolsConfig) []Tool {
 			Name:        "nod_yes",
 			Description: "Nod your head to agree with something.",
 			Parameters:  map[string]interface{}{},
-			Handler: func(args map[string]interface{}) (string, error) {
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
 				// Use RateController for motion (Issue #139)
 				if cfg.Motion != nil {
-					for i := 0; i < 2; i++ {
+					for i := 0; i < 2 && ctx.Err() == nil; i++ {
 						cfg.Motion.SetBaseHead(robot.Offset{Pitch: 0.15})
-						time.Sleep(200 * time.Millisecond)
+						sleepCtx(ctx, 200*time.Millisecond)
 						cfg.Motion.SetBaseHead(robot.Offset{Pitch: -0.1})
-						time.Sleep(200 * time.Millisecond)
+						sleepCtx(ctx, 200*time.Millisecond)
 					}
 					cfg.Motion.SetBaseHead(robot.Offset{})
 				}
@@ -333,14 +287,14 @@ func Tools(cfg ToolsConfig) []Tool {
 			Name:        "shake_head_no",
 			Description: "Shake your head to disagree with something.",
 			Parameters:  map[string]interface{}{},
-			Handler: func(args map[string]interface{}) (string, error) {
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
 				// Use RateController for motion (Issue #139)
 				if cfg.Motion != nil {
-					for i := 0; i < 2; i++ {
+					for i := 0; i < 2 && ctx.Err() == nil; i++ {
 						cfg.Motion.SetBaseHead(robot.Offset{Yaw: 0.2})
-						time.Sleep(200 * time.Millisecond)
+						sleepCtx(ctx, 200*time.Millisecond)
 						cfg.Motion.SetBaseHead(robot.Offset{Yaw: -0.2})
-						time.Sleep(200 * time.Millisecond)
+						sleepCtx(ctx, 200*time.Millisecond)
 					}
 					cfg.Motion.SetBaseHead(robot.Offset{})
 				}
@@ -358,7 +312,8 @@ func Tools(cfg ToolsConfig) []Tool {
 					"maximum":     100,
 				},
 			},
-			Handler: func(args map[string]interface{}) (string, error) {
+			Required: []string{"level"},
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
 				level := 100
 				if l, ok := args["level"].(float64); ok {
 					level = int(l)
@@ -378,51 +333,8 @@ func Tools(cfg ToolsConfig) []Tool {
 					"description": "What to focus on: 'general' for overall scene, 'people' to look for people, or a specific thing to look for",
 				},
 			},
-			Handler: func(args map[string]interface{}) (string, error) {
-				focus, _ := args["focus"].(string)
-				if focus == "" {
-					focus = "general"
-				}
-
-				fmt.Printf("👁️  describe_scene called (focus: %s)\n", focus)
-
-				if cfg.Vision == nil {
-					fmt.Println("👁️  Error: Vision provider is nil")
-					return "I cannot see right now - camera not connected", nil
-				}
-
-				if cfg.GoogleAPIKey == "" {
-					fmt.Println("👁️  Error: Google API key not set")
-					return "I cannot see right now - vision not configured", nil
-				}
-
-				fmt.Println("👁️  Capturing frame...")
-				imageData, err := cfg.Vision.CaptureFrame()
-				if err != nil {
-					fmt.Printf("👁️  Frame capture error: %v\n", err)
-					return fmt.Sprintf("Could not capture image: %v", err), nil
-				}
-				fmt.Printf("👁️  Captured %d bytes\n", len(imageData))
-
-				var prompt string
-				switch focus {
-				case "people":
-					prompt = "Describe any people you see in this image. How many people are there? What are they doing? Where are they positioned (left, center, right)? Be concise."
-				case "general":
-					prompt = "Briefly describe what you see in this image. Mention the setting, any people, and notable objects. Keep it to 2-3 sentences."
-				default:
-					prompt = fmt.Sprintf("Look at this image and tell me if you can see: %s. Describe what you find. Be concise.", focus)
-				}
-
-				fmt.Println("👁️  Calling Gemini Flash...")
-				description, err := vision.GeminiVision(cfg.GoogleAPIKey, imageData, prompt)
-				if err != nil {
-					fmt.Printf("👁️  Gemini error: %v\n", err)
-					return fmt.Sprintf("Vision error: %v", err), nil
-				}
-				fmt.Printf("👁️  Gemini response: %s\n", description)
-
-				return description, nil
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
+				return describeScene(ctx, cfg, args, nil)
 			},
 		},
 		{
@@ -434,7 +346,7 @@ func Tools(cfg ToolsConfig) []Tool {
 					"description": "What to look for: 'all' for everything, 'animals' for cats/dogs/birds, 'people', or a specific object like 'cat' or 'cup'",
 				},
 			},
-			Handler: func(args map[string]interface{}) (string, error) {
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
 				target, _ := args["target"].(string)
 				if target == "" {
 					target = "all"
@@ -534,26 +446,9 @@ func Tools(cfg ToolsConfig) []Tool {
 					"description": "The search query to look up",
 				},
 			},
-			Handler: func(args map[string]interface{}) (string, error) {
-				query, _ := args["query"].(string)
-				if query == "" {
-					return "I need a search query to look up", nil
-				}
-
-				fmt.Printf("🌐 web_search called (query: %s)\n", query)
-
-				if cfg.GoogleAPIKey == "" {
-					return "Web search not configured", nil
-				}
-
-				result, err := vision.WebSearch(cfg.GoogleAPIKey, query)
-				if err != nil {
-					fmt.Printf("🌐 Search error: %v\n", err)
-					return fmt.Sprintf("Search failed: %v", err), nil
-				}
-
-				fmt.Printf("🌐 Search result: %s\n", result)
-				return result, nil
+			Required: []string{"query"},
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
+				return webSearch(ctx, cfg, args, nil)
 			},
 		},
 		{
@@ -577,35 +472,9 @@ func Tools(cfg ToolsConfig) []Tool {
 					"description": "Cabin class: economy, business, or first",
 				},
 			},
-			Handler: func(args map[string]interface{}) (string, error) {
-				origin, _ := args["origin"].(string)
-				destination, _ := args["destination"].(string)
-				date, _ := args["date"].(string)
-				cabinClass, _ := args["cabin_class"].(string)
-				if cabinClass == "" {
-					cabinClass = "economy"
-				}
-
-				fmt.Printf("✈️  search_flights called (from: %s, to: %s, date: %s, class: %s)\n",
-					origin, destination, date, cabinClass)
-
-				if cfg.GoogleAPIKey == "" {
-					return "Flight search not configured", nil
-				}
-
-				query := fmt.Sprintf(
-					"Find specific flights from %s to %s on %s in %s class. "+
-						"I need: airline names, departure times, arrival times, flight numbers, and prices in USD. "+
-						"Search Google Flights or airline websites for real current availability.",
-					origin, destination, date, cabinClass)
-
-				result, err := vision.WebSearch(cfg.GoogleAPIKey, query)
-				if err != nil {
-					return fmt.Sprintf("Flight search failed: %v", err), nil
-				}
-
-				fmt.Printf("✈️  Flight search result: %s\n", result)
-				return result, nil
+			Required: []string{"origin", "destination", "date"},
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
+				return searchFlights(ctx, cfg, args, nil)
 			},
 		},
 		{
@@ -617,7 +486,7 @@ func Tools(cfg ToolsConfig) []Tool {
 					"description": "Name or description of the person to find",
 				},
 			},
-			Handler: func(args map[string]interface{}) (string, error) {
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
 				person, _ := args["person"].(string)
 				if person == "" {
 					person = "anyone"
@@ -630,7 +499,7 @@ func Tools(cfg ToolsConfig) []Tool {
 				// Use RateController for motion (Issue #139)
 				if cfg.Motion != nil {
 					cfg.Motion.SetBaseHead(robot.Offset{Yaw: 0.3})
-					time.Sleep(400 * time.Millisecond)
+					sleepCtx(ctx, 400*time.Millisecond)
 				}
 
 				imageData, err := cfg.Vision.CaptureFrame()
@@ -642,9 +511,12 @@ func Tools(cfg ToolsConfig) []Tool {
 					}
 				}
 
+				if err := ctx.Err(); err != nil {
+					return "", err
+				}
 				if cfg.Motion != nil {
 					cfg.Motion.SetBaseHead(robot.Offset{Yaw: -0.3})
-					time.Sleep(400 * time.Millisecond)
+					sleepCtx(ctx, 400*time.Millisecond)
 				}
 
 				imageData, err = cfg.Vision.CaptureFrame()
@@ -658,7 +530,10 @@ func Tools(cfg ToolsConfig) []Tool {
 
 				if cfg.Motion != nil {
 					cfg.Motion.SetBaseHead(robot.Offset{})
-					time.Sleep(300 * time.Millisecond)
+					sleepCtx(ctx, 300*time.Millisecond)
+				}
+				if err := ctx.Err(); err != nil {
+					return "", err
 				}
 
 				imageData, err = cfg.Vision.CaptureFrame()
@@ -689,7 +564,8 @@ func Tools(cfg ToolsConfig) []Tool {
 					"description": "The value to store",
 				},
 			},
-			Handler: func(args map[string]interface{}) (string, error) {
+			Required: []string{"key", "value"},
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
 				key, _ := args["key"].(string)
 				value, _ := args["value"].(string)
 
@@ -709,7 +585,8 @@ func Tools(cfg ToolsConfig) []Tool {
 					"description": "The key to look up",
 				},
 			},
-			Handler: func(args map[string]interface{}) (string, error) {
+			Required: []string{"key"},
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
 				key, _ := args["key"].(string)
 
 				if mem != nil && key != "" {
@@ -726,7 +603,7 @@ func Tools(cfg ToolsConfig) []Tool {
 			Name:        "list_context",
 			Description: "List all stored context keys. Use this to see what situational facts you have stored.",
 			Parameters:  map[string]interface{}{},
-			Handler: func(args map[string]interface{}) (string, error) {
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
 				if mem != nil {
 					keys := mem.GetContextKeys()
 					if len(keys) == 0 {
@@ -757,7 +634,8 @@ func Tools(cfg ToolsConfig) []Tool {
 					"description": "Optional description of the location (e.g., 'where we make food')",
 				},
 			},
-			Handler: func(args map[string]interface{}) (string, error) {
+			Required: []string{"name", "direction"},
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
 				name, _ := args["name"].(string)
 				direction, _ := args["direction"].(string)
 				description, _ := args["description"].(string)
@@ -781,7 +659,8 @@ func Tools(cfg ToolsConfig) []Tool {
 					"description": "The name of the location to find",
 				},
 			},
-			Handler: func(args map[string]interface{}) (string, error) {
+			Required: []string{"name"},
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
 				name, _ := args["name"].(string)
 
 				if mem != nil && name != "" {
@@ -809,7 +688,7 @@ func Tools(cfg ToolsConfig) []Tool {
 			Name:        "list_locations",
 			Description: "List all known locations. Use this to see what places you remember.",
 			Parameters:  map[string]interface{}{},
-			Handler: func(args map[string]interface{}) (string, error) {
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
 				if mem != nil {
 					locations := mem.GetAllLocations()
 					if len(locations) == 0 {
@@ -832,7 +711,8 @@ func Tools(cfg ToolsConfig) []Tool {
 					"description": "The name of the knowledge topic to create",
 				},
 			},
-			Handler: func(args map[string]interface{}) (string, error) {
+			Required: []string{"topic"},
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
 				topic, _ := args["topic"].(string)
 
 				if mem != nil && topic != "" {
@@ -866,7 +746,8 @@ func Tools(cfg ToolsConfig) []Tool {
 					"description": "The information to store",
 				},
 			},
-			Handler: func(args map[string]interface{}) (string, error) {
+			Required: []string{"topic", "key", "value"},
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
 				topic, _ := args["topic"].(string)
 				key, _ := args["key"].(string)
 				value, _ := args["value"].(string)
@@ -894,7 +775,8 @@ func Tools(cfg ToolsConfig) []Tool {
 					"description": "The key to look up",
 				},
 			},
-			Handler: func(args map[string]interface{}) (string, error) {
+			Required: []string{"topic", "key"},
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
 				topic, _ := args["topic"].(string)
 				key, _ := args["key"].(string)
 
@@ -915,7 +797,7 @@ func Tools(cfg ToolsConfig) []Tool {
 			Name:        "list_knowledge_topics",
 			Description: "List all knowledge topics you've created. Use this to see what categories of information you have.",
 			Parameters:  map[string]interface{}{},
-			Handler: func(args map[string]interface{}) (string, error) {
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
 				if mem != nil {
 					topics := mem.ListKnowledge()
 					if len(topics) == 0 {
@@ -935,7 +817,8 @@ func Tools(cfg ToolsConfig) []Tool {
 					"description": "The knowledge topic to list items from",
 				},
 			},
-			Handler: func(args map[string]interface{}) (string, error) {
+			Required: []string{"topic"},
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
 				topic, _ := args["topic"].(string)
 
 				if mem != nil && topic != "" {
@@ -951,6 +834,39 @@ func Tools(cfg ToolsConfig) []Tool {
 				return "No memory available", nil
 			},
 		},
+		{
+			Name:        "recall",
+			Description: "Search everything you remember (people, places, context and knowledge) for what's relevant to what someone just said, by meaning rather than exact words. Use this when you're not sure which person, place or topic a memory is filed under.",
+			Parameters: map[string]interface{}{
+				"query": map[string]interface{}{
+					"type":        "string",
+					"description": "What to remember, usually what the person just said (like 'what food does Alice like')",
+				},
+				"limit": map[string]interface{}{
+					"type":        "integer",
+					"description": "How many memories to return (default 5)",
+					"minimum":     1,
+					"maximum":     20,
+				},
+			},
+			Required: []string{"query"},
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
+				query, _ := args["query"].(string)
+				limit, _ := args["limit"].(float64)
+
+				if mem != nil && query != "" {
+					found, err := mem.Recall(ctx, query, int(limit))
+					if err != nil {
+						return "", err
+					}
+					if len(found) == 0 {
+						return "I don't remember anything about that", nil
+					}
+					return formatRecollections(found), nil
+				}
+				return "No memory available", nil
+			},
+		},
 	}
 
 	// Add Spark tools if store is available
@@ -960,16 +876,175 @@ func Tools(cfg ToolsConfig) []Tool {
 			Gemini:     cfg.SparkGemini,      // Gemini for AI title/tag generation
 			GoogleDocs: cfg.SparkGoogleDocs,  // Google Docs for syncing
 		}
-		sparkTools := spark.Tools(sparkCfg)
-		for _, st := range sparkTools {
-			tools = append(tools, Tool{
-				Name:        st.Name,
-				Description: st.Description,
-				Parameters:  st.Parameters,
-				Handler:     st.Handler,
-			})
+		for _, st := range spark.Tools(sparkCfg) {
+			tools = append(tools, Tool(st))
+		}
+	}
+
+	// Add timer, alarm and reminder tools if the scheduler is available
+	if cfg.Schedule != nil {
+		for _, st := range schedule.Tools(schedule.ToolsConfig{Scheduler: cfg.Schedule}) {
+			tools = append(tools, Tool(st))
+		}
+	}
+
+	// Add tools from MCP servers, unless they clash with Eva's own
+	if cfg.MCP != nil {
+		names := make(map[string]bool, len(tools))
+		for _, t := range tools {
+			names[t.Name] = true
+		}
+		for _, mt := range mcp.Tools(mcp.ToolsConfig{Manager: cfg.MCP}) {
+			if names[mt.Name] {
+				fmt.Printf("🔌 MCP: skipping %s, Eva already has a tool with that name\n", mt.Name)
+				continue
+			}
+			names[mt.Name] = true
+			tools = append(tools, Tool(mt))
 		}
 	}
 
 	return tools
 }
+
+// describeScene captures a frame and asks Gemini about it. It returns
+// ctx's error if ctx ends first.
+func describeScene(ctx context.Context, cfg ToolsConfig, args map[string]interface{}, progress ProgressFunc) (string, error) {
+	focus, _ := args["focus"].(string)
+	if focus == "" {
+		focus = "general"
+	}
+
+	fmt.Printf("👁️  describe_scene called (focus: %s)\n", focus)
+
+	if cfg.Vision == nil {
+		fmt.Println("👁️  Error: Vision provider is nil")
+		return "I cannot see right now - camera not connected", nil
+	}
+
+	if cfg.GoogleAPIKey == "" {
+		fmt.Println("👁️  Error: Google API key not set")
+		return "I cannot see right now - vision not configured", nil
+	}
+
+	fmt.Println("👁️  Capturing frame...")
+	progress.report("Capturing a frame")
+	imageData, err := cfg.Vision.CaptureFrame()
+	if err != nil {
+		fmt.Printf("👁️  Frame capture error: %v\n", err)
+		return fmt.Sprintf("Could not capture image: %v", err), nil
+	}
+	fmt.Printf("👁️  Captured %d bytes\n", len(imageData))
+
+	var prompt string
+	switch focus {
+	case "people":
+		prompt = "Describe any people you see in this image. How many people are there? What are they doing? Where are they positioned (left, center, right)? Be concise."
+	case "general":
+		prompt = "Briefly describe what you see in this image. Mention the setting, any people, and notable objects. Keep it to 2-3 sentences."
+	default:
+		prompt = fmt.Sprintf("Look at this image and tell me if you can see: %s. Describe what you find. Be concise.", focus)
+	}
+
+	fmt.Println("👁️  Calling Gemini Flash...")
+	progress.report("Looking at the picture")
+	description, err := vision.GeminiVisionContext(ctx, cfg.GoogleAPIKey, imageData, prompt)
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	if err != nil {
+		fmt.Printf("👁️  Gemini error: %v\n", err)
+		return fmt.Sprintf("Vision error: %v", err), nil
+	}
+	fmt.Printf("👁️  Gemini response: %s\n", description)
+
+	return description, nil
+}
+
+// webSearch searches the web with Gemini. It returns ctx's error if ctx
+// ends first.
+func webSearch(ctx context.Context, cfg ToolsConfig, args map[string]interface{}, progress ProgressFunc) (string, error) {
+	query, _ := args["query"].(string)
+	if query == "" {
+		return "I need a search query to look up", nil
+	}
+
+	fmt.Printf("🌐 web_search called (query: %s)\n", query)
+
+	if cfg.GoogleAPIKey == "" {
+		return "Web search not configured", nil
+	}
+
+	progress.report("Searching the web for " + query)
+	result, err := vision.WebSearchContext(ctx, cfg.GoogleAPIKey, query)
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	if err != nil {
+		fmt.Printf("🌐 Search error: %v\n", err)
+		return fmt.Sprintf("Search failed: %v", err), nil
+	}
+
+	fmt.Printf("🌐 Search result: %s\n", result)
+	return result, nil
+}
+
+// searchFlights looks up flights with a web search. It returns ctx's
+// error if ctx ends first.
+func searchFlights(ctx context.Context, cfg ToolsConfig, args map[string]interface{}, progress ProgressFunc) (string, error) {
+	origin, _ := args["origin"].(string)
+	destination, _ := args["destination"].(string)
+	date, _ := args["date"].(string)
+	cabinClass, _ := args["cabin_class"].(string)
+	if cabinClass == "" {
+		cabinClass = "economy"
+	}
+
+	fmt.Printf("✈️  search_flights called (from: %s, to: %s, date: %s, class: %s)\n",
+		origin, destination, date, cabinClass)
+
+	if cfg.GoogleAPIKey == "" {
+		return "Flight search not configured", nil
+	}
+
+	query := fmt.Sprintf(
+		"Find specific flights from %s to %s on %s in %s class. "+
+			"I need: airline names, departure times, arrival times, flight numbers, and prices in USD. "+
+			"Search Google Flights or airline websites for real current availability.",
+		origin, destination, date, cabinClass)
+
+	progress.report(fmt.Sprintf("Checking flights from %s to %s", origin, destination))
+	result, err := vision.WebSearchContext(ctx, cfg.GoogleAPIKey, query)
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	if err != nil {
+		return fmt.Sprintf("Flight search failed: %v", err), nil
+	}
+
+	fmt.Printf("✈️  Flight search result: %s\n", result)
+	return result, nil
+}
+
+// formatRecollections lists recalled memories, best first, with what
+// each is about, where it's filed and when it was learned.
+func formatRecollections(found []memory.Recollection) string {
+	var sb strings.Builder
+	sb.WriteString("Memories, most relevant first:")
+	for _, r := range found {
+		fmt.Fprintf(&sb, "\n- %s (%s, learned %s): %s", r.Subject, r.Source, r.Time.Format("Jan 2, 2006"), r.Text)
+	}
+	return sb.String()
+}
+
+// sleepCtx sleeps for d, or until ctx is done
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,292 @@
+// Package listen decides when Eva's microphone is open to the cloud.
+//
+// A Controller implements the listening modes: always on, gated by a
+// wake word, or push-to-talk, each optionally followed by a follow-up
+// window after Eva answers so the user can reply without repeating the
+// wake word. Spotters find wake words in the mic stream locally.
+package listen
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Mode is a listening mode.
+type Mode string
+
+const (
+	// ModeAlwaysOn streams all mic audio (the default).
+	ModeAlwaysOn Mode = "always_on"
+	// ModeWakeWord opens the mic when a wake word is heard.
+	ModeWakeWord Mode = "wake_word"
+	// ModePushToTalk opens the mic while the talk button is held.
+	ModePushToTalk Mode = "push_to_talk"
+)
+
+// Modes lists the valid modes.
+var Modes = []Mode{ModeAlwaysOn, ModeWakeWord, ModePushToTalk}
+
+// ParseMode parses a mode name.
+func ParseMode(s string) (Mode, error) {
+	for _, m := range Modes {
+		if string(m) == s {
+			return m, nil
+		}
+	}
+	return "", fmt.Errorf("unknown listening mode %q (use always_on, wake_word or push_to_talk)", s)
+}
+
+// Reasons the mic is open.
+const (
+	ReasonAlwaysOn   = "always_on"
+	ReasonWakeWord   = "wake_word"
+	ReasonPushToTalk = "push_to_talk"
+	ReasonFollowUp   = "follow_up"
+)
+
+// Config tunes a Controller.
+type Config struct {
+	Mode Mode
+
+	// WakeTimeout closes the mic if nobody speaks after the wake word.
+	WakeTimeout time.Duration
+
+	// FollowUp keeps the mic open after Eva answers, in the wake-word
+	// and push-to-talk modes. Zero disables follow-ups.
+	FollowUp time.Duration
+}
+
+// DefaultConfig returns always-on listening with 8s follow-ups.
+func DefaultConfig() Config {
+	return Config{
+		Mode:        ModeAlwaysOn,
+		WakeTimeout: 6 * time.Second,
+		FollowUp:    8 * time.Second,
+	}
+}
+
+// State is a snapshot of the controller.
+type State struct {
+	Mode Mode
+
+	// Open reports whether mic audio should be sent, and why.
+	Open   bool
+	Reason string
+
+	// Until is when an open window closes unless the user speaks; zero
+	// when open indefinitely.
+	Until time.Time
+}
+
+// Controller tracks the listening mode and whether the mic is open.
+// It is safe for concurrent use. Change listeners are called in order
+// and must not call back into the Controller.
+type Controller struct {
+	mu       sync.Mutex
+	cfg      Config
+	state    State
+	talking  bool // User speech in progress inside an open window
+	timer    *time.Timer
+	timerGen uint64
+
+	listeners []func(State)
+	notifyMu  sync.Mutex
+}
+
+// New creates a controller. A zero WakeTimeout takes the default.
+func New(cfg Config) *Controller {
+	if cfg.Mode == "" {
+		cfg.Mode = ModeAlwaysOn
+	}
+	if cfg.WakeTimeout <= 0 {
+		cfg.WakeTimeout = DefaultConfig().WakeTimeout
+	}
+	c := &Controller{cfg: cfg}
+	c.state = c.restingState()
+	return c
+}
+
+// OnChange registers a listener for state changes.
+func (c *Controller) OnChange(fn func(State)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.listeners = append(c.listeners, fn)
+}
+
+// State returns the current state.
+func (c *Controller) State() State {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state
+}
+
+// Open reports whether mic audio should be sent.
+func (c *Controller) Open() bool {
+	return c.State().Open
+}
+
+// Mode returns the current mode.
+func (c *Controller) Mode() Mode {
+	return c.State().Mode
+}
+
+// SetMode switches mode, closing any open window.
+func (c *Controller) SetMode(m Mode) {
+	c.update(func() {
+		c.cfg.Mode = m
+		c.talking = false
+		c.stopTimerLocked()
+		c.state = c.restingState()
+	})
+}
+
+// Wake opens the mic after a wake word, in wake-word mode. It returns
+// false (and does nothing) in other modes.
+func (c *Controller) Wake() bool {
+	ok := false
+	c.update(func() {
+		if c.cfg.Mode != ModeWakeWord {
+			return
+		}
+		ok = true
+		c.talking = false
+		c.openLocked(ReasonWakeWord, c.cfg.WakeTimeout)
+	})
+	return ok
+}
+
+// Press opens the mic while the talk button is held, in push-to-talk
+// mode. It returns false in other modes.
+func (c *Controller) Press() bool {
+	ok := false
+	c.update(func() {
+		if c.cfg.Mode != ModePushToTalk {
+			return
+		}
+		ok = true
+		c.talking = false
+		c.openLocked(ReasonPushToTalk, 0)
+	})
+	return ok
+}
+
+// Release closes a push-to-talk window. It reports whether the button
+// was held, in which case the caller should end the user's turn.
+func (c *Controller) Release() bool {
+	held := false
+	c.update(func() {
+		if c.state.Reason != ReasonPushToTalk {
+			return
+		}
+		held = true
+		c.closeLocked()
+	})
+	return held
+}
+
+// SpeechStarted keeps an open window open until TurnEnded.
+func (c *Controller) SpeechStarted() {
+	c.update(func() {
+		if !c.state.Open || c.state.Reason == ReasonAlwaysOn {
+			return
+		}
+		c.talking = true
+		if c.state.Reason != ReasonPushToTalk {
+			c.stopTimerLocked()
+			c.state.Until = time.Time{}
+		}
+	})
+}
+
+// TurnEnded closes a wake-word or follow-up window once the user has
+// finished speaking. Push-to-talk windows stay open until Release.
+func (c *Controller) TurnEnded() {
+	c.update(func() {
+		c.talking = false
+		switch c.state.Reason {
+		case ReasonWakeWord, ReasonFollowUp:
+			c.closeLocked()
+		}
+	})
+}
+
+// ResponseDone opens a follow-up window after Eva finishes answering.
+func (c *Controller) ResponseDone() {
+	c.update(func() {
+		if c.cfg.FollowUp <= 0 || c.cfg.Mode == ModeAlwaysOn || c.state.Open {
+			return
+		}
+		c.talking = false
+		c.openLocked(ReasonFollowUp, c.cfg.FollowUp)
+	})
+}
+
+// Close stops the window timer.
+func (c *Controller) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stopTimerLocked()
+}
+
+// restingState is the state with no window open. Caller holds mu.
+func (c *Controller) restingState() State {
+	if c.cfg.Mode == ModeAlwaysOn {
+		return State{Mode: c.cfg.Mode, Open: true, Reason: ReasonAlwaysOn}
+	}
+	return State{Mode: c.cfg.Mode}
+}
+
+// openLocked opens a window, closing after timeout unless the user
+// speaks (0 = no timeout). Caller holds mu.
+func (c *Controller) openLocked(reason string, timeout time.Duration) {
+	c.stopTimerLocked()
+	c.state = State{Mode: c.cfg.Mode, Open: true, Reason: reason}
+	if timeout <= 0 {
+		return
+	}
+	c.state.Until = time.Now().Add(timeout)
+	gen := c.timerGen
+	c.timer = time.AfterFunc(timeout, func() {
+		c.update(func() {
+			if c.timerGen == gen && !c.talking {
+				c.closeLocked()
+			}
+		})
+	})
+}
+
+// closeLocked returns to the resting state. Caller holds mu.
+func (c *Controller) closeLocked() {
+	c.stopTimerLocked()
+	c.state = c.restingState()
+}
+
+func (c *Controller) stopTimerLocked() {
+	c.timerGen++
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+}
+
+// update applies fn under the lock and notifies listeners if the state
+// changed.
+func (c *Controller) update(fn func()) {
+	c.notifyMu.Lock()
+	defer c.notifyMu.Unlock()
+
+	c.mu.Lock()
+	before := c.state
+	fn()
+	after := c.state
+	listeners := c.listeners
+	c.mu.Unlock()
+
+	if after == before {
+		return
+	}
+	for _, l := range listeners {
+		l(after)
+	}
+}
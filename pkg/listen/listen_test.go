@@ -0,0 +1,165 @@
+package listen
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseMode(t *testing.T) {
+	for _, m := range Modes {
+		got, err := ParseMode(string(m))
+		if err != nil || got != m {
+			t.Errorf("ParseMode(%q) = %v, %v", m, got, err)
+		}
+	}
+	if _, err := ParseMode("sometimes"); err == nil {
+		t.Error("ParseMode(sometimes) succeeded")
+	}
+}
+
+func TestController_Transitions(t *testing.T) {
+	type step struct {
+		do         func(c *Controller)
+		wantOpen   bool
+		wantReason string
+	}
+	wake := func(c *Controller) { c.Wake() }
+	press := func(c *Controller) { c.Press() }
+	release := func(c *Controller) { c.Release() }
+	speech := func(c *Controller) { c.SpeechStarted() }
+	ended := func(c *Controller) { c.TurnEnded() }
+	answered := func(c *Controller) { c.ResponseDone() }
+
+	tests := []struct {
+		name  string
+		mode  Mode
+		steps []step
+	}{
+		{"always on ignores everything", ModeAlwaysOn, []step{
+			{wake, true, ReasonAlwaysOn},
+			{ended, true, ReasonAlwaysOn},
+			{answered, true, ReasonAlwaysOn},
+			{press, true, ReasonAlwaysOn},
+		}},
+		{"wake word turn with follow-up", ModeWakeWord, []step{
+			{speech, false, ""},
+			{wake, true, ReasonWakeWord},
+			{speech, true, ReasonWakeWord},
+			{ended, false, ""},
+			{answered, true, ReasonFollowUp},
+			{speech, true, ReasonFollowUp},
+			{ended, false, ""},
+		}},
+		{"push-to-talk", ModePushToTalk, []step{
+			{wake, false, ""},
+			{press, true, ReasonPushToTalk},
+			{speech, true, ReasonPushToTalk},
+			{ended, true, ReasonPushToTalk},
+			{release, false, ""},
+			{answered, true, ReasonFollowUp},
+			{press, true, ReasonPushToTalk},
+			{release, false, ""},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(Config{Mode: tt.mode, FollowUp: time.Minute})
+			defer c.Close()
+			for i, s := range tt.steps {
+				s.do(c)
+				st := c.State()
+				if st.Open != s.wantOpen || st.Reason != s.wantReason {
+					t.Fatalf("step %d: open=%v reason=%q, want open=%v reason=%q",
+						i, st.Open, st.Reason, s.wantOpen, s.wantReason)
+				}
+			}
+		})
+	}
+}
+
+func TestController_ReleaseReportsHeld(t *testing.T) {
+	c := New(Config{Mode: ModePushToTalk})
+	if c.Release() {
+		t.Error("Release() = true without Press")
+	}
+	c.Press()
+	if !c.Release() {
+		t.Error("Release() = false after Press")
+	}
+}
+
+func TestController_WindowTimeout(t *testing.T) {
+	c := New(Config{Mode: ModeWakeWord, WakeTimeout: 30 * time.Millisecond})
+	defer c.Close()
+
+	c.Wake()
+	if st := c.State(); st.Until.IsZero() {
+		t.Error("wake window has no deadline")
+	}
+	waitClosed(t, c)
+
+	// Speech holds the window open past the timeout
+	c.Wake()
+	c.SpeechStarted()
+	time.Sleep(60 * time.Millisecond)
+	if !c.Open() {
+		t.Fatal("window closed while the user was talking")
+	}
+	c.TurnEnded()
+	if c.Open() {
+		t.Error("window open after the turn ended")
+	}
+}
+
+func TestController_FollowUpDisabled(t *testing.T) {
+	c := New(Config{Mode: ModeWakeWord})
+	c.ResponseDone()
+	if c.Open() {
+		t.Error("follow-up opened with FollowUp = 0")
+	}
+}
+
+func TestController_SetModeAndListeners(t *testing.T) {
+	c := New(DefaultConfig())
+	var mu sync.Mutex
+	var got []State
+	c.OnChange(func(s State) {
+		mu.Lock()
+		got = append(got, s)
+		mu.Unlock()
+	})
+
+	c.SetMode(ModeWakeWord)
+	c.SetMode(ModeWakeWord) // No change, no callback
+	c.Wake()
+	c.SetMode(ModeAlwaysOn)
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []State{
+		{Mode: ModeWakeWord},
+		{Mode: ModeWakeWord, Open: true, Reason: ReasonWakeWord},
+		{Mode: ModeAlwaysOn, Open: true, Reason: ReasonAlwaysOn},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d changes, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		got[i].Until = time.Time{}
+		if got[i] != want[i] {
+			t.Errorf("change %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func waitClosed(t *testing.T, c *Controller) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for c.Open() {
+		if time.Now().After(deadline) {
+			t.Fatal("window never timed out")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,300 @@
+package listen
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/teslashibe/go-reachy/pkg/audio"
+	"github.com/teslashibe/go-reachy/pkg/conversation"
+	"github.com/teslashibe/go-reachy/pkg/vad"
+)
+
+// Detection is a wake word found in the mic stream.
+type Detection struct {
+	Phrase string
+	Score  float64 // 0-1, higher is a closer match
+
+	// Start and End bound the audio the phrase was found in.
+	Start time.Time
+	End   time.Time
+}
+
+// Spotter finds wake words in a stream of PCM16 mono audio. Process is
+// called with consecutive blocks; t is the capture time of the first
+// sample. Detections may be reported blocks after the phrase was said.
+type Spotter interface {
+	Process(samples []int16, t time.Time) []Detection
+
+	// Reset drops buffered audio, e.g. while the mic is open.
+	Reset()
+}
+
+// segmenter cuts the mic stream into utterances with a local VAD, so
+// spotters only look at speech.
+type segmenter struct {
+	rate  int
+	det   *vad.Detector
+	gate  *vad.Gate
+	buf   []int16
+	start time.Time
+	max   int
+	full  bool // buf hit max; drop audio until the next utterance
+}
+
+func newSegmenter(sampleRate int, maxLen time.Duration) *segmenter {
+	cfg := vad.DefaultConfig()
+	cfg.SampleRate = sampleRate
+	cfg.EndSilence = 250 * time.Millisecond
+	cfg.PrefixPadding = 150 * time.Millisecond
+	cfg.MaxUtterance = maxLen
+	return &segmenter{
+		rate: sampleRate,
+		det:  vad.New(vad.NewFeatureClassifier(sampleRate), cfg),
+		gate: vad.NewGate(sampleRate, cfg.GatePrefix()),
+		max:  samplesFor(sampleRate, maxLen),
+	}
+}
+
+// Process feeds a block and reports whether the current utterance
+// ended with it. The utterance so far is in s.buf, starting at s.start.
+func (s *segmenter) Process(samples []int16, t time.Time) (ended bool) {
+	events := s.det.Process(samples, t)
+	out := s.gate.Process(samples, events)
+	if len(out) > 0 && len(s.buf) == 0 && !s.full {
+		end := t.Add(durationOf(s.rate, len(samples)))
+		s.start = end.Add(-durationOf(s.rate, len(out)))
+	}
+	if !s.full {
+		s.buf = append(s.buf, out...)
+		if len(s.buf) >= s.max {
+			s.buf = s.buf[:s.max]
+			s.full = true
+			return true
+		}
+	}
+	for _, ev := range events {
+		if ev.Type == vad.Endpoint {
+			if s.full {
+				s.full = false
+				return false
+			}
+			return len(s.buf) > 0
+		}
+	}
+	return false
+}
+
+// clear drops the current utterance.
+func (s *segmenter) clear() {
+	s.buf = s.buf[:0]
+}
+
+// Reset drops all state.
+func (s *segmenter) Reset() {
+	s.det.Reset()
+	s.gate.Reset()
+	s.buf = s.buf[:0]
+	s.full = false
+}
+
+// PhraseSpotter spots wake phrases by transcribing short utterances,
+// typically with a local speech-to-text server. Utterances are
+// transcribed in the background; results are reported by a later
+// Process call. One transcription runs at a time.
+type PhraseSpotter struct {
+	tr      conversation.Transcriber
+	phrases []string
+	seg     *segmenter
+
+	// Timeout bounds each transcription.
+	Timeout time.Duration
+
+	// OnError is called when a transcription fails.
+	OnError func(error)
+
+	mu      sync.Mutex
+	busy    bool
+	results []Detection
+}
+
+// NewPhraseSpotter creates a spotter for the given phrases, listening
+// to audio at sampleRate. Utterances longer than 3s are ignored.
+func NewPhraseSpotter(tr conversation.Transcriber, sampleRate int, phrases []string) *PhraseSpotter {
+	return &PhraseSpotter{
+		tr:      tr,
+		phrases: phrases,
+		seg:     newSegmenter(sampleRate, 3*time.Second),
+		Timeout: 5 * time.Second,
+	}
+}
+
+// Process implements Spotter.
+func (p *PhraseSpotter) Process(samples []int16, t time.Time) []Detection {
+	if p.seg.Process(samples, t) {
+		p.submit(t.Add(durationOf(p.seg.rate, len(samples))))
+		p.seg.clear()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	out := p.results
+	p.results = nil
+	return out
+}
+
+// submit transcribes the current utterance unless one is in flight.
+func (p *PhraseSpotter) submit(end time.Time) {
+	p.mu.Lock()
+	if p.busy {
+		p.mu.Unlock()
+		return
+	}
+	p.busy = true
+	p.mu.Unlock()
+
+	pcm := audio.ConvertInt16ToPCM16(p.seg.buf)
+	start, rate := p.seg.start, p.seg.rate
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), p.Timeout)
+		defer cancel()
+		text, err := p.tr.Transcribe(ctx, pcm, rate)
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.busy = false
+		if err != nil {
+			if p.OnError != nil {
+				p.OnError(err)
+			}
+			return
+		}
+		if phrase, score, ok := MatchPhrase(text, p.phrases); ok {
+			p.results = append(p.results, Detection{Phrase: phrase, Score: score, Start: start, End: end})
+		}
+	}()
+}
+
+// Reset drops buffered audio and pending results.
+func (p *PhraseSpotter) Reset() {
+	p.seg.Reset()
+	p.mu.Lock()
+	p.results = nil
+	p.mu.Unlock()
+}
+
+// MatchPhrase looks for any of phrases in a transcript, ignoring case
+// and punctuation and allowing one wrong letter in words of four or
+// more letters ("okay robit" matches "okay robot", "hey ava" does not
+// match "hey eva"). It returns the best phrase and a 0-1 score.
+func MatchPhrase(text string, phrases []string) (string, float64, bool) {
+	words := normalizeWords(text)
+	best, bestScore := "", 0.0
+	for _, phrase := range phrases {
+		want := normalizeWords(phrase)
+		if len(want) == 0 {
+			continue
+		}
+		for i := 0; i+len(want) <= len(words); i++ {
+			edits, letters, ok := 0, 0, true
+			for j, w := range want {
+				d := editDistance(words[i+j], w)
+				if d > 0 && (len(w) < 4 || d > 1) {
+					ok = false
+					break
+				}
+				edits += d
+				letters += len(w)
+			}
+			if !ok {
+				continue
+			}
+			if score := 1 - float64(edits)/float64(letters); score > bestScore {
+				best, bestScore = phrase, score
+			}
+		}
+	}
+	return best, bestScore, best != ""
+}
+
+func normalizeWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+// Recent keeps the last few seconds of mic audio, so the utterance a
+// wake word was found in can be sent once the mic opens.
+type Recent struct {
+	mu   sync.Mutex
+	rate int
+	buf  []int16
+	max  int
+	end  time.Time // Capture time just after the last sample
+}
+
+// NewRecent keeps up to keep of audio at sampleRate.
+func NewRecent(sampleRate int, keep time.Duration) *Recent {
+	return &Recent{rate: sampleRate, max: samplesFor(sampleRate, keep)}
+}
+
+// Write appends a block captured at t.
+func (r *Recent) Write(samples []int16, t time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.buf = append(r.buf, samples...)
+	if over := len(r.buf) - r.max; over > 0 {
+		r.buf = append(r.buf[:0], r.buf[over:]...)
+	}
+	r.end = t.Add(durationOf(r.rate, len(samples)))
+}
+
+// Since returns a copy of the audio captured at or after t.
+func (r *Recent) Since(t time.Time) []int16 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := len(r.buf)
+	if !t.IsZero() {
+		n = min(n, max(0, samplesFor(r.rate, r.end.Sub(t))))
+	}
+	return append([]int16(nil), r.buf[len(r.buf)-n:]...)
+}
+
+// Reset drops the buffered audio.
+func (r *Recent) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.buf = r.buf[:0]
+}
+
+func samplesFor(rate int, d time.Duration) int {
+	return int(int64(rate) * int64(d) / int64(time.Second))
+}
+
+func durationOf(rate, n int) time.Duration {
+	return time.Duration(n) * time.Second / time.Duration(rate)
+}
@@ -0,0 +1,146 @@
+package listen
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+var testStart = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func TestMatchPhrase(t *testing.T) {
+	phrases := []string{"hey eva", "okay robot"}
+	tests := []struct {
+		text   string
+		want   string
+		wantOK bool
+	}{
+		{"Hey Eva, what's the time?", "hey eva", true},
+		{"so... hey, eva!", "hey eva", true},
+		{"okey robot", "okay robot", true},
+		{"okay, robit", "okay robot", true},
+		{"hey ava", "", false},
+		{"eva hey", "", false},
+		{"what a day", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, score, ok := MatchPhrase(tt.text, phrases)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("MatchPhrase = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+			if ok && (score <= 0 || score > 1) {
+				t.Errorf("score = %v, want (0, 1]", score)
+			}
+		})
+	}
+}
+
+// fakeTranscriber returns text for every utterance and records what it
+// was sent.
+type fakeTranscriber struct {
+	mu    sync.Mutex
+	text  string
+	err   error
+	calls []int // Utterance lengths in samples
+}
+
+func (f *fakeTranscriber) Transcribe(_ context.Context, pcm []byte, _ int) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, len(pcm)/2)
+	return f.text, f.err
+}
+
+// feed runs audio through a spotter in 20ms blocks and collects
+// detections, then keeps polling with silence until wait elapses.
+func feed(s Spotter, audio []int16, wait time.Duration) []Detection {
+	block := testRate / 50
+	var out []Detection
+	off := 0
+	for ; off+block <= len(audio); off += block {
+		out = append(out, s.Process(audio[off:off+block], testStart.Add(durationOf(testRate, off)))...)
+	}
+	deadline := time.Now().Add(wait)
+	silence := make([]int16, block)
+	for len(out) == 0 && time.Now().Before(deadline) {
+		out = append(out, s.Process(silence, testStart.Add(durationOf(testRate, off)))...)
+		off += block
+		time.Sleep(time.Millisecond)
+	}
+	return out
+}
+
+func TestPhraseSpotter(t *testing.T) {
+	word := say(hey, 110)
+	utterance := concat(quiet(testRate), word, quiet(testRate))
+
+	t.Run("match", func(t *testing.T) {
+		tr := &fakeTranscriber{text: "Hey Eva."}
+		s := NewPhraseSpotter(tr, testRate, []string{"hey eva"})
+		got := feed(s, utterance, time.Second)
+		if len(got) != 1 || got[0].Phrase != "hey eva" {
+			t.Fatalf("detections = %+v, want one hey eva", got)
+		}
+		// The utterance is sent whole, with a little lead-in
+		if n := tr.calls[0]; n < len(word) || n > len(word)+testRate/2 {
+			t.Errorf("transcribed %d samples, want about %d", n, len(word))
+		}
+		if start := got[0].Start.Sub(testStart); start < 700*time.Millisecond || start > time.Second {
+			t.Errorf("detection start = %v, want just before 1s", start)
+		}
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		s := NewPhraseSpotter(&fakeTranscriber{text: "hello there"}, testRate, []string{"hey eva"})
+		if got := feed(s, utterance, 100*time.Millisecond); len(got) != 0 {
+			t.Errorf("detections = %+v, want none", got)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		var gotErr error
+		var mu sync.Mutex
+		s := NewPhraseSpotter(&fakeTranscriber{err: errors.New("offline")}, testRate, []string{"hey eva"})
+		s.OnError = func(err error) {
+			mu.Lock()
+			gotErr = err
+			mu.Unlock()
+		}
+		feed(s, utterance, 100*time.Millisecond)
+		mu.Lock()
+		defer mu.Unlock()
+		if gotErr == nil {
+			t.Error("OnError not called")
+		}
+	})
+}
+
+func TestRecent(t *testing.T) {
+	r := NewRecent(1000, time.Second)
+	for i := range 15 {
+		block := make([]int16, 100)
+		for j := range block {
+			block[j] = int16(i*100 + j)
+		}
+		r.Write(block, testStart.Add(time.Duration(i)*100*time.Millisecond))
+	}
+	// 1.5s written, 1s kept: samples 500..1499
+
+	if got := r.Since(time.Time{}); len(got) != 1000 || got[0] != 500 {
+		t.Errorf("Since(zero) = %d samples from %d, want 1000 from 500", len(got), got[0])
+	}
+	if got := r.Since(testStart.Add(1200 * time.Millisecond)); len(got) != 300 || got[0] != 1200 {
+		t.Errorf("Since(1.2s) = %d samples, want 300 from 1200", len(got))
+	}
+	if got := r.Since(testStart.Add(2 * time.Second)); len(got) != 0 {
+		t.Errorf("Since(future) = %d samples, want 0", len(got))
+	}
+	r.Reset()
+	if got := r.Since(time.Time{}); len(got) != 0 {
+		t.Errorf("after Reset, %d samples", len(got))
+	}
+}
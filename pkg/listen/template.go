@@ -0,0 +1,421 @@
+package listen
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/teslashibe/go-reachy/pkg/audio"
+)
+
+// TemplateSpotter spots wake words by comparing each utterance against
+// recorded examples with dynamic time warping over MFCC features. It
+// needs no model or network: record a few examples of each phrase in
+// the room and the spotter matches speech that sounds like them. The
+// phrase has to start the utterance ("hey eva, what time is it").
+type TemplateSpotter struct {
+	// Threshold is the largest average frame distance (0-2) that counts
+	// as a match.
+	Threshold float64
+
+	mu        sync.Mutex
+	rate      int
+	mfcc      *mfcc
+	seg       *segmenter
+	templates []template
+	checked   bool // Current utterance already compared
+}
+
+type template struct {
+	phrase string
+	feats  [][]float64
+}
+
+// NewTemplateSpotter creates a spotter for audio at sampleRate with no
+// templates; add them with Enroll or LoadTemplates.
+func NewTemplateSpotter(sampleRate int) *TemplateSpotter {
+	return &TemplateSpotter{
+		Threshold: 0.3,
+		rate:      sampleRate,
+		mfcc:      newMFCC(sampleRate),
+		seg:       newSegmenter(sampleRate, 3*time.Second),
+	}
+}
+
+// Enroll adds an example of phrase. Leading and trailing silence is
+// trimmed.
+func (s *TemplateSpotter) Enroll(phrase string, samples []int16) error {
+	feats := trimSilence(s.mfcc.features(samples))
+	if len(feats) < 20 {
+		return fmt.Errorf("template for %q too short (%d frames of speech)", phrase, len(feats))
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.templates = append(s.templates, template{phrase: phrase, feats: normalize(feats)})
+	return nil
+}
+
+// LoadTemplates enrolls every DIR/<phrase>/*.wav file; underscores in
+// the directory name become spaces ("hey_eva" → "hey eva"). It returns
+// the phrases loaded.
+func (s *TemplateSpotter) LoadTemplates(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*", "*.wav"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no templates in %s (expected %s/<phrase>/*.wav)", dir, dir)
+	}
+	var phrases []string
+	seen := make(map[string]bool)
+	for _, f := range files {
+		samples, rate, err := audio.ReadWAV(f)
+		if err != nil {
+			return nil, fmt.Errorf("template %s: %w", f, err)
+		}
+		if rate != s.rate {
+			samples = audio.Resample(samples, rate, s.rate)
+		}
+		phrase := strings.ReplaceAll(filepath.Base(filepath.Dir(f)), "_", " ")
+		if err := s.Enroll(phrase, samples); err != nil {
+			return nil, fmt.Errorf("template %s: %w", f, err)
+		}
+		if !seen[phrase] {
+			seen[phrase] = true
+			phrases = append(phrases, phrase)
+		}
+	}
+	return phrases, nil
+}
+
+// Phrases returns the enrolled phrases.
+func (s *TemplateSpotter) Phrases() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []string
+	seen := make(map[string]bool)
+	for _, t := range s.templates {
+		if !seen[t.phrase] {
+			seen[t.phrase] = true
+			out = append(out, t.phrase)
+		}
+	}
+	return out
+}
+
+// Process implements Spotter. An utterance is compared once, as soon
+// as it is long enough to hold the longest template, or when it ends.
+func (s *TemplateSpotter) Process(samples []int16, t time.Time) []Detection {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ended := s.seg.Process(samples, t)
+	var out []Detection
+	if !s.checked && len(s.templates) > 0 {
+		frames := s.mfcc.frames(len(s.seg.buf))
+		if ended || frames >= s.longest()*3/2+s.mfcc.frameRate()/5 {
+			s.checked = true
+			if d, ok := s.match(); ok {
+				d.End = t.Add(durationOf(s.rate, len(samples)))
+				out = append(out, d)
+			}
+		}
+	}
+	if ended {
+		s.seg.clear()
+		s.checked = false
+	}
+	return out
+}
+
+// Reset drops buffered audio.
+func (s *TemplateSpotter) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seg.Reset()
+	s.checked = false
+}
+
+// match compares the current utterance with every template. Caller
+// holds mu.
+func (s *TemplateSpotter) match() (Detection, bool) {
+	feats := trimSilence(s.mfcc.features(s.seg.buf))
+	if len(feats) == 0 {
+		return Detection{}, false
+	}
+	best := Detection{}
+	bestDist := math.Inf(1)
+	for _, tpl := range s.templates {
+		// Normalize over the stretch the template could cover
+		n := min(len(feats), len(tpl.feats)*2)
+		dist := openEndDTW(tpl.feats, normalize(feats[:n]))
+		if dist < bestDist {
+			bestDist = dist
+			best.Phrase = tpl.phrase
+		}
+	}
+	if bestDist > s.Threshold {
+		return Detection{}, false
+	}
+	best.Score = 1 - bestDist/2
+	best.Start = s.seg.start
+	return best, true
+}
+
+func (s *TemplateSpotter) longest() int {
+	n := 0
+	for _, t := range s.templates {
+		n = max(n, len(t.feats))
+	}
+	return n
+}
+
+// openEndDTW aligns all of tpl with a prefix of seq, each sequence
+// allowed to run at half to twice the speed of the other, and returns
+// the smallest average frame distance along the path.
+func openEndDTW(tpl, seq [][]float64) float64 {
+	n, m := len(tpl), len(seq)
+	if m < n/2 {
+		return math.Inf(1)
+	}
+	inf := math.Inf(1)
+	cost := make([][]float64, n)
+	steps := make([][]int, n)
+	for i := range cost {
+		cost[i] = make([]float64, m)
+		steps[i] = make([]int, m)
+		for j := range cost[i] {
+			cost[i][j] = inf
+		}
+	}
+	for i := range n {
+		lo, hi := i/2, min(m-1, 2*i+1)
+		for j := lo; j <= hi; j++ {
+			d := cosineDistance(tpl[i], seq[j])
+			if i == 0 && j == 0 {
+				cost[i][j], steps[i][j] = d, 1
+				continue
+			}
+			c, st := inf, 0
+			if i > 0 && cost[i-1][j] < c {
+				c, st = cost[i-1][j], steps[i-1][j]
+			}
+			if j > 0 && cost[i][j-1] < c {
+				c, st = cost[i][j-1], steps[i][j-1]
+			}
+			if i > 0 && j > 0 && cost[i-1][j-1] < c {
+				c, st = cost[i-1][j-1], steps[i-1][j-1]
+			}
+			if !math.IsInf(c, 1) {
+				cost[i][j], steps[i][j] = c+d, st+1
+			}
+		}
+	}
+	best := inf
+	for j := range m {
+		if c := cost[n-1][j]; !math.IsInf(c, 1) {
+			best = min(best, c/float64(steps[n-1][j]))
+		}
+	}
+	return best
+}
+
+func cosineDistance(a, b []float64) float64 {
+	var dot, na, nb float64
+	for i := range a {
+		dot += a[i] * b[i]
+		na += a[i] * a[i]
+		nb += b[i] * b[i]
+	}
+	if na == 0 || nb == 0 {
+		return 1
+	}
+	return 1 - dot/math.Sqrt(na*nb)
+}
+
+// trimSilence drops frames more than 30dB below the loudest at either
+// end. Frame energy is feature 0.
+func trimSilence(feats [][]float64) [][]float64 {
+	peak := math.Inf(-1)
+	for _, f := range feats {
+		peak = max(peak, f[0])
+	}
+	floor := peak - 30*math.Ln10/10
+	lo, hi := 0, len(feats)
+	for lo < hi && feats[lo][0] < floor {
+		lo++
+	}
+	for hi > lo && feats[hi-1][0] < floor {
+		hi--
+	}
+	return feats[lo:hi]
+}
+
+// normalize drops the energy term and subtracts the mean cepstrum, so
+// matching ignores loudness and the microphone's coloring.
+func normalize(feats [][]float64) [][]float64 {
+	if len(feats) == 0 {
+		return nil
+	}
+	dims := len(feats[0]) - 1
+	mean := make([]float64, dims)
+	for _, f := range feats {
+		for k := range dims {
+			mean[k] += f[k+1] / float64(len(feats))
+		}
+	}
+	out := make([][]float64, len(feats))
+	for i, f := range feats {
+		out[i] = make([]float64, dims)
+		for k := range dims {
+			out[i][k] = f[k+1] - mean[k]
+		}
+	}
+	return out
+}
+
+// mfcc computes log energy plus 12 mel cepstral coefficients over 25ms
+// frames every 10ms.
+type mfcc struct {
+	rate    int
+	frame   int
+	hop     int
+	nfft    int
+	window  []float64
+	filters [][]float64 // Mel filterbank weights per FFT bin
+}
+
+const (
+	melFilters = 26
+	cepstra    = 12
+)
+
+func newMFCC(sampleRate int) *mfcc {
+	m := &mfcc{
+		rate:  sampleRate,
+		frame: sampleRate / 40,
+		hop:   sampleRate / 100,
+	}
+	m.nfft = 1
+	for m.nfft < m.frame {
+		m.nfft <<= 1
+	}
+	m.window = make([]float64, m.frame)
+	for i := range m.window {
+		m.window[i] = 0.54 - 0.46*math.Cos(2*math.Pi*float64(i)/float64(m.frame-1))
+	}
+
+	mel := func(f float64) float64 { return 2595 * math.Log10(1+f/700) }
+	hz := func(m float64) float64 { return 700 * (math.Pow(10, m/2595) - 1) }
+	lo, hi := mel(100), mel(min(8000, float64(sampleRate)/2))
+	edges := make([]float64, melFilters+2)
+	for i := range edges {
+		edges[i] = hz(lo + (hi-lo)*float64(i)/float64(melFilters+1))
+	}
+	binHz := float64(sampleRate) / float64(m.nfft)
+	m.filters = make([][]float64, melFilters)
+	for k := range m.filters {
+		w := make([]float64, m.nfft/2+1)
+		for b := range w {
+			f := float64(b) * binHz
+			switch {
+			case f > edges[k] && f <= edges[k+1]:
+				w[b] = (f - edges[k]) / (edges[k+1] - edges[k])
+			case f > edges[k+1] && f < edges[k+2]:
+				w[b] = (edges[k+2] - f) / (edges[k+2] - edges[k+1])
+			}
+		}
+		m.filters[k] = w
+	}
+	return m
+}
+
+func (m *mfcc) frameRate() int {
+	return m.rate / m.hop
+}
+
+// frames returns how many frames n samples hold.
+func (m *mfcc) frames(n int) int {
+	if n < m.frame {
+		return 0
+	}
+	return (n-m.frame)/m.hop + 1
+}
+
+// features returns one vector per frame: log energy, then c1..c12.
+func (m *mfcc) features(samples []int16) [][]float64 {
+	out := make([][]float64, 0, m.frames(len(samples)))
+	buf := make([]complex128, m.nfft)
+	energies := make([]float64, melFilters)
+	for off := 0; off+m.frame <= len(samples); off += m.hop {
+		clear(buf)
+		var energy float64
+		prev := 0.0
+		if off > 0 {
+			prev = float64(samples[off-1]) / 32768
+		}
+		for i := range m.frame {
+			x := float64(samples[off+i]) / 32768
+			energy += x * x
+			buf[i] = complex((x-0.97*prev)*m.window[i], 0)
+			prev = x
+		}
+		fft(buf)
+		for k, w := range m.filters {
+			var e float64
+			for b, wb := range w {
+				if wb != 0 {
+					p := cmplx.Abs(buf[b])
+					e += wb * p * p
+				}
+			}
+			energies[k] = math.Log(e + 1e-10)
+		}
+		vec := make([]float64, cepstra+1)
+		vec[0] = math.Log(energy/float64(m.frame) + 1e-10)
+		for c := 1; c <= cepstra; c++ {
+			var sum float64
+			for k, e := range energies {
+				sum += e * math.Cos(math.Pi*float64(c)*(float64(k)+0.5)/melFilters)
+			}
+			vec[c] = sum
+		}
+		out = append(out, vec)
+	}
+	return out
+}
+
+// fft is an in-place radix-2 FFT; len(x) must be a power of two.
+func fft(x []complex128) {
+	n := len(x)
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+	for size := 2; size <= n; size <<= 1 {
+		w := cmplx.Exp(complex(0, -2*math.Pi/float64(size)))
+		for start := 0; start < n; start += size {
+			wk := complex(1, 0)
+			for k := 0; k < size/2; k++ {
+				a, b := x[start+k], x[start+k+size/2]*wk
+				x[start+k], x[start+k+size/2] = a+b, a-b
+				wk *= w
+			}
+		}
+	}
+}
+
+var (
+	_ Spotter = (*TemplateSpotter)(nil)
+	_ Spotter = (*PhraseSpotter)(nil)
+)
@@ -0,0 +1,143 @@
+package listen
+
+import (
+	"math"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/teslashibe/go-reachy/pkg/audio"
+)
+
+const testRate = 16000
+
+// A sound is a vowel-like stretch: two formant frequencies held for a
+// duration. A word is a sequence of sounds.
+type sound struct {
+	f1, f2 float64
+	dur    time.Duration
+}
+
+var (
+	hey   = []sound{{400, 2000, 120 * time.Millisecond}, {650, 1700, 180 * time.Millisecond}, {300, 2300, 150 * time.Millisecond}, {450, 1900, 120 * time.Millisecond}, {700, 1200, 200 * time.Millisecond}}
+	other = []sound{{300, 900, 200 * time.Millisecond}, {700, 1100, 150 * time.Millisecond}, {350, 800, 200 * time.Millisecond}, {600, 1000, 220 * time.Millisecond}}
+)
+
+// say renders a word with a glottal pulse train at pitch f0 through two
+// formant resonators.
+func say(word []sound, f0 float64) []int16 {
+	var out []int16
+	var y1, y2, z1, z2 float64
+	phase := 0.0
+	for _, s := range word {
+		r1 := math.Exp(-math.Pi * 100 / testRate)
+		r2 := math.Exp(-math.Pi * 150 / testRate)
+		a1, a2 := 2*r1*math.Cos(2*math.Pi*s.f1/testRate), -r1*r1
+		b1, b2 := 2*r2*math.Cos(2*math.Pi*s.f2/testRate), -r2*r2
+		for range int(s.dur.Seconds() * testRate) {
+			x := 0.0
+			if phase += f0 / testRate; phase >= 1 {
+				phase--
+				x = 1
+			}
+			y := x + a1*y1 + a2*y2
+			y2, y1 = y1, y
+			z := y + b1*z1 + b2*z2
+			z2, z1 = z1, z
+			out = append(out, int16(max(-32768, min(32767, 300*z))))
+		}
+	}
+	return out
+}
+
+// stretch changes a word's tempo by factor.
+func stretch(word []sound, factor float64) []sound {
+	out := make([]sound, len(word))
+	for i, s := range word {
+		out[i] = sound{s.f1, s.f2, time.Duration(float64(s.dur) * factor)}
+	}
+	return out
+}
+
+func quiet(n int) []int16 {
+	rng := rand.New(rand.NewPCG(1, 2))
+	out := make([]int16, n)
+	for i := range out {
+		out[i] = int16(20 * rng.NormFloat64())
+	}
+	return out
+}
+
+func concat(parts ...[]int16) []int16 {
+	var out []int16
+	for _, p := range parts {
+		out = append(out, p...)
+	}
+	return out
+}
+
+func TestTemplateSpotter(t *testing.T) {
+	s := NewTemplateSpotter(testRate)
+	if err := s.Enroll("hey eva", concat(quiet(2000), say(hey, 120), quiet(2000))); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		speech []int16
+		want   bool
+	}{
+		{"same", say(hey, 120), true},
+		{"higher and slower", say(stretch(hey, 1.2), 140), true},
+		{"faster then more speech", concat(say(stretch(hey, 0.85), 110), say(other, 110)), true},
+		{"other word", say(other, 120), false},
+		{"other word first", concat(say(other, 120), say(hey, 120)), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s.Reset()
+			got := feed(s, concat(quiet(testRate), tt.speech, quiet(testRate)), 0)
+			if (len(got) > 0) != tt.want {
+				t.Fatalf("detections = %+v, want match %v", got, tt.want)
+			}
+			if tt.want && (got[0].Phrase != "hey eva" || got[0].Start.Sub(testStart) > time.Second) {
+				t.Errorf("detection = %+v, want hey eva starting by 1s", got[0])
+			}
+		})
+	}
+}
+
+func TestTemplateSpotter_LoadTemplates(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "hey_eva"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// Recorded at 48kHz; the spotter resamples
+	if err := audio.WriteWAV(filepath.Join(dir, "hey_eva", "1.wav"), audio.Resample(say(hey, 120), testRate, 48000), 48000); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewTemplateSpotter(testRate)
+	phrases, err := s.LoadTemplates(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(phrases) != 1 || phrases[0] != "hey eva" {
+		t.Errorf("phrases = %q, want [hey eva]", phrases)
+	}
+	if got := feed(s, concat(quiet(testRate), say(hey, 125), quiet(testRate)), 0); len(got) != 1 {
+		t.Errorf("detections = %+v, want one", got)
+	}
+
+	if _, err := NewTemplateSpotter(testRate).LoadTemplates(t.TempDir()); err == nil {
+		t.Error("LoadTemplates on an empty dir succeeded")
+	}
+}
+
+func TestTemplateSpotter_TooShort(t *testing.T) {
+	if err := NewTemplateSpotter(testRate).Enroll("hi", say(hey[:1], 120)); err == nil {
+		t.Error("Enroll accepted a 120ms template")
+	}
+}
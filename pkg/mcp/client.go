@@ -0,0 +1,107 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+)
+
+// ClientInfo is how Eva introduces itself to servers.
+var ClientInfo = Implementation{Name: "eva", Version: "1.0.0"}
+
+// Client is a session with one MCP server.
+type Client struct {
+	transport Transport
+	nextID    atomic.Int64
+	server    InitializeResult
+}
+
+// Connect performs the MCP handshake over transport. The transport is
+// closed if it fails.
+func Connect(ctx context.Context, transport Transport) (*Client, error) {
+	c := &Client{transport: transport}
+	params := initializeParams{
+		ProtocolVersion: ProtocolVersion,
+		Capabilities:    map[string]any{},
+		ClientInfo:      ClientInfo,
+	}
+	if err := c.call(ctx, "initialize", params, &c.server); err != nil {
+		transport.Close()
+		return nil, fmt.Errorf("mcp: initialize: %w", err)
+	}
+	if err := c.notify(ctx, "notifications/initialized"); err != nil {
+		transport.Close()
+		return nil, fmt.Errorf("mcp: initialized: %w", err)
+	}
+	return c, nil
+}
+
+// Server returns what the server said about itself in the handshake.
+func (c *Client) Server() InitializeResult {
+	return c.server
+}
+
+// ListTools returns every tool the server offers.
+func (c *Client) ListTools(ctx context.Context) ([]ToolInfo, error) {
+	var tools []ToolInfo
+	cursor := ""
+	for {
+		params := map[string]any{}
+		if cursor != "" {
+			params["cursor"] = cursor
+		}
+		var page listToolsResult
+		if err := c.call(ctx, "tools/list", params, &page); err != nil {
+			return nil, fmt.Errorf("mcp: tools/list: %w", err)
+		}
+		tools = append(tools, page.Tools...)
+		if page.NextCursor == "" || page.NextCursor == cursor {
+			return tools, nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+// CallTool runs a tool on the server. A tool that ran but failed
+// returns a result with IsError set, not an error.
+func (c *Client) CallTool(ctx context.Context, name string, args map[string]any) (*CallToolResult, error) {
+	var result CallToolResult
+	if err := c.call(ctx, "tools/call", callToolParams{Name: name, Arguments: args}, &result); err != nil {
+		return nil, fmt.Errorf("mcp: tools/call %s: %w", name, err)
+	}
+	return &result, nil
+}
+
+// Close ends the session.
+func (c *Client) Close() error {
+	return c.transport.Close()
+}
+
+// call sends a request and decodes its result.
+func (c *Client) call(ctx context.Context, method string, params, result any) error {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	id := strconv.FormatInt(c.nextID.Add(1), 10)
+	req := &Message{JSONRPC: "2.0", ID: json.RawMessage(id), Method: method, Params: raw}
+
+	resp, err := c.transport.Call(ctx, req)
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if result == nil || len(resp.Result) == 0 {
+		return nil
+	}
+	return json.Unmarshal(resp.Result, result)
+}
+
+// notify sends a notification without params.
+func (c *Client) notify(ctx context.Context, method string) error {
+	return c.transport.Notify(ctx, &Message{JSONRPC: "2.0", Method: method})
+}
@@ -0,0 +1,233 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+// testTools are what the test server offers, over two tools/list pages.
+var testTools = [][]ToolInfo{
+	{
+		{Name: "echo", Description: "Echoes text", InputSchema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"text": map[string]any{"type": "string", "description": "What to say"}},
+			"required":   []any{"text"},
+		}},
+		{Name: "fail", InputSchema: map[string]any{"type": "object"}},
+	},
+	{
+		{Name: "secret", InputSchema: map[string]any{"type": "object"}},
+		{Name: "exit", InputSchema: map[string]any{"type": "object"}},
+	},
+}
+
+// handleTestRequest answers a request like a small MCP server.
+func handleTestRequest(req *Message) *Message {
+	resp := &Message{JSONRPC: "2.0", ID: req.ID}
+	result := func(v any) *Message {
+		resp.Result, _ = json.Marshal(v)
+		return resp
+	}
+	text := func(s string, isError bool) *Message {
+		return result(CallToolResult{Content: []Content{{Type: "text", Text: s}}, IsError: isError})
+	}
+
+	switch req.Method {
+	case "initialize":
+		var p initializeParams
+		json.Unmarshal(req.Params, &p)
+		return result(InitializeResult{
+			ProtocolVersion: p.ProtocolVersion,
+			Capabilities:    map[string]any{"tools": map[string]any{}},
+			ServerInfo:      Implementation{Name: "test-server", Version: "0.1"},
+		})
+	case "tools/list":
+		var p struct{ Cursor string }
+		json.Unmarshal(req.Params, &p)
+		if p.Cursor == "page2" {
+			return result(listToolsResult{Tools: testTools[1]})
+		}
+		return result(listToolsResult{Tools: testTools[0], NextCursor: "page2"})
+	case "tools/call":
+		var p callToolParams
+		json.Unmarshal(req.Params, &p)
+		switch p.Name {
+		case "echo":
+			return text(fmt.Sprint(p.Arguments["text"]), false)
+		case "fail":
+			return text("it broke", true)
+		case "secret":
+			return text("the secret", false)
+		case "exit":
+			os.Exit(0)
+		}
+		resp.Error = &RPCError{Code: CodeInvalidParams, Message: "unknown tool " + p.Name}
+		return resp
+	}
+	resp.Error = &RPCError{Code: CodeMethodNotFound, Message: "method not found"}
+	return resp
+}
+
+// TestHelperServer is the stdio test server, run as a subprocess by
+// testServerConfig.
+func TestHelperServer(t *testing.T) {
+	if os.Getenv("GO_MCP_TEST_SERVER") != "1" {
+		t.Skip("run as a subprocess")
+	}
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		var msg Message
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil || len(msg.ID) == 0 {
+			continue // Notifications need no reply
+		}
+		data, _ := json.Marshal(handleTestRequest(&msg))
+		fmt.Println(string(data))
+	}
+	os.Exit(0)
+}
+
+// testServerConfig runs this test binary as a stdio MCP server.
+func testServerConfig(allow ...string) ServerConfig {
+	return ServerConfig{
+		Command: os.Args[0],
+		Args:    []string{"-test.run=^TestHelperServer$"},
+		Env:     map[string]string{"GO_MCP_TEST_SERVER": "1"},
+		Allow:   allow,
+	}
+}
+
+func TestClient_Stdio(t *testing.T) {
+	transport, err := testServerConfig().Transport()
+	if err != nil {
+		t.Fatalf("Transport: %v", err)
+	}
+	ctx := context.Background()
+	c, err := Connect(ctx, transport)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer c.Close()
+
+	if got := c.Server().ServerInfo.Name; got != "test-server" {
+		t.Errorf("server = %q, want test-server", got)
+	}
+
+	tools, err := c.ListTools(ctx)
+	if err != nil {
+		t.Fatalf("ListTools: %v", err)
+	}
+	if len(tools) != 4 || tools[0].Name != "echo" || tools[3].Name != "exit" {
+		t.Errorf("tools = %+v, want both pages", tools)
+	}
+
+	result, err := c.CallTool(ctx, "echo", map[string]any{"text": "hello"})
+	if err != nil || result.IsError || result.Text() != "hello" {
+		t.Errorf("echo = %+v, %v", result, err)
+	}
+	result, err = c.CallTool(ctx, "fail", nil)
+	if err != nil || !result.IsError || result.Text() != "it broke" {
+		t.Errorf("fail = %+v, %v", result, err)
+	}
+	var rpcErr *RPCError
+	if _, err := c.CallTool(ctx, "nope", nil); !errors.As(err, &rpcErr) || rpcErr.Code != CodeInvalidParams {
+		t.Errorf("unknown tool = %v, want an RPC error", err)
+	}
+
+	// The server exiting fails calls instead of hanging them
+	if _, err := c.CallTool(ctx, "exit", nil); !errors.Is(err, ErrClosed) {
+		t.Errorf("call as the server exits = %v, want ErrClosed", err)
+	}
+	if _, err := c.CallTool(ctx, "echo", nil); !errors.Is(err, ErrClosed) {
+		t.Errorf("call after exit = %v, want ErrClosed", err)
+	}
+}
+
+func TestClient_HTTP(t *testing.T) {
+	var deleted bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if r.Method == http.MethodDelete {
+			deleted = r.Header.Get("Mcp-Session-Id") == "session-1"
+			return
+		}
+
+		var msg Message
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if msg.Method != "initialize" && r.Header.Get("Mcp-Session-Id") != "session-1" {
+			http.Error(w, "no session", http.StatusBadRequest)
+			return
+		}
+		if len(msg.ID) == 0 {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		resp, _ := json.Marshal(handleTestRequest(&msg))
+		switch msg.Method {
+		case "initialize":
+			w.Header().Set("Mcp-Session-Id", "session-1")
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(resp)
+		default:
+			// Streamed, with a server request and a comment first
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, ": keepalive\n\n")
+			fmt.Fprint(w, "event: message\ndata: {\"jsonrpc\":\"2.0\",\"id\":\"s1\",\"method\":\"ping\"}\n\n")
+			fmt.Fprintf(w, "event: message\ndata: %s\n\n", resp)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	if _, err := Connect(ctx, NewHTTPTransport(server.URL, nil)); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("Connect without auth = %v, want 401", err)
+	}
+
+	c, err := Connect(ctx, NewHTTPTransport(server.URL, map[string]string{"Authorization": "Bearer token"}))
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	tools, err := c.ListTools(ctx)
+	if err != nil || len(tools) != 4 {
+		t.Fatalf("ListTools = %d tools, %v", len(tools), err)
+	}
+	result, err := c.CallTool(ctx, "echo", map[string]any{"text": "over http"})
+	if err != nil || result.Text() != "over http" {
+		t.Errorf("echo = %+v, %v", result, err)
+	}
+
+	c.Close()
+	if !deleted {
+		t.Error("Close didn't end the session")
+	}
+	if _, err := c.CallTool(ctx, "echo", nil); !errors.Is(err, ErrClosed) {
+		t.Errorf("call after Close = %v, want ErrClosed", err)
+	}
+}
+
+func TestCallToolResult_Text(t *testing.T) {
+	r := CallToolResult{Content: []Content{
+		{Type: "text", Text: "Forecast:"},
+		{Type: "resource", Resource: &Resource{URI: "file:///a.txt", Text: "sunny"}},
+		{Type: "resource", Resource: &Resource{URI: "file:///b.png"}},
+		{Type: "image", MimeType: "image/png", Data: "AAAA"},
+	}}
+	want := "Forecast:\nsunny\n[resource file:///b.png]\n[image image/png]"
+	if got := r.Text(); got != want {
+		t.Errorf("Text() = %q, want %q", got, want)
+	}
+}
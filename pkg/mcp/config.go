@@ -0,0 +1,182 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+)
+
+// DefaultCallTimeout is how long a tool call may take when the server's
+// config doesn't say.
+const DefaultCallTimeout = 30 * time.Second
+
+// DefaultServeAddr is where Eva serves her tools over HTTP when the
+// config doesn't say.
+const DefaultServeAddr = "127.0.0.1:8765"
+
+// ServerConfig describes one MCP server: either a command to run
+// (stdio) or a URL (Streamable HTTP). Values in Env and Headers may
+// refer to environment variables as ${NAME}.
+type ServerConfig struct {
+	Command string            `json:"command,omitempty"`
+	Args    []string          `json:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+
+	URL     string            `json:"url,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Allow lists the server's tools Eva may use, by name or pattern
+	// ("read_*"); "*" allows all. Tools not listed are hidden.
+	Allow []string `json:"allow"`
+
+	TimeoutSeconds int  `json:"timeout_seconds,omitempty"` // Per call (default 30)
+	Disabled       bool `json:"disabled,omitempty"`
+}
+
+// ServeConfig is how Eva serves her own tools to other agents.
+type ServeConfig struct {
+	Addr string `json:"addr,omitempty"` // HTTP listen address (default DefaultServeAddr)
+
+	// Token is the bearer token HTTP clients must send, or ${NAME} to
+	// read it from the environment. Required unless Addr is loopback.
+	Token string `json:"token,omitempty"`
+
+	// Allow lists the tools other agents may call, like
+	// ServerConfig.Allow.
+	Allow []string `json:"allow"`
+}
+
+// Config is the "mcp" section of ~/.eva/config.json:
+//
+//	{
+//	  "mcp": {
+//	    "servers": {
+//	      "files": {"command": "mcp-files", "args": ["/home/eva"], "allow": ["read_file", "list_*"]},
+//	      "weather": {"url": "http://localhost:8000/mcp", "headers": {"Authorization": "Bearer ${WEATHER_TOKEN}"}, "allow": ["*"]}
+//	    },
+//	    "serve": {"addr": ":8765", "token": "${EVA_MCP_TOKEN}", "allow": ["move_head", "play_emotion", "recall_*"]}
+//	  }
+//	}
+type Config struct {
+	Servers map[string]ServerConfig `json:"servers"`
+	Serve   ServeConfig             `json:"serve"`
+}
+
+// evaConfig is the part of ~/.eva/config.json this package reads.
+type evaConfig struct {
+	MCP Config `json:"mcp"`
+}
+
+// DefaultConfigPath returns ~/.eva/config.json.
+func DefaultConfigPath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".eva", "config.json")
+	}
+	return filepath.Join(homeDir, ".eva", "config.json")
+}
+
+// LoadConfig reads the MCP servers from ~/.eva/config.json. A missing
+// file means no servers.
+func LoadConfig() (Config, error) {
+	return LoadConfigFile(DefaultConfigPath())
+}
+
+// LoadConfigFile reads the "mcp" section of an Eva config file and
+// checks each server. A missing file means no servers.
+func LoadConfigFile(file string) (Config, error) {
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return Config{}, nil
+	}
+	if err != nil {
+		return Config{}, err
+	}
+
+	var cfg evaConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("mcp: %s: %w", file, err)
+	}
+	for name, s := range cfg.MCP.Servers {
+		if err := s.Validate(); err != nil {
+			return Config{}, fmt.Errorf("mcp: server %q: %w", name, err)
+		}
+	}
+	if err := checkPatterns(cfg.MCP.Serve.Allow); err != nil {
+		return Config{}, fmt.Errorf("mcp: serve: %w", err)
+	}
+	return cfg.MCP, nil
+}
+
+// Validate checks that s names exactly one transport and that its
+// allow-list patterns are well formed.
+func (s ServerConfig) Validate() error {
+	if (s.Command == "") == (s.URL == "") {
+		return errors.New("set either command or url")
+	}
+	return checkPatterns(s.Allow)
+}
+
+// Allowed reports whether the allow-list lets Eva use tool.
+func (s ServerConfig) Allowed(tool string) bool {
+	return allowed(s.Allow, tool)
+}
+
+// Timeout returns the per-call timeout.
+func (s ServerConfig) Timeout() time.Duration {
+	if s.TimeoutSeconds > 0 {
+		return time.Duration(s.TimeoutSeconds) * time.Second
+	}
+	return DefaultCallTimeout
+}
+
+// Transport opens the server's transport, starting its command for
+// stdio.
+func (s ServerConfig) Transport() (Transport, error) {
+	if s.URL != "" {
+		headers := make(map[string]string, len(s.Headers))
+		for k, v := range s.Headers {
+			headers[k] = os.ExpandEnv(v)
+		}
+		return NewHTTPTransport(s.URL, headers), nil
+	}
+	env := make([]string, 0, len(s.Env))
+	for k, v := range s.Env {
+		env = append(env, k+"="+os.ExpandEnv(v))
+	}
+	return NewStdioTransport(s.Command, s.Args, env)
+}
+
+// Allowed reports whether the allow-list lets other agents call tool.
+func (s ServeConfig) Allowed(tool string) bool {
+	return allowed(s.Allow, tool)
+}
+
+// BearerToken returns the token with environment variables expanded.
+func (s ServeConfig) BearerToken() string {
+	return os.ExpandEnv(s.Token)
+}
+
+// checkPatterns checks that allow-list patterns are well formed.
+func checkPatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("bad allow pattern %q", pattern)
+		}
+	}
+	return nil
+}
+
+// allowed reports whether name matches one of the patterns.
+func allowed(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
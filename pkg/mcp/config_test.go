@@ -0,0 +1,84 @@
+package mcp
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadConfigFile(t *testing.T) {
+	dir := t.TempDir()
+
+	cfg, err := LoadConfigFile(filepath.Join(dir, "missing.json"))
+	if err != nil || len(cfg.Servers) != 0 {
+		t.Errorf("missing file = %+v, %v, want no servers", cfg, err)
+	}
+
+	file := filepath.Join(dir, "config.json")
+	os.WriteFile(file, []byte(`{
+		"spark": {"enabled": true},
+		"mcp": {"servers": {
+			"files": {"command": "mcp-files", "args": ["/tmp"], "allow": ["read_*"]},
+			"weather": {"url": "http://localhost:8000/mcp", "allow": ["*"], "timeout_seconds": 5}
+		}}
+	}`), 0600)
+	cfg, err = LoadConfigFile(file)
+	if err != nil {
+		t.Fatalf("LoadConfigFile: %v", err)
+	}
+	if len(cfg.Servers) != 2 || cfg.Servers["files"].Command != "mcp-files" || cfg.Servers["weather"].Timeout() != 5*time.Second {
+		t.Errorf("config = %+v", cfg)
+	}
+	if cfg.Servers["files"].Timeout() != DefaultCallTimeout {
+		t.Errorf("default timeout = %v", cfg.Servers["files"].Timeout())
+	}
+
+	os.WriteFile(file, []byte(`{"mcp": {"servers": {"both": {"command": "x", "url": "http://x"}}}}`), 0600)
+	if _, err := LoadConfigFile(file); err == nil || !strings.Contains(err.Error(), `"both"`) {
+		t.Errorf("server with two transports = %v, want an error naming it", err)
+	}
+}
+
+func TestServerConfig_Validate(t *testing.T) {
+	tests := []struct {
+		cfg     ServerConfig
+		wantErr bool
+	}{
+		{ServerConfig{Command: "x"}, false},
+		{ServerConfig{URL: "http://x", Allow: []string{"get_*"}}, false},
+		{ServerConfig{}, true},
+		{ServerConfig{Command: "x", URL: "http://x"}, true},
+		{ServerConfig{Command: "x", Allow: []string{"[bad"}}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%+v) = %v, wantErr %v", tt.cfg, err, tt.wantErr)
+		}
+	}
+}
+
+func TestServerConfig_Allowed(t *testing.T) {
+	cfg := ServerConfig{Allow: []string{"read_*", "search"}}
+	tests := []struct {
+		tool string
+		want bool
+	}{
+		{"read_file", true},
+		{"search", true},
+		{"search_all", false},
+		{"write_file", false},
+	}
+	for _, tt := range tests {
+		if got := cfg.Allowed(tt.tool); got != tt.want {
+			t.Errorf("Allowed(%q) = %v, want %v", tt.tool, got, tt.want)
+		}
+	}
+	if (ServerConfig{}).Allowed("anything") {
+		t.Error("an empty allow-list should allow nothing")
+	}
+	if !(ServerConfig{Allow: []string{"*"}}).Allowed("anything") {
+		t.Error(`"*" should allow everything`)
+	}
+}
@@ -0,0 +1,191 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HTTPTransport talks to a server over Streamable HTTP: each message is
+// POSTed, and the server replies with JSON or a server-sent event
+// stream. It keeps the session ID the server assigns.
+type HTTPTransport struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+
+	mu        sync.Mutex
+	sessionID string
+	closed    bool
+}
+
+// NewHTTPTransport talks to the server at url, adding headers (such as
+// Authorization) to every request.
+func NewHTTPTransport(url string, headers map[string]string) *HTTPTransport {
+	return &HTTPTransport{
+		url:     url,
+		headers: headers,
+		client:  &http.Client{},
+	}
+}
+
+// Call POSTs req and reads its response from the reply.
+func (t *HTTPTransport) Call(ctx context.Context, req *Message) (*Message, error) {
+	resp, err := t.post(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType == "text/event-stream" {
+		return t.readStream(ctx, resp.Body, req.ID)
+	}
+
+	var msg Message
+	if err := json.NewDecoder(resp.Body).Decode(&msg); err != nil {
+		return nil, fmt.Errorf("mcp: decode response: %w", err)
+	}
+	return &msg, nil
+}
+
+// readStream reads server-sent events until the response to id.
+// Requests the server sends meanwhile are answered.
+func (t *HTTPTransport) readStream(ctx context.Context, body io.Reader, id json.RawMessage) (*Message, error) {
+	reader := bufio.NewReader(body)
+	var data bytes.Buffer
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && line == "" {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, fmt.Errorf("%w: event stream ended without a response", ErrClosed)
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		if line != "" {
+			if value, ok := strings.CutPrefix(line, "data:"); ok {
+				data.WriteString(strings.TrimPrefix(value, " "))
+				data.WriteByte('\n')
+			}
+			continue // Event names, IDs and comments don't matter here
+		}
+		if data.Len() == 0 {
+			continue
+		}
+
+		var msg Message
+		err = json.Unmarshal(data.Bytes(), &msg)
+		data.Reset()
+		if err != nil {
+			continue
+		}
+		switch {
+		case msg.IsResponse() && bytes.Equal(msg.ID, id):
+			return &msg, nil
+		case !msg.IsResponse() && len(msg.ID) > 0:
+			go t.Notify(context.Background(), serverRequestReply(&msg))
+		}
+	}
+}
+
+// Notify POSTs a notification (or a reply to a server request).
+func (t *HTTPTransport) Notify(ctx context.Context, msg *Message) error {
+	resp, err := t.post(ctx, msg)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// post sends msg and checks the status, keeping any session ID.
+func (t *HTTPTransport) post(ctx context.Context, msg *Message) (*http.Response, error) {
+	t.mu.Lock()
+	closed, sessionID := t.closed, t.sessionID
+	t.mu.Unlock()
+	if closed {
+		return nil, errNotSent
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	if msg.Method != "initialize" {
+		req.Header.Set("MCP-Protocol-Version", ProtocolVersion)
+	}
+	if sessionID != "" {
+		req.Header.Set("Mcp-Session-Id", sessionID)
+	}
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("mcp: %s: %w", t.url, err)
+	}
+	if resp.StatusCode == http.StatusNotFound && sessionID != "" {
+		// The server forgot the session; the client must start over
+		resp.Body.Close()
+		t.mu.Lock()
+		t.closed = true
+		t.mu.Unlock()
+		return nil, fmt.Errorf("%w: session expired", errNotSent)
+	}
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		resp.Body.Close()
+		return nil, fmt.Errorf("mcp: %s: %s: %s", t.url, resp.Status, strings.TrimSpace(string(body)))
+	}
+	if id := resp.Header.Get("Mcp-Session-Id"); id != "" {
+		t.mu.Lock()
+		t.sessionID = id
+		t.mu.Unlock()
+	}
+	return resp, nil
+}
+
+// Close ends the session on the server, if it started one.
+func (t *HTTPTransport) Close() error {
+	t.mu.Lock()
+	closed, sessionID := t.closed, t.sessionID
+	t.closed = true
+	t.mu.Unlock()
+	if closed || sessionID == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, t.url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Mcp-Session-Id", sessionID)
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
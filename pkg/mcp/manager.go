@@ -0,0 +1,193 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Manager keeps sessions with the configured servers and routes tool
+// calls to them. A server that drops is reconnected on its next call.
+type Manager struct {
+	servers map[string]*server
+	order   []string
+}
+
+// server is one configured server and its session.
+type server struct {
+	name string
+	cfg  ServerConfig
+
+	mu     sync.Mutex
+	client *Client
+	tools  []ToolInfo // Allowed tools, from the last tools/list
+	err    error      // Last connection error
+}
+
+// ServerStatus describes a server's session, for logs and the
+// dashboard.
+type ServerStatus struct {
+	Name      string   `json:"name"`
+	Connected bool     `json:"connected"`
+	Server    string   `json:"server,omitempty"` // Name and version it reported
+	Tools     []string `json:"tools"`            // Allowed tools
+	Error     string   `json:"error,omitempty"`
+}
+
+// NewManager creates a manager for cfg's enabled servers. Call Connect
+// to start their sessions.
+func NewManager(cfg Config) *Manager {
+	m := &Manager{servers: make(map[string]*server)}
+	for name, s := range cfg.Servers {
+		if s.Disabled {
+			continue
+		}
+		m.servers[name] = &server{name: name, cfg: s}
+		m.order = append(m.order, name)
+	}
+	sort.Strings(m.order)
+	return m
+}
+
+// Connect starts a session with every server and lists its tools. It
+// returns the servers that failed; the others are usable regardless.
+func (m *Manager) Connect(ctx context.Context) error {
+	var errs []error
+	for _, name := range m.order {
+		s := m.servers[name]
+		s.mu.Lock()
+		err := s.connectLocked(ctx)
+		s.mu.Unlock()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// connectLocked starts a session and lists its allowed tools. Caller
+// holds s.mu.
+func (s *server) connectLocked(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout())
+	defer cancel()
+
+	s.err = func() error {
+		transport, err := s.cfg.Transport()
+		if err != nil {
+			return err
+		}
+		client, err := Connect(ctx, transport)
+		if err != nil {
+			return err
+		}
+		tools, err := client.ListTools(ctx)
+		if err != nil {
+			client.Close()
+			return err
+		}
+
+		s.client = client
+		s.tools = nil
+		for _, t := range tools {
+			if s.cfg.Allowed(t.Name) {
+				s.tools = append(s.tools, t)
+			}
+		}
+		return nil
+	}()
+	return s.err
+}
+
+// Call runs a tool on the named server. The allow-list is checked
+// again here. A session that dropped is reconnected; the call is
+// retried only if it never reached the server.
+func (m *Manager) Call(ctx context.Context, serverName, tool string, args map[string]any) (*CallToolResult, error) {
+	s, ok := m.servers[serverName]
+	if !ok {
+		return nil, fmt.Errorf("mcp: unknown server %q", serverName)
+	}
+	if !s.cfg.Allowed(tool) {
+		return nil, fmt.Errorf("%w: %s/%s", ErrNotAllowed, serverName, tool)
+	}
+
+	for attempt := 0; ; attempt++ {
+		client, err := s.session(ctx)
+		if err != nil {
+			return nil, err
+		}
+		callCtx, cancel := context.WithTimeout(ctx, s.cfg.Timeout())
+		result, err := client.CallTool(callCtx, tool, args)
+		cancel()
+		if errors.Is(err, ErrClosed) {
+			s.drop(client)
+			if attempt == 0 && errors.Is(err, errNotSent) {
+				continue
+			}
+		}
+		return result, err
+	}
+}
+
+// session returns the server's client, connecting if needed.
+func (s *server) session(ctx context.Context) (*Client, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.client == nil {
+		if err := s.connectLocked(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return s.client, nil
+}
+
+// drop forgets client after its connection closed.
+func (s *server) drop(client *Client) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.client == client {
+		client.Close()
+		s.client = nil
+	}
+}
+
+// Status describes every server, sorted by name.
+func (m *Manager) Status() []ServerStatus {
+	statuses := make([]ServerStatus, 0, len(m.order))
+	for _, name := range m.order {
+		s := m.servers[name]
+		s.mu.Lock()
+		st := ServerStatus{Name: name, Connected: s.client != nil, Tools: []string{}}
+		if s.client != nil {
+			info := s.client.Server().ServerInfo
+			st.Server = info.Name + " " + info.Version
+		}
+		for _, t := range s.tools {
+			st.Tools = append(st.Tools, t.Name)
+		}
+		if s.err != nil {
+			st.Error = s.err.Error()
+		}
+		s.mu.Unlock()
+		statuses = append(statuses, st)
+	}
+	return statuses
+}
+
+// Close ends every session.
+func (m *Manager) Close() error {
+	var errs []error
+	for _, name := range m.order {
+		s := m.servers[name]
+		s.mu.Lock()
+		if s.client != nil {
+			if err := s.client.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			}
+			s.client = nil
+		}
+		s.mu.Unlock()
+	}
+	return errors.Join(errs...)
+}
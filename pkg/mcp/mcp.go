@@ -0,0 +1,149 @@
+// Package mcp connects Eva to Model Context Protocol (MCP) servers, so
+// tools they provide can be used alongside Eva's own, and serves Eva's
+// tools to other agents (Server).
+//
+// It speaks JSON-RPC 2.0 over stdio (a server started as a subprocess)
+// or Streamable HTTP (POST, with JSON or server-sent event replies).
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ProtocolVersion is the MCP revision this package speaks.
+const ProtocolVersion = "2025-03-26"
+
+// JSON-RPC error codes.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+// Errors returned by clients and transports.
+var (
+	ErrClosed     = errors.New("mcp: connection closed")
+	ErrNotAllowed = errors.New("mcp: tool not allowed")
+)
+
+// errNotSent is ErrClosed for a message that was never delivered, so
+// the call is safe to retry on a new session.
+var errNotSent = fmt.Errorf("%w before sending", ErrClosed)
+
+// Message is a JSON-RPC 2.0 request, notification or response.
+// Notifications have no ID.
+type Message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// IsResponse reports whether m is a response rather than a request or
+// notification.
+func (m *Message) IsResponse() bool {
+	return m.Method == "" && len(m.ID) > 0
+}
+
+// RPCError is a JSON-RPC error.
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("mcp: %s (code %d)", e.Message, e.Code)
+}
+
+// Implementation names a client or server.
+type Implementation struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// initializeParams is the client's half of the handshake.
+type initializeParams struct {
+	ProtocolVersion string         `json:"protocolVersion"`
+	Capabilities    map[string]any `json:"capabilities"`
+	ClientInfo      Implementation `json:"clientInfo"`
+}
+
+// InitializeResult is the server's half of the handshake.
+type InitializeResult struct {
+	ProtocolVersion string         `json:"protocolVersion"`
+	Capabilities    map[string]any `json:"capabilities"`
+	ServerInfo      Implementation `json:"serverInfo"`
+	Instructions    string         `json:"instructions,omitempty"`
+}
+
+// ToolInfo describes a tool a server offers.
+type ToolInfo struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	InputSchema map[string]any `json:"inputSchema"`
+}
+
+// listToolsResult is one page of tools/list.
+type listToolsResult struct {
+	Tools      []ToolInfo `json:"tools"`
+	NextCursor string     `json:"nextCursor,omitempty"`
+}
+
+// callToolParams are the tools/call arguments.
+type callToolParams struct {
+	Name      string         `json:"name"`
+	Arguments map[string]any `json:"arguments,omitempty"`
+}
+
+// Content is one item of a tool result.
+type Content struct {
+	Type     string    `json:"type"` // "text", "image", "audio" or "resource"
+	Text     string    `json:"text,omitempty"`
+	Data     string    `json:"data,omitempty"` // Base64, for images and audio
+	MimeType string    `json:"mimeType,omitempty"`
+	Resource *Resource `json:"resource,omitempty"`
+}
+
+// Resource is an embedded resource in a tool result.
+type Resource struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text,omitempty"`
+}
+
+// CallToolResult is a tool's output. IsError means the tool ran but
+// failed; Content describes the failure.
+type CallToolResult struct {
+	Content []Content `json:"content"`
+	IsError bool      `json:"isError,omitempty"`
+}
+
+// Text joins the result's content as text for the model. Non-text
+// items are described by type.
+func (r *CallToolResult) Text() string {
+	var sb strings.Builder
+	for i, c := range r.Content {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		switch {
+		case c.Type == "text":
+			sb.WriteString(c.Text)
+		case c.Resource != nil && c.Resource.Text != "":
+			sb.WriteString(c.Resource.Text)
+		case c.Resource != nil:
+			fmt.Fprintf(&sb, "[resource %s]", c.Resource.URI)
+		default:
+			fmt.Fprintf(&sb, "[%s %s]", c.Type, c.MimeType)
+		}
+	}
+	return sb.String()
+}
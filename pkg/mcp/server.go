@@ -0,0 +1,429 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxMessageSize limits a message a client may send.
+const maxMessageSize = 4 << 20
+
+// HTTP sessions are dropped after sessionIdleTimeout without a request,
+// and the least recently used goes when there are maxSessions.
+const (
+	maxSessions        = 64
+	sessionIdleTimeout = time.Hour
+)
+
+// supportedVersions are the protocol revisions the server accepts from
+// a client; any other gets ProtocolVersion.
+var supportedVersions = map[string]bool{
+	"2024-11-05":    true,
+	ProtocolVersion: true,
+	"2025-06-18":    true,
+}
+
+// Server serves tools to MCP clients over stdio (ServeStdio) or
+// Streamable HTTP (it is an http.Handler). Only tools the ServeConfig
+// allows are listed or callable.
+type Server struct {
+	info         Implementation
+	instructions string
+	token        string
+
+	tools map[string]Tool
+	order []string
+
+	mu       sync.Mutex
+	sessions map[string]time.Time // Session ID -> last request
+	now      func() time.Time
+}
+
+// NewServer serves the tools cfg allows, introducing itself as info.
+// instructions tell clients' models how to use them (optional).
+func NewServer(info Implementation, instructions string, tools []Tool, cfg ServeConfig) *Server {
+	s := &Server{
+		info:         info,
+		instructions: instructions,
+		token:        cfg.BearerToken(),
+		tools:        make(map[string]Tool),
+		sessions:     make(map[string]time.Time),
+		now:          time.Now,
+	}
+	for _, t := range tools {
+		if !cfg.Allowed(t.Name) || t.Handler == nil {
+			continue
+		}
+		if _, dup := s.tools[t.Name]; dup {
+			continue
+		}
+		s.tools[t.Name] = t
+		s.order = append(s.order, t.Name)
+	}
+	return s
+}
+
+// ToolNames returns the tools clients can call, in the order given.
+func (s *Server) ToolNames() []string {
+	return append([]string(nil), s.order...)
+}
+
+// handle answers one message, or returns nil for notifications and
+// responses. Tool calls end when ctx does.
+func (s *Server) handle(ctx context.Context, msg *Message) *Message {
+	if msg.IsResponse() || len(msg.ID) == 0 {
+		return nil
+	}
+	resp := &Message{JSONRPC: "2.0", ID: msg.ID}
+	result := func(v any) *Message {
+		resp.Result, _ = json.Marshal(v)
+		return resp
+	}
+	fail := func(code int, format string, args ...any) *Message {
+		resp.Error = &RPCError{Code: code, Message: fmt.Sprintf(format, args...)}
+		return resp
+	}
+
+	if msg.JSONRPC != "2.0" || msg.Method == "" {
+		return fail(CodeInvalidRequest, "invalid request")
+	}
+	switch msg.Method {
+	case "initialize":
+		var p initializeParams
+		if err := json.Unmarshal(msg.Params, &p); err != nil {
+			return fail(CodeInvalidParams, "invalid params: %v", err)
+		}
+		version := ProtocolVersion
+		if supportedVersions[p.ProtocolVersion] {
+			version = p.ProtocolVersion
+		}
+		return result(InitializeResult{
+			ProtocolVersion: version,
+			Capabilities:    map[string]any{"tools": map[string]any{}},
+			ServerInfo:      s.info,
+			Instructions:    s.instructions,
+		})
+
+	case "ping":
+		return result(struct{}{})
+
+	case "tools/list":
+		tools := make([]ToolInfo, 0, len(s.order))
+		for _, name := range s.order {
+			t := s.tools[name]
+			tools = append(tools, ToolInfo{
+				Name:        t.Name,
+				Description: t.Description,
+				InputSchema: inputSchema(t.Parameters, t.Required),
+			})
+		}
+		return result(listToolsResult{Tools: tools})
+
+	case "tools/call":
+		var p callToolParams
+		if err := json.Unmarshal(msg.Params, &p); err != nil {
+			return fail(CodeInvalidParams, "invalid params: %v", err)
+		}
+		t, ok := s.tools[p.Name]
+		if !ok {
+			return fail(CodeInvalidParams, "unknown tool: %s", p.Name)
+		}
+		args := p.Arguments
+		if args == nil {
+			args = map[string]any{}
+		}
+		// Failures go back as a result, so the client's model sees them
+		out, err := t.Handler(ctx, args)
+		if err != nil {
+			return result(CallToolResult{Content: []Content{{Type: "text", Text: err.Error()}}, IsError: true})
+		}
+		return result(CallToolResult{Content: []Content{{Type: "text", Text: out}}})
+	}
+	return fail(CodeMethodNotFound, "method not found: %s", msg.Method)
+}
+
+// inputSchema wraps a tool's parameters and required list as a JSON
+// Schema object.
+func inputSchema(params map[string]interface{}, required []string) map[string]any {
+	if params == nil {
+		params = map[string]interface{}{}
+	}
+	schema := map[string]any{"type": "object", "properties": params}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// ServeStdio serves one client reading messages from r and writing
+// replies to w, a JSON message per line. Requests are handled
+// concurrently, so a slow tool doesn't hold up the others. It returns
+// when r ends or ctx is done.
+func (s *Server) ServeStdio(ctx context.Context, r io.Reader, w io.Writer) error {
+	var writeMu sync.Mutex
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	write := func(msg *Message) {
+		data, err := json.Marshal(msg)
+		if err != nil {
+			return
+		}
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		w.Write(append(data, '\n'))
+	}
+
+	lines := make(chan []byte)
+	errc := make(chan error, 1)
+	go func() {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), maxMessageSize)
+		for scanner.Scan() {
+			line := append([]byte(nil), scanner.Bytes()...)
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+				return
+			}
+		}
+		errc <- scanner.Err()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-errc:
+			return err
+		case line := <-lines:
+			if len(strings.TrimSpace(string(line))) == 0 {
+				continue
+			}
+			var msg Message
+			if err := json.Unmarshal(line, &msg); err != nil {
+				write(&Message{JSONRPC: "2.0", ID: json.RawMessage("null"),
+					Error: &RPCError{Code: CodeParseError, Message: "parse error"}})
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if resp := s.handle(ctx, &msg); resp != nil {
+					write(resp)
+				}
+			}()
+		}
+	}
+}
+
+// ServeHTTP serves the Streamable HTTP transport: a client POSTs each
+// message and gets a JSON reply. Clients must send the bearer token if
+// one is set, and come from the same origin if they're browsers; without
+// a token they must also address the server as localhost. The server
+// doesn't open streams of its own, so GET is refused. A tool call ends
+// when its request does.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if !s.allowedHost(r) {
+		http.Error(w, "forbidden host", http.StatusForbidden)
+		return
+	}
+	if !sameOrigin(r) {
+		http.Error(w, "forbidden origin", http.StatusForbidden)
+		return
+	}
+
+	sessionID := r.Header.Get("Mcp-Session-Id")
+	switch r.Method {
+	case http.MethodPost:
+	case http.MethodDelete:
+		if !s.endSession(sessionID) {
+			http.Error(w, "unknown session", http.StatusNotFound)
+		}
+		return
+	default:
+		w.Header().Set("Allow", "POST, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var msg Message
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxMessageSize)).Decode(&msg); err != nil {
+		writeJSON(w, http.StatusBadRequest, &Message{JSONRPC: "2.0", ID: json.RawMessage("null"),
+			Error: &RPCError{Code: CodeParseError, Message: "parse error"}})
+		return
+	}
+
+	if msg.Method == "initialize" && len(msg.ID) > 0 {
+		resp := s.handle(r.Context(), &msg)
+		if resp.Error == nil {
+			w.Header().Set("Mcp-Session-Id", s.newSession())
+		}
+		writeJSON(w, http.StatusOK, resp)
+		return
+	}
+	switch {
+	case sessionID == "":
+		http.Error(w, "missing Mcp-Session-Id", http.StatusBadRequest)
+		return
+	case !s.hasSession(sessionID):
+		http.Error(w, "unknown session", http.StatusNotFound)
+		return
+	}
+
+	resp := s.handle(r.Context(), &msg)
+	if resp == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// authorized checks the bearer token, if the server has one.
+func (s *Server) authorized(r *http.Request) bool {
+	if s.token == "" {
+		return true
+	}
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(got), []byte(s.token)) == 1
+}
+
+// allowedHost rejects requests addressed to another host name when
+// there's no token. In a DNS rebinding attack a web page's own name
+// resolves to this machine, so its requests carry that name as Host
+// (and a matching Origin); only loopback names are let through. With a
+// token any name is fine: a page can't send the token.
+func (s *Server) allowedHost(r *http.Request) bool {
+	if s.token != "" {
+		return true
+	}
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.Trim(host, "[]")
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// sameOrigin rejects browser requests from other sites, e.g. a page on
+// another localhost port. It doesn't stop DNS rebinding on its own (the
+// attacker controls both Origin and Host); allowedHost does.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
+
+// newSession starts a session, first dropping idle ones and, if there
+// are still maxSessions, the least recently used.
+func (s *Server) newSession() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	id := hex.EncodeToString(b)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	var oldest string
+	for sid, last := range s.sessions {
+		if now.Sub(last) > sessionIdleTimeout {
+			delete(s.sessions, sid)
+		} else if oldest == "" || last.Before(s.sessions[oldest]) {
+			oldest = sid
+		}
+	}
+	if len(s.sessions) >= maxSessions {
+		delete(s.sessions, oldest)
+	}
+	s.sessions[id] = now
+	return id
+}
+
+// hasSession reports whether id is a live session, and marks it used.
+func (s *Server) hasSession(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	last, ok := s.sessions[id]
+	if !ok {
+		return false
+	}
+	now := s.now()
+	if now.Sub(last) > sessionIdleTimeout {
+		delete(s.sessions, id)
+		return false
+	}
+	s.sessions[id] = now
+	return true
+}
+
+func (s *Server) endSession(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.sessions[id]; !ok {
+		return false
+	}
+	delete(s.sessions, id)
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, msg *Message) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(msg)
+}
+
+// ListenAndServe serves HTTP clients at addr, on any path, until ctx
+// is done. Without a token it only listens on loopback.
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	if s.token == "" && !isLoopback(addr) {
+		return fmt.Errorf("mcp: serving on %s needs a token", addr)
+	}
+	srv := &http.Server{Addr: addr, Handler: s, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// isLoopback reports whether addr only listens on this machine.
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,306 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testServer serves a move tool, a failing tool and one it doesn't
+// allow.
+func testServer(token string) *Server {
+	tools := []Tool{
+		{
+			Name:        "move_head",
+			Description: "Move the head",
+			Parameters:  map[string]interface{}{"direction": map[string]interface{}{"type": "string"}},
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
+				return "Looking " + args["direction"].(string), nil
+			},
+		},
+		{
+			Name:    "remember",
+			Handler: func(context.Context, map[string]interface{}) (string, error) { return "", errors.New("memory is full") },
+		},
+		{
+			Name:    "shutdown",
+			Handler: func(context.Context, map[string]interface{}) (string, error) { return "bye", nil },
+		},
+	}
+	return NewServer(Implementation{Name: "eva", Version: "2.0"}, "Eva's tools", tools,
+		ServeConfig{Token: token, Allow: []string{"move_*", "remember"}})
+}
+
+func TestServer_HTTP(t *testing.T) {
+	srv := testServer("secret")
+	if got := srv.ToolNames(); !reflect.DeepEqual(got, []string{"move_head", "remember"}) {
+		t.Errorf("ToolNames = %v", got)
+	}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	ctx := context.Background()
+	if _, err := Connect(ctx, NewHTTPTransport(ts.URL, map[string]string{"Authorization": "Bearer wrong"})); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("Connect with a wrong token = %v, want 401", err)
+	}
+
+	c, err := Connect(ctx, NewHTTPTransport(ts.URL, map[string]string{"Authorization": "Bearer secret"}))
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	if info := c.Server(); info.ServerInfo.Name != "eva" || info.Instructions != "Eva's tools" {
+		t.Errorf("server = %+v", info)
+	}
+
+	tools, err := c.ListTools(ctx)
+	if err != nil || len(tools) != 2 {
+		t.Fatalf("ListTools = %+v, %v", tools, err)
+	}
+	if params, _, err := parameters(tools[0].InputSchema); err != nil || params["direction"] == nil {
+		t.Errorf("move_head schema = %v, %v", tools[0].InputSchema, err)
+	}
+
+	result, err := c.CallTool(ctx, "move_head", map[string]any{"direction": "left"})
+	if err != nil || result.IsError || result.Text() != "Looking left" {
+		t.Errorf("move_head = %+v, %v", result, err)
+	}
+	result, err = c.CallTool(ctx, "remember", nil)
+	if err != nil || !result.IsError || result.Text() != "memory is full" {
+		t.Errorf("remember = %+v, %v", result, err)
+	}
+	var rpcErr *RPCError
+	if _, err := c.CallTool(ctx, "shutdown", nil); !errors.As(err, &rpcErr) || rpcErr.Code != CodeInvalidParams {
+		t.Errorf("shutdown = %v, want it refused", err)
+	}
+
+	c.Close()
+	if len(srv.sessions) != 0 {
+		t.Errorf("sessions after Close = %v", srv.sessions)
+	}
+}
+
+func TestServer_HTTPRequests(t *testing.T) {
+	ts := httptest.NewServer(testServer(""))
+	defer ts.Close()
+
+	post := func(body string, header map[string]string) *http.Response {
+		req, _ := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(body))
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		if host := header["Host"]; host != "" {
+			req.Host = host
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("POST: %v", err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	ping := `{"jsonrpc":"2.0","id":1,"method":"ping"}`
+	tests := []struct {
+		name   string
+		body   string
+		header map[string]string
+		want   int
+	}{
+		{"no session", ping, nil, http.StatusBadRequest},
+		{"unknown session", ping, map[string]string{"Mcp-Session-Id": "nope"}, http.StatusNotFound},
+		{"other origin", ping, map[string]string{"Origin": "http://evil.example"}, http.StatusForbidden},
+		{"other localhost port", ping, map[string]string{"Origin": "http://localhost:3000", "Host": "localhost:8765"}, http.StatusForbidden},
+		{"dns rebinding", ping, map[string]string{"Origin": "http://evil.example:8765", "Host": "evil.example:8765"}, http.StatusForbidden},
+		{"rebinding without origin", ping, map[string]string{"Host": "evil.example:8765"}, http.StatusForbidden},
+		{"localhost", ping, map[string]string{"Host": "localhost:8765"}, http.StatusBadRequest}, // Allowed; no session
+		{"ipv6 loopback", ping, map[string]string{"Host": "[::1]:8765"}, http.StatusBadRequest},
+		{"bad json", "{", nil, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		if got := post(tt.body, tt.header).StatusCode; got != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+
+	init := post(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05"}}`, nil)
+	session := init.Header.Get("Mcp-Session-Id")
+	if session == "" {
+		t.Fatal("initialize didn't start a session")
+	}
+	withSession := map[string]string{"Mcp-Session-Id": session}
+	if got := post(`{"jsonrpc":"2.0","method":"notifications/initialized"}`, withSession).StatusCode; got != http.StatusAccepted {
+		t.Errorf("notification: status = %d, want 202", got)
+	}
+	if got := post(ping, withSession).StatusCode; got != http.StatusOK {
+		t.Errorf("ping: status = %d, want 200", got)
+	}
+
+	resp, _ := http.Get(ts.URL)
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("GET: status = %d, want 405", resp.StatusCode)
+	}
+}
+
+func TestServer_AllowedHost(t *testing.T) {
+	tests := []struct {
+		host  string
+		token string
+		want  bool
+	}{
+		{"127.0.0.1:8765", "", true},
+		{"localhost:8765", "", true},
+		{"LOCALHOST", "", true},
+		{"[::1]:8765", "", true},
+		{"evil.example:8765", "", false},
+		{"192.168.1.5:8765", "", false},
+		{"192.168.1.5:8765", "secret", true}, // A page can't send the token
+	}
+	for _, tt := range tests {
+		s := testServer(tt.token)
+		r := httptest.NewRequest(http.MethodPost, "/", nil)
+		r.Host = tt.host
+		if got := s.allowedHost(r); got != tt.want {
+			t.Errorf("allowedHost(%q, token %q) = %v, want %v", tt.host, tt.token, got, tt.want)
+		}
+	}
+}
+
+func TestServer_Sessions(t *testing.T) {
+	s := testServer("")
+	clock := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return clock }
+
+	first := s.newSession()
+	for range maxSessions {
+		clock = clock.Add(time.Second)
+		s.newSession()
+	}
+	if len(s.sessions) != maxSessions {
+		t.Errorf("sessions = %d, want %d", len(s.sessions), maxSessions)
+	}
+	if s.hasSession(first) {
+		t.Error("least recently used session kept past the cap")
+	}
+
+	kept := s.newSession()
+	clock = clock.Add(sessionIdleTimeout + time.Minute)
+	if s.hasSession(kept) {
+		t.Error("idle session didn't expire")
+	}
+	s.newSession()
+	if len(s.sessions) != 1 {
+		t.Errorf("sessions = %d after the others went idle, want 1", len(s.sessions))
+	}
+}
+
+func TestServer_CallCanceledWithRequest(t *testing.T) {
+	stopped := make(chan error, 1)
+	tools := []Tool{{
+		Name: "look_around",
+		Handler: func(ctx context.Context, _ map[string]interface{}) (string, error) {
+			<-ctx.Done()
+			stopped <- ctx.Err()
+			return "", ctx.Err()
+		},
+	}}
+	s := NewServer(Implementation{Name: "eva"}, "", tools, ServeConfig{Allow: []string{"look_around"}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.handle(ctx, &Message{JSONRPC: "2.0", ID: json.RawMessage("1"), Method: "tools/call",
+			Params: json.RawMessage(`{"name":"look_around"}`)})
+		close(done)
+	}()
+	cancel() // The client went away
+	select {
+	case err := <-stopped:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("tool ctx error = %v, want Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("tool kept running after its request ended")
+	}
+	<-done
+}
+
+func TestServer_Stdio(t *testing.T) {
+	srv := testServer("")
+	clientOut, serverIn := io.Pipe()
+	serverOut, clientIn := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.ServeStdio(context.Background(), clientOut, clientIn)
+		clientIn.Close()
+	}()
+
+	replies := bufio.NewScanner(serverOut)
+	send := func(line string) *Message {
+		io.WriteString(serverIn, line+"\n")
+		if !replies.Scan() {
+			t.Fatalf("no reply to %s", line)
+		}
+		var msg Message
+		json.Unmarshal(replies.Bytes(), &msg)
+		return &msg
+	}
+
+	resp := send(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"1999-01-01"}}`)
+	var init InitializeResult
+	json.Unmarshal(resp.Result, &init)
+	if init.ProtocolVersion != ProtocolVersion || init.ServerInfo.Name != "eva" {
+		t.Errorf("initialize = %+v", init)
+	}
+
+	// Notifications get no reply; the next line answers the call
+	io.WriteString(serverIn, `{"jsonrpc":"2.0","method":"notifications/initialized"}`+"\n")
+	resp = send(`{"jsonrpc":"2.0","id":"a","method":"tools/call","params":{"name":"move_head","arguments":{"direction":"up"}}}`)
+	var result CallToolResult
+	json.Unmarshal(resp.Result, &result)
+	if string(resp.ID) != `"a"` || result.Text() != "Looking up" {
+		t.Errorf("call = %s, %+v", resp.ID, result)
+	}
+
+	if resp := send(`not json`); resp.Error == nil || resp.Error.Code != CodeParseError {
+		t.Errorf("bad line = %+v, want a parse error", resp)
+	}
+	if resp := send(`{"jsonrpc":"2.0","id":2,"method":"resources/list"}`); resp.Error == nil || resp.Error.Code != CodeMethodNotFound {
+		t.Errorf("unknown method = %+v, want method not found", resp)
+	}
+
+	serverIn.Close()
+	if err := <-done; err != nil {
+		t.Errorf("ServeStdio = %v, want nil at EOF", err)
+	}
+}
+
+func TestServer_ListenAndServe(t *testing.T) {
+	err := testServer("").ListenAndServe(context.Background(), "0.0.0.0:0")
+	if err == nil || !strings.Contains(err.Error(), "token") {
+		t.Errorf("open address without a token = %v, want an error", err)
+	}
+
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"127.0.0.1:8765", true},
+		{"localhost:8765", true},
+		{"[::1]:8765", true},
+		{":8765", false},
+		{"192.168.1.5:8765", false},
+	}
+	for _, tt := range tests {
+		if got := isLoopback(tt.addr); got != tt.want {
+			t.Errorf("isLoopback(%q) = %v, want %v", tt.addr, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// stdioCloseTimeout is how long Close waits for a server to exit after
+// its stdin closes.
+const stdioCloseTimeout = 2 * time.Second
+
+// Transport carries JSON-RPC messages to and from a server.
+type Transport interface {
+	// Call sends a request and waits for its response.
+	Call(ctx context.Context, req *Message) (*Message, error)
+
+	// Notify sends a notification.
+	Notify(ctx context.Context, msg *Message) error
+
+	// Close shuts the connection down.
+	Close() error
+}
+
+// StdioTransport talks to a server running as a subprocess, one JSON
+// message per line on its stdin and stdout. The server's stderr is
+// passed through to Eva's.
+type StdioTransport struct {
+	cmd *exec.Cmd
+	in  io.WriteCloser
+
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	pending map[string]chan *Message // Keyed by request ID
+	closed  bool
+	err     error         // Why the connection closed
+	done    chan struct{} // Closed when the reader exits
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewStdioTransport starts command and talks MCP over its stdin and
+// stdout. env is added to Eva's environment ("KEY=value").
+func NewStdioTransport(command string, args, env []string) (*StdioTransport, error) {
+	cmd := exec.Command(command, args...)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stderr = os.Stderr
+	return newStdioTransport(cmd)
+}
+
+func newStdioTransport(cmd *exec.Cmd) (*StdioTransport, error) {
+	in, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("mcp: start %s: %w", cmd.Path, err)
+	}
+
+	t := &StdioTransport{
+		cmd:     cmd,
+		in:      in,
+		pending: make(map[string]chan *Message),
+		done:    make(chan struct{}),
+	}
+	go t.read(out)
+	return t, nil
+}
+
+// read dispatches the server's messages until its stdout closes.
+func (t *StdioTransport) read(out io.Reader) {
+	defer close(t.done)
+	scanner := bufio.NewScanner(out)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var msg Message
+		if err := json.Unmarshal(line, &msg); err != nil {
+			fmt.Printf("🔌 MCP: bad message from %s: %v\n", t.cmd.Path, err)
+			continue
+		}
+		switch {
+		case msg.IsResponse():
+			t.mu.Lock()
+			ch := t.pending[string(msg.ID)]
+			delete(t.pending, string(msg.ID))
+			t.mu.Unlock()
+			if ch != nil {
+				ch <- &msg
+			}
+		case len(msg.ID) > 0:
+			// Requests from the server: answer pings, refuse the rest
+			go t.reply(serverRequestReply(&msg))
+		}
+		// Notifications (logging, list changes) are ignored
+	}
+
+	err := scanner.Err()
+	if err == nil {
+		err = ErrClosed
+	}
+	t.fail(err)
+}
+
+// reply writes a response to a server request.
+func (t *StdioTransport) reply(msg *Message) {
+	if err := t.write(msg); err != nil {
+		fmt.Printf("🔌 MCP: reply failed: %v\n", err)
+	}
+}
+
+// fail closes every pending call with err.
+func (t *StdioTransport) fail(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.err == nil {
+		t.err = err
+	}
+	for id, ch := range t.pending {
+		close(ch)
+		delete(t.pending, id)
+	}
+	t.closed = true
+}
+
+// Call sends req and waits for the response with the same ID.
+func (t *StdioTransport) Call(ctx context.Context, req *Message) (*Message, error) {
+	ch := make(chan *Message, 1)
+	id := string(req.ID)
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return nil, errNotSent
+	}
+	t.pending[id] = ch
+	t.mu.Unlock()
+
+	if err := t.write(req); err != nil {
+		t.mu.Lock()
+		delete(t.pending, id)
+		t.mu.Unlock()
+		return nil, err
+	}
+
+	select {
+	case resp, ok := <-ch:
+		if !ok {
+			return nil, t.closedErr()
+		}
+		return resp, nil
+	case <-ctx.Done():
+		t.mu.Lock()
+		delete(t.pending, id)
+		t.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// closedErr says why the connection closed.
+func (t *StdioTransport) closedErr() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.err == nil || errors.Is(t.err, ErrClosed) {
+		return ErrClosed
+	}
+	return fmt.Errorf("%w: %v", ErrClosed, t.err)
+}
+
+// Notify sends a notification.
+func (t *StdioTransport) Notify(ctx context.Context, msg *Message) error {
+	return t.write(msg)
+}
+
+// write sends one message as a line.
+func (t *StdioTransport) write(msg *Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	if _, err := t.in.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("%w: %v", errNotSent, err)
+	}
+	return nil
+}
+
+// Close closes the server's stdin and waits for it to exit, killing it
+// if it doesn't stop reading.
+func (t *StdioTransport) Close() error {
+	t.closeOnce.Do(func() {
+		t.in.Close()
+		select {
+		case <-t.done:
+		case <-time.After(stdioCloseTimeout):
+			t.cmd.Process.Kill()
+			<-t.done
+		}
+		t.fail(ErrClosed)
+		t.closeErr = t.cmd.Wait()
+	})
+	return t.closeErr
+}
+
+// serverRequestReply answers a request the server sent the client.
+func serverRequestReply(req *Message) *Message {
+	resp := &Message{JSONRPC: "2.0", ID: req.ID}
+	if req.Method == "ping" {
+		resp.Result = json.RawMessage("{}")
+	} else {
+		resp.Error = &RPCError{Code: CodeMethodNotFound, Message: "method not found: " + req.Method}
+	}
+	return resp
+}
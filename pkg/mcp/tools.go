@@ -0,0 +1,150 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// maxToolName is the longest tool name the conversation APIs accept.
+const maxToolName = 64
+
+// Tool represents an AI tool that Eva can use from an MCP server.
+type Tool struct {
+	Name        string
+	Description string
+	Parameters  map[string]interface{}
+	Required    []string // Parameters every call must include
+	Handler     func(ctx context.Context, args map[string]interface{}) (string, error)
+}
+
+// ToolsConfig holds dependencies for MCP tools.
+type ToolsConfig struct {
+	Manager *Manager
+}
+
+// Tools returns the allowed tools of every connected server, named
+// "<server>_<tool>". Tools whose input schema can't be expressed as
+// Eva's parameters are left out with a warning.
+func Tools(cfg ToolsConfig) []Tool {
+	m := cfg.Manager
+	if m == nil {
+		return nil
+	}
+
+	var tools []Tool
+	for _, name := range m.order {
+		s := m.servers[name]
+		s.mu.Lock()
+		infos := s.tools
+		s.mu.Unlock()
+
+		for _, info := range infos {
+			params, required, err := parameters(info.InputSchema)
+			if err != nil {
+				fmt.Printf("🔌 MCP: skipping %s/%s: %v\n", name, info.Name, err)
+				continue
+			}
+			serverName, toolName := name, info.Name
+			description := info.Description
+			if description == "" {
+				description = toolName
+			}
+			tools = append(tools, Tool{
+				Name:        toolID(serverName, toolName),
+				Description: fmt.Sprintf("%s (from %s)", description, serverName),
+				Parameters:  params,
+				Required:    required,
+				Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
+					result, err := m.Call(ctx, serverName, toolName, args)
+					if err != nil {
+						return "", err
+					}
+					if result.IsError {
+						return "", errors.New(result.Text())
+					}
+					return result.Text(), nil
+				},
+			})
+		}
+	}
+	return tools
+}
+
+var unsafeName = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// toolID names a server's tool for the model: "<server>_<tool>", with
+// characters the APIs reject replaced and cut to 64 characters.
+func toolID(server, tool string) string {
+	id := unsafeName.ReplaceAllString(server+"_"+tool, "_")
+	if len(id) > maxToolName {
+		id = id[:maxToolName]
+	}
+	return id
+}
+
+// parameters turns a tool's JSON Schema into Eva's parameter map (the
+// schema's properties, each with a single "type") and required list.
+func parameters(schema map[string]any) (map[string]interface{}, []string, error) {
+	props, _ := schema["properties"].(map[string]any)
+	var required []string
+	if list, ok := schema["required"].([]any); ok {
+		for _, r := range list {
+			if name, ok := r.(string); ok && props[name] != nil {
+				required = append(required, name)
+			}
+		}
+	}
+
+	params := make(map[string]interface{}, len(props))
+	for name, p := range props {
+		prop, ok := p.(map[string]any)
+		if !ok {
+			return nil, nil, fmt.Errorf("parameter %q is not a schema", name)
+		}
+		typ := schemaType(prop)
+		if typ == "" {
+			return nil, nil, fmt.Errorf("parameter %q has no single type", name)
+		}
+
+		out := make(map[string]interface{}, len(prop)+1)
+		for k, v := range prop {
+			switch k {
+			case "anyOf", "oneOf", "$schema":
+			default:
+				out[k] = v
+			}
+		}
+		out["type"] = typ
+		params[name] = out
+	}
+	return params, required, nil
+}
+
+// schemaType picks a property's type: its "type", the first non-null
+// entry of a type list (["string", "null"]), or the first typed branch
+// of anyOf/oneOf (how optional values are often written).
+func schemaType(prop map[string]any) string {
+	switch t := prop["type"].(type) {
+	case string:
+		return t
+	case []any:
+		for _, v := range t {
+			if s, ok := v.(string); ok && s != "null" {
+				return s
+			}
+		}
+	}
+	for _, key := range []string{"anyOf", "oneOf"} {
+		branches, _ := prop[key].([]any)
+		for _, b := range branches {
+			if branch, ok := b.(map[string]any); ok {
+				if t, ok := branch["type"].(string); ok && t != "null" {
+					return t
+				}
+			}
+		}
+	}
+	return ""
+}
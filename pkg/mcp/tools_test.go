@@ -0,0 +1,119 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParameters(t *testing.T) {
+	params, required, err := parameters(map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"city":  map[string]any{"type": "string", "description": "City name"},
+			"days":  map[string]any{"type": []any{"integer", "null"}},
+			"units": map[string]any{"anyOf": []any{map[string]any{"type": "null"}, map[string]any{"type": "string", "enum": []any{"c", "f"}}}},
+		},
+		"required": []any{"city", "gone"},
+	})
+	if err != nil {
+		t.Fatalf("parameters: %v", err)
+	}
+	want := map[string]interface{}{
+		"city":  map[string]interface{}{"type": "string", "description": "City name"},
+		"days":  map[string]interface{}{"type": "integer"},
+		"units": map[string]interface{}{"type": "string"},
+	}
+	if !reflect.DeepEqual(params, want) {
+		t.Errorf("parameters = %v, want %v", params, want)
+	}
+	// Names that aren't properties are dropped
+	if !reflect.DeepEqual(required, []string{"city"}) {
+		t.Errorf("required = %v, want [city]", required)
+	}
+
+	if _, _, err := parameters(map[string]any{"properties": map[string]any{"blob": map[string]any{}}}); err == nil {
+		t.Error("want an error for a parameter without a type")
+	}
+	if params, _, err := parameters(map[string]any{"type": "object"}); err != nil || len(params) != 0 {
+		t.Errorf("no properties = %v, %v", params, err)
+	}
+}
+
+func TestToolID(t *testing.T) {
+	tests := []struct {
+		server, tool, want string
+	}{
+		{"files", "read_file", "files_read_file"},
+		{"home.assistant", "lights/on", "home_assistant_lights_on"},
+		{"s", strings.Repeat("x", 80), "s_" + strings.Repeat("x", 62)},
+	}
+	for _, tt := range tests {
+		if got := toolID(tt.server, tt.tool); got != tt.want {
+			t.Errorf("toolID(%q, %q) = %q, want %q", tt.server, tt.tool, got, tt.want)
+		}
+	}
+}
+
+func TestManager(t *testing.T) {
+	m := NewManager(Config{Servers: map[string]ServerConfig{
+		"test":    testServerConfig("echo", "fail", "exit"),
+		"broken":  {Command: "/nonexistent/mcp-server", Allow: []string{"*"}},
+		"skipped": {Command: "/nonexistent/mcp-server", Disabled: true},
+	}})
+	defer m.Close()
+
+	err := m.Connect(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("Connect = %v, want the broken server's error", err)
+	}
+
+	status := m.Status()
+	if len(status) != 2 || status[0].Name != "broken" || status[0].Connected || status[0].Error == "" {
+		t.Errorf("broken status = %+v", status)
+	}
+	if st := status[1]; !st.Connected || st.Server != "test-server 0.1" || !reflect.DeepEqual(st.Tools, []string{"echo", "fail", "exit"}) {
+		t.Errorf("test status = %+v", st)
+	}
+
+	tools := Tools(ToolsConfig{Manager: m})
+	byName := map[string]Tool{}
+	for _, tool := range tools {
+		byName[tool.Name] = tool
+	}
+	if len(tools) != 3 || byName["test_secret"].Handler != nil {
+		t.Fatalf("tools = %v, want the allowed three", tools)
+	}
+	echo := byName["test_echo"]
+	if echo.Description != "Echoes text (from test)" || echo.Parameters["text"] == nil {
+		t.Errorf("echo tool = %+v", echo)
+	}
+
+	if out, err := echo.Handler(t.Context(), map[string]interface{}{"text": "hi"}); err != nil || out != "hi" {
+		t.Errorf("echo = %q, %v", out, err)
+	}
+	if _, err := byName["test_fail"].Handler(t.Context(), nil); err == nil || err.Error() != "it broke" {
+		t.Errorf("fail = %v, want the tool's error", err)
+	}
+	if _, err := m.Call(context.Background(), "test", "secret", nil); !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("secret = %v, want ErrNotAllowed", err)
+	}
+
+	// A server that drops mid-call fails the call, without running it
+	// twice, and is restarted for the next one
+	if _, err := byName["test_exit"].Handler(t.Context(), nil); !errors.Is(err, ErrClosed) {
+		t.Errorf("exit = %v, want ErrClosed", err)
+	}
+	if out, err := echo.Handler(t.Context(), map[string]interface{}{"text": "again"}); err != nil || out != "again" {
+		t.Errorf("echo after restart = %q, %v", out, err)
+	}
+
+	// The handler's context bounds the call
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := echo.Handler(ctx, map[string]interface{}{"text": "late"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("echo with a canceled context = %v, want context.Canceled", err)
+	}
+}
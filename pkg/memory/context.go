@@ -16,7 +16,7 @@ func (m *Memory) SetContext(key, value string) {
 	m.Context[key] = value
 	m.mu.Unlock()
 
-	m.Save()
+	m.persistContext(key)
 }
 
 // GetContext retrieves a situational fact.
@@ -43,11 +43,24 @@ func (m *Memory) DeleteContext(key string) bool {
 	m.mu.Unlock()
 
 	if exists {
-		m.Save()
+		m.persistContext(key)
 	}
 	return exists
 }
 
+// persistContext writes one context fact, or its removal.
+func (m *Memory) persistContext(key string) {
+	m.persist(func(s ItemStore) error {
+		m.mu.RLock()
+		value, ok := m.Context[key]
+		m.mu.RUnlock()
+		if !ok {
+			return s.DeleteContext(key)
+		}
+		return s.PutContext(key, value)
+	})
+}
+
 // GetAllContext returns a copy of all context key-value pairs.
 func (m *Memory) GetAllContext() map[string]string {
 	m.mu.RLock()
@@ -0,0 +1,222 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	openAIEmbeddingsURL   = "https://api.openai.com/v1"
+	defaultEmbeddingModel = "text-embedding-3-small"
+	defaultHashDims       = 512
+)
+
+// Embedder turns text into vectors for semantic recall. Texts with
+// similar meaning should get vectors with a high cosine similarity.
+type Embedder interface {
+	// Embed returns one vector per text, in order.
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+
+	// Name identifies the model. Vectors from different models can't
+	// be compared, so an index built with another one is re-embedded.
+	Name() string
+}
+
+// OpenAIEmbedder embeds text with any OpenAI-compatible /embeddings
+// endpoint.
+type OpenAIEmbedder struct {
+	// BaseURL is the API root, e.g. "http://localhost:8000/v1".
+	BaseURL string
+
+	// APIKey is sent as a bearer token when set.
+	APIKey string
+
+	// Model is the embedding model name.
+	Model string
+
+	// Client is the HTTP client to use.
+	Client *http.Client
+}
+
+// NewOpenAIEmbedder creates an embedder for an OpenAI-compatible
+// endpoint. Empty baseURL and model default to OpenAI's
+// text-embedding-3-small.
+func NewOpenAIEmbedder(baseURL, apiKey, model string) *OpenAIEmbedder {
+	if baseURL == "" {
+		baseURL = openAIEmbeddingsURL
+	}
+	if model == "" {
+		model = defaultEmbeddingModel
+	}
+	return &OpenAIEmbedder{
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+		APIKey:  apiKey,
+		Model:   model,
+		Client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Name returns "openai:<model>".
+func (e *OpenAIEmbedder) Name() string {
+	return "openai:" + e.Model
+}
+
+// Embed sends the texts in one request.
+func (e *OpenAIEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+	body, err := json.Marshal(map[string]interface{}{"model": e.Model, "input": texts})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.BaseURL+"/embeddings", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("memory: embed: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+e.APIKey)
+	}
+
+	resp, err := e.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("memory: embed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("memory: embed: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	var result struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("memory: embed: decode: %w", err)
+	}
+	vectors := make([][]float32, len(texts))
+	for _, d := range result.Data {
+		if d.Index >= 0 && d.Index < len(vectors) {
+			vectors[d.Index] = d.Embedding
+		}
+	}
+	for i, v := range vectors {
+		if v == nil {
+			return nil, fmt.Errorf("memory: embed: no vector for text %d", i)
+		}
+	}
+	return vectors, nil
+}
+
+// HashEmbedder embeds text locally by hashing its words and their
+// character trigrams into a fixed number of dimensions. It needs no
+// network and always gives the same vector for the same text, so it
+// suits tests and offline use, but it matches shared words rather than
+// meaning.
+type HashEmbedder struct {
+	Dims int
+}
+
+// NewHashEmbedder creates a local embedder with 512 dimensions.
+func NewHashEmbedder() *HashEmbedder {
+	return &HashEmbedder{Dims: defaultHashDims}
+}
+
+// Name returns "hash-<dims>".
+func (e *HashEmbedder) Name() string {
+	return fmt.Sprintf("hash-%d", e.dims())
+}
+
+func (e *HashEmbedder) dims() int {
+	if e.Dims <= 0 {
+		return defaultHashDims
+	}
+	return e.Dims
+}
+
+// Embed hashes each text.
+func (e *HashEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = e.embed(text)
+	}
+	return vectors, nil
+}
+
+func (e *HashEmbedder) embed(text string) []float32 {
+	v := make([]float32, e.dims())
+	add := func(feature string, weight float32) {
+		h := fnv.New32a()
+		h.Write([]byte(feature))
+		sum := h.Sum32()
+		if sum&1 == 0 {
+			weight = -weight
+		}
+		v[(sum>>1)%uint32(len(v))] += weight
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		if stopWords[word] {
+			continue
+		}
+		word = stem(word)
+		add("w:"+word, 1)
+		padded := []rune(" " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			add("t:"+string(padded[i:i+3]), 0.3)
+		}
+	}
+
+	var norm float64
+	for _, x := range v {
+		norm += float64(x) * float64(x)
+	}
+	if norm > 0 {
+		scale := float32(1 / math.Sqrt(norm))
+		for i := range v {
+			v[i] *= scale
+		}
+	}
+	return v
+}
+
+// stem strips common English endings so "likes" matches "like".
+func stem(word string) string {
+	for _, suffix := range []string{"ing", "ies", "es", "s", "ed"} {
+		if len(word) > len(suffix)+2 && strings.HasSuffix(word, suffix) {
+			return strings.TrimSuffix(word, suffix)
+		}
+	}
+	return word
+}
+
+// stopWords carry no meaning for matching.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "the": true, "and": true, "or": true, "of": true, "to": true,
+	"in": true, "on": true, "at": true, "for": true, "is": true, "are": true, "was": true,
+	"be": true, "it": true, "i": true, "me": true, "my": true, "you": true, "your": true,
+	"what": true, "who": true, "where": true, "when": true, "do": true, "does": true,
+	"did": true, "about": true, "know": true, "remember": true, "that": true, "this": true,
+}
+
+// Ensure both embedders implement Embedder
+var (
+	_ Embedder = (*OpenAIEmbedder)(nil)
+	_ Embedder = (*HashEmbedder)(nil)
+)
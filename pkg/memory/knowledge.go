@@ -23,7 +23,7 @@ func (m *Memory) CreateKnowledge(topic string) error {
 	m.Knowledge[topic] = make(map[string]any)
 	m.mu.Unlock()
 
-	m.Save()
+	m.persistTopic(topic)
 	return nil
 }
 
@@ -54,11 +54,37 @@ func (m *Memory) DeleteKnowledge(topic string) bool {
 	m.mu.Unlock()
 
 	if exists {
-		m.Save()
+		m.persistTopic(topic)
 	}
 	return exists
 }
 
+// persistTopic writes a knowledge topic, or its removal with its items.
+func (m *Memory) persistTopic(topic string) {
+	m.persist(func(s ItemStore) error {
+		m.mu.RLock()
+		_, ok := m.Knowledge[topic]
+		m.mu.RUnlock()
+		if !ok {
+			return s.DeleteKnowledgeTopic(topic)
+		}
+		return s.PutKnowledgeTopic(topic)
+	})
+}
+
+// persistKnowledgeItem writes one knowledge item, or its removal.
+func (m *Memory) persistKnowledgeItem(topic, key string) {
+	m.persist(func(s ItemStore) error {
+		m.mu.RLock()
+		value, ok := m.Knowledge[topic][key]
+		m.mu.RUnlock()
+		if !ok {
+			return s.DeleteKnowledgeItem(topic, key)
+		}
+		return s.PutKnowledgeItem(topic, key, value)
+	})
+}
+
 // HasKnowledge checks if a knowledge topic exists.
 func (m *Memory) HasKnowledge(topic string) bool {
 	topic = strings.ToLower(strings.TrimSpace(topic))
@@ -102,7 +128,7 @@ func (m *Memory) SetKnowledgeItem(topic, key string, value any) error {
 	m.Knowledge[topic][key] = value
 	m.mu.Unlock()
 
-	m.Save()
+	m.persistKnowledgeItem(topic, key)
 	return nil
 }
 
@@ -140,7 +166,7 @@ func (m *Memory) DeleteKnowledgeItem(topic, key string) bool {
 	m.mu.Unlock()
 
 	if itemExists {
-		m.Save()
+		m.persistKnowledgeItem(topic, key)
 	}
 	return itemExists
 }
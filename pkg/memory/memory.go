@@ -34,6 +34,12 @@ type Memory struct {
 	// store is the persistence backend (not serialized)
 	store Store `json:"-"`
 
+	// index holds embeddings for Recall (not serialized)
+	index *recallIndex `json:"-"`
+
+	// persistMu orders per-item writes to an ItemStore
+	persistMu sync.Mutex `json:"-"`
+
 	// mu protects concurrent access
 	mu sync.RWMutex `json:"-"`
 }
@@ -64,6 +70,8 @@ func NewWithFile(path string) *Memory {
 
 // Save persists memory to the configured store.
 func (m *Memory) Save() error {
+	m.noteRecall() // Stamp new memories with when they were learned
+
 	if m.store == nil {
 		return nil
 	}
@@ -79,6 +87,21 @@ func (m *Memory) Save() error {
 	return m.store.Save(data)
 }
 
+// persist writes a change: one item with write if the store is an
+// ItemStore, or everything through Save otherwise. write reads the
+// item's current state, so concurrent changes end with the latest.
+func (m *Memory) persist(write func(s ItemStore) error) error {
+	s, ok := m.store.(ItemStore)
+	if !ok {
+		return m.Save()
+	}
+	m.noteRecall()
+
+	m.persistMu.Lock()
+	defer m.persistMu.Unlock()
+	return write(s)
+}
+
 // Load reads memory from the configured store.
 func (m *Memory) Load() error {
 	if m.store == nil {
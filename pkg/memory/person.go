@@ -1,15 +1,17 @@
 package memory
 
 import (
+	"maps"
 	"strings"
 	"time"
 )
 
 // PersonMemory stores facts about a person.
 type PersonMemory struct {
-	Name     string    `json:"name"`
-	Facts    []string  `json:"facts"`
-	LastSeen time.Time `json:"last_seen"`
+	Name      string               `json:"name"`
+	Facts     []string             `json:"facts"`
+	FactTimes map[string]time.Time `json:"fact_times,omitempty"` // When each fact was learned
+	LastSeen  time.Time            `json:"last_seen"`
 }
 
 // NewPerson creates a new PersonMemory with the given name.
@@ -23,8 +25,21 @@ func NewPerson(name string) *PersonMemory {
 
 // AddFact adds a fact to the person's memory.
 func (p *PersonMemory) AddFact(fact string) {
+	now := time.Now()
 	p.Facts = append(p.Facts, fact)
-	p.LastSeen = time.Now()
+	if p.FactTimes == nil {
+		p.FactTimes = make(map[string]time.Time)
+	}
+	if _, ok := p.FactTimes[fact]; !ok {
+		p.FactTimes[fact] = now
+	}
+	p.LastSeen = now
+}
+
+// FactTime returns when fact was learned, or zero if that isn't known
+// (facts saved before times were kept).
+func (p *PersonMemory) FactTime(fact string) time.Time {
+	return p.FactTimes[fact]
 }
 
 // HasFact checks if the person has a specific fact (case-insensitive).
@@ -69,7 +84,7 @@ func (m *Memory) RememberPerson(name, fact string) {
 	m.People[name].AddFact(fact)
 	m.mu.Unlock()
 
-	m.Save()
+	m.persistPerson(name)
 }
 
 // RecallPerson retrieves facts about a person.
@@ -145,7 +160,26 @@ func (m *Memory) ForgetPerson(name string) bool {
 	m.mu.Unlock()
 
 	if exists {
-		m.Save()
+		m.persistPerson(name)
 	}
 	return exists
 }
+
+// persistPerson writes a person and their facts, or their removal.
+func (m *Memory) persistPerson(name string) {
+	m.persist(func(s ItemStore) error {
+		m.mu.RLock()
+		var person PersonMemory
+		p, ok := m.People[name]
+		if ok {
+			person = *p
+			person.Facts = append([]string(nil), p.Facts...)
+			person.FactTimes = maps.Clone(p.FactTimes)
+		}
+		m.mu.RUnlock()
+		if !ok {
+			return s.DeletePerson(name)
+		}
+		return s.PutPerson(person)
+	})
+}
@@ -0,0 +1,404 @@
+package memory
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultRecallLimit is how many memories Recall returns when the
+// caller doesn't say.
+const DefaultRecallLimit = 5
+
+// embedBatch is how many texts are embedded per request.
+const embedBatch = 100
+
+// minRecallScore drops matches that are no better than noise.
+const minRecallScore = 0.1
+
+// Recollection is a remembered item that matches a recall query.
+type Recollection struct {
+	Source  string    `json:"source"`  // "person", "location", "context" or "knowledge"
+	Subject string    `json:"subject"` // The person, place, context key or knowledge topic
+	Text    string    `json:"text"`    // The fact itself
+	Time    time.Time `json:"time"`    // When Eva learned it
+	Score   float64   `json:"score"`   // Cosine similarity to the query
+}
+
+// --- Memory methods for semantic recall ---
+
+// EnableRecall sets the embedder for Recall and keeps its index in
+// indexPath (no persistence if empty), so only new memories are
+// embedded after a restart. An index built with another embedder is
+// re-embedded. Without EnableRecall, Recall uses a HashEmbedder and an
+// in-memory index.
+func (m *Memory) EnableRecall(embedder Embedder, indexPath string) error {
+	idx := newRecallIndex(embedder, indexPath)
+	err := idx.load()
+	idx.note(m.documents())
+
+	m.mu.Lock()
+	m.index = idx
+	m.mu.Unlock()
+	return err
+}
+
+// Recall finds the memories most related to query, across people,
+// places, context and knowledge, best first. Memories that aren't
+// embedded yet are embedded first; if that fails, the ones already
+// embedded are searched and the rest are tried again next time.
+func (m *Memory) Recall(ctx context.Context, query string, limit int) ([]Recollection, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, nil
+	}
+	if limit <= 0 {
+		limit = DefaultRecallLimit
+	}
+
+	idx := m.recallIndex()
+	idx.note(m.documents())
+	idx.sync(ctx) // Unembedded memories are skipped until it succeeds
+	vectors, err := idx.embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, err
+	}
+	return idx.search(vectors[0], limit), nil
+}
+
+// SyncRecall embeds memories that aren't in the index yet and saves
+// it, so the first Recall doesn't have to wait.
+func (m *Memory) SyncRecall(ctx context.Context) error {
+	idx := m.recallIndex()
+	idx.note(m.documents())
+	return idx.sync(ctx)
+}
+
+// recallIndex returns the index, creating a local one if EnableRecall
+// wasn't called.
+func (m *Memory) recallIndex() *recallIndex {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.index == nil {
+		m.index = newRecallIndex(NewHashEmbedder(), "")
+	}
+	return m.index
+}
+
+// noteRecall stamps memories added since the last call with the time
+// Eva learned them. It doesn't embed anything.
+func (m *Memory) noteRecall() {
+	m.mu.RLock()
+	idx := m.index
+	m.mu.RUnlock()
+	if idx != nil {
+		idx.note(m.documents())
+	}
+}
+
+// document is one recallable memory.
+type document struct {
+	Source  string    `json:"source"`
+	Subject string    `json:"subject"`
+	Text    string    `json:"text"`
+	Time    time.Time `json:"time"` // Zero if unknown
+}
+
+func (d document) id() string {
+	return d.Source + "\x1f" + d.Subject + "\x1f" + d.Text
+}
+
+// embedText is what gets embedded: the fact with what it's about.
+func (d document) embedText() string {
+	return strings.ReplaceAll(d.Subject, "_", " ") + ": " + d.Text
+}
+
+// documents lists every memory as a document. Each person's fact is
+// its own document.
+func (m *Memory) documents() []document {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var docs []document
+	for key, value := range m.Context {
+		docs = append(docs, document{Source: "context", Subject: key, Text: value})
+	}
+	for name, person := range m.People {
+		for _, fact := range person.Facts {
+			docs = append(docs, document{Source: "person", Subject: name, Text: fact, Time: person.FactTime(fact)})
+		}
+	}
+	for name, loc := range m.Spatial {
+		docs = append(docs, document{Source: "location", Subject: name, Text: describeLocation(loc), Time: loc.LastMentioned})
+	}
+	for topic, items := range m.Knowledge {
+		for key, value := range items {
+			docs = append(docs, document{Source: "knowledge", Subject: topic, Text: key + ": " + valueText(value)})
+		}
+	}
+	return docs
+}
+
+// describeLocation puts a location's fields in one line.
+func describeLocation(loc *Location) string {
+	var parts []string
+	for _, s := range []string{loc.Direction, loc.Distance, loc.Description} {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// valueText formats a knowledge value: strings as they are, anything
+// else as JSON.
+func valueText(value any) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// recallIndex holds a vector per memory.
+type recallIndex struct {
+	embedder Embedder
+	path     string // "" = not persisted
+
+	mu      sync.Mutex
+	entries map[string]*indexEntry // Keyed by document ID
+	dirty   bool                   // Changed since the last save
+}
+
+type indexEntry struct {
+	document
+	Vector vector `json:"vector,omitempty"`
+}
+
+// indexFile is the index as saved.
+type indexFile struct {
+	Embedder string        `json:"embedder"`
+	Entries  []*indexEntry `json:"entries"`
+}
+
+func newRecallIndex(embedder Embedder, path string) *recallIndex {
+	return &recallIndex{
+		embedder: embedder,
+		path:     path,
+		entries:  make(map[string]*indexEntry),
+	}
+}
+
+// load reads the saved index. A missing file is an empty index.
+func (idx *recallIndex) load() error {
+	if idx.path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(idx.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("memory: read index: %w", err)
+	}
+	var file indexFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("memory: read index: %w", err)
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	for _, e := range file.Entries {
+		if file.Embedder != idx.embedder.Name() {
+			e.Vector = nil // Keep when it was learned; embed it again
+			idx.dirty = true
+		}
+		idx.entries[e.id()] = e
+	}
+	return nil
+}
+
+// note adds documents the index hasn't seen, stamped with their time
+// or now, and drops ones that are gone.
+func (idx *recallIndex) note(docs []document) {
+	now := time.Now()
+	seen := make(map[string]bool, len(docs))
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	for _, d := range docs {
+		id := d.id()
+		seen[id] = true
+		if _, ok := idx.entries[id]; ok {
+			continue
+		}
+		if d.Time.IsZero() || d.Time.After(now) {
+			d.Time = now
+		}
+		idx.entries[id] = &indexEntry{document: d}
+		idx.dirty = true
+	}
+	for id := range idx.entries {
+		if !seen[id] {
+			delete(idx.entries, id)
+			idx.dirty = true
+		}
+	}
+}
+
+// sync embeds entries without a vector and saves the index if it
+// changed, keeping what was embedded if a batch fails. The lock isn't
+// held while embedding.
+func (idx *recallIndex) sync(ctx context.Context) error {
+	idx.mu.Lock()
+	var ids, texts []string
+	for id, e := range idx.entries {
+		if e.Vector == nil {
+			ids = append(ids, id)
+			texts = append(texts, e.embedText())
+		}
+	}
+	idx.mu.Unlock()
+
+	for start := 0; start < len(texts); start += embedBatch {
+		end := min(start+embedBatch, len(texts))
+		vectors, err := idx.embedder.Embed(ctx, texts[start:end])
+		if err != nil {
+			idx.save()
+			return err
+		}
+		idx.mu.Lock()
+		for i, v := range vectors {
+			if e, ok := idx.entries[ids[start+i]]; ok {
+				e.Vector = v
+				idx.dirty = true
+			}
+		}
+		idx.mu.Unlock()
+	}
+	return idx.save()
+}
+
+// save writes the index if it changed, atomically.
+func (idx *recallIndex) save() error {
+	idx.mu.Lock()
+	if idx.path == "" || !idx.dirty {
+		idx.mu.Unlock()
+		return nil
+	}
+	file := indexFile{Embedder: idx.embedder.Name(), Entries: make([]*indexEntry, 0, len(idx.entries))}
+	for _, e := range idx.entries {
+		file.Entries = append(file.Entries, e)
+	}
+	sort.Slice(file.Entries, func(i, j int) bool { return file.Entries[i].id() < file.Entries[j].id() })
+	data, err := json.Marshal(file)
+	idx.dirty = false
+	idx.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := writeFileAtomic(idx.path, data); err != nil {
+		idx.mu.Lock()
+		idx.dirty = true
+		idx.mu.Unlock()
+		return fmt.Errorf("memory: save index: %w", err)
+	}
+	return nil
+}
+
+// search returns the limit entries closest to query.
+func (idx *recallIndex) search(query []float32, limit int) []Recollection {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	var results []Recollection
+	for _, e := range idx.entries {
+		if e.Vector == nil {
+			continue
+		}
+		score := cosine(query, e.Vector)
+		if score < minRecallScore {
+			continue
+		}
+		results = append(results, Recollection{
+			Source:  e.Source,
+			Subject: e.Subject,
+			Text:    e.Text,
+			Time:    e.Time,
+			Score:   score,
+		})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Time.After(results[j].Time)
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// cosine is the cosine similarity of a and b (0 if their lengths
+// differ).
+func cosine(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(na*nb)
+}
+
+// vector is an embedding, saved as base64 little-endian float32s to
+// keep the index file small.
+type vector []float32
+
+func (v vector) MarshalJSON() ([]byte, error) {
+	buf := make([]byte, 4*len(v))
+	for i, f := range v {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(f))
+	}
+	return json.Marshal(base64.StdEncoding.EncodeToString(buf))
+}
+
+func (v *vector) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	buf, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	if len(buf)%4 != 0 {
+		return errors.New("vector length is not a multiple of 4 bytes")
+	}
+	out := make(vector, len(buf)/4)
+	for i := range out {
+		out[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf[4*i:]))
+	}
+	*v = out
+	return nil
+}
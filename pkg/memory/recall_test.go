@@ -0,0 +1,251 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// countingEmbedder counts the texts it embeds.
+type countingEmbedder struct {
+	HashEmbedder
+	texts int
+}
+
+func (e *countingEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	e.texts += len(texts)
+	return e.HashEmbedder.Embed(ctx, texts)
+}
+
+// flakyEmbedder fails batches of more than one text, like an embedding
+// API that's down for the sync but not the query.
+type flakyEmbedder struct {
+	HashEmbedder
+	down bool
+}
+
+func (e *flakyEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if e.down && len(texts) > 1 {
+		return nil, errors.New("embeddings unavailable")
+	}
+	return e.HashEmbedder.Embed(ctx, texts)
+}
+
+func testMemory() *Memory {
+	m := New()
+	m.RememberPerson("Alice", "loves sushi and ramen")
+	m.RememberPerson("Alice", "works as a nurse")
+	m.RememberPerson("Bob", "plays the guitar")
+	m.RememberLocation("kitchen", "left", "where the coffee machine is")
+	m.SetContext("owner_name", "Brendan")
+	m.SetKnowledgeItem("recipes", "pancakes", "flour, eggs, milk")
+	return m
+}
+
+func TestRecall(t *testing.T) {
+	m := testMemory()
+	ctx := context.Background()
+
+	tests := []struct {
+		query   string
+		source  string
+		subject string
+		text    string
+	}{
+		{"what food does alice like", "person", "alice", "loves sushi and ramen"},
+		{"who plays guitar", "person", "bob", "plays the guitar"},
+		{"where is the coffee", "location", "kitchen", "left, where the coffee machine is"},
+		{"owner name", "context", "owner_name", "Brendan"},
+		{"how do I make pancakes", "knowledge", "recipes", "pancakes: flour, eggs, milk"},
+	}
+	for _, tt := range tests {
+		got, err := m.Recall(ctx, tt.query, 3)
+		if err != nil {
+			t.Fatalf("Recall(%q): %v", tt.query, err)
+		}
+		if len(got) == 0 {
+			t.Errorf("Recall(%q) found nothing", tt.query)
+			continue
+		}
+		if top := got[0]; top.Source != tt.source || top.Subject != tt.subject || top.Text != tt.text {
+			t.Errorf("Recall(%q)[0] = %+v, want %s %s %q", tt.query, top, tt.source, tt.subject, tt.text)
+		}
+		if got[0].Time.IsZero() || got[0].Score <= 0 {
+			t.Errorf("Recall(%q)[0] has no time or score: %+v", tt.query, got[0])
+		}
+	}
+
+	if got, _ := m.Recall(ctx, "zebra xylophone", 3); len(got) != 0 {
+		t.Errorf("unrelated query = %+v, want nothing", got)
+	}
+	if got, _ := m.Recall(ctx, "alice", 1); len(got) != 1 {
+		t.Errorf("limit 1 = %d results", len(got))
+	}
+
+	// Forgotten memories aren't recalled
+	m.ForgetPerson("bob")
+	if got, _ := m.Recall(ctx, "who plays guitar", 3); len(got) > 0 && got[0].Subject == "bob" {
+		t.Errorf("recalled a forgotten person: %+v", got[0])
+	}
+}
+
+func TestRecall_FactTimes(t *testing.T) {
+	m := New()
+	m.RememberPerson("Alice", "loves sushi")
+	learned := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	m.People["alice"].FactTimes["loves sushi"] = learned
+
+	// Seeing Alice again, or learning more about her, doesn't make the
+	// old fact new
+	m.RecallPerson("alice")
+	m.RememberPerson("Alice", "has a dog")
+	got, err := m.Recall(context.Background(), "sushi", 1)
+	if err != nil {
+		t.Fatalf("Recall: %v", err)
+	}
+	if len(got) != 1 || !got[0].Time.Equal(learned) {
+		t.Errorf("Recall = %+v, want the fact learned at %v", got, learned)
+	}
+}
+
+func TestRecall_Index(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "memory.vectors.json")
+	ctx := context.Background()
+
+	m := testMemory()
+	embedder := &countingEmbedder{}
+	if err := m.EnableRecall(embedder, path); err != nil {
+		t.Fatalf("EnableRecall: %v", err)
+	}
+	if err := m.SyncRecall(ctx); err != nil {
+		t.Fatalf("SyncRecall: %v", err)
+	}
+	if embedder.texts != 6 {
+		t.Errorf("embedded %d texts, want 6", embedder.texts)
+	}
+	first, _ := m.Recall(ctx, "alice sushi", 1)
+
+	// Reloaded, only new memories are embedded, and times are kept
+	time.Sleep(10 * time.Millisecond)
+	m2 := testMemory()
+	m2.RememberPerson("Carol", "has a cat")
+	embedder2 := &countingEmbedder{}
+	if err := m2.EnableRecall(embedder2, path); err != nil {
+		t.Fatalf("EnableRecall again: %v", err)
+	}
+	got, err := m2.Recall(ctx, "alice sushi", 1)
+	if err != nil {
+		t.Fatalf("Recall: %v", err)
+	}
+	if embedder2.texts != 2 { // Carol's fact and the query
+		t.Errorf("embedded %d texts after reload, want 2", embedder2.texts)
+	}
+	if len(got) != 1 || !got[0].Time.Equal(first[0].Time) {
+		t.Errorf("after reload = %+v, want the time from %+v", got, first)
+	}
+
+	// Another embedder re-embeds everything
+	embedder3 := &countingEmbedder{HashEmbedder: HashEmbedder{Dims: 64}}
+	m2.EnableRecall(embedder3, path)
+	m2.SyncRecall(ctx)
+	if embedder3.texts != 7 {
+		t.Errorf("embedded %d texts with a new embedder, want 7", embedder3.texts)
+	}
+
+	var file indexFile
+	data, _ := os.ReadFile(path)
+	if err := json.Unmarshal(data, &file); err != nil || file.Embedder != "hash-64" || len(file.Entries) != 7 {
+		t.Errorf("index file = %s, %d entries, %v", file.Embedder, len(file.Entries), err)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("index file mode = %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestRecall_EmbedFailure(t *testing.T) {
+	ctx := context.Background()
+	m := testMemory()
+	embedder := &flakyEmbedder{}
+	m.EnableRecall(embedder, "")
+	if err := m.SyncRecall(ctx); err != nil {
+		t.Fatalf("SyncRecall: %v", err)
+	}
+
+	// New memories can't be embedded; the ones already embedded are
+	// still searched
+	embedder.down = true
+	m.RememberPerson("Carol", "keeps bees")
+	m.RememberPerson("Dave", "keeps goats")
+	if err := m.SyncRecall(ctx); err == nil {
+		t.Error("SyncRecall with the embedder down = nil, want an error")
+	}
+	got, err := m.Recall(ctx, "alice sushi", 1)
+	if err != nil || len(got) != 1 || got[0].Subject != "alice" {
+		t.Errorf("Recall with the embedder down = %+v, %v, want alice", got, err)
+	}
+
+	// Once it's back, the rest are embedded
+	embedder.down = false
+	got, err = m.Recall(ctx, "keeps bees", 1)
+	if err != nil || len(got) != 1 || got[0].Subject != "carol" {
+		t.Errorf("Recall after recovery = %+v, %v, want carol", got, err)
+	}
+}
+
+func TestHashEmbedder(t *testing.T) {
+	e := NewHashEmbedder()
+	vectors, _ := e.Embed(context.Background(), []string{"Alice likes cats", "alice LIKES cats!", "Bob plays guitar", ""})
+	if len(vectors) != 4 || len(vectors[0]) != 512 {
+		t.Fatalf("Embed = %d vectors", len(vectors))
+	}
+	if got := cosine(vectors[0], vectors[1]); got < 0.999 {
+		t.Errorf("same words, different case: similarity %v, want 1", got)
+	}
+	if got := cosine(vectors[0], vectors[2]); got > 0.3 {
+		t.Errorf("different words: similarity %v, want low", got)
+	}
+	if got := cosine(vectors[0], vectors[3]); got != 0 {
+		t.Errorf("empty text: similarity %v, want 0", got)
+	}
+}
+
+func TestOpenAIEmbedder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/embeddings" || r.Header.Get("Authorization") != "Bearer key" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		var req struct {
+			Model string   `json:"model"`
+			Input []string `json:"input"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Model != "text-embedding-3-small" || len(req.Input) != 2 {
+			http.Error(w, "bad input", http.StatusBadRequest)
+			return
+		}
+		// Out of order, as the API allows
+		w.Write([]byte(`{"data":[{"index":1,"embedding":[0,1]},{"index":0,"embedding":[1,0]}]}`))
+	}))
+	defer server.Close()
+
+	e := NewOpenAIEmbedder(server.URL+"/v1/", "key", "")
+	vectors, err := e.Embed(context.Background(), []string{"a", "b"})
+	if err != nil || len(vectors) != 2 || vectors[0][0] != 1 || vectors[1][1] != 1 {
+		t.Errorf("Embed = %v, %v", vectors, err)
+	}
+	if e.Name() != "openai:text-embedding-3-small" {
+		t.Errorf("Name = %q", e.Name())
+	}
+
+	e.APIKey = "wrong"
+	if _, err := e.Embed(context.Background(), []string{"a", "b"}); err == nil {
+		t.Error("want an error for a failed request")
+	}
+}
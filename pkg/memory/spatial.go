@@ -49,7 +49,7 @@ func (m *Memory) SetLocation(name string, loc *Location) {
 	m.Spatial[name] = loc
 	m.mu.Unlock()
 
-	m.Save()
+	m.persistLocation(name)
 }
 
 // RememberLocation is a convenience method to store a location by its properties.
@@ -122,11 +122,28 @@ func (m *Memory) ForgetLocation(name string) bool {
 	m.mu.Unlock()
 
 	if exists {
-		m.Save()
+		m.persistLocation(name)
 	}
 	return exists
 }
 
+// persistLocation writes one location, or its removal.
+func (m *Memory) persistLocation(name string) {
+	m.persist(func(s ItemStore) error {
+		m.mu.RLock()
+		var loc Location
+		l, ok := m.Spatial[name]
+		if ok {
+			loc = *l
+		}
+		m.mu.RUnlock()
+		if !ok {
+			return s.DeleteLocation(name)
+		}
+		return s.PutLocation(name, loc)
+	})
+}
+
 // GetLocationsByDirection returns all locations in a given direction.
 func (m *Memory) GetLocationsByDirection(direction string) map[string]*Location {
 	direction = strings.ToLower(strings.TrimSpace(direction))
@@ -0,0 +1,588 @@
+package memory
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	_ "modernc.org/sqlite" // Pure-Go driver, registered as "sqlite"
+)
+
+// migrations build the SQLite schema. Each runs once, in order, in its
+// own transaction; PRAGMA user_version records how many have run.
+// Append new ones; never edit one that has shipped.
+var migrations = []string{
+	// 1: Normalized tables for each kind of memory
+	`CREATE TABLE context (
+		key        TEXT PRIMARY KEY,
+		value      TEXT NOT NULL,
+		updated_at TEXT NOT NULL
+	);
+	CREATE TABLE locations (
+		name           TEXT PRIMARY KEY,
+		direction      TEXT NOT NULL DEFAULT '',
+		distance       TEXT NOT NULL DEFAULT '',
+		description    TEXT NOT NULL DEFAULT '',
+		last_mentioned TEXT
+	);
+	CREATE TABLE people (
+		id        INTEGER PRIMARY KEY,
+		name      TEXT NOT NULL UNIQUE,
+		last_seen TEXT
+	);
+	CREATE TABLE facts (
+		id         INTEGER PRIMARY KEY,
+		person_id  INTEGER NOT NULL REFERENCES people(id) ON DELETE CASCADE,
+		position   INTEGER NOT NULL,
+		fact       TEXT NOT NULL,
+		created_at TEXT NOT NULL,
+		UNIQUE (person_id, position)
+	);
+	CREATE TABLE knowledge_topics (
+		id   INTEGER PRIMARY KEY,
+		name TEXT NOT NULL UNIQUE
+	);
+	CREATE TABLE knowledge_items (
+		topic_id   INTEGER NOT NULL REFERENCES knowledge_topics(id) ON DELETE CASCADE,
+		key        TEXT NOT NULL,
+		value      TEXT NOT NULL, -- JSON
+		updated_at TEXT NOT NULL,
+		PRIMARY KEY (topic_id, key)
+	);`,
+	// 2: Bookkeeping, such as whether memory.json was imported. A
+	// database that already holds memories imported it before this
+	// table existed.
+	`CREATE TABLE meta (
+		key   TEXT PRIMARY KEY,
+		value TEXT NOT NULL
+	);
+	INSERT INTO meta (key, value)
+		SELECT 'json_imported_at', strftime('%Y-%m-%dT%H:%M:%fZ', 'now')
+		WHERE EXISTS (SELECT 1 FROM context) OR EXISTS (SELECT 1 FROM locations)
+			OR EXISTS (SELECT 1 FROM people) OR EXISTS (SELECT 1 FROM knowledge_topics);`,
+}
+
+// metaJSONImported is the meta key recording when a JSON memory file
+// was imported.
+const metaJSONImported = "json_imported_at"
+
+// SQLiteStore implements ItemStore in a SQLite database, with a table
+// per kind of memory. Each change is its own transaction, so a write
+// never touches other items and a crash never loses more than it.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore opens (or creates, readable only by its owner) the
+// database at path and brings its schema up to date.
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	if dir := filepath.Dir(path); dir != "" && dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("create directory: %w", err)
+		}
+	}
+	f, err := os.OpenFile(path, os.O_RDONLY|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("memory: open %s: %w", path, err)
+	}
+	f.Close()
+
+	// foreign_keys is per connection, so it goes in the DSN. WAL lets
+	// readers (another Eva process, a backup) work while Eva writes.
+	dsn := (&url.URL{
+		Scheme:   "file",
+		Path:     path,
+		RawQuery: "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)",
+	}).String()
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("memory: open %s: %w", path, err)
+	}
+	db.SetMaxOpenConns(1) // One writer; SQLite serializes them anyway
+
+	s := &SQLiteStore{db: db}
+	if err := s.migrate(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("memory: migrate %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// NewWithSQLite creates a memory that persists to a SQLite database.
+func NewWithSQLite(path string) (*Memory, error) {
+	store, err := NewSQLiteStore(path)
+	if err != nil {
+		return nil, err
+	}
+	m := New()
+	m.store = store
+	if err := m.Load(); err != nil {
+		store.Close()
+		return nil, err
+	}
+	return m, nil
+}
+
+// migrate runs the migrations the database hasn't had yet.
+func (s *SQLiteStore) migrate() error {
+	var version int
+	if err := s.db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return err
+	}
+	if version > len(migrations) {
+		return fmt.Errorf("schema version %d is newer than this build (%d)", version, len(migrations))
+	}
+	for i := version; i < len(migrations); i++ {
+		err := s.tx(func(tx *sql.Tx) error {
+			if _, err := tx.Exec(migrations[i]); err != nil {
+				return err
+			}
+			_, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", i+1))
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// SchemaVersion returns how many migrations the database has had.
+func (s *SQLiteStore) SchemaVersion() (int, error) {
+	var version int
+	err := s.db.QueryRow("PRAGMA user_version").Scan(&version)
+	return version, err
+}
+
+// tx runs fn in a transaction, committing if it succeeds.
+func (s *SQLiteStore) tx(fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// snapshot is Memory's JSON form, as Load returns and Save takes.
+type snapshot struct {
+	Context   map[string]string         `json:"context"`
+	Spatial   map[string]*Location      `json:"spatial"`
+	People    map[string]*PersonMemory  `json:"people"`
+	Knowledge map[string]map[string]any `json:"knowledge"`
+}
+
+// Load reads every table into Memory's JSON form.
+func (s *SQLiteStore) Load() ([]byte, error) {
+	snap := snapshot{
+		Context:   make(map[string]string),
+		Spatial:   make(map[string]*Location),
+		People:    make(map[string]*PersonMemory),
+		Knowledge: make(map[string]map[string]any),
+	}
+
+	err := s.query("SELECT key, value FROM context", func(rows *sql.Rows) error {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return err
+		}
+		snap.Context[key] = value
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.query("SELECT name, direction, distance, description, last_mentioned FROM locations", func(rows *sql.Rows) error {
+		var name string
+		var loc Location
+		var mentioned sql.NullString
+		if err := rows.Scan(&name, &loc.Direction, &loc.Distance, &loc.Description, &mentioned); err != nil {
+			return err
+		}
+		loc.LastMentioned = parseTime(mentioned)
+		snap.Spatial[name] = &loc
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.query(`SELECT p.name, p.last_seen, f.fact, f.created_at FROM people p
+		LEFT JOIN facts f ON f.person_id = p.id
+		ORDER BY p.id, f.position`, func(rows *sql.Rows) error {
+		var name string
+		var seen, fact, created sql.NullString
+		if err := rows.Scan(&name, &seen, &fact, &created); err != nil {
+			return err
+		}
+		p, ok := snap.People[name]
+		if !ok {
+			p = &PersonMemory{Name: name, Facts: []string{}, FactTimes: make(map[string]time.Time), LastSeen: parseTime(seen)}
+			snap.People[name] = p
+		}
+		if fact.Valid {
+			p.Facts = append(p.Facts, fact.String)
+			if _, ok := p.FactTimes[fact.String]; !ok {
+				p.FactTimes[fact.String] = parseTime(created)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.query(`SELECT t.name, i.key, i.value FROM knowledge_topics t
+		LEFT JOIN knowledge_items i ON i.topic_id = t.id`, func(rows *sql.Rows) error {
+		var topic string
+		var key, value sql.NullString
+		if err := rows.Scan(&topic, &key, &value); err != nil {
+			return err
+		}
+		items, ok := snap.Knowledge[topic]
+		if !ok {
+			items = make(map[string]any)
+			snap.Knowledge[topic] = items
+		}
+		if key.Valid {
+			var v any
+			if err := json.Unmarshal([]byte(value.String), &v); err != nil {
+				return fmt.Errorf("knowledge %s/%s: %w", topic, key.String, err)
+			}
+			items[key.String] = v
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(snap)
+}
+
+// query runs a query and calls fn for each row.
+func (s *SQLiteStore) query(query string, fn func(rows *sql.Rows) error) error {
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return fmt.Errorf("memory: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := fn(rows); err != nil {
+			return fmt.Errorf("memory: %w", err)
+		}
+	}
+	return rows.Err()
+}
+
+// Save replaces everything with data (Memory's JSON form) in one
+// transaction. Memory only calls it when asked to save everything;
+// changes go through the Put and Delete methods.
+func (s *SQLiteStore) Save(data []byte) error {
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return fmt.Errorf("memory: %w", err)
+	}
+	return s.tx(func(tx *sql.Tx) error {
+		for _, table := range []string{"context", "locations", "facts", "people", "knowledge_items", "knowledge_topics"} {
+			if _, err := tx.Exec("DELETE FROM " + table); err != nil {
+				return err
+			}
+		}
+		return importSnapshot(tx, snap)
+	})
+}
+
+// Import adds the memories in data (Memory's JSON form, as in
+// memory.json) in one transaction. Items already in the database are
+// replaced; others are kept.
+func (s *SQLiteStore) Import(data []byte) error {
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return fmt.Errorf("memory: import: %w", err)
+	}
+	return s.tx(func(tx *sql.Tx) error {
+		return importSnapshot(tx, snap)
+	})
+}
+
+// ImportJSONFile imports a JSON memory file, such as one a JSONStore
+// wrote.
+func (s *SQLiteStore) ImportJSONFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("memory: import: %w", err)
+	}
+	return s.Import(data)
+}
+
+// ImportJSONFileOnce imports a JSON memory file unless the database
+// has had one imported, and reports whether it imported. The import
+// and the record of it commit together, so an import cut short (say
+// by a crash) runs again next time.
+func (s *SQLiteStore) ImportJSONFileOnce(path string) (bool, error) {
+	imported := false
+	err := s.tx(func(tx *sql.Tx) error {
+		var at string
+		err := tx.QueryRow("SELECT value FROM meta WHERE key = ?", metaJSONImported).Scan(&at)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var snap snapshot
+		if err := json.Unmarshal(data, &snap); err != nil {
+			return err
+		}
+		if err := importSnapshot(tx, snap); err != nil {
+			return err
+		}
+		if _, err := tx.Exec("INSERT INTO meta (key, value) VALUES (?, ?)", metaJSONImported, formatTime(time.Now())); err != nil {
+			return err
+		}
+		imported = true
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("memory: import: %w", err)
+	}
+	return imported, nil
+}
+
+func importSnapshot(tx *sql.Tx, snap snapshot) error {
+	for key, value := range snap.Context {
+		if err := putContext(tx, key, value); err != nil {
+			return err
+		}
+	}
+	for name, loc := range snap.Spatial {
+		if loc == nil {
+			continue
+		}
+		if err := putLocation(tx, name, *loc); err != nil {
+			return err
+		}
+	}
+	for name, p := range snap.People {
+		if p == nil {
+			continue
+		}
+		person := *p
+		person.Name = name // Keyed by name; the map key wins
+		if err := putPerson(tx, person); err != nil {
+			return err
+		}
+	}
+	for topic, items := range snap.Knowledge {
+		if _, err := putTopic(tx, topic); err != nil {
+			return err
+		}
+		for key, value := range items {
+			if err := putKnowledgeItem(tx, topic, key, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// PutContext stores a context fact.
+func (s *SQLiteStore) PutContext(key, value string) error {
+	return s.tx(func(tx *sql.Tx) error { return putContext(tx, key, value) })
+}
+
+// DeleteContext removes a context fact.
+func (s *SQLiteStore) DeleteContext(key string) error {
+	_, err := s.db.Exec("DELETE FROM context WHERE key = ?", key)
+	return err
+}
+
+// PutLocation stores a location.
+func (s *SQLiteStore) PutLocation(name string, loc Location) error {
+	return s.tx(func(tx *sql.Tx) error { return putLocation(tx, name, loc) })
+}
+
+// DeleteLocation removes a location.
+func (s *SQLiteStore) DeleteLocation(name string) error {
+	_, err := s.db.Exec("DELETE FROM locations WHERE name = ?", name)
+	return err
+}
+
+// PutPerson stores a person and replaces their facts. Facts they
+// already had keep when they were first stored.
+func (s *SQLiteStore) PutPerson(p PersonMemory) error {
+	return s.tx(func(tx *sql.Tx) error { return putPerson(tx, p) })
+}
+
+// DeletePerson removes a person and their facts.
+func (s *SQLiteStore) DeletePerson(name string) error {
+	_, err := s.db.Exec("DELETE FROM people WHERE name = ?", name)
+	return err
+}
+
+// PutKnowledgeTopic creates a knowledge topic if it doesn't exist.
+func (s *SQLiteStore) PutKnowledgeTopic(topic string) error {
+	return s.tx(func(tx *sql.Tx) error {
+		_, err := putTopic(tx, topic)
+		return err
+	})
+}
+
+// DeleteKnowledgeTopic removes a knowledge topic and its items.
+func (s *SQLiteStore) DeleteKnowledgeTopic(topic string) error {
+	_, err := s.db.Exec("DELETE FROM knowledge_topics WHERE name = ?", topic)
+	return err
+}
+
+// PutKnowledgeItem stores an item, as JSON, creating its topic if
+// needed.
+func (s *SQLiteStore) PutKnowledgeItem(topic, key string, value any) error {
+	return s.tx(func(tx *sql.Tx) error { return putKnowledgeItem(tx, topic, key, value) })
+}
+
+// DeleteKnowledgeItem removes an item from a topic.
+func (s *SQLiteStore) DeleteKnowledgeItem(topic, key string) error {
+	_, err := s.db.Exec(`DELETE FROM knowledge_items
+		WHERE key = ? AND topic_id = (SELECT id FROM knowledge_topics WHERE name = ?)`, key, topic)
+	return err
+}
+
+// Close closes the database.
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+func putContext(tx *sql.Tx, key, value string) error {
+	_, err := tx.Exec(`INSERT INTO context (key, value, updated_at) VALUES (?, ?, ?)
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at`,
+		key, value, formatTime(time.Now()))
+	return err
+}
+
+func putLocation(tx *sql.Tx, name string, loc Location) error {
+	_, err := tx.Exec(`INSERT INTO locations (name, direction, distance, description, last_mentioned)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (name) DO UPDATE SET direction = excluded.direction, distance = excluded.distance,
+			description = excluded.description, last_mentioned = excluded.last_mentioned`,
+		name, loc.Direction, loc.Distance, loc.Description, nullTime(loc.LastMentioned))
+	return err
+}
+
+func putPerson(tx *sql.Tx, p PersonMemory) error {
+	var id int64
+	err := tx.QueryRow(`INSERT INTO people (name, last_seen) VALUES (?, ?)
+		ON CONFLICT (name) DO UPDATE SET last_seen = excluded.last_seen
+		RETURNING id`, p.Name, nullTime(p.LastSeen)).Scan(&id)
+	if err != nil {
+		return err
+	}
+
+	// Keep when each fact was learned: the time p carries, else when it
+	// was first stored. A fact with neither (from a JSON file written
+	// before facts had times) can't be newer than the person's last
+	// sighting.
+	created := make(map[string]string)
+	rows, err := tx.Query("SELECT fact, created_at FROM facts WHERE person_id = ?", id)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var fact, at string
+		if err := rows.Scan(&fact, &at); err != nil {
+			rows.Close()
+			return err
+		}
+		if _, ok := created[fact]; !ok {
+			created[fact] = at
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM facts WHERE person_id = ?", id); err != nil {
+		return err
+	}
+	fallback := time.Now()
+	if !p.LastSeen.IsZero() {
+		fallback = p.LastSeen
+	}
+	for i, fact := range p.Facts {
+		at, ok := created[fact]
+		if learned := p.FactTime(fact); !learned.IsZero() {
+			at = formatTime(learned)
+		} else if !ok {
+			at = formatTime(fallback)
+		}
+		if _, err := tx.Exec("INSERT INTO facts (person_id, position, fact, created_at) VALUES (?, ?, ?, ?)",
+			id, i, fact, at); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// putTopic creates a topic if needed and returns its ID.
+func putTopic(tx *sql.Tx, topic string) (int64, error) {
+	var id int64
+	err := tx.QueryRow(`INSERT INTO knowledge_topics (name) VALUES (?)
+		ON CONFLICT (name) DO UPDATE SET name = excluded.name
+		RETURNING id`, topic).Scan(&id)
+	return id, err
+}
+
+func putKnowledgeItem(tx *sql.Tx, topic, key string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("knowledge %s/%s: %w", topic, key, err)
+	}
+	id, err := putTopic(tx, topic)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`INSERT INTO knowledge_items (topic_id, key, value, updated_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (topic_id, key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at`,
+		id, key, string(data), formatTime(time.Now()))
+	return err
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// nullTime stores the zero time as NULL.
+func nullTime(t time.Time) sql.NullString {
+	if t.IsZero() {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: formatTime(t), Valid: true}
+}
+
+func parseTime(s sql.NullString) time.Time {
+	if !s.Valid {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339Nano, s.String)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// Ensure SQLiteStore implements ItemStore
+var _ ItemStore = (*SQLiteStore)(nil)
@@ -0,0 +1,348 @@
+package memory
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func testSQLite(t *testing.T) (*Memory, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "memory.db")
+	m, err := NewWithSQLite(path)
+	if err != nil {
+		t.Fatalf("NewWithSQLite: %v", err)
+	}
+	t.Cleanup(func() { m.Close() })
+	return m, path
+}
+
+// reopen loads the database at path into a new Memory.
+func reopen(t *testing.T, path string) *Memory {
+	t.Helper()
+	m, err := NewWithSQLite(path)
+	if err != nil {
+		t.Fatalf("NewWithSQLite: %v", err)
+	}
+	t.Cleanup(func() { m.Close() })
+	return m
+}
+
+func TestSQLiteStore_Migrate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "eva", "memory.db")
+	s, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	version, err := s.SchemaVersion()
+	if err != nil || version != len(migrations) {
+		t.Errorf("SchemaVersion() = %d, %v, want %d", version, err, len(migrations))
+	}
+	s.Close()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Errorf("mode = %o, want 600", mode)
+	}
+
+	// Opening again runs nothing
+	s, err = NewSQLiteStore(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	s.Close()
+
+	// A database from a newer build is refused
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(fmt.Sprintf("PRAGMA user_version = %d", len(migrations)+1)); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+	if _, err := NewSQLiteStore(path); err == nil {
+		t.Error("NewSQLiteStore accepted a newer schema")
+	}
+}
+
+func TestSQLiteStore_Items(t *testing.T) {
+	m, path := testSQLite(t)
+	m.SetContext("owner_name", "Brendan")
+	m.SetContext("mood", "happy")
+	m.RememberPerson("Alice", "loves sushi")
+	m.RememberPerson("Alice", "works as a nurse")
+	m.RememberPerson("Bob", "plays the guitar")
+	m.SetLocation("kitchen", &Location{Direction: "left", Distance: "5m", Description: "coffee"})
+	m.SetKnowledgeItem("recipes", "pancakes", "flour, eggs, milk")
+	m.SetKnowledgeItem("recipes", "servings", map[string]any{"pancakes": 4.0})
+	m.CreateKnowledge("empty")
+
+	got := reopen(t, path)
+	if v, _ := got.GetContext("owner_name"); v != "Brendan" {
+		t.Errorf("context owner_name = %q, want Brendan", v)
+	}
+	if facts := got.RecallPerson("Alice"); !reflect.DeepEqual(facts, []string{"loves sushi", "works as a nurse"}) {
+		t.Errorf("Alice facts = %v", facts)
+	}
+	loc := got.GetLocation("kitchen")
+	if loc == nil || loc.Distance != "5m" || loc.LastMentioned.IsZero() {
+		t.Errorf("kitchen = %+v", loc)
+	}
+	if v, _ := got.GetKnowledgeItem("recipes", "servings"); !reflect.DeepEqual(v, map[string]any{"pancakes": 4.0}) {
+		t.Errorf("servings = %v", v)
+	}
+	if !got.HasKnowledge("empty") {
+		t.Error("empty topic not stored")
+	}
+
+	// Deletes
+	m.DeleteContext("mood")
+	m.ForgetPerson("Bob")
+	m.ForgetLocation("kitchen")
+	m.DeleteKnowledgeItem("recipes", "servings")
+	m.DeleteKnowledge("empty")
+
+	got = reopen(t, path)
+	want := map[string]int{"context": 1, "spatial": 0, "people": 1, "knowledge_topics": 1, "knowledge_items": 1}
+	if stats := got.Stats(); !reflect.DeepEqual(stats, want) {
+		t.Errorf("Stats() = %v, want %v", stats, want)
+	}
+
+	// Removing a topic removes its items
+	m.DeleteKnowledge("recipes")
+	var items int
+	m.store.(*SQLiteStore).db.QueryRow("SELECT COUNT(*) FROM knowledge_items").Scan(&items)
+	if items != 0 {
+		t.Errorf("%d knowledge items left after deleting topic", items)
+	}
+}
+
+func TestSQLiteStore_FactTimes(t *testing.T) {
+	m, path := testSQLite(t)
+	db := m.store.(*SQLiteStore).db
+	createdAt := func(fact string) string {
+		var at string
+		db.QueryRow("SELECT created_at FROM facts WHERE fact = ?", fact).Scan(&at)
+		return at
+	}
+
+	m.RememberPerson("Alice", "loves sushi")
+	first := createdAt("loves sushi")
+	if first == "" {
+		t.Fatal("fact not stored")
+	}
+	time.Sleep(2 * time.Millisecond)
+	m.RememberPerson("Alice", "works as a nurse")
+
+	if got := createdAt("loves sushi"); got != first {
+		t.Errorf("created_at = %s after adding a fact, want %s", got, first)
+	}
+	if createdAt("works as a nurse") == first {
+		t.Error("new fact has the old fact's time")
+	}
+
+	// The times come back with the facts
+	alice := reopen(t, path).GetPerson("alice")
+	if got := formatTime(alice.FactTime("loves sushi")); got != first {
+		t.Errorf("FactTime after reopening = %s, want %s", got, first)
+	}
+
+	// A fact imported without a time gets the person's last sighting
+	seen := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	data := fmt.Sprintf(`{"people": {"bob": {"name": "bob", "facts": ["plays the guitar"], "last_seen": %q}}}`, seen.Format(time.RFC3339))
+	if err := m.store.(*SQLiteStore).Import([]byte(data)); err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if got := createdAt("plays the guitar"); got != formatTime(seen) {
+		t.Errorf("imported fact created_at = %s, want %s", got, formatTime(seen))
+	}
+}
+
+func TestSQLiteStore_SaveAndImport(t *testing.T) {
+	m, path := testSQLite(t)
+	m.SetContext("keep", "old")
+	m.SetContext("drop", "old")
+
+	other := New()
+	other.SetContext("keep", "new")
+	other.RememberPerson("Carol", "paints")
+	other.SetKnowledgeItem("books", "dune", "Frank Herbert")
+	data, err := other.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Import merges
+	store := m.store.(*SQLiteStore)
+	if err := store.Import(data); err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	got := reopen(t, path)
+	if v, _ := got.GetContext("keep"); v != "new" {
+		t.Errorf("keep = %q, want new", v)
+	}
+	if !got.HasContext("drop") {
+		t.Error("Import removed an item it didn't mention")
+	}
+	if got.GetPerson("carol") == nil {
+		t.Error("Carol not imported")
+	}
+
+	// Save replaces
+	if err := store.Save(data); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	got = reopen(t, path)
+	if got.HasContext("drop") {
+		t.Error("Save kept an item it didn't mention")
+	}
+	if v, _ := got.RecallKnowledge("books", "dune"); v != "Frank Herbert" {
+		t.Errorf("books/dune = %q", v)
+	}
+}
+
+func TestSQLiteStore_ImportJSONFile(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "memory.json")
+	old := NewWithFile(jsonPath)
+	old.RememberPerson("Alice", "loves sushi")
+	old.RememberPerson("Alice", "works as a nurse")
+	old.RememberLocation("kitchen", "left", "coffee")
+	old.SetContext("owner_name", "Brendan")
+	old.SetKnowledgeItem("recipes", "pancakes", "flour, eggs, milk")
+
+	info, err := os.Stat(jsonPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Errorf("JSON mode = %o, want 600", mode)
+	}
+
+	store, err := NewSQLiteStore(filepath.Join(dir, "memory.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.ImportJSONFile(jsonPath); err != nil {
+		t.Fatalf("ImportJSONFile: %v", err)
+	}
+	got := NewWithStore(store)
+	defer got.Close()
+
+	want, _ := old.ToJSON()
+	have, _ := got.ToJSON()
+	if string(have) != string(want) {
+		t.Errorf("imported memory differs:\n got %s\nwant %s", have, want)
+	}
+
+	if err := store.ImportJSONFile(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("ImportJSONFile(missing) = nil, want error")
+	}
+}
+
+func TestSQLiteStore_ImportJSONFileOnce(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "memory.json")
+	dbPath := filepath.Join(dir, "memory.db")
+	old := NewWithFile(jsonPath)
+	old.SetContext("owner_name", "Brendan")
+
+	// A file that doesn't parse leaves nothing recorded, so it's tried again
+	if err := os.WriteFile(jsonPath, []byte("{broken"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	store, err := NewSQLiteStore(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported, err := store.ImportJSONFileOnce(jsonPath); err == nil || imported {
+		t.Errorf("ImportJSONFileOnce(broken) = %v, %v, want an error", imported, err)
+	}
+	store.Close()
+
+	old.SetContext("mood", "happy") // Rewrites the file
+	store, err = NewSQLiteStore(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported, err := store.ImportJSONFileOnce(jsonPath); err != nil || !imported {
+		t.Errorf("ImportJSONFileOnce = %v, %v, want imported", imported, err)
+	}
+	// Forgotten memories don't come back from the file
+	store.DeleteContext("mood")
+	if imported, err := store.ImportJSONFileOnce(jsonPath); err != nil || imported {
+		t.Errorf("second ImportJSONFileOnce = %v, %v, want skipped", imported, err)
+	}
+	got := NewWithStore(store)
+	owner, _ := got.GetContext("owner_name")
+	if _, ok := got.GetContext("mood"); owner != "Brendan" || ok {
+		t.Errorf("context = %v, want only owner_name", got.Context)
+	}
+	got.Close()
+
+	// A database that held memories before imports were recorded
+	// counts as imported
+	oldPath := filepath.Join(dir, "v1.db")
+	db, err := sql.Open("sqlite", oldPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, q := range []string{
+		migrations[0],
+		"INSERT INTO context (key, value, updated_at) VALUES ('owner_name', 'Sam', '2025-01-01T00:00:00Z')",
+		"PRAGMA user_version = 1",
+	} {
+		if _, err := db.Exec(q); err != nil {
+			t.Fatal(err)
+		}
+	}
+	db.Close()
+	store, err = NewSQLiteStore(oldPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if imported, err := store.ImportJSONFileOnce(jsonPath); err != nil || imported {
+		t.Errorf("ImportJSONFileOnce(v1 with data) = %v, %v, want skipped", imported, err)
+	}
+}
+
+func TestSQLiteStore_Concurrent(t *testing.T) {
+	m, path := testSQLite(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				m.RememberPerson("Alice", fmt.Sprintf("fact %d-%d", i, j))
+				m.SetContext(fmt.Sprintf("key%d", i), fmt.Sprint(j))
+				m.SetKnowledgeItem("counts", fmt.Sprintf("worker%d", i), j)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	got := reopen(t, path)
+	if n := len(got.RecallPerson("Alice")); n != 80 {
+		t.Errorf("Alice has %d facts, want 80", n)
+	}
+	for i := 0; i < 8; i++ {
+		if v, _ := got.GetContext(fmt.Sprintf("key%d", i)); v != "9" {
+			t.Errorf("key%d = %q, want 9", i, v)
+		}
+	}
+	if n := len(got.ListKnowledgeItems("counts")); n != 8 {
+		t.Errorf("counts has %d items, want 8", n)
+	}
+}
@@ -19,6 +19,28 @@ type Store interface {
 	Close() error
 }
 
+// ItemStore is a Store that can write one item at a time. Memory uses
+// it for each change instead of saving everything. Put methods insert
+// or replace the item.
+type ItemStore interface {
+	Store
+
+	PutContext(key, value string) error
+	DeleteContext(key string) error
+
+	PutLocation(name string, loc Location) error
+	DeleteLocation(name string) error
+
+	// PutPerson replaces the person's facts with p.Facts.
+	PutPerson(p PersonMemory) error
+	DeletePerson(name string) error
+
+	PutKnowledgeTopic(topic string) error
+	DeleteKnowledgeTopic(topic string) error
+	PutKnowledgeItem(topic, key string, value any) error
+	DeleteKnowledgeItem(topic, key string) error
+}
+
 // JSONStore implements Store for file-based JSON persistence.
 type JSONStore struct {
 	FilePath string
@@ -29,21 +51,14 @@ func NewJSONStore(path string) *JSONStore {
 	return &JSONStore{FilePath: path}
 }
 
-// Save writes data to the JSON file.
+// Save writes data to the JSON file. The file is replaced atomically
+// and only readable by its owner.
 func (s *JSONStore) Save(data []byte) error {
 	if s.FilePath == "" {
 		return nil
 	}
 
-	// Ensure directory exists
-	dir := filepath.Dir(s.FilePath)
-	if dir != "" && dir != "." {
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return fmt.Errorf("create directory: %w", err)
-		}
-	}
-
-	if err := os.WriteFile(s.FilePath, data, 0644); err != nil {
+	if err := writeFileAtomic(s.FilePath, data); err != nil {
 		return fmt.Errorf("write file: %w", err)
 	}
 
@@ -75,6 +90,32 @@ func (s *JSONStore) Close() error {
 // Ensure JSONStore implements Store
 var _ Store = (*JSONStore)(nil)
 
+// writeFileAtomic writes data to a temporary file (mode 0600) and
+// renames it over path, so a crash never leaves a half-written file.
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
 
 
 
@@ -24,6 +24,7 @@ type Tool struct {
 	Name        string                 `json:"name"`
 	Description string                 `json:"description"`
 	Parameters  map[string]interface{} `json:"parameters"`
+	Required    []string               `json:"required,omitempty"`
 	Handler     func(args map[string]interface{}) (string, error)
 }
 
@@ -54,8 +55,18 @@ type Client struct {
 	OnSpeechStarted  func() // User started speaking
 	OnSpeechStopped  func() // User stopped speaking
 
+	// ClientVAD disables server-side turn detection; the caller endpoints
+	// locally and calls CommitTurn. Set before ConfigureSession.
+	ClientVAD bool
+
 	// Internal state
 	closed bool
+
+	// Response state, for Inject
+	respMu        sync.Mutex
+	responding    bool     // A response is in progress
+	injects       []string // Text waiting for it to finish
+	retryResponse bool     // A response.create was refused while one was active
 }
 
 // NewClient creates a new Realtime API client.
@@ -119,6 +130,10 @@ func (c *Client) ConfigureSession(instructions string, voice string) error {
 
 	apiTools := make([]map[string]interface{}, len(c.tools))
 	for i, tool := range c.tools {
+		required := tool.Required
+		if required == nil {
+			required = []string{}
+		}
 		apiTools[i] = map[string]interface{}{
 			"type":        "function",
 			"name":        tool.Name,
@@ -126,11 +141,21 @@ func (c *Client) ConfigureSession(instructions string, voice string) error {
 			"parameters": map[string]interface{}{
 				"type":       "object",
 				"properties": tool.Parameters,
-				"required":   []string{},
+				"required":   required,
 			},
 		}
 	}
 
+	var turnDetection interface{}
+	if !c.ClientVAD {
+		turnDetection = map[string]interface{}{
+			"type":                "server_vad",
+			"threshold":           0.5,
+			"prefix_padding_ms":   300,
+			"silence_duration_ms": 300,
+		}
+	}
+
 	msg := map[string]interface{}{
 		"type": "session.update",
 		"session": map[string]interface{}{
@@ -142,14 +167,9 @@ func (c *Client) ConfigureSession(instructions string, voice string) error {
 			"input_audio_transcription": map[string]interface{}{
 				"model": "whisper-1",
 			},
-			"turn_detection": map[string]interface{}{
-				"type":                "server_vad",
-				"threshold":           0.5,
-				"prefix_padding_ms":   300,
-				"silence_duration_ms": 300,
-			},
-			"tools":       apiTools,
-			"tool_choice": "auto",
+			"turn_detection": turnDetection,
+			"tools":          apiTools,
+			"tool_choice":    "auto",
 		},
 	}
 
@@ -179,6 +199,17 @@ func (c *Client) CommitAudio() error {
 	})
 }
 
+// CommitTurn commits the audio buffer and asks for a response, ending the
+// user's turn when ClientVAD is set.
+func (c *Client) CommitTurn() error {
+	if err := c.CommitAudio(); err != nil {
+		return err
+	}
+	return c.sendJSON(map[string]string{
+		"type": "response.create",
+	})
+}
+
 // ClearAudio clears the audio input buffer.
 func (c *Client) ClearAudio() error {
 	return c.sendJSON(map[string]string{
@@ -211,6 +242,58 @@ func (c *Client) SendText(text string) error {
 	})
 }
 
+// Inject adds text to the conversation as a system message and asks for
+// a response, such as a background tool's result. While a response is
+// in progress the text waits until it finishes, so Eva isn't cut off.
+func (c *Client) Inject(text string) error {
+	if !c.IsConnected() {
+		return fmt.Errorf("not connected")
+	}
+	c.respMu.Lock()
+	if c.responding {
+		c.injects = append(c.injects, text)
+		c.respMu.Unlock()
+		return nil
+	}
+	c.responding = true
+	c.respMu.Unlock()
+	return c.sendInjected([]string{text})
+}
+
+// sendInjected adds texts to the conversation and asks for one
+// response to them all.
+func (c *Client) sendInjected(texts []string) error {
+	err := func() error {
+		for _, text := range texts {
+			msg := map[string]interface{}{
+				"type": "conversation.item.create",
+				"item": map[string]interface{}{
+					"type": "message",
+					"role": "system",
+					"content": []map[string]interface{}{
+						{
+							"type": "input_text",
+							"text": text,
+						},
+					},
+				},
+			}
+			if err := c.sendJSON(msg); err != nil {
+				return err
+			}
+		}
+		return c.sendJSON(map[string]string{
+			"type": "response.create",
+		})
+	}()
+	if err != nil {
+		c.respMu.Lock()
+		c.responding = false
+		c.respMu.Unlock()
+	}
+	return err
+}
+
 // CancelResponse interrupts the current response.
 func (c *Client) CancelResponse() error {
 	return c.sendJSON(map[string]string{
@@ -307,11 +390,37 @@ func (c *Client) handleMessages() {
 		case "response.function_call_arguments.done":
 			c.handleFunctionCall(msg)
 
+		case "response.created":
+			c.respMu.Lock()
+			c.responding = true
+			c.respMu.Unlock()
+
 		case "response.done":
-			// Full response complete
+			// Full response complete; send text injected meanwhile
+			c.respMu.Lock()
+			c.responding = false
+			texts, retry := c.injects, c.retryResponse
+			c.injects, c.retryResponse = nil, false
+			if len(texts) > 0 || retry {
+				c.responding = true
+			}
+			c.respMu.Unlock()
+			if len(texts) > 0 || retry {
+				if err := c.sendInjected(texts); err != nil {
+					fmt.Printf("⚠️  Failed to send injected text: %v\n", err)
+				}
+			}
 
 		case "error":
 			if errData, ok := msg["error"].(map[string]interface{}); ok {
+				// A response was requested while another was running:
+				// ask again once it finishes
+				if code, _ := errData["code"].(string); code == "conversation_already_has_active_response" {
+					c.respMu.Lock()
+					c.retryResponse = true
+					c.respMu.Unlock()
+					continue
+				}
 				if errMsg, ok := errData["message"].(string); ok {
 					fmt.Printf("⚠️  OpenAI error: %s\n", errMsg)
 					if c.OnError != nil {
@@ -0,0 +1,73 @@
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// clockLayouts are the accepted times of day.
+var clockLayouts = []string{"15:04", "3:04pm", "3pm", "15"}
+
+// dateLayouts are the accepted absolute times, in the local zone
+// (input is lowercased first).
+var dateLayouts = []string{"2006-01-02t15:04", "2006-01-02 15:04", "2006-01-02 3:04pm"}
+
+// ParseTime parses an alarm time: a time of day ("7:30", "7:30 am",
+// "19:05", "7pm"), which means its next occurrence after now, or an
+// absolute date and time ("2026-10-19 07:30", RFC 3339).
+func ParseTime(s string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, strings.TrimSpace(s)); err == nil {
+		return t, nil
+	}
+	norm := strings.ToLower(strings.Join(strings.Fields(s), " "))
+	norm = strings.NewReplacer("a.m.", "am", "p.m.", "pm").Replace(norm)
+	norm = strings.NewReplacer(" am", "am", " pm", "pm").Replace(norm)
+
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, norm, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+
+	for _, layout := range clockLayouts {
+		c, err := time.Parse(layout, norm)
+		if err != nil {
+			continue
+		}
+		t := time.Date(now.Year(), now.Month(), now.Day(), c.Hour(), c.Minute(), 0, 0, now.Location())
+		if !t.After(now) {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("can't read %q as a time (try 7:30, 7:30pm or 2006-01-02 15:04)", s)
+}
+
+// FormatDuration formats d for speech: "5 minutes", "1 hour 30 minutes",
+// "45 seconds".
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d < time.Minute {
+		return plural(int(d.Seconds()), "second")
+	}
+	h, m, sec := int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60
+	var parts []string
+	if h > 0 {
+		parts = append(parts, plural(h, "hour"))
+	}
+	if m > 0 {
+		parts = append(parts, plural(m, "minute"))
+	}
+	if sec > 0 && h == 0 {
+		parts = append(parts, plural(sec, "second"))
+	}
+	return strings.Join(parts, " ")
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
@@ -0,0 +1,60 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2026, 10, 14, 10, 17, 0, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"10:30", time.Date(2026, 10, 14, 10, 30, 0, 0, time.UTC)},
+		{"7:30", time.Date(2026, 10, 15, 7, 30, 0, 0, time.UTC)},
+		{"7:30 am", time.Date(2026, 10, 15, 7, 30, 0, 0, time.UTC)},
+		{"7:30 P.M.", time.Date(2026, 10, 14, 19, 30, 0, 0, time.UTC)},
+		{"7pm", time.Date(2026, 10, 14, 19, 0, 0, 0, time.UTC)},
+		{"19:05", time.Date(2026, 10, 14, 19, 5, 0, 0, time.UTC)},
+		{"10:17", time.Date(2026, 10, 15, 10, 17, 0, 0, time.UTC)},
+		{"2026-10-20 06:45", time.Date(2026, 10, 20, 6, 45, 0, 0, time.UTC)},
+		{"2026-10-20T06:45", time.Date(2026, 10, 20, 6, 45, 0, 0, time.UTC)},
+		{"2026-10-20T06:45:00Z", time.Date(2026, 10, 20, 6, 45, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := ParseTime(tt.in, now)
+		if err != nil {
+			t.Errorf("ParseTime(%q): %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseTime(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "tomorrow", "25:00", "7:75"} {
+		if _, err := ParseTime(in, now); err == nil {
+			t.Errorf("ParseTime(%q) succeeded, want error", in)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{45 * time.Second, "45 seconds"},
+		{time.Second, "1 second"},
+		{5 * time.Minute, "5 minutes"},
+		{90 * time.Second, "1 minute 30 seconds"},
+		{90 * time.Minute, "1 hour 30 minutes"},
+		{2*time.Hour + 10*time.Second, "2 hours"},
+	}
+	for _, tt := range tests {
+		if got := FormatDuration(tt.d); got != tt.want {
+			t.Errorf("FormatDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,497 @@
+// Package schedule provides Eva's timers, alarms and reminders. Items
+// are persisted to a JSON file, so they survive restarts, and fire
+// through a callback that announces them.
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Kind is the type of a scheduled item.
+type Kind string
+
+const (
+	KindTimer    Kind = "timer"    // Countdown ("10 minute pasta timer")
+	KindAlarm    Kind = "alarm"    // Absolute time ("wake me at 7")
+	KindReminder Kind = "reminder" // Message at a time, optionally recurring
+)
+
+// Item is a scheduled timer, alarm or reminder.
+type Item struct {
+	ID       string        `json:"id"`
+	Kind     Kind          `json:"kind"`
+	Label    string        `json:"label,omitempty"`    // Name, or the reminder's message
+	Due      time.Time     `json:"due"`                // Next time it fires
+	Duration time.Duration `json:"duration,omitempty"` // Timers: the countdown length
+	Recur    string        `json:"recur,omitempty"`    // Reminders: cron expression
+	Emotion  string        `json:"emotion,omitempty"`  // Played when it fires (optional)
+	Created  time.Time     `json:"created"`
+}
+
+// Recurring reports whether the item repeats.
+func (it Item) Recurring() bool {
+	return it.Recur != ""
+}
+
+// Name returns how to refer to the item in speech: "pasta timer",
+// "alarm", "reminder to call mom".
+func (it Item) Name() string {
+	switch {
+	case it.Label == "":
+		return string(it.Kind)
+	case it.Kind == KindReminder:
+		return "reminder to " + it.Label
+	}
+	return it.Label + " " + string(it.Kind)
+}
+
+// Firing is an item going off.
+type Firing struct {
+	Item
+	Late time.Duration // How long after Due it fired (e.g. after a restart)
+}
+
+// SnoozeWindow is how long after an item goes off that Snooze can
+// bring it back.
+const SnoozeWindow = 10 * time.Minute
+
+// Errors returned by the scheduler.
+var (
+	ErrNotFound  = errors.New("no matching timer, alarm or reminder")
+	ErrAmbiguous = errors.New("more than one timer, alarm or reminder matches")
+)
+
+// Scheduler holds the scheduled items and fires them when due.
+type Scheduler struct {
+	path string
+
+	// OnFire is called from Run for each item that goes off. It should
+	// return quickly.
+	OnFire func(Firing)
+
+	// OnChange is called after items are added, removed or rescheduled.
+	OnChange func()
+
+	// MaxLate drops one-off items that would fire later than this (after
+	// a long shutdown); recurring ones skip to their next time.
+	MaxLate time.Duration
+
+	now  func() time.Time
+	wake chan struct{}
+
+	mu        sync.Mutex
+	items     map[string]*Item
+	nextID    int
+	lastFired *Item // For snoozing what just went off
+	firedAt   time.Time
+}
+
+// storeData is the JSON structure for the schedule file.
+type storeData struct {
+	Version   int     `json:"version"`
+	UpdatedAt string  `json:"updated_at"`
+	NextID    int     `json:"next_id"`
+	Items     []*Item `json:"items"`
+}
+
+const currentVersion = 1
+
+// New creates a scheduler persisted at path, loading any items saved
+// there. An empty path keeps the items in memory only.
+func New(path string) (*Scheduler, error) {
+	s := &Scheduler{
+		path:    path,
+		MaxLate: 12 * time.Hour,
+		now:     time.Now,
+		wake:    make(chan struct{}, 1),
+		items:   make(map[string]*Item),
+		nextID:  1,
+	}
+
+	if path == "" {
+		return s, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+	if _, err := os.Stat(path); err == nil {
+		if err := s.load(); err != nil {
+			return nil, fmt.Errorf("failed to load schedule: %w", err)
+		}
+	}
+	return s, nil
+}
+
+// NewDefault creates a scheduler at the default location
+// (~/.eva/schedule.json).
+func NewDefault() (*Scheduler, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return New(filepath.Join(homeDir, ".eva", "schedule.json"))
+}
+
+// load reads the schedule from disk.
+func (s *Scheduler) load() error {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	var stored storeData
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	for _, it := range stored.Items {
+		if it.Recurring() {
+			if _, err := ParseCron(it.Recur); err != nil {
+				return err
+			}
+		}
+		s.items[it.ID] = it
+	}
+	s.nextID = max(stored.NextID, 1)
+	return nil
+}
+
+// save writes the schedule to disk, readable only by the owner. Caller
+// holds mu.
+func (s *Scheduler) save() error {
+	if s.path == "" {
+		return nil
+	}
+	stored := storeData{
+		Version:   currentVersion,
+		UpdatedAt: s.now().Format(time.RFC3339),
+		NextID:    s.nextID,
+		Items:     s.sortedLocked(),
+	}
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	// Write to temp file first, then rename (atomic write)
+	tmpPath := s.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+	return nil
+}
+
+// AddTimer starts a countdown of d.
+func (s *Scheduler) AddTimer(d time.Duration, label string) (Item, error) {
+	if d <= 0 {
+		return Item{}, fmt.Errorf("timer length must be positive, got %v", d)
+	}
+	return s.add(&Item{Kind: KindTimer, Label: label, Duration: d, Due: s.now().Add(d)})
+}
+
+// AddAlarm sets an alarm for at.
+func (s *Scheduler) AddAlarm(at time.Time, label string) (Item, error) {
+	if !at.After(s.now()) {
+		return Item{}, fmt.Errorf("alarm time %s is in the past", at.Format("Jan 2 15:04"))
+	}
+	return s.add(&Item{Kind: KindAlarm, Label: label, Due: at})
+}
+
+// AddReminder sets a one-off reminder for at.
+func (s *Scheduler) AddReminder(at time.Time, message string) (Item, error) {
+	if !at.After(s.now()) {
+		return Item{}, fmt.Errorf("reminder time %s is in the past", at.Format("Jan 2 15:04"))
+	}
+	return s.add(&Item{Kind: KindReminder, Label: message, Due: at})
+}
+
+// AddRecurring sets a reminder that repeats on a cron schedule (see
+// ParseCron).
+func (s *Scheduler) AddRecurring(cron, message string) (Item, error) {
+	c, err := ParseCron(cron)
+	if err != nil {
+		return Item{}, err
+	}
+	due := c.Next(s.now())
+	if due.IsZero() {
+		return Item{}, fmt.Errorf("cron %q never fires", cron)
+	}
+	return s.add(&Item{Kind: KindReminder, Label: message, Recur: c.String(), Due: due})
+}
+
+// SetEmotion sets the emotion played when the item fires.
+func (s *Scheduler) SetEmotion(id, emotion string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	it, ok := s.items[id]
+	if !ok {
+		return ErrNotFound
+	}
+	it.Emotion = emotion
+	return s.save()
+}
+
+func (s *Scheduler) add(it *Item) (Item, error) {
+	s.mu.Lock()
+	it.ID = strconv.Itoa(s.nextID)
+	it.Created = s.now()
+	s.nextID++
+	s.items[it.ID] = it
+	err := s.save()
+	if err != nil {
+		delete(s.items, it.ID)
+	}
+	added := *it
+	s.mu.Unlock()
+
+	if err != nil {
+		return Item{}, err
+	}
+	s.changed()
+	return added, nil
+}
+
+// List returns the pending items, soonest first.
+func (s *Scheduler) List() []Item {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sorted := s.sortedLocked()
+	items := make([]Item, len(sorted))
+	for i, it := range sorted {
+		items[i] = *it
+	}
+	return items
+}
+
+// sortedLocked returns the items by due time. Caller holds mu.
+func (s *Scheduler) sortedLocked() []*Item {
+	items := make([]*Item, 0, len(s.items))
+	for _, it := range s.items {
+		items = append(items, it)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if !items[i].Due.Equal(items[j].Due) {
+			return items[i].Due.Before(items[j].Due)
+		}
+		return items[i].ID < items[j].ID
+	})
+	return items
+}
+
+// Find returns the item matching ref: an ID, a label (case-insensitive,
+// whole or part), or a kind ("timer"). An empty ref matches the only
+// pending item.
+func (s *Scheduler) Find(ref string) (Item, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	it, err := s.findLocked(ref)
+	if err != nil {
+		return Item{}, err
+	}
+	return *it, nil
+}
+
+// findLocked resolves ref. Caller holds mu.
+func (s *Scheduler) findLocked(ref string) (*Item, error) {
+	ref = strings.ToLower(strings.TrimSpace(ref))
+	if it, ok := s.items[ref]; ok {
+		return it, nil
+	}
+
+	var exact, partial, kind []*Item
+	for _, it := range s.sortedLocked() {
+		label := strings.ToLower(it.Label)
+		switch {
+		case ref == "":
+			partial = append(partial, it)
+		case label == ref || strings.ToLower(it.Name()) == ref:
+			exact = append(exact, it)
+		case label != "" && strings.Contains(label, ref):
+			partial = append(partial, it)
+		case strings.TrimSuffix(ref, "s") == string(it.Kind):
+			kind = append(kind, it)
+		}
+	}
+	for _, matches := range [][]*Item{exact, partial, kind} {
+		switch len(matches) {
+		case 0:
+			continue
+		case 1:
+			return matches[0], nil
+		}
+		names := make([]string, len(matches))
+		for i, it := range matches {
+			names[i] = it.Name()
+		}
+		return nil, fmt.Errorf("%w: %s", ErrAmbiguous, strings.Join(names, ", "))
+	}
+	return nil, ErrNotFound
+}
+
+// Cancel removes the item matching ref (see Find).
+func (s *Scheduler) Cancel(ref string) (Item, error) {
+	s.mu.Lock()
+	it, err := s.findLocked(ref)
+	if err != nil {
+		s.mu.Unlock()
+		return Item{}, err
+	}
+	delete(s.items, it.ID)
+	err = s.save()
+	s.mu.Unlock()
+
+	if err != nil {
+		return Item{}, err
+	}
+	s.changed()
+	return *it, nil
+}
+
+// Snooze puts off the item matching ref by d. Within SnoozeWindow of
+// an item going off, an empty ref or one that matches it brings that
+// item back in d instead.
+func (s *Scheduler) Snooze(ref string, d time.Duration) (Item, error) {
+	if d <= 0 {
+		return Item{}, fmt.Errorf("snooze length must be positive, got %v", d)
+	}
+
+	s.mu.Lock()
+	now := s.now()
+	var it *Item
+	var err error
+	if s.lastFired != nil && now.Sub(s.firedAt) <= SnoozeWindow && s.matchesLastFired(ref) {
+		// Bring back what just went off; a recurring one gets a one-off copy
+		again := *s.lastFired
+		again.ID = strconv.Itoa(s.nextID)
+		again.Recur = ""
+		again.Created = now
+		s.nextID++
+		s.items[again.ID] = &again
+		s.lastFired = nil
+		it = &again
+	} else if it, err = s.findLocked(ref); err != nil {
+		s.mu.Unlock()
+		return Item{}, err
+	}
+	it.Due = maxTime(it.Due, now).Add(d)
+	err = s.save()
+	snoozed := *it
+	s.mu.Unlock()
+
+	if err != nil {
+		return Item{}, err
+	}
+	s.changed()
+	return snoozed, nil
+}
+
+// matchesLastFired reports whether ref names the item that last went
+// off. Caller holds mu.
+func (s *Scheduler) matchesLastFired(ref string) bool {
+	ref = strings.ToLower(strings.TrimSpace(ref))
+	it := s.lastFired
+	label := strings.ToLower(it.Label)
+	return ref == "" || ref == it.ID || strings.TrimSuffix(ref, "s") == string(it.Kind) ||
+		(label != "" && strings.Contains(label, ref))
+}
+
+// Run fires items as they come due until ctx is done. Items that came
+// due while Eva was off fire right away, with Firing.Late set.
+func (s *Scheduler) Run(ctx context.Context) {
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+	for {
+		s.fireDue()
+
+		// Sleep until the soonest item, or an hour if there are none
+		wait := time.Hour
+		s.mu.Lock()
+		if items := s.sortedLocked(); len(items) > 0 {
+			wait = min(items[0].Due.Sub(s.now()), time.Hour)
+		}
+		s.mu.Unlock()
+		timer.Reset(wait)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wake:
+		case <-timer.C:
+		}
+	}
+}
+
+// fireDue fires every item that is due and reschedules or removes it.
+func (s *Scheduler) fireDue() {
+	s.mu.Lock()
+	now := s.now()
+	var fired []Firing
+	changed := false
+	for _, it := range s.sortedLocked() {
+		if it.Due.After(now) {
+			break
+		}
+		changed = true
+		late := now.Sub(it.Due)
+		if late <= s.MaxLate {
+			fired = append(fired, Firing{Item: *it, Late: late})
+			last := *it
+			s.lastFired, s.firedAt = &last, now
+		}
+		if it.Recurring() {
+			if c, err := ParseCron(it.Recur); err == nil {
+				if next := c.Next(now); !next.IsZero() {
+					it.Due = next
+					continue
+				}
+			}
+		}
+		delete(s.items, it.ID)
+	}
+	if !changed {
+		s.mu.Unlock()
+		return
+	}
+	err := s.save()
+	s.mu.Unlock()
+
+	if err != nil {
+		fmt.Printf("⏰ Failed to save schedule: %v\n", err)
+	}
+	for _, f := range fired {
+		if s.OnFire != nil {
+			s.OnFire(f)
+		}
+	}
+	s.changed()
+}
+
+// changed wakes Run and notifies OnChange.
+func (s *Scheduler) changed() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	if s.OnChange != nil {
+		s.OnChange()
+	}
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
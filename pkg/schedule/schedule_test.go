@@ -0,0 +1,250 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type schedHarness struct {
+	s     *Scheduler
+	path  string
+	clock time.Time
+	fired []Firing
+}
+
+// newSchedHarness creates a scheduler in a temp dir with a fake clock.
+func newSchedHarness(t *testing.T) *schedHarness {
+	t.Helper()
+	h := &schedHarness{
+		path:  filepath.Join(t.TempDir(), "schedule.json"),
+		clock: time.Date(2026, 10, 14, 10, 0, 0, 0, time.UTC),
+	}
+	h.s = h.open(t)
+	return h
+}
+
+// open loads a scheduler from the harness file, as after a restart.
+func (h *schedHarness) open(t *testing.T) *Scheduler {
+	t.Helper()
+	s, err := New(h.path)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	s.now = func() time.Time { return h.clock }
+	s.OnFire = func(f Firing) { h.fired = append(h.fired, f) }
+	return s
+}
+
+func (h *schedHarness) advance(d time.Duration) {
+	h.clock = h.clock.Add(d)
+	h.s.fireDue()
+}
+
+func TestScheduler_TimerFires(t *testing.T) {
+	h := newSchedHarness(t)
+	if _, err := h.s.AddTimer(0, "bad"); err == nil {
+		t.Error("AddTimer(0) succeeded")
+	}
+	it, err := h.s.AddTimer(10*time.Minute, "pasta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if it.Name() != "pasta timer" || !it.Due.Equal(h.clock.Add(10*time.Minute)) {
+		t.Errorf("item = %+v", it)
+	}
+
+	h.advance(9 * time.Minute)
+	if len(h.fired) != 0 {
+		t.Fatalf("fired early: %+v", h.fired)
+	}
+	h.advance(time.Minute)
+	if len(h.fired) != 1 || h.fired[0].Label != "pasta" || h.fired[0].Late != 0 {
+		t.Fatalf("fired = %+v, want the pasta timer", h.fired)
+	}
+	if n := len(h.s.List()); n != 0 {
+		t.Errorf("%d items left after firing, want 0", n)
+	}
+}
+
+func TestScheduler_Persistence(t *testing.T) {
+	h := newSchedHarness(t)
+	h.s.AddTimer(10*time.Minute, "tea")
+	h.s.AddAlarm(h.clock.Add(48*time.Hour), "flight")
+	rem, err := h.s.AddRecurring("0 9 * * *", "stretch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.s.SetEmotion(rem.ID, "cheerful1")
+
+	// Restart after the timer was due: it fires late, once
+	h.clock = h.clock.Add(15 * time.Minute)
+	h.s = h.open(t)
+	items := h.s.List()
+	if len(items) != 3 || items[0].Label != "tea" || items[1].Label != "stretch" || items[1].Emotion != "cheerful1" {
+		t.Fatalf("loaded %+v", items)
+	}
+	h.s.fireDue()
+	if len(h.fired) != 1 || h.fired[0].Late != 5*time.Minute {
+		t.Fatalf("fired = %+v, want tea 5m late", h.fired)
+	}
+
+	// IDs keep counting after a restart
+	it, _ := h.s.AddTimer(time.Minute, "")
+	if it.ID != "4" {
+		t.Errorf("ID = %q, want 4", it.ID)
+	}
+
+	if info, err := os.Stat(h.path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("schedule file mode = %v, %v, want 0600", info.Mode().Perm(), err)
+	}
+}
+
+func TestScheduler_InMemory(t *testing.T) {
+	s, err := New("")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, err := s.AddTimer(time.Minute, "tea"); err != nil {
+		t.Fatalf("AddTimer: %v", err)
+	}
+	if items := s.List(); len(items) != 1 || items[0].Label != "tea" {
+		t.Errorf("items = %+v, want the tea timer", items)
+	}
+}
+
+func TestScheduler_DropsStale(t *testing.T) {
+	h := newSchedHarness(t)
+	h.s.AddTimer(time.Minute, "old")
+	h.s.AddRecurring("0 11 * * *", "water plants")
+
+	h.clock = h.clock.Add(3 * 24 * time.Hour)
+	h.s = h.open(t)
+	h.s.fireDue()
+	if len(h.fired) != 0 {
+		t.Errorf("fired = %+v, want stale items skipped", h.fired)
+	}
+	items := h.s.List()
+	if len(items) != 1 || !items[0].Due.Equal(time.Date(2026, 10, 17, 11, 0, 0, 0, time.UTC)) {
+		t.Errorf("items = %+v, want the reminder at its next time", items)
+	}
+}
+
+func TestScheduler_Recurring(t *testing.T) {
+	h := newSchedHarness(t)
+	if _, err := h.s.AddRecurring("every day", "x"); err == nil {
+		t.Error("AddRecurring accepted a bad cron")
+	}
+	h.s.AddRecurring("0 */2 * * *", "stand up")
+
+	h.advance(2 * time.Hour)
+	h.advance(2 * time.Hour)
+	if len(h.fired) != 2 {
+		t.Fatalf("fired %d times, want 2", len(h.fired))
+	}
+	if items := h.s.List(); len(items) != 1 || !items[0].Due.Equal(h.clock.Add(2*time.Hour)) {
+		t.Errorf("items = %+v, want rescheduled", items)
+	}
+}
+
+func TestScheduler_Find(t *testing.T) {
+	h := newSchedHarness(t)
+	if _, err := h.s.Find(""); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Find on empty = %v, want ErrNotFound", err)
+	}
+	h.s.AddTimer(5*time.Minute, "pasta")
+	if it, err := h.s.Find(""); err != nil || it.Label != "pasta" {
+		t.Errorf("Find(\"\") = %+v, %v, want the only item", it, err)
+	}
+	h.s.AddTimer(8*time.Minute, "pasta sauce")
+	h.s.AddAlarm(h.clock.Add(time.Hour), "")
+	h.s.AddReminder(h.clock.Add(2*time.Hour), "call mom")
+
+	tests := []struct {
+		ref   string
+		label string
+		err   error
+	}{
+		{"1", "pasta", nil},
+		{"Pasta", "pasta", nil},
+		{"sauce", "pasta sauce", nil},
+		{"pasta sauce timer", "pasta sauce", nil},
+		{"alarm", "", nil},
+		{"mom", "call mom", nil},
+		{"timers", "", ErrAmbiguous},
+		{"", "", ErrAmbiguous},
+		{"laundry", "", ErrNotFound},
+	}
+	for _, tt := range tests {
+		it, err := h.s.Find(tt.ref)
+		if tt.err != nil {
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Find(%q) error = %v, want %v", tt.ref, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || it.Label != tt.label {
+			t.Errorf("Find(%q) = %q, %v, want %q", tt.ref, it.Label, err, tt.label)
+		}
+	}
+}
+
+func TestScheduler_CancelAndSnooze(t *testing.T) {
+	h := newSchedHarness(t)
+	h.s.AddTimer(5*time.Minute, "eggs")
+	h.s.AddAlarm(h.clock.Add(time.Hour), "")
+
+	if _, err := h.s.Cancel("eggs"); err != nil {
+		t.Fatal(err)
+	}
+	it, err := h.s.Snooze("alarm", 10*time.Minute)
+	if err != nil || !it.Due.Equal(h.clock.Add(70*time.Minute)) {
+		t.Errorf("Snooze = %+v, %v", it, err)
+	}
+
+	// Snoozing right after it went off brings it back
+	h.advance(70 * time.Minute)
+	if len(h.fired) != 1 {
+		t.Fatalf("fired = %+v", h.fired)
+	}
+	if it, err = h.s.Snooze("", 5*time.Minute); err != nil || !it.Due.Equal(h.clock.Add(5*time.Minute)) {
+		t.Errorf("Snooze after firing = %+v, %v", it, err)
+	}
+	h.advance(5 * time.Minute)
+	if len(h.fired) != 2 {
+		t.Errorf("snoozed alarm didn't fire again")
+	}
+
+	// Too long after, there's nothing to snooze
+	h.clock = h.clock.Add(SnoozeWindow + time.Minute)
+	if _, err := h.s.Snooze("", time.Minute); !errors.Is(err, ErrNotFound) {
+		t.Errorf("late Snooze error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestScheduler_Run(t *testing.T) {
+	s, err := New(filepath.Join(t.TempDir(), "schedule.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fired := make(chan Firing, 1)
+	s.OnFire = func(f Firing) { fired <- f }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx)
+
+	// Added while Run sleeps: the wake-up picks it up
+	s.AddTimer(20*time.Millisecond, "quick")
+	select {
+	case f := <-fired:
+		if f.Label != "quick" {
+			t.Errorf("fired %q", f.Label)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timer never fired")
+	}
+}
@@ -0,0 +1,327 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Tool represents an AI tool that Eva can use for timers, alarms and
+// reminders.
+type Tool struct {
+	Name        string
+	Description string
+	Parameters  map[string]interface{}
+	Required    []string // Parameters every call must include
+	Handler     func(ctx context.Context, args map[string]interface{}) (string, error)
+}
+
+// ToolsConfig holds dependencies for schedule tools.
+type ToolsConfig struct {
+	Scheduler *Scheduler
+}
+
+// Tools returns the timer, alarm and reminder tools for Eva.
+func Tools(cfg ToolsConfig) []Tool {
+	s := cfg.Scheduler
+
+	emotionParam := map[string]interface{}{
+		"type":        "string",
+		"description": "Optional emotion to play when it goes off (e.g., 'cheerful1', 'surprised1', 'dance1', 'welcoming1')",
+	}
+
+	return []Tool{
+		// ============================================================
+		// set_timer - Countdown timer
+		// ============================================================
+		{
+			Name:        "set_timer",
+			Description: `Set a countdown timer. Use when someone says "set a timer for 10 minutes", "pasta timer", "remind me in 5 minutes". Timers survive restarts and can be listed, cancelled and snoozed.`,
+			Parameters: map[string]interface{}{
+				"duration": map[string]interface{}{
+					"type":        "integer",
+					"description": "Number of seconds, minutes or hours",
+				},
+				"unit": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"seconds", "minutes", "hours"},
+					"description": "Time unit: seconds, minutes or hours",
+				},
+				"label": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional label for the timer like 'pasta' or 'meeting'",
+				},
+				"emotion": emotionParam,
+			},
+			Required: []string{"duration"},
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
+				if s == nil {
+					return "Timers not available", nil
+				}
+				duration := 1
+				if d, ok := args["duration"].(float64); ok {
+					duration = int(d)
+				}
+				unit, _ := args["unit"].(string)
+				label, _ := args["label"].(string)
+
+				d := time.Duration(duration) * unitDuration(unit)
+				it, err := s.AddTimer(d, label)
+				if err != nil {
+					return fmt.Sprintf("Couldn't set the timer: %v", err), nil
+				}
+				setEmotion(s, it, args)
+
+				fmt.Printf("⏱️  Timer set: %s (label: %s)\n", FormatDuration(d), label)
+				return fmt.Sprintf("%s set for %s", capitalize(it.Name()), FormatDuration(d)), nil
+			},
+		},
+
+		// ============================================================
+		// set_alarm - Alarm at a time of day
+		// ============================================================
+		{
+			Name:        "set_alarm",
+			Description: `Set an alarm for a time of day or a date and time. Use when someone says "wake me up at 7", "set an alarm for 6:30 am", "alarm at 3pm tomorrow".`,
+			Parameters: map[string]interface{}{
+				"time": map[string]interface{}{
+					"type":        "string",
+					"description": "When: a time of day like '7:30', '7:30 am' or '19:00' (the next one coming), or 'YYYY-MM-DD HH:MM'",
+				},
+				"label": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional label like 'wake up' or 'leave for the airport'",
+				},
+				"emotion": emotionParam,
+			},
+			Required: []string{"time"},
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
+				if s == nil {
+					return "Alarms not available", nil
+				}
+				when, _ := args["time"].(string)
+				if when == "" {
+					return "What time should the alarm go off?", nil
+				}
+				label, _ := args["label"].(string)
+
+				at, err := ParseTime(when, s.now())
+				if err != nil {
+					return err.Error(), nil
+				}
+				it, err := s.AddAlarm(at, label)
+				if err != nil {
+					return fmt.Sprintf("Couldn't set the alarm: %v", err), nil
+				}
+				setEmotion(s, it, args)
+
+				fmt.Printf("⏰ Alarm set: %s (label: %s)\n", at.Format("Mon 15:04"), label)
+				return fmt.Sprintf("%s set %s", capitalize(it.Name()), formatWhen(at, s.now())), nil
+			},
+		},
+
+		// ============================================================
+		// set_reminder - One-off or recurring reminder
+		// ============================================================
+		{
+			Name:        "set_reminder",
+			Description: `Set a reminder with a message, once at a time or repeating. Use when someone says "remind me to call mom at 6pm", "remind me every weekday at 9 to stand up", "every Monday at 8 remind me about the bins".`,
+			Parameters: map[string]interface{}{
+				"message": map[string]interface{}{
+					"type":        "string",
+					"description": "What to remind about, like 'call mom'",
+				},
+				"time": map[string]interface{}{
+					"type":        "string",
+					"description": "For a one-off reminder: a time of day like '6pm' or 'YYYY-MM-DD HH:MM'",
+				},
+				"repeat": map[string]interface{}{
+					"type":        "string",
+					"description": "For a repeating reminder: a cron expression 'minute hour day month weekday' (e.g. '0 9 * * 1-5' = weekdays at 9:00, '30 7 * * 1' = Mondays at 7:30), or @daily, @weekdays, @weekly, @hourly",
+				},
+				"emotion": emotionParam,
+			},
+			Required: []string{"message"},
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
+				if s == nil {
+					return "Reminders not available", nil
+				}
+				message, _ := args["message"].(string)
+				if message == "" {
+					return "What should I remind you about?", nil
+				}
+				when, _ := args["time"].(string)
+				repeat, _ := args["repeat"].(string)
+
+				var it Item
+				var err error
+				switch {
+				case repeat != "":
+					it, err = s.AddRecurring(repeat, message)
+				case when != "":
+					var at time.Time
+					if at, err = ParseTime(when, s.now()); err != nil {
+						return err.Error(), nil
+					}
+					it, err = s.AddReminder(at, message)
+				default:
+					return "When should I remind you?", nil
+				}
+				if err != nil {
+					return fmt.Sprintf("Couldn't set the reminder: %v", err), nil
+				}
+				setEmotion(s, it, args)
+
+				fmt.Printf("📝 Reminder set: %s (%s)\n", message, it.Due.Format("Mon 15:04"))
+				if it.Recurring() {
+					return fmt.Sprintf("I'll remind you to %s on schedule %s, next %s", message, it.Recur, formatWhen(it.Due, s.now())), nil
+				}
+				return fmt.Sprintf("I'll remind you to %s %s", message, formatWhen(it.Due, s.now())), nil
+			},
+		},
+
+		// ============================================================
+		// list_timers - What's pending
+		// ============================================================
+		{
+			Name:        "list_timers",
+			Description: `List active timers, alarms and reminders with the time left. Use when someone asks "how long is left on my timer?", "what alarms do I have?", "what are my reminders?".`,
+			Parameters:  map[string]interface{}{},
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
+				if s == nil {
+					return "Timers not available", nil
+				}
+				items := s.List()
+				if len(items) == 0 {
+					return "No timers, alarms or reminders are set", nil
+				}
+				now := s.now()
+				lines := make([]string, len(items))
+				for i, it := range items {
+					lines[i] = fmt.Sprintf("%s %s", capitalize(it.Name()), formatWhen(it.Due, now))
+					if it.Recurring() {
+						lines[i] += " (repeats " + it.Recur + ")"
+					}
+				}
+				return strings.Join(lines, "\n"), nil
+			},
+		},
+
+		// ============================================================
+		// cancel_timer - Remove one
+		// ============================================================
+		{
+			Name:        "cancel_timer",
+			Description: `Cancel a timer, alarm or reminder. Use when someone says "cancel the pasta timer", "turn off my alarm", "stop reminding me about the bins".`,
+			Parameters: map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Which one: its label (like 'pasta'), or 'timer' / 'alarm' / 'reminder'. Leave empty if only one is set.",
+				},
+			},
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
+				if s == nil {
+					return "Timers not available", nil
+				}
+				name, _ := args["name"].(string)
+				it, err := s.Cancel(name)
+				if err != nil {
+					return lookupError(err), nil
+				}
+				fmt.Printf("⏱️  Cancelled %s\n", it.Name())
+				return fmt.Sprintf("Cancelled the %s", it.Name()), nil
+			},
+		},
+
+		// ============================================================
+		// snooze_timer - Put one off
+		// ============================================================
+		{
+			Name:        "snooze_timer",
+			Description: `Snooze a timer, alarm or reminder, including one that just went off. Use when someone says "snooze", "five more minutes", "remind me again in 10 minutes".`,
+			Parameters: map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Which one: its label, or 'timer' / 'alarm' / 'reminder'. Leave empty for the one that just went off.",
+				},
+				"minutes": map[string]interface{}{
+					"type":        "integer",
+					"description": "How many minutes to snooze (default 5)",
+				},
+			},
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
+				if s == nil {
+					return "Timers not available", nil
+				}
+				name, _ := args["name"].(string)
+				minutes := 5
+				if m, ok := args["minutes"].(float64); ok && m > 0 {
+					minutes = int(m)
+				}
+				d := time.Duration(minutes) * time.Minute
+
+				it, err := s.Snooze(name, d)
+				if err != nil {
+					return lookupError(err), nil
+				}
+				fmt.Printf("😴 Snoozed %s until %s\n", it.Name(), it.Due.Format("15:04"))
+				return fmt.Sprintf("Snoozed the %s, it'll go off %s", it.Name(), formatWhen(it.Due, s.now())), nil
+			},
+		},
+	}
+}
+
+// unitDuration returns the length of one unit ("minutes" by default).
+func unitDuration(unit string) time.Duration {
+	switch strings.TrimSuffix(strings.ToLower(unit), "s") {
+	case "second", "sec":
+		return time.Second
+	case "hour", "hr":
+		return time.Hour
+	}
+	return time.Minute
+}
+
+// setEmotion attaches the requested emotion to a new item.
+func setEmotion(s *Scheduler, it Item, args map[string]interface{}) {
+	if emotion, _ := args["emotion"].(string); emotion != "" {
+		if err := s.SetEmotion(it.ID, emotion); err != nil {
+			fmt.Printf("⏱️  Failed to set emotion: %v\n", err)
+		}
+	}
+}
+
+// formatWhen describes when t is relative to now: "in 5 minutes",
+// "at 7:30 AM", "on Tuesday at 9:00 AM".
+func formatWhen(t, now time.Time) string {
+	d := t.Sub(now)
+	switch {
+	case d < time.Hour:
+		return "in " + FormatDuration(max(d, 0))
+	case t.YearDay() == now.YearDay() && t.Year() == now.Year():
+		return t.Format("at 3:04 PM")
+	case d < 7*24*time.Hour:
+		return t.Format("on Monday at 3:04 PM")
+	}
+	return t.Format("on January 2 at 3:04 PM")
+}
+
+// lookupError turns a Find error into a reply.
+func lookupError(err error) string {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return "I couldn't find that timer, alarm or reminder"
+	case errors.Is(err, ErrAmbiguous):
+		return fmt.Sprintf("Which one? %v", strings.TrimPrefix(err.Error(), ErrAmbiguous.Error()+": "))
+	}
+	return err.Error()
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,89 @@
+package schedule
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func findTool(t *testing.T, tools []Tool, name string) Tool {
+	t.Helper()
+	for _, tool := range tools {
+		if tool.Name == name {
+			return tool
+		}
+	}
+	t.Fatalf("no %s tool", name)
+	return Tool{}
+}
+
+func TestTools(t *testing.T) {
+	h := newSchedHarness(t)
+	tools := Tools(ToolsConfig{Scheduler: h.s})
+
+	call := func(name string, args map[string]interface{}) string {
+		t.Helper()
+		result, err := findTool(t, tools, name).Handler(t.Context(), args)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		return result
+	}
+
+	tests := []struct {
+		tool string
+		args map[string]interface{}
+		want string
+	}{
+		{"list_timers", nil, "No timers"},
+		{"set_timer", map[string]interface{}{"duration": 10.0, "unit": "minutes", "label": "pasta", "emotion": "cheerful1"}, "Pasta timer set for 10 minutes"},
+		{"set_timer", map[string]interface{}{"duration": 90.0, "unit": "seconds"}, "Timer set for 1 minute 30 seconds"},
+		{"set_alarm", map[string]interface{}{"time": "7:30 am", "label": "wake up"}, "Wake up alarm set on Thursday at 7:30 AM"},
+		{"set_alarm", map[string]interface{}{"time": "whenever"}, "can't read"},
+		{"set_reminder", map[string]interface{}{"message": "call mom", "time": "6pm"}, "I'll remind you to call mom at 6:00 PM"},
+		{"set_reminder", map[string]interface{}{"message": "stretch", "repeat": "@weekdays"}, "on schedule @weekdays, next on Thursday at 9:00 AM"},
+		{"set_reminder", map[string]interface{}{"message": "stretch"}, "When should I"},
+		{"cancel_timer", map[string]interface{}{"name": "timers"}, "Which one? timer, pasta timer"},
+		{"snooze_timer", map[string]interface{}{"name": "pasta", "minutes": 5.0}, "it'll go off in 15 minutes"},
+		{"cancel_timer", map[string]interface{}{"name": "mom"}, "Cancelled the reminder to call mom"},
+		{"cancel_timer", map[string]interface{}{"name": "laundry"}, "couldn't find"},
+	}
+	for _, tt := range tests {
+		if got := call(tt.tool, tt.args); !strings.Contains(got, tt.want) {
+			t.Errorf("%s(%v) = %q, want it to contain %q", tt.tool, tt.args, got, tt.want)
+		}
+	}
+
+	items := h.s.List()
+	if len(items) != 4 {
+		t.Fatalf("%d items, want 4", len(items))
+	}
+	if items[1].Label != "pasta" || items[1].Emotion != "cheerful1" {
+		t.Errorf("pasta timer = %+v", items[1])
+	}
+
+	list := call("list_timers", nil)
+	if !strings.Contains(list, "Timer in 1 minute 30 seconds\nPasta timer in 15 minutes") {
+		t.Errorf("list_timers = %q", list)
+	}
+	if !strings.Contains(list, "(repeats @weekdays)") {
+		t.Errorf("list_timers = %q, want the repeat shown", list)
+	}
+}
+
+func TestTools_NoScheduler(t *testing.T) {
+	for _, tool := range Tools(ToolsConfig{}) {
+		result, err := tool.Handler(t.Context(), map[string]interface{}{"duration": 1.0, "time": "7:00", "message": "x"})
+		if err != nil || !strings.Contains(result, "not available") {
+			t.Errorf("%s = %q, %v, want not available", tool.Name, result, err)
+		}
+	}
+}
+
+func TestUnitDuration(t *testing.T) {
+	for unit, want := range map[string]time.Duration{"seconds": time.Second, "Hours": time.Hour, "minutes": time.Minute, "": time.Minute} {
+		if got := unitDuration(unit); got != want {
+			t.Errorf("unitDuration(%q) = %v, want %v", unit, got, want)
+		}
+	}
+}
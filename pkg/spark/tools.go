@@ -1,6 +1,7 @@
 package spark
 
 import (
+	"context"
 	"fmt"
 	"strings"
 )
@@ -10,7 +11,8 @@ type Tool struct {
 	Name        string
 	Description string
 	Parameters  map[string]interface{}
-	Handler     func(args map[string]interface{}) (string, error)
+	Required    []string // Parameters every call must include
+	Handler     func(ctx context.Context, args map[string]interface{}) (string, error)
 }
 
 // ToolsConfig holds dependencies for Spark tools.
@@ -35,7 +37,7 @@ func Tools(cfg ToolsConfig) []Tool {
 					"description": "The idea or inspiration to capture",
 				},
 			},
-			Handler: func(args map[string]interface{}) (string, error) {
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
 				content, _ := args["content"].(string)
 				if content == "" {
 					return "I need to know what spark of inspiration you want to save!", nil
@@ -99,7 +101,7 @@ func Tools(cfg ToolsConfig) []Tool {
 					"description": "The additional context or inspiration to add",
 				},
 			},
-			Handler: func(args map[string]interface{}) (string, error) {
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
 				sparkID, _ := args["spark"].(string)
 				context, _ := args["context"].(string)
 
@@ -150,7 +152,7 @@ func Tools(cfg ToolsConfig) []Tool {
 					"description": "The new title for the spark",
 				},
 			},
-			Handler: func(args map[string]interface{}) (string, error) {
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
 				sparkID, _ := args["spark"].(string)
 				newTitle, _ := args["new_title"].(string)
 
@@ -192,7 +194,7 @@ func Tools(cfg ToolsConfig) []Tool {
 			Name: "list_sparks",
 			Description: `List all saved sparks of inspiration. Use when someone asks "what sparks do I have?", "show my ideas", "list sparks", or wants to see all their captured ideas.`,
 			Parameters: map[string]interface{}{},
-			Handler: func(args map[string]interface{}) (string, error) {
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
 				if cfg.Store == nil {
 					return "Spark storage not available", nil
 				}
@@ -236,7 +238,7 @@ func Tools(cfg ToolsConfig) []Tool {
 					"description": "The spark title or keyword to view",
 				},
 			},
-			Handler: func(args map[string]interface{}) (string, error) {
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
 				sparkID, _ := args["spark"].(string)
 				if sparkID == "" {
 					return "Which spark do you want to view?", nil
@@ -302,7 +304,7 @@ func Tools(cfg ToolsConfig) []Tool {
 					"description": "The spark title or keyword to delete",
 				},
 			},
-			Handler: func(args map[string]interface{}) (string, error) {
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
 				sparkID, _ := args["spark"].(string)
 				if sparkID == "" {
 					return "Which spark do you want to delete?", nil
@@ -343,7 +345,7 @@ func Tools(cfg ToolsConfig) []Tool {
 					"description": "The search query or keyword",
 				},
 			},
-			Handler: func(args map[string]interface{}) (string, error) {
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
 				query, _ := args["query"].(string)
 				if query == "" {
 					return "What do you want to search for?", nil
@@ -383,7 +385,7 @@ func Tools(cfg ToolsConfig) []Tool {
 					"description": "The spark title or keyword to sync",
 				},
 			},
-			Handler: func(args map[string]interface{}) (string, error) {
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
 				sparkID, _ := args["spark"].(string)
 				if sparkID == "" {
 					return "Which spark do you want to sync to Google Docs?", nil
@@ -433,7 +435,7 @@ func Tools(cfg ToolsConfig) []Tool {
 			Name: "google_status",
 			Description: `Check if Google Docs is connected. Use when someone asks "is Google connected?", "am I connected to Google?", or wants to know the sync status.`,
 			Parameters:  map[string]interface{}{},
-			Handler: func(args map[string]interface{}) (string, error) {
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
 				if cfg.GoogleDocs == nil {
 					return "Google Docs is not configured. Please set GOOGLE_CLIENT_ID and GOOGLE_CLIENT_SECRET environment variables.", nil
 				}
@@ -458,7 +460,7 @@ func Tools(cfg ToolsConfig) []Tool {
 					"description": "The spark title or keyword to generate a plan for",
 				},
 			},
-			Handler: func(args map[string]interface{}) (string, error) {
+			Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
 				sparkID, _ := args["spark"].(string)
 				if sparkID == "" {
 					return "Which spark do you want me to create a plan for?", nil
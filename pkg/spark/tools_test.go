@@ -47,7 +47,7 @@ func TestSaveSparkTool(t *testing.T) {
 	}
 
 	// Test saving a spark
-	result, err := saveTool.Handler(map[string]interface{}{
+	result, err := saveTool.Handler(t.Context(), map[string]interface{}{
 		"content": "Build a robot that waters plants based on soil moisture",
 	})
 	if err != nil {
@@ -64,7 +64,7 @@ func TestSaveSparkTool(t *testing.T) {
 	}
 
 	// Test empty content
-	result, _ = saveTool.Handler(map[string]interface{}{
+	result, _ = saveTool.Handler(t.Context(), map[string]interface{}{
 		"content": "",
 	})
 	if !strings.Contains(result, "need to know") {
@@ -103,7 +103,7 @@ func TestSaveSparkWithGeminiClient(t *testing.T) {
 		}
 	}
 
-	result, _ := saveTool.Handler(map[string]interface{}{
+	result, _ := saveTool.Handler(t.Context(), map[string]interface{}{
 		"content": content,
 	})
 
@@ -145,7 +145,7 @@ func TestAddContextTool(t *testing.T) {
 	}
 
 	// Add context by title
-	result, err := addContextTool.Handler(map[string]interface{}{
+	result, err := addContextTool.Handler(t.Context(), map[string]interface{}{
 		"spark":   "Plant",
 		"context": "Could use Arduino with capacitive sensors",
 	})
@@ -164,7 +164,7 @@ func TestAddContextTool(t *testing.T) {
 	}
 
 	// Test not found
-	result, _ = addContextTool.Handler(map[string]interface{}{
+	result, _ = addContextTool.Handler(t.Context(), map[string]interface{}{
 		"spark":   "NonExistent",
 		"context": "Some context",
 	})
@@ -192,7 +192,7 @@ func TestUpdateTitleTool(t *testing.T) {
 		}
 	}
 
-	result, err := updateTitleTool.Handler(map[string]interface{}{
+	result, err := updateTitleTool.Handler(t.Context(), map[string]interface{}{
 		"spark":     "Old",
 		"new_title": "New Awesome Title",
 	})
@@ -227,7 +227,7 @@ func TestListSparksTool(t *testing.T) {
 	}
 
 	// Empty list
-	result, _ := listTool.Handler(map[string]interface{}{})
+	result, _ := listTool.Handler(t.Context(), map[string]interface{}{})
 	if !strings.Contains(result, "don't have any sparks") {
 		t.Errorf("expected empty message, got: %s", result)
 	}
@@ -242,7 +242,7 @@ func TestListSparksTool(t *testing.T) {
 	spark2.AddContext("Some context", SourceVoice)
 	store.Save(spark2)
 
-	result, _ = listTool.Handler(map[string]interface{}{})
+	result, _ = listTool.Handler(t.Context(), map[string]interface{}{})
 	if !strings.Contains(result, "2 sparks") {
 		t.Errorf("expected 2 sparks message, got: %s", result)
 	}
@@ -276,7 +276,7 @@ func TestViewSparkTool(t *testing.T) {
 		}
 	}
 
-	result, err := viewTool.Handler(map[string]interface{}{
+	result, err := viewTool.Handler(t.Context(), map[string]interface{}{
 		"spark": "Plant",
 	})
 	if err != nil {
@@ -323,7 +323,7 @@ func TestDeleteSparkTool(t *testing.T) {
 		t.Fatalf("expected 1 spark before delete")
 	}
 
-	result, err := deleteTool.Handler(map[string]interface{}{
+	result, err := deleteTool.Handler(t.Context(), map[string]interface{}{
 		"spark": "Delete",
 	})
 	if err != nil {
@@ -370,7 +370,7 @@ func TestSearchSparksTool(t *testing.T) {
 	}
 
 	// Search by content
-	result, _ := searchTool.Handler(map[string]interface{}{
+	result, _ := searchTool.Handler(t.Context(), map[string]interface{}{
 		"query": "robot",
 	})
 	if !strings.Contains(result, "2 sparks") {
@@ -378,7 +378,7 @@ func TestSearchSparksTool(t *testing.T) {
 	}
 
 	// Search by tag
-	result, _ = searchTool.Handler(map[string]interface{}{
+	result, _ = searchTool.Handler(t.Context(), map[string]interface{}{
 		"query": "gardening",
 	})
 	if !strings.Contains(result, "1 spark") {
@@ -386,7 +386,7 @@ func TestSearchSparksTool(t *testing.T) {
 	}
 
 	// No results
-	result, _ = searchTool.Handler(map[string]interface{}{
+	result, _ = searchTool.Handler(t.Context(), map[string]interface{}{
 		"query": "blockchain",
 	})
 	if !strings.Contains(result, "No sparks found") {
@@ -503,7 +503,7 @@ func TestSyncSparkToolNoGoogleDocs(t *testing.T) {
 		}
 	}
 
-	result, _ := syncTool.Handler(map[string]interface{}{
+	result, _ := syncTool.Handler(t.Context(), map[string]interface{}{
 		"spark": "Sync Test",
 	})
 
@@ -530,7 +530,7 @@ func TestGoogleStatusToolNoGoogleDocs(t *testing.T) {
 		}
 	}
 
-	result, _ := statusTool.Handler(map[string]interface{}{})
+	result, _ := statusTool.Handler(t.Context(), map[string]interface{}{})
 
 	if !strings.Contains(result, "not configured") {
 		t.Errorf("expected 'not configured' message, got: %s", result)
@@ -556,7 +556,7 @@ func TestSyncSparkToolMissingSpark(t *testing.T) {
 	}
 
 	// Test with empty spark
-	result, _ := syncTool.Handler(map[string]interface{}{
+	result, _ := syncTool.Handler(t.Context(), map[string]interface{}{
 		"spark": "",
 	})
 
@@ -588,7 +588,7 @@ func TestGeneratePlanToolNoGemini(t *testing.T) {
 		}
 	}
 
-	result, _ := planTool.Handler(map[string]interface{}{
+	result, _ := planTool.Handler(t.Context(), map[string]interface{}{
 		"spark": "Plan Test",
 	})
 
@@ -616,7 +616,7 @@ func TestGeneratePlanToolMissingSpark(t *testing.T) {
 	}
 
 	// Test with empty spark
-	result, _ := planTool.Handler(map[string]interface{}{
+	result, _ := planTool.Handler(t.Context(), map[string]interface{}{
 		"spark": "",
 	})
 
@@ -631,7 +631,7 @@ func TestToolsWithNilStore(t *testing.T) {
 
 	// All tools should handle nil store gracefully
 	for _, tool := range tools {
-		result, _ := tool.Handler(map[string]interface{}{
+		result, _ := tool.Handler(t.Context(), map[string]interface{}{
 			"content":   "test",
 			"spark":     "test",
 			"context":   "test",
@@ -670,7 +670,7 @@ func TestViewSparkWithPlan(t *testing.T) {
 		}
 	}
 
-	result, _ := viewTool.Handler(map[string]interface{}{
+	result, _ := viewTool.Handler(t.Context(), map[string]interface{}{
 		"spark": "Cool",
 	})
 
@@ -698,7 +698,7 @@ func TestEmptyParameterHandling(t *testing.T) {
 		}
 	}
 
-	result, _ := addContextTool.Handler(map[string]interface{}{
+	result, _ := addContextTool.Handler(t.Context(), map[string]interface{}{
 		"spark":   "",
 		"context": "test",
 	})
@@ -706,7 +706,7 @@ func TestEmptyParameterHandling(t *testing.T) {
 		t.Errorf("expected prompt for spark, got: %s", result)
 	}
 
-	result, _ = addContextTool.Handler(map[string]interface{}{
+	result, _ = addContextTool.Handler(t.Context(), map[string]interface{}{
 		"spark":   "test",
 		"context": "",
 	})
@@ -723,7 +723,7 @@ func TestEmptyParameterHandling(t *testing.T) {
 		}
 	}
 
-	result, _ = updateTitleTool.Handler(map[string]interface{}{
+	result, _ = updateTitleTool.Handler(t.Context(), map[string]interface{}{
 		"spark":     "",
 		"new_title": "test",
 	})
@@ -731,7 +731,7 @@ func TestEmptyParameterHandling(t *testing.T) {
 		t.Errorf("expected prompt for spark, got: %s", result)
 	}
 
-	result, _ = updateTitleTool.Handler(map[string]interface{}{
+	result, _ = updateTitleTool.Handler(t.Context(), map[string]interface{}{
 		"spark":     "test",
 		"new_title": "",
 	})
@@ -748,7 +748,7 @@ func TestEmptyParameterHandling(t *testing.T) {
 		}
 	}
 
-	result, _ = viewTool.Handler(map[string]interface{}{
+	result, _ = viewTool.Handler(t.Context(), map[string]interface{}{
 		"spark": "",
 	})
 	if !strings.Contains(result, "Which spark") {
@@ -764,7 +764,7 @@ func TestEmptyParameterHandling(t *testing.T) {
 		}
 	}
 
-	result, _ = deleteTool.Handler(map[string]interface{}{
+	result, _ = deleteTool.Handler(t.Context(), map[string]interface{}{
 		"spark": "",
 	})
 	if !strings.Contains(result, "Which spark") {
@@ -780,7 +780,7 @@ func TestEmptyParameterHandling(t *testing.T) {
 		}
 	}
 
-	result, _ = searchTool.Handler(map[string]interface{}{
+	result, _ = searchTool.Handler(t.Context(), map[string]interface{}{
 		"query": "",
 	})
 	if !strings.Contains(result, "What do you want to search") {
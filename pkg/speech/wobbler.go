@@ -2,8 +2,11 @@
 package speech
 
 import (
+	"log"
 	"math"
 	"sync"
+
+	"github.com/teslashibe/go-reachy/pkg/audio/dsp"
 )
 
 // Tunable parameters (ported from Python speech_tapper.py)
@@ -67,6 +70,11 @@ type Wobbler struct {
 	// Audio buffer
 	samples []float64
 
+	// Converts input to SampleRate; kept across calls so chunk
+	// boundaries don't click
+	resampler *dsp.Resampler
+	badRate   int // Rate the resampler refused; falls back to linear
+
 	// VAD state
 	vadOn    bool
 	vadAbove int
@@ -104,6 +112,9 @@ func (w *Wobbler) Reset() {
 	defer w.mu.Unlock()
 
 	w.samples = w.samples[:0]
+	if w.resampler != nil {
+		w.resampler.Reset()
+	}
 	w.vadOn = false
 	w.vadAbove = 0
 	w.vadBelow = 0
@@ -124,14 +135,19 @@ func (w *Wobbler) Feed(samples []int16, sampleRate int) {
 		return
 	}
 
+	r := w.resamplerFor(sampleRate)
+	if r != nil {
+		samples = r.Process(samples)
+	}
+
 	// Convert int16 to float64 [-1, 1]
 	floats := make([]float64, len(samples))
 	for i, s := range samples {
 		floats[i] = float64(s) / 32768.0
 	}
 
-	// Simple resampling if needed (linear interpolation)
-	if sampleRate != SampleRate {
+	// Simple resampling if the polyphase resampler can't handle the rate
+	if r == nil && sampleRate != SampleRate {
 		floats = resampleLinear(floats, sampleRate, SampleRate)
 	}
 
@@ -153,14 +169,19 @@ func (w *Wobbler) FeedFloat32(samples []float32, sampleRate int) {
 		return
 	}
 
+	r := w.resamplerFor(sampleRate)
+	if r != nil {
+		samples = r.ProcessFloat32(samples)
+	}
+
 	// Convert float32 to float64
 	floats := make([]float64, len(samples))
 	for i, s := range samples {
 		floats[i] = float64(s)
 	}
 
-	// Simple resampling if needed
-	if sampleRate != SampleRate {
+	// Simple resampling if the polyphase resampler can't handle the rate
+	if r == nil && sampleRate != SampleRate {
 		floats = resampleLinear(floats, sampleRate, SampleRate)
 	}
 
@@ -276,10 +297,34 @@ func loudnessGain(db float64) float64 {
 	return t
 }
 
+// resamplerFor returns a resampler from rate to SampleRate, or nil when
+// none is needed or the rate can't be resampled that way (callers then
+// fall back to resampleLinear). A refused rate is logged once.
+func (w *Wobbler) resamplerFor(rate int) *dsp.Resampler {
+	if rate == SampleRate {
+		return nil
+	}
+	if w.resampler == nil || w.resampler.InRate() != rate {
+		r, err := dsp.NewResampler(rate, SampleRate, dsp.QualityFast)
+		if err != nil {
+			if w.badRate != rate {
+				log.Printf("Speech wobbler: %v; using linear resampling for %d Hz", err, rate)
+				w.badRate = rate
+			}
+			return nil
+		}
+		w.resampler = r
+	}
+	return w.resampler
+}
+
 func resampleLinear(samples []float64, srIn, srOut int) []float64 {
 	if srIn == srOut || len(samples) == 0 {
 		return samples
 	}
+	if srIn <= 0 {
+		return nil
+	}
 	nOut := int(math.Round(float64(len(samples)) * float64(srOut) / float64(srIn)))
 	if nOut <= 1 {
 		return nil
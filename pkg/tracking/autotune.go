@@ -0,0 +1,659 @@
+package tracking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Axis selects which head axis is being tuned
+type Axis string
+
+const (
+	AxisYaw   Axis = "yaw"
+	AxisPitch Axis = "pitch"
+)
+
+// Plant is the system under test during auto-tuning: something that can be
+// commanded to a head angle and report how far off-centre the target is.
+// SimulatedPlant models the head and detector; VisualPlant drives the real
+// head against a face held still in front of the camera.
+type Plant interface {
+	// Reset returns the plant to its starting position before a trial.
+	// It must give up when ctx is done.
+	Reset(ctx context.Context, axis Axis) error
+
+	// Command moves the head to the given angle on the axis (radians)
+	Command(axis Axis, angle float64) error
+
+	// Measure returns the camera-relative offset to a virtual target placed
+	// at the reference angle, exactly as the tracker would receive it.
+	// ok is false when no measurement is available this tick.
+	Measure(axis Axis, reference float64) (offset float64, ok bool)
+
+	// RealTime reports whether the trial must run at wall-clock speed
+	RealTime() bool
+}
+
+// AutoTuneConfig controls an auto-tuning run
+type AutoTuneConfig struct {
+	Axis Axis
+
+	// Candidate gains (grid search over every Kp × Kd pair)
+	KpCandidates []float64
+	KdCandidates []float64
+
+	// Step profile: 0 → +StepAmplitude → 0, holding each for StepHold
+	StepAmplitude float64
+	StepHold      time.Duration
+
+	// Sinusoidal profile
+	SineAmplitude float64       // radians
+	SineFrequency float64       // Hz
+	SineDuration  time.Duration // total duration (0 = skip sine)
+
+	// Control period (normally Config.MovementInterval)
+	Tick time.Duration
+
+	// Error band for settling (radians, 0 = 1.25 × dead zone / response scale)
+	SettleBand float64
+
+	// Score weights (lower score is better)
+	SettlingWeight  float64 // per second of settling time
+	OvershootWeight float64 // per unit overshoot fraction
+	JitterWeight    float64 // per radian RMS of command chatter
+	TrackingWeight  float64 // per radian RMS sine tracking error
+
+	// OnTrial is called after each trial (optional, for progress reporting)
+	OnTrial func(done, total int, trial TrialResult)
+}
+
+// DefaultAutoTuneConfig returns a grid around the production defaults.
+// A full yaw run is 25 trials of ~16 s each on hardware.
+func DefaultAutoTuneConfig(axis Axis) AutoTuneConfig {
+	cfg := AutoTuneConfig{
+		Axis:            axis,
+		StepHold:        3 * time.Second,
+		SineFrequency:   0.2,
+		SineDuration:    10 * time.Second,
+		Tick:            50 * time.Millisecond,
+		SettlingWeight:  1.0,
+		OvershootWeight: 4.0,
+		JitterWeight:    200.0,
+		TrackingWeight:  10.0,
+	}
+	if axis == AxisPitch {
+		cfg.KpCandidates = []float64{0.02, 0.04, 0.06, 0.08}
+		cfg.KdCandidates = []float64{0.08, 0.12, 0.15, 0.20}
+		cfg.StepAmplitude = 0.35
+		cfg.SineAmplitude = 0.20
+	} else {
+		cfg.KpCandidates = []float64{0.05, 0.08, 0.10, 0.15, 0.20}
+		cfg.KdCandidates = []float64{0.04, 0.08, 0.12, 0.16, 0.20}
+		cfg.StepAmplitude = 0.50
+		cfg.SineAmplitude = 0.30
+	}
+	return cfg
+}
+
+// ResponseMetrics summarises how the controller followed a profile
+type ResponseMetrics struct {
+	Overshoot    float64       `json:"overshoot"`     // Peak overshoot as a fraction of the step (0.1 = 10%)
+	SettlingTime time.Duration `json:"settling_time"` // Time until error stays inside the band (worst step)
+	Settled      bool          `json:"settled"`       // False if any step never settled
+	Jitter       float64       `json:"jitter"`        // RMS command change per tick once settled (radians)
+	TrackingRMS  float64       `json:"tracking_rms"`  // RMS error following the sine (radians)
+}
+
+// TrialResult is the outcome of one candidate gain pair
+type TrialResult struct {
+	Kp    float64         `json:"kp"`
+	Kd    float64         `json:"kd"`
+	Step  ResponseMetrics `json:"step"`
+	Sine  ResponseMetrics `json:"sine"`
+	Score float64         `json:"score"`
+}
+
+// AutoTuneResult is the outcome of a full auto-tuning run
+type AutoTuneResult struct {
+	Axis     Axis          `json:"axis"`
+	Baseline TrialResult   `json:"baseline"` // Current gains, for comparison
+	Best     TrialResult   `json:"best"`     // Proposed gains
+	Trials   []TrialResult `json:"trials"`
+	Applied  bool          `json:"applied"`
+}
+
+// AutoTune grid-searches Kp/Kd for one axis against a plant.
+// base supplies every non-gain parameter (dead zones, limits, response scale)
+// and the baseline gains that the proposal is compared against.
+func AutoTune(ctx context.Context, plant Plant, cfg AutoTuneConfig, base Config) (*AutoTuneResult, error) {
+	if plant == nil {
+		return nil, errors.New("autotune: plant is required")
+	}
+	if len(cfg.KpCandidates) == 0 || len(cfg.KdCandidates) == 0 {
+		return nil, errors.New("autotune: no gain candidates")
+	}
+	if cfg.Tick <= 0 {
+		cfg.Tick = base.MovementInterval
+	}
+	if cfg.Tick <= 0 {
+		return nil, errors.New("autotune: tick must be positive")
+	}
+
+	baseKp, baseKd := base.Kp, base.Kd
+	if cfg.Axis == AxisPitch {
+		baseKp, baseKd = base.EffectiveKpPitch(), base.EffectiveKdPitch()
+	}
+
+	result := &AutoTuneResult{Axis: cfg.Axis}
+	total := len(cfg.KpCandidates)*len(cfg.KdCandidates) + 1
+
+	baseline, err := runTrial(ctx, plant, cfg, base, baseKp, baseKd)
+	if err != nil {
+		return nil, err
+	}
+	result.Baseline = baseline
+	result.Best = baseline
+	if cfg.OnTrial != nil {
+		cfg.OnTrial(1, total, baseline)
+	}
+
+	done := 1
+	for _, kp := range cfg.KpCandidates {
+		for _, kd := range cfg.KdCandidates {
+			trial, err := runTrial(ctx, plant, cfg, base, kp, kd)
+			if err != nil {
+				return result, err
+			}
+			result.Trials = append(result.Trials, trial)
+			if trial.Score < result.Best.Score {
+				result.Best = trial
+			}
+			done++
+			if cfg.OnTrial != nil {
+				cfg.OnTrial(done, total, trial)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// runTrial drives one gain pair through the step and sine profiles
+func runTrial(ctx context.Context, plant Plant, cfg AutoTuneConfig, base Config, kp, kd float64) (TrialResult, error) {
+	trial := TrialResult{Kp: kp, Kd: kd}
+
+	ctrl := NewPDController(base)
+	deadZone := ctrl.DeadZone
+	if cfg.Axis == AxisPitch {
+		ctrl.KpPitch, ctrl.KdPitch = kp, kd
+		deadZone = ctrl.PitchDeadZone
+	} else {
+		ctrl.Kp, ctrl.Kd = kp, kd
+	}
+
+	scale := base.ResponseScale
+	if scale <= 0 {
+		scale = 1.0
+	}
+
+	// The loop stops correcting once the scaled offset falls inside the dead
+	// zone, so the natural settling band is the dead zone seen through the scale
+	band := cfg.SettleBand
+	if band <= 0 {
+		band = 1.25 * deadZone / scale
+	}
+
+	if err := plant.Reset(ctx, cfg.Axis); err != nil {
+		return trial, fmt.Errorf("autotune: reset: %w", err)
+	}
+
+	// Step profile: two steps (out and back) so both directions are measured
+	holdTicks := int(cfg.StepHold / cfg.Tick)
+	steps := []float64{cfg.StepAmplitude, 0}
+	var stepTraces [][]sample
+	var lastOffset float64
+	for _, ref := range steps {
+		trace := make([]sample, 0, holdTicks)
+		for i := 0; i < holdTicks; i++ {
+			s, err := tick(ctx, plant, cfg, ctrl, ref, scale, &lastOffset)
+			if err != nil {
+				return trial, err
+			}
+			trace = append(trace, s)
+		}
+		stepTraces = append(stepTraces, trace)
+	}
+	trial.Step = stepMetrics(stepTraces, cfg.StepAmplitude, band, cfg.Tick)
+
+	// Sine profile (tracking a moving target)
+	if cfg.SineDuration > 0 && cfg.SineFrequency > 0 {
+		sineTicks := int(cfg.SineDuration / cfg.Tick)
+		trace := make([]sample, 0, sineTicks)
+		for i := 0; i < sineTicks; i++ {
+			t := float64(i) * cfg.Tick.Seconds()
+			ref := cfg.SineAmplitude * math.Sin(2*math.Pi*cfg.SineFrequency*t)
+			s, err := tick(ctx, plant, cfg, ctrl, ref, scale, &lastOffset)
+			if err != nil {
+				return trial, err
+			}
+			trace = append(trace, s)
+		}
+		// Skip the first period while the controller catches up
+		skip := int(1 / cfg.SineFrequency / cfg.Tick.Seconds())
+		trial.Sine = sineMetrics(trace, skip)
+	}
+
+	trial.Score = cfg.SettlingWeight*trial.Step.SettlingTime.Seconds() +
+		cfg.OvershootWeight*trial.Step.Overshoot +
+		cfg.JitterWeight*trial.Step.Jitter +
+		cfg.TrackingWeight*trial.Sine.TrackingRMS
+	if !trial.Step.Settled {
+		trial.Score += 5 // Heavy penalty for never settling
+	}
+
+	return trial, nil
+}
+
+// sample is one control tick of a trial
+type sample struct {
+	err      float64 // Measured offset (target - head)
+	measured bool    // Whether err is a fresh measurement
+	command  float64 // Commanded angle
+}
+
+// tick runs one control cycle the same way Tracker.updateMovement does:
+// the latest offset is held between detections and fed through the PD loop.
+func tick(ctx context.Context, plant Plant, cfg AutoTuneConfig, ctrl *PDController, ref, scale float64, lastOffset *float64) (sample, error) {
+	select {
+	case <-ctx.Done():
+		return sample{}, ctx.Err()
+	default:
+	}
+
+	offset, ok := plant.Measure(cfg.Axis, ref)
+	if ok {
+		*lastOffset = offset
+	}
+
+	var cmd float64
+	if cfg.Axis == AxisPitch {
+		ctrl.SetTargetPitchFromOffset(*lastOffset * scale)
+		cmd, _ = ctrl.UpdatePitch()
+	} else {
+		ctrl.SetTargetFromOffset(*lastOffset * scale)
+		cmd, _ = ctrl.Update()
+	}
+
+	if err := plant.Command(cfg.Axis, cmd); err != nil {
+		return sample{}, fmt.Errorf("autotune: command: %w", err)
+	}
+
+	if plant.RealTime() {
+		if err := sleepCtx(ctx, cfg.Tick); err != nil {
+			return sample{}, err
+		}
+	}
+
+	return sample{err: offset, measured: ok, command: cmd}, nil
+}
+
+// sleepCtx sleeps for d, or until ctx is done
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// stepMetrics computes overshoot, settling time and jitter over step segments.
+// Each segment starts at a step change of size amplitude.
+func stepMetrics(segments [][]sample, amplitude, band float64, tickDur time.Duration) ResponseMetrics {
+	m := ResponseMetrics{Settled: true}
+	if amplitude == 0 {
+		return m
+	}
+
+	var jitterSum float64
+	var jitterN int
+	for _, seg := range segments {
+		// Initial error sign tells us which way the head must move;
+		// overshoot is error of the opposite sign
+		sign := 0.0
+		lastOutside := -1
+		for i, s := range seg {
+			if !s.measured {
+				continue
+			}
+			if sign == 0 && math.Abs(s.err) > band {
+				sign = math.Copysign(1, s.err)
+			}
+			if sign != 0 && -s.err*sign > 0 {
+				if os := -s.err * sign / math.Abs(amplitude); os > m.Overshoot {
+					m.Overshoot = os
+				}
+			}
+			if math.Abs(s.err) > band {
+				lastOutside = i
+			}
+		}
+
+		settleIdx := lastOutside + 1
+		if settleIdx >= len(seg) {
+			m.Settled = false
+		}
+		if settling := time.Duration(settleIdx) * tickDur; settling > m.SettlingTime {
+			m.SettlingTime = settling
+		}
+
+		// Jitter: command chatter in the second half of the hold
+		for i := len(seg)/2 + 1; i < len(seg); i++ {
+			d := seg[i].command - seg[i-1].command
+			jitterSum += d * d
+			jitterN++
+		}
+	}
+
+	if jitterN > 0 {
+		m.Jitter = math.Sqrt(jitterSum / float64(jitterN))
+	}
+	return m
+}
+
+// sineMetrics computes tracking RMS error and jitter after skipping warm-up
+func sineMetrics(trace []sample, skip int) ResponseMetrics {
+	m := ResponseMetrics{Settled: true}
+	var sum float64
+	var n int
+	for i := skip; i < len(trace); i++ {
+		if !trace[i].measured {
+			continue
+		}
+		sum += trace[i].err * trace[i].err
+		n++
+	}
+	if n > 0 {
+		m.TrackingRMS = math.Sqrt(sum / float64(n))
+	}
+	return m
+}
+
+// SimulatedPlant models the head as a first-order lag with a delayed, noisy
+// detector running slower than the control loop. It lets gains be explored
+// offline and in tests without moving the robot.
+type SimulatedPlant struct {
+	Lag         float64 // Fraction of the command reached per tick (0-1]
+	Delay       int     // Measurement latency in ticks
+	Noise       float64 // Measurement noise standard deviation (radians)
+	DetectEvery int     // A measurement arrives every N ticks
+
+	rng     *rand.Rand
+	pos     float64
+	history []float64
+	ticks   int
+}
+
+// NewSimulatedPlant returns a plant matching the real loop: 20 Hz control,
+// 10 Hz detection, ~100 ms camera/detector latency and small pixel noise.
+func NewSimulatedPlant(seed int64) *SimulatedPlant {
+	return &SimulatedPlant{
+		Lag:         0.6,
+		Delay:       2,
+		Noise:       0.005,
+		DetectEvery: 2,
+		rng:         rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Reset implements Plant
+func (s *SimulatedPlant) Reset(ctx context.Context, axis Axis) error {
+	s.pos = 0
+	s.history = s.history[:0]
+	s.ticks = 0
+	return nil
+}
+
+// Command implements Plant
+func (s *SimulatedPlant) Command(axis Axis, angle float64) error {
+	lag := s.Lag
+	if lag <= 0 || lag > 1 {
+		lag = 1
+	}
+	s.pos += lag * (angle - s.pos)
+	s.history = append(s.history, s.pos)
+	return nil
+}
+
+// Measure implements Plant
+func (s *SimulatedPlant) Measure(axis Axis, reference float64) (float64, bool) {
+	s.ticks++
+	if s.DetectEvery > 1 && s.ticks%s.DetectEvery != 0 {
+		return 0, false
+	}
+
+	seen := 0.0
+	if idx := len(s.history) - 1 - s.Delay; idx >= 0 {
+		seen = s.history[idx]
+	}
+
+	noise := 0.0
+	if s.Noise > 0 && s.rng != nil {
+		noise = s.rng.NormFloat64() * s.Noise
+	}
+	return reference - seen + noise, true
+}
+
+// RealTime implements Plant
+func (s *SimulatedPlant) RealTime() bool { return false }
+
+// VisualPlant drives the real head against a stationary face. The face's
+// position at reset becomes the origin and the reference profile is added
+// as a virtual displacement, so the head chases a target that steps and
+// sweeps even though the person sits still.
+type VisualPlant struct {
+	t      *Tracker
+	origin float64 // Offset to the face at reset
+	yaw    float64
+	pitch  float64
+}
+
+// NewVisualPlant creates a plant that uses the tracker's camera, detector
+// and output path (offset handler or direct robot control).
+func NewVisualPlant(t *Tracker) *VisualPlant {
+	return &VisualPlant{t: t}
+}
+
+// Reset implements Plant. It centres the head and records the face offset.
+func (v *VisualPlant) Reset(ctx context.Context, axis Axis) error {
+	v.yaw, v.pitch = 0, 0
+	v.t.outputPose(0, 0, 0)
+	if err := sleepCtx(ctx, time.Second); err != nil { // Let the head arrive
+		return err
+	}
+
+	for attempt := 0; attempt < 10; attempt++ {
+		if off, ok := v.detect(axis); ok {
+			v.origin = off
+			return nil
+		}
+		if err := sleepCtx(ctx, 100*time.Millisecond); err != nil {
+			return err
+		}
+	}
+	return errors.New("no face visible: sit still in front of the camera")
+}
+
+// Command implements Plant
+func (v *VisualPlant) Command(axis Axis, angle float64) error {
+	if axis == AxisPitch {
+		v.pitch = angle
+	} else {
+		v.yaw = angle
+	}
+	v.t.outputPose(v.yaw, v.pitch, angle)
+	return nil
+}
+
+// Measure implements Plant
+func (v *VisualPlant) Measure(axis Axis, reference float64) (float64, bool) {
+	off, ok := v.detect(axis)
+	if !ok {
+		return 0, false
+	}
+	return off - v.origin + reference, true
+}
+
+// RealTime implements Plant
+func (v *VisualPlant) RealTime() bool { return true }
+
+// detect captures a frame and returns the face offset on the axis
+func (v *VisualPlant) detect(axis Axis) (float64, bool) {
+	if v.t.video == nil {
+		return 0, false
+	}
+	frame, err := v.t.video.CaptureJPEG()
+	if err != nil {
+		return 0, false
+	}
+	yawOff, pitchOff, _, found := v.t.perception.DetectFaceOffsetInFrame(frame)
+	if !found {
+		return 0, false
+	}
+	if axis == AxisPitch {
+		return pitchOff, true
+	}
+	return yawOff, true
+}
+
+// AutoTuneStatus reports progress of the tracker's current or last run
+type AutoTuneStatus struct {
+	Running bool            `json:"running"`
+	Axis    Axis            `json:"axis,omitempty"`
+	Done    int             `json:"done"`
+	Total   int             `json:"total"`
+	Result  *AutoTuneResult `json:"result,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
+
+// autoTuneState guards the tracker's auto-tune bookkeeping
+type autoTuneState struct {
+	mu     sync.Mutex
+	status AutoTuneStatus
+	cancel context.CancelFunc // Stops the running run (nil when idle)
+}
+
+// ErrSimulatedApply is returned when asked to apply gains tuned on a
+// SimulatedPlant: they fit the model, not the robot.
+var ErrSimulatedApply = errors.New("auto-tune: can't apply gains tuned on the simulated plant (use visual mode)")
+
+// AutoTune runs auto-tuning for one axis using the tracker's current
+// configuration as the baseline. Normal tracking is suspended for the
+// duration. If apply is true and the best candidate beats the baseline,
+// its gains are written to the live controller; a SimulatedPlant can't
+// be applied. The run stops when ctx is done or CancelAutoTune is called.
+func (t *Tracker) AutoTune(ctx context.Context, cfg AutoTuneConfig, plant Plant, apply bool) (*AutoTuneResult, error) {
+	if _, simulated := plant.(*SimulatedPlant); simulated && apply {
+		return nil, ErrSimulatedApply
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	t.autoTune.mu.Lock()
+	if t.autoTune.status.Running {
+		t.autoTune.mu.Unlock()
+		return nil, errors.New("auto-tune already running")
+	}
+	t.autoTune.status = AutoTuneStatus{Running: true, Axis: cfg.Axis}
+	t.autoTune.cancel = cancel
+	t.autoTune.mu.Unlock()
+
+	// Snapshot live gains into the baseline config
+	t.mu.Lock()
+	t.isAutoTuning = true
+	base := t.config
+	base.Kp, base.Kd = t.controller.Kp, t.controller.Kd
+	base.KpPitch, base.KdPitch = t.controller.KpPitch, t.controller.KdPitch
+	base.ControlDeadZone, base.PitchDeadZone = t.controller.DeadZone, t.controller.PitchDeadZone
+	base.MaxTargetVelocity = t.controller.MaxTargetVelocity
+	t.mu.Unlock()
+
+	userOnTrial := cfg.OnTrial
+	cfg.OnTrial = func(done, total int, trial TrialResult) {
+		t.autoTune.mu.Lock()
+		t.autoTune.status.Done, t.autoTune.status.Total = done, total
+		t.autoTune.mu.Unlock()
+		t.debugLog("🎛️  Auto-tune %s %d/%d: kp=%.3f kd=%.3f score=%.3f\n",
+			cfg.Axis, done, total, trial.Kp, trial.Kd, trial.Score)
+		if userOnTrial != nil {
+			userOnTrial(done, total, trial)
+		}
+	}
+
+	if t.state != nil {
+		t.state.AddLog("autotune", fmt.Sprintf("Auto-tuning %s gains", cfg.Axis))
+	}
+
+	result, err := AutoTune(ctx, plant, cfg, base)
+
+	t.mu.Lock()
+	if err == nil && apply && result.Best.Score < result.Baseline.Score {
+		if cfg.Axis == AxisPitch {
+			t.controller.KpPitch, t.controller.KdPitch = result.Best.Kp, result.Best.Kd
+			t.config.KpPitch, t.config.KdPitch = result.Best.Kp, result.Best.Kd
+		} else {
+			t.controller.Kp, t.controller.Kd = result.Best.Kp, result.Best.Kd
+			t.config.Kp, t.config.Kd = result.Best.Kp, result.Best.Kd
+		}
+		result.Applied = true
+	}
+	t.isAutoTuning = false
+	t.mu.Unlock()
+
+	t.autoTune.mu.Lock()
+	t.autoTune.status.Running = false
+	t.autoTune.cancel = nil
+	t.autoTune.status.Result = result
+	if err != nil {
+		t.autoTune.status.Error = err.Error()
+	}
+	t.autoTune.mu.Unlock()
+
+	if t.state != nil {
+		if err != nil {
+			t.state.AddLog("autotune", fmt.Sprintf("Auto-tune %s failed: %v", cfg.Axis, err))
+		} else {
+			t.state.AddLog("autotune", fmt.Sprintf("Auto-tune %s: kp=%.3f kd=%.3f (score %.2f → %.2f, applied=%v)",
+				cfg.Axis, result.Best.Kp, result.Best.Kd, result.Baseline.Score, result.Best.Score, result.Applied))
+		}
+	}
+
+	return result, err
+}
+
+// CancelAutoTune stops the running auto-tune, if any, leaving the gains
+// as they were. It reports whether a run was stopped.
+func (t *Tracker) CancelAutoTune() bool {
+	t.autoTune.mu.Lock()
+	defer t.autoTune.mu.Unlock()
+	if t.autoTune.cancel == nil {
+		return false
+	}
+	t.autoTune.cancel()
+	return true
+}
+
+// GetAutoTuneStatus returns progress of the current or last auto-tune run
+func (t *Tracker) GetAutoTuneStatus() AutoTuneStatus {
+	t.autoTune.mu.Lock()
+	defer t.autoTune.mu.Unlock()
+	return t.autoTune.status
+}
@@ -0,0 +1,266 @@
+package tracking
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/teslashibe/go-reachy/pkg/worldmodel"
+)
+
+func TestStepMetrics(t *testing.T) {
+	tick := 50 * time.Millisecond
+
+	tests := []struct {
+		name          string
+		errs          []float64
+		wantOvershoot float64
+		wantSettling  time.Duration
+		wantSettled   bool
+	}{
+		{
+			name:         "clean approach",
+			errs:         []float64{0.3, 0.2, 0.1, 0.04, 0.01, 0, 0, 0},
+			wantSettling: 3 * tick,
+			wantSettled:  true,
+		},
+		{
+			name:          "overshoot then settle",
+			errs:          []float64{0.3, 0.1, -0.06, -0.03, 0, 0, 0, 0},
+			wantOvershoot: 0.2,
+			wantSettling:  3 * tick,
+			wantSettled:   true,
+		},
+		{
+			name:         "never settles",
+			errs:         []float64{0.3, 0.3, 0.3, 0.3},
+			wantSettling: 4 * tick,
+			wantSettled:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seg := make([]sample, len(tt.errs))
+			for i, e := range tt.errs {
+				seg[i] = sample{err: e, measured: true}
+			}
+			m := stepMetrics([][]sample{seg}, 0.3, 0.05, tick)
+
+			if !floatNear(m.Overshoot, tt.wantOvershoot, 1e-9) {
+				t.Errorf("Overshoot = %v, want %v", m.Overshoot, tt.wantOvershoot)
+			}
+			if m.SettlingTime != tt.wantSettling {
+				t.Errorf("SettlingTime = %v, want %v", m.SettlingTime, tt.wantSettling)
+			}
+			if m.Settled != tt.wantSettled {
+				t.Errorf("Settled = %v, want %v", m.Settled, tt.wantSettled)
+			}
+		})
+	}
+}
+
+func TestStepMetrics_Jitter(t *testing.T) {
+	seg := []sample{
+		{command: 0}, {command: 0}, {command: 0}, {command: 0},
+		{command: 0.01}, {command: -0.01}, {command: 0.01}, {command: -0.01},
+	}
+	m := stepMetrics([][]sample{seg}, 0.3, 0.05, 50*time.Millisecond)
+	if !floatNear(m.Jitter, 0.02, 1e-9) {
+		t.Errorf("Jitter = %v, want 0.02", m.Jitter)
+	}
+}
+
+func TestSimulatedPlant_DelayAndRate(t *testing.T) {
+	p := NewSimulatedPlant(1)
+	p.Noise = 0
+	p.Lag = 1
+	p.Reset(context.Background(), AxisYaw)
+
+	// First tick has no measurement (DetectEvery = 2)
+	if _, ok := p.Measure(AxisYaw, 0.5); ok {
+		t.Error("Measure on tick 1 should be unavailable")
+	}
+	p.Command(AxisYaw, 0.5)
+
+	// Head moved but measurement is delayed, so target still looks 0.5 away
+	off, ok := p.Measure(AxisYaw, 0.5)
+	if !ok {
+		t.Fatal("Measure on tick 2 should be available")
+	}
+	if !floatNear(off, 0.5, 1e-9) {
+		t.Errorf("offset = %v, want 0.5 (delayed)", off)
+	}
+}
+
+func TestAutoTune_Simulated(t *testing.T) {
+	cfg := DefaultAutoTuneConfig(AxisYaw)
+	// Include a clearly bad candidate (sluggish) and a reasonable one
+	cfg.KpCandidates = []float64{0.01, 0.10}
+	cfg.KdCandidates = []float64{0.12}
+
+	base := DefaultConfig()
+	result, err := AutoTune(context.Background(), NewSimulatedPlant(42), cfg, base)
+	if err != nil {
+		t.Fatalf("AutoTune error: %v", err)
+	}
+
+	if len(result.Trials) != 2 {
+		t.Fatalf("Trials = %d, want 2", len(result.Trials))
+	}
+	if result.Best.Kp != 0.10 {
+		t.Errorf("Best.Kp = %v, want 0.10 (trials: %+v)", result.Best.Kp, result.Trials)
+	}
+	if result.Best.Score > result.Baseline.Score {
+		t.Errorf("Best score %v worse than baseline %v", result.Best.Score, result.Baseline.Score)
+	}
+	if !result.Best.Step.Settled {
+		t.Errorf("Best step response did not settle: %+v", result.Best.Step)
+	}
+}
+
+func TestAutoTune_Pitch(t *testing.T) {
+	cfg := DefaultAutoTuneConfig(AxisPitch)
+	cfg.KpCandidates = []float64{0.06}
+	cfg.KdCandidates = []float64{0.15}
+	cfg.SineDuration = 0
+
+	result, err := AutoTune(context.Background(), NewSimulatedPlant(7), cfg, DefaultConfig())
+	if err != nil {
+		t.Fatalf("AutoTune error: %v", err)
+	}
+	if result.Axis != AxisPitch {
+		t.Errorf("Axis = %v, want %v", result.Axis, AxisPitch)
+	}
+	if result.Best.Sine.TrackingRMS != 0 {
+		t.Errorf("Sine.TrackingRMS = %v, want 0 when sine skipped", result.Best.Sine.TrackingRMS)
+	}
+}
+
+func TestAutoTune_Errors(t *testing.T) {
+	cfg := DefaultAutoTuneConfig(AxisYaw)
+	if _, err := AutoTune(context.Background(), nil, cfg, DefaultConfig()); err == nil {
+		t.Error("expected error for nil plant")
+	}
+
+	cfg.KpCandidates = nil
+	if _, err := AutoTune(context.Background(), NewSimulatedPlant(1), cfg, DefaultConfig()); err == nil {
+		t.Error("expected error for empty candidates")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := AutoTune(ctx, NewSimulatedPlant(1), DefaultAutoTuneConfig(AxisYaw), DefaultConfig()); err == nil {
+		t.Error("expected error for cancelled context")
+	}
+}
+
+func TestTracker_AutoTuneApply(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Kp = 0.01 // Deliberately sluggish so tuning must improve on it
+
+	tracker := &Tracker{
+		config:     cfg,
+		robot:      &mockRobotController{},
+		world:      worldmodel.New(),
+		controller: NewPDController(cfg),
+	}
+
+	tuneCfg := DefaultAutoTuneConfig(AxisYaw)
+	tuneCfg.KpCandidates = []float64{0.10}
+	tuneCfg.KdCandidates = []float64{0.12}
+
+	// Gains tuned on the model are never applied
+	if _, err := tracker.AutoTune(context.Background(), tuneCfg, NewSimulatedPlant(3), true); err != ErrSimulatedApply {
+		t.Fatalf("AutoTune(simulated, apply) error = %v, want ErrSimulatedApply", err)
+	}
+	if tracker.controller.Kp != 0.01 {
+		t.Fatalf("controller.Kp = %v after a refused apply, want 0.01", tracker.controller.Kp)
+	}
+
+	result, err := tracker.AutoTune(context.Background(), tuneCfg, hardwarePlant{NewSimulatedPlant(3)}, true)
+	if err != nil {
+		t.Fatalf("AutoTune error: %v", err)
+	}
+	if !result.Applied {
+		t.Fatalf("Applied = false, want true (baseline %v, best %v)", result.Baseline.Score, result.Best.Score)
+	}
+	if tracker.controller.Kp != 0.10 {
+		t.Errorf("controller.Kp = %v, want 0.10", tracker.controller.Kp)
+	}
+	if tracker.config.Kp != 0.10 {
+		t.Errorf("config.Kp = %v, want 0.10", tracker.config.Kp)
+	}
+
+	status := tracker.GetAutoTuneStatus()
+	if status.Running {
+		t.Error("status.Running = true after completion")
+	}
+	if status.Done != status.Total || status.Total != 2 {
+		t.Errorf("status progress = %d/%d, want 2/2", status.Done, status.Total)
+	}
+	if tracker.isAutoTuning {
+		t.Error("isAutoTuning still set after completion")
+	}
+}
+
+// hardwarePlant stands in for the real head: a model that isn't a
+// *SimulatedPlant, so its gains may be applied
+type hardwarePlant struct{ *SimulatedPlant }
+
+// blockingPlant waits in Reset until its context is done
+type blockingPlant struct {
+	SimulatedPlant
+	reset chan struct{}
+}
+
+func (p *blockingPlant) Reset(ctx context.Context, axis Axis) error {
+	close(p.reset)
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestTracker_CancelAutoTune(t *testing.T) {
+	cfg := DefaultConfig()
+	tracker := &Tracker{
+		config:     cfg,
+		robot:      &mockRobotController{},
+		world:      worldmodel.New(),
+		controller: NewPDController(cfg),
+	}
+	if tracker.CancelAutoTune() {
+		t.Error("CancelAutoTune() = true with nothing running")
+	}
+
+	plant := &blockingPlant{reset: make(chan struct{})}
+	errc := make(chan error, 1)
+	go func() {
+		_, err := tracker.AutoTune(context.Background(), DefaultAutoTuneConfig(AxisYaw), plant, true)
+		errc <- err
+	}()
+	<-plant.reset
+
+	if !tracker.CancelAutoTune() {
+		t.Error("CancelAutoTune() = false during a run")
+	}
+	select {
+	case err := <-errc:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("AutoTune error = %v, want context.Canceled", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("AutoTune didn't stop after CancelAutoTune")
+	}
+	if status := tracker.GetAutoTuneStatus(); status.Running || status.Error == "" {
+		t.Errorf("status = %+v, want stopped with an error", status)
+	}
+	if tracker.isAutoTuning {
+		t.Error("isAutoTuning still set after cancel")
+	}
+}
+
+func floatNear(a, b, tol float64) bool {
+	d := a - b
+	return d < tol && d > -tol
+}
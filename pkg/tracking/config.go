@@ -41,6 +41,18 @@ type Config struct {
 	ScanSpeed      float64       // Radians per second when scanning
 	ScanRange      float64       // How far to scan left/right (radians)
 
+	// Search behavior (look where the target was last seen/heard before scanning)
+	SearchEnabled         bool          // Search planned look points before sweeping (default: true)
+	SearchDwell           time.Duration // How long to look at each point
+	SearchMaxDuration     time.Duration // Give up searching after this long
+	SearchMaxPoints       int           // Maximum look points per search
+	SearchPredictHorizon  time.Duration // Max extrapolation of the lost target's motion
+	SearchClueMaxAge      time.Duration // Ignore sightings and voices older than this
+	SearchMemoryMaxAge    time.Duration // Forget remembered locations after this long
+	SearchMergeDistance   float64       // Merge look points closer than this (radians)
+	SearchArriveTolerance float64       // Head counts as arrived within this (radians)
+	SearchGiveUp          SearchGiveUp  // What to do when every point failed
+
 	// Logging
 	LogThreshold float64 // Only log movements larger than this (radians)
 	DebugEnabled bool    // Enable verbose tracking debug logs (face detection, not moving, etc.)
@@ -124,6 +136,18 @@ func DefaultConfig() Config {
 		ScanSpeed:      0.3,             // 0.3 rad/sec when scanning
 		ScanRange:      1.0,             // Scan ±1.0 rad (±57°)
 
+		// Search behavior
+		SearchEnabled:         true,              // Enable by default
+		SearchDwell:           1 * time.Second,   // Look at each point for 1s
+		SearchMaxDuration:     10 * time.Second,  // Body turns are slow, allow a few points
+		SearchMaxPoints:       4,                 // Predicted, last seen, voice, best memory
+		SearchPredictHorizon:  1 * time.Second,   // Extrapolate at most 1s of motion
+		SearchClueMaxAge:      15 * time.Second,  // Clues older than this are stale
+		SearchMemoryMaxAge:    10 * time.Minute,  // Remember where people sit for a while
+		SearchMergeDistance:   0.25,              // ~14° - within one camera view
+		SearchArriveTolerance: 0.08,              // ~5°
+		SearchGiveUp:          SearchGiveUpSweep, // Fall back to the sweep
+
 		// Logging
 		LogThreshold: 0.05, // Log movements >0.05 rad (~3°)
 
@@ -13,6 +13,11 @@ func (d Detection) Center() (x, y float64) {
 	return d.X + d.W/2, d.Y + d.H/2
 }
 
+// Contains reports whether the point (x, y) lies inside the bounding box
+func (d Detection) Contains(x, y float64) bool {
+	return x >= d.X && x <= d.X+d.W && y >= d.Y && y <= d.Y+d.H
+}
+
 // Area returns the area of the bounding box
 func (d Detection) Area() float64 {
 	return d.W * d.H
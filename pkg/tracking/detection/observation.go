@@ -0,0 +1,158 @@
+package detection
+
+import "time"
+
+// Kind identifies what an observation is of
+type Kind string
+
+const (
+	KindFace   Kind = "face"   // Face from YuNet
+	KindBody   Kind = "body"   // Whole person from YOLO ("person" class)
+	KindAnimal Kind = "animal" // Pet or other animal from YOLO
+	KindObject Kind = "object" // Any other YOLO class
+)
+
+// Observation is a typed detection produced by any detector in the pipeline.
+// Faces, bodies and objects all share this shape so downstream consumers
+// (the world model, the dashboard) don't need to know which backend ran.
+type Observation struct {
+	Detection           // Bounding box (0-1 normalized) and confidence
+	Kind      Kind      // What was detected
+	Label     string    // Class name ("face", "person", "dog", "cup", ...)
+	Source    string    // Name of the detector that produced it
+	Timestamp time.Time // When the frame was processed
+}
+
+// ObservationDetector is implemented by anything that can turn a frame into
+// typed observations. Adapters exist for the face Detector and YOLO.
+type ObservationDetector interface {
+	// Name identifies the detector in logs and Observation.Source
+	Name() string
+
+	// Observe runs detection on a JPEG frame
+	Observe(jpeg []byte) ([]Observation, error)
+}
+
+// ObjectDetector is the interface satisfied by YOLODetector
+type ObjectDetector interface {
+	Detect(jpeg []byte) ([]ObjectDetection, error)
+}
+
+// animalClasses are the COCO classes treated as animals (pets)
+var animalClasses = map[string]bool{
+	"bird": true, "cat": true, "dog": true, "horse": true, "sheep": true,
+	"cow": true, "elephant": true, "bear": true, "zebra": true, "giraffe": true,
+}
+
+// IsAnimalClass returns true if the COCO class name is an animal
+func IsAnimalClass(className string) bool {
+	return animalClasses[className]
+}
+
+// KindForClass maps a COCO class name to an observation kind
+func KindForClass(className string) Kind {
+	switch {
+	case className == "person":
+		return KindBody
+	case IsAnimalClass(className):
+		return KindAnimal
+	default:
+		return KindObject
+	}
+}
+
+// FaceObservations converts face detections to observations
+func FaceObservations(dets []Detection, source string, ts time.Time) []Observation {
+	obs := make([]Observation, 0, len(dets))
+	for _, d := range dets {
+		obs = append(obs, Observation{
+			Detection: d,
+			Kind:      KindFace,
+			Label:     "face",
+			Source:    source,
+			Timestamp: ts,
+		})
+	}
+	return obs
+}
+
+// DropFacedBodies returns obs without the bodies that contain the center
+// of one of faces. A face inside a body box is the same person, so the
+// face (which also gives a distance) stands for both and the world model
+// keeps one track per person.
+func DropFacedBodies(faces, obs []Observation) []Observation {
+	result := make([]Observation, 0, len(obs))
+	for _, o := range obs {
+		if o.Kind == KindBody && containsFace(o.Detection, faces) {
+			continue
+		}
+		result = append(result, o)
+	}
+	return result
+}
+
+func containsFace(body Detection, faces []Observation) bool {
+	for _, f := range faces {
+		if f.Kind == KindFace && body.Contains(f.Center()) {
+			return true
+		}
+	}
+	return false
+}
+
+// ObjectObservations converts YOLO detections to observations
+func ObjectObservations(dets []ObjectDetection, source string, ts time.Time) []Observation {
+	obs := make([]Observation, 0, len(dets))
+	for _, d := range dets {
+		obs = append(obs, Observation{
+			Detection: d.Detection,
+			Kind:      KindForClass(d.ClassName),
+			Label:     d.ClassName,
+			Source:    source,
+			Timestamp: ts,
+		})
+	}
+	return obs
+}
+
+// faceSource adapts a face Detector to ObservationDetector
+type faceSource struct {
+	name     string
+	detector Detector
+}
+
+// NewFaceSource wraps a face Detector (e.g. YuNet) as an ObservationDetector
+func NewFaceSource(name string, d Detector) ObservationDetector {
+	return &faceSource{name: name, detector: d}
+}
+
+func (f *faceSource) Name() string { return f.name }
+
+func (f *faceSource) Observe(jpeg []byte) ([]Observation, error) {
+	dets, err := f.detector.Detect(jpeg)
+	if err != nil {
+		return nil, err
+	}
+	return FaceObservations(dets, f.name, time.Now()), nil
+}
+
+// objectSource adapts an ObjectDetector to ObservationDetector
+type objectSource struct {
+	name     string
+	detector ObjectDetector
+}
+
+// NewObjectSource wraps an ObjectDetector (e.g. YOLO) as an ObservationDetector
+func NewObjectSource(name string, d ObjectDetector) ObservationDetector {
+	return &objectSource{name: name, detector: d}
+}
+
+func (o *objectSource) Name() string { return o.name }
+
+func (o *objectSource) Observe(jpeg []byte) ([]Observation, error) {
+	dets, err := o.detector.Detect(jpeg)
+	if err != nil {
+		return nil, err
+	}
+	return ObjectObservations(dets, o.name, time.Now()), nil
+}
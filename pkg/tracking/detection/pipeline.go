@@ -0,0 +1,108 @@
+package detection
+
+import (
+	"sync"
+	"time"
+
+	"github.com/teslashibe/go-reachy/pkg/debug"
+)
+
+// stage is one detector in the pipeline with its own run rate
+type stage struct {
+	detector ObservationDetector
+	interval time.Duration // 0 = run on every frame
+	lastRun  time.Time
+	latest   []Observation
+}
+
+// Pipeline runs several detectors on the same frame at independent rates.
+// A cheap face detector can run on every frame while YOLO runs at 1-2 Hz;
+// each call to Process only invokes the stages that are due.
+type Pipeline struct {
+	mu     sync.Mutex
+	stages []*stage
+}
+
+// NewPipeline creates an empty detection pipeline
+func NewPipeline() *Pipeline {
+	return &Pipeline{}
+}
+
+// AddStage registers a detector that runs at most once per interval.
+// An interval of 0 runs the detector on every frame.
+func (p *Pipeline) AddStage(d ObservationDetector, interval time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stages = append(p.stages, &stage{detector: d, interval: interval})
+}
+
+// Len returns the number of registered stages
+func (p *Pipeline) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.stages)
+}
+
+// Process runs every due stage on the frame concurrently and returns the
+// fresh observations. Stages that are not due contribute nothing, so callers
+// only ever see each detection once. Detector errors are logged and skipped.
+func (p *Pipeline) Process(jpeg []byte, now time.Time) []Observation {
+	p.mu.Lock()
+	var due []*stage
+	for _, s := range p.stages {
+		if s.interval <= 0 || s.lastRun.IsZero() || now.Sub(s.lastRun) >= s.interval {
+			s.lastRun = now
+			due = append(due, s)
+		}
+	}
+	p.mu.Unlock()
+
+	if len(due) == 0 {
+		return nil
+	}
+
+	results := make([][]Observation, len(due))
+	var wg sync.WaitGroup
+	for i, s := range due {
+		wg.Add(1)
+		go func(i int, s *stage) {
+			defer wg.Done()
+			obs, err := s.detector.Observe(jpeg)
+			if err != nil {
+				debug.Log("🔍 %s detection error: %v\n", s.detector.Name(), err)
+				return
+			}
+			for j := range obs {
+				obs[j].Timestamp = now
+				if obs[j].Source == "" {
+					obs[j].Source = s.detector.Name()
+				}
+			}
+			results[i] = obs
+		}(i, s)
+	}
+	wg.Wait()
+
+	var all []Observation
+	p.mu.Lock()
+	for i, s := range due {
+		s.latest = results[i]
+		all = append(all, results[i]...)
+	}
+	p.mu.Unlock()
+
+	return all
+}
+
+// Latest returns the most recent observations from every stage,
+// regardless of when each stage last ran.
+func (p *Pipeline) Latest() []Observation {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var all []Observation
+	for _, s := range p.stages {
+		all = append(all, s.latest...)
+	}
+	return all
+}
@@ -0,0 +1,145 @@
+package detection
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeObjectDetector returns canned YOLO results
+type fakeObjectDetector struct {
+	dets  []ObjectDetection
+	err   error
+	calls int
+}
+
+func (f *fakeObjectDetector) Detect(jpeg []byte) ([]ObjectDetection, error) {
+	f.calls++
+	return f.dets, f.err
+}
+
+// fakeFaceDetector returns canned face results
+type fakeFaceDetector struct {
+	dets  []Detection
+	calls int
+}
+
+func (f *fakeFaceDetector) Detect(jpeg []byte) ([]Detection, error) {
+	f.calls++
+	return f.dets, nil
+}
+
+func (f *fakeFaceDetector) Close() error { return nil }
+
+func TestKindForClass(t *testing.T) {
+	tests := []struct {
+		class string
+		want  Kind
+	}{
+		{"person", KindBody},
+		{"dog", KindAnimal},
+		{"cat", KindAnimal},
+		{"cup", KindObject},
+		{"laptop", KindObject},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.class, func(t *testing.T) {
+			if got := KindForClass(tc.class); got != tc.want {
+				t.Errorf("KindForClass(%q) = %q, want %q", tc.class, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestObjectSource_Observe(t *testing.T) {
+	yolo := &fakeObjectDetector{dets: []ObjectDetection{
+		{Detection: Detection{X: 0.1, Y: 0.1, W: 0.2, H: 0.5, Confidence: 0.9}, ClassName: "person"},
+		{Detection: Detection{X: 0.6, Y: 0.7, W: 0.1, H: 0.1, Confidence: 0.8}, ClassName: "dog"},
+	}}
+
+	obs, err := NewObjectSource("yolo", yolo).Observe(nil)
+	if err != nil {
+		t.Fatalf("Observe failed: %v", err)
+	}
+	if len(obs) != 2 {
+		t.Fatalf("len(obs) = %d, want 2", len(obs))
+	}
+	if obs[0].Kind != KindBody || obs[0].Label != "person" || obs[0].Source != "yolo" {
+		t.Errorf("obs[0] = %+v, want body/person/yolo", obs[0])
+	}
+	if obs[1].Kind != KindAnimal || obs[1].Confidence != 0.8 {
+		t.Errorf("obs[1] = %+v, want animal with confidence 0.8", obs[1])
+	}
+}
+
+func TestPipeline_StageRates(t *testing.T) {
+	face := &fakeFaceDetector{dets: []Detection{{X: 0.4, Y: 0.4, W: 0.2, H: 0.2, Confidence: 0.9}}}
+	yolo := &fakeObjectDetector{dets: []ObjectDetection{{ClassName: "cup"}}}
+
+	p := NewPipeline()
+	p.AddStage(NewFaceSource("yunet", face), 0)                     // every frame
+	p.AddStage(NewObjectSource("yolo", yolo), 500*time.Millisecond) // 2 Hz
+
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		p.Process([]byte{0}, start.Add(time.Duration(i)*100*time.Millisecond))
+	}
+
+	if face.calls != 10 {
+		t.Errorf("face detector calls = %d, want 10", face.calls)
+	}
+	// Runs at t=0 and t=500ms
+	if yolo.calls != 2 {
+		t.Errorf("yolo detector calls = %d, want 2", yolo.calls)
+	}
+}
+
+func TestPipeline_ProcessReturnsOnlyFresh(t *testing.T) {
+	yolo := &fakeObjectDetector{dets: []ObjectDetection{{ClassName: "cup"}}}
+
+	p := NewPipeline()
+	p.AddStage(NewObjectSource("yolo", yolo), time.Second)
+
+	now := time.Now()
+	if got := p.Process(nil, now); len(got) != 1 {
+		t.Fatalf("first Process returned %d observations, want 1", len(got))
+	}
+	if got := p.Process(nil, now.Add(100*time.Millisecond)); len(got) != 0 {
+		t.Errorf("second Process returned %d observations, want 0 (stage not due)", len(got))
+	}
+	if got := p.Latest(); len(got) != 1 {
+		t.Errorf("Latest returned %d observations, want 1", len(got))
+	}
+}
+
+func TestPipeline_ErrorSkipsStage(t *testing.T) {
+	bad := &fakeObjectDetector{err: errors.New("boom")}
+	face := &fakeFaceDetector{dets: []Detection{{Confidence: 0.9}}}
+
+	p := NewPipeline()
+	p.AddStage(NewObjectSource("yolo", bad), 0)
+	p.AddStage(NewFaceSource("yunet", face), 0)
+
+	obs := p.Process(nil, time.Now())
+	if len(obs) != 1 || obs[0].Kind != KindFace {
+		t.Errorf("Process = %+v, want single face observation", obs)
+	}
+}
+
+func TestDropFacedBodies(t *testing.T) {
+	faces := FaceObservations([]Detection{{X: 0.4, Y: 0.2, W: 0.2, H: 0.2}}, "yunet", time.Now())
+	obs := []Observation{
+		{Detection: Detection{X: 0.3, Y: 0.1, W: 0.4, H: 0.9}, Kind: KindBody, Label: "person"}, // Holds the face
+		{Detection: Detection{X: 0.7, Y: 0.1, W: 0.2, H: 0.9}, Kind: KindBody, Label: "person"}, // Facing away
+		{Detection: Detection{X: 0.3, Y: 0.1, W: 0.4, H: 0.9}, Kind: KindAnimal, Label: "dog"},
+	}
+
+	got := DropFacedBodies(faces, obs)
+	if len(got) != 2 {
+		t.Fatalf("DropFacedBodies kept %d observations, want 2", len(got))
+	}
+	if got[0].X != 0.7 || got[1].Label != "dog" {
+		t.Errorf("DropFacedBodies = %+v, want the faceless body and the dog", got)
+	}
+}
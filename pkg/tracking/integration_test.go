@@ -154,6 +154,7 @@ func TestTracker_BodyRotation(t *testing.T) {
 func TestTracker_SmoothInterpolation(t *testing.T) {
 	cfg := DefaultConfig()
 	cfg.ScanStartDelay = 50 * time.Millisecond // Short delay for testing
+	cfg.SearchEnabled = false                   // Return to neutral without searching
 
 	var receivedOffsets []robot.Offset
 	var mu sync.Mutex
@@ -179,7 +180,7 @@ func TestTracker_SmoothInterpolation(t *testing.T) {
 	tracker.updateNoTarget()
 
 	// Should start interpolating
-	if !tracker.isInterpolating {
+	if tracker.GetState() != StateReturning {
 		t.Error("Expected interpolation to start")
 	}
 
@@ -0,0 +1,140 @@
+package tracking
+
+import (
+	"context"
+	"time"
+
+	"github.com/teslashibe/go-reachy/pkg/tracking/detection"
+	"github.com/teslashibe/go-reachy/pkg/worldmodel"
+)
+
+// SetObservationPipeline attaches extra detectors (e.g. YOLO for bodies,
+// pets and objects) that run on the same frames as face detection.
+// Their observations, together with every detected face, are associated
+// into multi-frame tracks on the world model (see worldmodel.UpdateTracks).
+// The pipeline runs on its own goroutine, so a slow detector never delays
+// face tracking; it skips to the newest frame when it falls behind.
+func (t *Tracker) SetObservationPipeline(p *detection.Pipeline) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pipeline = p
+}
+
+// pipelineFrame is a frame waiting for the observation pipeline, with
+// the faces found in it and the pose it was captured at
+type pipelineFrame struct {
+	jpeg    []byte
+	faces   []detection.Observation
+	headYaw float64
+	bodyYaw float64
+}
+
+// updateTracks converts this frame's faces into room-coordinate
+// observations, updates the world model's tracks, and hands the frame
+// to the pipeline worker.
+func (t *Tracker) updateTracks(frame []byte) {
+	t.mu.RLock()
+	pipeline := t.pipeline
+	t.mu.RUnlock()
+
+	faces := t.perception.GetLastDetections()
+	obs := detection.FaceObservations(faces, "yunet", time.Now())
+	headYaw := t.controller.GetCurrentYaw()
+	bodyYaw := t.world.GetBodyYaw()
+
+	if pipeline != nil {
+		t.queuePipelineFrame(pipelineFrame{jpeg: frame, faces: obs, headYaw: headYaw, bodyYaw: bodyYaw})
+	}
+	if len(obs) == 0 {
+		return
+	}
+
+	wobs := t.toWorldObservations(obs, headYaw, bodyYaw)
+	ids := t.world.UpdateTracks(wobs)
+
+	// obs are in the same order as faces, so the followed face keeps its index
+	t.rememberSightings(wobs, ids, bestFaceIndex(faces))
+}
+
+// queuePipelineFrame replaces any frame the pipeline worker hasn't
+// started on yet, so it always works on the newest one
+func (t *Tracker) queuePipelineFrame(f pipelineFrame) {
+	select {
+	case <-t.pipelineFrames: // Drop the stale frame
+	default:
+	}
+	select {
+	case t.pipelineFrames <- f:
+	default:
+	}
+}
+
+// runPipeline runs the observation pipeline on queued frames until ctx
+// is done. Bodies that contain one of the frame's faces are merged into
+// that face's track rather than starting their own.
+func (t *Tracker) runPipeline(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case f := <-t.pipelineFrames:
+			t.mu.RLock()
+			pipeline := t.pipeline
+			t.mu.RUnlock()
+			if pipeline == nil {
+				continue
+			}
+
+			obs := detection.DropFacedBodies(f.faces, pipeline.Process(f.jpeg, time.Now()))
+			if len(obs) == 0 {
+				continue
+			}
+			wobs := t.toWorldObservations(obs, f.headYaw, f.bodyYaw)
+			ids := t.world.UpdateTracks(wobs)
+			t.rememberSightings(wobs, ids, -1)
+		}
+	}
+}
+
+// toWorldObservations projects detections into room coordinates
+func (t *Tracker) toWorldObservations(obs []detection.Observation, headYaw, bodyYaw float64) []worldmodel.Observation {
+	crop := t.perception.Crop()
+	result := make([]worldmodel.Observation, 0, len(obs))
+	for _, o := range obs {
+		cx, cy := o.Center()
+		frameX := clamp(cx*100.0, 0, 100)
+		frameY := clamp(cy*100.0, 0, 100)
+
+		var distance float64
+		if o.Kind == detection.KindFace {
+			distance = worldmodel.EstimateDepth(o.W * crop.W) // Zoom makes faces look closer
+		}
+
+		result = append(result, worldmodel.Observation{
+			Kind:       trackKind(o.Kind),
+			Label:      o.Label,
+			WorldAngle: t.perception.FrameToRoomAngle(frameX, headYaw, bodyYaw),
+			FrameX:     frameX,
+			FrameY:     frameY,
+			Width:      o.W,
+			Height:     o.H,
+			Distance:   distance,
+			Confidence: o.Confidence,
+			Source:     o.Source,
+			Time:       o.Timestamp,
+		})
+	}
+	return result
+}
+
+// trackKind maps a detection kind to the world model track category
+func trackKind(k detection.Kind) worldmodel.TrackKind {
+	switch k {
+	case detection.KindFace, detection.KindBody:
+		return worldmodel.TrackPerson
+	case detection.KindAnimal:
+		return worldmodel.TrackPet
+	default:
+		return worldmodel.TrackObject
+	}
+}
@@ -0,0 +1,147 @@
+package tracking
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/teslashibe/go-reachy/pkg/tracking/detection"
+	"github.com/teslashibe/go-reachy/pkg/worldmodel"
+)
+
+// stubVideo returns a fixed frame or error
+type stubVideo struct {
+	err error
+}
+
+func (v *stubVideo) CaptureJPEG() ([]byte, error) {
+	return []byte("frame"), v.err
+}
+
+// stubFaces always finds the same face
+type stubFaces struct{ face detection.Detection }
+
+func (d *stubFaces) Detect(jpeg []byte) ([]detection.Detection, error) {
+	return []detection.Detection{d.face}, nil
+}
+
+func (d *stubFaces) Close() error { return nil }
+
+// blockingObjects waits for release before returning its detections
+type blockingObjects struct {
+	dets    []detection.ObjectDetection
+	release chan struct{}
+	calls   atomic.Int32
+}
+
+func (d *blockingObjects) Detect(jpeg []byte) ([]detection.ObjectDetection, error) {
+	d.calls.Add(1)
+	<-d.release
+	return d.dets, nil
+}
+
+func newObservationTracker(video VideoSource) *Tracker {
+	cfg := DefaultConfig()
+	face := detection.Detection{X: 0.4, Y: 0.2, W: 0.2, H: 0.2, Confidence: 0.9}
+	return &Tracker{
+		config:         cfg,
+		video:          video,
+		world:          worldmodel.New(),
+		controller:     NewPDController(cfg),
+		perception:     NewPerception(cfg, &stubFaces{face: face}),
+		isEnabled:      true,
+		isFaceEnabled:  true,
+		lastLoggedYaw:  999.0,
+		pipelineFrames: make(chan pipelineFrame, 1),
+	}
+}
+
+func TestTracker_PipelineRunsBesideDetection(t *testing.T) {
+	tracker := newObservationTracker(&stubVideo{})
+	objects := &blockingObjects{
+		release: make(chan struct{}),
+		dets: []detection.ObjectDetection{
+			{Detection: detection.Detection{X: 0.3, Y: 0.1, W: 0.4, H: 0.9, Confidence: 0.8}, ClassName: "person"},
+			{Detection: detection.Detection{X: 0.0, Y: 0.6, W: 0.1, H: 0.2, Confidence: 0.8}, ClassName: "dog"},
+		},
+	}
+	pipeline := detection.NewPipeline()
+	pipeline.AddStage(detection.NewObjectSource("yolo", objects), 0)
+	tracker.SetObservationPipeline(pipeline)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go tracker.runPipeline(ctx)
+
+	// Face tracking goes on while the pipeline is stuck on the first frame
+	for i := 0; i < 3; i++ {
+		done := make(chan struct{})
+		go func() {
+			tracker.detectAndUpdate()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("detectAndUpdate waited for the pipeline")
+		}
+		if i == 0 {
+			waitFor(t, func() bool { return objects.calls.Load() == 1 })
+		}
+	}
+	if !tracker.hasFaceTarget {
+		t.Error("hasFaceTarget = false, want true")
+	}
+	if n := len(tracker.world.GetTracks(worldmodel.TrackPerson)); n != 1 {
+		t.Errorf("%d person tracks before the pipeline finished, want 1", n)
+	}
+
+	// The pipeline skips the stale frame and only runs on the newest
+	close(objects.release)
+	waitFor(t, func() bool { return len(tracker.world.GetTracks(worldmodel.TrackPet)) == 1 })
+	time.Sleep(20 * time.Millisecond)
+	if n := objects.calls.Load(); n != 2 {
+		t.Errorf("pipeline ran %d times, want 2", n)
+	}
+
+	// The body around the face is the same person
+	if n := len(tracker.world.GetTracks(worldmodel.TrackPerson)); n != 1 {
+		t.Errorf("%d person tracks, want 1 (face and body merged)", n)
+	}
+}
+
+func TestTracker_CaptureErrorClearsFace(t *testing.T) {
+	video := &stubVideo{}
+	tracker := newObservationTracker(video)
+
+	tracker.detectAndUpdate()
+	if !tracker.hasFaceTarget {
+		t.Fatal("hasFaceTarget = false after a face, want true")
+	}
+
+	video.err = errors.New("camera gone")
+	tracker.detectAndUpdate()
+	if tracker.hasFaceTarget {
+		t.Error("hasFaceTarget = true after a capture error, want false")
+	}
+
+	tracker.video = nil
+	tracker.hasFaceTarget = true
+	tracker.detectAndUpdate()
+	if tracker.hasFaceTarget {
+		t.Error("hasFaceTarget = true without a video source, want false")
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
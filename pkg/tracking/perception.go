@@ -1,12 +1,36 @@
 package tracking
 
 import (
-	"math"
+	"sync"
 
 	"github.com/teslashibe/go-reachy/pkg/debug"
 	"github.com/teslashibe/go-reachy/pkg/tracking/detection"
 )
 
+// Crop is the part of the camera's full view that frames show, normalized
+// to the full view (0-1, top-left origin). CameraFOV and VerticalFOV
+// describe the full view; a digital zoom or reframing crop narrows and
+// shifts it.
+type Crop struct {
+	X, Y, W, H float64
+}
+
+// FullCrop is the uncropped view.
+func FullCrop() Crop {
+	return Crop{W: 1, H: 1}
+}
+
+// ToView converts a frame position (0-1) to a full-view position (0-1).
+func (c Crop) ToView(x, y float64) (float64, float64) {
+	return c.X + x*c.W, c.Y + y*c.H
+}
+
+// ToFrame converts a full-view position (0-1) to a frame position (0-1),
+// outside 0-1 if the crop doesn't show it.
+func (c Crop) ToFrame(x, y float64) (float64, float64) {
+	return (x - c.X) / c.W, (y - c.Y) / c.H
+}
+
 // Perception handles converting camera frame detections to world coordinates
 type Perception struct {
 	// Detector
@@ -16,6 +40,11 @@ type Perception struct {
 	CameraFOV   float64 // Horizontal field of view in radians
 	VerticalFOV float64 // Vertical field of view in radians
 
+	// Current crop of the full view; set from other goroutines
+	crop     Crop
+	cropUsed Crop // Crop of the last detection, to notice changes
+	cropMu   sync.RWMutex
+
 	// Smoothing (horizontal position)
 	smoothedPosition float64
 	hasLastPosition  bool
@@ -32,6 +61,7 @@ type Perception struct {
 	offsetSmoothingAlpha float64 // 0-1, higher = more responsive, lower = smoother
 
 	// Detection state
+	lastDetections     []detection.Detection // All faces from the last frame
 	lastValidPosition  float64
 	lastValidPositionY float64
 	consecutiveMisses  int
@@ -43,17 +73,53 @@ func NewPerception(config Config, detector detection.Detector) *Perception {
 		detector:             detector,
 		CameraFOV:            config.CameraFOV,
 		VerticalFOV:          config.VerticalFOV,
+		crop:                 FullCrop(),
+		cropUsed:             FullCrop(),
 		smoothingFactor:      config.PositionSmoothing,
 		offsetSmoothingAlpha: config.OffsetSmoothingAlpha,
 	}
 }
 
+// SetCrop tells perception which part of the full view frames show, so
+// angles stay correct under digital zoom.
+func (p *Perception) SetCrop(c Crop) {
+	if c.W <= 0 || c.H <= 0 {
+		c = FullCrop()
+	}
+	p.cropMu.Lock()
+	defer p.cropMu.Unlock()
+	p.crop = c
+}
+
+// Crop returns the current crop.
+func (p *Perception) Crop() Crop {
+	p.cropMu.RLock()
+	defer p.cropMu.RUnlock()
+	return p.crop
+}
+
+// detectionCrop returns the crop for a detection. Position smoothing
+// restarts when it changed, since frame positions from the old crop
+// don't compare.
+func (p *Perception) detectionCrop() Crop {
+	p.cropMu.Lock()
+	c, changed := p.crop, p.crop != p.cropUsed
+	p.cropUsed = p.crop
+	p.cropMu.Unlock()
+	if changed {
+		p.hasLastPosition = false
+		p.hasLastPositionY = false
+	}
+	return c
+}
+
 // FrameToWorld converts a frame position (0-100%) to a body-relative world angle.
 // currentYaw is the current head yaw in radians (relative to body).
 // The returned angle is body-relative, suitable for storing in the world model.
 func (p *Perception) FrameToWorld(framePosition float64, currentYaw float64) float64 {
-	// Frame offset from center: -0.5 to +0.5
-	frameOffset := (framePosition - 50) / 100.0
+	// Offset from the center of the full view: -0.5 to +0.5
+	viewX, _ := p.Crop().ToView(framePosition/100.0, 0.5)
+	frameOffset := viewX - 0.5
 
 	// Convert to camera-relative angle
 	// At 0% position, we're at -FOV/2 from camera center
@@ -86,17 +152,18 @@ func (p *Perception) WorldToFrame(worldAngle float64, currentYaw float64) float6
 	// Calculate camera-relative angle
 	cameraAngle := currentYaw - worldAngle
 
-	// Convert to frame position
+	// Convert to frame position (inverse of FrameToWorld: positive
+	// camera angle = right side of frame)
 	frameOffset := cameraAngle / p.CameraFOV
-	framePosition := 50 - frameOffset*100
+	frameX, _ := p.Crop().ToFrame(0.5+frameOffset, 0.5)
 
-	return framePosition
+	return frameX * 100
 }
 
 // IsInFrame returns true if a world angle would be visible in the current frame
 func (p *Perception) IsInFrame(worldAngle float64, currentYaw float64) bool {
-	cameraAngle := math.Abs(currentYaw - worldAngle)
-	return cameraAngle < p.CameraFOV/2
+	framePosition := p.WorldToFrame(worldAngle, currentYaw)
+	return framePosition > 0 && framePosition < 100
 }
 
 // FrameToPitch converts vertical frame position to room-relative pitch angle.
@@ -105,8 +172,9 @@ func (p *Perception) IsInFrame(worldAngle float64, currentYaw float64) bool {
 // Returns: target pitch in radians (negative = looking up, positive = looking down)
 // Note: Reachy Mini uses negative pitch for looking UP
 func (p *Perception) FrameToPitch(framePositionY float64, currentPitch float64) float64 {
-	// Frame offset from center: -0.5 (top) to +0.5 (bottom)
-	frameOffset := (framePositionY - 50) / 100.0
+	// Offset from the center of the full view: -0.5 (top) to +0.5 (bottom)
+	_, viewY := p.Crop().ToView(0.5, framePositionY/100.0)
+	frameOffset := viewY - 0.5
 
 	// Convert to camera-relative pitch offset
 	// Face above center (negative offset) = need to pitch up more (add negative)
@@ -179,8 +247,8 @@ func (p *Perception) DetectFaceRoomFull(video VideoSource, headYaw, headPitch, b
 	positionX := clamp(cx*100.0, 0, 100)
 	positionY := clamp(cy*100.0, 0, 100)
 
-	// Get face width for depth estimation (already normalized 0-1)
-	faceWidth := best.W
+	// Face width in full-view units for depth estimation
+	faceWidth := best.W * p.detectionCrop().W
 
 	// Apply smoothing to X (horizontal)
 	if p.hasLastPosition {
@@ -235,8 +303,19 @@ func (p *Perception) DetectFaceOffset(video VideoSource) (yawOffset, pitchOffset
 		return 0, 0, 0, false
 	}
 
+	return p.DetectFaceOffsetInFrame(frame)
+}
+
+// DetectFaceOffsetInFrame is DetectFaceOffset on an already captured frame.
+// Use this when the same frame is shared with other detectors.
+func (p *Perception) DetectFaceOffsetInFrame(frame []byte) (yawOffset, pitchOffset, faceWidth float64, found bool) {
+	if p.detector == nil {
+		return 0, 0, 0, false
+	}
+
 	// Run local face detection
 	detections, err := p.detector.Detect(frame)
+	p.lastDetections = detections
 	if err != nil {
 		debug.Log("👁️  Detection error: %v\n", err)
 		p.consecutiveMisses++
@@ -255,8 +334,9 @@ func (p *Perception) DetectFaceOffset(video VideoSource) (yawOffset, pitchOffset
 	positionX := clamp(cx*100.0, 0, 100)
 	positionY := clamp(cy*100.0, 0, 100)
 
-	// Get face width for depth estimation (already normalized 0-1)
-	faceWidth = best.W
+	// Face width in full-view units for depth estimation
+	crop := p.detectionCrop()
+	faceWidth = best.W * crop.W
 
 	// Apply smoothing to X (horizontal)
 	if p.hasLastPosition {
@@ -280,11 +360,14 @@ func (p *Perception) DetectFaceOffset(video VideoSource) (yawOffset, pitchOffset
 	// Face at 50% = centered, offset = 0
 	// Face at 75% = 25% right of center, need to turn right (negative yaw)
 	// Face at 25% = 25% left of center, need to turn left (positive yaw)
-	frameOffsetX := (50 - positionX) / 100.0   // -0.5 to +0.5
+	// Offsets are from the center of the full view, so a shifted crop
+	// still points the head at the face
+	viewX, viewY := crop.ToView(positionX/100.0, positionY/100.0)
+	frameOffsetX := 0.5 - viewX                // -0.5 to +0.5
 	rawYawOffset := frameOffsetX * p.CameraFOV // Scale by FOV
 
 	// Pitch: face above center = need to look up (negative pitch for Reachy)
-	frameOffsetY := (50 - positionY) / 100.0        // -0.5 to +0.5
+	frameOffsetY := 0.5 - viewY                     // -0.5 to +0.5
 	rawPitchOffset := -frameOffsetY * p.VerticalFOV // Negative because up is negative pitch
 
 	// Apply EMA smoothing to offsets (reduces jitter in controller input)
@@ -304,6 +387,11 @@ func (p *Perception) DetectFaceOffset(video VideoSource) (yawOffset, pitchOffset
 	return yawOffset, pitchOffset, faceWidth, true
 }
 
+// GetLastDetections returns every face found in the last processed frame
+func (p *Perception) GetLastDetections() []detection.Detection {
+	return p.lastDetections
+}
+
 // GetFramePosition returns the last detected frame position (0-100%)
 func (p *Perception) GetFramePosition() (x, y float64) {
 	return p.lastValidPosition, p.lastValidPositionY
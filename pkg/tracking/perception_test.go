@@ -3,6 +3,8 @@ package tracking
 import (
 	"math"
 	"testing"
+
+	"github.com/teslashibe/go-reachy/pkg/tracking/detection"
 )
 
 func TestPerception_FrameToWorld(t *testing.T) {
@@ -136,3 +138,67 @@ func TestPerception_FrameToRoomAngle_BodyYawMaintainsAccuracy(t *testing.T) {
 	}
 }
 
+
+// fixedDetector returns the same faces for every frame.
+type fixedDetector []detection.Detection
+
+func (d fixedDetector) Detect([]byte) ([]detection.Detection, error) { return d, nil }
+func (d fixedDetector) Close() error                                 { return nil }
+
+func TestPerception_Crop(t *testing.T) {
+	cfg := DefaultConfig()
+	p := NewPerception(cfg, nil)
+
+	// 2x zoom on the right half of the view
+	p.SetCrop(Crop{X: 0.5, Y: 0.25, W: 0.5, H: 0.5})
+
+	// The crop's center is a quarter of the view right of the camera's center
+	if got, want := p.FrameToWorld(50, 0), -cfg.CameraFOV/4; math.Abs(got-want) > 1e-9 {
+		t.Errorf("FrameToWorld(50) = %v, want %v", got, want)
+	}
+	// Its left edge is the camera's center
+	if got := p.FrameToWorld(0, 0.3); math.Abs(got-0.3) > 1e-9 {
+		t.Errorf("FrameToWorld(0) = %v, want 0.3", got)
+	}
+	if got := p.WorldToFrame(-cfg.CameraFOV/4, 0); math.Abs(got-50) > 1e-9 {
+		t.Errorf("WorldToFrame = %v, want 50", got)
+	}
+	if p.IsInFrame(0.1, 0) || !p.IsInFrame(-0.1, 0) {
+		t.Error("IsInFrame should only see the right half")
+	}
+	// Vertically centered, so only the scale changes
+	if got, want := p.FrameToPitch(100, 0), cfg.VerticalFOV/4; math.Abs(got-want) > 1e-9 {
+		t.Errorf("FrameToPitch(100) = %v, want %v", got, want)
+	}
+
+	p.SetCrop(Crop{})
+	if p.Crop() != FullCrop() {
+		t.Errorf("Crop() = %+v after an empty crop, want the full view", p.Crop())
+	}
+}
+
+func TestPerception_CropOffsets(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.OffsetSmoothingAlpha = 1
+	// A face centered in the frame
+	p := NewPerception(cfg, fixedDetector{{X: 0.45, Y: 0.45, W: 0.1, H: 0.1, Confidence: 0.9}})
+
+	yaw, pitch, width, found := p.DetectFaceOffsetInFrame(nil)
+	if !found || math.Abs(yaw) > 1e-9 || math.Abs(pitch) > 1e-9 {
+		t.Fatalf("uncropped offsets = %v, %v, %v, want 0, 0", yaw, pitch, found)
+	}
+
+	// Cropped to the top-left quarter, the face is left of and above center
+	p.SetCrop(Crop{W: 0.5, H: 0.5})
+	yaw, pitch, width, _ = p.DetectFaceOffsetInFrame(nil)
+	if want := cfg.CameraFOV / 4; math.Abs(yaw-want) > 1e-9 {
+		t.Errorf("yaw offset = %v, want %v (turn left)", yaw, want)
+	}
+	if want := -cfg.VerticalFOV / 4; math.Abs(pitch-want) > 1e-9 {
+		t.Errorf("pitch offset = %v, want %v (look up)", pitch, want)
+	}
+	// The face fills twice as much of the frame but is no closer
+	if math.Abs(width-0.05) > 1e-9 {
+		t.Errorf("face width = %v, want 0.05 of the full view", width)
+	}
+}
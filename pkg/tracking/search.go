@@ -0,0 +1,360 @@
+package tracking
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/teslashibe/go-reachy/pkg/debug"
+	"github.com/teslashibe/go-reachy/pkg/tracking/detection"
+	"github.com/teslashibe/go-reachy/pkg/worldmodel"
+)
+
+// SearchGiveUp selects what the tracker does once every search point failed
+type SearchGiveUp string
+
+const (
+	SearchGiveUpSweep   SearchGiveUp = "sweep"   // Return to neutral, sweep scan, then breathe
+	SearchGiveUpNeutral SearchGiveUp = "neutral" // Return to neutral and breathe (no sweep)
+	SearchGiveUpHold    SearchGiveUp = "hold"    // Keep looking at the last point
+)
+
+// Look point sources
+const (
+	SourcePredicted = "predicted" // Last target position extrapolated by its velocity
+	SourceLastSeen  = "last_seen" // Where the target was last seen
+	SourceVoice     = "voice"     // Most recent voice direction (DOA)
+	SourceMemory    = "memory"    // Place where people are often seen
+)
+
+// maxSearchSpots bounds the remembered locations
+const maxSearchSpots = 32
+
+// LookPoint is one place to look while searching for a lost target
+type LookPoint struct {
+	Angle    float64 `json:"angle"`    // Room angle (radians, +left)
+	BodyYaw  float64 `json:"body_yaw"` // Body yaw needed for the head to reach Angle
+	Priority float64 `json:"priority"` // Higher is searched first
+	Source   string  `json:"source"`   // SourcePredicted, SourceVoice, ...
+}
+
+// VoiceClue is the most recent voice direction in room coordinates
+type VoiceClue struct {
+	Angle      float64   // Room angle (radians, +left)
+	Confidence float64   // DOA confidence (0-1)
+	Time       time.Time // When heard
+}
+
+// SearchSpot is a remembered location where people were seen
+type SearchSpot struct {
+	Angle    float64   // Mean room angle (radians)
+	Count    int       // Number of sightings
+	LastSeen time.Time // Most recent sighting
+}
+
+// SearchClues is everything the planner knows when the target is lost
+type SearchClues struct {
+	Target        *worldmodel.Track // Last followed person (nil = none)
+	Voice         *VoiceClue        // Last voice direction (nil = none)
+	Spots         []SearchSpot      // Remembered locations
+	BodyYaw       float64           // Current body yaw
+	CanRotateBody bool              // Whether the body can turn to reach far points
+}
+
+// PlanSearch turns clues into a prioritised list of look points.
+// The last target (extrapolated by its velocity) ranks highest, then the
+// last voice direction (boosted if heard after the target vanished), then
+// remembered locations. Points closer than SearchMergeDistance are merged.
+// An empty plan means there is nothing better to do than a sweep.
+func PlanSearch(clues SearchClues, cfg Config, now time.Time) []LookPoint {
+	var candidates []LookPoint
+
+	if tr := clues.Target; tr != nil && now.Sub(tr.LastSeen) <= cfg.SearchClueMaxAge {
+		elapsed := now.Sub(tr.LastSeen)
+		if elapsed > cfg.SearchPredictHorizon {
+			elapsed = cfg.SearchPredictHorizon
+		}
+		predicted := tr.WorldAngle + tr.Velocity*elapsed.Seconds()
+		candidates = append(candidates,
+			LookPoint{Angle: predicted, Priority: 1.0, Source: SourcePredicted},
+			LookPoint{Angle: tr.WorldAngle, Priority: 0.8, Source: SourceLastSeen},
+		)
+	}
+
+	if v := clues.Voice; v != nil && now.Sub(v.Time) <= cfg.SearchClueMaxAge {
+		priority := 0.6 + 0.3*v.Confidence
+		if clues.Target == nil || v.Time.After(clues.Target.LastSeen) {
+			priority += 0.3 // Spoke after we lost them: likely where they went
+		}
+		candidates = append(candidates, LookPoint{Angle: v.Angle, Priority: priority, Source: SourceVoice})
+	}
+
+	maxCount := 0
+	for _, s := range clues.Spots {
+		if now.Sub(s.LastSeen) <= cfg.SearchMemoryMaxAge && s.Count > maxCount {
+			maxCount = s.Count
+		}
+	}
+	for _, s := range clues.Spots {
+		if maxCount == 0 || now.Sub(s.LastSeen) > cfg.SearchMemoryMaxAge {
+			continue
+		}
+		priority := 0.5 * float64(s.Count) / float64(maxCount)
+		candidates = append(candidates, LookPoint{Angle: s.Angle, Priority: priority, Source: SourceMemory})
+	}
+
+	// Highest priority first; merge points that are close together
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Priority > candidates[j].Priority
+	})
+
+	var plan []LookPoint
+	for _, c := range candidates {
+		c = reachLookPoint(c, clues, cfg)
+		merged := false
+		for _, p := range plan {
+			if math.Abs(p.Angle-c.Angle) < cfg.SearchMergeDistance {
+				merged = true
+				break
+			}
+		}
+		if merged {
+			continue
+		}
+		plan = append(plan, c)
+		if cfg.SearchMaxPoints > 0 && len(plan) >= cfg.SearchMaxPoints {
+			break
+		}
+	}
+	return plan
+}
+
+// reachLookPoint sets the body yaw needed for the head to reach the point,
+// clamping the point to what head and body can reach
+func reachLookPoint(p LookPoint, clues SearchClues, cfg Config) LookPoint {
+	headRange := cfg.YawRange * 0.9 // Keep a margin from the mechanical limit
+	body := clues.BodyYaw
+
+	rel := p.Angle - body
+	if math.Abs(rel) > headRange && clues.CanRotateBody {
+		body = p.Angle - math.Copysign(headRange, rel)
+		body = clamp(body, -cfg.BodyYawLimit, cfg.BodyYawLimit)
+	}
+
+	p.BodyYaw = body
+	p.Angle = clamp(p.Angle, body-headRange, body+headRange)
+	return p
+}
+
+// searchMemory collects search clues while tracking. Written from the
+// detection and movement goroutines, read when a search starts.
+type searchMemory struct {
+	mu     sync.Mutex
+	target *worldmodel.Track
+	voice  *VoiceClue
+	spots  []SearchSpot
+}
+
+// rememberTarget records the person currently being followed
+func (m *searchMemory) rememberTarget(tr *worldmodel.Track) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.target = tr
+}
+
+// rememberVoice records the latest voice direction
+func (m *searchMemory) rememberVoice(angle, confidence float64, at time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.voice = &VoiceClue{Angle: angle, Confidence: confidence, Time: at}
+}
+
+// rememberSpot counts a person sighting, binning sightings within bin radians
+func (m *searchMemory) rememberSpot(angle, bin float64, at time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range m.spots {
+		s := &m.spots[i]
+		if math.Abs(s.Angle-angle) <= bin/2 {
+			s.Count++
+			s.Angle += (angle - s.Angle) / float64(s.Count)
+			s.LastSeen = at
+			return
+		}
+	}
+
+	spot := SearchSpot{Angle: angle, Count: 1, LastSeen: at}
+	if len(m.spots) < maxSearchSpots {
+		m.spots = append(m.spots, spot)
+		return
+	}
+
+	// Full: replace the stalest spot
+	oldest := 0
+	for i := range m.spots {
+		if m.spots[i].LastSeen.Before(m.spots[oldest].LastSeen) {
+			oldest = i
+		}
+	}
+	m.spots[oldest] = spot
+}
+
+// clues returns a snapshot of the remembered clues
+func (m *searchMemory) clues() SearchClues {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c := SearchClues{Spots: append([]SearchSpot(nil), m.spots...)}
+	if m.target != nil {
+		tr := *m.target
+		c.Target = &tr
+	}
+	if m.voice != nil {
+		v := *m.voice
+		c.Voice = &v
+	}
+	return c
+}
+
+// rememberSightings feeds this frame's people into the search memory.
+// best is the index of the face being followed (-1 = none).
+func (t *Tracker) rememberSightings(obs []worldmodel.Observation, ids []string, best int) {
+	for i, o := range obs {
+		if o.Kind != worldmodel.TrackPerson {
+			continue
+		}
+		t.search.rememberSpot(o.WorldAngle, t.config.SearchMergeDistance, o.Time)
+		if i == best {
+			if tr := t.world.GetTrack(ids[i]); tr != nil {
+				t.search.rememberTarget(tr)
+			}
+		}
+	}
+}
+
+// rememberVoice records the current voice direction in room coordinates.
+// audioAngle is relative to the head, as in updateTracking.
+func (t *Tracker) rememberVoice(audioAngle float64) {
+	audio := t.world.GetAudioSource()
+	if audio == nil {
+		return
+	}
+	angle := t.world.GetBodyYaw() + t.controller.GetCurrentYaw() + audioAngle
+	t.search.rememberVoice(angle, audio.Confidence, time.Now())
+}
+
+// bestFaceIndex returns the index of the face perception follows, or -1
+func bestFaceIndex(faces []detection.Detection) int {
+	best := detection.SelectBest(faces)
+	for i := range faces {
+		if &faces[i] == best {
+			return i
+		}
+	}
+	return -1
+}
+
+// planSearch builds a search plan from the remembered clues
+func (t *Tracker) planSearch() []LookPoint {
+	t.mu.RLock()
+	canRotate := t.onBodyRotation != nil
+	t.mu.RUnlock()
+
+	clues := t.search.clues()
+	clues.BodyYaw = t.world.GetBodyYaw()
+	clues.CanRotateBody = canRotate
+	return PlanSearch(clues, t.config, time.Now())
+}
+
+// startSearch is the StateSearching entry action
+func (t *Tracker) startSearch() {
+	t.searchPlan = t.planSearch()
+	t.searchIndex = 0
+	t.searchStartedAt = time.Now()
+	t.searchArrivedAt = time.Time{}
+	t.searchHolding = false
+
+	if len(t.searchPlan) == 0 {
+		debug.Logln("🔍 No clues where the target went")
+		return
+	}
+	debug.Log("🔍 Searching %d places (first: %s at %.2f rad)\n",
+		len(t.searchPlan), t.searchPlan[0].Source, t.searchPlan[0].Angle)
+	if t.state != nil {
+		t.state.AddLog("search", fmt.Sprintf("Searching %d places for lost target", len(t.searchPlan)))
+	}
+}
+
+// updateSearching looks at each planned point in turn, rotating the body
+// when the head cannot reach, and gives up once the plan is exhausted or
+// SearchMaxDuration has passed
+func (t *Tracker) updateSearching() {
+	if t.searchIndex >= len(t.searchPlan) || time.Since(t.searchStartedAt) > t.config.SearchMaxDuration {
+		t.giveUpSearch()
+		return
+	}
+
+	p := t.searchPlan[t.searchIndex]
+	bodyDone := t.stepBodyToward(p.BodyYaw)
+
+	headTarget := p.Angle - t.world.GetBodyYaw()
+	t.controller.SetTarget(headTarget)
+	t.controller.SetTargetPitch(0)
+	newYaw, _ := t.controller.Update()
+	newPitch, _ := t.controller.UpdatePitch()
+	t.outputPose(newYaw, newPitch, headTarget)
+
+	if !bodyDone || math.Abs(t.controller.GetCurrentYaw()-headTarget) > t.config.SearchArriveTolerance {
+		return
+	}
+
+	// Arrived: dwell, then move on to the next point
+	if t.searchArrivedAt.IsZero() {
+		t.searchArrivedAt = time.Now()
+		debug.Log("🔍 Looking at %s point (%.2f rad)\n", p.Source, p.Angle)
+		return
+	}
+	if time.Since(t.searchArrivedAt) >= t.config.SearchDwell {
+		t.searchIndex++
+		t.searchArrivedAt = time.Time{}
+	}
+}
+
+// giveUpSearch applies the configured give-up behaviour
+func (t *Tracker) giveUpSearch() {
+	if t.config.SearchGiveUp == SearchGiveUpHold {
+		if !t.searchHolding {
+			t.searchHolding = true
+			debug.Logln("🔍 Search failed, holding position")
+		}
+		return
+	}
+	if len(t.searchPlan) > 0 {
+		debug.Logln("🔍 Search failed, falling back to sweep")
+	}
+	t.machine().Fire(EventSearchFailed)
+}
+
+// stepBodyToward rotates the body one step toward target, counter-rotating
+// the head. Returns true once the body is there (or cannot move further).
+func (t *Tracker) stepBodyToward(target float64) bool {
+	t.mu.RLock()
+	handler := t.onBodyRotation
+	t.mu.RUnlock()
+
+	remaining := target - t.world.GetBodyYaw()
+	if handler == nil || math.Abs(remaining) <= t.config.BodyAlignmentDeadZone {
+		return true
+	}
+
+	step := t.config.BodyAlignmentSpeed * t.config.MovementInterval.Seconds()
+	actual := handler(clamp(remaining, -step, step))
+	if math.Abs(actual) < 0.001 {
+		return true // Body at its limit
+	}
+	t.controller.AdjustForBodyRotation(actual)
+	return false
+}
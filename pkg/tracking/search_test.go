@@ -0,0 +1,253 @@
+package tracking
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/teslashibe/go-reachy/pkg/worldmodel"
+)
+
+func TestPlanSearch(t *testing.T) {
+	cfg := DefaultConfig()
+	now := time.Now()
+
+	moving := &worldmodel.Track{ID: "person-1", WorldAngle: 0.2, Velocity: 0.5, LastSeen: now.Add(-2 * time.Second)}
+	still := &worldmodel.Track{ID: "person-2", WorldAngle: -0.3, LastSeen: now.Add(-2 * time.Second)}
+
+	tests := []struct {
+		name        string
+		clues       SearchClues
+		wantSources []string
+		wantAngles  []float64
+	}{
+		{
+			name: "no clues",
+		},
+		{
+			name:        "moving target predicted then last seen",
+			clues:       SearchClues{Target: moving},
+			wantSources: []string{SourcePredicted, SourceLastSeen},
+			wantAngles:  []float64{0.7, 0.2}, // Extrapolation capped at SearchPredictHorizon (1s)
+		},
+		{
+			name:        "still target merges prediction",
+			clues:       SearchClues{Target: still},
+			wantSources: []string{SourcePredicted},
+			wantAngles:  []float64{-0.3},
+		},
+		{
+			name: "voice after loss ranks first",
+			clues: SearchClues{
+				Target: still,
+				Voice:  &VoiceClue{Angle: 1.0, Confidence: 0.9, Time: now.Add(-time.Second)},
+			},
+			wantSources: []string{SourceVoice, SourcePredicted},
+			wantAngles:  []float64{1.0, -0.3},
+		},
+		{
+			name: "older voice ranks after target",
+			clues: SearchClues{
+				Target: still,
+				Voice:  &VoiceClue{Angle: 1.0, Confidence: 0.9, Time: now.Add(-5 * time.Second)},
+			},
+			wantSources: []string{SourcePredicted, SourceVoice},
+			wantAngles:  []float64{-0.3, 1.0},
+		},
+		{
+			name: "stale clues ignored",
+			clues: SearchClues{
+				Target: &worldmodel.Track{WorldAngle: 0.4, LastSeen: now.Add(-time.Minute)},
+				Voice:  &VoiceClue{Angle: 1.0, Confidence: 0.9, Time: now.Add(-time.Minute)},
+				Spots:  []SearchSpot{{Angle: 0.8, Count: 10, LastSeen: now.Add(-time.Hour)}},
+			},
+		},
+		{
+			name: "memory ranked by sightings",
+			clues: SearchClues{
+				Spots: []SearchSpot{
+					{Angle: -0.8, Count: 3, LastSeen: now},
+					{Angle: 0.6, Count: 30, LastSeen: now},
+				},
+			},
+			wantSources: []string{SourceMemory, SourceMemory},
+			wantAngles:  []float64{0.6, -0.8},
+		},
+		{
+			name: "max points",
+			clues: SearchClues{
+				Target: moving,
+				Voice:  &VoiceClue{Angle: -1.0, Confidence: 0.5, Time: now},
+				Spots: []SearchSpot{
+					{Angle: 1.2, Count: 5, LastSeen: now},
+					{Angle: -0.5, Count: 4, LastSeen: now},
+				},
+			},
+			wantSources: []string{SourceVoice, SourcePredicted, SourceLastSeen, SourceMemory},
+			wantAngles:  []float64{-1.0, 0.7, 0.2, 1.2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := PlanSearch(tt.clues, cfg, now)
+			if len(plan) != len(tt.wantSources) {
+				t.Fatalf("plan = %+v, want %d points", plan, len(tt.wantSources))
+			}
+			for i, p := range plan {
+				if p.Source != tt.wantSources[i] {
+					t.Errorf("plan[%d].Source = %s, want %s", i, p.Source, tt.wantSources[i])
+				}
+				if !floatNear(p.Angle, tt.wantAngles[i], 1e-9) {
+					t.Errorf("plan[%d].Angle = %v, want %v", i, p.Angle, tt.wantAngles[i])
+				}
+				if i > 0 && p.Priority > plan[i-1].Priority {
+					t.Errorf("plan[%d] priority %v above plan[%d] %v", i, p.Priority, i-1, plan[i-1].Priority)
+				}
+			}
+		})
+	}
+}
+
+func TestPlanSearch_BodyRotation(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.YawRange = 1.0
+	now := time.Now()
+	headRange := cfg.YawRange * 0.9
+	behind := &VoiceClue{Angle: 2.5, Confidence: 1, Time: now}
+
+	tests := []struct {
+		name      string
+		canRotate bool
+		bodyYaw   float64
+		wantAngle float64
+		wantBody  float64
+	}{
+		{"within head range", true, 1.8, 2.5, 1.8},
+		{"body turns", true, 0, 2.5, 2.5 - headRange},
+		{"body cannot turn", false, 0, headRange, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := PlanSearch(SearchClues{Voice: behind, BodyYaw: tt.bodyYaw, CanRotateBody: tt.canRotate}, cfg, now)
+			if len(plan) != 1 {
+				t.Fatalf("plan = %+v, want 1 point", plan)
+			}
+			if !floatNear(plan[0].Angle, tt.wantAngle, 1e-9) || !floatNear(plan[0].BodyYaw, tt.wantBody, 1e-9) {
+				t.Errorf("point = angle %v body %v, want %v / %v", plan[0].Angle, plan[0].BodyYaw, tt.wantAngle, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestSearchMemory_Spots(t *testing.T) {
+	var m searchMemory
+	now := time.Now()
+
+	m.rememberSpot(0.50, 0.25, now)
+	m.rememberSpot(0.60, 0.25, now)
+	m.rememberSpot(-0.5, 0.25, now)
+
+	c := m.clues()
+	if len(c.Spots) != 2 {
+		t.Fatalf("spots = %+v, want 2", c.Spots)
+	}
+	if c.Spots[0].Count != 2 || !floatNear(c.Spots[0].Angle, 0.55, 1e-9) {
+		t.Errorf("spot[0] = %+v, want count 2 at 0.55", c.Spots[0])
+	}
+
+	// Full memory replaces the stalest spot
+	for i := 0; i < maxSearchSpots; i++ {
+		m.rememberSpot(float64(i), 0.25, now.Add(time.Duration(i+1)*time.Second))
+	}
+	c = m.clues()
+	if len(c.Spots) != maxSearchSpots {
+		t.Fatalf("len(spots) = %d, want %d", len(c.Spots), maxSearchSpots)
+	}
+	for _, s := range c.Spots {
+		if s.Angle == -0.5 {
+			t.Error("stalest spot was not evicted")
+		}
+	}
+}
+
+func TestTracker_Search(t *testing.T) {
+	tr := newStateTestTracker()
+	tr.config.ScanStartDelay = 0
+	tr.config.SearchDwell = 0
+	tr.config.YawRange = 1.0
+	tr.controller.MaxYaw = 1.0
+
+	var bodyCalls int
+	tr.SetBodyRotationHandler(func(delta float64) float64 {
+		bodyCalls++
+		tr.world.SetBodyYaw(tr.world.GetBodyYaw() + delta)
+		return delta
+	})
+
+	// Voice behind the head's reach: the body has to turn
+	tr.search.rememberVoice(2.0, 0.9, time.Now())
+	tr.machine().reset(StateIdle)
+	tr.lastFaceSeenAt = time.Now().Add(-time.Second)
+
+	visited := false
+	for i := 0; i < 2000 && tr.GetState() != StateReturning; i++ {
+		tr.updateMovement()
+		if !tr.searchArrivedAt.IsZero() {
+			visited = true
+			room := tr.world.GetBodyYaw() + tr.controller.GetCurrentYaw()
+			if math.Abs(room-2.0) > tr.config.SearchArriveTolerance {
+				t.Errorf("arrived looking at %.2f, want 2.0", room)
+			}
+		}
+	}
+
+	if !visited {
+		t.Error("search never arrived at the voice direction")
+	}
+	if bodyCalls == 0 {
+		t.Error("body never rotated toward the point")
+	}
+	if got := tr.GetState(); got != StateReturning {
+		t.Errorf("after search: state = %s, want returning", got)
+	}
+}
+
+func TestTracker_SearchGiveUpHold(t *testing.T) {
+	tr := newStateTestTracker()
+	tr.config.SearchGiveUp = SearchGiveUpHold
+	tr.machine().reset(StateSearching)
+	tr.startSearch() // No clues: plan is empty
+
+	for i := 0; i < 5; i++ {
+		tr.updateMovement()
+	}
+	if got := tr.GetState(); got != StateSearching {
+		t.Errorf("state = %s, want searching (holding)", got)
+	}
+	if !tr.searchHolding {
+		t.Error("searchHolding = false, want true")
+	}
+}
+
+func TestTracker_SearchTuning(t *testing.T) {
+	tr := newStateTestTracker()
+	tr.perception = &Perception{}
+
+	off := false
+	tr.SetTuningParams(TuningParams{SearchEnabled: &off, SearchDwell: 2.5, SearchGiveUp: "hold"})
+	if tr.config.SearchEnabled || tr.config.SearchDwell != 2500*time.Millisecond || tr.config.SearchGiveUp != SearchGiveUpHold {
+		t.Errorf("config = enabled %v dwell %v give up %q", tr.config.SearchEnabled, tr.config.SearchDwell, tr.config.SearchGiveUp)
+	}
+
+	tr.SetTuningParams(TuningParams{SearchGiveUp: "bogus"})
+	if tr.config.SearchGiveUp != SearchGiveUpHold {
+		t.Errorf("invalid give up changed config to %q", tr.config.SearchGiveUp)
+	}
+
+	p := tr.GetTuningParams()
+	if p.SearchEnabled == nil || *p.SearchEnabled || p.SearchGiveUp != "hold" {
+		t.Errorf("GetTuningParams search = %v / %q", p.SearchEnabled, p.SearchGiveUp)
+	}
+}
@@ -0,0 +1,171 @@
+package tracking
+
+import (
+	"sync"
+	"time"
+)
+
+// State is a tracker behaviour. Exactly one is active at a time.
+type State string
+
+// Event triggers a transition between states
+type Event string
+
+// AnyState matches every source state in AddTransition (except the target
+// state itself, so self-transitions never fire from a wildcard)
+const AnyState State = "*"
+
+// maxHistory is how many transitions the machine remembers
+const maxHistory = 50
+
+// TransitionRecord is one entry in the transition history
+type TransitionRecord struct {
+	From  State     `json:"from"`
+	To    State     `json:"to"`
+	Event Event     `json:"event"`
+	Time  time.Time `json:"time"`
+}
+
+// transition is one row of the transition table
+type transition struct {
+	to    State
+	guard func() bool // nil = always allowed
+}
+
+// StateMachine is a small table-driven finite state machine with guards,
+// entry/exit actions and a bounded transition history. Fire, Current and
+// History are safe for concurrent use; actions run on the goroutine that
+// calls Fire, outside the machine's lock.
+type StateMachine struct {
+	mu        sync.RWMutex
+	current   State
+	enteredAt time.Time
+	table     map[State]map[Event][]transition
+	onEnter   map[State]func(from State, ev Event)
+	onExit    map[State]func(to State, ev Event)
+	history   []TransitionRecord
+}
+
+// NewStateMachine creates a machine in the initial state
+func NewStateMachine(initial State) *StateMachine {
+	return &StateMachine{
+		current:   initial,
+		enteredAt: time.Now(),
+		table:     make(map[State]map[Event][]transition),
+		onEnter:   make(map[State]func(State, Event)),
+		onExit:    make(map[State]func(State, Event)),
+	}
+}
+
+// AddTransition registers from --ev--> to. Several transitions may share
+// (from, ev); they are tried in registration order and the first whose
+// guard passes wins. Specific states take priority over AnyState.
+func (m *StateMachine) AddTransition(from State, ev Event, to State, guard func() bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.table[from] == nil {
+		m.table[from] = make(map[Event][]transition)
+	}
+	m.table[from][ev] = append(m.table[from][ev], transition{to: to, guard: guard})
+}
+
+// OnEnter sets the entry action for a state
+func (m *StateMachine) OnEnter(s State, fn func(from State, ev Event)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onEnter[s] = fn
+}
+
+// OnExit sets the exit action for a state
+func (m *StateMachine) OnExit(s State, fn func(to State, ev Event)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onExit[s] = fn
+}
+
+// Fire applies an event. Returns true if a transition was taken, in which
+// case the old state's exit action and the new state's entry action have run.
+// Events with no matching transition (or failing guards) are ignored.
+func (m *StateMachine) Fire(ev Event) bool {
+	m.mu.Lock()
+	from := m.current
+	candidates := append([]transition(nil), m.table[from][ev]...)
+	for _, tr := range m.table[AnyState][ev] {
+		if tr.to != from {
+			candidates = append(candidates, tr)
+		}
+	}
+	m.mu.Unlock()
+
+	// Guards run unlocked so they may inspect the machine
+	var to State
+	found := false
+	for _, tr := range candidates {
+		if tr.guard == nil || tr.guard() {
+			to, found = tr.to, true
+			break
+		}
+	}
+	if !found {
+		return false
+	}
+
+	m.mu.Lock()
+	if m.current != from {
+		// Another goroutine transitioned while guards ran
+		m.mu.Unlock()
+		return false
+	}
+	m.current = to
+	m.enteredAt = time.Now()
+	m.history = append(m.history, TransitionRecord{From: from, To: to, Event: ev, Time: m.enteredAt})
+	if len(m.history) > maxHistory {
+		m.history = m.history[len(m.history)-maxHistory:]
+	}
+	exit := m.onExit[from]
+	enter := m.onEnter[to]
+	m.mu.Unlock()
+
+	if exit != nil {
+		exit(to, ev)
+	}
+	if enter != nil {
+		enter(from, ev)
+	}
+	return true
+}
+
+// Current returns the active state
+func (m *StateMachine) Current() State {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.current
+}
+
+// Is reports whether s is the active state
+func (m *StateMachine) Is(s State) bool {
+	return m.Current() == s
+}
+
+// TimeInState returns how long the machine has been in the current state
+func (m *StateMachine) TimeInState() time.Duration {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return time.Since(m.enteredAt)
+}
+
+// History returns recent transitions, oldest first
+func (m *StateMachine) History() []TransitionRecord {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]TransitionRecord(nil), m.history...)
+}
+
+// reset forces a state without running actions or recording history (tests)
+func (m *StateMachine) reset(s State) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.current = s
+	m.enteredAt = time.Now()
+}
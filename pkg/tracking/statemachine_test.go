@@ -0,0 +1,99 @@
+package tracking
+
+import (
+	"testing"
+)
+
+func TestStateMachine_Fire(t *testing.T) {
+	const (
+		a State = "a"
+		b State = "b"
+		c State = "c"
+	)
+	allow := true
+
+	tests := []struct {
+		name   string
+		from   State
+		event  Event
+		want   State
+		wantOK bool
+	}{
+		{"simple transition", a, "go", b, true},
+		{"unknown event ignored", a, "nope", a, false},
+		{"no transition from state", c, "go", c, false},
+		{"wildcard from any", b, "reset", a, true},
+		{"wildcard skips self", a, "reset", a, false},
+		{"specific beats wildcard", c, "reset", b, true},
+		{"guard selects first passing", b, "guarded", c, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewStateMachine(tt.from)
+			m.AddTransition(a, "go", b, nil)
+			m.AddTransition(AnyState, "reset", a, nil)
+			m.AddTransition(c, "reset", b, nil)
+			m.AddTransition(b, "guarded", a, func() bool { return !allow })
+			m.AddTransition(b, "guarded", c, func() bool { return allow })
+
+			ok := m.Fire(tt.event)
+			if ok != tt.wantOK {
+				t.Errorf("Fire(%q) = %v, want %v", tt.event, ok, tt.wantOK)
+			}
+			if got := m.Current(); got != tt.want {
+				t.Errorf("Current() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStateMachine_Actions(t *testing.T) {
+	m := NewStateMachine("a")
+	m.AddTransition("a", "go", "b", nil)
+
+	var calls []string
+	m.OnExit("a", func(to State, ev Event) {
+		calls = append(calls, "exit a→"+string(to)+" on "+string(ev))
+	})
+	m.OnEnter("b", func(from State, ev Event) {
+		// Actions run after the state changed
+		calls = append(calls, "enter b from "+string(from)+" now "+string(m.Current()))
+	})
+
+	m.Fire("go")
+
+	want := []string{"exit a→b on go", "enter b from a now b"}
+	if len(calls) != len(want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("calls[%d] = %q, want %q", i, calls[i], want[i])
+		}
+	}
+}
+
+func TestStateMachine_History(t *testing.T) {
+	m := NewStateMachine("a")
+	m.AddTransition("a", "go", "b", nil)
+	m.AddTransition("b", "back", "a", nil)
+
+	for i := 0; i < maxHistory; i++ {
+		m.Fire("go")
+		m.Fire("back")
+	}
+	m.Fire("nope") // Ignored events are not recorded
+
+	h := m.History()
+	if len(h) != maxHistory {
+		t.Fatalf("len(History) = %d, want %d", len(h), maxHistory)
+	}
+	last := h[len(h)-1]
+	if last.From != "b" || last.To != "a" || last.Event != "back" {
+		t.Errorf("last = %+v, want b→a on back", last)
+	}
+	if last.Time.IsZero() {
+		t.Error("last.Time is zero")
+	}
+}
@@ -0,0 +1,190 @@
+package tracking
+
+import (
+	"time"
+
+	"github.com/teslashibe/go-reachy/pkg/debug"
+)
+
+// Tracker behaviour states
+const (
+	StateIdle         State = "idle"          // No target, holding position during the grace period
+	StateTracking     State = "tracking"      // Following a face or voice
+	StateBodyAligning State = "body_aligning" // Tracking while the body rotates under the head
+	StateAudioSwitch  State = "audio_switch"  // Turning toward a new voice to look for its face
+	StateSearching    State = "searching"     // Looking where the lost target was last seen or heard
+	StateReturning    State = "returning"     // Interpolating back to neutral after losing the target
+	StateScanning     State = "scanning"      // Sweeping left/right looking for faces
+	StateBreathing    State = "breathing"     // Idle breathing animation
+	StateDisabled     State = "disabled"      // Tracking switched off, head parked at neutral
+	StateAutoTune     State = "autotune"      // Auto-tune owns the head
+)
+
+// Tracker events
+const (
+	EventTargetAcquired   Event = "target_acquired"    // Face or voice available
+	EventTargetLost       Event = "target_lost"        // Neither face nor voice
+	EventGraceExpired     Event = "grace_expired"      // ScanStartDelay passed without a face
+	EventSearchFailed     Event = "search_failed"      // Every search point failed (or timed out)
+	EventReturnComplete   Event = "return_complete"    // Head reached neutral
+	EventScanComplete     Event = "scan_complete"      // Finished a full scan cycle
+	EventAudioSwitchStart Event = "audio_switch_start" // Voice from a different direction
+	EventAudioSwitchEnd   Event = "audio_switch_end"   // Found face, timed out or voice stopped
+	EventAlignStart       Event = "align_start"        // Body began rotating under the head
+	EventAlignDone        Event = "align_done"         // Head and body centred (or face lost)
+	EventDisable          Event = "disable"            // SetEnabled(false)
+	EventEnable           Event = "enable"             // SetEnabled(true)
+	EventAutoTuneStart    Event = "autotune_start"     // AutoTune started
+	EventAutoTuneEnd      Event = "autotune_end"       // AutoTune finished
+)
+
+// StateInfo describes the tracker's behaviour state for the dashboard
+type StateInfo struct {
+	State       State              `json:"state"`
+	TimeInState float64            `json:"time_in_state"` // seconds
+	History     []TransitionRecord `json:"history"`
+}
+
+// GetStateInfo returns the current behaviour state and recent transitions
+func (t *Tracker) GetStateInfo() StateInfo {
+	m := t.machine()
+	return StateInfo{
+		State:       m.Current(),
+		TimeInState: m.TimeInState().Seconds(),
+		History:     m.History(),
+	}
+}
+
+// GetState returns the current behaviour state
+func (t *Tracker) GetState() State {
+	return t.machine().Current()
+}
+
+// machine returns the tracker's state machine, building it on first use
+func (t *Tracker) machine() *StateMachine {
+	t.smOnce.Do(func() {
+		t.sm = t.newStateMachine()
+	})
+	return t.sm
+}
+
+// newStateMachine builds the behaviour transition table:
+//
+//	idle ──grace_expired──▶ searching ──search_failed──▶ returning ──return_complete──▶ scanning ──scan_complete──▶ breathing
+//	  ▲                         │                           │                             │                          │
+//	  └──target_lost── tracking ◀────────────────────────── target_acquired ──────────────┴──────────────────────────┘
+//	                    │  ▲  ▲
+//	       align_start  │  │  └── audio_switch_end ── audio_switch ◀── audio_switch_start
+//	                    ▼  │
+//	               body_aligning ──align_done──▶ tracking
+//
+//	idle ──grace_expired──▶ returning               (search disabled)
+//	returning ──return_complete──▶ breathing        (give up "neutral")
+//
+//	any ──disable──▶ disabled ──enable──▶ idle
+//	any ──autotune_start──▶ autotune ──autotune_end──▶ idle
+//
+// Guards read tracker state that is only written on the Run goroutine.
+func (t *Tracker) newStateMachine() *StateMachine {
+	m := NewStateMachine(StateIdle)
+
+	// Losing and finding targets
+	for _, s := range []State{StateIdle, StateSearching, StateReturning, StateScanning, StateBreathing} {
+		m.AddTransition(s, EventTargetAcquired, StateTracking, nil)
+	}
+	m.AddTransition(StateTracking, EventTargetLost, StateIdle, nil)
+	m.AddTransition(StateBodyAligning, EventTargetLost, StateIdle, nil)
+
+	// Idle progression
+	graceExpired := func() bool {
+		return time.Since(t.lastFaceSeenAt) >= t.config.ScanStartDelay
+	}
+	m.AddTransition(StateIdle, EventGraceExpired, StateSearching, func() bool {
+		return graceExpired() && t.config.SearchEnabled
+	})
+	m.AddTransition(StateIdle, EventGraceExpired, StateReturning, graceExpired)
+	m.AddTransition(StateSearching, EventSearchFailed, StateReturning, nil)
+	m.AddTransition(StateReturning, EventReturnComplete, StateBreathing, func() bool {
+		return !t.controller.IsInterpolating() &&
+			t.config.SearchGiveUp == SearchGiveUpNeutral && t.config.BreathingEnabled
+	})
+	m.AddTransition(StateReturning, EventReturnComplete, StateScanning, func() bool {
+		return !t.controller.IsInterpolating() && t.config.SearchGiveUp != SearchGiveUpNeutral
+	})
+	m.AddTransition(StateScanning, EventScanComplete, StateBreathing, func() bool {
+		return t.scanCyclesDone >= 1 && t.config.BreathingEnabled
+	})
+
+	// Audio-triggered speaker switching
+	m.AddTransition(StateTracking, EventAudioSwitchStart, StateAudioSwitch, nil)
+	m.AddTransition(StateBodyAligning, EventAudioSwitchStart, StateAudioSwitch, nil)
+	m.AddTransition(StateAudioSwitch, EventAudioSwitchEnd, StateTracking, nil)
+
+	// Body alignment
+	m.AddTransition(StateTracking, EventAlignStart, StateBodyAligning, nil)
+	m.AddTransition(StateBodyAligning, EventAlignDone, StateTracking, nil)
+
+	// Master toggles
+	m.AddTransition(AnyState, EventDisable, StateDisabled, func() bool {
+		return !m.Is(StateAutoTune) // Auto-tune finishes first
+	})
+	m.AddTransition(StateDisabled, EventEnable, StateIdle, nil)
+	m.AddTransition(AnyState, EventAutoTuneStart, StateAutoTune, nil)
+	m.AddTransition(StateAutoTune, EventAutoTuneEnd, StateIdle, nil)
+
+	// Entry/exit actions
+	m.OnEnter(StateTracking, func(from State, ev Event) {
+		switch from {
+		case StateSearching, StateReturning, StateScanning, StateBreathing:
+			t.mu.RLock()
+			hasFace := t.hasFaceTarget
+			t.mu.RUnlock()
+			if hasFace {
+				debug.Logln("👁️  Found face, stopping idle behavior")
+			} else {
+				debug.Logln("🎤 Heard voice, turning toward sound")
+			}
+		}
+	})
+	m.OnEnter(StateSearching, func(from State, ev Event) {
+		t.startSearch()
+	})
+	m.OnEnter(StateReturning, func(from State, ev Event) {
+		t.controller.InterpolateToNeutral(1 * time.Second)
+		if from == StateSearching {
+			debug.Logln("👁️  Search over, returning to neutral")
+		} else {
+			debug.Logln("👁️  Grace period expired, returning to neutral")
+		}
+	})
+	m.OnEnter(StateScanning, func(from State, ev Event) {
+		t.scanStartTime = time.Now()
+		t.scanDirection = 1.0
+		t.scanCyclesDone = 0
+		debug.Logln("👀 Starting scan for faces...")
+		if t.state != nil {
+			t.state.AddLog("scan", "Scanning for faces")
+		}
+	})
+	m.OnEnter(StateBreathing, func(from State, ev Event) {
+		t.breathingPhase = 0
+		debug.Logln("😮‍💨 Scan complete, starting breathing animation")
+		if t.state != nil {
+			t.state.AddLog("breathing", "Breathing animation started")
+		}
+	})
+	m.OnExit(StateBreathing, func(to State, ev Event) {
+		t.breathingPhase = 0
+	})
+	m.OnEnter(StateAudioSwitch, func(from State, ev Event) {
+		t.mu.Lock()
+		t.audioSwitchStartedAt = time.Now()
+		t.mu.Unlock()
+	})
+	m.OnEnter(StateDisabled, func(from State, ev Event) {
+		t.controller.InterpolateToNeutral(1 * time.Second)
+		debug.Logln("👁️  Tracking disabled, returning to neutral")
+	})
+
+	return m
+}
@@ -0,0 +1,251 @@
+package tracking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/teslashibe/go-reachy/pkg/worldmodel"
+)
+
+func newStateTestTracker() *Tracker {
+	cfg := DefaultConfig()
+	return &Tracker{
+		config:        cfg,
+		world:         worldmodel.New(),
+		controller:    NewPDController(cfg),
+		lastLoggedYaw: 999.0,
+		isEnabled:     true,
+		isFaceEnabled: true,
+	}
+}
+
+func TestTracker_StateTransitions(t *testing.T) {
+	tests := []struct {
+		name  string
+		from  State
+		setup func(*Tracker)
+		event Event
+		want  State
+		check func(*testing.T, *Tracker)
+	}{
+		// Finding a target
+		{name: "idle finds target", from: StateIdle, event: EventTargetAcquired, want: StateTracking},
+		{name: "returning finds target", from: StateReturning, event: EventTargetAcquired, want: StateTracking},
+		{name: "scanning finds target", from: StateScanning, event: EventTargetAcquired, want: StateTracking},
+		{name: "searching finds target", from: StateSearching, event: EventTargetAcquired, want: StateTracking},
+		{
+			name: "breathing finds target", from: StateBreathing, event: EventTargetAcquired, want: StateTracking,
+			setup: func(tr *Tracker) { tr.breathingPhase = 1.5 },
+			check: func(t *testing.T, tr *Tracker) {
+				if tr.breathingPhase != 0 {
+					t.Errorf("breathingPhase = %v, want 0 after exit", tr.breathingPhase)
+				}
+			},
+		},
+		{name: "tracking ignores target acquired", from: StateTracking, event: EventTargetAcquired, want: StateTracking},
+
+		// Losing a target
+		{name: "tracking loses target", from: StateTracking, event: EventTargetLost, want: StateIdle},
+		{name: "body aligning loses target", from: StateBodyAligning, event: EventTargetLost, want: StateIdle},
+		{name: "audio switch keeps target", from: StateAudioSwitch, event: EventTargetLost, want: StateAudioSwitch},
+		{name: "idle ignores target lost", from: StateIdle, event: EventTargetLost, want: StateIdle},
+
+		// Idle progression with guards
+		{
+			name: "grace expired", from: StateIdle, event: EventGraceExpired, want: StateSearching,
+			setup: func(tr *Tracker) {
+				tr.lastFaceSeenAt = time.Now().Add(-time.Hour)
+				tr.search.rememberVoice(0.5, 0.8, time.Now())
+			},
+			check: func(t *testing.T, tr *Tracker) {
+				if len(tr.searchPlan) != 1 || tr.searchIndex != 0 || tr.searchStartedAt.IsZero() {
+					t.Errorf("search not started: plan %+v, index %d", tr.searchPlan, tr.searchIndex)
+				}
+			},
+		},
+		{
+			name: "grace expired without search", from: StateIdle, event: EventGraceExpired, want: StateReturning,
+			setup: func(tr *Tracker) {
+				tr.lastFaceSeenAt = time.Now().Add(-time.Hour)
+				tr.config.SearchEnabled = false
+			},
+			check: func(t *testing.T, tr *Tracker) {
+				if !tr.controller.IsInterpolating() {
+					t.Error("entering returning should start interpolation to neutral")
+				}
+			},
+		},
+		{
+			name: "grace not expired", from: StateIdle, event: EventGraceExpired, want: StateIdle,
+			setup: func(tr *Tracker) { tr.lastFaceSeenAt = time.Now() },
+		},
+		{
+			name: "search failed", from: StateSearching, event: EventSearchFailed, want: StateReturning,
+			check: func(t *testing.T, tr *Tracker) {
+				if !tr.controller.IsInterpolating() {
+					t.Error("entering returning should start interpolation to neutral")
+				}
+			},
+		},
+		{name: "idle ignores search failed", from: StateIdle, event: EventSearchFailed, want: StateIdle},
+		{
+			name: "return complete, give up neutral", from: StateReturning, event: EventReturnComplete, want: StateBreathing,
+			setup: func(tr *Tracker) { tr.config.SearchGiveUp = SearchGiveUpNeutral },
+		},
+		{
+			name: "return complete, give up neutral without breathing", from: StateReturning, event: EventReturnComplete, want: StateReturning,
+			setup: func(tr *Tracker) {
+				tr.config.SearchGiveUp = SearchGiveUpNeutral
+				tr.config.BreathingEnabled = false
+			},
+		},
+		{
+			name: "return complete", from: StateReturning, event: EventReturnComplete, want: StateScanning,
+			setup: func(tr *Tracker) { tr.scanCyclesDone = 3; tr.scanDirection = -1 },
+			check: func(t *testing.T, tr *Tracker) {
+				if tr.scanCyclesDone != 0 || tr.scanDirection != 1 {
+					t.Errorf("scan state = cycles %d dir %v, want 0/1", tr.scanCyclesDone, tr.scanDirection)
+				}
+			},
+		},
+		{
+			name: "return still interpolating", from: StateReturning, event: EventReturnComplete, want: StateReturning,
+			setup: func(tr *Tracker) { tr.controller.InterpolateToNeutral(time.Hour) },
+		},
+		{
+			name: "scan complete", from: StateScanning, event: EventScanComplete, want: StateBreathing,
+			setup: func(tr *Tracker) { tr.scanCyclesDone = 1; tr.breathingPhase = 2 },
+			check: func(t *testing.T, tr *Tracker) {
+				if tr.breathingPhase != 0 {
+					t.Errorf("breathingPhase = %v, want 0 on entry", tr.breathingPhase)
+				}
+			},
+		},
+		{
+			name: "scan complete without breathing", from: StateScanning, event: EventScanComplete, want: StateScanning,
+			setup: func(tr *Tracker) { tr.scanCyclesDone = 1; tr.config.BreathingEnabled = false },
+		},
+		{name: "scan not yet complete", from: StateScanning, event: EventScanComplete, want: StateScanning},
+
+		// Audio switch
+		{
+			name: "tracking starts audio switch", from: StateTracking, event: EventAudioSwitchStart, want: StateAudioSwitch,
+			check: func(t *testing.T, tr *Tracker) {
+				if tr.audioSwitchStartedAt.IsZero() {
+					t.Error("audioSwitchStartedAt not set on entry")
+				}
+			},
+		},
+		{name: "body aligning starts audio switch", from: StateBodyAligning, event: EventAudioSwitchStart, want: StateAudioSwitch},
+		{name: "idle cannot audio switch", from: StateIdle, event: EventAudioSwitchStart, want: StateIdle},
+		{name: "audio switch ends", from: StateAudioSwitch, event: EventAudioSwitchEnd, want: StateTracking},
+
+		// Body alignment
+		{name: "align start", from: StateTracking, event: EventAlignStart, want: StateBodyAligning},
+		{name: "align done", from: StateBodyAligning, event: EventAlignDone, want: StateTracking},
+		{name: "align done ignored when tracking", from: StateTracking, event: EventAlignDone, want: StateTracking},
+
+		// Master toggles
+		{
+			name: "disable from tracking", from: StateTracking, event: EventDisable, want: StateDisabled,
+			check: func(t *testing.T, tr *Tracker) {
+				if !tr.controller.IsInterpolating() {
+					t.Error("entering disabled should start interpolation to neutral")
+				}
+			},
+		},
+		{name: "disable from breathing", from: StateBreathing, event: EventDisable, want: StateDisabled},
+		{name: "disable waits for autotune", from: StateAutoTune, event: EventDisable, want: StateAutoTune},
+		{name: "disable when disabled", from: StateDisabled, event: EventDisable, want: StateDisabled},
+		{name: "enable", from: StateDisabled, event: EventEnable, want: StateIdle},
+		{name: "enable ignored when tracking", from: StateTracking, event: EventEnable, want: StateTracking},
+		{name: "autotune start", from: StateScanning, event: EventAutoTuneStart, want: StateAutoTune},
+		{name: "autotune end", from: StateAutoTune, event: EventAutoTuneEnd, want: StateIdle},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := newStateTestTracker()
+			tr.machine().reset(tt.from)
+			if tt.setup != nil {
+				tt.setup(tr)
+			}
+
+			tr.machine().Fire(tt.event)
+
+			if got := tr.GetState(); got != tt.want {
+				t.Errorf("%s --%s--> %s, want %s", tt.from, tt.event, got, tt.want)
+			}
+			if tt.check != nil {
+				tt.check(t, tr)
+			}
+		})
+	}
+}
+
+func TestTracker_StateFlow(t *testing.T) {
+	tr := newStateTestTracker()
+	tr.config.ScanStartDelay = 0
+
+	// Face appears
+	tr.hasFaceTarget = true
+	tr.lastYawOffset = 0.2
+	tr.updateMovement()
+	if got := tr.GetState(); got != StateTracking {
+		t.Fatalf("with face: state = %s, want tracking", got)
+	}
+
+	// Face lost: idle, then the (zero) grace period expires; with no
+	// clues the search fails at once and falls back to the sweep
+	tr.hasFaceTarget = false
+	tr.updateMovement()
+	tr.updateMovement()
+	tr.updateMovement()
+	if got := tr.GetState(); got != StateReturning {
+		t.Fatalf("after losing face: state = %s, want returning", got)
+	}
+
+	// Disabled from anywhere
+	tr.SetEnabled(false)
+	tr.updateMovement()
+	if got := tr.GetState(); got != StateDisabled {
+		t.Fatalf("disabled: state = %s, want disabled", got)
+	}
+
+	tr.SetEnabled(true)
+	tr.updateMovement()
+	if got := tr.GetState(); got == StateDisabled {
+		t.Fatal("re-enabled: still disabled")
+	}
+
+	info := tr.GetStateInfo()
+	if info.State != tr.GetState() {
+		t.Errorf("GetStateInfo().State = %s, want %s", info.State, tr.GetState())
+	}
+	wantPath := []State{StateTracking, StateIdle, StateSearching, StateReturning, StateDisabled, StateIdle}
+	if len(info.History) < len(wantPath) {
+		t.Fatalf("history = %+v, want at least %d entries", info.History, len(wantPath))
+	}
+	for i, want := range wantPath {
+		if info.History[i].To != want {
+			t.Errorf("History[%d].To = %s, want %s", i, info.History[i].To, want)
+		}
+	}
+}
+
+func TestTracker_AutoTuneFlagSuspendsTracking(t *testing.T) {
+	tr := newStateTestTracker()
+	tr.hasFaceTarget = true
+	tr.isAutoTuning = true
+
+	tr.updateMovement()
+	if got := tr.GetState(); got != StateAutoTune {
+		t.Fatalf("state = %s, want autotune", got)
+	}
+
+	tr.isAutoTuning = false
+	tr.updateMovement()
+	if got := tr.GetState(); got != StateTracking {
+		t.Errorf("after autotune: state = %s, want tracking", got)
+	}
+}
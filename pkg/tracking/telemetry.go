@@ -0,0 +1,73 @@
+package tracking
+
+import (
+	"github.com/teslashibe/go-reachy/pkg/tracking/telemetry"
+)
+
+// SetTelemetry attaches a recorder that receives one record per movement
+// tick (nil disables telemetry)
+func (t *Tracker) SetTelemetry(r *telemetry.Recorder) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.telemetry = r
+}
+
+// GetTelemetry returns the attached recorder, or nil
+func (t *Tracker) GetTelemetry() *telemetry.Recorder {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.telemetry
+}
+
+// recordTelemetry captures the state after a movement tick.
+// Runs on the Run goroutine, like updateMovement.
+func (t *Tracker) recordTelemetry() {
+	t.mu.RLock()
+	rec := t.telemetry
+	detectSeq := t.detectSeq
+	hasFace := t.hasFaceTarget
+	yawOffset := t.lastYawOffset
+	pitchOffset := t.lastPitchOffset
+	cmdYaw := t.lastOutputYaw
+	cmdPitch := t.lastOutputPitch
+	t.mu.RUnlock()
+
+	if rec == nil {
+		return
+	}
+
+	r := telemetry.Record{
+		Mode:         string(t.machine().Current()),
+		HasTarget:    hasFace,
+		YawOffset:    yawOffset,
+		PitchOffset:  pitchOffset,
+		TargetYaw:    t.controller.GetTargetYaw(),
+		YawError:     t.controller.GetError(),
+		TargetPitch:  t.controller.GetTargetPitch(),
+		CommandYaw:   cmdYaw,
+		CommandPitch: cmdPitch,
+		BodyYaw:      t.world.GetBodyYaw(),
+	}
+
+	// Attach detections only on the first tick after a new detection
+	if detectSeq != t.telemetryDetectSeq {
+		t.telemetryDetectSeq = detectSeq
+		r.Fresh = true
+		if t.perception != nil {
+			for _, d := range t.perception.GetLastDetections() {
+				r.Detections = append(r.Detections, telemetry.Detection{
+					X: d.X, Y: d.Y, W: d.W, H: d.H, Confidence: d.Confidence,
+				})
+			}
+		}
+	}
+
+	if audio := t.world.GetAudioSource(); audio != nil {
+		r.HasAudio = true
+		r.AudioAngle = audio.Angle
+		r.AudioConfidence = audio.Confidence
+		r.AudioSpeaking = audio.Speaking
+	}
+
+	rec.Add(r)
+}
@@ -0,0 +1,236 @@
+package telemetry
+
+import (
+	"encoding/csv"
+	"io"
+	"math"
+	"strconv"
+	"time"
+)
+
+// AnalyzeConfig controls how responses are segmented
+type AnalyzeConfig struct {
+	StepThreshold float64       // |offset| that starts a response event (radians)
+	SettleBand    float64       // |offset| considered centred (radians)
+	SettleHold    time.Duration // Time offset must stay in band to count as settled
+}
+
+// DefaultAnalyzeConfig matches the production dead zones (~3° yaw / 0.45 scale)
+func DefaultAnalyzeConfig() AnalyzeConfig {
+	return AnalyzeConfig{
+		StepThreshold: 0.20,
+		SettleBand:    0.12,
+		SettleHold:    500 * time.Millisecond,
+	}
+}
+
+// AxisStats summarises responses on one axis
+type AxisStats struct {
+	Events        int           `json:"events"`         // Large offsets the tracker had to correct
+	Settled       int           `json:"settled"`        // Events that settled inside the band
+	MeanSettling  time.Duration `json:"mean_settling"`  // Over settled events
+	MaxSettling   time.Duration `json:"max_settling"`   // Over settled events
+	MeanOvershoot float64       `json:"mean_overshoot"` // Fraction of the initial offset
+	MaxOvershoot  float64       `json:"max_overshoot"`
+	Jitter        float64       `json:"jitter"` // RMS command change per tick while centred (radians)
+}
+
+// Summary is the result of analysing a telemetry recording
+type Summary struct {
+	Records        int                      `json:"records"`
+	Duration       time.Duration            `json:"duration"`
+	TickHz         float64                  `json:"tick_hz"`
+	DetectionHz    float64                  `json:"detection_hz"`
+	TargetFraction float64                  `json:"target_fraction"` // Ticks with a face target
+	Modes          map[string]time.Duration `json:"modes"`           // Time spent per mode
+	Yaw            AxisStats                `json:"yaw"`
+	Pitch          AxisStats                `json:"pitch"`
+}
+
+// Analyze computes settling, overshoot and jitter statistics.
+// Records must be in time order.
+func Analyze(records []Record, cfg AnalyzeConfig) Summary {
+	s := Summary{Records: len(records), Modes: make(map[string]time.Duration)}
+	if len(records) == 0 {
+		return s
+	}
+
+	s.Duration = records[len(records)-1].Time.Sub(records[0].Time)
+
+	var targets, fresh int
+	for i, r := range records {
+		if r.HasTarget {
+			targets++
+		}
+		if r.Fresh {
+			fresh++
+		}
+		if i > 0 {
+			s.Modes[records[i-1].Mode] += r.Time.Sub(records[i-1].Time)
+		}
+	}
+	s.TargetFraction = float64(targets) / float64(len(records))
+	if secs := s.Duration.Seconds(); secs > 0 {
+		s.TickHz = float64(len(records)-1) / secs
+		s.DetectionHz = float64(fresh) / secs
+	}
+
+	s.Yaw = analyzeAxis(records, cfg,
+		func(r Record) float64 { return r.YawOffset },
+		func(r Record) float64 { return r.CommandYaw })
+	s.Pitch = analyzeAxis(records, cfg,
+		func(r Record) float64 { return r.PitchOffset },
+		func(r Record) float64 { return r.CommandPitch })
+	return s
+}
+
+// analyzeAxis segments one axis into response events.
+// An event starts when the offset leaves the threshold while the target is
+// held; it settles once the offset stays inside the band for SettleHold.
+// Losing the target ends an event unsettled.
+func analyzeAxis(records []Record, cfg AnalyzeConfig, offset, command func(Record) float64) AxisStats {
+	var st AxisStats
+
+	var (
+		inEvent     bool
+		start       time.Time
+		sign        float64
+		initial     float64
+		overshoot   float64
+		enteredBand time.Time // Zero while outside band
+	)
+
+	var settlingSum time.Duration
+	var overshootSum float64
+	var jitterSum float64
+	var jitterN int
+
+	finish := func(settledAt time.Time) {
+		inEvent = false
+		overshootSum += overshoot
+		if overshoot > st.MaxOvershoot {
+			st.MaxOvershoot = overshoot
+		}
+		if settledAt.IsZero() {
+			return
+		}
+		st.Settled++
+		d := settledAt.Sub(start)
+		settlingSum += d
+		if d > st.MaxSettling {
+			st.MaxSettling = d
+		}
+	}
+
+	for i, r := range records {
+		off := offset(r)
+
+		if !r.HasTarget {
+			if inEvent {
+				finish(time.Time{})
+			}
+			continue
+		}
+
+		if !inEvent {
+			if math.Abs(off) > cfg.StepThreshold {
+				inEvent = true
+				st.Events++
+				start = r.Time
+				sign = math.Copysign(1, off)
+				initial = math.Abs(off)
+				overshoot = 0
+				enteredBand = time.Time{}
+				continue
+			}
+
+			// Steady tracking: accumulate command chatter
+			if i > 0 && records[i-1].HasTarget && math.Abs(off) <= cfg.SettleBand {
+				d := command(r) - command(records[i-1])
+				jitterSum += d * d
+				jitterN++
+			}
+			continue
+		}
+
+		if math.Abs(off) > initial && off*sign > 0 {
+			initial = math.Abs(off) // Still growing (target moving away)
+		}
+		if off*sign < 0 {
+			if os := -off * sign / initial; os > overshoot {
+				overshoot = os
+			}
+		}
+
+		if math.Abs(off) <= cfg.SettleBand {
+			if enteredBand.IsZero() {
+				enteredBand = r.Time
+			}
+			if r.Time.Sub(enteredBand) >= cfg.SettleHold {
+				finish(enteredBand)
+			}
+		} else {
+			enteredBand = time.Time{}
+		}
+	}
+	if inEvent {
+		finish(time.Time{})
+	}
+
+	if st.Settled > 0 {
+		st.MeanSettling = settlingSum / time.Duration(st.Settled)
+	}
+	if st.Events > 0 {
+		st.MeanOvershoot = overshootSum / float64(st.Events)
+	}
+	if jitterN > 0 {
+		st.Jitter = math.Sqrt(jitterSum / float64(jitterN))
+	}
+	return st
+}
+
+// csvHeader lists the CSV columns written by WriteCSV
+var csvHeader = []string{
+	"seq", "t", "mode", "fresh", "faces", "has_target",
+	"yaw_offset", "pitch_offset", "target_yaw", "yaw_error", "target_pitch",
+	"cmd_yaw", "cmd_pitch", "body_yaw",
+	"has_audio", "audio_angle", "audio_conf", "audio_speaking",
+}
+
+// WriteCSV writes records as CSV for plotting. Time is seconds since the
+// first record.
+func WriteCSV(w io.Writer, records []Record) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	var t0 time.Time
+	if len(records) > 0 {
+		t0 = records[0].Time
+	}
+
+	f := func(v float64) string { return strconv.FormatFloat(v, 'f', 5, 64) }
+	b := strconv.FormatBool
+
+	for _, r := range records {
+		row := []string{
+			strconv.FormatUint(r.Seq, 10),
+			f(r.Time.Sub(t0).Seconds()),
+			r.Mode,
+			b(r.Fresh),
+			strconv.Itoa(len(r.Detections)),
+			b(r.HasTarget),
+			f(r.YawOffset), f(r.PitchOffset),
+			f(r.TargetYaw), f(r.YawError), f(r.TargetPitch),
+			f(r.CommandYaw), f(r.CommandPitch), f(r.BodyYaw),
+			b(r.HasAudio), f(r.AudioAngle), f(r.AudioConfidence), b(r.AudioSpeaking),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,280 @@
+// Package telemetry records per-tick head tracking state for live
+// inspection and offline analysis.
+//
+// The tracker emits one Record per movement tick into a Recorder, which keeps
+// a ring buffer of recent records, fans them out to subscribers (e.g. the web
+// dashboard websocket) and optionally appends them to a JSON-lines file.
+// Analyze and WriteCSV turn a recorded file into jitter/settling statistics
+// and plot-ready CSV.
+//
+// This package has no OpenCV dependency so analysis tools stay pure Go.
+package telemetry
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultCapacity is the ring buffer size (~50 s at 20 Hz)
+const DefaultCapacity = 1000
+
+// Detection is a face box seen on the frame that fed this tick
+type Detection struct {
+	X          float64 `json:"x"` // Normalized 0-1
+	Y          float64 `json:"y"`
+	W          float64 `json:"w"`
+	H          float64 `json:"h"`
+	Confidence float64 `json:"conf"`
+}
+
+// Record is the tracker state for one movement tick
+type Record struct {
+	Seq  uint64    `json:"seq"`
+	Time time.Time `json:"t"`
+	Mode string    `json:"mode"` // Tracker behaviour ("tracking", "scanning", ...)
+
+	// Perception (Detections only present on ticks with a fresh detection)
+	Fresh      bool        `json:"fresh,omitempty"`
+	Detections []Detection `json:"detections,omitempty"`
+	HasTarget  bool        `json:"has_target"`
+
+	// Camera-relative offsets fed to the controller (radians)
+	YawOffset   float64 `json:"yaw_offset"`
+	PitchOffset float64 `json:"pitch_offset"`
+
+	// PD controller
+	TargetYaw   float64 `json:"target_yaw"`
+	YawError    float64 `json:"yaw_error"`
+	TargetPitch float64 `json:"target_pitch"`
+
+	// Pose actually sent (including speech wobble)
+	CommandYaw   float64 `json:"cmd_yaw"`
+	CommandPitch float64 `json:"cmd_pitch"`
+	BodyYaw      float64 `json:"body_yaw"`
+
+	// Audio direction of arrival
+	HasAudio        bool    `json:"has_audio,omitempty"`
+	AudioAngle      float64 `json:"audio_angle,omitempty"`
+	AudioConfidence float64 `json:"audio_conf,omitempty"`
+	AudioSpeaking   bool    `json:"audio_speaking,omitempty"`
+}
+
+// Recorder buffers telemetry records and fans them out to subscribers
+type Recorder struct {
+	mu   sync.Mutex
+	buf  []Record
+	next int
+	full bool
+	seq  uint64
+
+	subs   map[int]func(Record)
+	subSeq int
+
+	out    *bufio.Writer
+	enc    *json.Encoder
+	closer io.Closer
+
+	// Records the output couldn't take, reported by Flush and Close
+	lost    int
+	lostErr error
+}
+
+// NewRecorder creates a recorder keeping the last capacity records
+// (DefaultCapacity if capacity <= 0)
+func NewRecorder(capacity int) *Recorder {
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+	return &Recorder{
+		buf:  make([]Record, capacity),
+		subs: make(map[int]func(Record)),
+	}
+}
+
+// Add stores a record, assigning its sequence number (and time if zero),
+// writes it to the output file if one is open, and notifies subscribers.
+// Subscribers run synchronously and must not block. Records the output
+// fails to take are counted and reported by Flush and Close.
+func (r *Recorder) Add(rec Record) {
+	r.mu.Lock()
+	r.seq++
+	rec.Seq = r.seq
+	if rec.Time.IsZero() {
+		rec.Time = time.Now()
+	}
+
+	r.buf[r.next] = rec
+	r.next = (r.next + 1) % len(r.buf)
+	if r.next == 0 {
+		r.full = true
+	}
+
+	if r.enc != nil {
+		if err := r.enc.Encode(rec); err != nil {
+			r.lost++
+			if r.lostErr == nil {
+				r.lostErr = err
+			}
+		}
+	}
+
+	subs := make([]func(Record), 0, len(r.subs))
+	for _, fn := range r.subs {
+		subs = append(subs, fn)
+	}
+	r.mu.Unlock()
+
+	for _, fn := range subs {
+		fn(rec)
+	}
+}
+
+// Recent returns up to n of the newest records, oldest first (n <= 0 = all)
+func (r *Recorder) Recent(n int) []Record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	size := r.next
+	if r.full {
+		size = len(r.buf)
+	}
+	if n <= 0 || n > size {
+		n = size
+	}
+
+	result := make([]Record, n)
+	start := r.next - n
+	if start < 0 {
+		start += len(r.buf)
+	}
+	for i := 0; i < n; i++ {
+		result[i] = r.buf[(start+i)%len(r.buf)]
+	}
+	return result
+}
+
+// Subscribe registers a callback for every new record.
+// Returns a function that removes the subscription.
+func (r *Recorder) Subscribe(fn func(Record)) func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.subSeq++
+	id := r.subSeq
+	r.subs[id] = fn
+
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.subs, id)
+	}
+}
+
+// SetOutput streams records as JSON lines to w (nil stops streaming)
+func (r *Recorder) SetOutput(w io.Writer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.setOutputLocked(w, nil)
+}
+
+// OpenFile appends records to a JSON-lines file, replacing any previous output
+func (r *Recorder) OpenFile(path string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("open telemetry file: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.setOutputLocked(f, f)
+	return nil
+}
+
+// Close flushes and closes the output file, if any
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.setOutputLocked(nil, nil)
+}
+
+// setOutputLocked swaps the output, flushing and closing the previous one
+func (r *Recorder) setOutputLocked(w io.Writer, c io.Closer) error {
+	err := r.lostLocked()
+	if r.out != nil {
+		if ferr := r.out.Flush(); err == nil {
+			err = ferr
+		}
+	}
+	if r.closer != nil {
+		if cerr := r.closer.Close(); err == nil {
+			err = cerr
+		}
+	}
+
+	r.out, r.enc, r.closer = nil, nil, c
+	r.lost, r.lostErr = 0, nil
+	if w != nil {
+		r.out = bufio.NewWriter(w)
+		r.enc = json.NewEncoder(r.out)
+	}
+	return err
+}
+
+// Flush writes buffered records to the output
+func (r *Recorder) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.out == nil {
+		return nil
+	}
+	err := r.lostLocked()
+	if ferr := r.out.Flush(); err == nil {
+		err = ferr
+	}
+	return err
+}
+
+// lostLocked reports records the output couldn't take, if any
+func (r *Recorder) lostLocked() error {
+	if r.lost == 0 {
+		return nil
+	}
+	return fmt.Errorf("telemetry: %d records not written: %w", r.lost, r.lostErr)
+}
+
+// Read parses JSON-lines telemetry. Blank lines are skipped.
+func Read(rd io.Reader) ([]Record, error) {
+	var records []Record
+	scanner := bufio.NewScanner(rd)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		data := scanner.Bytes()
+		if len(data) == 0 {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal(data, &rec); err != nil {
+			return records, fmt.Errorf("line %d: %w", line, err)
+		}
+		records = append(records, rec)
+	}
+	return records, scanner.Err()
+}
+
+// ReadFile parses a JSON-lines telemetry file
+func ReadFile(path string) ([]Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
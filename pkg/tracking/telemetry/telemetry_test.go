@@ -0,0 +1,242 @@
+package telemetry
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecorder_RingBuffer(t *testing.T) {
+	r := NewRecorder(3)
+	for i := 0; i < 5; i++ {
+		r.Add(Record{YawOffset: float64(i)})
+	}
+
+	recent := r.Recent(0)
+	if len(recent) != 3 {
+		t.Fatalf("len(Recent) = %d, want 3", len(recent))
+	}
+	for i, want := range []uint64{3, 4, 5} {
+		if recent[i].Seq != want {
+			t.Errorf("Recent[%d].Seq = %d, want %d", i, recent[i].Seq, want)
+		}
+	}
+
+	last := r.Recent(1)
+	if len(last) != 1 || last[0].Seq != 5 {
+		t.Errorf("Recent(1) = %+v, want seq 5", last)
+	}
+}
+
+func TestRecorder_RecentPartial(t *testing.T) {
+	r := NewRecorder(10)
+	r.Add(Record{})
+	r.Add(Record{})
+
+	if got := len(r.Recent(5)); got != 2 {
+		t.Errorf("len(Recent(5)) = %d, want 2", got)
+	}
+}
+
+func TestRecorder_Subscribe(t *testing.T) {
+	r := NewRecorder(10)
+
+	var got []uint64
+	cancel := r.Subscribe(func(rec Record) { got = append(got, rec.Seq) })
+	r.Add(Record{})
+	r.Add(Record{})
+	cancel()
+	r.Add(Record{})
+
+	if len(got) != 2 || got[0] != 1 || got[1] != 2 {
+		t.Errorf("subscriber saw %v, want [1 2]", got)
+	}
+}
+
+func TestRecorder_FileRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "telemetry.jsonl")
+
+	r := NewRecorder(10)
+	if err := r.OpenFile(path); err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	r.Add(Record{Mode: "tracking", HasTarget: true, YawOffset: 0.25,
+		Fresh: true, Detections: []Detection{{X: 0.4, Y: 0.3, W: 0.1, H: 0.1, Confidence: 0.9}}})
+	r.Add(Record{Mode: "scanning", CommandYaw: -0.5})
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	records, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("len(records) = %d, want 2", len(records))
+	}
+	if records[0].YawOffset != 0.25 || len(records[0].Detections) != 1 {
+		t.Errorf("records[0] = %+v", records[0])
+	}
+	if records[1].Mode != "scanning" || records[1].CommandYaw != -0.5 {
+		t.Errorf("records[1] = %+v", records[1])
+	}
+}
+
+// failingWriter rejects every write
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestRecorder_WriteErrors(t *testing.T) {
+	r := NewRecorder(10)
+	r.SetOutput(failingWriter{})
+	for i := 0; i < 200; i++ { // Enough to overflow the write buffer
+		r.Add(Record{Mode: "tracking", CommandYaw: float64(i)})
+	}
+
+	err := r.Close()
+	if err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Fatalf("Close() = %v, want the write error", err)
+	}
+	if !strings.Contains(err.Error(), "records not written") {
+		t.Errorf("Close() = %v, want the count of lost records", err)
+	}
+	if len(r.Recent(0)) != 10 {
+		t.Error("write errors dropped records from the buffer")
+	}
+
+	// A new output starts clean
+	var buf bytes.Buffer
+	r.SetOutput(&buf)
+	r.Add(Record{Mode: "tracking"})
+	if err := r.Flush(); err != nil {
+		t.Errorf("Flush() = %v after a new output, want nil", err)
+	}
+}
+
+func TestRead_BadLine(t *testing.T) {
+	in := bytes.NewBufferString("{\"seq\":1}\n\nnot json\n")
+	records, err := Read(in)
+	if err == nil {
+		t.Fatal("expected error for malformed line")
+	}
+	if len(records) != 1 {
+		t.Errorf("len(records) = %d, want 1 before error", len(records))
+	}
+}
+
+// stepRecording simulates a face appearing off-centre and the head correcting
+func stepRecording(offsets []float64, commands []float64) []Record {
+	t0 := time.Unix(1000, 0)
+	records := make([]Record, len(offsets))
+	for i := range offsets {
+		records[i] = Record{
+			Seq:        uint64(i + 1),
+			Time:       t0.Add(time.Duration(i) * 50 * time.Millisecond),
+			Mode:       "tracking",
+			HasTarget:  true,
+			Fresh:      i%2 == 0,
+			YawOffset:  offsets[i],
+			CommandYaw: commands[i],
+		}
+	}
+	return records
+}
+
+func TestAnalyze_StepResponse(t *testing.T) {
+	// Step of 0.5 rad, overshoots by 0.1 then settles after 5 ticks
+	offsets := []float64{0, 0, 0.5, 0.35, 0.15, -0.1, -0.05, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+	commands := make([]float64, len(offsets))
+
+	s := Analyze(stepRecording(offsets, commands), DefaultAnalyzeConfig())
+
+	if s.Yaw.Events != 1 {
+		t.Fatalf("Yaw.Events = %d, want 1", s.Yaw.Events)
+	}
+	if s.Yaw.Settled != 1 {
+		t.Errorf("Yaw.Settled = %d, want 1", s.Yaw.Settled)
+	}
+	// Offset enters the band at tick 5 (0.1), event started at tick 2
+	if want := 150 * time.Millisecond; s.Yaw.MaxSettling != want {
+		t.Errorf("Yaw.MaxSettling = %v, want %v", s.Yaw.MaxSettling, want)
+	}
+	if math.Abs(s.Yaw.MaxOvershoot-0.2) > 1e-9 {
+		t.Errorf("Yaw.MaxOvershoot = %v, want 0.2", s.Yaw.MaxOvershoot)
+	}
+	if s.Pitch.Events != 0 {
+		t.Errorf("Pitch.Events = %d, want 0", s.Pitch.Events)
+	}
+	if math.Abs(s.TickHz-20) > 0.01 {
+		t.Errorf("TickHz = %v, want 20", s.TickHz)
+	}
+	if s.TargetFraction != 1 {
+		t.Errorf("TargetFraction = %v, want 1", s.TargetFraction)
+	}
+}
+
+func TestAnalyze_LostTargetUnsettled(t *testing.T) {
+	records := stepRecording([]float64{0, 0.5, 0.4, 0.3}, make([]float64, 4))
+	records[3].HasTarget = false
+
+	s := Analyze(records, DefaultAnalyzeConfig())
+	if s.Yaw.Events != 1 || s.Yaw.Settled != 0 {
+		t.Errorf("Yaw events/settled = %d/%d, want 1/0", s.Yaw.Events, s.Yaw.Settled)
+	}
+}
+
+func TestAnalyze_Jitter(t *testing.T) {
+	offsets := make([]float64, 9)
+	commands := []float64{0, 0.01, 0, 0.01, 0, 0.01, 0, 0.01, 0}
+
+	s := Analyze(stepRecording(offsets, commands), DefaultAnalyzeConfig())
+	if math.Abs(s.Yaw.Jitter-0.01) > 1e-9 {
+		t.Errorf("Yaw.Jitter = %v, want 0.01", s.Yaw.Jitter)
+	}
+	if s.Pitch.Jitter != 0 {
+		t.Errorf("Pitch.Jitter = %v, want 0", s.Pitch.Jitter)
+	}
+}
+
+func TestAnalyze_Modes(t *testing.T) {
+	records := stepRecording(make([]float64, 5), make([]float64, 5))
+	records[3].Mode = "scanning"
+	records[4].Mode = "scanning"
+
+	s := Analyze(records, DefaultAnalyzeConfig())
+	if got := s.Modes["tracking"]; got != 150*time.Millisecond {
+		t.Errorf("Modes[tracking] = %v, want 150ms", got)
+	}
+	if got := s.Modes["scanning"]; got != 50*time.Millisecond {
+		t.Errorf("Modes[scanning] = %v, want 50ms", got)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	records := stepRecording([]float64{0.1, 0.2}, []float64{0, 0.05})
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, records); err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("parse CSV: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("rows = %d, want 3 (header + 2)", len(rows))
+	}
+	if len(rows[0]) != len(rows[1]) {
+		t.Errorf("header has %d columns, row has %d", len(rows[0]), len(rows[1]))
+	}
+	if rows[2][1] != "0.05000" {
+		t.Errorf("t = %q, want 0.05000", rows[2][1])
+	}
+}
@@ -0,0 +1,60 @@
+package tracking
+
+import (
+	"testing"
+
+	"github.com/teslashibe/go-reachy/pkg/tracking/telemetry"
+	"github.com/teslashibe/go-reachy/pkg/worldmodel"
+)
+
+func TestTracker_RecordTelemetry(t *testing.T) {
+	cfg := DefaultConfig()
+	robot := &mockRobotController{}
+
+	tracker := &Tracker{
+		config:          cfg,
+		robot:           robot,
+		world:           worldmodel.New(),
+		controller:      NewPDController(cfg),
+		lastLoggedYaw:   999.0,
+		isEnabled:       true,
+		isFaceEnabled:   true,
+		hasFaceTarget:   true,
+		lastYawOffset:   0.3,
+		lastPitchOffset: -0.1,
+		detectSeq:       1,
+	}
+
+	rec := telemetry.NewRecorder(10)
+	tracker.SetTelemetry(rec)
+
+	tracker.updateMovement()
+	tracker.recordTelemetry()
+	tracker.updateMovement()
+	tracker.recordTelemetry()
+
+	records := rec.Recent(0)
+	if len(records) != 2 {
+		t.Fatalf("len(records) = %d, want 2", len(records))
+	}
+
+	first := records[0]
+	if first.Mode != "tracking" {
+		t.Errorf("Mode = %q, want tracking", first.Mode)
+	}
+	if !first.HasTarget || first.YawOffset != 0.3 || first.PitchOffset != -0.1 {
+		t.Errorf("target/offsets = %v/%v/%v, want true/0.3/-0.1", first.HasTarget, first.YawOffset, first.PitchOffset)
+	}
+	if !first.Fresh {
+		t.Error("first record should carry the fresh detection")
+	}
+	if records[1].Fresh {
+		t.Error("second record should not repeat the detection")
+	}
+	if first.CommandYaw != robot.headCalls[0].yaw {
+		t.Errorf("CommandYaw = %v, want %v (sent pose)", first.CommandYaw, robot.headCalls[0].yaw)
+	}
+	if first.CommandYaw == 0 {
+		t.Error("CommandYaw = 0, expected head to move toward offset")
+	}
+}
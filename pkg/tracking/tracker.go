@@ -11,6 +11,7 @@ import (
 	"github.com/teslashibe/go-reachy/pkg/debug"
 	"github.com/teslashibe/go-reachy/pkg/robot"
 	"github.com/teslashibe/go-reachy/pkg/tracking/detection"
+	"github.com/teslashibe/go-reachy/pkg/tracking/telemetry"
 	"github.com/teslashibe/go-reachy/pkg/worldmodel"
 )
 
@@ -41,6 +42,10 @@ type BodyRotationHandler func(direction float64) float64
 // Routes through RateController to prevent HTTP racing (Issue #139).
 type AntennaHandler func(left, right float64)
 
+// FrameHandler is called with each analysed frame and the faces found in
+// it, e.g. to meter exposure. It runs on the detection loop and must not block.
+type FrameHandler func(frame []byte, faces []detection.Detection)
+
 // Tracker handles head tracking with world-coordinate awareness
 type Tracker struct {
 	config Config
@@ -55,10 +60,14 @@ type Tracker struct {
 
 	// Core components
 	detector   detection.Detector
+	pipeline   *detection.Pipeline // Optional extra detectors (YOLO etc.) for world tracks
 	world      *worldmodel.WorldModel
 	controller *PDController
 	perception *Perception
 
+	// Newest frame for the pipeline worker (see runPipeline)
+	pipelineFrames chan pipelineFrame
+
 	// Audio DOA client (optional, from go-eva)
 	audioClient *audio.Client
 
@@ -68,6 +77,9 @@ type Tracker struct {
 	// Body rotation callback: if set, called when head reaches limits
 	onBodyRotation BodyRotationHandler
 
+	// Frame callback: if set, called with each analysed frame
+	onFrame FrameHandler
+
 	// State
 	mu            sync.RWMutex
 	lastLoggedYaw float64
@@ -81,15 +93,32 @@ type Tracker struct {
 	isFaceEnabled  bool // Whether face tracking is active (default: true)
 	isAudioEnabled bool // Whether audio DOA tracking is active (default: true)
 
+	// Local VAD state (see SetUserSpeaking)
+	userSpeaking bool
+
+	// Auto-tune state (normal tracking is suspended while a run owns the head)
+	isAutoTuning bool
+	autoTune     autoTuneState
+
+	// Behaviour state machine (see states.go); built lazily by machine()
+	sm     *StateMachine
+	smOnce sync.Once
+
 	// Scanning state
-	isScanning     bool
 	scanDirection  float64 // 1 = right, -1 = left
 	scanStartTime  time.Time
 	lastFaceSeenAt time.Time
 	scanCyclesDone int // Number of complete scan cycles
 
+	// Search state (StateSearching, Run goroutine only except search)
+	search          searchMemory // Clues collected while tracking
+	searchPlan      []LookPoint
+	searchIndex     int       // Current point in searchPlan
+	searchStartedAt time.Time // When the search started
+	searchArrivedAt time.Time // When the head reached the current point (zero = moving)
+	searchHolding   bool      // Gave up with SearchGiveUpHold
+
 	// Breathing state (idle animation)
-	isBreathing    bool
 	breathingPhase float64 // Current phase in radians (0 to 2π)
 
 	// Detection rate control (for runtime tuning)
@@ -99,20 +128,22 @@ type Tracker struct {
 	// Speech wobble offsets (additive to tracking output)
 	speechOffsets robot.Offset
 
-	// Interpolation for smooth return to neutral
-	interpStartedAt time.Time
-	isInterpolating bool
-
 	// Error tracking (avoid log spam)
 	lastRobotError time.Time
 
+	// Telemetry (optional per-tick recording)
+	telemetry          *telemetry.Recorder
+	detectSeq          uint64  // Incremented after every face detection
+	telemetryDetectSeq uint64  // detectSeq at the last record (Run goroutine only)
+	lastOutputYaw      float64 // Last pose sent by outputPose
+	lastOutputPitch    float64
+
 	// Camera-relative offsets (from latest detection)
 	lastYawOffset   float64 // How much to turn horizontally
 	lastPitchOffset float64 // How much to tilt vertically
 	hasFaceTarget   bool    // Whether we have a recent face detection
 
-	// Audio-triggered speaker switching state
-	audioSwitchActive    bool      // Currently turning toward audio source
+	// Audio-triggered speaker switching state (StateAudioSwitch)
 	audioSwitchStartedAt time.Time // When we started the audio switch
 	audioSwitchTarget    float64   // Target yaw for audio switch
 	preSwitchFaceTarget  string    // Focus target before switching (to return to)
@@ -120,7 +151,6 @@ type Tracker struct {
 	// Body alignment state (gradual centering when locked on target)
 	lockedOnFaceAt      time.Time // When stable tracking started (for lock detection)
 	lastBodyAlignmentAt time.Time // Last alignment action (for cooldown)
-	isBodyAligning      bool      // Currently performing body alignment
 }
 
 // New creates a new head tracker with local face detection.
@@ -152,6 +182,7 @@ func New(config Config, robotCtrl robot.HeadController, video VideoSource, model
 		isFaceEnabled:     true,                        // Face tracking enabled by default
 		isAudioEnabled:    true,                        // Audio tracking enabled by default
 		detectTickerReset: make(chan time.Duration, 1), // Buffer of 1 for non-blocking send
+		pipelineFrames:    make(chan pipelineFrame, 1),
 	}, nil
 }
 
@@ -213,6 +244,20 @@ func (t *Tracker) SetBodyRotationHandler(handler BodyRotationHandler) {
 	t.onBodyRotation = handler
 }
 
+// SetFrameHandler sets the callback for analysed frames.
+func (t *Tracker) SetFrameHandler(handler FrameHandler) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onFrame = handler
+}
+
+// SetCameraCrop tells the tracker which part of the camera's full view
+// frames show (digital zoom or reframing), so angles and distances stay
+// correct. Call it once the crop is applied.
+func (t *Tracker) SetCameraCrop(c Crop) {
+	t.perception.SetCrop(c)
+}
+
 // SetBodyYaw updates the world model with current body orientation.
 // Call this when the body rotates so tracking remains accurate.
 func (t *Tracker) SetBodyYaw(yaw float64) {
@@ -352,6 +397,33 @@ func (t *Tracker) handleAudioDOA(doa *audio.DOAResult) {
 	}
 }
 
+// SetUserSpeaking reports speech start/end from the local VAD. A start
+// with a known direction confirms the DOA reading, so the speaker's face
+// is marked as talking even if the XVF3800 flag lags.
+func (t *Tracker) SetUserSpeaking(speaking bool, angle float64, hasAngle bool) {
+	t.mu.Lock()
+	t.userSpeaking = speaking
+	audioEnabled := t.isAudioEnabled
+	t.mu.Unlock()
+
+	if !speaking || !hasAngle || !audioEnabled {
+		return
+	}
+	t.world.UpdateAudioSource(angle, 1.0, true)
+	if entityID := t.world.AssociateAudio(angle, true, 1.0); entityID != "" {
+		debug.Log("🗣️  VAD: speech at %.2f rad → face %s\n", angle, entityID)
+	} else {
+		debug.Log("🗣️  VAD: speech at %.2f rad (no face match)\n", angle)
+	}
+}
+
+// UserSpeaking reports whether the local VAD hears the user.
+func (t *Tracker) UserSpeaking() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.userSpeaking
+}
+
 // GetCurrentYaw returns the current head yaw
 func (t *Tracker) GetCurrentYaw() float64 {
 	return t.controller.GetCurrentYaw()
@@ -432,6 +504,9 @@ func (t *Tracker) Run(ctx context.Context) {
 		defer audioTicker.Stop()
 	}
 
+	// Extra detectors run beside the loop, not in it
+	go t.runPipeline(ctx)
+
 	lastDecay := time.Now()
 
 	// Build channel for audio polling (only if WebSocket failed)
@@ -452,6 +527,7 @@ func (t *Tracker) Run(ctx context.Context) {
 
 		case <-moveTicker.C:
 			t.updateMovement()
+			t.recordTelemetry()
 
 		case <-t.detectTicker.C:
 			if t.video != nil {
@@ -509,74 +585,78 @@ func (t *Tracker) pollAudioDOA() {
 	}
 }
 
-// updateMovement uses the PD controller to smoothly move toward target
+// updateMovement runs one tick of the behaviour state machine: it turns the
+// latest inputs (toggles, face, voice) into events, then performs the active
+// state's movement.
 func (t *Tracker) updateMovement() {
-	// Check if tracking is disabled
 	t.mu.RLock()
 	enabled := t.isEnabled
-	disabledAt := t.disabledAt
+	autoTuning := t.isAutoTuning
 	hasFace := t.hasFaceTarget
 	yawOffset := t.lastYawOffset
 	pitchOffset := t.lastPitchOffset
 	t.mu.RUnlock()
 
-	if !enabled {
-		// Tracking disabled - return to neutral
-		t.updateDisabled(disabledAt)
+	m := t.machine()
+
+	// Master toggles apply from any state
+	if autoTuning {
+		m.Fire(EventAutoTuneStart)
+	} else {
+		m.Fire(EventAutoTuneEnd)
+	}
+	if enabled {
+		m.Fire(EventEnable)
+	} else {
+		m.Fire(EventDisable)
+	}
+
+	switch m.Current() {
+	case StateAutoTune:
+		return // Auto-tune owns the head
+	case StateDisabled:
+		t.updateDisabled()
 		return
 	}
 
 	// Check for audio-only target (when no face but audio DOA active)
 	audioAngle, hasAudio := t.getAudioTarget()
+	if hasAudio {
+		t.rememberVoice(audioAngle)
+	}
 
 	// Check for audio-triggered speaker switching
 	// This allows Eva to turn toward a new speaker even when tracking a face
 	t.checkAudioSwitch(hasFace, audioAngle, hasAudio)
 
-	// Read audio switch state
-	t.mu.RLock()
-	audioSwitchActive := t.audioSwitchActive
-	audioSwitchTarget := t.audioSwitchTarget
-	t.mu.RUnlock()
-
 	// Update lock state for body alignment
 	t.updateLockState(hasFace)
 
-	if !hasFace && !hasAudio && !audioSwitchActive {
-		// No target - check if we should scan or interpolate to neutral
-		t.updateNoTarget()
-		return
-	}
-
-	// Determine source and target
-	var source string
-	if audioSwitchActive {
-		source = "audio-switch"
-	} else if hasFace {
-		source = "face"
+	if hasFace || hasAudio || m.Is(StateAudioSwitch) {
+		m.Fire(EventTargetAcquired)
 	} else {
-		source = "audio"
+		m.Fire(EventTargetLost)
 	}
 
-	// We have a target - stop scanning, breathing, and interpolation
-	if t.isScanning || t.isBreathing {
-		if t.isScanning {
-			t.isScanning = false
-		}
-		if t.isBreathing {
-			t.isBreathing = false
-			t.breathingPhase = 0
-		}
-		if source == "face" {
-			debug.Logln("👁️  Found face, stopping idle behavior")
-		} else if source == "audio" || source == "audio-switch" {
-			debug.Logln("🎤 Heard voice, turning toward sound")
-		}
+	switch m.Current() {
+	case StateTracking, StateBodyAligning, StateAudioSwitch:
+		t.updateTracking(hasFace, hasAudio, yawOffset, pitchOffset, audioAngle)
+	default:
+		// No target - hold, return to neutral, scan or breathe
+		t.updateNoTarget()
 	}
-	t.isInterpolating = false
+}
+
+// updateTracking moves toward the current target (tracking, body aligning
+// and audio switch states)
+func (t *Tracker) updateTracking(hasFace, hasAudio bool, yawOffset, pitchOffset, audioAngle float64) {
+	t.mu.RLock()
+	audioSwitchTarget := t.audioSwitchTarget
+	t.mu.RUnlock()
+	audioSwitchActive := t.machine().Is(StateAudioSwitch)
 
 	// Only update lastFaceSeenAt for visual targets (not during audio switch)
-	if source == "face" {
+	if hasFace && !audioSwitchActive {
 		t.lastFaceSeenAt = time.Now()
 	}
 
@@ -648,6 +728,10 @@ func (t *Tracker) outputPose(yaw, pitch, targetAngle float64) {
 	finalPitch := pitch + speech.Pitch
 	finalRoll := speech.Roll // Roll only from speech (tracking doesn't use roll)
 
+	t.mu.Lock()
+	t.lastOutputYaw, t.lastOutputPitch = finalYaw, finalPitch
+	t.mu.Unlock()
+
 	if handler != nil {
 		// Offset mode: output for fusion with unified controller
 		handler(robot.Offset{Roll: finalRoll, Pitch: finalPitch, Yaw: finalYaw})
@@ -749,18 +833,19 @@ func (t *Tracker) isLockedOnTarget() bool {
 // Called from updateMovement when we have a face target.
 func (t *Tracker) updateLockState(hasFace bool) {
 	t.mu.Lock()
-	defer t.mu.Unlock()
-
 	if hasFace {
 		// If we just acquired a face, record the time
 		if t.lockedOnFaceAt.IsZero() {
 			t.lockedOnFaceAt = time.Now()
 		}
-	} else {
-		// Lost face - reset lock state
-		t.lockedOnFaceAt = time.Time{}
-		t.isBodyAligning = false
+		t.mu.Unlock()
+		return
 	}
+
+	// Lost face - reset lock state and stop aligning
+	t.lockedOnFaceAt = time.Time{}
+	t.mu.Unlock()
+	t.machine().Fire(EventAlignDone)
 }
 
 // checkBodyAlignment rotates body to stay under the head.
@@ -807,6 +892,7 @@ func (t *Tracker) checkBodyAlignment() {
 		reason = "returning to neutral"
 	} else {
 		// Both head and body are centered - nothing to do
+		t.machine().Fire(EventAlignDone)
 		return
 	}
 
@@ -848,8 +934,8 @@ func (t *Tracker) checkBodyAlignment() {
 	// Update state
 	t.mu.Lock()
 	t.lastBodyAlignmentAt = time.Now()
-	t.isBodyAligning = true
 	t.mu.Unlock()
+	t.machine().Fire(EventAlignStart)
 
 	// Trigger body rotation and get ACTUAL delta (may be less if clamped)
 	actualDelta := handler(bodyDelta)
@@ -864,57 +950,20 @@ func (t *Tracker) checkBodyAlignment() {
 // checkAudioSwitch determines if we should turn toward a new audio source.
 // This is called during updateMovement() to check if someone is speaking
 // from a significantly different direction than where Eva is currently looking.
+// It fires EventAudioSwitchStart / EventAudioSwitchEnd on the state machine.
 func (t *Tracker) checkAudioSwitch(hasFace bool, audioAngle float64, hasAudio bool) {
 	// Skip if audio switch is disabled
 	if !t.config.AudioSwitchEnabled {
 		return
 	}
 
-	t.mu.Lock()
-	defer t.mu.Unlock()
+	m := t.machine()
 
 	// If audio switch is already active, check if we should end it
-	if t.audioSwitchActive {
-		// End conditions:
-		// 1. Found a face at the new position (success!)
-		// 2. Timeout expired (no face found, return to original)
-		// 3. Audio stopped (speaker stopped talking)
-
-		elapsed := time.Since(t.audioSwitchStartedAt)
-
-		// Check if we found a face during the switch
-		if t.hasFaceTarget {
-			debug.Log("🎤→👁️  Audio switch SUCCESS: found face after %.1fs, switching focus\n", elapsed.Seconds())
-			t.audioSwitchActive = false
-			t.preSwitchFaceTarget = "" // Clear, we're committed to new face
-			return
+	if m.Is(StateAudioSwitch) {
+		if t.shouldEndAudioSwitch(hasAudio, audioAngle) {
+			m.Fire(EventAudioSwitchEnd)
 		}
-
-		// Check timeout
-		if elapsed > t.config.AudioSwitchLookDuration {
-			debug.Log("🎤→❌ Audio switch TIMEOUT: no face found after %.1fs, returning to previous target\n", elapsed.Seconds())
-			t.audioSwitchActive = false
-			// Restore previous focus target if we had one
-			if t.preSwitchFaceTarget != "" {
-				t.world.SetFocusTarget(t.preSwitchFaceTarget)
-			}
-			t.preSwitchFaceTarget = ""
-			return
-		}
-
-		// Check if audio stopped
-		if !hasAudio {
-			debug.Log("🎤→🔇 Audio switch CANCELLED: speaker stopped after %.1fs\n", elapsed.Seconds())
-			t.audioSwitchActive = false
-			if t.preSwitchFaceTarget != "" {
-				t.world.SetFocusTarget(t.preSwitchFaceTarget)
-			}
-			t.preSwitchFaceTarget = ""
-			return
-		}
-
-		// Still in audio switch mode, update target if audio moved
-		t.audioSwitchTarget = t.controller.GetCurrentYaw() + audioAngle
 		return
 	}
 
@@ -942,23 +991,74 @@ func (t *Tracker) checkAudioSwitch(hasFace bool, audioAngle float64, hasAudio bo
 		return
 	}
 
-	// Audio is from a different direction! Start audio switch
-	debug.Log("🎤🔀 Audio switch TRIGGERED: voice at %.2f rad from gaze (threshold=%.2f, confidence=%.2f)\n",
-		angleDiff, t.config.AudioSwitchThreshold, audio.Confidence)
-
-	t.audioSwitchActive = true
-	t.audioSwitchStartedAt = time.Now()
+	// Store the target and previous focus before switching
+	t.mu.Lock()
 	t.audioSwitchTarget = currentYaw + audioAngle
-	// Store previous focus target to return to if audio switch fails
+	t.preSwitchFaceTarget = ""
 	if focus := t.world.GetFocusTarget(); focus != nil {
 		t.preSwitchFaceTarget = focus.ID
 	}
+	t.mu.Unlock()
+
+	// Audio is from a different direction! Start audio switch
+	if !m.Fire(EventAudioSwitchStart) {
+		return
+	}
+	debug.Log("🎤🔀 Audio switch TRIGGERED: voice at %.2f rad from gaze (threshold=%.2f, confidence=%.2f)\n",
+		angleDiff, t.config.AudioSwitchThreshold, audio.Confidence)
 
 	if t.state != nil {
 		t.state.AddLog("audio-switch", fmt.Sprintf("Turning toward voice (%.0f° away)", angleDiff*180/math.Pi))
 	}
 }
 
+// shouldEndAudioSwitch checks the end conditions of an active audio switch:
+//  1. Found a face at the new position (success!)
+//  2. Timeout expired (no face found, return to original)
+//  3. Audio stopped (speaker stopped talking)
+//
+// Otherwise it follows the voice if it moved.
+func (t *Tracker) shouldEndAudioSwitch(hasAudio bool, audioAngle float64) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	elapsed := time.Since(t.audioSwitchStartedAt)
+
+	// Check if we found a face during the switch
+	if t.hasFaceTarget {
+		debug.Log("🎤→👁️  Audio switch SUCCESS: found face after %.1fs, switching focus\n", elapsed.Seconds())
+		t.preSwitchFaceTarget = "" // Clear, we're committed to new face
+		return true
+	}
+
+	// Check timeout
+	if elapsed > t.config.AudioSwitchLookDuration {
+		debug.Log("🎤→❌ Audio switch TIMEOUT: no face found after %.1fs, returning to previous target\n", elapsed.Seconds())
+		t.restorePreSwitchFocus()
+		return true
+	}
+
+	// Check if audio stopped
+	if !hasAudio {
+		debug.Log("🎤→🔇 Audio switch CANCELLED: speaker stopped after %.1fs\n", elapsed.Seconds())
+		t.restorePreSwitchFocus()
+		return true
+	}
+
+	// Still in audio switch mode, update target if audio moved
+	t.audioSwitchTarget = t.controller.GetCurrentYaw() + audioAngle
+	return false
+}
+
+// restorePreSwitchFocus returns focus to the face we tracked before an
+// audio switch. Caller must hold t.mu.
+func (t *Tracker) restorePreSwitchFocus() {
+	if t.preSwitchFaceTarget != "" {
+		t.world.SetFocusTarget(t.preSwitchFaceTarget)
+	}
+	t.preSwitchFaceTarget = ""
+}
+
 // getAudioTarget returns audio-based target offset if available
 func (t *Tracker) getAudioTarget() (float64, bool) {
 	t.mu.RLock()
@@ -980,90 +1080,51 @@ func (t *Tracker) getAudioTarget() (float64, bool) {
 	return audio.Angle, true
 }
 
-// updateNoTarget handles the case when no face is detected
+// updateNoTarget handles the states without a target:
+// idle (grace period) → searching → returning to neutral → scanning → breathing
 func (t *Tracker) updateNoTarget() {
-	// If breathing, continue breathing animation
-	if t.isBreathing {
-		t.updateBreathing()
-		return
-	}
+	m := t.machine()
 
-	// Check if we should start interpolating to neutral
-	if !t.isInterpolating && !t.isScanning {
+	switch m.Current() {
+	case StateIdle:
 		if t.lastFaceSeenAt.IsZero() {
 			t.lastFaceSeenAt = time.Now()
 		}
-
-		timeSinceFace := time.Since(t.lastFaceSeenAt)
-		if timeSinceFace >= t.config.ScanStartDelay {
-			// Grace period expired - start smooth interpolation to neutral
-			t.isInterpolating = true
-			t.interpStartedAt = time.Now()
-			t.controller.InterpolateToNeutral(1 * time.Second)
-			debug.Logln("👁️  Grace period expired, returning to neutral")
-		}
+		// Guard checks the grace period; entering returning starts interpolation.
 		// During grace period: hold current position (do nothing)
-		return
-	}
+		m.Fire(EventGraceExpired)
 
-	// If interpolating, continue the interpolation
-	if t.isInterpolating {
+	case StateReturning:
 		newYaw, shouldMove := t.controller.Update()
 		if shouldMove {
 			// During interpolation to neutral, also return pitch to 0
 			t.outputPose(newYaw, 0, 0)
 		}
+		// Guard waits for interpolation to complete, then scanning starts
+		m.Fire(EventReturnComplete)
 
-		// Check if interpolation is complete, then start scanning
-		if !t.controller.IsInterpolating() {
-			t.isInterpolating = false
-			t.isScanning = true
-			t.scanStartTime = time.Now()
-			t.scanDirection = 1.0
-			t.scanCyclesDone = 0 // Reset cycle counter
-			debug.Logln("👀 Starting scan for faces...")
-			if t.state != nil {
-				t.state.AddLog("scan", "Scanning for faces")
-			}
-		}
-		return
-	}
+	case StateSearching:
+		t.updateSearching()
 
-	// Continue scanning
-	t.updateScanning()
-}
+	case StateScanning:
+		t.updateScanning()
 
-// updateDisabled handles movement when tracking is disabled.
-// Smoothly returns to neutral position.
-func (t *Tracker) updateDisabled(disabledAt time.Time) {
-	// Stop scanning and breathing when disabled
-	if t.isScanning {
-		t.isScanning = false
-	}
-	if t.isBreathing {
-		t.isBreathing = false
-		t.breathingPhase = 0
+	case StateBreathing:
+		t.updateBreathing()
 	}
+}
 
-	// Start interpolation to neutral if not already interpolating
-	if !t.isInterpolating {
-		t.isInterpolating = true
-		t.interpStartedAt = time.Now()
-		t.controller.InterpolateToNeutral(1 * time.Second)
-		debug.Logln("👁️  Tracking disabled, returning to neutral")
+// updateDisabled handles movement when tracking is disabled.
+// Entering StateDisabled starts interpolation to neutral; once there, hold.
+func (t *Tracker) updateDisabled() {
+	if !t.controller.IsInterpolating() {
+		return // Holding at neutral - no further movement needed
 	}
 
-	// Continue interpolation
 	newYaw, shouldMove := t.controller.Update()
 	if shouldMove {
 		t.outputPose(newYaw, 0, 0)
 	}
-
-	// When interpolation completes, just hold position
-	if !t.controller.IsInterpolating() {
-		t.isInterpolating = false
-		// Hold at neutral - no further movement needed
-	}
 }
 
 // detectAndUpdate detects faces and updates the world model
@@ -1072,20 +1133,41 @@ func (t *Tracker) detectAndUpdate() {
 	t.mu.RLock()
 	enabled := t.isEnabled
 	faceEnabled := t.isFaceEnabled
+	autoTuning := t.isAutoTuning
 	t.mu.RUnlock()
-	if !enabled || !faceEnabled {
+	if !enabled || !faceEnabled || autoTuning {
+		return
+	}
+
+	// Capture once so faces and any pipeline detectors see the same frame
+	if t.video == nil {
+		t.clearFaceTarget()
+		return
+	}
+	frame, err := t.video.CaptureJPEG()
+	if err != nil {
+		t.clearFaceTarget() // No frame, so no current face either
 		return
 	}
 
 	// Detect face using camera-relative offset approach
 	// No dependency on knowing head or body position - self-correcting
-	yawOffset, pitchOffset, faceWidth, found := t.perception.DetectFaceOffset(t.video)
+	yawOffset, pitchOffset, faceWidth, found := t.perception.DetectFaceOffsetInFrame(frame)
+
+	t.mu.Lock()
+	t.detectSeq++
+	onFrame := t.onFrame
+	t.mu.Unlock()
+
+	if onFrame != nil {
+		onFrame(frame, t.perception.GetLastDetections())
+	}
+
+	// Feed faces and pipeline detections (bodies, pets, objects) into world tracks
+	t.updateTracks(frame)
 
 	if !found {
-		// Clear face target when no face detected
-		t.mu.Lock()
-		t.hasFaceTarget = false
-		t.mu.Unlock()
+		t.clearFaceTarget()
 
 		// Log occasional misses and reset smoothing on face loss
 		misses := t.perception.GetConsecutiveMisses()
@@ -1133,6 +1215,13 @@ func (t *Tracker) detectAndUpdate() {
 	}
 }
 
+// clearFaceTarget forgets the face offsets once no face is in view
+func (t *Tracker) clearFaceTarget() {
+	t.mu.Lock()
+	t.hasFaceTarget = false
+	t.mu.Unlock()
+}
+
 // updateScanning implements scan behavior when no face is detected
 func (t *Tracker) updateScanning() {
 	// Calculate scan position
@@ -1154,15 +1243,8 @@ func (t *Tracker) updateScanning() {
 		t.scanCyclesDone++ // Complete cycle when returning to right
 		debug.Logln("👀 Scan: reversing to right")
 
-		// After one full scan cycle, switch to breathing if enabled
-		if t.scanCyclesDone >= 1 && t.config.BreathingEnabled {
-			t.isScanning = false
-			t.isBreathing = true
-			t.breathingPhase = 0
-			debug.Logln("😮‍💨 Scan complete, starting breathing animation")
-			if t.state != nil {
-				t.state.AddLog("breathing", "Breathing animation started")
-			}
+		// After one full scan cycle, switch to breathing if enabled (guarded)
+		if t.machine().Fire(EventScanComplete) {
 			return
 		}
 	}
@@ -60,6 +60,12 @@ type TuningParams struct {
 	ScanStartDelay float64 `json:"scan_start_delay"` // Seconds before scanning starts
 	ScanSpeed      float64 `json:"scan_speed"`       // Scan speed (rad/s)
 	ScanRange      float64 `json:"scan_range"`       // Scan extent (radians)
+
+	// Search behavior (nil/zero/empty = unchanged)
+	SearchEnabled     *bool   `json:"search_enabled,omitempty"`      // Search look points before sweeping
+	SearchDwell       float64 `json:"search_dwell,omitempty"`        // Seconds to look at each point
+	SearchMaxDuration float64 `json:"search_max_duration,omitempty"` // Seconds before giving up
+	SearchGiveUp      string  `json:"search_give_up,omitempty"`      // "sweep", "neutral" or "hold"
 }
 
 // GetTuningParams returns current tuning parameters from the tracker.
@@ -68,6 +74,7 @@ func (t *Tracker) GetTuningParams() TuningParams {
 	defer t.mu.RUnlock()
 
 	detectionHz := 1.0 / t.config.DetectionInterval.Seconds()
+	searchEnabled := t.config.SearchEnabled
 
 	return TuningParams{
 		// Existing params
@@ -117,6 +124,12 @@ func (t *Tracker) GetTuningParams() TuningParams {
 		ScanStartDelay: t.config.ScanStartDelay.Seconds(),
 		ScanSpeed:      t.config.ScanSpeed,
 		ScanRange:      t.config.ScanRange,
+
+		// Search behavior
+		SearchEnabled:     &searchEnabled,
+		SearchDwell:       t.config.SearchDwell.Seconds(),
+		SearchMaxDuration: t.config.SearchMaxDuration.Seconds(),
+		SearchGiveUp:      string(t.config.SearchGiveUp),
 	}
 }
 
@@ -269,6 +282,21 @@ func (t *Tracker) SetTuningParams(params TuningParams) {
 	if params.ScanRange > 0 {
 		t.config.ScanRange = params.ScanRange
 	}
+
+	// === Search behavior ===
+	if params.SearchEnabled != nil {
+		t.config.SearchEnabled = *params.SearchEnabled
+	}
+	if params.SearchDwell > 0 {
+		t.config.SearchDwell = time.Duration(params.SearchDwell * float64(time.Second))
+	}
+	if params.SearchMaxDuration > 0 {
+		t.config.SearchMaxDuration = time.Duration(params.SearchMaxDuration * float64(time.Second))
+	}
+	switch g := SearchGiveUp(params.SearchGiveUp); g {
+	case SearchGiveUpSweep, SearchGiveUpNeutral, SearchGiveUpHold:
+		t.config.SearchGiveUp = g
+	}
 }
 
 // setDetectionHz updates the detection rate at runtime.
@@ -40,7 +40,8 @@ type OpenAI struct {
 	baseURL string
 }
 
-// NewOpenAI creates a new OpenAI TTS provider.
+// NewOpenAI creates a new OpenAI TTS provider. OpenAI itself needs an
+// API key; a local OpenAI-compatible server (WithBaseURL) may not.
 func NewOpenAI(opts ...Option) (*OpenAI, error) {
 	cfg := DefaultConfig()
 	cfg.ModelID = ModelTTS1
@@ -48,8 +49,10 @@ func NewOpenAI(opts ...Option) (*OpenAI, error) {
 	cfg.OutputFormat = EncodingMP3
 	cfg.Apply(opts...)
 
-	if err := cfg.Validate(); err != nil {
-		return nil, err
+	if cfg.BaseURL == "" {
+		if err := cfg.Validate(); err != nil {
+			return nil, err
+		}
 	}
 
 	// Default voice if not set
@@ -79,6 +82,9 @@ func (o *OpenAI) Synthesize(ctx context.Context, text string) (*AudioResult, err
 		"voice": o.config.VoiceID,
 		"input": text,
 	}
+	if o.config.OutputFormat == EncodingPCM24 {
+		payload["response_format"] = "pcm"
+	}
 
 	body, err := json.Marshal(payload)
 	if err != nil {
@@ -90,7 +96,9 @@ func (o *OpenAI) Synthesize(ctx context.Context, text string) (*AudioResult, err
 		return nil, WrapError(providerOpenAI, fmt.Errorf("create request: %w", err))
 	}
 
-	req.Header.Set("Authorization", "Bearer "+o.config.APIKey)
+	if o.config.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+o.config.APIKey)
+	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := o.doWithRetry(ctx, req, body)
@@ -239,7 +247,17 @@ func (o *OpenAI) parseError(resp *http.Response) error {
 
 // outputFormat returns the audio format configuration.
 func (o *OpenAI) outputFormat() AudioFormat {
-	// OpenAI TTS returns MP3 at 44.1kHz
+	// Raw PCM is 24kHz 16-bit mono
+	if o.config.OutputFormat == EncodingPCM24 {
+		return AudioFormat{
+			Encoding:   EncodingPCM24,
+			SampleRate: 24000,
+			Channels:   1,
+			BitDepth:   16,
+		}
+	}
+
+	// Otherwise OpenAI TTS returns MP3 at 44.1kHz
 	return AudioFormat{
 		Encoding:   EncodingMP3,
 		SampleRate: 44100,
@@ -2,7 +2,10 @@ package tts_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -380,3 +383,70 @@ func TestProviderError(t *testing.T) {
 
 
 
+
+func TestOpenAIOutputFormat(t *testing.T) {
+	var formats []any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		formats = append(formats, body["response_format"])
+		w.Write([]byte{0, 0})
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		format     tts.Encoding
+		wantFormat any
+		wantRate   int
+	}{
+		{tts.EncodingPCM24, "pcm", 24000},
+		{tts.EncodingMP3, nil, 44100},
+	}
+
+	for _, tt := range tests {
+		provider, err := tts.NewOpenAI(
+			tts.WithAPIKey("test-key"),
+			tts.WithBaseURL(server.URL),
+			tts.WithOutputFormat(tt.format),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := provider.Synthesize(context.Background(), "hi")
+		if err != nil {
+			t.Fatalf("Synthesize: %v", err)
+		}
+		if got := formats[len(formats)-1]; got != tt.wantFormat {
+			t.Errorf("%s: response_format = %v, want %v", tt.format, got, tt.wantFormat)
+		}
+		if result.Format.Encoding != tt.format || result.Format.SampleRate != tt.wantRate {
+			t.Errorf("%s: format = %+v", tt.format, result.Format)
+		}
+	}
+}
+
+func TestOpenAINoKey(t *testing.T) {
+	// OpenAI's own endpoint needs a key
+	if _, err := tts.NewOpenAI(); err != tts.ErrNoAPIKey {
+		t.Errorf("NewOpenAI() = %v, want ErrNoAPIKey", err)
+	}
+
+	// A local server doesn't, and gets no Authorization header
+	auth := "unset"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		w.Write([]byte{0, 0})
+	}))
+	defer server.Close()
+
+	provider, err := tts.NewOpenAI(tts.WithBaseURL(server.URL), tts.WithOutputFormat(tts.EncodingPCM24))
+	if err != nil {
+		t.Fatalf("NewOpenAI(local) = %v, want no error", err)
+	}
+	if _, err := provider.Synthesize(context.Background(), "hi"); err != nil {
+		t.Fatalf("Synthesize: %v", err)
+	}
+	if auth != "" {
+		t.Errorf("Authorization = %q, want none", auth)
+	}
+}
@@ -0,0 +1,182 @@
+package vad
+
+import (
+	"encoding/binary"
+	"math"
+	"math/cmplx"
+)
+
+// Feature classifier tuning
+const (
+	featureSilenceDBFS = -65.0 // Frames quieter than this are never speech
+	featureFloorStart  = -50.0 // Initial noise floor ceiling, in case the stream opens on speech
+	featureFloorRise   = 10.0  // Noise floor rise rate (dB/s) on noise-like frames
+	featureFloorCreep  = 1.0   // ... and on peaky, speech-like ones, so talking doesn't raise it
+	featureNoiseFlat   = 0.3   // Flatness above which a frame looks like noise
+	featureFloorFall   = 0.2   // Noise floor smoothing when the level drops below it
+	featureBandLow     = 300.0 // Speech band (Hz)
+	featureBandHigh    = 3400.0
+)
+
+// FeatureClassifier scores frames from hand-made features: loudness over
+// an adaptive noise floor, spectral flatness (speech is peaky, fans and
+// hiss are flat) and the share of energy in the speech band. It needs no
+// model and costs a 512-point FFT per frame.
+type FeatureClassifier struct {
+	sampleRate int
+	floor      float64 // Noise floor (dBFS)
+	haveFloor  bool
+
+	// Threshold is the probability IsSpeech treats as speech.
+	Threshold float64
+
+	window []float64
+	spec   []complex128
+}
+
+// NewFeatureClassifier creates a classifier for audio at sampleRate.
+func NewFeatureClassifier(sampleRate int) *FeatureClassifier {
+	return &FeatureClassifier{sampleRate: sampleRate, Threshold: DefaultConfig().Threshold}
+}
+
+// Probability implements Classifier.
+func (c *FeatureClassifier) Probability(frame []int16) float64 {
+	if len(frame) == 0 {
+		return 0
+	}
+	level := levelDBFS(frame)
+	if level < featureSilenceDBFS {
+		c.track(level, featureFloorRise, len(frame))
+		return 0
+	}
+
+	flatness, band := c.spectrum(frame)
+	rise := featureFloorRise
+	if flatness < featureNoiseFlat {
+		rise = featureFloorCreep
+	}
+	c.track(level, rise, len(frame))
+	snr := level - c.floor
+
+	// Logistic blend; each term is zero at a typical decision point
+	z := 0.5*(snr-8) + 6*(0.35-flatness) + 4*(band-0.5)
+	return 1 / (1 + math.Exp(-z))
+}
+
+// IsSpeech scores little-endian PCM16 bytes, so the classifier can stand
+// in for conversation.VAD in a Cascade.
+func (c *FeatureClassifier) IsSpeech(frame []byte) bool {
+	samples := make([]int16, len(frame)/2)
+	for i := range samples {
+		samples[i] = int16(binary.LittleEndian.Uint16(frame[2*i:]))
+	}
+	return c.Probability(samples) >= c.Threshold
+}
+
+// Reset forgets the noise floor.
+func (c *FeatureClassifier) Reset() {
+	c.haveFloor = false
+}
+
+// track follows the noise floor: quickly down, up at rise dB/s.
+func (c *FeatureClassifier) track(level, rise float64, n int) {
+	if math.IsInf(level, -1) {
+		return
+	}
+	if !c.haveFloor {
+		c.floor = min(level, featureFloorStart)
+		c.haveFloor = true
+		return
+	}
+	if level < c.floor {
+		c.floor += featureFloorFall * (level - c.floor)
+		return
+	}
+	c.floor += min(level-c.floor, rise*float64(n)/float64(c.sampleRate))
+}
+
+// spectrum returns the spectral flatness and speech-band energy share of
+// a Hann-windowed frame.
+func (c *FeatureClassifier) spectrum(frame []int16) (flatness, band float64) {
+	n := 1
+	for n < len(frame) {
+		n <<= 1
+	}
+	if len(c.window) != len(frame) {
+		c.window = make([]float64, len(frame))
+		for i := range c.window {
+			c.window[i] = 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(len(frame)))
+		}
+	}
+	if len(c.spec) != n {
+		c.spec = make([]complex128, n)
+	}
+	for i := range c.spec {
+		c.spec[i] = 0
+		if i < len(frame) {
+			c.spec[i] = complex(float64(frame[i])/32768*c.window[i], 0)
+		}
+	}
+	fft(c.spec)
+
+	hz := float64(c.sampleRate) / float64(n)
+	var total, inBand, logSum float64
+	var bins int
+	for k := 1; k < n/2; k++ {
+		p := real(c.spec[k])*real(c.spec[k]) + imag(c.spec[k])*imag(c.spec[k])
+		total += p
+		f := float64(k) * hz
+		if f >= featureBandLow && f <= featureBandHigh {
+			inBand += p
+			logSum += math.Log(p + 1e-12)
+			bins++
+		}
+	}
+	if total == 0 || bins == 0 {
+		return 1, 0
+	}
+	arith := inBand / float64(bins)
+	geo := math.Exp(logSum / float64(bins))
+	return geo / (arith + 1e-12), inBand / total
+}
+
+// levelDBFS returns the RMS level of a frame in dBFS.
+func levelDBFS(frame []int16) float64 {
+	var sum float64
+	for _, s := range frame {
+		v := float64(s) / 32768
+		sum += v * v
+	}
+	if sum == 0 {
+		return math.Inf(-1)
+	}
+	return 10 * math.Log10(sum/float64(len(frame)))
+}
+
+// fft is an in-place radix-2 FFT; len(x) must be a power of two.
+func fft(x []complex128) {
+	n := len(x)
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+	for size := 2; size <= n; size <<= 1 {
+		w := cmplx.Exp(complex(0, -2*math.Pi/float64(size)))
+		for start := 0; start < n; start += size {
+			wk := complex(1, 0)
+			for k := 0; k < size/2; k++ {
+				a, b := x[start+k], x[start+k+size/2]*wk
+				x[start+k], x[start+k+size/2] = a+b, a-b
+				wk *= w
+			}
+		}
+	}
+}
+
+var _ Classifier = (*FeatureClassifier)(nil)
@@ -0,0 +1,131 @@
+package vad
+
+import (
+	"encoding/binary"
+	"math"
+	"math/cmplx"
+	"math/rand/v2"
+	"testing"
+)
+
+const testRate = 16000
+
+// voiced returns a vowel-like signal: a 120Hz glottal pulse train through
+// two formant resonators, at roughly the given peak level.
+func voiced(n int, level float64) []int16 {
+	out := make([]int16, n)
+	type res struct{ a1, a2, y1, y2 float64 }
+	formant := func(f, bw float64) res {
+		r := math.Exp(-math.Pi * bw / testRate)
+		return res{a1: 2 * r * math.Cos(2*math.Pi*f/testRate), a2: -r * r}
+	}
+	f1, f2 := formant(700, 130), formant(1200, 150)
+	period := testRate / 120
+	for i := range out {
+		var x float64
+		if i%period == 0 {
+			x = 1
+		}
+		y1 := x + f1.a1*f1.y1 + f1.a2*f1.y2
+		f1.y2, f1.y1 = f1.y1, y1
+		y2 := y1 + f2.a1*f2.y1 + f2.a2*f2.y2
+		f2.y2, f2.y1 = f2.y1, y2
+		out[i] = int16(max(-32768, min(32767, level*y2/40)))
+	}
+	return out
+}
+
+// noise returns white noise at the given RMS.
+func noise(rng *rand.Rand, n int, rms float64) []int16 {
+	out := make([]int16, n)
+	for i := range out {
+		out[i] = int16(max(-32768, min(32767, rms*rng.NormFloat64())))
+	}
+	return out
+}
+
+func mixed(a, b []int16) []int16 {
+	out := make([]int16, len(a))
+	for i := range a {
+		out[i] = int16(max(-32768, min(32767, int(a[i])+int(b[i]))))
+	}
+	return out
+}
+
+// meanProbability scores 20ms frames of s and averages the last half.
+func meanProbability(c Classifier, s []int16) float64 {
+	frame := testRate / 50
+	var probs []float64
+	for off := 0; off+frame <= len(s); off += frame {
+		probs = append(probs, c.Probability(s[off:off+frame]))
+	}
+	var sum float64
+	tail := probs[len(probs)/2:]
+	for _, p := range tail {
+		sum += p
+	}
+	return sum / float64(len(tail))
+}
+
+func TestFeatureClassifier(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	room := noise(rng, 2*testRate, 60)
+
+	tests := []struct {
+		name     string
+		warmup   []int16
+		signal   []int16
+		min, max float64
+	}{
+		{"digital silence", nil, make([]int16, testRate), 0, 0},
+		{"quiet room", room, noise(rng, testRate, 60), 0, 0.1},
+		{"loud fan", noise(rng, 4*testRate, 2000), noise(rng, testRate, 2000), 0, 0.1},
+		{"voice in quiet room", room, mixed(voiced(testRate, 3000), noise(rng, testRate, 60)), 0.9, 1},
+		{"voice over fan", noise(rng, 2*testRate, 300), mixed(voiced(testRate, 3000), noise(rng, testRate, 300)), 0.7, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewFeatureClassifier(testRate)
+			if tt.warmup != nil {
+				meanProbability(c, tt.warmup)
+			}
+			if p := meanProbability(c, tt.signal); p < tt.min || p > tt.max {
+				t.Errorf("probability = %.2f, want [%.2f, %.2f]", p, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestFeatureClassifier_IsSpeech(t *testing.T) {
+	c := NewFeatureClassifier(testRate)
+	c.Probability(make([]int16, 320))
+
+	frame := voiced(320, 3000)
+	pcm := make([]byte, 2*len(frame))
+	for i, s := range frame {
+		binary.LittleEndian.PutUint16(pcm[2*i:], uint16(s))
+	}
+	if !c.IsSpeech(pcm) {
+		t.Error("IsSpeech(voice) = false, want true")
+	}
+	if c.IsSpeech(make([]byte, 640)) {
+		t.Error("IsSpeech(silence) = true, want false")
+	}
+}
+
+func TestFFT(t *testing.T) {
+	x := make([]complex128, 16)
+	for i := range x {
+		x[i] = complex(math.Cos(2*math.Pi*3*float64(i)/16), 0)
+	}
+	fft(x)
+	for k, v := range x {
+		want := 0.0
+		if k == 3 || k == 13 {
+			want = 8
+		}
+		if math.Abs(cmplx.Abs(v)-want) > 1e-9 {
+			t.Errorf("|X[%d]| = %.3f, want %.0f", k, cmplx.Abs(v), want)
+		}
+	}
+}
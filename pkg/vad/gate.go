@@ -0,0 +1,82 @@
+package vad
+
+import "time"
+
+// Gate passes uplink audio only during the user's turn: from SpeechStart
+// (plus some earlier audio, so onsets aren't clipped) until the Endpoint.
+// Pauses between words inside a turn are kept.
+//
+// The gate is driven by a Detector's events, so it can gate audio at a
+// different rate from the one the detector runs at.
+type Gate struct {
+	prefix int
+	ring   []int16
+	open   bool
+}
+
+// NewGate creates a gate for audio at sampleRate. prefix is the audio
+// kept from before SpeechStart; it should cover the detector's MinSpeech
+// plus PrefixPadding (see Config.GatePrefix).
+func NewGate(sampleRate int, prefix time.Duration) *Gate {
+	return &Gate{prefix: int(int64(sampleRate) * int64(prefix) / int64(time.Second))}
+}
+
+// GatePrefix returns the prefix a Gate needs so the audio sent starts
+// PrefixPadding before speech did.
+func (c Config) GatePrefix() time.Duration {
+	return c.MinSpeech + c.PrefixPadding
+}
+
+// Process takes a block of audio and the events the detector produced
+// for the same block. It returns the audio to send, nil while closed.
+func (g *Gate) Process(samples []int16, events []Event) []int16 {
+	wasOpen := g.open
+	closing := false
+	for _, ev := range events {
+		switch ev.Type {
+		case SpeechStart:
+			g.open = true
+			closing = false
+		case Endpoint:
+			closing = true
+		}
+	}
+
+	var out []int16
+	switch {
+	case g.open && !wasOpen:
+		out = append(append(out, g.ring...), samples...)
+		g.ring = g.ring[:0]
+	case g.open:
+		out = samples
+	default:
+		g.remember(samples)
+	}
+	if closing {
+		g.open = false
+	}
+	return out
+}
+
+// Open reports whether the gate is passing audio.
+func (g *Gate) Open() bool {
+	return g.open
+}
+
+// Reset closes the gate and drops buffered audio.
+func (g *Gate) Reset() {
+	g.open = false
+	g.ring = g.ring[:0]
+}
+
+// remember keeps the most recent prefix samples.
+func (g *Gate) remember(samples []int16) {
+	if len(samples) >= g.prefix {
+		g.ring = append(g.ring[:0], samples[len(samples)-g.prefix:]...)
+		return
+	}
+	if over := len(g.ring) + len(samples) - g.prefix; over > 0 {
+		g.ring = append(g.ring[:0], g.ring[over:]...)
+	}
+	g.ring = append(g.ring, samples...)
+}
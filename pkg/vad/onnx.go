@@ -0,0 +1,190 @@
+package vad
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+
+	"gocv.io/x/gocv"
+)
+
+// ONNXState is a recurrent tensor carried between frames.
+type ONNXState struct {
+	Input  string // Input blob name
+	Output string // Output blob holding the next value
+	Shape  []int
+}
+
+// ONNXConfig describes a Silero-style model: one frame of float audio in
+// [-1, 1], one probability out, plus optional recurrent state.
+//
+// OpenCV's DNN module has no int64 tensors, so the model must be
+// exported with its sample rate input folded in as a constant.
+type ONNXConfig struct {
+	ModelPath    string
+	SampleRate   int
+	FrameSamples int // Samples per inference
+	Context      int // Trailing samples of the previous frame prepended to each input
+	Input        string
+	Output       string
+	States       []ONNXState
+}
+
+// DefaultONNXConfig returns the layout of Silero VAD v5 at 16kHz.
+func DefaultONNXConfig() ONNXConfig {
+	return ONNXConfig{
+		ModelPath:    "models/silero_vad.onnx",
+		SampleRate:   16000,
+		FrameSamples: 512,
+		Context:      64,
+		Input:        "input",
+		Output:       "output",
+		States:       []ONNXState{{Input: "state", Output: "stateN", Shape: []int{2, 1, 128}}},
+	}
+}
+
+// ONNXClassifier runs a neural VAD through gocv's DNN module. It is more
+// robust than FeatureClassifier to music and TV, at ~1ms per frame.
+type ONNXClassifier struct {
+	net     gocv.Net
+	cfg     ONNXConfig
+	input   []float32
+	states  [][]float32
+	outputs []string
+	err     error
+}
+
+// NewONNXClassifier loads a model.
+func NewONNXClassifier(cfg ONNXConfig) (*ONNXClassifier, error) {
+	if _, err := os.Stat(cfg.ModelPath); err != nil {
+		return nil, fmt.Errorf("vad model: %w", err)
+	}
+	if cfg.FrameSamples <= 0 || cfg.Context < 0 {
+		return nil, fmt.Errorf("vad model: invalid frame %d / context %d", cfg.FrameSamples, cfg.Context)
+	}
+
+	net := gocv.ReadNetFromONNX(cfg.ModelPath)
+	if net.Empty() {
+		return nil, fmt.Errorf("failed to load VAD model from %s", cfg.ModelPath)
+	}
+	net.SetPreferableBackend(gocv.NetBackendDefault)
+	net.SetPreferableTarget(gocv.NetTargetCPU)
+
+	c := &ONNXClassifier{
+		net:     net,
+		cfg:     cfg,
+		input:   make([]float32, cfg.Context+cfg.FrameSamples),
+		outputs: []string{cfg.Output},
+	}
+	for _, s := range cfg.States {
+		n := 1
+		for _, d := range s.Shape {
+			n *= d
+		}
+		c.states = append(c.states, make([]float32, n))
+		c.outputs = append(c.outputs, s.Output)
+	}
+	return c, nil
+}
+
+// FrameSamples returns the model's frame size; the Detector frames audio
+// to match.
+func (c *ONNXClassifier) FrameSamples() int {
+	return c.cfg.FrameSamples
+}
+
+// SampleRate returns the rate the model expects.
+func (c *ONNXClassifier) SampleRate() int {
+	return c.cfg.SampleRate
+}
+
+// Probability implements Classifier. Frames of the wrong size and
+// inference failures score 0; see Err.
+func (c *ONNXClassifier) Probability(frame []int16) float64 {
+	if len(frame) != c.cfg.FrameSamples {
+		c.err = fmt.Errorf("vad model: frame of %d samples, want %d", len(frame), c.cfg.FrameSamples)
+		return 0
+	}
+
+	// Slide the context window along, then append the new frame
+	copy(c.input, c.input[c.cfg.FrameSamples:])
+	for i, s := range frame {
+		c.input[c.cfg.Context+i] = float32(s) / 32768
+	}
+
+	p, err := c.infer()
+	c.err = err
+	return p
+}
+
+func (c *ONNXClassifier) infer() (float64, error) {
+	in, err := tensor(c.input, []int{1, len(c.input)})
+	if err != nil {
+		return 0, err
+	}
+	defer in.Close()
+	c.net.SetInput(in, c.cfg.Input)
+
+	for i, s := range c.cfg.States {
+		m, err := tensor(c.states[i], s.Shape)
+		if err != nil {
+			return 0, err
+		}
+		defer m.Close()
+		c.net.SetInput(m, s.Input)
+	}
+
+	outs := c.net.ForwardLayers(c.outputs)
+	defer func() {
+		for i := range outs {
+			outs[i].Close()
+		}
+	}()
+	if len(outs) != len(c.outputs) {
+		return 0, fmt.Errorf("vad model: %d outputs, want %d", len(outs), len(c.outputs))
+	}
+
+	for i := range c.states {
+		data, err := outs[i+1].DataPtrFloat32()
+		if err != nil || len(data) != len(c.states[i]) {
+			return 0, fmt.Errorf("vad model: bad state %q", c.cfg.States[i].Output)
+		}
+		copy(c.states[i], data)
+	}
+	data, err := outs[0].DataPtrFloat32()
+	if err != nil || len(data) == 0 {
+		return 0, fmt.Errorf("vad model: bad output %q", c.cfg.Output)
+	}
+	return math.Max(0, math.Min(1, float64(data[0]))), nil
+}
+
+// Err returns the error from the latest frame, if any.
+func (c *ONNXClassifier) Err() error {
+	return c.err
+}
+
+// Reset clears the recurrent state and context.
+func (c *ONNXClassifier) Reset() {
+	clear(c.input)
+	for _, s := range c.states {
+		clear(s)
+	}
+	c.err = nil
+}
+
+// Close releases the network.
+func (c *ONNXClassifier) Close() error {
+	return c.net.Close()
+}
+
+// tensor wraps float data in an N-dimensional Mat.
+func tensor(data []float32, shape []int) (gocv.Mat, error) {
+	buf := make([]byte, 4*len(data))
+	for i, v := range data {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(v))
+	}
+	return gocv.NewMatWithSizesFromBytes(shape, gocv.MatTypeCV32F, buf)
+}
+
+var _ Classifier = (*ONNXClassifier)(nil)
@@ -0,0 +1,364 @@
+// Package vad detects speech in the microphone stream locally.
+//
+// A Classifier scores each frame (FeatureClassifier needs no model;
+// ONNXClassifier runs a Silero-style network). The Detector fuses those
+// scores with the XVF3800 speaking flag from DOA readings, applies
+// hysteresis and hangover, and emits speech start/end events plus an
+// endpoint once the user has finished their turn. A Gate follows those
+// events to pass uplink audio only while someone is talking.
+package vad
+
+import (
+	"sync"
+	"time"
+
+	"github.com/teslashibe/go-reachy/pkg/audio"
+)
+
+// Classifier scores a frame of PCM16 mono audio. Implementations may keep
+// state between frames (noise estimates, recurrent model state).
+type Classifier interface {
+	// Probability returns how likely the frame is speech, in [0, 1].
+	Probability(frame []int16) float64
+
+	// Reset clears state, e.g. after a gap in capture.
+	Reset()
+}
+
+// framer is implemented by classifiers that need a fixed frame size.
+type framer interface {
+	FrameSamples() int
+}
+
+// Config tunes a Detector.
+type Config struct {
+	// SampleRate of the audio passed to Process.
+	SampleRate int
+
+	// FrameDuration is the classification frame. Ignored for classifiers
+	// with a fixed frame size.
+	FrameDuration time.Duration
+
+	// Threshold is the probability that starts speech; ReleaseThreshold
+	// (lower) is the one that ends it.
+	Threshold        float64
+	ReleaseThreshold float64
+
+	// MinSpeech is how long the probability must stay above Threshold
+	// before SpeechStart, so clicks and bumps are ignored.
+	MinSpeech time.Duration
+
+	// Hangover keeps speech on through short dips between words.
+	Hangover time.Duration
+
+	// EndSilence is the silence after speech that ends the user's turn
+	// (the Endpoint event). It is at least Hangover.
+	EndSilence time.Duration
+
+	// PrefixPadding is the audio a Gate sends from before SpeechStart.
+	PrefixPadding time.Duration
+
+	// MaxUtterance forces an endpoint on very long speech.
+	MaxUtterance time.Duration
+
+	// DOAWeight is how much the XVF3800 speaking flag counts, in [0, 1].
+	// Readings older than DOAMaxAge are ignored.
+	DOAWeight float64
+	DOAMaxAge time.Duration
+}
+
+// DefaultConfig returns settings for 24kHz uplink audio.
+func DefaultConfig() Config {
+	return Config{
+		SampleRate:       24000,
+		FrameDuration:    20 * time.Millisecond,
+		Threshold:        0.5,
+		ReleaseThreshold: 0.35,
+		MinSpeech:        100 * time.Millisecond,
+		Hangover:         200 * time.Millisecond,
+		EndSilence:       600 * time.Millisecond,
+		PrefixPadding:    300 * time.Millisecond,
+		MaxUtterance:     30 * time.Second,
+		DOAWeight:        0.2,
+		DOAMaxAge:        500 * time.Millisecond,
+	}
+}
+
+// EventType identifies a detector event.
+type EventType int
+
+const (
+	// SpeechStart fires when speech begins.
+	SpeechStart EventType = iota
+	// SpeechEnd fires when speech stops for longer than the hangover.
+	SpeechEnd
+	// Endpoint fires when the user's turn is over: EndSilence after the
+	// last SpeechEnd, or at MaxUtterance.
+	Endpoint
+)
+
+func (t EventType) String() string {
+	switch t {
+	case SpeechStart:
+		return "speech_start"
+	case SpeechEnd:
+		return "speech_end"
+	case Endpoint:
+		return "endpoint"
+	default:
+		return "unknown"
+	}
+}
+
+// Event is a speech activity change.
+type Event struct {
+	Type EventType
+
+	// Time is when the change happened in the audio stream: the first
+	// speech frame for SpeechStart, the first silent frame otherwise.
+	Time time.Time
+
+	// Duration is the speech segment (SpeechEnd) or whole turn
+	// (Endpoint) length.
+	Duration time.Duration
+
+	// Angle is the DOA angle of the talker when a fresh reading said
+	// someone was speaking.
+	Angle    float64
+	HasAngle bool
+
+	// Probability is the fused speech probability of the deciding frame.
+	Probability float64
+}
+
+// Detector turns classifier scores into speech events.
+type Detector struct {
+	cls   Classifier
+	cfg   Config
+	frame int
+
+	mu        sync.Mutex
+	listeners []func(Event)
+
+	doa     audio.DOAResult
+	doaTime time.Time
+
+	// Stream state, owned by Process
+	pending      []int16
+	pendingStart time.Time
+	run          int       // Consecutive frames above Threshold
+	runStart     time.Time // First frame of that run
+	quiet        int       // Consecutive frames below ReleaseThreshold
+	quietStart   time.Time
+	inSpeech     bool
+	segStart     time.Time
+	inTurn       bool
+	turnStart    time.Time
+	lastEnd      time.Time
+	probability  float64
+}
+
+// New creates a detector. Start from DefaultConfig; a zero sample rate,
+// frame, threshold or limit falls back to its default.
+func New(cls Classifier, cfg Config) *Detector {
+	def := DefaultConfig()
+	if cfg.SampleRate <= 0 {
+		cfg.SampleRate = def.SampleRate
+	}
+	if cfg.FrameDuration <= 0 {
+		cfg.FrameDuration = def.FrameDuration
+	}
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = def.Threshold
+	}
+	if cfg.ReleaseThreshold <= 0 || cfg.ReleaseThreshold > cfg.Threshold {
+		cfg.ReleaseThreshold = cfg.Threshold * def.ReleaseThreshold / def.Threshold
+	}
+	if cfg.MaxUtterance <= 0 {
+		cfg.MaxUtterance = def.MaxUtterance
+	}
+	if cfg.DOAMaxAge <= 0 {
+		cfg.DOAMaxAge = def.DOAMaxAge
+	}
+	if cfg.EndSilence < cfg.Hangover {
+		cfg.EndSilence = cfg.Hangover
+	}
+
+	frame := int(int64(cfg.SampleRate) * int64(cfg.FrameDuration) / int64(time.Second))
+	if f, ok := cls.(framer); ok {
+		frame = f.FrameSamples()
+		cfg.FrameDuration = time.Duration(frame) * time.Second / time.Duration(cfg.SampleRate)
+	}
+	return &Detector{cls: cls, cfg: cfg, frame: max(frame, 1)}
+}
+
+// Config returns the effective configuration.
+func (d *Detector) Config() Config {
+	return d.cfg
+}
+
+// OnEvent registers a listener. Listeners run on the goroutine calling
+// Process, in registration order.
+func (d *Detector) OnEvent(fn func(Event)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.listeners = append(d.listeners, fn)
+}
+
+// UpdateDOA records a DOA reading; it has the audio.DOAHandler signature
+// so it can be added as a listener on the DOA client.
+func (d *Detector) UpdateDOA(doa *audio.DOAResult) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.doa = *doa
+	d.doaTime = time.Now()
+}
+
+// Speaking reports whether the detector is inside a speech segment.
+func (d *Detector) Speaking() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.inSpeech
+}
+
+// Probability returns the fused probability of the latest frame.
+func (d *Detector) Probability() float64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.probability
+}
+
+// Process classifies samples captured starting at t and returns the
+// events they produced, which are also sent to listeners. Audio is
+// framed across calls; t is only used when no partial frame is pending.
+func (d *Detector) Process(samples []int16, t time.Time) []Event {
+	d.mu.Lock()
+	if len(d.pending) == 0 {
+		d.pendingStart = t
+	}
+	d.pending = append(d.pending, samples...)
+
+	var events []Event
+	for len(d.pending) >= d.frame {
+		at := d.pendingStart
+		events = d.step(d.pending[:d.frame], at, events)
+		d.pending = d.pending[d.frame:]
+		d.pendingStart = at.Add(d.cfg.FrameDuration)
+	}
+	listeners := d.listeners
+	d.mu.Unlock()
+
+	for _, ev := range events {
+		for _, fn := range listeners {
+			fn(ev)
+		}
+	}
+	return events
+}
+
+// Reset ends any speech in progress without emitting events, e.g. after
+// a gap in capture.
+func (d *Detector) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.cls.Reset()
+	d.pending = nil
+	d.run, d.quiet = 0, 0
+	d.inSpeech, d.inTurn = false, false
+	d.probability = 0
+}
+
+// step runs one frame through the state machine. Caller holds mu.
+func (d *Detector) step(frame []int16, at time.Time, events []Event) []Event {
+	p := d.fuse(d.cls.Probability(frame), at)
+	d.probability = p
+	end := at.Add(d.cfg.FrameDuration)
+
+	if !d.inSpeech {
+		if p >= d.cfg.Threshold {
+			if d.run == 0 {
+				d.runStart = at
+			}
+			d.run++
+		} else {
+			d.run = 0
+		}
+
+		if d.run > 0 && end.Sub(d.runStart) >= d.cfg.MinSpeech {
+			d.inSpeech = true
+			d.quiet = 0
+			d.segStart = d.runStart
+			if !d.inTurn {
+				d.inTurn = true
+				d.turnStart = d.runStart
+			}
+			ev := Event{Type: SpeechStart, Time: d.runStart, Probability: p}
+			ev.Angle, ev.HasAngle = d.angle(at)
+			return append(events, ev)
+		}
+
+		if d.inTurn && end.Sub(d.lastEnd) >= d.cfg.EndSilence {
+			d.inTurn = false
+			return append(events, Event{Type: Endpoint, Time: d.lastEnd, Duration: d.lastEnd.Sub(d.turnStart), Probability: p})
+		}
+		return events
+	}
+
+	if p >= d.cfg.ReleaseThreshold {
+		d.quiet = 0
+	} else {
+		if d.quiet == 0 {
+			d.quietStart = at
+		}
+		d.quiet++
+	}
+
+	switch {
+	case d.quiet > 0 && end.Sub(d.quietStart) >= d.cfg.Hangover:
+		d.inSpeech = false
+		d.run = 0
+		d.lastEnd = d.quietStart
+		events = append(events, Event{Type: SpeechEnd, Time: d.quietStart, Duration: d.quietStart.Sub(d.segStart), Probability: p})
+		if end.Sub(d.lastEnd) >= d.cfg.EndSilence {
+			d.inTurn = false
+			events = append(events, Event{Type: Endpoint, Time: d.lastEnd, Duration: d.lastEnd.Sub(d.turnStart), Probability: p})
+		}
+	case end.Sub(d.turnStart) >= d.cfg.MaxUtterance:
+		d.inSpeech, d.inTurn = false, false
+		d.run = 0
+		events = append(events,
+			Event{Type: SpeechEnd, Time: end, Duration: end.Sub(d.segStart), Probability: p},
+			Event{Type: Endpoint, Time: end, Duration: end.Sub(d.turnStart), Probability: p})
+	}
+	return events
+}
+
+// fuse blends the classifier score with a fresh DOA speaking flag.
+// Caller holds mu.
+func (d *Detector) fuse(p float64, at time.Time) float64 {
+	if d.cfg.DOAWeight <= 0 || !d.doaFresh(at) {
+		return p
+	}
+	var flag float64
+	if d.doa.Speaking {
+		flag = 1
+	}
+	return (1-d.cfg.DOAWeight)*p + d.cfg.DOAWeight*flag
+}
+
+// angle returns the talker direction if a fresh reading has one.
+// Caller holds mu.
+func (d *Detector) angle(at time.Time) (float64, bool) {
+	if !d.doaFresh(at) || !d.doa.Speaking {
+		return 0, false
+	}
+	return d.doa.Angle, true
+}
+
+func (d *Detector) doaFresh(at time.Time) bool {
+	if d.doaTime.IsZero() {
+		return false
+	}
+	age := at.Sub(d.doaTime)
+	return age > -d.cfg.DOAMaxAge && age < d.cfg.DOAMaxAge
+}
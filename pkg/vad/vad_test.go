@@ -0,0 +1,199 @@
+package vad
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/teslashibe/go-reachy/pkg/audio"
+)
+
+// levelClassifier reads the probability off the audio: a frame of
+// samples valued 70 scores 0.7. It makes detector tests exact.
+type levelClassifier struct{}
+
+func (levelClassifier) Probability(frame []int16) float64 { return float64(frame[0]) / 100 }
+func (levelClassifier) Reset()                            {}
+
+var testStart = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// script renders (probability, duration) pairs as 1kHz test audio.
+func script(parts ...any) []int16 {
+	var out []int16
+	for i := 0; i < len(parts); i += 2 {
+		p, d := parts[i].(float64), parts[i+1].(time.Duration)
+		for range int(d / time.Millisecond) {
+			out = append(out, int16(p*100))
+		}
+	}
+	return out
+}
+
+func testDetector(mod func(*Config)) *Detector {
+	cfg := DefaultConfig()
+	cfg.SampleRate = 1000
+	cfg.FrameDuration = 10 * time.Millisecond
+	cfg.DOAWeight = 0
+	if mod != nil {
+		mod(&cfg)
+	}
+	return New(levelClassifier{}, cfg)
+}
+
+// describe renders events as "type@ms/duration" relative to testStart.
+func describe(events []Event) string {
+	var parts []string
+	for _, ev := range events {
+		s := fmt.Sprintf("%v@%d", ev.Type, ev.Time.Sub(testStart).Milliseconds())
+		if ev.Type != SpeechStart {
+			s += fmt.Sprintf("/%d", ev.Duration.Milliseconds())
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestDetector_Events(t *testing.T) {
+	tests := []struct {
+		name  string
+		audio []int16
+		want  string
+	}{
+		{
+			"utterance",
+			script(0.0, 200*time.Millisecond, 0.9, 500*time.Millisecond, 0.0, time.Second),
+			"speech_start@200 speech_end@700/500 endpoint@700/500",
+		},
+		{
+			"pause inside hangover",
+			script(0.9, 300*time.Millisecond, 0.1, 150*time.Millisecond, 0.9, 300*time.Millisecond, 0.0, time.Second),
+			"speech_start@0 speech_end@750/750 endpoint@750/750",
+		},
+		{
+			"pause between phrases",
+			script(0.9, 300*time.Millisecond, 0.0, 400*time.Millisecond, 0.9, 300*time.Millisecond, 0.0, time.Second),
+			"speech_start@0 speech_end@300/300 speech_start@700 speech_end@1000/300 endpoint@1000/1000",
+		},
+		{
+			"hysteresis holds speech between thresholds",
+			script(0.9, 200*time.Millisecond, 0.4, 500*time.Millisecond, 0.0, time.Second),
+			"speech_start@0 speech_end@700/700 endpoint@700/700",
+		},
+		{
+			"click is ignored",
+			script(0.9, 50*time.Millisecond, 0.0, time.Second),
+			"",
+		},
+		{
+			"between thresholds never starts",
+			script(0.4, time.Second),
+			"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := testDetector(nil)
+			// Feed in uneven chunks to exercise framing
+			var events []Event
+			for off := 0; off < len(tt.audio); off += 37 {
+				end := min(off+37, len(tt.audio))
+				events = append(events, d.Process(tt.audio[off:end], testStart.Add(time.Duration(off)*time.Millisecond))...)
+			}
+			if got := describe(events); got != tt.want {
+				t.Errorf("events = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetector_MaxUtterance(t *testing.T) {
+	d := testDetector(func(c *Config) { c.MaxUtterance = 500 * time.Millisecond })
+	events := d.Process(script(0.9, 800*time.Millisecond), testStart)
+
+	want := "speech_start@0 speech_end@500/500 endpoint@500/500 speech_start@500"
+	if got := describe(events); got != want {
+		t.Errorf("events = %q, want %q", got, want)
+	}
+	if !d.Speaking() {
+		t.Error("Speaking() = false while speech continues")
+	}
+}
+
+func TestDetector_DOAFusion(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name      string
+		doa       *audio.DOAResult
+		at        time.Time
+		wantStart bool
+	}{
+		{"no DOA", nil, now, false},
+		{"DOA speaking", &audio.DOAResult{Speaking: true, Angle: 0.4}, now, true},
+		{"DOA silent", &audio.DOAResult{Speaking: false, Angle: 0.4}, now, false},
+		{"stale DOA", &audio.DOAResult{Speaking: true, Angle: 0.4}, now.Add(2 * time.Second), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 0.45 alone is below threshold; the speaking flag tips it over
+			d := testDetector(func(c *Config) { c.DOAWeight = 0.2 })
+			if tt.doa != nil {
+				d.UpdateDOA(tt.doa)
+			}
+			events := d.Process(script(0.45, 300*time.Millisecond), tt.at)
+			started := len(events) > 0 && events[0].Type == SpeechStart
+			if started != tt.wantStart {
+				t.Fatalf("started = %v, want %v (events %q)", started, tt.wantStart, describe(events))
+			}
+			if started && (!events[0].HasAngle || events[0].Angle != 0.4) {
+				t.Errorf("angle = %v/%v, want 0.4", events[0].Angle, events[0].HasAngle)
+			}
+		})
+	}
+}
+
+func TestDetector_Listeners(t *testing.T) {
+	d := testDetector(nil)
+	var got []EventType
+	d.OnEvent(func(ev Event) { got = append(got, ev.Type) })
+
+	d.Process(script(0.9, 300*time.Millisecond, 0.0, time.Second), testStart)
+	want := []EventType{SpeechStart, SpeechEnd, Endpoint}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("listener saw %v, want %v", got, want)
+	}
+}
+
+func TestGate(t *testing.T) {
+	d := testDetector(func(c *Config) { c.PrefixPadding = 100 * time.Millisecond })
+	g := NewGate(1000, d.Config().GatePrefix())
+
+	// 1s of silence, a 400ms utterance, then silence past the endpoint
+	in := script(0.0, time.Second, 0.9, 400*time.Millisecond, 0.0, 1500*time.Millisecond)
+	var sent []int16
+	for off := 0; off < len(in); off += 20 {
+		block := in[off : off+20]
+		events := d.Process(block, testStart.Add(time.Duration(off)*time.Millisecond))
+		sent = append(sent, g.Process(block, events)...)
+	}
+
+	var speech int
+	for _, s := range sent {
+		if s > 0 {
+			speech++
+		}
+	}
+	if speech != 400 {
+		t.Errorf("sent %d speech samples, want all 400", speech)
+	}
+	// Prefix padding before, the turn's trailing silence after
+	if lead := len(sent) - speech; lead < 100 || lead > 100+100+600+20 {
+		t.Errorf("sent %d non-speech samples, want prefix plus trailing silence", lead)
+	}
+	if sent[0] != 0 {
+		t.Errorf("first sample = %d, want prefix silence", sent[0])
+	}
+	if g.Open() {
+		t.Error("gate still open after endpoint")
+	}
+}
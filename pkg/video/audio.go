@@ -0,0 +1,144 @@
+package video
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// MicSampleRate is the rate of decoded microphone audio.
+const MicSampleRate = 48000
+
+// DefaultAudioBuffer is the subscription buffer used when none is given:
+// 50 frames is one second of 20ms Opus frames.
+const DefaultAudioBuffer = 50
+
+// AudioFrame is one decoded microphone frame.
+type AudioFrame struct {
+	// Samples is 48kHz mono PCM. It is shared between subscribers and
+	// must not be modified.
+	Samples []int16
+
+	// Time is when the packet arrived.
+	Time time.Time
+
+	// Timestamp and Seq come from the RTP header, so consumers can spot
+	// gaps.
+	Timestamp uint32
+	Seq       uint16
+}
+
+// Duration returns the length of the frame.
+func (f AudioFrame) Duration() time.Duration {
+	return time.Duration(len(f.Samples)) * time.Second / MicSampleRate
+}
+
+// AudioSubscription receives microphone frames from a Client.
+//
+// Frames are buffered up to a fixed depth; when the consumer falls
+// behind the oldest frame is dropped so the stream stays live.
+type AudioSubscription struct {
+	ch      chan AudioFrame
+	hub     *audioHub
+	dropped atomic.Uint64
+}
+
+// Frames returns the frame channel. It is closed by Close or when the
+// client closes.
+func (s *AudioSubscription) Frames() <-chan AudioFrame {
+	return s.ch
+}
+
+// Dropped returns how many frames were discarded because the consumer
+// was too slow.
+func (s *AudioSubscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close stops delivery and closes the channel.
+func (s *AudioSubscription) Close() {
+	s.hub.remove(s)
+}
+
+// deliver queues a frame, dropping the oldest one if the buffer is full.
+// Only the hub calls it, under its lock.
+func (s *AudioSubscription) deliver(f AudioFrame) {
+	for {
+		select {
+		case s.ch <- f:
+			return
+		default:
+		}
+		select {
+		case <-s.ch:
+			s.dropped.Add(1)
+		default:
+		}
+	}
+}
+
+// audioHub fans decoded frames out to subscribers.
+type audioHub struct {
+	mu     sync.Mutex
+	subs   map[*AudioSubscription]struct{}
+	closed bool
+}
+
+func (h *audioHub) subscribe(buffer int) *AudioSubscription {
+	if buffer <= 0 {
+		buffer = DefaultAudioBuffer
+	}
+	s := &AudioSubscription{ch: make(chan AudioFrame, buffer), hub: h}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		close(s.ch)
+		return s
+	}
+	if h.subs == nil {
+		h.subs = make(map[*AudioSubscription]struct{})
+	}
+	h.subs[s] = struct{}{}
+	return s
+}
+
+func (h *audioHub) publish(f AudioFrame) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for s := range h.subs {
+		s.deliver(f)
+	}
+}
+
+func (h *audioHub) remove(s *AudioSubscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subs[s]; ok {
+		delete(h.subs, s)
+		close(s.ch)
+	}
+}
+
+func (h *audioHub) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for s := range h.subs {
+		close(s.ch)
+	}
+	h.subs = nil
+}
+
+func (h *audioHub) count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subs)
+}
+
+// SubscribeAudio returns a live stream of decoded microphone frames.
+// buffer is the queue depth in frames (0 uses DefaultAudioBuffer).
+// Each subscriber gets every frame; call Close when done.
+func (c *Client) SubscribeAudio(buffer int) *AudioSubscription {
+	return c.audioHub.subscribe(buffer)
+}
@@ -0,0 +1,75 @@
+package video
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAudioHub_FanOut(t *testing.T) {
+	var h audioHub
+	a := h.subscribe(4)
+	b := h.subscribe(4)
+
+	h.publish(AudioFrame{Samples: make([]int16, 960), Seq: 1})
+
+	for name, s := range map[string]*AudioSubscription{"a": a, "b": b} {
+		select {
+		case f := <-s.Frames():
+			if f.Seq != 1 {
+				t.Errorf("%s: seq = %d, want 1", name, f.Seq)
+			}
+		default:
+			t.Errorf("%s: no frame delivered", name)
+		}
+	}
+}
+
+func TestAudioHub_DropsOldest(t *testing.T) {
+	var h audioHub
+	s := h.subscribe(2)
+
+	for i := uint16(1); i <= 5; i++ {
+		h.publish(AudioFrame{Seq: i})
+	}
+
+	if got := s.Dropped(); got != 3 {
+		t.Errorf("Dropped() = %d, want 3", got)
+	}
+	for _, want := range []uint16{4, 5} {
+		if f := <-s.Frames(); f.Seq != want {
+			t.Errorf("seq = %d, want %d", f.Seq, want)
+		}
+	}
+}
+
+func TestAudioHub_Close(t *testing.T) {
+	var h audioHub
+	a := h.subscribe(1)
+	b := h.subscribe(1)
+
+	a.Close()
+	if _, ok := <-a.Frames(); ok {
+		t.Error("channel open after Close")
+	}
+	if n := h.count(); n != 1 {
+		t.Errorf("count = %d after Close, want 1", n)
+	}
+	a.Close() // Idempotent
+
+	h.close()
+	if _, ok := <-b.Frames(); ok {
+		t.Error("channel open after hub close")
+	}
+
+	late := h.subscribe(1)
+	if _, ok := <-late.Frames(); ok {
+		t.Error("subscription on closed hub is open")
+	}
+}
+
+func TestAudioFrame_Duration(t *testing.T) {
+	f := AudioFrame{Samples: make([]int16, 960)}
+	if d := f.Duration(); d != 20*time.Millisecond {
+		t.Errorf("Duration() = %v, want 20ms", d)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"os"
 	"os/exec"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -24,9 +25,10 @@ type Client struct {
 	robotIP       string
 	signallingURL string
 
-	ws      *websocket.Conn
-	pc      *webrtc.PeerConnection
-	wsMutex sync.Mutex
+	ws        *websocket.Conn
+	pc        *webrtc.PeerConnection
+	wsMutex   sync.Mutex   // Serializes writes to ws
+	connMutex sync.RWMutex // Guards ws, pc, producerID, sessionID and connected
 
 	myPeerID   string
 	producerID string
@@ -41,6 +43,7 @@ type Client struct {
 
 	// Latest decoded frame
 	latestFrame []byte
+	frameTime   time.Time // When latestFrame was decoded
 	frameMutex  sync.RWMutex
 	frameReady  chan struct{}
 
@@ -50,9 +53,10 @@ type Client struct {
 	audioRecording bool
 	audioReady     chan struct{}
 	opusDecoder    *opus.Decoder
+	audioHub       audioHub // Live microphone subscribers
 
 	connected bool
-	closed    bool
+	closed    atomic.Bool
 }
 
 // NewClient creates a new WebRTC video client
@@ -87,40 +91,50 @@ func (c *Client) Connect() error {
 		HandshakeTimeout: 10 * time.Second,
 	}
 
-	var err error
-	c.ws, _, err = dialer.Dial(c.signallingURL, nil)
+	// The session's goroutines get ws and pc as arguments rather than
+	// reading the fields, which Reconnect swaps underneath them
+	ws, _, err := dialer.Dial(c.signallingURL, nil)
 	if err != nil {
 		return fmt.Errorf("signalling connect failed: %w", err)
 	}
+	c.connMutex.Lock()
+	c.ws = ws
+	c.connMutex.Unlock()
 
 	// Wait for welcome message
 	fmt.Println("  Waiting for welcome...")
-	if err := c.waitForWelcome(); err != nil {
+	if err := c.waitForWelcome(ws); err != nil {
 		return fmt.Errorf("welcome failed: %w", err)
 	}
 	fmt.Printf("  Got peer ID: %s\n", c.myPeerID[:8])
 
 	// Get producer list
 	fmt.Println("  Finding producer...")
-	if err := c.findProducer(); err != nil {
+	producerID, err := c.findProducer(ws)
+	if err != nil {
 		return fmt.Errorf("find producer failed: %w", err)
 	}
-	fmt.Printf("  Found producer: %s\n", c.producerID[:8])
+	fmt.Printf("  Found producer: %s\n", producerID[:8])
 
 	// Create peer connection
 	fmt.Println("  Creating peer connection...")
-	if err := c.createPeerConnection(); err != nil {
+	pc, err := c.createPeerConnection(ws)
+	if err != nil {
 		return fmt.Errorf("peer connection failed: %w", err)
 	}
+	c.connMutex.Lock()
+	c.pc = pc
+	c.producerID = producerID
+	c.connMutex.Unlock()
 
 	// Start session
 	fmt.Println("  Starting session...")
-	if err := c.startSession(); err != nil {
+	if err := c.startSession(ws, producerID); err != nil {
 		return fmt.Errorf("start session failed: %w", err)
 	}
 
 	// Start signalling handler
-	go c.handleSignalling()
+	go c.handleSignalling(ws, pc)
 
 	// Wait for connection
 	fmt.Println("  Waiting for video track...")
@@ -131,14 +145,16 @@ func (c *Client) Connect() error {
 		return fmt.Errorf("timeout waiting for video")
 	}
 
+	c.connMutex.Lock()
 	c.connected = true
+	c.connMutex.Unlock()
 	return nil
 }
 
-func (c *Client) waitForWelcome() error {
-	c.ws.SetReadDeadline(time.Now().Add(10 * time.Second))
-	_, msg, err := c.ws.ReadMessage()
-	c.ws.SetReadDeadline(time.Time{})
+func (c *Client) waitForWelcome(ws *websocket.Conn) error {
+	ws.SetReadDeadline(time.Now().Add(10 * time.Second))
+	_, msg, err := ws.ReadMessage()
+	ws.SetReadDeadline(time.Time{})
 
 	if err != nil {
 		return err
@@ -158,19 +174,19 @@ func (c *Client) waitForWelcome() error {
 	return nil
 }
 
-func (c *Client) findProducer() error {
+func (c *Client) findProducer(ws *websocket.Conn) (string, error) {
 	c.wsMutex.Lock()
-	err := c.ws.WriteJSON(map[string]string{"type": "list"})
+	err := ws.WriteJSON(map[string]string{"type": "list"})
 	c.wsMutex.Unlock()
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	c.ws.SetReadDeadline(time.Now().Add(5 * time.Second))
-	_, msg, err := c.ws.ReadMessage()
-	c.ws.SetReadDeadline(time.Time{})
+	ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, msg, err := ws.ReadMessage()
+	ws.SetReadDeadline(time.Time{})
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	var listResp struct {
@@ -181,43 +197,45 @@ func (c *Client) findProducer() error {
 		} `json:"producers"`
 	}
 	if err := json.Unmarshal(msg, &listResp); err != nil {
-		return err
+		return "", err
 	}
 
 	for _, p := range listResp.Producers {
 		if name, ok := p.Meta["name"]; ok && name == "reachymini" {
-			c.producerID = p.ID
-			return nil
+			return p.ID, nil
 		}
 	}
-	return fmt.Errorf("reachymini producer not found in %d producers", len(listResp.Producers))
+	return "", fmt.Errorf("reachymini producer not found in %d producers", len(listResp.Producers))
 }
 
-func (c *Client) createPeerConnection() error {
+// createPeerConnection creates the receive-only peer connection whose
+// ICE candidates are sent on ws
+func (c *Client) createPeerConnection(ws *websocket.Conn) (*webrtc.PeerConnection, error) {
 	config := webrtc.Configuration{}
 
-	var err error
-	c.pc, err = webrtc.NewPeerConnection(config)
+	pc, err := webrtc.NewPeerConnection(config)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// We want to receive video
-	if _, err = c.pc.AddTransceiverFromKind(webrtc.RTPCodecTypeVideo, webrtc.RTPTransceiverInit{
+	if _, err = pc.AddTransceiverFromKind(webrtc.RTPCodecTypeVideo, webrtc.RTPTransceiverInit{
 		Direction: webrtc.RTPTransceiverDirectionRecvonly,
 	}); err != nil {
-		return err
+		pc.Close()
+		return nil, err
 	}
 
 	// We want to receive audio too
-	if _, err = c.pc.AddTransceiverFromKind(webrtc.RTPCodecTypeAudio, webrtc.RTPTransceiverInit{
+	if _, err = pc.AddTransceiverFromKind(webrtc.RTPCodecTypeAudio, webrtc.RTPTransceiverInit{
 		Direction: webrtc.RTPTransceiverDirectionRecvonly,
 	}); err != nil {
-		return err
+		pc.Close()
+		return nil, err
 	}
 
 	// Handle incoming video/audio tracks
-	c.pc.OnTrack(func(track *webrtc.TrackRemote, receiver *webrtc.RTPReceiver) {
+	pc.OnTrack(func(track *webrtc.TrackRemote, receiver *webrtc.RTPReceiver) {
 		fmt.Printf("  Got track: %s (codec: %s)\n", track.Kind(), track.Codec().MimeType)
 		if track.Kind() == webrtc.RTPCodecTypeVideo {
 			go c.handleVideoTrack(track)
@@ -227,34 +245,39 @@ func (c *Client) createPeerConnection() error {
 	})
 
 	// Handle ICE candidates
-	c.pc.OnICECandidate(func(candidate *webrtc.ICECandidate) {
+	pc.OnICECandidate(func(candidate *webrtc.ICECandidate) {
 		if candidate != nil {
-			c.sendICECandidate(candidate)
+			c.sendICECandidate(ws, candidate)
 		}
 	})
 
-	c.pc.OnConnectionStateChange(func(state webrtc.PeerConnectionState) {
+	pc.OnConnectionStateChange(func(state webrtc.PeerConnectionState) {
 		fmt.Printf("  Connection state: %s\n", state)
 	})
 
-	return nil
+	return pc, nil
 }
 
-func (c *Client) startSession() error {
+func (c *Client) startSession(ws *websocket.Conn, producerID string) error {
 	c.wsMutex.Lock()
-	err := c.ws.WriteJSON(map[string]string{
+	err := ws.WriteJSON(map[string]string{
 		"type":   "startSession",
-		"peerId": c.producerID,
+		"peerId": producerID,
 	})
 	c.wsMutex.Unlock()
 	return err
 }
 
-func (c *Client) handleSignalling() {
-	for !c.closed {
-		_, msg, err := c.ws.ReadMessage()
+// handleSignalling reads the signalling messages of the session on ws
+// and pc until ws is closed
+func (c *Client) handleSignalling(ws *websocket.Conn, pc *webrtc.PeerConnection) {
+	for !c.closed.Load() {
+		_, msg, err := ws.ReadMessage()
 		if err != nil {
-			if !c.closed {
+			c.connMutex.RLock()
+			current := ws == c.ws
+			c.connMutex.RUnlock()
+			if !c.closed.Load() && current {
 				fmt.Printf("  Signalling error: %v\n", err)
 			}
 			return
@@ -268,10 +291,14 @@ func (c *Client) handleSignalling() {
 
 		switch baseMsg.Type {
 		case "sessionStarted":
-			c.sessionID = baseMsg.SessionID
+			c.connMutex.Lock()
+			if ws == c.ws {
+				c.sessionID = baseMsg.SessionID
+			}
+			c.connMutex.Unlock()
 
 		case "peer":
-			c.handlePeerMessage(msg)
+			c.handlePeerMessage(ws, pc, msg)
 
 		case "endSession":
 			return
@@ -279,7 +306,7 @@ func (c *Client) handleSignalling() {
 	}
 }
 
-func (c *Client) handlePeerMessage(msg []byte) {
+func (c *Client) handlePeerMessage(ws *websocket.Conn, pc *webrtc.PeerConnection, msg []byte) {
 	// Parse the peer message
 	var peerMsg map[string]interface{}
 	json.Unmarshal(msg, &peerMsg)
@@ -296,23 +323,23 @@ func (c *Client) handlePeerMessage(msg []byte) {
 				SDP:  sdpStr,
 			}
 
-			if err := c.pc.SetRemoteDescription(offer); err != nil {
+			if err := pc.SetRemoteDescription(offer); err != nil {
 				fmt.Printf("  SetRemoteDescription error: %v\n", err)
 				return
 			}
 
-			answer, err := c.pc.CreateAnswer(nil)
+			answer, err := pc.CreateAnswer(nil)
 			if err != nil {
 				fmt.Printf("  CreateAnswer error: %v\n", err)
 				return
 			}
 
-			if err := c.pc.SetLocalDescription(answer); err != nil {
+			if err := pc.SetLocalDescription(answer); err != nil {
 				fmt.Printf("  SetLocalDescription error: %v\n", err)
 				return
 			}
 
-			c.sendSDP(answer)
+			c.sendSDP(ws, answer)
 		}
 	}
 
@@ -331,7 +358,7 @@ func (c *Client) handlePeerMessage(msg []byte) {
 			sdpMLineIndex = uint16(idx.(float64))
 		}
 
-		c.pc.AddICECandidate(webrtc.ICECandidateInit{
+		pc.AddICECandidate(webrtc.ICECandidateInit{
 			Candidate:     candidate,
 			SDPMid:        &sdpMid,
 			SDPMLineIndex: &sdpMLineIndex,
@@ -339,29 +366,30 @@ func (c *Client) handlePeerMessage(msg []byte) {
 	}
 }
 
-func (c *Client) sendSDP(sdp webrtc.SessionDescription) {
+func (c *Client) sendSDP(ws *websocket.Conn, sdp webrtc.SessionDescription) {
 	msg := map[string]interface{}{
 		"type":      "peer",
-		"sessionId": c.sessionID,
+		"sessionId": c.session(),
 		"sdp": map[string]string{
 			"type": sdp.Type.String(),
 			"sdp":  sdp.SDP,
 		},
 	}
 	c.wsMutex.Lock()
-	c.ws.WriteJSON(msg)
+	ws.WriteJSON(msg)
 	c.wsMutex.Unlock()
 }
 
-func (c *Client) sendICECandidate(candidate *webrtc.ICECandidate) {
-	if c.sessionID == "" {
+func (c *Client) sendICECandidate(ws *websocket.Conn, candidate *webrtc.ICECandidate) {
+	sessionID := c.session()
+	if sessionID == "" {
 		return
 	}
 
 	init := candidate.ToJSON()
 	msg := map[string]interface{}{
 		"type":      "peer",
-		"sessionId": c.sessionID,
+		"sessionId": sessionID,
 		"ice": map[string]interface{}{
 			"candidate":     init.Candidate,
 			"sdpMid":        init.SDPMid,
@@ -369,10 +397,17 @@ func (c *Client) sendICECandidate(candidate *webrtc.ICECandidate) {
 		},
 	}
 	c.wsMutex.Lock()
-	c.ws.WriteJSON(msg)
+	ws.WriteJSON(msg)
 	c.wsMutex.Unlock()
 }
 
+// session returns the current signalling session ID
+func (c *Client) session() string {
+	c.connMutex.RLock()
+	defer c.connMutex.RUnlock()
+	return c.sessionID
+}
+
 func (c *Client) handleVideoTrack(track *webrtc.TrackRemote) {
 	// Signal that we got video
 	select {
@@ -389,7 +424,7 @@ func (c *Client) handleVideoTrack(track *webrtc.TrackRemote) {
 	hasKeyframe := false
 	var keyframeBuffer bytes.Buffer
 
-	for !c.closed {
+	for !c.closed.Load() {
 		// Read RTP packet
 		rtpPacket, _, err := track.ReadRTP()
 		if err != nil {
@@ -498,16 +533,12 @@ func (c *Client) decodeH264ToJPEG(h264Data []byte) {
 	if c.fastDecoder != nil {
 		jpegData, err := c.fastDecoder.DecodeNAL(h264Data)
 		if err == nil && len(jpegData) > 1000 {
-			c.frameMutex.Lock()
-			c.latestFrame = jpegData
-			c.frameMutex.Unlock()
+			c.setFrame(jpegData)
 			return
 		}
 		// If fast decoder returned cached frame, use it
 		if jpegData != nil && len(jpegData) > 1000 {
-			c.frameMutex.Lock()
-			c.latestFrame = jpegData
-			c.frameMutex.Unlock()
+			c.setFrame(jpegData)
 			return
 		}
 	}
@@ -550,9 +581,7 @@ func (c *Client) decodeH264ToJPEG(h264Data []byte) {
 	jpegData, err := os.ReadFile(tmpJPEG)
 	if err == nil && len(jpegData) > 1000 {
 		if len(jpegData) > 5000 && !isGrayFrame(jpegData) {
-			c.frameMutex.Lock()
-			c.latestFrame = jpegData
-			c.frameMutex.Unlock()
+			c.setFrame(jpegData)
 		}
 		os.Remove(tmpH264)
 	}
@@ -603,6 +632,22 @@ func isGrayFrame(jpegData []byte) bool {
 	return variance < 200
 }
 
+// setFrame stores a decoded frame.
+func (c *Client) setFrame(jpegData []byte) {
+	c.frameMutex.Lock()
+	c.latestFrame = jpegData
+	c.frameTime = time.Now()
+	c.frameMutex.Unlock()
+}
+
+// LastFrameTime returns when the latest frame was decoded, or the zero
+// time before the first one.
+func (c *Client) LastFrameTime() time.Time {
+	c.frameMutex.RLock()
+	defer c.frameMutex.RUnlock()
+	return c.frameTime
+}
+
 // GetFrame returns the latest video frame as JPEG bytes
 func (c *Client) GetFrame() ([]byte, error) {
 	c.frameMutex.RLock()
@@ -637,17 +682,43 @@ func (c *Client) CaptureJPEG() ([]byte, error) {
 	return c.WaitForFrame(500 * time.Millisecond)
 }
 
+// Reconnect drops the WebRTC session and connects again, e.g. after the
+// robot restarted the producer. Audio subscribers carry over.
+func (c *Client) Reconnect() error {
+	c.disconnect()
+	return c.Connect()
+}
+
 // Close closes the WebRTC connection
 func (c *Client) Close() {
-	c.closed = true
+	c.closed.Store(true)
+	c.audioHub.close()
 	if c.fastDecoder != nil {
 		c.fastDecoder.Close()
 	}
-	if c.pc != nil {
-		c.pc.Close()
-	}
-	if c.ws != nil {
-		c.ws.Close()
+	c.disconnect()
+}
+
+// disconnect clears the session and closes its peer connection and
+// signalling socket. Closing them ends the session's goroutines: their
+// reads fail, and they only use the ws and pc they were started with.
+func (c *Client) disconnect() {
+	c.connMutex.Lock()
+	ws, pc := c.ws, c.pc
+	c.ws = nil
+	c.pc = nil
+	c.connected = false
+	c.producerID = ""
+	c.sessionID = ""
+	c.connMutex.Unlock()
+
+	if pc != nil {
+		pc.Close()
+	}
+	if ws != nil {
+		c.wsMutex.Lock()
+		ws.Close()
+		c.wsMutex.Unlock()
 	}
 }
 
@@ -663,7 +734,7 @@ func (c *Client) handleAudioTrack(track *webrtc.TrackRemote) {
 	// Buffer for decoded PCM (max 120ms at 48kHz = 5760 samples)
 	frameBuf := make([]int16, 5760)
 
-	for !c.closed {
+	for !c.closed.Load() {
 		rtpPacket, _, err := track.ReadRTP()
 		if err != nil {
 			return
@@ -681,6 +752,16 @@ func (c *Client) handleAudioTrack(track *webrtc.TrackRemote) {
 			c.audioBuffer = append(c.audioBuffer, frameBuf[:n]...)
 		}
 		c.audioMutex.Unlock()
+
+		// Fan out to live subscribers (frameBuf is reused, so copy)
+		if c.audioHub.count() > 0 {
+			c.audioHub.publish(AudioFrame{
+				Samples:   append([]int16(nil), frameBuf[:n]...),
+				Time:      time.Now(),
+				Timestamp: rtpPacket.Timestamp,
+				Seq:       rtpPacket.SequenceNumber,
+			})
+		}
 	}
 }
 
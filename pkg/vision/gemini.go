@@ -3,6 +3,7 @@ package vision
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -14,6 +15,12 @@ import (
 
 // GeminiVision calls Gemini Flash to describe an image.
 func GeminiVision(apiKey string, imageData []byte, prompt string) (string, error) {
+	return GeminiVisionContext(context.Background(), apiKey, imageData, prompt)
+}
+
+// GeminiVisionContext is GeminiVision with a context that can cancel
+// the request.
+func GeminiVisionContext(ctx context.Context, apiKey string, imageData []byte, prompt string) (string, error) {
 	if apiKey == "" {
 		return "", fmt.Errorf("GOOGLE_API_KEY not set")
 	}
@@ -41,7 +48,7 @@ func GeminiVision(apiKey string, imageData []byte, prompt string) (string, error
 
 	// Using Gemini 2.0 Flash - stable model
 	url := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/gemini-2.0-flash:generateContent?key=%s", apiKey)
-	req, _ := http.NewRequest("POST", url, bytes.NewReader(jsonData))
+	req, _ := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(jsonData))
 	req.Header.Set("Content-Type", "application/json")
 
 	client := &http.Client{Timeout: 15 * time.Second}
@@ -75,6 +82,12 @@ func GeminiVision(apiKey string, imageData []byte, prompt string) (string, error
 
 // WebSearch uses Gemini with Google Search grounding to search the web.
 func WebSearch(apiKey string, query string) (string, error) {
+	return WebSearchContext(context.Background(), apiKey, query)
+}
+
+// WebSearchContext is WebSearch with a context that can cancel the
+// request.
+func WebSearchContext(ctx context.Context, apiKey string, query string) (string, error) {
 	if apiKey == "" {
 		return "", fmt.Errorf("GOOGLE_API_KEY not set")
 	}
@@ -107,7 +120,7 @@ func WebSearch(apiKey string, query string) (string, error) {
 	jsonData, _ := json.Marshal(payload)
 
 	url := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/gemini-2.0-flash:generateContent?key=%s", apiKey)
-	req, _ := http.NewRequest("POST", url, bytes.NewReader(jsonData))
+	req, _ := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(jsonData))
 	req.Header.Set("Content-Type", "application/json")
 
 	client := &http.Client{Timeout: 30 * time.Second}
@@ -23,6 +23,7 @@ var availableTools = []ToolInfo{
 	{Name: "shake_head_no", Description: "Shake head no"},
 	{Name: "get_time", Description: "Get current time"},
 	{Name: "set_timer", Description: "Set a timer"},
+	{Name: "list_timers", Description: "List timers, alarms and reminders"},
 }
 
 // handleStatus returns Eva's current state
@@ -32,11 +33,48 @@ func (s *Server) handleStatus(c *fiber.Ctx) error {
 	return c.JSON(s.state)
 }
 
-// handleListTools returns available tools
+// handleListTools returns available tools, with metrics when the tool
+// registry is wired up
 func (s *Server) handleListTools(c *fiber.Ctx) error {
+	if s.OnGetTools != nil {
+		return c.JSON(s.OnGetTools())
+	}
 	return c.JSON(availableTools)
 }
 
+// handleSetToolEnabled turns a tool on or off
+func (s *Server) handleSetToolEnabled(c *fiber.Ctx) error {
+	if s.OnSetToolEnabled == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "Tool registry not configured",
+		})
+	}
+
+	var req struct {
+		Enabled bool `json:"enabled"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	name := c.Params("name")
+	if err := s.OnSetToolEnabled(name, req.Enabled); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	state := "disabled"
+	if req.Enabled {
+		state = "enabled"
+	}
+	s.AddLog("tool", "Tool "+name+" "+state)
+
+	return c.JSON(fiber.Map{"tool": name, "enabled": req.Enabled})
+}
+
 // TriggerToolRequest is the request body for triggering a tool
 type TriggerToolRequest struct {
 	Args map[string]interface{} `json:"args"`
@@ -107,6 +145,13 @@ func (s *Server) handleCameraWS(c *websocket.Conn) {
 	client.Run()
 }
 
+// handleTelemetryWS handles WebSocket connections for live tracking telemetry
+func (s *Server) handleTelemetryWS(c *websocket.Conn) {
+	// Create hub client and run (blocks until disconnect)
+	client := hub.NewClient(s.telemetryHub, c)
+	client.Run()
+}
+
 // handleStatusWS handles WebSocket connections for status updates
 func (s *Server) handleStatusWS(c *websocket.Conn) {
 	// Send current status first (before registering with hub)
@@ -189,3 +234,61 @@ func (s *Server) handleSetListening(c *fiber.Ctx) error {
 		"status":    status,
 	})
 }
+
+// handleSetListeningMode switches between always-on, wake-word and
+// push-to-talk listening
+func (s *Server) handleSetListeningMode(c *fiber.Ctx) error {
+	var req struct {
+		Mode string `json:"mode"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid JSON: " + err.Error(),
+		})
+	}
+
+	if s.OnSetListeningMode == nil {
+		return c.Status(503).JSON(fiber.Map{
+			"error": "Listening modes not available",
+		})
+	}
+	if err := s.OnSetListeningMode(req.Mode); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	s.AddLog("info", "Listening mode: "+req.Mode)
+
+	return c.JSON(fiber.Map{
+		"listening_mode": req.Mode,
+	})
+}
+
+// handlePushToTalk opens the microphone while the dashboard's talk
+// button is held
+func (s *Server) handlePushToTalk(c *fiber.Ctx) error {
+	var req struct {
+		Pressed bool `json:"pressed"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid JSON: " + err.Error(),
+		})
+	}
+
+	if s.OnPushToTalk == nil {
+		return c.Status(503).JSON(fiber.Map{
+			"error": "Push-to-talk not available",
+		})
+	}
+	if err := s.OnPushToTalk(req.Pressed); err != nil {
+		return c.Status(409).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"pressed": req.Pressed,
+	})
+}
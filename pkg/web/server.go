@@ -19,15 +19,30 @@ type EvaState struct {
 	WebRTCConnected  bool    `json:"webrtc_connected"`
 	Speaking         bool    `json:"speaking"`
 	Listening        bool    `json:"listening"`
+	UserSpeaking     bool    `json:"user_speaking"` // Local VAD hears the user
 	Paused           bool    `json:"paused"`  // Eva completely paused (not listening or responding)
 	Muted            bool    `json:"muted"`   // Microphone muted (not listening but can respond to tools)
+	ListeningMode    string  `json:"listening_mode"`  // always_on, wake_word or push_to_talk
+	MicOpen          bool    `json:"mic_open"`        // Mic audio is being sent
+	MicOpenReason    string  `json:"mic_open_reason"` // Why: always_on, wake_word, push_to_talk, follow_up
 	HeadYaw          float64 `json:"head_yaw"`
 	FacePosition     float64 `json:"face_position"` // 0-100%
-	ActiveTimer      string  `json:"active_timer"`
+	ActiveTimer      string  `json:"active_timer"` // Soonest timer/alarm/reminder, e.g. "pasta timer in 4:59"
+	Timers           []TimerState `json:"timers"`
 	LastUserMessage  string  `json:"last_user_message"`
 	LastEvaMessage   string  `json:"last_eva_message"`
 }
 
+// TimerState is a pending timer, alarm or reminder on the dashboard
+type TimerState struct {
+	ID        string `json:"id"`
+	Kind      string `json:"kind"` // timer, alarm or reminder
+	Label     string `json:"label"`
+	Due       string `json:"due"`       // RFC 3339
+	Remaining int    `json:"remaining"` // Seconds until it goes off
+	Recur     string `json:"recur,omitempty"`
+}
+
 // LogEntry represents a log line for the dashboard
 type LogEntry struct {
 	Time    string `json:"time"`
@@ -64,9 +79,15 @@ type Server struct {
 	logHub    *hub.Hub
 	cameraHub *hub.Hub
 
+	telemetryHub *hub.Hub
+
 	// Tool trigger callback
 	OnToolTrigger func(name string, args map[string]interface{}) (string, error)
 
+	// Tool registry callbacks (per-tool metrics, runtime enable/disable)
+	OnGetTools       func() interface{}
+	OnSetToolEnabled func(name string, enabled bool) error
+
 	// Frame capture callback
 	OnCaptureFrame func() ([]byte, error)
 
@@ -75,6 +96,17 @@ type Server struct {
 	OnSetTuningParams func(params map[string]interface{})
 	OnSetTuningMode   func(enabled bool)
 
+	// Auto-tune callbacks (PD gain search; mode is "sim" or "visual")
+	OnStartAutoTune     func(axis, mode string, apply bool) error
+	OnCancelAutoTune    func() bool // Reports whether a run was stopped
+	OnGetAutoTuneStatus func() interface{}
+
+	// Telemetry callback (recent per-tick tracking records, n <= 0 = all)
+	OnGetTelemetry func(n int) interface{}
+
+	// Tracker state machine callback (current state and recent transitions)
+	OnGetTrackingState func() interface{}
+
 	// Camera callbacks (for camera configuration)
 	OnGetCameraConfig func() interface{}
 	OnSetCameraConfig func(params map[string]interface{}) error
@@ -92,9 +124,17 @@ type Server struct {
 	OnSparkDelete  func(id string) error
 	OnSparkGenPlan func(id string) error
 
+	// Timer callbacks (timers, alarms and reminders)
+	OnGetTimers   func() interface{}
+	OnCancelTimer func(id string) error
+
 	// Audio control callbacks
 	OnSetPaused    func(paused bool)  // Pause/resume Eva completely
 	OnSetListening func(enabled bool) // Mute/unmute microphone
+
+	// Listening mode callbacks
+	OnSetListeningMode func(mode string) error
+	OnPushToTalk       func(pressed bool) error
 }
 
 // NewServer creates a new web dashboard server
@@ -106,6 +146,7 @@ func NewServer(port string) *Server {
 		statusHub:    hub.New("status"),
 		logHub:       hub.New("logs"),
 		cameraHub:    hub.New("camera"),
+		telemetryHub: hub.New("telemetry"),
 	}
 
 	app := fiber.New(fiber.Config{
@@ -124,6 +165,7 @@ func NewServer(port string) *Server {
 	api.Get("/status", s.handleStatus)
 	api.Get("/tools", s.handleListTools)
 	api.Post("/tools/:name", s.handleTriggerTool)
+	api.Post("/tools/:name/enabled", s.handleSetToolEnabled)
 	api.Get("/logs", s.handleGetLogs)
 	api.Get("/conversation", s.handleGetConversation)
 
@@ -131,6 +173,11 @@ func NewServer(port string) *Server {
 	api.Get("/tracking/params", s.handleGetTuningParams)
 	api.Post("/tracking/params", s.handleSetTuningParams)
 	api.Post("/tracking/tuning-mode", s.handleSetTuningMode)
+	api.Get("/tracking/autotune", s.handleGetAutoTune)
+	api.Post("/tracking/autotune", s.handleStartAutoTune)
+	api.Delete("/tracking/autotune", s.handleCancelAutoTune)
+	api.Get("/tracking/telemetry", s.handleGetTelemetry)
+	api.Get("/tracking/state", s.handleGetTrackingState)
 
 	// Camera API routes
 	api.Get("/camera/config", s.handleGetCameraConfig)
@@ -151,9 +198,15 @@ func NewServer(port string) *Server {
 	api.Post("/sparks/:id/plan", s.handleSparkGenPlan)
 	api.Delete("/sparks/:id", s.handleSparkDeleteOne)
 
+	// Timer routes
+	api.Get("/timers", s.handleGetTimers)
+	api.Delete("/timers/:id", s.handleCancelTimer)
+
 	// Audio control routes
 	api.Post("/paused", s.handleSetPaused)
 	api.Post("/listening", s.handleSetListening)
+	api.Post("/listening/mode", s.handleSetListeningMode)
+	api.Post("/talk", s.handlePushToTalk)
 
 	// WebSocket upgrade middleware
 	app.Use("/ws", func(c *fiber.Ctx) error {
@@ -167,6 +220,7 @@ func NewServer(port string) *Server {
 	app.Get("/ws/logs", websocket.New(s.handleLogsWS))
 	app.Get("/ws/camera", websocket.New(s.handleCameraWS))
 	app.Get("/ws/status", websocket.New(s.handleStatusWS))
+	app.Get("/ws/telemetry", websocket.New(s.handleTelemetryWS))
 
 	s.app = app
 	return s
@@ -180,6 +234,7 @@ func (s *Server) Start() error {
 	go s.statusHub.Run()
 	go s.logHub.Run()
 	go s.cameraHub.Run()
+	go s.telemetryHub.Run()
 
 	return s.app.Listen(":" + s.port)
 }
@@ -245,6 +300,15 @@ func (s *Server) SendCameraFrame(jpegData []byte) {
 	s.cameraHub.BroadcastBinary(jpegData)
 }
 
+// SendTelemetry broadcasts a tracking telemetry record to connected clients
+func (s *Server) SendTelemetry(record interface{}) {
+	// Skip encoding when nobody is watching (called at 20 Hz)
+	if s.telemetryHub.ClientCount() == 0 {
+		return
+	}
+	s.telemetryHub.BroadcastJSON(record)
+}
+
 // GetStatusHub returns the status hub for external use
 func (s *Server) GetStatusHub() *hub.Hub {
 	return s.statusHub
@@ -326,6 +390,104 @@ func (s *Server) handleSetTuningMode(c *fiber.Ctx) error {
 	})
 }
 
+// handleGetAutoTune returns auto-tune progress and the last result
+func (s *Server) handleGetAutoTune(c *fiber.Ctx) error {
+	if s.OnGetAutoTuneStatus == nil {
+		return c.Status(503).JSON(fiber.Map{
+			"error": "Auto-tune not available (tracker not connected)",
+		})
+	}
+	return c.JSON(s.OnGetAutoTuneStatus())
+}
+
+// handleStartAutoTune starts an auto-tune run in the background
+func (s *Server) handleStartAutoTune(c *fiber.Ctx) error {
+	if s.OnStartAutoTune == nil {
+		return c.Status(503).JSON(fiber.Map{
+			"error": "Auto-tune not available (tracker not connected)",
+		})
+	}
+
+	var req struct {
+		Axis  string `json:"axis"`
+		Mode  string `json:"mode"`
+		Apply bool   `json:"apply"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid JSON: " + err.Error(),
+		})
+	}
+	if req.Axis == "" {
+		req.Axis = "yaw"
+	}
+	if req.Mode == "" {
+		req.Mode = "sim"
+	}
+	if req.Axis != "yaw" && req.Axis != "pitch" {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "axis must be \"yaw\" or \"pitch\"",
+		})
+	}
+	if req.Mode != "sim" && req.Mode != "visual" {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "mode must be \"sim\" or \"visual\"",
+		})
+	}
+	if req.Apply && req.Mode == "sim" {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "apply needs mode \"visual\": gains tuned on the simulated head don't fit the robot",
+		})
+	}
+
+	if err := s.OnStartAutoTune(req.Axis, req.Mode, req.Apply); err != nil {
+		return c.Status(409).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return c.JSON(fiber.Map{
+		"status": "started",
+		"axis":   req.Axis,
+		"mode":   req.Mode,
+		"apply":  req.Apply,
+	})
+}
+
+// handleCancelAutoTune stops a running auto-tune, leaving the gains as they were
+func (s *Server) handleCancelAutoTune(c *fiber.Ctx) error {
+	if s.OnCancelAutoTune == nil {
+		return c.Status(503).JSON(fiber.Map{
+			"error": "Auto-tune not available (tracker not connected)",
+		})
+	}
+	if !s.OnCancelAutoTune() {
+		return c.Status(409).JSON(fiber.Map{
+			"error": "no auto-tune running",
+		})
+	}
+	return c.JSON(fiber.Map{"status": "cancelled"})
+}
+
+// handleGetTelemetry returns recent tracking telemetry records
+func (s *Server) handleGetTelemetry(c *fiber.Ctx) error {
+	if s.OnGetTelemetry == nil {
+		return c.Status(503).JSON(fiber.Map{
+			"error": "Telemetry not available (tracker not connected)",
+		})
+	}
+	return c.JSON(s.OnGetTelemetry(c.QueryInt("n", 200)))
+}
+
+// handleGetTrackingState returns the tracker's behaviour state
+func (s *Server) handleGetTrackingState(c *fiber.Ctx) error {
+	if s.OnGetTrackingState == nil {
+		return c.Status(503).JSON(fiber.Map{
+			"error": "Tracking state not available (tracker not connected)",
+		})
+	}
+	return c.JSON(s.OnGetTrackingState())
+}
+
 // handleGetCameraConfig returns current camera configuration
 func (s *Server) handleGetCameraConfig(c *fiber.Ctx) error {
 	if s.OnGetCameraConfig == nil {
@@ -584,3 +746,30 @@ func (s *Server) handleSparkDeleteOne(c *fiber.Ctx) error {
 	}
 	return c.JSON(fiber.Map{"success": true})
 }
+
+// handleGetTimers returns pending timers, alarms and reminders
+func (s *Server) handleGetTimers(c *fiber.Ctx) error {
+	if s.OnGetTimers == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "Timers not configured",
+		})
+	}
+	return c.JSON(s.OnGetTimers())
+}
+
+// handleCancelTimer cancels a timer, alarm or reminder
+func (s *Server) handleCancelTimer(c *fiber.Ctx) error {
+	if s.OnCancelTimer == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "Timers not configured",
+		})
+	}
+
+	id := c.Params("id")
+	if err := s.OnCancelTimer(id); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return c.JSON(fiber.Map{"success": true})
+}
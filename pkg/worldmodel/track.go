@@ -0,0 +1,245 @@
+package worldmodel
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// TrackKind is the category of a tracked thing
+type TrackKind string
+
+const (
+	TrackPerson TrackKind = "person" // Faces and bodies
+	TrackPet    TrackKind = "pet"    // Animals
+	TrackObject TrackKind = "object" // Everything else
+)
+
+// DefaultTrackGate is the maximum angle difference (radians) for an
+// observation to be associated with an existing track (~11°)
+const DefaultTrackGate = 0.2
+
+// Observation is a single detection in room coordinates, ready to be
+// associated with a track. Produced from detection.Observation by the tracker.
+type Observation struct {
+	Kind       TrackKind // Category
+	Label      string    // Class name ("face", "person", "dog", "cup")
+	WorldAngle float64   // Room angle (radians, +left)
+	FrameX     float64   // Horizontal frame position (0-100%)
+	FrameY     float64   // Vertical frame position (0-100%)
+	Width      float64   // Normalized width (0-1)
+	Height     float64   // Normalized height (0-1)
+	Distance   float64   // Estimated distance in meters (0 = unknown)
+	Confidence float64   // Detector confidence (0-1)
+	Source     string    // Detector name
+	Time       time.Time // Frame time (zero = now)
+}
+
+// Track is a person, pet or object followed across frames
+type Track struct {
+	ID         string    // Stable identifier, e.g. "person-3"
+	Kind       TrackKind // Category
+	Label      string    // Most recent class name
+	WorldAngle float64   // Smoothed room angle (radians)
+	Velocity   float64   // Angular velocity (rad/sec)
+	FrameX     float64   // Last frame position (0-100%)
+	FrameY     float64   // Last frame position (0-100%)
+	Width      float64   // Last normalized width
+	Height     float64   // Last normalized height
+	Distance   float64   // Smoothed distance in meters (0 = unknown)
+	Confidence float64   // 0-1, decays when not seen
+	Source     string    // Detector that last updated the track
+	FirstSeen  time.Time // When the track was created
+	LastSeen   time.Time // When last observed
+	Hits       int       // Number of observations associated
+}
+
+// PredictedAngle extrapolates the track's room angle to time t using its
+// velocity. Prediction is capped at one second to avoid runaway estimates.
+func (tr *Track) PredictedAngle(t time.Time) float64 {
+	dt := t.Sub(tr.LastSeen).Seconds()
+	if dt <= 0 {
+		return tr.WorldAngle
+	}
+	if dt > 1.0 {
+		dt = 1.0
+	}
+	return tr.WorldAngle + tr.Velocity*dt
+}
+
+// SetTrackGate sets the association gate in radians
+func (w *WorldModel) SetTrackGate(gate float64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if gate > 0 {
+		w.trackGate = gate
+	}
+}
+
+// UpdateTracks associates a batch of observations (normally from one frame)
+// with existing tracks, creating new tracks for unmatched observations.
+// Matching is greedy nearest-neighbour on predicted angle within the track
+// gate; people match any label, pets and objects must share a label.
+// Returns the track ID assigned to each observation, in order.
+func (w *WorldModel) UpdateTracks(obs []Observation) []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ids := make([]string, len(obs))
+	if len(obs) == 0 {
+		return ids
+	}
+
+	now := time.Now()
+	for i := range obs {
+		if obs[i].Time.IsZero() {
+			obs[i].Time = now
+		}
+	}
+
+	// Build all candidate pairs within the gate
+	type pair struct {
+		obs   int
+		track *Track
+		diff  float64
+	}
+	var pairs []pair
+	for i, o := range obs {
+		for _, tr := range w.tracks {
+			if tr.Kind != o.Kind {
+				continue
+			}
+			if o.Kind != TrackPerson && tr.Label != o.Label {
+				continue
+			}
+			diff := math.Abs(tr.PredictedAngle(o.Time) - o.WorldAngle)
+			if diff <= w.trackGate {
+				pairs = append(pairs, pair{obs: i, track: tr, diff: diff})
+			}
+		}
+	}
+	sort.Slice(pairs, func(a, b int) bool { return pairs[a].diff < pairs[b].diff })
+
+	// Greedy assignment: closest pairs first, one-to-one
+	usedTrack := make(map[*Track]bool)
+	for _, p := range pairs {
+		if ids[p.obs] != "" || usedTrack[p.track] {
+			continue
+		}
+		usedTrack[p.track] = true
+		ids[p.obs] = p.track.ID
+		p.track.update(obs[p.obs])
+	}
+
+	// Unmatched observations start new tracks
+	for i, o := range obs {
+		if ids[i] != "" {
+			continue
+		}
+		w.trackSeq++
+		tr := &Track{
+			ID:         fmt.Sprintf("%s-%d", o.Kind, w.trackSeq),
+			Kind:       o.Kind,
+			Label:      o.Label,
+			WorldAngle: o.WorldAngle,
+			FrameX:     o.FrameX,
+			FrameY:     o.FrameY,
+			Width:      o.Width,
+			Height:     o.Height,
+			Distance:   o.Distance,
+			Confidence: 1.0,
+			Source:     o.Source,
+			FirstSeen:  o.Time,
+			LastSeen:   o.Time,
+			Hits:       1,
+		}
+		w.tracks[tr.ID] = tr
+		ids[i] = tr.ID
+	}
+
+	return ids
+}
+
+// update folds a new observation into the track
+func (tr *Track) update(o Observation) {
+	dt := o.Time.Sub(tr.LastSeen).Seconds()
+	if dt > 0 && dt < 1.0 {
+		v := (o.WorldAngle - tr.WorldAngle) / dt
+		tr.Velocity = 0.5*v + 0.5*tr.Velocity
+	} else if dt >= 1.0 {
+		tr.Velocity = 0 // Too stale to trust
+	}
+
+	smoothing := 0.7 // Weight of new reading (same as entities)
+	tr.WorldAngle = smoothing*o.WorldAngle + (1-smoothing)*tr.WorldAngle
+	if o.Distance > 0 {
+		if tr.Distance > 0 {
+			tr.Distance = smoothing*o.Distance + (1-smoothing)*tr.Distance
+		} else {
+			tr.Distance = o.Distance
+		}
+	}
+
+	tr.Label = o.Label
+	tr.FrameX = o.FrameX
+	tr.FrameY = o.FrameY
+	tr.Width = o.Width
+	tr.Height = o.Height
+	tr.Source = o.Source
+	tr.Confidence = 1.0
+	tr.LastSeen = o.Time
+	tr.Hits++
+}
+
+// decayTracks lowers track confidence and drops forgotten tracks.
+// Caller must hold w.mu.
+func (w *WorldModel) decayTracks(dt float64) {
+	for id, tr := range w.tracks {
+		tr.Confidence -= w.confidenceDecay * dt
+		if tr.Confidence < 0 {
+			tr.Confidence = 0
+		}
+		if tr.Confidence < w.forgetThreshold || time.Since(tr.LastSeen) > w.forgetTimeout {
+			delete(w.tracks, id)
+		}
+	}
+}
+
+// GetTracks returns copies of all tracks of the given kind, sorted by ID.
+// An empty kind returns every track.
+func (w *WorldModel) GetTracks(kind TrackKind) []*Track {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	result := make([]*Track, 0, len(w.tracks))
+	for _, tr := range w.tracks {
+		if kind != "" && tr.Kind != kind {
+			continue
+		}
+		copy := *tr
+		result = append(result, &copy)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+// GetTrack returns a copy of a track by ID, or nil if unknown
+func (w *WorldModel) GetTrack(id string) *Track {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	tr, ok := w.tracks[id]
+	if !ok {
+		return nil
+	}
+	copy := *tr
+	return &copy
+}
+
+// ClearTracks removes all tracks
+func (w *WorldModel) ClearTracks() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.tracks = make(map[string]*Track)
+}
@@ -0,0 +1,132 @@
+package worldmodel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUpdateTracks_CreatesTracks(t *testing.T) {
+	w := New()
+
+	ids := w.UpdateTracks([]Observation{
+		{Kind: TrackPerson, Label: "face", WorldAngle: 0.1},
+		{Kind: TrackPet, Label: "dog", WorldAngle: -0.5},
+	})
+
+	if len(ids) != 2 || ids[0] == "" || ids[1] == "" || ids[0] == ids[1] {
+		t.Fatalf("UpdateTracks ids = %v, want two distinct IDs", ids)
+	}
+	if got := len(w.GetTracks("")); got != 2 {
+		t.Errorf("GetTracks(\"\") len = %d, want 2", got)
+	}
+	if got := len(w.GetTracks(TrackPet)); got != 1 {
+		t.Errorf("GetTracks(pet) len = %d, want 1", got)
+	}
+}
+
+func TestUpdateTracks_AssociatesAcrossFrames(t *testing.T) {
+	w := New()
+	t0 := time.Now()
+
+	first := w.UpdateTracks([]Observation{{Kind: TrackPerson, Label: "face", WorldAngle: 0.30, Time: t0}})
+	second := w.UpdateTracks([]Observation{{Kind: TrackPerson, Label: "face", WorldAngle: 0.35, Time: t0.Add(100 * time.Millisecond)}})
+
+	if first[0] != second[0] {
+		t.Fatalf("track ID changed across frames: %q → %q", first[0], second[0])
+	}
+
+	tr := w.GetTrack(first[0])
+	if tr == nil {
+		t.Fatal("GetTrack returned nil")
+	}
+	if tr.Hits != 2 {
+		t.Errorf("Hits = %d, want 2", tr.Hits)
+	}
+	if tr.Velocity <= 0 {
+		t.Errorf("Velocity = %v, want > 0 (moving left)", tr.Velocity)
+	}
+	if !tr.LastSeen.Equal(t0.Add(100 * time.Millisecond)) {
+		t.Errorf("LastSeen = %v, want second frame time", tr.LastSeen)
+	}
+}
+
+func TestUpdateTracks_FaceAndBodyShareTrack(t *testing.T) {
+	w := New()
+	t0 := time.Now()
+
+	w.UpdateTracks([]Observation{{Kind: TrackPerson, Label: "face", WorldAngle: 0.2, Time: t0}})
+	ids := w.UpdateTracks([]Observation{{Kind: TrackPerson, Label: "person", WorldAngle: 0.22, Time: t0.Add(50 * time.Millisecond)}})
+
+	if got := len(w.GetTracks(TrackPerson)); got != 1 {
+		t.Errorf("person tracks = %d, want 1 (body should join face track)", got)
+	}
+	if tr := w.GetTrack(ids[0]); tr == nil || tr.Label != "person" {
+		t.Errorf("track label = %+v, want latest label 'person'", tr)
+	}
+}
+
+func TestUpdateTracks_ObjectsRequireSameLabel(t *testing.T) {
+	w := New()
+
+	w.UpdateTracks([]Observation{{Kind: TrackObject, Label: "cup", WorldAngle: 0.0}})
+	w.UpdateTracks([]Observation{{Kind: TrackObject, Label: "bottle", WorldAngle: 0.01}})
+
+	if got := len(w.GetTracks(TrackObject)); got != 2 {
+		t.Errorf("object tracks = %d, want 2 (different labels)", got)
+	}
+}
+
+func TestUpdateTracks_OutsideGateStartsNewTrack(t *testing.T) {
+	w := New()
+
+	a := w.UpdateTracks([]Observation{{Kind: TrackPerson, WorldAngle: 0.0}})
+	b := w.UpdateTracks([]Observation{{Kind: TrackPerson, WorldAngle: 1.0}})
+
+	if a[0] == b[0] {
+		t.Errorf("observation 1 rad away reused track %q", a[0])
+	}
+}
+
+func TestUpdateTracks_TwoPeopleKeepIdentity(t *testing.T) {
+	w := New()
+	t0 := time.Now()
+
+	first := w.UpdateTracks([]Observation{
+		{Kind: TrackPerson, WorldAngle: -0.3, Time: t0},
+		{Kind: TrackPerson, WorldAngle: 0.3, Time: t0},
+	})
+	// Same two people, reported in the opposite order
+	second := w.UpdateTracks([]Observation{
+		{Kind: TrackPerson, WorldAngle: 0.32, Time: t0.Add(100 * time.Millisecond)},
+		{Kind: TrackPerson, WorldAngle: -0.28, Time: t0.Add(100 * time.Millisecond)},
+	})
+
+	if second[0] != first[1] || second[1] != first[0] {
+		t.Errorf("identities swapped: first=%v second=%v", first, second)
+	}
+}
+
+func TestDecayConfidence_PrunesTracks(t *testing.T) {
+	w := New()
+	w.UpdateTracks([]Observation{{Kind: TrackPet, Label: "cat", WorldAngle: 0.0}})
+
+	// 0.3/s decay: 4 seconds drops confidence below the 0.1 threshold
+	w.DecayConfidence(4.0)
+
+	if got := len(w.GetTracks("")); got != 0 {
+		t.Errorf("tracks after decay = %d, want 0", got)
+	}
+}
+
+func TestTrack_PredictedAngle(t *testing.T) {
+	now := time.Now()
+	tr := &Track{WorldAngle: 0.5, Velocity: 0.2, LastSeen: now}
+
+	if got := tr.PredictedAngle(now.Add(500 * time.Millisecond)); !floatEquals(got, 0.6) {
+		t.Errorf("PredictedAngle(+0.5s) = %v, want 0.6", got)
+	}
+	// Capped at one second
+	if got := tr.PredictedAngle(now.Add(5 * time.Second)); !floatEquals(got, 0.7) {
+		t.Errorf("PredictedAngle(+5s) = %v, want 0.7 (capped)", got)
+	}
+}
@@ -21,6 +21,11 @@ type WorldModel struct {
 	// Detected objects (non-face detections)
 	objects map[string]*DetectedObject
 
+	// Multi-frame tracks of people, pets and objects (see track.go)
+	tracks    map[string]*Track
+	trackSeq  int     // Monotonic counter for track IDs
+	trackGate float64 // Association gate (radians)
+
 	// Configuration
 	confidenceDecay float64       // How fast confidence decays per second
 	forgetThreshold float64       // Remove entities below this confidence
@@ -35,6 +40,8 @@ func New() *WorldModel {
 	return &WorldModel{
 		entities:        make(map[string]*TrackedEntity),
 		objects:         make(map[string]*DetectedObject),
+		tracks:          make(map[string]*Track),
+		trackGate:       DefaultTrackGate,
 		bodyYawLimit:    DefaultBodyYawLimit, // ±162° matching Python reachy
 		confidenceDecay: 0.3,                 // Lose 30% confidence per second
 		forgetThreshold: 0.1,                 // Forget below 10% confidence
@@ -176,6 +183,9 @@ func (w *WorldModel) DecayConfidence(dt float64) {
 		}
 	}
 
+	// Decay and prune multi-frame tracks
+	w.decayTracks(dt)
+
 	// Delete forgotten entities
 	for _, id := range toDelete {
 		delete(w.entities, id)